@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
+	"delpresence-api/pkg/database"
+	"delpresence-api/pkg/jwt"
+)
+
+// cliActorType marks audit entries of actions taken from the command line
+const cliActorType = "cli"
+
+// migrate migrates the database schema without starting the server, for deploy pipelines
+func migrate(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flags.Usage = commandUsage(flags, "migrate")
+	flags.Parse(args)
+
+	if err := database.ConnectDB(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	if err := database.RunMigrations(); err != nil {
+		return fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	version, err := database.CurrentSchemaVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database schema is at version %d\n", version)
+	return nil
+}
+
+// seed creates the data a fresh database needs, such as the default admin account, and with
+// -dev sample data for local development
+func seed(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	flags.Usage = commandUsage(flags, "seed")
+	dev := flags.Bool("dev", false, "Also create sample courses, rooms, schedules, students, lecturers and attendance sessions")
+	flags.Parse(args)
+
+	if *dev && cfg.IsProduction() {
+		return errors.New("sample data must not be seeded in production")
+	}
+
+	if err := database.ConnectDB(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	if err := database.Seed(); err != nil {
+		return err
+	}
+	if *dev {
+		return seedDevData(cfg, true)
+	}
+	return nil
+}
+
+// seedDevData creates the sample data and, for the command line, prints access tokens of a
+// sample lecturer and student so the apps can sign in without the campus API
+func seedDevData(cfg *config.Config, printTokens bool) error {
+	seeded, err := database.SeedDevData()
+	if err != nil {
+		return fmt.Errorf("failed to seed sample data: %w", err)
+	}
+	if !printTokens || len(seeded.Lecturers) == 0 || len(seeded.Students) == 0 {
+		return nil
+	}
+
+	lecturer, student := seeded.Lecturers[0], seeded.Students[0]
+	lecturerToken, expiresAt, err := jwt.GenerateRoleToken(lecturer.LecturerUserID, int(lecturer.LecturerUserID), lecturer.Email, []string{string(models.LecturerType)}, string(models.LecturerType))
+	if err != nil {
+		return err
+	}
+	studentToken, _, err := jwt.GenerateRoleToken(uint(student.UserID), student.UserID, student.Email, []string{string(models.StudentType)}, string(models.StudentType))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Sample lecturer %s (user ID %d):\n  %s\n", lecturer.FullName, lecturer.LecturerUserID, lecturerToken)
+	fmt.Printf("Sample student %s, NIM %s (user ID %d):\n  %s\n", student.Nama, student.Nim, student.UserID, studentToken)
+	fmt.Printf("Tokens expire at %s\n", expiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// createAdmin creates an admin account. The password is read from standard input unless it
+// is passed with -password, so it stays out of the shell history.
+func createAdmin(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	flags.Usage = commandUsage(flags, "create-admin")
+	username := flags.String("username", "", "Username the admin signs in with (required)")
+	email := flags.String("email", "", "Email address of the admin (required)")
+	password := flags.String("password", "", "Password; read from standard input when empty")
+	firstName := flags.String("first-name", "", "First name; the username when empty")
+	lastName := flags.String("last-name", "", "Last name")
+	position := flags.String("position", "Administrator", "Position of the admin")
+	department := flags.String("department", "", "Prodi or unit of the admin")
+	accessLevel := flags.String("access-level", string(models.StandardAdminAccess), "Access level: super, standard, limited or viewer")
+	flags.Parse(args)
+
+	if *username == "" || *email == "" {
+		flags.Usage()
+		return errors.New("-username and -email are required")
+	}
+	level := models.AccessLevel(*accessLevel)
+	if !models.IsValidAccessLevel(level) {
+		return fmt.Errorf("unknown access level %q", *accessLevel)
+	}
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+	if len(*password) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
+	if *firstName == "" {
+		*firstName = *username
+	}
+
+	if err := database.ConnectDB(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	user := &models.User{
+		Username:  *username,
+		Email:     *email,
+		Password:  *password,
+		FirstName: *firstName,
+		LastName:  *lastName,
+		Verified:  true,
+		Active:    true,
+	}
+	admin := &models.Admin{
+		Position:    *position,
+		Department:  *department,
+		AccessLevel: level,
+		IsActive:    true,
+	}
+	if err := repository.NewAdminRepository().CreateAdmin(user, admin); err != nil {
+		return fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	services.NewAuditService(repository.NewAuditRepository(database.GetDB())).Record(services.AuditEntry{
+		ActorType:  cliActorType,
+		Action:     "admin.create",
+		EntityType: "user",
+		EntityID:   user.ID,
+		Details: map[string]interface{}{
+			"username":     user.Username,
+			"access_level": admin.AccessLevel,
+		},
+	})
+	fmt.Printf("Created %s admin %s (user ID %d)\n", admin.AccessLevel, user.Username, user.ID)
+	return nil
+}
+
+// syncCampus runs a lecturer or prodi sync in the foreground and reports its outcome, for
+// refreshes that cannot wait for the schedule or an admin in the dashboard
+func syncCampus(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("sync-campus", flag.ExitOnError)
+	flags.Usage = commandUsage(flags, "sync-campus")
+	lecturers := flags.Bool("lecturers", false, "Import every lecturer of the campus API")
+	prodiID := flags.Uint("prodi", 0, "Re-sync every local profile of the prodi with this campus ID")
+	flags.Parse(args)
+
+	if *lecturers == (*prodiID != 0) {
+		flags.Usage()
+		return errors.New("pass either -lecturers or -prodi")
+	}
+
+	if err := database.ConnectDB(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+	db := database.GetDB()
+
+	// Synced profiles are audited, linked to their roles and streamed as when the server syncs them
+	bus := events.NewBus()
+	services.NewAuditService(repository.NewAuditRepository(db)).Subscribe(bus)
+	services.SubscribeRoleLinking(bus, repository.NewUserRoleRepository(db))
+	outboxRepo := repository.NewOutboxRepository(db)
+	if _, ok := services.NewStreamRelay(outboxRepo, cfg.Stream); ok {
+		pseudonymizer, err := pseudonym.New(cfg.Privacy.PseudonymKey)
+		if err != nil {
+			return err
+		}
+		bus.SetOutbox(services.NewOutboxForwarder(outboxRepo, pseudonymizer))
+	}
+	defer bus.Wait()
+
+	cacheDriver, err := cache.Open(cfg.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to set up cache: %w", err)
+	}
+	campusClient := utils.NewCampusClient(cfg.Campus, utils.NewCircuitBreaker(cfg.Campus.BreakerThreshold, cfg.Campus.BreakerCooldown), cache.New("campus_nims", cacheDriver))
+	lecturerRepo := repository.NewLecturerRepository(db)
+	assistantRepo := repository.NewAssistantRepository(db)
+	syncConflictService := services.NewSyncConflictService(repository.NewSyncConflictRepository(db), lecturerRepo, assistantRepo, cfg.Campus.FieldPolicies)
+	syncRunRepo := repository.NewSyncRunRepository(db)
+
+	if *lecturers {
+		workers := services.NewWorkers()
+		lecturerSyncService := services.NewLecturerSyncService(campusClient, lecturerRepo, syncRunRepo, syncConflictService, bus, workers)
+		run, err := lecturerSyncService.Trigger(0)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Importing lecturers (sync %d)...\n", run.ID)
+		// Waits for the import, which runs as a background task of the workers
+		workers.Shutdown(context.Background())
+
+		run, err = syncRunRepo.FindByID(run.ID)
+		if err != nil {
+			return err
+		}
+		if run.Status == models.SyncFailed {
+			return fmt.Errorf("lecturer sync %d failed: %s", run.ID, run.Error)
+		}
+		fmt.Printf("Lecturer sync %d completed: %d created, %d updated, %d skipped\n", run.ID, run.Created, run.Updated, run.Skipped)
+		return nil
+	}
+
+	prodiSyncService := services.NewProdiSyncService(campusClient, syncRunRepo, lecturerRepo, assistantRepo, repository.NewMahasiswaRepository(db), syncConflictService, bus, cfg.Campus.SyncInterval)
+	run, err := prodiSyncService.Trigger(*prodiID, 0)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Syncing %d profiles of prodi %d (sync %d)...\n", run.Fetched, *prodiID, run.ID)
+
+	stop := make(chan struct{})
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-quit
+		close(stop)
+	}()
+	if !prodiSyncService.Process(run, stop) {
+		log.Printf("Prodi sync %d stopped early; its remaining profiles stay queued for the server", run.ID)
+		return fmt.Errorf("prodi sync %d did not complete", run.ID)
+	}
+	fmt.Printf("Prodi sync %d completed: %d synced, %d failed\n", run.ID, run.Updated, run.Failed)
+	return nil
+}
+
+// warehouseBackfill writes the data warehouse snapshots of a range of days in the foreground,
+// e.g. after the nightly job was off or attendance was corrected later
+func warehouseBackfill(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("warehouse-backfill", flag.ExitOnError)
+	flags.Usage = commandUsage(flags, "warehouse-backfill")
+	from := flags.String("from", "", "First day to write, YYYY-MM-DD (required)")
+	to := flags.String("to", "", "Last day to write, YYYY-MM-DD (default yesterday)")
+	flags.Parse(args)
+
+	if *from == "" {
+		flags.Usage()
+		return errors.New("-from is required")
+	}
+	fromDay, err := services.ParseWarehouseDate(*from)
+	if err != nil {
+		return err
+	}
+	toDay := services.Yesterday(time.Now())
+	if *to != "" {
+		if toDay, err = services.ParseWarehouseDate(*to); err != nil {
+			return err
+		}
+	}
+	if toDay.Before(fromDay) {
+		return errors.New("-to is before -from")
+	}
+
+	pseudonymizer, err := pseudonym.New(cfg.Privacy.PseudonymKey)
+	if err != nil {
+		return err
+	}
+	if err := database.ConnectDB(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	warehouseService := services.NewWarehouseExportService(repository.NewWarehouseRepository(database.GetDB()), pseudonymizer, cfg.Storage.WarehouseDir)
+	return warehouseService.Backfill(ctx, fromDay, toDay, func(snapshot *models.WarehouseSnapshot) {
+		fmt.Printf("Snapshot %d of %s %s: %d facts, %d students, %d courses\n", snapshot.ID, snapshot.SnapshotDate, snapshot.Status,
+			snapshot.Rows[services.WarehouseFactsFile], snapshot.Rows[services.WarehouseStudentsFile], snapshot.Rows[services.WarehouseCoursesFile])
+	})
+}
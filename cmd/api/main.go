@@ -1,136 +1,623 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
-	"path/filepath"
-	"strings"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/captcha"
+	"delpresence-api/internal/capture"
+	"delpresence-api/internal/chaos"
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/features"
 	"delpresence-api/internal/handlers"
+	"delpresence-api/internal/logging"
+	"delpresence-api/internal/metrics"
 	"delpresence-api/internal/middleware"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
 	"delpresence-api/internal/repository"
+	"delpresence-api/internal/scheduler"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
 	"delpresence-api/pkg/database"
+	"delpresence-api/pkg/qrtoken"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
-func main() {
-	// Get the executable path
-	ex, err := os.Executable()
-	if err != nil {
-		log.Fatal(err)
-	}
-	exPath := filepath.Dir(ex)
-
-	// Try to load .env from multiple locations
-	envPaths := []string{
-		".env",                        // Current directory
-		"../../.env",                  // Project root when running from cmd/api
-		filepath.Join(exPath, ".env"), // Binary location
-	}
-
-	envLoaded := false
-	for _, path := range envPaths {
-		if err := godotenv.Load(path); err == nil {
-			envLoaded = true
-			log.Printf("Loaded .env from: %s", path)
-			break
-		}
-	}
-
-	if !envLoaded {
-		log.Println("Warning: .env file not found, using default values")
-	}
+// serve runs the API server until SIGINT or SIGTERM, migrating and seeding the database first
+func serve(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = commandUsage(flags, "serve")
+	flags.Parse(args)
 
 	// Set Gin mode
-	env := os.Getenv("ENV")
-	if env == "production" {
+	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
 	}
 
 	// Connect to database
-	if err := database.ConnectDB(); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	if err := database.ConnectDB(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Run database migrations
+	// Run database migrations and create the default admin account
 	if err := database.RunMigrations(); err != nil {
-		log.Fatalf("Failed to run database migrations: %v", err)
+		return fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	if err := database.Seed(); err != nil {
+		return fmt.Errorf("failed to seed database: %w", err)
+	}
+	if cfg.SeedDevData {
+		if err := seedDevData(cfg, false); err != nil {
+			return err
+		}
 	}
 
-	// Create router
-	router := gin.Default()
+	// Check the environment in the background and keep the report for /readyz/details
+	go services.DefaultSelfTest.Run(services.NewEmailService(cfg.SMTP, nil, nil), cfg)
+
+	// Create router; requests are logged with their request ID instead of gin's access log
+	router := gin.New()
+	router.Use(gin.Recovery(), middleware.RequestLogger())
+
+	// Only believe X-Forwarded-For from our own proxies; the client IP decides the Wi-Fi check-in
+	// factor, so a forged header must not move a student onto the campus network
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
 
 	// Configure CORS
-	configCors(router)
+	configCors(router, cfg.CORS)
 
-	// Create API routes
-	setupRoutes(router)
+	// Create API routes and start their background jobs
+	workers := services.NewWorkers()
+	setupRoutes(router, cfg, workers)
 
-	// Get port from environment or use default
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
+	// Start server
+	server := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: router,
 	}
+	go func() {
+		log.Printf("Server running at http://localhost:%s", cfg.Server.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
 
-	// Start server
-	log.Printf("Server running at http://localhost:%s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// Wait for SIGINT or SIGTERM, then shut down gracefully
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	shutdown(server, workers, cfg.Server.ShutdownTimeout)
+	return nil
+}
+
+// shutdown stops accepting requests and drains the in-flight ones, then stops the background
+// jobs and waits for their pending writes before closing the database pool
+func shutdown(server *http.Server, workers *services.Workers, timeout time.Duration) {
+	log.Printf("Shutting down, waiting up to %s for in-flight work", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to drain in-flight requests: %v", err)
+	}
+	if err := workers.Shutdown(ctx); err != nil {
+		log.Printf("Failed to wait for background jobs: %v", err)
 	}
+	if err := database.Close(); err != nil {
+		log.Printf("Failed to close database connections: %v", err)
+	}
+	log.Println("Server stopped")
 }
 
-func configCors(router *gin.Engine) {
-	// Get allowed origins from environment
-	allowedOriginsStr := os.Getenv("ALLOWED_ORIGINS")
-	allowedOrigins := []string{"http://localhost:3000"}
+// registerMetrics registers the metrics read on every scrape: the database pool, the email
+// queue, the campus API circuit breaker and the caches
+func registerMetrics(db *gorm.DB, emailQueueRepo repository.EmailQueueRepository, campusBreaker *utils.CircuitBreaker) {
+	poolStat := func(read func(sql.DBStats) float64) func() []metrics.Sample {
+		return func() []metrics.Sample {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return nil
+			}
+			return []metrics.Sample{{Value: read(sqlDB.Stats())}}
+		}
+	}
+	metrics.Default.NewGaugeFunc("delpresence_db_open_connections", "Open connections of the database pool.", nil,
+		poolStat(func(stats sql.DBStats) float64 { return float64(stats.OpenConnections) }))
+	metrics.Default.NewGaugeFunc("delpresence_db_in_use_connections", "Database connections in use.", nil,
+		poolStat(func(stats sql.DBStats) float64 { return float64(stats.InUse) }))
+	metrics.Default.NewGaugeFunc("delpresence_db_idle_connections", "Idle database connections.", nil,
+		poolStat(func(stats sql.DBStats) float64 { return float64(stats.Idle) }))
+	metrics.Default.NewCounterFunc("delpresence_db_wait_count_total", "Times a query waited for a free database connection.", nil,
+		poolStat(func(stats sql.DBStats) float64 { return float64(stats.WaitCount) }))
+	metrics.Default.NewCounterFunc("delpresence_db_wait_duration_seconds_total", "Time queries waited for a free database connection.", nil,
+		poolStat(func(stats sql.DBStats) float64 { return stats.WaitDuration.Seconds() }))
+
+	metrics.Default.NewGaugeFunc("delpresence_email_queue_depth", "Queued emails by status.", []string{"status"}, func() []metrics.Sample {
+		counts, err := emailQueueRepo.CountByStatus(models.EmailPending, models.EmailSending, models.EmailFailed)
+		if err != nil {
+			log.Printf("[METRICS] Failed to count queued emails: %v", err)
+			return nil
+		}
+		var samples []metrics.Sample
+		for _, status := range []models.QueuedEmailStatus{models.EmailPending, models.EmailSending, models.EmailFailed} {
+			samples = append(samples, metrics.Sample{LabelValues: []string{string(status)}, Value: float64(counts[status])})
+		}
+		return samples
+	})
+
+	metrics.Default.NewGaugeFunc("delpresence_campus_api_circuit_open", "Whether the circuit breaker to the campus API rejects calls (1) or not (0).", nil, func() []metrics.Sample {
+		open := 0.0
+		if campusBreaker.State() == utils.CircuitOpen {
+			open = 1
+		}
+		return []metrics.Sample{{Value: open}}
+	})
+
+	cacheStat := func(read func(cache.Stat) int64) func() []metrics.Sample {
+		return func() []metrics.Sample {
+			var samples []metrics.Sample
+			for _, stat := range cache.Stats() {
+				samples = append(samples, metrics.Sample{LabelValues: []string{stat.Name}, Value: float64(read(stat))})
+			}
+			return samples
+		}
+	}
+	metrics.Default.NewCounterFunc("delpresence_cache_hits_total", "Cache hits by cache.", []string{"cache"},
+		cacheStat(func(stat cache.Stat) int64 { return stat.Hits }))
+	metrics.Default.NewCounterFunc("delpresence_cache_misses_total", "Cache misses by cache.", []string{"cache"},
+		cacheStat(func(stat cache.Stat) int64 { return stat.Misses }))
+}
 
-	if allowedOriginsStr != "" {
-		allowedOrigins = strings.Split(allowedOriginsStr, ",")
+// scheduleJob registers a recurring job and stops startup when its schedule is invalid
+func scheduleJob(jobScheduler *scheduler.Scheduler, name, defaultSpec string, job scheduler.Job) {
+	if err := jobScheduler.Add(name, defaultSpec, job); err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
 	}
+}
 
+func configCors(router *gin.Engine, cfg config.CORSConfig) {
 	// Configure CORS middleware
-	config := cors.DefaultConfig()
-	config.AllowOrigins = allowedOrigins
-	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	config.ExposeHeaders = []string{"Content-Length"}
-	config.AllowCredentials = true
-
-	router.Use(cors.New(config))
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = cfg.AllowedOrigins
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Sudo-Token", "X-Chaos", "X-App-Version", middleware.CaptchaTokenHeader, middleware.ClientTokenHeader, middleware.CSRFHeader}
+	corsConfig.ExposeHeaders = []string{"Content-Length", logging.RequestIDHeader}
+	corsConfig.AllowCredentials = true
+
+	router.Use(cors.New(corsConfig))
 }
 
-func setupRoutes(router *gin.Engine) {
+func setupRoutes(router *gin.Engine, cfg *config.Config, workers *services.Workers) {
+	// Get database connection
+	db := database.GetDB()
+
+	// Shared cache driver; each feature caches under its own name
+	cacheDriver, err := cache.Open(cfg.Cache)
+	if err != nil {
+		log.Fatalf("Failed to set up cache: %v", err)
+	}
+
+	// Circuit breaker of the campus API, shared by the client, health checks and metrics
+	campusBreaker := utils.NewCircuitBreaker(cfg.Campus.BreakerThreshold, cfg.Campus.BreakerCooldown)
+
+	// Attribute usage to routes, API keys and prodi; must be registered before any route
+	prodiResolver := services.NewProdiResolver(repository.NewMahasiswaRepository(db), repository.NewLecturerRepository(db), cache.New("prodi", cacheDriver))
+	router.Use(middleware.UsageMetrics(metrics.DefaultUsage, prodiResolver.Resolve))
+	router.Use(middleware.PrometheusMetrics())
+
+	// Persist per-endpoint, per-user usage in hourly rollups; the same service enforces the
+	// quotas admins set on expensive endpoints in every authenticated group
+	usageService := services.NewUsageService(repository.NewUsageRepository(db), cache.New("usage_quotas", cacheDriver))
+	workers.Run("usage rollup", usageService.Run)
+	router.Use(middleware.RecordUsage(usageService))
+	quota := middleware.EnforceQuota(usageService)
+
+	// Sampled request/response capture for the users and routes admins opted in
+	router.Use(middleware.RequestCapture(capture.Default))
+
+	// Fault injection for resilience testing on staging
+	if chaos.Enabled() {
+		log.Println("Chaos fault injection is enabled")
+		router.Use(middleware.Chaos())
+	}
+
+	// Startup self-test report for operators
+	selfTestHandler := handlers.NewSelfTestHandler(services.DefaultSelfTest)
+	router.GET("/readyz/details", selfTestHandler.GetReadinessDetails)
+
+	// Prometheus scrape endpoint, outside the API prefix like the other probes
+	metricsHandler := handlers.NewMetricsHandler(metrics.Default, cfg.Server.MetricsToken)
+	router.GET("/metrics", metricsHandler.GetMetrics)
+
 	// API version prefix
 	api := router.Group("/api/v1")
 
 	// Health check
 	api.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"status":  "success",
-			"message": "DelPresence API is running",
+			"status":   "success",
+			"message":  "DelPresence API is running",
+			"degraded": campusBreaker.State() == utils.CircuitOpen,
+			"campus_api": gin.H{
+				"circuit": campusBreaker.State(),
+			},
 		})
 	})
 
+	// Liveness and readiness probes for the orchestrator
+	healthHandler := handlers.NewHealthHandler(services.NewHealthService(services.NewEmailService(cfg.SMTP, nil, nil), cfg.Campus, campusBreaker))
+	api.GET("/health/live", healthHandler.Live)
+	api.GET("/health/ready", healthHandler.Ready)
+
+	// OpenAPI document of the API for the frontend and mobile teams
+	docsHandler := handlers.NewDocsHandler()
+	api.GET("/docs", docsHandler.GetOpenAPI)
+
+	// App version check; registered before the gate so outdated apps can still reach it
+	appVersionPolicy := utils.AppVersionPolicy{
+		MinimumVersion: cfg.AppVersion.MinimumVersion,
+		LatestVersion:  cfg.AppVersion.LatestVersion,
+		UpdateURL:      cfg.AppVersion.UpdateURL,
+	}
+	appVersionHandler := handlers.NewAppVersionHandler(appVersionPolicy)
+	api.GET("/app/version", appVersionHandler.CheckVersion)
+	api.Use(middleware.AppVersionGate(appVersionPolicy))
+
+	// Feature flags from FEATURE_<NAME>
+	flags := features.New(cfg.Features)
+
 	// Create handlers
-	authHandler := handlers.NewAuthHandler()
-	mahasiswaHandler := handlers.NewMahasiswaHandler()
 	adminHandler := handlers.NewAdminHandler()
 
-	// Get database connection
-	db := database.GetDB()
+	// Domain events published by handlers; subscribers are registered below
+	bus := events.NewBus()
 
-	// Setup lecturer repository and handler
-	lecturerRepo := repository.NewLecturerRepository(db)
-	lecturerHandler := handlers.NewLecturerHandler(lecturerRepo)
+	// One campus API client shared by every handler so they reuse its service account token
+	campusClient := utils.NewCampusClient(cfg.Campus, campusBreaker, cache.New("campus_nims", cacheDriver))
+
+	// Setup mahasiswa repository and handler
+	mahasiswaRepo := repository.NewMahasiswaRepository(db)
+	mahasiswaHandler := handlers.NewMahasiswaHandler(mahasiswaRepo, bus, campusClient)
 
-	// Setup assistant repository and handler
+	// Setup lecturer and assistant repositories and the merge of the profile fields both the
+	// campus API and their owners can change
+	lecturerRepo := repository.NewLecturerRepository(db)
 	assistantRepo := repository.NewAssistantRepository(db)
-	assistantHandler := handlers.NewAssistantHandler(assistantRepo)
+	syncConflictService := services.NewSyncConflictService(repository.NewSyncConflictRepository(db), lecturerRepo, assistantRepo, cfg.Campus.FieldPolicies)
+
+	// Setup lecturer handler
+	lecturerHandler := handlers.NewLecturerHandler(lecturerRepo, syncConflictService, bus, campusClient)
+
+	// Setup assistant handler
+	assistantHandler := handlers.NewAssistantHandler(assistantRepo, syncConflictService, bus, campusClient)
+
+	// Setup API key and proctoring handlers
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	examAttendanceRepo := repository.NewExamAttendanceRepository(db)
+	proctoringHandler := handlers.NewProctoringHandler(examAttendanceRepo)
+
+	// Setup activity repository and handler
+	activityRepo := repository.NewActivityRepository(db)
+	activityHandler := handlers.NewActivityHandler(activityRepo, mahasiswaRepo, campusClient)
+
+	// Setup email service with the branding admins configure per faculty and opt-out tracking
+	// of critical emails
+	emailBrandingRepo := repository.NewEmailBrandingRepository(db)
+	emailTrackingRepo := repository.NewEmailTrackingRepository(db)
+	emailTracker := services.NewEmailTracker(emailTrackingRepo, cfg.Server.PublicBaseURL, cfg.JWT.SubKey("email-tracking"), cfg.SMTP.Tracking)
+	emailService := services.NewEmailService(cfg.SMTP, emailBrandingRepo, emailTracker)
+	emailTrackingHandler := handlers.NewEmailTrackingHandler(emailTracker, emailTrackingRepo)
+
+	// Setup the email queue, which retries failed SMTP sends with backoff
+	emailQueueRepo := repository.NewEmailQueueRepository(db)
+	emailQueue := services.NewEmailQueue(emailService, emailQueueRepo)
+	registerMetrics(db, emailQueueRepo, campusBreaker)
+	workers.Run("email queue", emailQueue.Run)
+
+	// Notify campus IT when the campus API is unhealthy; off until a webhook or email is set
+	integrationAlertRepo := repository.NewIntegrationAlertRepository(db)
+	campusHealthMonitor := services.NewCampusHealthMonitor(integrationAlertRepo, emailQueue, cfg.CampusAlert, campusBreaker)
+	if cfg.CampusAlert.Enabled() {
+		workers.Run("campus health alerts", campusHealthMonitor.Run)
+	}
+	campusAlertHandler := handlers.NewCampusAlertHandler(integrationAlertRepo, campusHealthMonitor)
+
+	// Setup internship repository, service and handler
+	internshipRepo := repository.NewInternshipRepository(db)
+	internshipService := services.NewInternshipService(internshipRepo, mahasiswaRepo, emailService)
+	internshipHandler := handlers.NewInternshipHandler(internshipRepo, mahasiswaRepo, internshipService, emailQueue, cfg.Server.PublicBaseURL, campusClient)
+
+	// Setup audit and notification services
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := services.NewAuditService(auditRepo)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := services.NewNotificationService(notificationRepo)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+
+	// Setup email branding configured by admins
+	emailBrandingHandler := handlers.NewEmailBrandingHandler(emailBrandingRepo, emailService, auditService)
+	emailQueueHandler := handlers.NewEmailQueueHandler(emailQueue, auditService)
+
+	// Setup approval delegation for lecturers who are out of office
+	delegationRepo := repository.NewDelegationRepository(db)
+	approvalRouter := services.NewApprovalRouter(delegationRepo)
+	delegationHandler := handlers.NewDelegationHandler(delegationRepo, lecturerRepo, auditService, notificationService)
+
+	// Setup the workflow engine shared by approval types
+	workflowRepo := repository.NewWorkflowRepository(db)
+	escalation := services.ProdiAdminEscalation(prodiResolver, repository.NewAdminRepository())
+	workflowEngine := services.NewWorkflowEngine(workflowRepo, approvalRouter, escalation, bus, cfg.Workflow)
+	workflowEngine.Register(services.SupervisionWorkflow())
+	workflowEngine.Register(services.RoomBookingWorkflow())
+	workflowEngine.Register(services.PermissionWorkflow())
+	workers.Run("workflow SLA checks", workflowEngine.RunSLAChecks)
+	workflowHandler := handlers.NewWorkflowHandler(workflowEngine)
+
+	// Setup supervision repository and handler
+	supervisionRepo := repository.NewSupervisionRepository(db)
+	supervisionHandler := handlers.NewSupervisionHandler(supervisionRepo, mahasiswaRepo, approvalRouter, workflowEngine, bus, campusClient)
+
+	// Setup attendance repository and handler
+	attendanceRepo := repository.NewAttendanceRepository(db, bus)
+	enrollmentRepo := repository.NewEnrollmentRepository(db)
+	roomRepo := repository.NewRoomRepository(db)
+	faceRepo := repository.NewFaceRepository(db)
+	faceService := services.NewFaceService(faceRepo, cfg.Attendance.FaceMatchThreshold)
+	faceHandler := handlers.NewFaceHandler(faceService, faceRepo, mahasiswaRepo, auditService, campusClient)
+	exportService := services.NewExportService(attendanceRepo)
+	latePolicyRepo := repository.NewLatePolicyRepository(db)
+	latePolicyService := services.NewLatePolicyService(latePolicyRepo, attendanceRepo)
+	latePolicyHandler := handlers.NewLatePolicyHandler(latePolicyRepo, latePolicyService, auditService)
+
+	// Hours in which check-ins are rejected outright, globally or per event type
+	checkInBlackoutRepo := repository.NewCheckInBlackoutRepository(db)
+	checkInBlackoutHandler := handlers.NewCheckInBlackoutHandler(checkInBlackoutRepo, auditService)
+	checkInHours := func(locate middleware.CheckInEventLocator) gin.HandlerFunc {
+		return middleware.RejectCheckInBlackout(checkInBlackoutRepo, locate, cfg.Location)
+	}
+	telemetryRepo := repository.NewCheckInTelemetryRepository(db)
+	telemetryService := services.NewTelemetryService(telemetryRepo, workers, cfg.Attendance.TelemetryRetention)
+	workers.Run("telemetry retention", telemetryService.RunRetention)
+	attestationService, err := services.NewAttestationService(cfg.Attestation, cfg.JWT.SubKey("attestation-challenge"), repository.NewAppAttestKeyRepository(db))
+	if err != nil {
+		log.Fatalf("Failed to set up device attestation: %v", err)
+	}
+	attestationHandler := handlers.NewAttestationHandler(attestationService, auditService)
+	factorRolloutRepo := repository.NewFactorRolloutRepository(db)
+	factorRolloutService := services.NewFactorRolloutService(factorRolloutRepo, faceService, cfg.Wifi, workers)
+	factorRolloutHandler := handlers.NewFactorRolloutHandler(factorRolloutRepo, factorRolloutService, auditService)
+	// Short-lived tokens for classroom displays and room kiosks
+	scopedTokenService := services.NewScopedTokenService(cfg.Scoped)
+	// Rotating session QR codes, accepted by every instance through the shared cache
+	qrSigner, err := qrtoken.NewSigner(cfg.Attendance.QRTokenSecret)
+	if err != nil {
+		log.Fatalf("Failed to set up QR codes: %v", err)
+	}
+	scheduleRepo := repository.NewScheduleRepository(db)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceRepo, enrollmentRepo, mahasiswaRepo, roomRepo, scheduleRepo, faceService, exportService, latePolicyService, telemetryService, attestationService, factorRolloutService, scopedTokenService, prodiResolver, flags, bus, campusClient, qrSigner)
+	// Live check-ins for the lecturer's screen
+	liveAttendanceService := services.NewLiveAttendanceService()
+	liveAttendanceService.Subscribe(bus)
+	liveAttendanceHandler := handlers.NewLiveAttendanceHandler(attendanceRepo, liveAttendanceService, cfg.CORS.AllowedOrigins)
+
+	// Setup attendance gamification, computed nightly for prodi where it is enabled
+	achievementRepo := repository.NewAchievementRepository(db)
+	achievementService := services.NewAchievementService(achievementRepo, mahasiswaRepo, flags, cfg.Achievement.Hour)
+	workers.Run("nightly achievements", achievementService.RunNightly)
+	achievementHandler := handlers.NewAchievementHandler(achievementRepo, mahasiswaRepo, achievementService, prodiResolver, flags, auditService, campusClient)
+
+	// Setup guest event repository and handler
+	guestEventRepo := repository.NewGuestEventRepository(db)
+	guestEventHandler := handlers.NewGuestEventHandler(guestEventRepo)
+	certificateRepo := repository.NewCertificateRepository(db)
+	certificateService := services.NewCertificateService(certificateRepo, cfg.Server.PublicBaseURL, cfg.Documents.CertificateKey, cfg.InstitutionName)
+	certificateHandler := handlers.NewCertificateHandler(certificateService, certificateRepo, guestEventRepo)
+	verificationService := services.NewVerificationService(repository.NewIssuedDocumentRepository(db), certificateService, cfg.Server.PublicBaseURL, cfg.Documents.VerificationKey, cfg.InstitutionName)
+	verificationHandler := handlers.NewVerificationHandler(verificationService)
+
+	// Setup identity handler for accounts holding several roles
+	userRoleRepo := repository.NewUserRoleRepository(db)
+	campusCredentialRepo := repository.NewCampusCredentialRepository(db)
+	authHandler := handlers.NewAuthHandler(userRoleRepo, campusCredentialRepo, auditService)
+	userHandler := handlers.NewUserHandler()
+	ssoHandler := handlers.NewSSOHandler(userRoleRepo, campusCredentialRepo, auditService, cfg.CORS.AllowedOrigins, cfg.Session)
+	identityHandler := handlers.NewIdentityHandler(userRoleRepo, lecturerRepo, assistantRepo, mahasiswaRepo, campusClient)
+
+	// Subscribe modules to domain events
+	auditService.Subscribe(bus)
+	prodiResolver.Subscribe(bus)
+	notificationService.Subscribe(bus)
+	services.SubscribeRoleLinking(bus, userRoleRepo)
+
+	// Students are pseudonymized in warehouse events and in the responses of admins who may not see them
+	pseudonymizer, err := pseudonym.New(cfg.Privacy.PseudonymKey)
+	if err != nil {
+		log.Fatalf("Failed to create pseudonymizer: %v", err)
+	}
+	pseudonymHandler := handlers.NewPseudonymHandler(services.NewPseudonymService(pseudonymizer, mahasiswaRepo), auditService)
+
+	// Stream domain events to the data warehouse through the outbox when configured
+	outboxRepo := repository.NewOutboxRepository(db)
+	if streamRelay, ok := services.NewStreamRelay(outboxRepo, cfg.Stream); ok {
+		bus.SetOutbox(services.NewOutboxForwarder(outboxRepo, pseudonymizer))
+		workers.Run("outbox stream relay", streamRelay.Run)
+		log.Println("Streaming domain events through the outbox")
+	}
+
+	// Setup account merge handler for duplicate users
+	accountMergeRepo := repository.NewAccountMergeRepository(db)
+	accountMergeHandler := handlers.NewAccountMergeHandler(accountMergeRepo, auditService)
+
+	// Setup access level permissions for admin routes
+	accessLevelRepo := repository.NewAccessLevelRepository(db)
+	accessLevelHandler := handlers.NewAccessLevelHandler(accessLevelRepo, auditService)
+	requirePermission := func(permission models.AdminPermission) gin.HandlerFunc {
+		return middleware.RequirePermission(accessLevelRepo, permission)
+	}
+	// Destructive actions stay with super admins even if other levels are granted their permission
+	superAdminOnly := middleware.RequireAccessLevel(models.SuperAdminAccess)
+	sudoHandler := handlers.NewSudoHandler(auditService)
+
+	// Setup backup operations
+	backupRepo := repository.NewBackupRepository(db)
+	backupService := services.NewBackupService(backupRepo, cfg.Database, cfg.Storage.BackupDir)
+	backupHandler := handlers.NewBackupHandler(backupRepo, backupService, auditService)
+
+	// Daily snapshots for the data warehouse
+	warehouseRepo := repository.NewWarehouseRepository(db)
+	warehouseService := services.NewWarehouseExportService(warehouseRepo, pseudonymizer, cfg.Storage.WarehouseDir)
+	warehouseHandler := handlers.NewWarehouseHandler(warehouseRepo, warehouseService, auditService)
+
+	// Setup bulk syncs from the campus API
+	syncRunRepo := repository.NewSyncRunRepository(db)
+	lecturerSyncService := services.NewLecturerSyncService(campusClient, lecturerRepo, syncRunRepo, syncConflictService, bus, workers)
+	prodiSyncService := services.NewProdiSyncService(campusClient, syncRunRepo, lecturerRepo, assistantRepo, mahasiswaRepo, syncConflictService, bus, cfg.Campus.SyncInterval)
+	workers.Run("prodi sync", prodiSyncService.Run)
+	syncHandler := handlers.NewSyncHandler(lecturerSyncService, prodiSyncService, auditService)
+
+	// Recurring jobs; SCHEDULE_<NAME> overrides or turns off each schedule
+	jobScheduler := scheduler.New(cfg.Schedules)
+	tokenRepo := repository.NewTokenRepository()
+	userRepo := repository.NewUserRepository()
+	emailChangeService := services.NewEmailChangeService(tokenRepo, userRepo, emailQueue, auditService, cfg.Server.PublicBaseURL)
+	emailChangeHandler := handlers.NewEmailChangeHandler(emailChangeService, userRepo, auditService)
+	scheduleJob(jobScheduler, "token_purge", "17 * * * *", func(ctx context.Context) error {
+		return tokenRepo.DeleteExpiredTokens()
+	})
+	scheduleJob(jobScheduler, "email_changes", "*/15 * * * *", func(ctx context.Context) error {
+		applied, err := emailChangeService.ApplyDue(ctx)
+		if applied > 0 {
+			log.Printf("[SCHEDULER] Applied %d confirmed email changes", applied)
+		}
+		return err
+	})
+	scheduleJob(jobScheduler, "lecturer_sync", "0 2 * * *", func(ctx context.Context) error {
+		if _, err := lecturerSyncService.Trigger(0); err != nil && !errors.Is(err, services.ErrSyncRunning) {
+			return err
+		}
+		return nil
+	})
+	scheduleJob(jobScheduler, "internship_weekly_summaries", "0 7 * * 1", func(ctx context.Context) error {
+		sent, err := internshipService.SendWeeklySummaries(ctx, time.Now())
+		log.Printf("[SCHEDULER] Sent %d internship weekly summaries", sent)
+		return err
+	})
+	attendanceStatsRepo := repository.NewAttendanceStatsRepository(db)
+	scheduleJob(jobScheduler, "attendance_stats", "*/15 * * * *", func(ctx context.Context) error {
+		return attendanceStatsRepo.Refresh()
+	})
+	scheduleJob(jobScheduler, "warehouse_snapshot", "30 1 * * *", func(ctx context.Context) error {
+		_, err := warehouseService.Snapshot(ctx, services.Yesterday(time.Now()), 0)
+		return err
+	})
+	workers.Run("scheduler", jobScheduler.Run)
+	schedulerHandler := handlers.NewSchedulerHandler(jobScheduler)
+
+	// Outage and maintenance banners of the clients
+	statusRepo := repository.NewStatusMessageRepository(db)
+	statusHandler := handlers.NewStatusHandler(statusRepo, services.NewStatusService(statusRepo), auditService)
+	// Registered on the router rather than the api group so apps held back by the version gate
+	// still see the banners
+	router.GET("/api/v1/status", statusHandler.GetStatus)
+
+	// Setup usage reporting
+	usageHandler := handlers.NewUsageHandler(metrics.DefaultUsage, usageService, auditService)
+	diagnosticsHandler := handlers.NewDiagnosticsHandler()
+	logLevelHandler := handlers.NewLogLevelHandler(auditService)
+	captureHandler := handlers.NewCaptureHandler(capture.Default, auditService)
+
+	// Setup class schedules
+	scheduleHandler := handlers.NewScheduleHandler(scheduleRepo, auditService)
+	roomHandler := handlers.NewRoomHandler(roomRepo, scopedTokenService, auditService)
+	roomBookingHandler := handlers.NewRoomBookingHandler(repository.NewRoomBookingRepository(db), roomRepo, workflowEngine, bus)
+
+	// Setup lecturer office hours, with reminders before each slot
+	officeHourRepo := repository.NewOfficeHourRepository(db)
+	officeHourService := services.NewOfficeHourService(officeHourRepo, notificationService, cfg.OfficeHours.ReminderBefore)
+	workers.Run("office hour reminders", officeHourService.RunReminders)
+	officeHourHandler := handlers.NewOfficeHourHandler(officeHourRepo, bus)
+	enrollmentHandler := handlers.NewEnrollmentHandler(enrollmentRepo, scheduleRepo, mahasiswaRepo, auditService, campusClient)
+
+	// Permission (izin and sakit) requests decided by the lecturer of the course
+	attachmentStore := services.NewAttachmentStore(cfg.Storage.AttachmentDir)
+	permissionRepo := repository.NewPermissionRequestRepository(db)
+	services.SubscribePermissionExcusal(bus, permissionRepo)
+	assignmentRepo := repository.NewAssistantAssignmentRepository(db)
+	permissionHandler := handlers.NewPermissionHandler(permissionRepo, assignmentRepo, enrollmentRepo, scheduleRepo, mahasiswaRepo, attachmentStore, approvalRouter, workflowEngine, bus, campusClient)
+
+	// Setup investigation console for suspected attendance fraud
+	investigationService := services.NewInvestigationService(telemetryRepo, attendanceRepo, auditRepo, permissionRepo)
+	investigationHandler := handlers.NewInvestigationHandler(investigationService, auditService)
+
+	// Per-course permissions granted to assistants by the coordinating lecturer
+	assignmentHandler := handlers.NewAssistantAssignmentHandler(assignmentRepo, scheduleRepo, auditService)
+	coursePermission := func(permission models.AssistantPermission, locate middleware.CourseLocator) gin.HandlerFunc {
+		return middleware.RequireCoursePermission(assignmentRepo, permission, locate)
+	}
+	sessionCourse := middleware.CourseFromSession(attendanceRepo)
+
+	// Notes and files lecturers attach to attendance sessions
+	materialRepo := repository.NewSessionMaterialRepository(db)
+	materialHandler := handlers.NewSessionMaterialHandler(materialRepo, attendanceRepo, enrollmentRepo, mahasiswaRepo, attachmentStore, campusClient)
+	materialCourse := middleware.CourseFromSessionMaterial(materialRepo, attendanceRepo)
+
+	// Archives of complete semesters for audits and disaster-recovery drills
+	semesterArchiveService := services.NewSemesterArchiveService(repository.NewSemesterArchiveRepository(db), attachmentStore)
+	semesterArchiveHandler := handlers.NewSemesterArchiveHandler(semesterArchiveService, auditService)
+
+	reportService := services.NewReportService(cfg.InstitutionName)
+	pddiktiExport := services.NewPDDIKTIExportService(attendanceRepo, scheduleRepo, lecturerRepo, cfg.Attendance.PlannedMeetings)
+	reportHandler := handlers.NewReportHandler(attendanceRepo, scheduleRepo, lecturerRepo, reportService, verificationService, pddiktiExport, attendanceStatsRepo)
+
+	// Minimum attendance for exams and the eligibility lists for exam cards
+	examEligibilityRepo := repository.NewExamEligibilityRepository(db)
+	examEligibilityService := services.NewExamEligibilityService(examEligibilityRepo, attendanceRepo, enrollmentRepo)
+	examEligibilityHandler := handlers.NewExamEligibilityHandler(examEligibilityRepo, mahasiswaRepo, scheduleRepo, examEligibilityService, reportService, auditService, campusClient)
+
+	// Attendance component of final grades for the grading system
+	attendanceScoreRepo := repository.NewAttendanceScoreRepository(db)
+	attendanceScoreHandler := handlers.NewAttendanceScoreHandler(attendanceScoreRepo, services.NewAttendanceScoreService(attendanceScoreRepo, attendanceRepo), auditService)
+
+	// Academic calendar and expected meetings of schedules
+	calendarRepo := repository.NewCalendarRepository(db)
+	sessionCalendar := services.NewSessionCalendar(calendarRepo)
+	calendarHandler := handlers.NewCalendarHandler(calendarRepo, scheduleRepo, sessionCalendar, auditService)
+
+	// Home screen summary of the lecturer app
+	lecturerOverviewService := services.NewLecturerOverviewService(scheduleRepo, attendanceRepo, sessionCalendar, workflowEngine, cache.New("lecturer_overview", cacheDriver))
+	lecturerOverviewService.Subscribe(bus)
+	overviewHandler := handlers.NewOverviewHandler(lecturerOverviewService)
+
+	// Client capability negotiation
+	capabilityHandler := handlers.NewCapabilityHandler(prodiResolver, flags, appVersionPolicy)
+	api.GET("/capabilities", middleware.AuthMiddleware(), capabilityHandler.GetCapabilities)
+
+	// Email open and click tracking (not protected, the token is anonymous)
+	api.GET("/email/o/:token", emailTrackingHandler.TrackOpen)
+	api.GET("/email/c/:token", emailTrackingHandler.TrackClick)
+
+	// CAPTCHA on the admin login, skipped for the clients on the bypass list
+	requireCaptcha := middleware.Captcha(captcha.New(cfg.Captcha), captcha.NewBypassList(cfg.Captcha.BypassClients))
 
 	// Auth routes
 	auth := api.Group("/auth")
@@ -138,57 +625,422 @@ func setupRoutes(router *gin.Engine) {
 		// Campus login endpoint (not protected)
 		auth.POST("/campus/login", authHandler.CampusLogin)
 
+		// Refresh token endpoints (not protected, the refresh token is the credential)
+		auth.POST("/refresh", authHandler.RefreshToken)
+		auth.POST("/logout", authHandler.Logout)
+
 		// Admin login endpoint (not protected)
-		auth.POST("/admin/login", adminHandler.Login)
+		auth.POST("/admin/login", requireCaptcha, adminHandler.Login)
+
+		// Email change links (not protected, the emailed token is the credential)
+		auth.GET("/email-change/confirm/:token", emailChangeHandler.ConfirmChange)
+		auth.GET("/email-change/cancel/:token", emailChangeHandler.CancelByLink)
+
+		// Campus SSO for the web dashboard; the session lives in cookies set by these endpoints
+		sso := auth.Group("/sso")
+		{
+			sso.GET("/login", ssoHandler.LoginPage)
+			sso.POST("/login", ssoHandler.Login)
+			sso.POST("/refresh", ssoHandler.Refresh)
+			sso.POST("/switch-role", ssoHandler.SwitchRole)
+			sso.POST("/logout", ssoHandler.Logout)
+			sso.GET("/session", middleware.AuthMiddleware(), ssoHandler.Session)
+		}
 
 		// Auth required endpoints
 		authRequired := auth.Group("/")
-		authRequired.Use(middleware.AuthMiddleware())
+		authRequired.Use(middleware.AuthMiddleware(), quota)
 		{
 			authRequired.GET("/me", authHandler.GetCurrentUser)
+			authRequired.GET("/roles", identityHandler.GetMyRoles)
+			authRequired.POST("/roles/link", identityHandler.LinkRole)
+			authRequired.PUT("/roles/default", identityHandler.SetDefaultRole)
+			authRequired.POST("/switch-role", identityHandler.SwitchRole)
+			authRequired.GET("/email-tracking", emailTrackingHandler.GetMyPreference)
+			authRequired.PUT("/email-tracking", emailTrackingHandler.SetMyPreference)
+			authRequired.GET("/email-change", emailChangeHandler.GetPendingChange)
+			authRequired.POST("/email-change", emailChangeHandler.RequestChange)
+			authRequired.DELETE("/email-change", emailChangeHandler.CancelChange)
 		}
 	}
 
 	// Mahasiswa routes
 	mahasiswa := api.Group("/mahasiswa")
 	mahasiswa.Use(middleware.AuthMiddleware()) // Protect all mahasiswa routes
+	mahasiswa.Use(middleware.RequireRole(models.StudentType), quota)
 	{
 		mahasiswa.GET("", mahasiswaHandler.GetMahasiswaByUserID)
 		mahasiswa.GET("/", mahasiswaHandler.GetMahasiswaByUserID)
 		mahasiswa.GET("/by-user-id", mahasiswaHandler.GetMahasiswaByUserID)
 		mahasiswa.GET("/by-nim", mahasiswaHandler.GetMahasiswaDetailByNIM)
 		mahasiswa.GET("/complete", mahasiswaHandler.GetMahasiswaComplete)
+		mahasiswa.GET("/activities/progress", activityHandler.GetMyProgress)
+		mahasiswa.GET("/internships", internshipHandler.GetMyInternships)
+		mahasiswa.POST("/internships", internshipHandler.RegisterInternship)
+		mahasiswa.GET("/internships/:id/check-ins", internshipHandler.GetCheckIns)
+		mahasiswa.POST("/internships/:id/check-ins", checkInHours(middleware.FixedCheckInEvent(models.InternshipCheckInEvent)), internshipHandler.CheckIn)
+		mahasiswa.GET("/supervision-meetings", supervisionHandler.GetMyMeetings)
+		mahasiswa.POST("/supervision-meetings", supervisionHandler.LogMeeting)
+		mahasiswa.GET("/courses", enrollmentHandler.GetMyCourses)
+		mahasiswa.GET("/attendance", attendanceHandler.GetMyAttendance)
+		mahasiswa.POST("/attendance/check-in", checkInHours(middleware.FixedCheckInEvent(models.ClassCheckInEvent)), attendanceHandler.CheckIn)
+		mahasiswa.GET("/attendance/sessions/:id", materialHandler.GetSessionDetail)
+		mahasiswa.GET("/attendance/materials/:id/file", materialHandler.GetMaterialFile)
+		mahasiswa.GET("/face", faceHandler.GetMyFace)
+		mahasiswa.POST("/face", faceHandler.RegisterFace)
+		mahasiswa.GET("/achievements", achievementHandler.GetMyAchievements)
+		mahasiswa.GET("/exam-eligibility", examEligibilityHandler.GetMyEligibility)
+		mahasiswa.GET("/permissions", permissionHandler.GetMyRequests)
+		mahasiswa.POST("/permissions", permissionHandler.CreateRequest)
+		mahasiswa.GET("/permissions/:id/attachment", permissionHandler.GetAttachment)
+		mahasiswa.PATCH("/permissions/:id/cancel", permissionHandler.CancelRequest)
+		mahasiswa.DELETE("/face", faceHandler.DeleteMyFace)
+		mahasiswa.GET("/attestation/challenge", attestationHandler.GetChallenge)
+		mahasiswa.POST("/attestation/app-attest-keys", attestationHandler.RegisterAppAttestKey)
+		mahasiswa.GET("/office-hours", officeHourHandler.GetAvailableSlots)
+		mahasiswa.POST("/office-hours/:id/bookings", officeHourHandler.BookSlot)
+		mahasiswa.GET("/office-hours/bookings", officeHourHandler.GetMyBookings)
+		mahasiswa.DELETE("/office-hours/bookings/:id", officeHourHandler.CancelMyBooking)
 	}
 
 	// Admin routes
 	admin := api.Group("/admin")
 	{
-		admin.POST("/login", adminHandler.Login)
+		admin.POST("/login", requireCaptcha, adminHandler.Login)
 
 		// Admin endpoints that require auth
 		adminAuth := admin.Group("")
-		adminAuth.Use(middleware.AdminAuth())
+		adminAuth.Use(middleware.AdminAuth(repository.NewAdminRepository()), quota, middleware.PseudonymizeStudents(accessLevelRepo, pseudonymizer))
 		{
 			adminAuth.GET("/profile", adminHandler.GetAdminProfile)
+
+			// Re-authentication for destructive actions
+			adminAuth.POST("/sudo", sudoHandler.Elevate)
+
+			// User accounts
+			adminAuth.GET("/users", requirePermission(models.ViewUsersPermission), userHandler.ListUsers)
+
+			// Campus user session revocation
+			adminAuth.POST("/campus-users/:campusUserId/revoke-tokens", requirePermission(models.RevokeSessionsPermission), authHandler.RevokeCampusTokens)
+
+			// Duplicate account cleanup
+			adminAuth.POST("/users/merge", requirePermission(models.MergeUsersPermission), superAdminOnly, middleware.RequireSudo(), accountMergeHandler.MergeUsers)
+
+			// API keys for external systems
+			adminAuth.GET("/api-keys", requirePermission(models.ManageAPIKeysPermission), apiKeyHandler.ListAPIKeys)
+			adminAuth.POST("/api-keys", requirePermission(models.ManageAPIKeysPermission), apiKeyHandler.CreateAPIKey)
+			adminAuth.DELETE("/api-keys/:id", requirePermission(models.ManageAPIKeysPermission), superAdminOnly, apiKeyHandler.RevokeAPIKey)
+
+			// Non-academic activity configuration
+			adminAuth.GET("/activities/quotas", requirePermission(models.ManageActivitiesPermission), activityHandler.GetQuotas)
+			adminAuth.PUT("/activities/quotas", requirePermission(models.ManageActivitiesPermission), activityHandler.SetQuota)
+			adminAuth.POST("/activities/coordinators", requirePermission(models.ManageActivitiesPermission), activityHandler.AddCoordinator)
+			adminAuth.DELETE("/activities/coordinators/:userId/:category", requirePermission(models.ManageActivitiesPermission), activityHandler.RemoveCoordinator)
+
+			// Internship supervision
+			adminAuth.POST("/internships/weekly-summaries", requirePermission(models.ManageInternshipsPermission), internshipHandler.SendWeeklySummaries)
+
+			// Class schedules
+			adminAuth.GET("/schedules", requirePermission(models.ManageSchedulesPermission), scheduleHandler.ListSchedules)
+			adminAuth.POST("/schedules", requirePermission(models.ManageSchedulesPermission), scheduleHandler.CreateSchedule)
+			adminAuth.PUT("/schedules/:id", requirePermission(models.ManageSchedulesPermission), scheduleHandler.UpdateSchedule)
+			adminAuth.DELETE("/schedules/:id", requirePermission(models.ManageSchedulesPermission), scheduleHandler.DeleteSchedule)
+			adminAuth.GET("/schedules/:id/expected-sessions", requirePermission(models.ManageSchedulesPermission), calendarHandler.GetExpectedSessions)
+			adminAuth.GET("/courses", requirePermission(models.ManageSchedulesPermission), scheduleHandler.ListCourses)
+
+			// Late policies and attendance credit
+			adminAuth.GET("/late-policies", requirePermission(models.ManageAttendancePoliciesPermission), latePolicyHandler.ListPolicies)
+			adminAuth.POST("/late-policies", requirePermission(models.ManageAttendancePoliciesPermission), latePolicyHandler.CreatePolicy)
+			adminAuth.PUT("/late-policies/:id", requirePermission(models.ManageAttendancePoliciesPermission), latePolicyHandler.UpdatePolicy)
+			adminAuth.DELETE("/late-policies/:id", requirePermission(models.ManageAttendancePoliciesPermission), latePolicyHandler.DeletePolicy)
+			adminAuth.GET("/check-in-blackouts", requirePermission(models.ManageAttendancePoliciesPermission), checkInBlackoutHandler.ListBlackouts)
+			adminAuth.PUT("/check-in-blackouts/:eventType", requirePermission(models.ManageAttendancePoliciesPermission), checkInBlackoutHandler.SaveBlackout)
+			adminAuth.DELETE("/check-in-blackouts/:eventType", requirePermission(models.ManageAttendancePoliciesPermission), checkInBlackoutHandler.DeleteBlackout)
+			adminAuth.GET("/factor-rollouts", requirePermission(models.ManageAttendancePoliciesPermission), factorRolloutHandler.ListRollouts)
+			adminAuth.PUT("/factor-rollouts", requirePermission(models.ManageAttendancePoliciesPermission), factorRolloutHandler.SetRollout)
+			adminAuth.DELETE("/factor-rollouts/:id", requirePermission(models.ManageAttendancePoliciesPermission), factorRolloutHandler.DeleteRollout)
+			adminAuth.GET("/attendance-goals", requirePermission(models.ManageAttendancePoliciesPermission), achievementHandler.ListGoals)
+			adminAuth.PUT("/attendance-goals", requirePermission(models.ManageAttendancePoliciesPermission), achievementHandler.SaveGoal)
+			adminAuth.DELETE("/attendance-goals/:id", requirePermission(models.ManageAttendancePoliciesPermission), achievementHandler.DeleteGoal)
+			adminAuth.POST("/achievements/recompute", requirePermission(models.ManageAttendancePoliciesPermission), achievementHandler.RecomputeAchievements)
+			adminAuth.GET("/exam-eligibility-policies", requirePermission(models.ManageAttendancePoliciesPermission), examEligibilityHandler.ListPolicies)
+			adminAuth.PUT("/exam-eligibility-policies", requirePermission(models.ManageAttendancePoliciesPermission), examEligibilityHandler.SavePolicy)
+			adminAuth.DELETE("/exam-eligibility-policies/:id", requirePermission(models.ManageAttendancePoliciesPermission), examEligibilityHandler.DeletePolicy)
+			adminAuth.GET("/attendance-score-policies", requirePermission(models.ManageAttendancePoliciesPermission), attendanceScoreHandler.ListPolicies)
+			adminAuth.PUT("/attendance-score-policies", requirePermission(models.ManageAttendancePoliciesPermission), attendanceScoreHandler.SavePolicy)
+			adminAuth.DELETE("/attendance-score-policies/:id", requirePermission(models.ManageAttendancePoliciesPermission), attendanceScoreHandler.DeletePolicy)
+
+			// Academic calendar: holidays and exam weeks
+			adminAuth.GET("/calendar", requirePermission(models.ManageSchedulesPermission), calendarHandler.ListEvents)
+			adminAuth.POST("/calendar", requirePermission(models.ManageSchedulesPermission), calendarHandler.CreateEvent)
+			adminAuth.DELETE("/calendar/:id", requirePermission(models.ManageSchedulesPermission), calendarHandler.DeleteEvent)
+
+			// Rooms and their geofence locations
+			adminAuth.GET("/rooms", requirePermission(models.ManageSchedulesPermission), roomHandler.ListRooms)
+			adminAuth.POST("/rooms", requirePermission(models.ManageSchedulesPermission), roomHandler.CreateRoom)
+			adminAuth.PUT("/rooms/:id", requirePermission(models.ManageSchedulesPermission), roomHandler.UpdateRoom)
+			adminAuth.POST("/rooms/:id/kiosk-token", requirePermission(models.ManageSchedulesPermission), roomHandler.CreateKioskToken)
+
+			// Room bookings outside the class schedule
+			adminAuth.GET("/bookings", requirePermission(models.ManageBookingsPermission), roomBookingHandler.ListBookings)
+			adminAuth.PATCH("/bookings/:id/approve", requirePermission(models.ManageBookingsPermission), roomBookingHandler.ApproveBooking)
+			adminAuth.PATCH("/bookings/:id/reject", requirePermission(models.ManageBookingsPermission), roomBookingHandler.RejectBooking)
+
+			// Course enrollment
+			adminAuth.GET("/enrollments", requirePermission(models.ManageEnrollmentsPermission), enrollmentHandler.ListEnrollments)
+			adminAuth.POST("/enrollments/bulk", requirePermission(models.ManageEnrollmentsPermission), enrollmentHandler.BulkEnroll)
+			adminAuth.DELETE("/enrollments/:id", requirePermission(models.ManageEnrollmentsPermission), enrollmentHandler.DeleteEnrollment)
+
+			// Reports
+			adminAuth.GET("/reports/supervision", requirePermission(models.ViewReportsPermission), supervisionHandler.GetFrequencyReport)
+			adminAuth.GET("/reports/approval-sla", requirePermission(models.ViewReportsPermission), workflowHandler.GetSLAReport)
+			adminAuth.GET("/attendance/sessions", requirePermission(models.ViewReportsPermission), attendanceHandler.ListSessions)
+			adminAuth.GET("/reports/attendance.pdf", requirePermission(models.ViewReportsPermission), reportHandler.GetAttendancePDF)
+			adminAuth.GET("/reports/attendance/students", requirePermission(models.ViewReportsPermission), reportHandler.GetStudentStats)
+			adminAuth.GET("/reports/attendance/prodi", requirePermission(models.ViewReportsPermission), reportHandler.GetProdiStats)
+			adminAuth.GET("/courses/:id/exam-eligibility", requirePermission(models.ViewReportsPermission), examEligibilityHandler.GetCourseEligibility)
+			adminAuth.GET("/courses/:id/pddikti", requirePermission(models.ViewReportsPermission), reportHandler.GetPDDIKTIExport)
+			adminAuth.GET("/courses/:id/attendance-score", requirePermission(models.ViewReportsPermission), attendanceScoreHandler.GetCourseScores)
+			adminAuth.GET("/reports/usage", requirePermission(models.ViewReportsPermission), usageHandler.GetUsageSummary)
+			adminAuth.GET("/usage", requirePermission(models.ViewReportsPermission), usageHandler.GetUsage)
+			adminAuth.GET("/usage/quotas", requirePermission(models.ViewReportsPermission), usageHandler.ListQuotas)
+			adminAuth.PUT("/usage/quotas", requirePermission(models.ManageOperationsPermission), usageHandler.SetQuota)
+			adminAuth.DELETE("/usage/quotas/:id", requirePermission(models.ManageOperationsPermission), usageHandler.DeleteQuota)
+			adminAuth.GET("/reports/email-engagement", requirePermission(models.ViewReportsPermission), emailTrackingHandler.GetEngagementReport)
+			adminAuth.GET("/reports/shadow-factors", requirePermission(models.ViewReportsPermission), factorRolloutHandler.GetShadowReport)
+			adminAuth.GET("/reports/check-in-sla", requirePermission(models.ViewReportsPermission), attendanceHandler.GetCheckInSLAReport)
+
+			// Email branding per faculty
+			adminAuth.GET("/email-branding", requirePermission(models.ManageBrandingPermission), emailBrandingHandler.ListBrandings)
+			adminAuth.PUT("/email-branding", requirePermission(models.ManageBrandingPermission), emailBrandingHandler.SaveBranding)
+			adminAuth.GET("/email-branding/preview", requirePermission(models.ManageBrandingPermission), emailBrandingHandler.PreviewBranding)
+			adminAuth.DELETE("/email-branding/:id", requirePermission(models.ManageBrandingPermission), emailBrandingHandler.DeleteBranding)
+
+			// Emails the queue failed to send
+			adminAuth.GET("/emails/failed", requirePermission(models.ManageOperationsPermission), emailQueueHandler.ListFailed)
+			adminAuth.POST("/emails/:id/retry", requirePermission(models.ManageOperationsPermission), emailQueueHandler.RetryFailed)
+
+			// Fraud investigations
+			adminAuth.GET("/investigations/students/:id", requirePermission(models.InvestigateStudentsPermission), investigationHandler.GetStudentTimeline)
+			adminAuth.GET("/investigations/devices/:deviceId", requirePermission(models.InvestigateStudentsPermission), investigationHandler.GetDeviceActivity)
+
+			// Status banners
+			adminAuth.GET("/status-messages", requirePermission(models.ManageOperationsPermission), statusHandler.ListMessages)
+			adminAuth.POST("/status-messages", requirePermission(models.ManageOperationsPermission), statusHandler.CreateMessage)
+			adminAuth.PUT("/status-messages/:id", requirePermission(models.ManageOperationsPermission), statusHandler.UpdateMessage)
+			adminAuth.DELETE("/status-messages/:id", requirePermission(models.ManageOperationsPermission), statusHandler.DeleteMessage)
+
+			// Bulk syncs from the campus API
+			adminAuth.GET("/sync/lecturers", requirePermission(models.SyncCampusDataPermission), syncHandler.ListLecturerSyncs)
+			adminAuth.POST("/sync/lecturers", requirePermission(models.SyncCampusDataPermission), syncHandler.TriggerLecturerSync)
+			adminAuth.GET("/sync/prodi", requirePermission(models.SyncCampusDataPermission), syncHandler.ListProdiSyncs)
+			adminAuth.POST("/sync/prodi/:id", requirePermission(models.SyncCampusDataPermission), syncHandler.TriggerProdiSync)
+			adminAuth.GET("/sync/prodi/runs/:id", requirePermission(models.SyncCampusDataPermission), syncHandler.GetProdiSyncProgress)
+			adminAuth.GET("/sync/prodi/runs/:id/errors", requirePermission(models.SyncCampusDataPermission), syncHandler.GetProdiSyncErrors)
+
+			// Access level permissions
+			adminAuth.GET("/access-levels", requirePermission(models.ManagePermissionsPermission), accessLevelHandler.GetAccessLevels)
+			adminAuth.PUT("/access-levels/:level", requirePermission(models.ManagePermissionsPermission), superAdminOnly, middleware.RequireSudo(), accessLevelHandler.UpdateAccessLevel)
+			adminAuth.DELETE("/access-levels/:level", requirePermission(models.ManagePermissionsPermission), superAdminOnly, middleware.RequireSudo(), accessLevelHandler.ResetAccessLevel)
+
+			// Re-identification of pseudonymized students
+			adminAuth.POST("/pseudonyms/reidentify", requirePermission(models.DeanonymizeStudentsPermission), superAdminOnly, middleware.RequireSudo(), pseudonymHandler.Reidentify)
+
+			// Operations
+			operations := adminAuth.Group("/operations")
+			operations.Use(requirePermission(models.ManageOperationsPermission))
+			{
+				operations.GET("/backups", backupHandler.ListBackups)
+				operations.POST("/backups", backupHandler.TriggerBackup)
+				operations.POST("/backups/:id/restore-drills", backupHandler.RecordRestoreDrill)
+				operations.GET("/restore-drills", backupHandler.ListRestoreDrills)
+				operations.GET("/warehouse-snapshots", warehouseHandler.ListSnapshots)
+				operations.POST("/warehouse-snapshots", warehouseHandler.TriggerSnapshot)
+				operations.GET("/semester-archives", semesterArchiveHandler.ExportSemester)
+				operations.POST("/semester-archives", middleware.RequireSudo(), semesterArchiveHandler.ImportSemester)
+				operations.GET("/jobs", schedulerHandler.GetJobs)
+				operations.GET("/slow-queries", diagnosticsHandler.GetSlowQueries)
+				operations.GET("/caches", diagnosticsHandler.GetCacheStats)
+				operations.GET("/campus-alerts", campusAlertHandler.GetCampusAlerts)
+				operations.GET("/log-levels", logLevelHandler.GetLogLevels)
+				operations.PUT("/log-levels", logLevelHandler.UpdateLogLevels)
+				operations.GET("/captures", captureHandler.GetCaptures)
+				operations.GET("/captures/rules", captureHandler.GetCaptureRules)
+				operations.POST("/captures/rules", captureHandler.CreateCaptureRule)
+				operations.DELETE("/captures/rules/:id", captureHandler.DeleteCaptureRule)
+			}
 		}
 	}
 
 	// Lecturer routes
 	lecturer := api.Group("/lecturer")
 	lecturer.Use(middleware.AuthMiddleware()) // Protect all lecturer routes
+	lecturer.Use(middleware.RequireRole(models.LecturerType), quota)
 	{
 		lecturer.GET("/profile", lecturerHandler.GetLecturerProfile)
 		lecturer.POST("/sync", lecturerHandler.SyncLecturerProfile)
 		lecturer.PATCH("/profile", lecturerHandler.UpdateLecturerProfile)
+		lecturer.GET("/profile/conflicts", lecturerHandler.GetProfileConflicts)
+		lecturer.POST("/profile/conflicts/:id/resolve", lecturerHandler.ResolveProfileConflict)
+		lecturer.GET("/supervision-meetings", supervisionHandler.GetSupervisedMeetings)
+		lecturer.PATCH("/supervision-meetings/:id/confirm", supervisionHandler.ConfirmMeeting)
+		lecturer.PATCH("/supervision-meetings/:id/reject", supervisionHandler.RejectMeeting)
+		lecturer.GET("/overview", overviewHandler.GetLecturerOverview)
+		lecturer.GET("/permissions", permissionHandler.GetLecturerRequests)
+		lecturer.GET("/permissions/:id/attachment", permissionHandler.GetAttachment)
+		lecturer.PATCH("/permissions/:id/approve", permissionHandler.ApproveRequest)
+		lecturer.PATCH("/permissions/:id/reject", permissionHandler.RejectRequest)
+		lecturer.GET("/schedules", scheduleHandler.GetMySchedules)
+		lecturer.GET("/schedules/:id/expected-sessions", calendarHandler.GetMyExpectedSessions)
+		lecturer.GET("/delegations", delegationHandler.GetMyDelegations)
+		lecturer.POST("/delegations", delegationHandler.CreateDelegation)
+		lecturer.DELETE("/delegations/:id", delegationHandler.RevokeDelegation)
+		lecturer.GET("/attendance/sessions", attendanceHandler.GetMySessions)
+		lecturer.POST("/attendance/sessions", attendanceHandler.OpenSession)
+		lecturer.GET("/attendance/sessions/:id/records", attendanceHandler.GetSessionRecords)
+		lecturer.GET("/attendance/sessions/:id/check-in-health", attendanceHandler.GetCheckInHealth)
+		lecturer.PATCH("/attendance/records/:id", attendanceHandler.UpdateRecord)
+		lecturer.PATCH("/attendance/sessions/:id/records/:studentId", attendanceHandler.EditStudentRecord)
+		lecturer.GET("/attendance/sessions/:id/edits", attendanceHandler.GetRecordEdits)
+		lecturer.GET("/attendance/sessions/:id/qr", attendanceHandler.GetSessionQR)
+		lecturer.POST("/attendance/sessions/:id/display-token", attendanceHandler.CreateDisplayToken)
+		lecturer.PATCH("/attendance/sessions/:id/open", attendanceHandler.StartSession)
+		lecturer.PATCH("/attendance/sessions/:id/close", attendanceHandler.CloseSession)
+		lecturer.POST("/attendance/sessions/:id/anchor", attendanceHandler.AnchorSession)
+		lecturer.PATCH("/attendance/sessions/:id/optional", attendanceHandler.SetSessionOptional)
+		lecturer.GET("/attendance/sessions/:id/materials", materialHandler.GetMaterials)
+		lecturer.POST("/attendance/sessions/:id/materials", materialHandler.AddMaterial)
+		lecturer.DELETE("/attendance/materials/:id", materialHandler.DeleteMaterial)
+		lecturer.GET("/attendance/materials/:id/file", materialHandler.GetMaterialFile)
+		lecturer.GET("/courses/:id/attendance/recap", attendanceHandler.GetCourseRecap)
+		lecturer.GET("/courses/:id/attendance/export", attendanceHandler.ExportCourseAttendance)
+		lecturer.GET("/courses/:id/attendance/score", attendanceScoreHandler.GetMyCourseScores)
+		lecturer.GET("/assistants", assignmentHandler.ListAssignments)
+		lecturer.PUT("/assistants", assignmentHandler.SaveAssignment)
+		lecturer.DELETE("/assistants/:id", assignmentHandler.DeleteAssignment)
+		lecturer.GET("/office-hours", officeHourHandler.GetMySlots)
+		lecturer.POST("/office-hours", officeHourHandler.CreateSlots)
+		lecturer.GET("/office-hours/:id/bookings", officeHourHandler.GetSlotBookings)
+		lecturer.DELETE("/office-hours/:id", officeHourHandler.CancelSlot)
 	}
 
 	// Assistant routes
 	assistant := api.Group("/assistant")
 	assistant.Use(middleware.AuthMiddleware()) // Protect all assistant routes
+	assistant.Use(middleware.RequireRole(models.AssistantType), quota)
 	{
 		assistant.GET("/profile", assistantHandler.GetAssistantProfile)
 		assistant.POST("/sync", assistantHandler.SyncAssistantProfile)
 		assistant.PATCH("/profile", assistantHandler.UpdateAssistantProfile)
+		assistant.GET("/profile/conflicts", assistantHandler.GetProfileConflicts)
+		assistant.POST("/profile/conflicts/:id/resolve", assistantHandler.ResolveProfileConflict)
+		assistant.GET("/courses", assignmentHandler.GetMyCourses)
+		// Sessions of the assistant's approved room bookings, and of the courses they are assigned to
+		assistant.GET("/attendance/sessions", attendanceHandler.GetMySessions)
+		assistant.POST("/attendance/sessions", coursePermission(models.OpenSessionsPermission, middleware.CourseFromBody()), attendanceHandler.OpenSession)
+		assistant.GET("/attendance/sessions/:id/records", coursePermission(models.ViewCourseReportsPermission, sessionCourse), attendanceHandler.GetSessionRecords)
+		assistant.GET("/attendance/sessions/:id/check-in-health", coursePermission(models.ViewCourseReportsPermission, sessionCourse), attendanceHandler.GetCheckInHealth)
+		assistant.GET("/attendance/sessions/:id/qr", coursePermission(models.OpenSessionsPermission, sessionCourse), attendanceHandler.GetSessionQR)
+		assistant.POST("/attendance/sessions/:id/display-token", coursePermission(models.OpenSessionsPermission, sessionCourse), attendanceHandler.CreateDisplayToken)
+		assistant.PATCH("/attendance/sessions/:id/open", coursePermission(models.OpenSessionsPermission, sessionCourse), attendanceHandler.StartSession)
+		assistant.PATCH("/attendance/sessions/:id/close", coursePermission(models.OpenSessionsPermission, sessionCourse), attendanceHandler.CloseSession)
+		assistant.POST("/attendance/sessions/:id/anchor", coursePermission(models.OpenSessionsPermission, sessionCourse), attendanceHandler.AnchorSession)
+		assistant.PATCH("/attendance/sessions/:id/optional", coursePermission(models.OpenSessionsPermission, sessionCourse), attendanceHandler.SetSessionOptional)
+		assistant.GET("/attendance/sessions/:id/materials", coursePermission(models.OpenSessionsPermission, sessionCourse), materialHandler.GetMaterials)
+		assistant.POST("/attendance/sessions/:id/materials", coursePermission(models.OpenSessionsPermission, sessionCourse), materialHandler.AddMaterial)
+		assistant.DELETE("/attendance/materials/:id", coursePermission(models.OpenSessionsPermission, materialCourse), materialHandler.DeleteMaterial)
+		assistant.GET("/attendance/materials/:id/file", coursePermission(models.OpenSessionsPermission, materialCourse), materialHandler.GetMaterialFile)
+		assistant.PATCH("/attendance/records/:id", coursePermission(models.EditRecordsPermission, middleware.CourseFromRecord(attendanceRepo)), attendanceHandler.UpdateRecord)
+		assistant.PATCH("/attendance/sessions/:id/records/:studentId", coursePermission(models.EditRecordsPermission, sessionCourse), attendanceHandler.EditStudentRecord)
+		assistant.GET("/attendance/sessions/:id/edits", coursePermission(models.EditRecordsPermission, sessionCourse), attendanceHandler.GetRecordEdits)
+		assistant.GET("/courses/:id/attendance/recap", coursePermission(models.ViewCourseReportsPermission, middleware.CourseFromPath("id")), attendanceHandler.GetCourseRecap)
+		assistant.GET("/courses/:id/attendance/score", coursePermission(models.ViewCourseReportsPermission, middleware.CourseFromPath("id")), attendanceScoreHandler.GetMyCourseScores)
+		assistant.GET("/courses/:id/attendance/export", coursePermission(models.ViewCourseReportsPermission, middleware.CourseFromPath("id")), attendanceHandler.ExportCourseAttendance)
+		assistant.GET("/permissions", permissionHandler.GetAssistantRequests)
+		assistant.GET("/permissions/:id/attachment", coursePermission(models.ApproveExcusesPermission, middleware.CourseFromPermissionRequest(permissionRepo)), permissionHandler.GetAttachment)
+		assistant.PATCH("/permissions/:id/approve", coursePermission(models.ApproveExcusesPermission, middleware.CourseFromPermissionRequest(permissionRepo)), permissionHandler.ApproveRequest)
+		assistant.PATCH("/permissions/:id/reject", coursePermission(models.ApproveExcusesPermission, middleware.CourseFromPermissionRequest(permissionRepo)), permissionHandler.RejectRequest)
+	}
+
+	// Room booking routes for lecturers and assistants
+	bookings := api.Group("/bookings")
+	bookings.Use(middleware.AuthMiddleware())
+	bookings.Use(middleware.RequireRole(models.LecturerType, models.AssistantType), quota)
+	{
+		bookings.GET("", roomBookingHandler.GetMyBookings)
+		bookings.POST("", roomBookingHandler.CreateBooking)
+		bookings.PATCH("/:id/cancel", roomBookingHandler.CancelBooking)
+	}
+
+	// Notification routes
+	notifications := api.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware(), quota)
+	{
+		notifications.GET("", notificationHandler.GetNotifications)
+		notifications.PATCH("/:id/read", notificationHandler.MarkAsRead)
+	}
+
+	// Non-academic activity routes (coordinator role checked per activity category)
+	activities := api.Group("/activities")
+	activities.Use(middleware.AuthMiddleware(), quota)
+	{
+		activities.GET("", activityHandler.ListActivities)
+		activities.POST("", activityHandler.CreateActivity)
+		activities.GET("/:id/participants", activityHandler.GetParticipants)
+		activities.POST("/:id/participants", checkInHours(middleware.CheckInEventFromActivity(activityRepo)), activityHandler.RecordParticipants)
+	}
+
+	// Public event routes for guests without a campus account
+	events := api.Group("/events")
+	{
+		events.GET("/:id", guestEventHandler.GetPublicEvent)
+		events.POST("/:id/register", guestEventHandler.RegisterGuest)
+		events.GET("/:id/certificate", certificateHandler.DownloadMyCertificate)
+
+		// Organizer endpoints
+		organizer := events.Group("")
+		organizer.Use(middleware.AuthMiddleware(), quota)
+		{
+			organizer.GET("", guestEventHandler.GetMyEvents)
+			organizer.POST("", guestEventHandler.CreateEvent)
+			organizer.POST("/:id/check-in", checkInHours(middleware.FixedCheckInEvent(models.GuestCheckInEvent)), guestEventHandler.CheckInGuest)
+			organizer.GET("/:id/attendees", guestEventHandler.GetAttendees)
+			organizer.GET("/:id/attendees.csv", guestEventHandler.ExportAttendeesCSV)
+			organizer.GET("/:id/certificate-template", certificateHandler.GetTemplate)
+			organizer.PUT("/:id/certificate-template", certificateHandler.SaveTemplate)
+			organizer.GET("/:id/certificates", certificateHandler.GetCertificates)
+			organizer.POST("/:id/certificates", certificateHandler.IssueCertificates)
+			organizer.GET("/:id/certificates/:certificateId/pdf", certificateHandler.DownloadCertificate)
+			organizer.PATCH("/:id/certificates/:certificateId/revoke", certificateHandler.RevokeCertificate)
+		}
+	}
+
+	// Public verification of participation certificates
+	api.GET("/certificates/:code", certificateHandler.VerifyCertificate)
+
+	// Public verification of the code printed on certificates and exported reports
+	router.GET("/verify/:code", verificationHandler.Verify)
+
+	// Classroom displays and kiosks (authorized by scoped tokens, never by user tokens)
+	api.GET("/display/sessions/:id/qr", middleware.RequireDisplayToken("id"), attendanceHandler.GetDisplayQR)
+	api.GET("/kiosk/rooms/:id/sessions", middleware.RequireKioskToken("id"), attendanceHandler.GetKioskSessions)
+
+	// Live attendance stream (WebSocket) for the lecturer's screen or a classroom display
+	api.GET("/ws/attendance/sessions/:id", middleware.RequireDisplayTokenOrAuth("id"), liveAttendanceHandler.StreamSession)
+
+	// Internship mentor confirmation links (public, authorized by the emailed token)
+	api.GET("/internships/confirm/:token", internshipHandler.ConfirmCheckIn)
+
+	// Proctoring system routes (API key auth, each route checks its own operation)
+	proctoring := api.Group("/proctoring")
+	{
+		proctoring.POST("/exam-attendance",
+			middleware.APIKeyAuth(apiKeyRepo, models.ExamAttendanceWriteOperation),
+			checkInHours(middleware.FixedCheckInEvent(models.ExamCheckInEvent)),
+			proctoringHandler.MarkExamAttendance)
+		proctoring.GET("/exam-attendance/:courseCode",
+			middleware.APIKeyAuth(apiKeyRepo, models.ExamAttendanceReadOperation),
+			proctoringHandler.GetExamAttendance)
 	}
 
 	// Add more API routes here
@@ -0,0 +1,184 @@
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+)
+
+// appAttestNonceOID is the certificate extension holding the nonce of an App Attest attestation
+var appAttestNonceOID = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 8, 2}
+
+var (
+	// aaguidProduction identifies keys attested in the production App Attest environment
+	aaguidProduction = []byte("appattest\x00\x00\x00\x00\x00\x00\x00")
+	// aaguidDevelopment identifies keys attested in the development environment
+	aaguidDevelopment = []byte("appattestdevelop")
+)
+
+// AppAttest verifies App Attest keys when they are registered and the assertions they sign
+// on every check-in
+type AppAttest struct {
+	appIDHash        []byte
+	roots            *x509.CertPool
+	allowDevelopment bool
+}
+
+// NewAppAttest creates an AppAttest for the app ID ("<team ID>.<bundle ID>"), trusting the
+// Apple App Attestation root CA in rootCAFile (PEM). Keys attested by development builds are
+// only accepted when allowDevelopment is set.
+func NewAppAttest(appID, rootCAFile string, allowDevelopment bool) (*AppAttest, error) {
+	pem, err := os.ReadFile(rootCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read App Attest root CA: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in App Attest root CA file %s", rootCAFile)
+	}
+
+	return &AppAttest{
+		appIDHash:        sha256Sum([]byte(appID)),
+		roots:            roots,
+		allowDevelopment: allowDevelopment,
+	}, nil
+}
+
+// VerifyAttestation verifies the attestation of a new key made with challenge and returns the
+// key's public key in PKIX form. keyID is the base64 key identifier reported by the device.
+func (a *AppAttest) VerifyAttestation(keyID string, attestation []byte, challenge string) ([]byte, error) {
+	keyIDBytes, err := base64.StdEncoding.DecodeString(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid key ID", ErrRejected)
+	}
+
+	object, err := cborMap(attestation)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	statement, _ := object["attStmt"].(map[string]interface{})
+	authData, _ := object["authData"].([]byte)
+	chain, _ := statement["x5c"].([]interface{})
+	if object["fmt"] != "apple-appattest" || len(chain) == 0 || len(authData) < 55 {
+		return nil, fmt.Errorf("%w: malformed attestation", ErrRejected)
+	}
+
+	// The credential certificate must chain up to Apple's root
+	certificates := make([]*x509.Certificate, 0, len(chain))
+	for _, entry := range chain {
+		der, _ := entry.([]byte)
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid certificate", ErrRejected)
+		}
+		certificates = append(certificates, certificate)
+	}
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	credential := certificates[0]
+	if _, err := credential.Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		CurrentTime:   time.Now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, fmt.Errorf("%w: certificate chain: %v", ErrRejected, err)
+	}
+
+	// The certificate must carry the nonce of this attestation and challenge
+	nonce := sha256Sum(authData, sha256Sum([]byte(challenge)))
+	if !bytes.Equal(certificateNonce(credential), nonce) {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrRejected)
+	}
+
+	// The key ID is the hash of the attested public key
+	publicKey, ok := credential.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected key type", ErrRejected)
+	}
+	point, err := publicKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	if !bytes.Equal(sha256Sum(point.Bytes()), keyIDBytes) {
+		return nil, fmt.Errorf("%w: key ID mismatch", ErrRejected)
+	}
+
+	// The authenticator data must be for this app, a fresh key and the expected environment
+	aaguid := authData[37:53]
+	credentialIDLength := int(binary.BigEndian.Uint16(authData[53:55]))
+	switch {
+	case !bytes.Equal(authData[:32], a.appIDHash):
+		return nil, fmt.Errorf("%w: attested for another app", ErrRejected)
+	case uint32At(authData, 33) != 0:
+		return nil, fmt.Errorf("%w: key was already used", ErrRejected)
+	case !bytes.Equal(aaguid, aaguidProduction) && !(a.allowDevelopment && bytes.Equal(aaguid, aaguidDevelopment)):
+		return nil, fmt.Errorf("%w: unexpected App Attest environment", ErrRejected)
+	case len(authData) < 55+credentialIDLength || !bytes.Equal(authData[55:55+credentialIDLength], keyIDBytes):
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrRejected)
+	}
+
+	return x509.MarshalPKIXPublicKey(publicKey)
+}
+
+// certificateNonce returns the nonce embedded in an App Attest credential certificate
+func certificateNonce(certificate *x509.Certificate) []byte {
+	for _, extension := range certificate.Extensions {
+		if !extension.Id.Equal(appAttestNonceOID) {
+			continue
+		}
+		var value struct {
+			Nonce []byte `asn1:"tag:1,explicit"`
+		}
+		if _, err := asn1.Unmarshal(extension.Value, &value); err == nil {
+			return value.Nonce
+		}
+	}
+	return nil
+}
+
+// VerifyAssertion verifies an assertion signed for challenge by a registered key and returns
+// its counter, which must be above the last counter seen for the key so assertions cannot be
+// replayed
+func (a *AppAttest) VerifyAssertion(publicKeyDER []byte, lastCounter uint32, assertion []byte, challenge string) (uint32, error) {
+	parsed, err := x509.ParsePKIXPublicKey(publicKeyDER)
+	if err != nil {
+		return 0, err
+	}
+	publicKey, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return 0, fmt.Errorf("unexpected App Attest key type")
+	}
+
+	object, err := cborMap(assertion)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	signature, _ := object["signature"].([]byte)
+	authData, _ := object["authenticatorData"].([]byte)
+	if len(signature) == 0 || len(authData) < 37 {
+		return 0, fmt.Errorf("%w: malformed assertion", ErrRejected)
+	}
+
+	nonce := sha256Sum(authData, sha256Sum([]byte(challenge)))
+	if !ecdsa.VerifyASN1(publicKey, nonce, signature) {
+		return 0, fmt.Errorf("%w: invalid signature", ErrRejected)
+	}
+	if !bytes.Equal(authData[:32], a.appIDHash) {
+		return 0, fmt.Errorf("%w: asserted for another app", ErrRejected)
+	}
+
+	counter := uint32At(authData, 33)
+	if counter <= lastCounter {
+		return 0, fmt.Errorf("%w: assertion was replayed", ErrRejected)
+	}
+	return counter, nil
+}
@@ -0,0 +1,122 @@
+package attestation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// errCBOR is returned for input the CBOR decoder does not understand
+var errCBOR = errors.New("invalid CBOR")
+
+// decodeCBOR decodes the subset of CBOR used by App Attest: integers, byte and text strings,
+// arrays, maps with text keys and tags, all with definite lengths
+func decodeCBOR(data []byte) (interface{}, error) {
+	value, rest, err := decodeCBORItem(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data", errCBOR)
+	}
+	return value, nil
+}
+
+func decodeCBORItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > 16 || len(data) == 0 {
+		return nil, nil, errCBOR
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	// The argument is the value of integers, the length of strings and containers, or the tag
+	var arg uint64
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < size {
+			return nil, nil, errCBOR
+		}
+		for _, b := range data[:size] {
+			arg = arg<<8 | uint64(b)
+		}
+		data = data[size:]
+	default:
+		return nil, nil, fmt.Errorf("%w: indefinite lengths are not supported", errCBOR)
+	}
+
+	switch major {
+	case 0:
+		return arg, data, nil
+	case 1:
+		return -1 - int64(arg), data, nil
+	case 2, 3:
+		if uint64(len(data)) < arg {
+			return nil, nil, errCBOR
+		}
+		value := data[:arg]
+		if major == 3 {
+			return string(value), data[arg:], nil
+		}
+		return append([]byte(nil), value...), data[arg:], nil
+	case 4:
+		if arg > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, rest, err := decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items, data = append(items, item), rest
+		}
+		return items, data, nil
+	case 5:
+		if arg > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+		entries := make(map[string]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, rest, err := decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: map keys must be text", errCBOR)
+			}
+			value, rest, err := decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			entries[name], data = value, rest
+		}
+		return entries, data, nil
+	case 6:
+		// Tags only annotate the item that follows
+		return decodeCBORItem(data, depth+1)
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported major type %d", errCBOR, major)
+	}
+}
+
+// cborMap decodes a CBOR map
+func cborMap(data []byte) (map[string]interface{}, error) {
+	value, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a map", errCBOR)
+	}
+	return entries, nil
+}
+
+// uint32At reads a big-endian counter from authenticator data
+func uint32At(data []byte, offset int) uint32 {
+	return binary.BigEndian.Uint32(data[offset : offset+4])
+}
@@ -0,0 +1,200 @@
+package attestation
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// playIntegrityScope is the OAuth scope of the Play Integrity API
+	playIntegrityScope = "https://www.googleapis.com/auth/playintegrity"
+	// playIntegrityURL decodes integrity tokens of a package
+	playIntegrityURL = "https://playintegrity.googleapis.com/v1/%s:decodeIntegrityToken"
+)
+
+// PlayIntegrity verifies Play Integrity tokens by having Google decode them with the
+// credentials of a service account linked to the app in the Play Console
+type PlayIntegrity struct {
+	packageName string
+	account     serviceAccount
+	client      *http.Client
+
+	mutex       sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// serviceAccount holds the fields of a Google service account key file that are needed to
+// get an access token
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+	key         *rsa.PrivateKey
+}
+
+// NewPlayIntegrity creates a PlayIntegrity for the app with the given package name, reading
+// the service account key file at credentialsFile
+func NewPlayIntegrity(packageName, credentialsFile string) (*PlayIntegrity, error) {
+	raw, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Play Integrity credentials: %w", err)
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("invalid Play Integrity credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("invalid Play Integrity credentials: client_email and token_uri are required")
+	}
+	if account.key, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey)); err != nil {
+		return nil, fmt.Errorf("invalid Play Integrity credentials: %w", err)
+	}
+
+	return &PlayIntegrity{
+		packageName: packageName,
+		account:     account,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// integrityVerdict is the decoded payload of an integrity token
+type integrityVerdict struct {
+	TokenPayloadExternal struct {
+		RequestDetails struct {
+			RequestPackageName string `json:"requestPackageName"`
+			Nonce              string `json:"nonce"`
+			TimestampMillis    string `json:"timestampMillis"`
+		} `json:"requestDetails"`
+		AppIntegrity struct {
+			AppRecognitionVerdict string `json:"appRecognitionVerdict"`
+		} `json:"appIntegrity"`
+		DeviceIntegrity struct {
+			DeviceRecognitionVerdict []string `json:"deviceRecognitionVerdict"`
+		} `json:"deviceIntegrity"`
+	} `json:"tokenPayloadExternal"`
+}
+
+// Verify checks that an integrity token was requested with challenge as its nonce by the
+// Play-recognized app on a device that passes device integrity
+func (p *PlayIntegrity) Verify(ctx context.Context, integrityToken, challenge string) error {
+	verdict, err := p.decode(ctx, integrityToken)
+	if err != nil {
+		return err
+	}
+
+	payload := verdict.TokenPayloadExternal
+	switch {
+	case payload.RequestDetails.RequestPackageName != p.packageName:
+		return fmt.Errorf("%w: token was requested by package %q", ErrRejected, payload.RequestDetails.RequestPackageName)
+	case payload.RequestDetails.Nonce != challenge:
+		return fmt.Errorf("%w: token is bound to another challenge", ErrRejected)
+	case payload.AppIntegrity.AppRecognitionVerdict != "PLAY_RECOGNIZED":
+		return fmt.Errorf("%w: app is %s", ErrRejected, strings.ToLower(payload.AppIntegrity.AppRecognitionVerdict))
+	}
+	for _, label := range payload.DeviceIntegrity.DeviceRecognitionVerdict {
+		if label == "MEETS_DEVICE_INTEGRITY" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: device does not meet device integrity", ErrRejected)
+}
+
+// decode asks Google to decrypt and verify an integrity token
+func (p *PlayIntegrity) decode(ctx context.Context, integrityToken string) (*integrityVerdict, error) {
+	accessToken, err := p.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := json.Marshal(map[string]string{"integrity_token": integrityToken})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(playIntegrityURL, p.packageName), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Play Integrity API unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		return nil, fmt.Errorf("%w: integrity token could not be decoded", ErrRejected)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("Play Integrity API returned status %d", resp.StatusCode)
+	}
+
+	var verdict integrityVerdict
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("invalid Play Integrity response: %w", err)
+	}
+	return &verdict, nil
+}
+
+// token returns a cached access token of the service account, getting a new one shortly
+// before it expires
+func (p *PlayIntegrity) token(ctx context.Context) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.accessToken != "" && time.Now().Add(time.Minute).Before(p.expiresAt) {
+		return p.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": playIntegrityScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(p.account.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Google token endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Google token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("invalid Google token response")
+	}
+
+	p.accessToken = result.AccessToken
+	p.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"delpresence-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey is the gin context key the authenticated principal is stored under
+const principalKey = "auth.principal"
+
+// Principal describes who is making the current request
+type Principal struct {
+	UserID              uint            // Local user ID, or the campus user ID for campus-authenticated users
+	CampusUserID        int             // Set for campus-authenticated users
+	CampusAuthenticated bool            // True when authenticated through the campus API
+	UserType            models.UserType // Account type of local users and admins
+	Email               string
+	FirstName           string
+	MiddleName          string
+	LastName            string
+	AdminID             uint   // Set on admin routes
+	AccessLevel         string // Set on admin routes
+	Roles               []string
+	ActiveRole          string
+	Elevated            bool // Admin re-authenticated recently (sudo mode)
+	// CourseGrant is the assignment that lets an assistant act on the course of the request.
+	// It is set by middleware.RequireCoursePermission when the assistant does not own the resource.
+	CourseGrant *models.AssistantAssignment
+}
+
+// SetPrincipal stores the authenticated principal in the request context
+func SetPrincipal(c *gin.Context, principal *Principal) {
+	c.Set(principalKey, principal)
+}
+
+// FromContext returns the authenticated principal of the request
+func FromContext(c *gin.Context) (*Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*Principal)
+	return principal, ok && principal != nil
+}
+
+// IsAdmin reports whether the principal authenticated as an admin
+func (p *Principal) IsAdmin() bool {
+	return p.UserType == models.AdminType
+}
+
+// IdentitySource returns the ID space of the principal's UserID, for looking up its roles
+func (p *Principal) IdentitySource() models.IdentitySource {
+	if p.CampusAuthenticated {
+		return models.CampusIdentity
+	}
+	return models.LocalIdentity
+}
+
+// HasRole reports whether the principal holds the given role
+func (p *Principal) HasRole(role models.UserType) bool {
+	for _, held := range p.Roles {
+		if held == string(role) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scopedTokenKey is the gin context key the validated scoped token is stored under
+const scopedTokenKey = "auth.scoped_token"
+
+// Capabilities a scoped token can grant
+const (
+	// DisplayCapability lets a projector or shared screen show the rotating QR code of a session
+	DisplayCapability = "display"
+	// KioskCapability lets a device mounted in a room show the QR codes of the sessions open there
+	KioskCapability = "kiosk"
+)
+
+// Resources a scoped token can be bound to
+const (
+	SessionResource = "session"
+	RoomResource    = "room"
+)
+
+// ScopedToken describes the scoped token a request was authorized with
+type ScopedToken struct {
+	Scope    string
+	IssuedBy uint
+}
+
+// Scope builds the scope of a token granting capability on one resource, e.g.
+// "display:session:123"
+func Scope(capability, resource string, id uint) string {
+	return fmt.Sprintf("%s:%s:%d", capability, resource, id)
+}
+
+// SetScopedToken stores the validated scoped token in the request context
+func SetScopedToken(c *gin.Context, token *ScopedToken) {
+	c.Set(scopedTokenKey, token)
+}
+
+// ScopedTokenFromContext returns the scoped token the request was authorized with
+func ScopedTokenFromContext(c *gin.Context) (*ScopedToken, bool) {
+	value, exists := c.Get(scopedTokenKey)
+	if !exists {
+		return nil, false
+	}
+	token, ok := value.(*ScopedToken)
+	return token, ok && token != nil
+}
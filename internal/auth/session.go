@@ -0,0 +1,11 @@
+package auth
+
+// Cookies of the web dashboard's campus SSO session. The dashboard never sees
+// campus passwords or tokens; the API keeps them in HttpOnly cookies.
+const (
+	// SessionCookie carries the access token of a dashboard session
+	SessionCookie = "dp_session"
+	// SessionRefreshCookie carries the refresh token of a dashboard session; it is only sent
+	// to the SSO endpoints
+	SessionRefreshCookie = "dp_session_refresh"
+)
@@ -0,0 +1,198 @@
+// Package cache stores short-lived copies of expensive results behind a common interface, so
+// features share one caching setup instead of keeping their own maps. Values live in memory or
+// in Redis depending on CACHE_DRIVER.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/pkg/config"
+)
+
+// errorLogInterval is the least time between two logged errors of the same cache
+const errorLogInterval = 30 * time.Second
+
+// Interface is a key-value store with expiring entries. Drivers must be safe for concurrent use.
+type Interface interface {
+	// Get returns the value of key and whether it was found
+	Get(key string) ([]byte, bool, error)
+	// Set stores value under key until ttl passes
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; missing keys are ignored
+	Delete(keys ...string) error
+}
+
+// Open creates the driver selected by the configuration
+func Open(cfg config.CacheConfig) (Interface, error) {
+	switch cfg.Driver {
+	case "", config.MemoryCacheDriver:
+		return NewMemory(), nil
+	case config.RedisCacheDriver:
+		return NewRedis(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB), nil
+	}
+	return nil, fmt.Errorf("unknown cache driver %q", cfg.Driver)
+}
+
+// Cache is a named cache of JSON-encoded values in a shared driver. Its keys are prefixed with
+// the name, and its hits and misses are counted under it. Driver errors are logged and treated
+// as misses, so a cache outage only makes requests slower.
+type Cache struct {
+	name   string
+	driver Interface
+	stats  *counters
+}
+
+// New creates a cache storing its values in driver under name
+func New(name string, driver Interface) *Cache {
+	return &Cache{
+		name:   name,
+		driver: driver,
+		stats:  countersFor(name),
+	}
+}
+
+// Name returns the name of the cache
+func (c *Cache) Name() string {
+	return c.name
+}
+
+// key namespaces a key with the cache name
+func (c *Cache) key(key string) string {
+	return "delpresence:" + c.name + ":" + key
+}
+
+// Get decodes the value of key into value and reports whether it was found
+func (c *Cache) Get(key string, value interface{}) bool {
+	data, found, err := c.driver.Get(c.key(key))
+	if err != nil {
+		c.logError("Failed to read %s from %s: %v", key, c.name, err)
+		found = false
+	}
+	if found {
+		if err := json.Unmarshal(data, value); err != nil {
+			c.logError("Failed to decode %s from %s: %v", key, c.name, err)
+			found = false
+		}
+	}
+
+	if found {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+	return found
+}
+
+// Set stores value under key until ttl passes
+func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = c.driver.Set(c.key(key), data, ttl)
+	}
+	if err != nil {
+		c.logError("Failed to store %s in %s: %v", key, c.name, err)
+	}
+}
+
+// Delete removes keys from the cache
+func (c *Cache) Delete(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = c.key(key)
+	}
+	if err := c.driver.Delete(namespaced...); err != nil {
+		c.logError("Failed to invalidate %d keys of %s: %v", len(keys), c.name, err)
+		return
+	}
+	c.stats.invalidations.Add(int64(len(keys)))
+}
+
+// logError counts a driver error and logs it, at most once per errorLogInterval per cache
+// name so an unreachable Redis does not flood the log
+func (c *Cache) logError(format string, args ...interface{}) {
+	c.stats.errors.Add(1)
+	now := time.Now().UnixNano()
+	last := c.stats.lastLogged.Load()
+	if now-last < int64(errorLogInterval) || !c.stats.lastLogged.CompareAndSwap(last, now) {
+		return
+	}
+	log.Printf("[CACHE] "+format, args...)
+}
+
+// InvalidateOn deletes the keys returned by keys whenever an event named eventName is published
+func (c *Cache) InvalidateOn(bus *events.Bus, eventName string, keys func(events.Event) []string) {
+	bus.Subscribe(eventName, func(event events.Event) {
+		c.Delete(keys(event)...)
+	})
+}
+
+// counters holds the metrics of one cache name
+type counters struct {
+	hits          atomic.Int64
+	misses        atomic.Int64
+	errors        atomic.Int64
+	invalidations atomic.Int64
+	lastLogged    atomic.Int64 // Unix nanoseconds of the last logged error
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      = make(map[string]*counters)
+)
+
+// countersFor returns the counters of a cache name, shared by every cache with that name
+func countersFor(name string) *counters {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	stats, ok := registry[name]
+	if !ok {
+		stats = &counters{}
+		registry[name] = stats
+	}
+	return stats
+}
+
+// Stat is the hit and miss count of a cache since the process started
+type Stat struct {
+	Name          string  `json:"name"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRate       float64 `json:"hit_rate"`
+	Errors        int64   `json:"errors"`
+	Invalidations int64   `json:"invalidations"`
+}
+
+// Stats returns the metrics of every cache, sorted by name
+func Stats() []Stat {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	stats := make([]Stat, 0, len(registry))
+	for name, counters := range registry {
+		stat := Stat{
+			Name:          name,
+			Hits:          counters.hits.Load(),
+			Misses:        counters.misses.Load(),
+			Errors:        counters.errors.Load(),
+			Invalidations: counters.invalidations.Load(),
+		}
+		if lookups := stat.Hits + stat.Misses; lookups > 0 {
+			stat.HitRate = float64(stat.Hits) / float64(lookups)
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
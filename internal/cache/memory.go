@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// memorySweepEvery is how many writes pass between two sweeps of expired entries
+const memorySweepEvery = 256
+
+// memoryEntry is a value together with when it expires
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory keeps entries in the memory of the process. Each instance of the API has its own
+// copy, so it suits single-instance deployments and values that may be briefly stale.
+type Memory struct {
+	mutex   sync.Mutex
+	entries map[string]memoryEntry
+	writes  int
+}
+
+// NewMemory creates an empty in-memory cache
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get implements Interface
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implements Interface
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	m.writes++
+	if m.writes%memorySweepEvery == 0 {
+		for key, entry := range m.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(m.entries, key)
+			}
+		}
+	}
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Delete implements Interface
+func (m *Memory) Delete(keys ...string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	// redisTimeout bounds a whole command, so a slow Redis degrades to cache misses
+	redisTimeout = 500 * time.Millisecond
+	// redisIdleConns is how many connections are kept open between commands
+	redisIdleConns = 8
+)
+
+// redisError is an error reply of the Redis server; the connection stays usable after one
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a connection to Redis with its buffered reader
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Redis keeps entries in a Redis server shared by every instance of the API. It speaks the
+// subset of the Redis protocol the cache needs (GET, SET with PX, DEL).
+type Redis struct {
+	addr     string
+	password string
+	db       int
+	idle     chan *redisConn
+}
+
+// NewRedis creates a cache stored in the Redis server at addr. Connections are opened on
+// first use.
+func NewRedis(addr, password string, db int) *Redis {
+	return &Redis{
+		addr:     addr,
+		password: password,
+		db:       db,
+		idle:     make(chan *redisConn, redisIdleConns),
+	}
+}
+
+// Get implements Interface
+func (r *Redis) Get(key string) ([]byte, bool, error) {
+	reply, err := r.do("GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected reply to GET: %v", reply)
+	}
+	return value, true, nil
+}
+
+// Set implements Interface
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) error {
+	milliseconds := max(ttl.Milliseconds(), 1)
+	_, err := r.do("SET", key, string(value), "PX", strconv.FormatInt(milliseconds, 10))
+	return err
+}
+
+// Delete implements Interface
+func (r *Redis) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.do(append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Ping checks that the server can be reached
+func (r *Redis) Ping() error {
+	_, err := r.do("PING")
+	return err
+}
+
+// do sends a command and reads its reply on an idle connection or a new one
+func (r *Redis) do(args ...string) (interface{}, error) {
+	conn, err := r.acquire()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.command(args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection may hold half a reply; never reuse it
+		conn.conn.Close()
+		return nil, err
+	}
+	r.release(conn)
+	return reply, err
+}
+
+// acquire returns an idle connection, or dials, authenticates and selects the database
+func (r *Redis) acquire() (*redisConn, error) {
+	select {
+	case conn := <-r.idle:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", r.addr, redisTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+	if r.password != "" {
+		if _, err := conn.command("AUTH", r.password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if r.db != 0 {
+		if _, err := conn.command("SELECT", strconv.Itoa(r.db)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// release keeps a connection for the next command, closing it when enough are idle
+func (r *Redis) release(conn *redisConn) {
+	select {
+	case r.idle <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+// command writes a command as an array of bulk strings and reads the reply
+func (c *redisConn) command(args ...string) (interface{}, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, 0, 64)
+	buffer = append(buffer, '*')
+	buffer = strconv.AppendInt(buffer, int64(len(args)), 10)
+	buffer = append(buffer, '\r', '\n')
+	for _, arg := range args {
+		buffer = append(buffer, '$')
+		buffer = strconv.AppendInt(buffer, int64(len(arg)), 10)
+		buffer = append(buffer, '\r', '\n')
+		buffer = append(buffer, arg...)
+		buffer = append(buffer, '\r', '\n')
+	}
+	if _, err := c.conn.Write(buffer); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply reads one reply: a string, an integer, bulk bytes (nil when missing) or an array
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply")
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+}
@@ -0,0 +1,124 @@
+// Package captcha verifies CAPTCHA tokens solved in the web dashboard with the provider's
+// siteverify API. reCAPTCHA, hCaptcha and Turnstile share the same request and response shape,
+// so one verifier serves all of them.
+package captcha
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"delpresence-api/pkg/config"
+)
+
+// ErrFailed is returned when the provider rejects a token
+var ErrFailed = errors.New("captcha verification failed")
+
+// verifyURLs are the siteverify endpoints of the supported providers
+var verifyURLs = map[string]string{
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// Verifier checks CAPTCHA tokens
+type Verifier interface {
+	// Verify returns ErrFailed when the token is invalid, or another error when the provider
+	// could not be asked
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// New returns the verifier of the configured provider, or nil when CAPTCHA is off
+func New(cfg config.CaptchaConfig) Verifier {
+	endpoint, ok := verifyURLs[cfg.Provider]
+	if !ok {
+		return nil
+	}
+	return &siteVerifier{
+		endpoint: endpoint,
+		secret:   cfg.Secret,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// siteVerifier calls a siteverify endpoint
+type siteVerifier struct {
+	endpoint string
+	secret   string
+	client   *http.Client
+}
+
+// siteVerifyResponse is the answer of a siteverify endpoint
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Verify implements Verifier
+func (v *siteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("captcha provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha provider returned status %d", resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("invalid captcha provider response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrFailed, strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
+
+// BypassList recognizes the clients allowed to skip the CAPTCHA by the client token they send,
+// such as the mobile app builds, which cannot show a web CAPTCHA
+type BypassList struct {
+	tokens [][]byte
+}
+
+// NewBypassList creates a BypassList of client tokens
+func NewBypassList(tokens []string) *BypassList {
+	list := &BypassList{}
+	for _, token := range tokens {
+		list.tokens = append(list.tokens, []byte(token))
+	}
+	return list
+}
+
+// Allows reports whether a client token is on the list
+func (l *BypassList) Allows(token string) bool {
+	if l == nil || token == "" {
+		return false
+	}
+	for _, allowed := range l.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), allowed) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+// Package capture keeps sampled request/response pairs for users or endpoints an admin
+// opted in, so client issues can be debugged without digging through server logs.
+// Captures are redacted, held in memory only and dropped after a short retention.
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCaptures bounds how many captures are held in memory
+	maxCaptures = 200
+	// Retention is how long a capture is kept
+	Retention = time.Hour
+	// MaxBodyBytes is how much of each body is kept
+	MaxBodyBytes = 16 * 1024
+	// redacted replaces sensitive values
+	redacted = "[REDACTED]"
+)
+
+// sensitiveHeaders are replaced before a capture is stored
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Api-Key":     true,
+	"X-Sudo-Token":  true,
+}
+
+// sensitiveFields are JSON keys whose values are replaced before a capture is stored
+var sensitiveFields = []string{"password", "token", "secret", "otp", "embedding"}
+
+// Rule opts requests of a user and/or route into capturing
+type Rule struct {
+	ID         uint64    `json:"id"`
+	UserID     uint      `json:"user_id,omitempty"`     // Zero matches any user
+	PathPrefix string    `json:"path_prefix,omitempty"` // Empty matches any route
+	SampleRate float64   `json:"sample_rate"`           // Share of matching requests captured, 0 < rate <= 1
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedBy  uint      `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Matches checks whether a request falls under the rule
+func (r *Rule) Matches(userID uint, path string) bool {
+	if r.UserID != 0 && r.UserID != userID {
+		return false
+	}
+	return r.PathPrefix == "" || strings.HasPrefix(path, r.PathPrefix)
+}
+
+// Capture is a redacted request/response pair
+type Capture struct {
+	ID              uint64            `json:"id"`
+	RuleID          uint64            `json:"rule_id"`
+	UserID          uint              `json:"user_id,omitempty"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Route           string            `json:"route"`
+	Query           string            `json:"query,omitempty"`
+	Status          int               `json:"status"`
+	DurationMs      float64           `json:"duration_ms"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	CapturedAt      time.Time         `json:"captured_at"`
+}
+
+// Store holds the capture rules and the captures they produced
+type Store struct {
+	mutex    sync.Mutex
+	rules    []*Rule
+	captures []Capture
+	seq      uint64
+}
+
+// Default is the store the API captures into
+var Default = &Store{}
+
+// AddRule starts capturing requests matching the rule
+func (s *Store) AddRule(rule Rule) Rule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.seq++
+	rule.ID = s.seq
+	rule.CreatedAt = time.Now()
+	s.rules = append(s.rules, &rule)
+	return rule
+}
+
+// RemoveRule stops a rule; its captures are kept until they expire
+func (s *Store) RemoveRule(id uint64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, rule := range s.rules {
+		if rule.ID == id {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the rules that have not expired
+func (s *Store) Rules() []Rule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.purge()
+	rules := make([]Rule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		rules = append(rules, *rule)
+	}
+	return rules
+}
+
+// Active reports whether any rule is in effect, so requests skip buffering otherwise
+func (s *Store) Active() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.purge()
+	return len(s.rules) > 0
+}
+
+// Sample returns the rule a request is captured under, or nil if it is not sampled
+func (s *Store) Sample(userID uint, path string) *Rule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, rule := range s.rules {
+		if time.Now().Before(rule.ExpiresAt) && rule.Matches(userID, path) && rand.Float64() < rule.SampleRate {
+			matched := *rule
+			return &matched
+		}
+	}
+	return nil
+}
+
+// Record redacts and stores a capture
+func (s *Store) Record(capture Capture) {
+	capture.RequestBody = RedactBody(capture.RequestBody)
+	capture.ResponseBody = RedactBody(capture.ResponseBody)
+	capture.Query = RedactQuery(capture.Query)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.purge()
+	s.seq++
+	capture.ID = s.seq
+	s.captures = append(s.captures, capture)
+	if len(s.captures) > maxCaptures {
+		s.captures = s.captures[len(s.captures)-maxCaptures:]
+	}
+}
+
+// Recent returns the captures still retained, newest first, optionally for one user
+func (s *Store) Recent(userID uint) []Capture {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.purge()
+	captures := make([]Capture, 0, len(s.captures))
+	for i := len(s.captures) - 1; i >= 0; i-- {
+		if userID == 0 || s.captures[i].UserID == userID {
+			captures = append(captures, s.captures[i])
+		}
+	}
+	return captures
+}
+
+// purge drops expired rules and captures past retention; callers hold the mutex
+func (s *Store) purge() {
+	now := time.Now()
+	rules := s.rules[:0]
+	for _, rule := range s.rules {
+		if now.Before(rule.ExpiresAt) {
+			rules = append(rules, rule)
+		}
+	}
+	s.rules = rules
+
+	cutoff := now.Add(-Retention)
+	i := 0
+	for i < len(s.captures) && s.captures[i].CapturedAt.Before(cutoff) {
+		i++
+	}
+	s.captures = s.captures[i:]
+}
+
+// RedactHeaders flattens headers and replaces credentials
+func RedactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// RedactBody replaces sensitive fields of JSON bodies. Other bodies (forms, uploads,
+// truncated JSON) cannot be redacted reliably and are omitted.
+func RedactBody(body string) string {
+	if body == "" {
+		return body
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		return fmt.Sprintf("[%d bytes of non-JSON body omitted]", len(body))
+	}
+	encoded, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return redacted
+	}
+	return string(encoded)
+}
+
+// RedactQuery replaces sensitive query parameters
+func RedactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for key := range values {
+		if isSensitiveField(key) {
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
+
+// redactValue walks a decoded JSON value and replaces sensitive fields
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveField checks whether a JSON key likely holds a credential
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range sensitiveFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// Package chaos injects latency and failures into dependency calls so resilience
+// features (circuit breaker, retries, degradation mode) can be exercised in staging.
+// It is inert unless switched on with Configure, which the API does for CHAOS_ENABLED=true
+// outside production.
+//
+// Faults travel in the context of the request that asked for them, so only dependency calls
+// made with that context are affected and other requests running at the same time never are.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Target is a dependency faults can be injected into
+type Target string
+
+const (
+	// Campus targets calls to the campus API
+	Campus Target = "campus"
+	// DB targets database queries
+	DB Target = "db"
+	// Email targets outgoing email
+	Email Target = "email"
+)
+
+// ErrInjected is returned by dependency calls failed on purpose
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Fault describes what happens to calls to a target
+type Fault struct {
+	Fail    bool
+	Latency time.Duration
+}
+
+// Faults maps targets to the fault injected into them
+type Faults map[Target]Fault
+
+var enabled bool
+
+// Configure switches fault injection on or off; it must be called once at startup
+func Configure(on bool) {
+	enabled = on
+}
+
+// Enabled reports whether fault injection is switched on
+func Enabled() bool {
+	return enabled
+}
+
+// ParseFaults parses a spec such as "campus=error, db=latency:300ms, email=error"
+func ParseFaults(spec string) (Faults, error) {
+	faults := Faults{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		target, mode, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid fault %q, expected target=mode", part)
+		}
+		switch Target(target) {
+		case Campus, DB, Email:
+		default:
+			return nil, fmt.Errorf("unknown chaos target %q", target)
+		}
+
+		fault := faults[Target(target)]
+		switch {
+		case mode == "error":
+			fault.Fail = true
+		case strings.HasPrefix(mode, "latency:"):
+			latency, err := time.ParseDuration(strings.TrimPrefix(mode, "latency:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid latency in %q: %v", part, err)
+			}
+			fault.Latency = latency
+		default:
+			return nil, fmt.Errorf("unknown chaos mode %q", mode)
+		}
+		faults[Target(target)] = fault
+	}
+	return faults, nil
+}
+
+// faultsKey is the context key the faults of a request are stored under
+type faultsKey struct{}
+
+// WithFaults returns a copy of ctx that carries faults to the dependency calls made with it
+func WithFaults(ctx context.Context, faults Faults) context.Context {
+	return context.WithValue(ctx, faultsKey{}, faults)
+}
+
+// Inject applies the fault ctx carries for target: it sleeps for the injected latency and
+// returns ErrInjected when the call should fail
+func Inject(ctx context.Context, target Target) error {
+	if !enabled || ctx == nil {
+		return nil
+	}
+
+	faults, _ := ctx.Value(faultsKey{}).(Faults)
+	fault, ok := faults[target]
+	if !ok {
+		return nil
+	}
+
+	if fault.Latency > 0 {
+		time.Sleep(fault.Latency)
+	}
+	if fault.Fail {
+		return fmt.Errorf("%w (%s)", ErrInjected, target)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package chaos
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// RoundTripper injects faults into HTTP calls to a dependency
+type RoundTripper struct {
+	Base   http.RoundTripper
+	Target Target
+}
+
+// RoundTrip implements the http.RoundTripper interface, injecting the faults of the request
+// context
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := Inject(req.Context(), rt.Target); err != nil {
+		return nil, err
+	}
+	return rt.Base.RoundTrip(req)
+}
+
+// RegisterGormCallbacks injects DB faults before every query when chaos is enabled. Only
+// queries run with a request context through WithContext carry faults.
+func RegisterGormCallbacks(db *gorm.DB) error {
+	if !enabled {
+		return nil
+	}
+
+	inject := func(tx *gorm.DB) {
+		if err := Inject(tx.Statement.Context, DB); err != nil {
+			tx.AddError(err)
+		}
+	}
+
+	callback := db.Callback()
+	if err := callback.Query().Before("gorm:query").Register("chaos:query", inject); err != nil {
+		return err
+	}
+	if err := callback.Create().Before("gorm:create").Register("chaos:create", inject); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("chaos:update", inject); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("chaos:delete", inject); err != nil {
+		return err
+	}
+	if err := callback.Row().Before("gorm:row").Register("chaos:row", inject); err != nil {
+		return err
+	}
+	return callback.Raw().Before("gorm:raw").Register("chaos:raw", inject)
+}
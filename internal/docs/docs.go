@@ -0,0 +1,13 @@
+// Package docs holds the OpenAPI document of the API. The document is maintained by hand next
+// to the routes in cmd/api and must be updated together with them.
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document of the API in YAML
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+// ContentType is the media type of OpenAPI
+const ContentType = "application/yaml"
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"log"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Event is a domain event published on the bus
+type Event interface {
+	// EventName identifies the event type subscribers register for
+	EventName() string
+}
+
+// Handler consumes an event
+type Handler func(event Event)
+
+// Forwarder delivers events to an external broker (NATS, RabbitMQ, ...) in addition
+// to the in-process subscribers
+type Forwarder interface {
+	Forward(event Event) error
+}
+
+// Outbox stores events for the streaming backend. Stage writes the event in the database
+// transaction of the change it describes, so the event exists exactly when the change
+// committed; Forward writes it on its own for events published without a transaction.
+type Outbox interface {
+	Forwarder
+	Stage(tx *gorm.DB, event Event) error
+}
+
+// Stager writes an event inside the database transaction of the change it describes
+type Stager interface {
+	Stage(tx *gorm.DB, event Event) error
+}
+
+// Bus dispatches domain events to the modules that react to them, so handlers only
+// publish what happened instead of calling every side effect themselves
+type Bus struct {
+	mutex      sync.RWMutex
+	handlers   map[string][]Handler
+	forwarders []Forwarder
+	outbox     Outbox
+	wg         sync.WaitGroup
+}
+
+// NewBus creates an in-process event bus
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler for events with the given name
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// AddForwarder registers an external broker backend
+func (b *Bus) AddForwarder(forwarder Forwarder) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.forwarders = append(b.forwarders, forwarder)
+}
+
+// SetOutbox registers the outbox that events are staged in
+func (b *Bus) SetOutbox(outbox Outbox) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.outbox = outbox
+}
+
+// Stage implements Stager. It writes the event to the outbox inside tx and does nothing when
+// no outbox is configured; once tx committed, hand the event to PublishStaged.
+func (b *Bus) Stage(tx *gorm.DB, event Event) error {
+	b.mutex.RLock()
+	outbox := b.outbox
+	b.mutex.RUnlock()
+
+	if outbox == nil {
+		return nil
+	}
+	return outbox.Stage(tx, event)
+}
+
+// Publish hands the event to every subscriber, forwarder and the outbox. Subscribers run in
+// the background so a slow or failing side effect never blocks the request publishing it.
+// The outbox write happens after the change committed, so use Stage and PublishStaged for
+// events that must never be lost.
+func (b *Bus) Publish(event Event) {
+	b.publish(event, true)
+}
+
+// PublishStaged hands an event that was already staged in the outbox to every subscriber
+// and forwarder
+func (b *Bus) PublishStaged(event Event) {
+	b.publish(event, false)
+}
+
+// publish dispatches the event, writing it to the outbox unless it was staged
+func (b *Bus) publish(event Event, toOutbox bool) {
+	b.mutex.RLock()
+	handlers := append([]Handler(nil), b.handlers[event.EventName()]...)
+	forwarders := append([]Forwarder(nil), b.forwarders...)
+	if toOutbox && b.outbox != nil {
+		forwarders = append(forwarders, b.outbox)
+	}
+	b.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		b.wg.Add(1)
+		go b.dispatch(event, handler)
+	}
+
+	for _, forwarder := range forwarders {
+		if err := forwarder.Forward(event); err != nil {
+			log.Printf("[EVENTS] Failed to forward %s: %v", event.EventName(), err)
+		}
+	}
+}
+
+// Wait blocks until every dispatched handler has finished
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
+
+// dispatch runs a single handler, recovering from panics so one subscriber cannot crash the API
+func (b *Bus) dispatch(event Event, handler Handler) {
+	defer b.wg.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[EVENTS] Subscriber of %s panicked: %v", event.EventName(), r)
+		}
+	}()
+	handler(event)
+}
@@ -0,0 +1,184 @@
+package events
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+)
+
+// Names of the domain events published by the API
+const (
+	ProfileSyncedEvent              = "profile.synced"
+	SupervisionMeetingLoggedEvent   = "supervision.logged"
+	SupervisionMeetingDecidedEvent  = "supervision.decided"
+	AttendanceSessionOpenedEvent    = "attendance.session_opened"
+	AttendanceSessionClosedEvent    = "attendance.session_closed"
+	AttendanceCheckedInEvent        = "attendance.checked_in"
+	WorkflowReminderDueEvent        = "workflow.reminder_due"
+	WorkflowEscalatedEvent          = "workflow.escalated"
+	RoomBookingRequestedEvent       = "booking.requested"
+	RoomBookingDecidedEvent         = "booking.decided"
+	PermissionRequestedEvent        = "permission.requested"
+	PermissionDecidedEvent          = "permission.decided"
+	OfficeHourBookedEvent           = "office_hour.booked"
+	OfficeHourBookingCancelledEvent = "office_hour.booking_cancelled"
+	OfficeHourSlotCancelledEvent    = "office_hour.slot_cancelled"
+)
+
+// Actor identifies who caused an event. It is only used in-process and never leaves the
+// API, so streamed events carry no client IP addresses.
+type Actor struct {
+	UserID    uint
+	Type      string
+	IPAddress string
+}
+
+// ProfileSynced is published when a lecturer, assistant or student profile was synced from the campus API
+type ProfileSynced struct {
+	Actor       Actor           `json:"-"`
+	UserID      uint            `json:"user_id"`
+	ProfileType models.UserType `json:"profile_type"`
+	ProfileID   uint            `json:"profile_id"`
+	SyncedAt    time.Time       `json:"synced_at"`
+}
+
+// EventName implements Event
+func (ProfileSynced) EventName() string { return ProfileSyncedEvent }
+
+// SupervisionMeetingLogged is published when a student logs a supervision meeting
+type SupervisionMeetingLogged struct {
+	Actor          Actor                     `json:"-"`
+	Meeting        models.SupervisionMeeting `json:"meeting"`
+	ApproverUserID uint                      `json:"approver_user_id"` // Supervisor, or their delegate while out of office
+}
+
+// EventName implements Event
+func (SupervisionMeetingLogged) EventName() string { return SupervisionMeetingLoggedEvent }
+
+// SupervisionMeetingDecided is published when a supervisor confirms or rejects a meeting
+type SupervisionMeetingDecided struct {
+	Actor   Actor                     `json:"-"`
+	Meeting models.SupervisionMeeting `json:"meeting"`
+	Note    string                    `json:"note"`
+}
+
+// EventName implements Event
+func (SupervisionMeetingDecided) EventName() string { return SupervisionMeetingDecidedEvent }
+
+// AttendanceSessionOpened is published when a lecturer opens an attendance session
+type AttendanceSessionOpened struct {
+	Actor   Actor                    `json:"-"`
+	Session models.AttendanceSession `json:"session"`
+}
+
+// EventName implements Event
+func (AttendanceSessionOpened) EventName() string { return AttendanceSessionOpenedEvent }
+
+// AttendanceSessionClosed is published when a lecturer closes an attendance session
+type AttendanceSessionClosed struct {
+	Actor        Actor                    `json:"-"`
+	Session      models.AttendanceSession `json:"session"`
+	PresentCount int                      `json:"present_count"`
+}
+
+// EventName implements Event
+func (AttendanceSessionClosed) EventName() string { return AttendanceSessionClosedEvent }
+
+// AttendanceCheckedIn is published when a student checks in to a session
+type AttendanceCheckedIn struct {
+	Actor  Actor                   `json:"-"`
+	Record models.AttendanceRecord `json:"record"`
+}
+
+// EventName implements Event
+func (AttendanceCheckedIn) EventName() string { return AttendanceCheckedInEvent }
+
+// WorkflowReminderDue is published when an approval is about to pass its SLA
+type WorkflowReminderDue struct {
+	Actor          Actor                   `json:"-"`
+	Instance       models.WorkflowInstance `json:"instance"`
+	AssigneeUserID uint                    `json:"assignee_user_id"`
+}
+
+// EventName implements Event
+func (WorkflowReminderDue) EventName() string { return WorkflowReminderDueEvent }
+
+// WorkflowEscalated is published when an approval has stayed in one state past its SLA
+type WorkflowEscalated struct {
+	Actor          Actor                   `json:"-"`
+	Instance       models.WorkflowInstance `json:"instance"`
+	AssigneeUserID uint                    `json:"assignee_user_id"` // Owner, or their delegate at the time of escalation
+	EscalatedTo    []uint                  `json:"escalated_to"`     // Prodi admins told about the breach
+}
+
+// EventName implements Event
+func (WorkflowEscalated) EventName() string { return WorkflowEscalatedEvent }
+
+// RoomBookingRequested is published when a lecturer or assistant requests a room
+type RoomBookingRequested struct {
+	Actor   Actor              `json:"-"`
+	Booking models.RoomBooking `json:"booking"`
+}
+
+// EventName implements Event
+func (RoomBookingRequested) EventName() string { return RoomBookingRequestedEvent }
+
+// RoomBookingDecided is published when a room booking is approved, rejected or cancelled
+type RoomBookingDecided struct {
+	Actor   Actor              `json:"-"`
+	Booking models.RoomBooking `json:"booking"`
+	Note    string             `json:"note"`
+}
+
+// EventName implements Event
+func (RoomBookingDecided) EventName() string { return RoomBookingDecidedEvent }
+
+// PermissionRequested is published when a student asks to be excused from a course's meetings
+type PermissionRequested struct {
+	Actor          Actor                    `json:"-"`
+	Request        models.PermissionRequest `json:"request"`
+	ApproverUserID uint                     `json:"approver_user_id"` // Lecturer, or their delegate while out of office
+}
+
+// EventName implements Event
+func (PermissionRequested) EventName() string { return PermissionRequestedEvent }
+
+// PermissionDecided is published when a permission request is approved, rejected or cancelled
+type PermissionDecided struct {
+	Actor   Actor                    `json:"-"`
+	Request models.PermissionRequest `json:"request"`
+	Note    string                   `json:"note"`
+	Excused int64                    `json:"excused"` // Absences turned into excused records on approval
+}
+
+// EventName implements Event
+func (PermissionDecided) EventName() string { return PermissionDecidedEvent }
+
+// OfficeHourBooked is published when a student books a place in a lecturer's office hours
+type OfficeHourBooked struct {
+	Actor   Actor                    `json:"-"`
+	Booking models.OfficeHourBooking `json:"booking"`
+}
+
+// EventName implements Event
+func (OfficeHourBooked) EventName() string { return OfficeHourBookedEvent }
+
+// OfficeHourBookingCancelled is published when a student cancels an office-hour booking
+type OfficeHourBookingCancelled struct {
+	Actor   Actor                    `json:"-"`
+	Booking models.OfficeHourBooking `json:"booking"`
+}
+
+// EventName implements Event
+func (OfficeHourBookingCancelled) EventName() string { return OfficeHourBookingCancelledEvent }
+
+// OfficeHourSlotCancelled is published when a lecturer cancels an office-hour slot, with the
+// bookings that were cancelled along with it
+type OfficeHourSlotCancelled struct {
+	Actor    Actor                      `json:"-"`
+	Slot     models.OfficeHourSlot      `json:"slot"`
+	Bookings []models.OfficeHourBooking `json:"bookings"`
+}
+
+// EventName implements Event
+func (OfficeHourSlotCancelled) EventName() string { return OfficeHourSlotCancelledEvent }
@@ -0,0 +1,99 @@
+// Package features decides which optional attendance features are enabled for a caller,
+// so rollouts can be limited to some roles or prodi and the mobile app adapts its UI.
+package features
+
+import (
+	"strings"
+)
+
+// Feature identifies an optional capability of the API
+type Feature string
+
+const (
+	// QRCheckIn lets students check in by scanning the session QR code
+	QRCheckIn Feature = "qr_check_in"
+	// FaceVerification verifies the student's face on check-in
+	FaceVerification Feature = "face_verification"
+	// Geofence requires students to be near the classroom on check-in
+	Geofence Feature = "geofence"
+	// OfflineSync lets the app queue check-ins while offline and submit them later
+	OfflineSync Feature = "offline_sync"
+	// WifiVerification requires students to be on the campus Wi-Fi (CAMPUS_WIFI_NETWORKS) on check-in
+	WifiVerification Feature = "wifi_verification"
+	// Gamification awards students streaks, goals and badges for their attendance
+	Gamification Feature = "gamification"
+	// DeviceAttestation requires check-ins to carry a Play Integrity or App Attest attestation
+	DeviceAttestation Feature = "device_attestation"
+)
+
+// defaults holds the state of each feature when its FEATURE_<NAME> variable is not set.
+// Features the API does not support yet, or that students must set up first (face), stay off.
+var defaults = map[Feature]bool{
+	QRCheckIn:         true,
+	FaceVerification:  false,
+	Geofence:          true,
+	OfflineSync:       false,
+	WifiVerification:  false,
+	Gamification:      false,
+	DeviceAttestation: false,
+}
+
+// All lists every known feature
+func All() []Feature {
+	return []Feature{QRCheckIn, FaceVerification, Geofence, OfflineSync, WifiVerification, Gamification, DeviceAttestation}
+}
+
+// Caller describes who a feature is evaluated for
+type Caller struct {
+	Role  string // Active role or user type, e.g. student
+	Prodi string // Prodi name, empty when unknown
+}
+
+// Flags holds the configured state of each feature
+type Flags struct {
+	values map[Feature]string
+}
+
+// New creates the flags from the FEATURE_<NAME> values by lowercase feature name
+func New(values map[string]string) *Flags {
+	flags := &Flags{values: make(map[Feature]string, len(values))}
+	for name, value := range values {
+		flags.values[Feature(name)] = strings.TrimSpace(value)
+	}
+	return flags
+}
+
+// EnabledFor reports whether a feature is enabled for the caller.
+//
+// FEATURE_<NAME> (e.g. FEATURE_GEOFENCE) accepts "on", "off", or a comma-separated rollout
+// list such as "role:lecturer,prodi:Informatika" enabling the feature only for callers
+// matching any entry.
+func (f *Flags) EnabledFor(feature Feature, caller Caller) bool {
+	value := f.values[feature]
+	switch strings.ToLower(value) {
+	case "":
+		return defaults[feature]
+	case "on", "true", "1":
+		return true
+	case "off", "false", "0":
+		return false
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		kind, target, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			continue
+		}
+		switch strings.ToLower(kind) {
+		case "role":
+			if caller.Role != "" && strings.EqualFold(caller.Role, target) {
+				return true
+			}
+		case "prodi":
+			if caller.Prodi != "" && strings.EqualFold(caller.Prodi, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLevelHandler lets admins customize which permissions each access level grants
+type AccessLevelHandler struct {
+	accessLevelRepo repository.AccessLevelRepository
+	auditService    *services.AuditService
+}
+
+// NewAccessLevelHandler creates a new AccessLevelHandler
+func NewAccessLevelHandler(accessLevelRepo repository.AccessLevelRepository, auditService *services.AuditService) *AccessLevelHandler {
+	return &AccessLevelHandler{
+		accessLevelRepo: accessLevelRepo,
+		auditService:    auditService,
+	}
+}
+
+// UpdateAccessLevelRequest is the request body for customizing an access level
+type UpdateAccessLevelRequest struct {
+	Permissions []models.AdminPermission `json:"permissions" binding:"required"`
+}
+
+// parseAccessLevel reads and validates the :level route parameter
+func parseAccessLevel(c *gin.Context) (models.AccessLevel, bool) {
+	level := models.AccessLevel(c.Param("level"))
+	if !models.IsValidAccessLevel(level) {
+		utils.BadRequestResponse(c, "Unknown access level: "+string(level))
+		return "", false
+	}
+	return level, true
+}
+
+// GetAccessLevels lists the effective permissions of every access level
+func (h *AccessLevelHandler) GetAccessLevels(c *gin.Context) {
+	levels, err := h.accessLevelRepo.FindAllPermissions()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load access levels")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Access levels retrieved successfully", gin.H{
+		"access_levels":         levels,
+		"available_permissions": models.KnownAdminPermissions,
+	})
+}
+
+// UpdateAccessLevel replaces the permissions of an access level
+func (h *AccessLevelHandler) UpdateAccessLevel(c *gin.Context) {
+	level, ok := parseAccessLevel(c)
+	if !ok {
+		return
+	}
+	if level == models.SuperAdminAccess {
+		utils.BadRequestResponse(c, "The super access level always holds every permission")
+		return
+	}
+
+	var req UpdateAccessLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	for _, permission := range req.Permissions {
+		if !models.IsKnownAdminPermission(permission) {
+			utils.BadRequestResponse(c, fmt.Sprintf("Unknown permission: %s", permission), models.KnownAdminPermissions)
+			return
+		}
+	}
+
+	updatedBy, _ := currentUserID(c)
+	policy := &models.AccessLevelPolicy{
+		AccessLevel: level,
+		UpdatedBy:   updatedBy,
+	}
+	policy.SetPermissions(req.Permissions)
+	if err := h.accessLevelRepo.SavePolicy(policy); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save access level")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "access_level.update", "access_level", level, map[string]interface{}{
+		"permissions": req.Permissions,
+	}))
+
+	permissions, err := h.accessLevelRepo.FindPermissions(level)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load access level")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Access level updated successfully", permissions)
+}
+
+// ResetAccessLevel restores the default permissions of an access level
+func (h *AccessLevelHandler) ResetAccessLevel(c *gin.Context) {
+	level, ok := parseAccessLevel(c)
+	if !ok {
+		return
+	}
+
+	if err := h.accessLevelRepo.ResetPolicy(level); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reset access level")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "access_level.reset", "access_level", level, nil))
+
+	permissions, err := h.accessLevelRepo.FindPermissions(level)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load access level")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Access level reset to defaults", permissions)
+}
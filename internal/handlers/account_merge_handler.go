@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountMergeHandler lets admins merge duplicate user accounts
+type AccountMergeHandler struct {
+	mergeRepo    repository.AccountMergeRepository
+	auditService *services.AuditService
+}
+
+// NewAccountMergeHandler creates a new AccountMergeHandler
+func NewAccountMergeHandler(mergeRepo repository.AccountMergeRepository, auditService *services.AuditService) *AccountMergeHandler {
+	return &AccountMergeHandler{
+		mergeRepo:    mergeRepo,
+		auditService: auditService,
+	}
+}
+
+// MergeUsers moves everything owned by a duplicate account to the surviving account
+func (h *AccountMergeHandler) MergeUsers(c *gin.Context) {
+	var req models.AccountMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	result, err := h.mergeRepo.Merge(req.SourceUserID, req.TargetUserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrMergeSameUser):
+			utils.BadRequestResponse(c, "Source and target user must be different")
+		case errors.Is(err, repository.ErrUserNotFound):
+			utils.NotFoundResponse(c, "Source or target user not found")
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to merge accounts: "+err.Error())
+		}
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "user.merge", "user", result.TargetUserID, map[string]interface{}{
+		"source_user_id": result.SourceUserID,
+		"moved":          result.Moved,
+		"dropped":        result.Dropped,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Accounts merged successfully", result)
+}
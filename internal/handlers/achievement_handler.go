@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/features"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AchievementHandler exposes attendance streaks, goals and badges
+type AchievementHandler struct {
+	achievementRepo    repository.AchievementRepository
+	mahasiswaRepo      repository.MahasiswaRepository
+	achievementService *services.AchievementService
+	prodiResolver      *services.ProdiResolver
+	features           *features.Flags
+	auditService       *services.AuditService
+	campusClient       *utils.CampusClient
+}
+
+// NewAchievementHandler creates a new instance of AchievementHandler
+func NewAchievementHandler(achievementRepo repository.AchievementRepository, mahasiswaRepo repository.MahasiswaRepository, achievementService *services.AchievementService, prodiResolver *services.ProdiResolver, flags *features.Flags, auditService *services.AuditService, campusClient *utils.CampusClient) *AchievementHandler {
+	return &AchievementHandler{
+		achievementRepo:    achievementRepo,
+		mahasiswaRepo:      mahasiswaRepo,
+		achievementService: achievementService,
+		prodiResolver:      prodiResolver,
+		features:           flags,
+		auditService:       auditService,
+		campusClient:       campusClient,
+	}
+}
+
+// AttendanceGoalRequest is the request body for setting an attendance goal
+type AttendanceGoalRequest struct {
+	Prodi         string  `json:"prodi"`    // Empty for every prodi
+	Semester      string  `json:"semester"` // Empty for every semester
+	TargetPercent float64 `json:"target_percent" binding:"required,gt=0,max=100"`
+}
+
+// GetMyAchievements returns the current student's streaks, goal progress and badges
+func (h *AchievementHandler) GetMyAchievements(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	caller, ok := featureCaller(c, h.prodiResolver)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	if !h.features.EnabledFor(features.Gamification, caller) {
+		utils.NotFoundResponse(c, "Achievements are not enabled for your study program")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	achievements, err := h.achievementRepo.FindAchievements(nim)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch achievements: "+err.Error())
+		return
+	}
+	badges, err := h.achievementRepo.FindBadges(nim)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch badges: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Achievements retrieved successfully", gin.H{
+		"nim":          nim,
+		"achievements": achievements,
+		"badges":       badges,
+	})
+}
+
+// ListGoals lists the attendance goals of every prodi and semester
+func (h *AchievementHandler) ListGoals(c *gin.Context) {
+	goals, err := h.achievementRepo.FindGoals()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance goals: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance goals retrieved successfully", goals)
+}
+
+// SaveGoal creates or replaces the attendance goal of a prodi and semester
+func (h *AchievementHandler) SaveGoal(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req AttendanceGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	goal := &models.AttendanceGoal{
+		Prodi:         req.Prodi,
+		Semester:      req.Semester,
+		TargetPercent: req.TargetPercent,
+		UpdatedBy:     adminID,
+	}
+	if err := h.achievementRepo.SaveGoal(goal); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save attendance goal: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "attendance_goal.save", "attendance_goal", goal.ID, map[string]interface{}{
+		"prodi":          goal.Prodi,
+		"semester":       goal.Semester,
+		"target_percent": goal.TargetPercent,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance goal saved successfully", goal)
+}
+
+// DeleteGoal removes an attendance goal
+func (h *AchievementHandler) DeleteGoal(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	goal, err := h.achievementRepo.FindGoalByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance goal: "+err.Error())
+		return
+	}
+	if goal == nil {
+		utils.NotFoundResponse(c, "Attendance goal not found")
+		return
+	}
+
+	if err := h.achievementRepo.DeleteGoal(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete attendance goal: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "attendance_goal.delete", "attendance_goal", goal.ID, map[string]interface{}{
+		"prodi":    goal.Prodi,
+		"semester": goal.Semester,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance goal deleted successfully", nil)
+}
+
+// RecomputeAchievements starts computing achievements now instead of waiting for the nightly run
+func (h *AchievementHandler) RecomputeAchievements(c *gin.Context) {
+	go h.achievementService.Compute()
+
+	h.auditService.Record(newAuditEntry(c, "achievements.recompute", "student_achievement", 0, nil))
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Achievement computation started", nil)
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityHandler handles mandatory non-academic activities (chapel, character building, dormitory)
+type ActivityHandler struct {
+	activityRepo  repository.ActivityRepository
+	mahasiswaRepo repository.MahasiswaRepository
+	campusClient  *utils.CampusClient
+}
+
+// NewActivityHandler creates a new instance of ActivityHandler
+func NewActivityHandler(activityRepo repository.ActivityRepository, mahasiswaRepo repository.MahasiswaRepository, campusClient *utils.CampusClient) *ActivityHandler {
+	return &ActivityHandler{
+		activityRepo:  activityRepo,
+		mahasiswaRepo: mahasiswaRepo,
+		campusClient:  campusClient,
+	}
+}
+
+// requireCoordinator checks that the current user coordinates the given category.
+// It writes the error response and returns false otherwise.
+func (h *ActivityHandler) requireCoordinator(c *gin.Context, category models.ActivityCategory) (uint, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return 0, false
+	}
+
+	isCoordinator, err := h.activityRepo.IsCoordinator(userID, category)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check coordinator role: "+err.Error())
+		return 0, false
+	}
+	if !isCoordinator {
+		utils.ForbiddenResponse(c, "Only coordinators of "+string(category)+" activities can perform this action")
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// ListActivities returns activities filtered by category and semester
+func (h *ActivityHandler) ListActivities(c *gin.Context) {
+	category := models.ActivityCategory(c.Query("category"))
+	if category != "" && !category.IsValid() {
+		utils.BadRequestResponse(c, "Invalid activity category")
+		return
+	}
+
+	activities, err := h.activityRepo.FindAll(category, c.Query("semester"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch activities: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Activities retrieved successfully", activities)
+}
+
+// CreateActivity creates a new activity occurrence
+func (h *ActivityHandler) CreateActivity(c *gin.Context) {
+	var req struct {
+		Name        string                  `json:"name" binding:"required"`
+		Category    models.ActivityCategory `json:"category" binding:"required"`
+		Semester    string                  `json:"semester" binding:"required"`
+		Location    string                  `json:"location"`
+		StartsAt    time.Time               `json:"starts_at" binding:"required"`
+		EndsAt      time.Time               `json:"ends_at" binding:"required"`
+		Description string                  `json:"description"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if !req.Category.IsValid() {
+		utils.BadRequestResponse(c, "Invalid activity category")
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		utils.BadRequestResponse(c, "ends_at must be after starts_at")
+		return
+	}
+
+	userID, ok := h.requireCoordinator(c, req.Category)
+	if !ok {
+		return
+	}
+
+	activity := &models.Activity{
+		Name:        req.Name,
+		Category:    req.Category,
+		Semester:    req.Semester,
+		Location:    req.Location,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		CreatedBy:   userID,
+		Description: req.Description,
+	}
+
+	if err := h.activityRepo.Create(activity); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create activity: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Activity created successfully", activity)
+}
+
+// RecordParticipants records the students who attended an activity
+func (h *ActivityHandler) RecordParticipants(c *gin.Context) {
+	activityID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req struct {
+		Nims []string `json:"nims" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	activity, err := h.activityRepo.FindByID(activityID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch activity: "+err.Error())
+		return
+	}
+	if activity == nil {
+		utils.NotFoundResponse(c, "Activity not found")
+		return
+	}
+
+	userID, ok := h.requireCoordinator(c, activity.Category)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	participations := make([]models.ActivityParticipation, 0, len(req.Nims))
+	for _, nim := range req.Nims {
+		nim = strings.TrimSpace(nim)
+		if nim == "" {
+			continue
+		}
+		participations = append(participations, models.ActivityParticipation{
+			ActivityID: activity.ID,
+			Nim:        nim,
+			RecordedBy: userID,
+			RecordedAt: now,
+		})
+	}
+
+	recorded, err := h.activityRepo.AddParticipants(participations)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to record participants: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Participants recorded successfully", gin.H{
+		"activity_id":       activity.ID,
+		"recorded":          recorded,
+		"already_recorded":  int64(len(participations)) - recorded,
+		"submitted_entries": len(req.Nims),
+	})
+}
+
+// GetParticipants returns the students who attended an activity
+func (h *ActivityHandler) GetParticipants(c *gin.Context) {
+	activityID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	activity, err := h.activityRepo.FindByID(activityID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch activity: "+err.Error())
+		return
+	}
+	if activity == nil {
+		utils.NotFoundResponse(c, "Activity not found")
+		return
+	}
+
+	if _, ok := h.requireCoordinator(c, activity.Category); !ok {
+		return
+	}
+
+	participations, err := h.activityRepo.FindParticipants(activity.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch participants: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Participants retrieved successfully", gin.H{
+		"activity":     activity,
+		"participants": participations,
+	})
+}
+
+// GetMyProgress returns the current student's progress towards the semester quotas
+func (h *ActivityHandler) GetMyProgress(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	semester := c.Query("semester")
+	if semester == "" {
+		utils.BadRequestResponse(c, "semester is required")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	quotas, err := h.activityRepo.FindQuotas(semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch activity quotas: "+err.Error())
+		return
+	}
+
+	counts, err := h.activityRepo.CountAttendedByCategory(nim, semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count attended activities: "+err.Error())
+		return
+	}
+
+	progress := make([]models.ActivityProgress, 0, len(quotas))
+	for _, quota := range quotas {
+		attended := counts[quota.Category]
+		remaining := quota.RequiredCount - attended
+		if remaining < 0 {
+			remaining = 0
+		}
+		progress = append(progress, models.ActivityProgress{
+			Category:      quota.Category,
+			Semester:      semester,
+			RequiredCount: quota.RequiredCount,
+			AttendedCount: attended,
+			Remaining:     remaining,
+			Completed:     remaining == 0,
+		})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Activity progress retrieved successfully", gin.H{
+		"nim":      nim,
+		"semester": semester,
+		"progress": progress,
+	})
+}
+
+// GetQuotas returns the activity quotas of a semester
+func (h *ActivityHandler) GetQuotas(c *gin.Context) {
+	semester := c.Query("semester")
+	if semester == "" {
+		utils.BadRequestResponse(c, "semester is required")
+		return
+	}
+
+	quotas, err := h.activityRepo.FindQuotas(semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch activity quotas: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Activity quotas retrieved successfully", quotas)
+}
+
+// SetQuota creates or updates the quota of a category for a semester
+func (h *ActivityHandler) SetQuota(c *gin.Context) {
+	var req struct {
+		Category      models.ActivityCategory `json:"category" binding:"required"`
+		Semester      string                  `json:"semester" binding:"required"`
+		RequiredCount int                     `json:"required_count" binding:"min=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+	if !req.Category.IsValid() {
+		utils.BadRequestResponse(c, "Invalid activity category")
+		return
+	}
+
+	quota := &models.ActivityQuota{
+		Category:      req.Category,
+		Semester:      req.Semester,
+		RequiredCount: req.RequiredCount,
+	}
+	if err := h.activityRepo.SaveQuota(quota); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save activity quota: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Activity quota saved successfully", quota)
+}
+
+// AddCoordinator grants a user the coordinator role for a category
+func (h *ActivityHandler) AddCoordinator(c *gin.Context) {
+	var req struct {
+		UserID   uint                    `json:"user_id" binding:"required"`
+		Category models.ActivityCategory `json:"category" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+	if !req.Category.IsValid() {
+		utils.BadRequestResponse(c, "Invalid activity category")
+		return
+	}
+
+	adminUserID, _ := currentUserID(c)
+	coordinator := &models.ActivityCoordinator{
+		UserID:    req.UserID,
+		Category:  req.Category,
+		CreatedBy: adminUserID,
+	}
+	if err := h.activityRepo.AddCoordinator(coordinator); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to add coordinator: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coordinator added successfully", coordinator)
+}
+
+// RemoveCoordinator revokes the coordinator role of a user for a category
+func (h *ActivityHandler) RemoveCoordinator(c *gin.Context) {
+	userID, err := parseIDParam(c, "userId")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	category := models.ActivityCategory(c.Param("category"))
+	if !category.IsValid() {
+		utils.BadRequestResponse(c, "Invalid activity category")
+		return
+	}
+
+	if err := h.activityRepo.RemoveCoordinator(userID, category); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to remove coordinator: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coordinator removed successfully", nil)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"delpresence-api/internal/auth"
 	"delpresence-api/internal/models"
 	"delpresence-api/internal/repository"
 	"delpresence-api/internal/utils"
@@ -55,21 +56,14 @@ func (h *AdminHandler) Login(c *gin.Context) {
 // GetAdminProfile mengembalikan profil lengkap admin
 func (h *AdminHandler) GetAdminProfile(c *gin.Context) {
 	// Ambil user_id dari token JWT (via middleware)
-	userID, exists := c.Get("user_id")
-	if !exists {
-		utils.UnauthorizedResponse(c, "User tidak terautentikasi")
-		return
-	}
-
-	// Convert ke uint
-	userIDUint, ok := userID.(uint)
+	principal, ok := auth.FromContext(c)
 	if !ok {
-		utils.InternalServerErrorResponse(c, "Invalid user ID format")
+		utils.UnauthorizedResponse(c, "User tidak terautentikasi")
 		return
 	}
 
 	// Dapatkan profil admin
-	adminWithUser, err := h.adminRepo.GetAdminByUserID(userIDUint)
+	adminWithUser, err := h.adminRepo.GetAdminByUserID(principal.UserID)
 	if err != nil {
 		utils.NotFoundResponse(c, "Profil admin tidak ditemukan")
 		return
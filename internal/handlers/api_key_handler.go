@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles management of API keys for external systems
+type APIKeyHandler struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyHandler creates a new instance of APIKeyHandler
+func NewAPIKeyHandler(apiKeyRepo repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// ListAPIKeys returns all issued API keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	apiKeys, err := h.apiKeyRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch API keys: "+err.Error())
+		return
+	}
+
+	response := make([]models.APIKeyResponse, len(apiKeys))
+	for i := range apiKeys {
+		response[i] = apiKeys[i].ToAPIKeyResponse()
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API keys retrieved successfully", response)
+}
+
+// CreateAPIKey issues a new API key. The plaintext key is only returned once.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req struct {
+		Name          string                `json:"name" binding:"required"`
+		Operations    []models.APIOperation `json:"operations" binding:"required"`
+		ExpiresInDays int                   `json:"expires_in_days"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	if len(req.Operations) == 0 {
+		utils.BadRequestResponse(c, "At least one operation is required")
+		return
+	}
+	for _, op := range req.Operations {
+		if !models.IsKnownAPIOperation(op) {
+			utils.BadRequestResponse(c, fmt.Sprintf("Unknown operation: %s", op), models.KnownAPIOperations)
+			return
+		}
+	}
+
+	key, err := utils.GenerateAPIKey()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate API key: "+err.Error())
+		return
+	}
+
+	createdBy, _ := currentUserID(c)
+	apiKey := &models.APIKey{
+		Name:      req.Name,
+		Prefix:    key[:12],
+		KeyHash:   utils.HashAPIKey(key),
+		CreatedBy: createdBy,
+	}
+	apiKey.SetOperations(req.Operations)
+
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := h.apiKeyRepo.Create(apiKey); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save API key: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "API key created successfully", gin.H{
+		"api_key": apiKey.ToAPIKeyResponse(),
+		"key":     key,
+	})
+}
+
+// RevokeAPIKey revokes an API key
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid API key ID")
+		return
+	}
+
+	apiKey, err := h.apiKeyRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch API key: "+err.Error())
+		return
+	}
+	if apiKey == nil {
+		utils.NotFoundResponse(c, "API key not found")
+		return
+	}
+
+	if err := h.apiKeyRepo.Revoke(apiKey.ID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to revoke API key: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked successfully", nil)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppVersionHandler tells the mobile app whether it must be updated
+type AppVersionHandler struct {
+	policy utils.AppVersionPolicy
+}
+
+// NewAppVersionHandler creates a new AppVersionHandler
+func NewAppVersionHandler(policy utils.AppVersionPolicy) *AppVersionHandler {
+	return &AppVersionHandler{policy: policy}
+}
+
+// CheckVersion returns the supported versions and whether the client must upgrade.
+// The client version is read from the X-App-Version header or the version query parameter.
+func (h *AppVersionHandler) CheckVersion(c *gin.Context) {
+	version := c.GetHeader(utils.AppVersionHeader)
+	if version == "" {
+		version = c.Query("version")
+	}
+
+	policy := h.policy
+	utils.SuccessResponse(c, http.StatusOK, "App version policy retrieved successfully", gin.H{
+		"current_version":  version,
+		"minimum_version":  policy.MinimumVersion,
+		"latest_version":   policy.LatestVersion,
+		"update_url":       policy.UpdateURL,
+		"upgrade_required": version != "" && policy.RequiresUpgrade(version),
+		"update_available": version != "" && policy.LatestVersion != "" && utils.CompareVersions(version, policy.LatestVersion) < 0,
+	})
+}
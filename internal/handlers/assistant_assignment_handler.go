@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssistantAssignmentHandler lets coordinating lecturers grant assistants permissions per course
+type AssistantAssignmentHandler struct {
+	assignmentRepo repository.AssistantAssignmentRepository
+	scheduleRepo   repository.ScheduleRepository
+	auditService   *services.AuditService
+}
+
+// NewAssistantAssignmentHandler creates a new instance of AssistantAssignmentHandler
+func NewAssistantAssignmentHandler(assignmentRepo repository.AssistantAssignmentRepository, scheduleRepo repository.ScheduleRepository, auditService *services.AuditService) *AssistantAssignmentHandler {
+	return &AssistantAssignmentHandler{
+		assignmentRepo: assignmentRepo,
+		scheduleRepo:   scheduleRepo,
+		auditService:   auditService,
+	}
+}
+
+// AssistantAssignmentRequest is the request body for assigning an assistant to a course
+type AssistantAssignmentRequest struct {
+	AssistantUserID uint                         `json:"assistant_user_id" binding:"required"`
+	CourseCode      string                       `json:"course_code" binding:"required"`
+	ClassName       string                       `json:"class_name"` // Empty for every class of the course
+	Semester        string                       `json:"semester" binding:"required"`
+	Permissions     []models.AssistantPermission `json:"permissions"`
+}
+
+// coordinates checks whether the lecturer is scheduled to teach the course offering
+func (h *AssistantAssignmentHandler) coordinates(lecturerUserID uint, courseCode, className, semester string) (bool, error) {
+	schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+		LecturerUserID: lecturerUserID,
+		CourseCode:     courseCode,
+		ClassName:      className,
+		Semester:       semester,
+	})
+	return len(schedules) > 0, err
+}
+
+// ListAssignments returns the assistants assigned to the current lecturer's courses
+func (h *AssistantAssignmentHandler) ListAssignments(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	assignments, err := h.assignmentRepo.FindByCoordinator(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch assistant assignments: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Assistant assignments retrieved successfully", gin.H{
+		"assignments": assignments,
+		"permissions": models.KnownAssistantPermissions,
+	})
+}
+
+// SaveAssignment assigns an assistant to one of the current lecturer's courses, replacing the
+// permissions of an existing assignment
+func (h *AssistantAssignmentHandler) SaveAssignment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req AssistantAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Assistant, course code and semester are required")
+		return
+	}
+	for _, permission := range req.Permissions {
+		if !models.IsKnownAssistantPermission(permission) {
+			utils.BadRequestResponse(c, "Unknown permission: "+string(permission))
+			return
+		}
+	}
+
+	coordinates, err := h.coordinates(userID, req.CourseCode, req.ClassName, req.Semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+		return
+	}
+	if !coordinates {
+		utils.ForbiddenResponse(c, "You are not scheduled to teach this course")
+		return
+	}
+
+	assignment := &models.AssistantAssignment{
+		AssistantUserID: req.AssistantUserID,
+		CourseCode:      req.CourseCode,
+		ClassName:       req.ClassName,
+		Semester:        req.Semester,
+		AssignedBy:      userID,
+	}
+	assignment.SetPermissions(req.Permissions)
+
+	if err := h.assignmentRepo.Save(assignment); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save assistant assignment: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "assistant_assignment.save", "assistant_assignment", assignment.ID, map[string]interface{}{
+		"assistant_user_id": assignment.AssistantUserID,
+		"course_code":       assignment.CourseCode,
+		"class_name":        assignment.ClassName,
+		"semester":          assignment.Semester,
+		"permissions":       assignment.Permissions,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Assistant assignment saved successfully", assignment)
+}
+
+// DeleteAssignment removes an assistant from one of the current lecturer's courses
+func (h *AssistantAssignmentHandler) DeleteAssignment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	assignment, err := h.assignmentRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch assistant assignment: "+err.Error())
+		return
+	}
+	if assignment == nil {
+		utils.NotFoundResponse(c, "Assistant assignment not found")
+		return
+	}
+	coordinates, err := h.coordinates(userID, assignment.CourseCode, assignment.ClassName, assignment.Semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+		return
+	}
+	if !coordinates {
+		utils.NotFoundResponse(c, "Assistant assignment not found")
+		return
+	}
+
+	if err := h.assignmentRepo.Delete(assignment.ID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete assistant assignment: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "assistant_assignment.delete", "assistant_assignment", assignment.ID, map[string]interface{}{
+		"assistant_user_id": assignment.AssistantUserID,
+		"course_code":       assignment.CourseCode,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Assistant assignment deleted successfully", nil)
+}
+
+// GetMyCourses returns the courses the current assistant is assigned to with their permissions
+func (h *AssistantAssignmentHandler) GetMyCourses(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	assignments, err := h.assignmentRepo.FindByAssistant(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch course assignments: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Course assignments retrieved successfully", assignments)
+}
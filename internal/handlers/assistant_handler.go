@@ -4,14 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/events"
 	"delpresence-api/internal/models"
 	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
 	"delpresence-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -20,22 +22,37 @@ import (
 // AssistantHandler menangani request terkait asisten dosen
 type AssistantHandler struct {
 	assistantRepo repository.AssistantRepository
+	conflicts     *services.SyncConflictService
+	bus           *events.Bus
 	campusClient  *utils.CampusClient
 }
 
 // NewAssistantHandler membuat instance baru AssistantHandler
-func NewAssistantHandler(assistantRepo repository.AssistantRepository) *AssistantHandler {
+func NewAssistantHandler(assistantRepo repository.AssistantRepository, conflicts *services.SyncConflictService, bus *events.Bus, campusClient *utils.CampusClient) *AssistantHandler {
 	return &AssistantHandler{
 		assistantRepo: assistantRepo,
-		campusClient:  utils.NewCampusClient(),
+		conflicts:     conflicts,
+		bus:           bus,
+		campusClient:  campusClient,
 	}
 }
 
+// publishSynced mengumumkan bahwa profil asisten baru saja disinkronkan dari API kampus
+func (h *AssistantHandler) publishSynced(c *gin.Context, assistant *models.Assistant) {
+	h.bus.Publish(events.ProfileSynced{
+		Actor:       eventActor(c),
+		UserID:      assistant.AssistantUserID,
+		ProfileType: models.AssistantType,
+		ProfileID:   assistant.ID,
+		SyncedAt:    assistant.LastSyncAt,
+	})
+}
+
 // GetAssistantProfile mengembalikan detail profil asisten dosen
 func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 	// Get user ID from JWT claim
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
 		})
@@ -43,7 +60,7 @@ func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 	}
 
 	// Find assistant profile by user ID
-	assistant, err := h.assistantRepo.FindByUserID(userID.(uint))
+	assistant, err := h.assistantRepo.FindByUserID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch assistant profile",
@@ -54,10 +71,10 @@ func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 	// If assistant profile doesn't exist, try to fetch from campus API
 	if assistant == nil {
 		// First check if campus_user_id is in the context (from JWT)
-		campusUserID, exists := c.Get("campus_user_id")
+		campusUserID := principal.CampusUserID
 
 		// If not in context, try to get from query parameter
-		if !exists {
+		if campusUserID == 0 {
 			campusUserIDStr := c.Query("campus_user_id")
 			if campusUserIDStr == "" {
 				c.JSON(http.StatusBadRequest, gin.H{
@@ -79,25 +96,14 @@ func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 			campusUserID = campusUserIDInt
 		}
 
-		// Log campus user ID type and value for debugging
-		log.Printf("Campus User ID: %v (type: %T)", campusUserID, campusUserID)
-
 		// Fetch assistant details from campus API
-		var campusUserIDInt int
-		switch v := campusUserID.(type) {
-		case int:
-			campusUserIDInt = v
-		case float64:
-			campusUserIDInt = int(v)
-		default:
-			log.Printf("Unexpected campus user ID type: %T", campusUserID)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid campus user ID type",
+		newAssistant, err := h.fetchAssistantDetails(c, campusUserID)
+		if err != nil && h.campusClient.IsUnavailable(err) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Campus API is unavailable and no local assistant profile has been synced yet",
 			})
 			return
 		}
-
-		newAssistant, err := h.fetchAssistantDetails(campusUserIDInt)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("Failed to fetch assistant details from campus API: %v", err),
@@ -106,7 +112,7 @@ func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 		}
 
 		// Set user ID and save to database
-		newAssistant.AssistantUserID = userID.(uint)
+		newAssistant.AssistantUserID = principal.UserID
 		newAssistant.LastSyncAt = time.Now()
 		if err := h.assistantRepo.Create(newAssistant); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -116,6 +122,7 @@ func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 		}
 
 		assistant = newAssistant
+		h.publishSynced(c, assistant)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -132,8 +139,8 @@ func (h *AssistantHandler) GetAssistantProfile(c *gin.Context) {
 // SyncAssistantProfile memperbarui data asisten dosen dari API kampus
 func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 	// Get user ID from JWT claim
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
 		})
@@ -141,8 +148,8 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 	}
 
 	// Get campus user ID from JWT claim
-	campusUserID, exists := c.Get("campus_user_id")
-	if !exists {
+	campusUserID := principal.CampusUserID
+	if campusUserID == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Campus user ID not found",
 		})
@@ -150,7 +157,7 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 	}
 
 	// Find existing assistant profile
-	existingAssistant, err := h.assistantRepo.FindByUserID(userID.(uint))
+	existingAssistant, err := h.assistantRepo.FindByUserID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch assistant profile",
@@ -159,21 +166,23 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 	}
 
 	// Fetch updated assistant details from campus API
-	var campusUserIDInt int
-	switch v := campusUserID.(type) {
-	case int:
-		campusUserIDInt = v
-	case float64:
-		campusUserIDInt = int(v)
-	default:
-		log.Printf("Unexpected campus user ID type in Sync: %T", campusUserID)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Invalid campus user ID type",
+	updatedAssistant, err := h.fetchAssistantDetails(c, campusUserID)
+	if err != nil && h.campusClient.IsUnavailable(err) && existingAssistant != nil {
+		// Degradation mode: keep serving the last-synced profile
+		requestLog(c).Warnf("Campus API unavailable, serving stale assistant profile for user ID %d", existingAssistant.AssistantUserID)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Campus API is unavailable, returning last synced profile",
+			"stale":   true,
+			"assistant": gin.H{
+				"editable_fields": existingAssistant.GetEditableFields(),
+				"readonly_fields": existingAssistant.GetReadOnlyFields(),
+				"id":              existingAssistant.ID,
+				"user_id":         existingAssistant.CampusUserID,
+				"last_sync_at":    existingAssistant.LastSyncAt,
+			},
 		})
 		return
 	}
-
-	updatedAssistant, err := h.fetchAssistantDetails(campusUserIDInt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to fetch assistant details from campus API: %v", err),
@@ -182,7 +191,18 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 	}
 
 	// Update or create assistant record
+	conflicts := []models.SyncConflict{}
 	if existingAssistant != nil {
+		// Merge the synced fields the assistant may have edited by their conflict policy
+		merge, err := h.conflicts.Merge(models.AssistantType, existingAssistant.AssistantUserID, existingAssistant.LastSyncAt,
+			existingAssistant.SyncedFields(), updatedAssistant.SyncedFields())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to merge assistant details",
+			})
+			return
+		}
+
 		// Preserve user-editable fields
 		updatedAssistant.ID = existingAssistant.ID
 		updatedAssistant.AssistantUserID = existingAssistant.AssistantUserID
@@ -198,9 +218,13 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 			})
 			return
 		}
+		if err := h.conflicts.Save(merge); err != nil {
+			requestLog(c).Errorf("Failed to record synced fields of assistant %d: %v", updatedAssistant.AssistantUserID, err)
+		}
+		conflicts = merge.Conflicts()
 	} else {
 		// Create new assistant record
-		updatedAssistant.AssistantUserID = userID.(uint)
+		updatedAssistant.AssistantUserID = principal.UserID
 		updatedAssistant.LastSyncAt = time.Now()
 		if err := h.assistantRepo.Create(updatedAssistant); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -209,6 +233,7 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 			return
 		}
 	}
+	h.publishSynced(c, updatedAssistant)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Assistant profile synchronized successfully",
@@ -219,14 +244,15 @@ func (h *AssistantHandler) SyncAssistantProfile(c *gin.Context) {
 			"user_id":         updatedAssistant.CampusUserID,
 			"last_sync_at":    updatedAssistant.LastSyncAt,
 		},
+		"conflicts": conflicts,
 	})
 }
 
 // UpdateAssistantProfile memperbarui informasi profil asisten dosen yang dapat diubah
 func (h *AssistantHandler) UpdateAssistantProfile(c *gin.Context) {
 	// Get user ID from JWT claim
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
 		})
@@ -234,7 +260,7 @@ func (h *AssistantHandler) UpdateAssistantProfile(c *gin.Context) {
 	}
 
 	// Find assistant by user ID
-	assistant, err := h.assistantRepo.FindByUserID(userID.(uint))
+	assistant, err := h.assistantRepo.FindByUserID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch assistant profile",
@@ -251,6 +277,8 @@ func (h *AssistantHandler) UpdateAssistantProfile(c *gin.Context) {
 
 	// Parse request body
 	var req struct {
+		FullName    *string `json:"full_name" binding:"omitempty,min=1,max=255"`
+		Email       *string `json:"email" binding:"omitempty,email,max=255"`
 		Avatar      *string `json:"avatar"`
 		Biography   *string `json:"biography"`
 		PhoneNumber *string `json:"phone_number"`
@@ -265,6 +293,13 @@ func (h *AssistantHandler) UpdateAssistantProfile(c *gin.Context) {
 	}
 
 	// Update fields if provided
+	before := services.FieldValues(assistant.SyncedFields())
+	if req.FullName != nil {
+		assistant.FullName = *req.FullName
+	}
+	if req.Email != nil {
+		assistant.Email = *req.Email
+	}
 	if req.Avatar != nil {
 		assistant.Avatar = *req.Avatar
 	}
@@ -285,6 +320,10 @@ func (h *AssistantHandler) UpdateAssistantProfile(c *gin.Context) {
 		})
 		return
 	}
+	// Remember the edit so the next sync does not take it for a stale value
+	if err := h.conflicts.RecordLocalEdits(models.AssistantType, assistant.AssistantUserID, before, assistant.SyncedFields()); err != nil {
+		requestLog(c).Errorf("Failed to record edited fields of assistant %d: %v", assistant.AssistantUserID, err)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Assistant profile updated successfully",
@@ -295,16 +334,26 @@ func (h *AssistantHandler) UpdateAssistantProfile(c *gin.Context) {
 	})
 }
 
+// GetProfileConflicts mengembalikan konflik sinkronisasi profil asisten dosen yang belum ditinjau
+func (h *AssistantHandler) GetProfileConflicts(c *gin.Context) {
+	listProfileConflicts(c, h.conflicts, models.AssistantType)
+}
+
+// ResolveProfileConflict menyelesaikan konflik sinkronisasi profil asisten dosen dengan nilai yang dipilih
+func (h *AssistantHandler) ResolveProfileConflict(c *gin.Context) {
+	resolveProfileConflict(c, h.conflicts, models.AssistantType)
+}
+
 // fetchAssistantDetails retrieves assistant details from the campus API
-func (h *AssistantHandler) fetchAssistantDetails(campusUserID int) (*models.Assistant, error) {
-	url := fmt.Sprintf("https://cis.del.ac.id/api/library-api/pegawai?userid=%d", campusUserID)
+func (h *AssistantHandler) fetchAssistantDetails(c *gin.Context, campusUserID int) (*models.Assistant, error) {
+	url := h.campusClient.URL(fmt.Sprintf("/library-api/pegawai?userid=%d", campusUserID))
 
-	log.Printf("Fetching assistant details for campus user ID: %d from URL: %s", campusUserID, url)
+	requestLog(c).Debugf("Fetching assistant details for campus user ID: %d from URL: %s", campusUserID, url)
 
 	// Use campus client to make authenticated request
-	response, err := h.campusClient.GetWithAuth(url)
+	response, err := h.campusClient.GetWithAuth(c.Request.Context(), url)
 	if err != nil {
-		log.Printf("Error fetching assistant details: %v", err)
+		requestLog(c).Warnf("Error fetching assistant details: %v", err)
 		return nil, fmt.Errorf("error fetching assistant details: %w", err)
 	}
 	defer response.Body.Close()
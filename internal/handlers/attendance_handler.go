@@ -0,0 +1,1272 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/attestation"
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/features"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/qrtoken"
+	"delpresence-api/pkg/xlsx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceHandler handles class attendance sessions and student check-ins
+type AttendanceHandler struct {
+	attendanceRepo repository.AttendanceRepository
+	enrollmentRepo repository.EnrollmentRepository
+	mahasiswaRepo  repository.MahasiswaRepository
+	roomRepo       repository.RoomRepository
+	scheduleRepo   repository.ScheduleRepository
+	faceService    *services.FaceService
+	exportService  *services.ExportService
+	latePolicy     *services.LatePolicyService
+	telemetry      *services.TelemetryService
+	attestation    *services.AttestationService
+	factorRollouts *services.FactorRolloutService
+	scopedTokens   *services.ScopedTokenService
+	prodiResolver  *services.ProdiResolver
+	features       *features.Flags
+	bus            *events.Bus
+	campusClient   *utils.CampusClient
+	qrSigner       *qrtoken.Signer
+}
+
+// NewAttendanceHandler creates a new instance of AttendanceHandler
+func NewAttendanceHandler(attendanceRepo repository.AttendanceRepository, enrollmentRepo repository.EnrollmentRepository, mahasiswaRepo repository.MahasiswaRepository, roomRepo repository.RoomRepository, scheduleRepo repository.ScheduleRepository, faceService *services.FaceService, exportService *services.ExportService, latePolicy *services.LatePolicyService, telemetry *services.TelemetryService, attestationService *services.AttestationService, factorRollouts *services.FactorRolloutService, scopedTokens *services.ScopedTokenService, prodiResolver *services.ProdiResolver, flags *features.Flags, bus *events.Bus, campusClient *utils.CampusClient, qrSigner *qrtoken.Signer) *AttendanceHandler {
+	return &AttendanceHandler{
+		attendanceRepo: attendanceRepo,
+		enrollmentRepo: enrollmentRepo,
+		mahasiswaRepo:  mahasiswaRepo,
+		roomRepo:       roomRepo,
+		scheduleRepo:   scheduleRepo,
+		faceService:    faceService,
+		exportService:  exportService,
+		latePolicy:     latePolicy,
+		telemetry:      telemetry,
+		attestation:    attestationService,
+		factorRollouts: factorRollouts,
+		scopedTokens:   scopedTokens,
+		prodiResolver:  prodiResolver,
+		features:       flags,
+		bus:            bus,
+		campusClient:   campusClient,
+		qrSigner:       qrSigner,
+	}
+}
+
+// findOwnSession loads an attendance session and checks it was opened by the current lecturer,
+// or that the current assistant was granted access to its course. It writes the error
+// response and returns nil otherwise.
+func (h *AttendanceHandler) findOwnSession(c *gin.Context) *models.AttendanceSession {
+	return findManagedSession(c, h.attendanceRepo)
+}
+
+// findManagedSession loads the attendance session in the id route parameter like findOwnSession
+func findManagedSession(c *gin.Context, attendanceRepo repository.AttendanceRepository) *models.AttendanceSession {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return nil
+	}
+
+	sessionID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil
+	}
+
+	session, err := attendanceRepo.FindSessionByID(sessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return nil
+	}
+	if session == nil || !managesSession(c, userID, session) {
+		utils.NotFoundResponse(c, "Attendance session not found")
+		return nil
+	}
+
+	return session
+}
+
+// managesSession checks whether the current user opened a session or was granted access to its course
+func managesSession(c *gin.Context, userID uint, session *models.AttendanceSession) bool {
+	return session.LecturerUserID == userID || grantCovers(c, session)
+}
+
+// grantCovers checks whether the current assistant's course grant applies to a session
+func grantCovers(c *gin.Context, session *models.AttendanceSession) bool {
+	grant := courseGrant(c)
+	return grant != nil && grant.Covers(models.CourseOffering{
+		CourseCode: session.CourseCode,
+		ClassName:  session.ClassName,
+		Semester:   session.Semester,
+	})
+}
+
+// recapFilter selects the sessions of a course recap for the current user. Lecturers see
+// their own sessions; assistants granted access to the course see every session of the
+// classes they are assigned to.
+func recapFilter(c *gin.Context, userID uint) models.AttendanceRecapFilter {
+	filter := models.AttendanceRecapFilter{
+		LecturerUserID: userID,
+		CourseCode:     c.Param("id"),
+		Semester:       c.Query("semester"),
+		ClassName:      c.Query("class_name"),
+	}
+	if grant := courseGrant(c); grant != nil {
+		filter.LecturerUserID = 0
+		filter.Semester = grant.Semester
+		if grant.ClassName != "" {
+			filter.ClassName = grant.ClassName
+		}
+	}
+	return filter
+}
+
+// OpenSession opens an attendance session for a class meeting of the current lecturer, or of
+// a course the current assistant may open sessions for. The lecturer, or the lecturer who
+// granted the assistant access, must be scheduled to teach the class.
+func (h *AttendanceHandler) OpenSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		CourseCode    string `json:"course_code" binding:"required"`
+		CourseName    string `json:"course_name"`
+		ClassName     string `json:"class_name"`
+		Semester      string `json:"semester"`
+		MeetingNumber int    `json:"meeting_number" binding:"required,min=1"`
+		Topic         string `json:"topic"`
+		Room          string `json:"room"`
+		RequireQR     bool   `json:"require_qr"`
+		// Optional sessions, such as guest lectures, are recorded but left out of percentages
+		Optional bool `json:"optional"`
+		// Check-ins beyond the capacity are recorded as overflow; cap_at_room_capacity takes the
+		// capacity of the room instead
+		Capacity          int  `json:"capacity" binding:"omitempty,min=1"`
+		CapAtRoomCapacity bool `json:"cap_at_room_capacity"`
+		// The geofence defaults to the room's location; disable it for sessions held elsewhere
+		Latitude        *float64 `json:"latitude"`
+		Longitude       *float64 `json:"longitude"`
+		GeofenceRadius  int      `json:"geofence_radius" binding:"omitempty,min=10,max=5000"`
+		DisableGeofence bool     `json:"disable_geofence"`
+		// Centers the geofence on the lecturer's device instead of the room; latitude and
+		// longitude, when given, are the device's location at opening
+		AnchorToLecturer bool `json:"anchor_to_lecturer"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		utils.BadRequestResponse(c, "latitude and longitude must be set together")
+		return
+	}
+	if req.Latitude != nil && !utils.ValidCoordinates(*req.Latitude, *req.Longitude) {
+		utils.BadRequestResponse(c, "latitude or longitude is out of range")
+		return
+	}
+	if req.AnchorToLecturer && req.DisableGeofence {
+		utils.BadRequestResponse(c, "anchor_to_lecturer cannot be combined with disable_geofence")
+		return
+	}
+
+	session := &models.AttendanceSession{
+		LecturerUserID: userID,
+		CourseCode:     req.CourseCode,
+		CourseName:     req.CourseName,
+		ClassName:      req.ClassName,
+		Semester:       req.Semester,
+		MeetingNumber:  req.MeetingNumber,
+		Topic:          req.Topic,
+		Room:           req.Room,
+		RequireQR:      req.RequireQR,
+		Capacity:       req.Capacity,
+		Optional:       req.Optional,
+		Status:         models.SessionOpen,
+		OpenedAt:       time.Now(),
+	}
+	// Assistants open sessions on behalf of the lecturer who granted them the permission
+	if grant := courseGrant(c); grant != nil {
+		session.LecturerUserID = grant.AssignedBy
+	}
+	schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+		LecturerUserID: session.LecturerUserID,
+		CourseCode:     session.CourseCode,
+		ClassName:      session.ClassName,
+		Semester:       session.Semester,
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+		return
+	}
+	if len(schedules) == 0 {
+		utils.ForbiddenResponse(c, "You are not scheduled to teach this course")
+		return
+	}
+
+	if req.CapAtRoomCapacity {
+		room, err := h.roomRepo.FindByCode(req.Room)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to fetch room: "+err.Error())
+			return
+		}
+		if room == nil || room.Capacity <= 0 {
+			utils.BadRequestResponse(c, "cap_at_room_capacity needs a room with a known capacity")
+			return
+		}
+		session.Capacity = room.Capacity
+	}
+
+	switch {
+	case req.AnchorToLecturer:
+		// Students wait for the lecturer's device check-in unless its location came with the request
+		session.AnchorToLecturer = true
+		session.GeofenceRadius = req.GeofenceRadius
+		if session.GeofenceRadius == 0 {
+			session.GeofenceRadius = models.DefaultGeofenceRadius
+		}
+		if req.Latitude != nil {
+			session.Latitude, session.Longitude, session.AnchoredAt = req.Latitude, req.Longitude, &session.OpenedAt
+		}
+	case !req.DisableGeofence:
+		session.Latitude, session.Longitude, session.GeofenceRadius = req.Latitude, req.Longitude, req.GeofenceRadius
+		if err := applyRoomGeofence(h.roomRepo, session); err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to fetch room: "+err.Error())
+			return
+		}
+	}
+
+	opened := func() events.Event { return events.AttendanceSessionOpened{Actor: eventActor(c), Session: *session} }
+	if err := h.attendanceRepo.CreateSession(session, opened); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to open attendance session: "+err.Error())
+		return
+	}
+
+	h.bus.PublishStaged(opened())
+
+	utils.SuccessResponse(c, http.StatusCreated, "Attendance session opened successfully", session)
+}
+
+// StartSession opens one of the current user's scheduled sessions, such as the session
+// created for an approved room booking
+func (h *AttendanceHandler) StartSession(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	if session.Status != models.SessionScheduled {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is already "+string(session.Status), nil)
+		return
+	}
+
+	opened := func() events.Event { return events.AttendanceSessionOpened{Actor: eventActor(c), Session: *session} }
+	if err := h.attendanceRepo.OpenScheduledSession(session, opened); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to open attendance session: "+err.Error())
+		return
+	}
+
+	h.bus.PublishStaged(opened())
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance session opened successfully", session)
+}
+
+// CloseSession closes one of the current lecturer's attendance sessions
+func (h *AttendanceHandler) CloseSession(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	if !session.IsOpen() {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is "+string(session.Status), nil)
+		return
+	}
+
+	var closed events.AttendanceSessionClosed
+	err := h.attendanceRepo.CloseSession(session, func(presentCount int) events.Event {
+		closed = events.AttendanceSessionClosed{Actor: eventActor(c), Session: *session, PresentCount: presentCount}
+		return closed
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to close attendance session: "+err.Error())
+		return
+	}
+
+	h.bus.PublishStaged(closed)
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance session closed successfully", gin.H{
+		"session":       session,
+		"present_count": closed.PresentCount,
+	})
+}
+
+// AnchorSession checks the lecturer's device in to one of their sessions and centers the
+// session's geofence on the device's location, so students are checked against where the class
+// is actually held. It can be repeated when the class moves.
+func (h *AttendanceHandler) AnchorSession(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	if session.Status == models.SessionClosed {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is "+string(session.Status), nil)
+		return
+	}
+
+	var req struct {
+		Latitude  *float64 `json:"latitude" binding:"required"`
+		Longitude *float64 `json:"longitude" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if !utils.ValidCoordinates(*req.Latitude, *req.Longitude) {
+		utils.BadRequestResponse(c, "latitude or longitude is out of range")
+		return
+	}
+
+	if err := h.attendanceRepo.AnchorSession(session, *req.Latitude, *req.Longitude); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to anchor attendance session: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance session anchored to your location", session)
+}
+
+// SetSessionOptional marks one of the current lecturer's sessions as optional or required.
+// Optional sessions are left out of attendance percentages, so the course's check-ins are
+// regraded by its late policy afterwards.
+func (h *AttendanceHandler) SetSessionOptional(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	var req struct {
+		Optional *bool `json:"optional" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.attendanceRepo.SetSessionOptional(session, *req.Optional); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update attendance session: "+err.Error())
+		return
+	}
+
+	regraded, err := h.latePolicy.Recalculate(session.CourseCode)
+	if err != nil {
+		// The flag is saved; the next late policy change regrades the rest
+		utils.LogError("AttendanceHandler", "SetSessionOptional", err)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance session updated successfully", gin.H{
+		"session":  session,
+		"regraded": regraded,
+	})
+}
+
+// GetSessionQR returns the current short-lived QR payload of one of the current lecturer's open
+// sessions. The lecturer's screen calls this again at rotates_at to show the next code.
+func (h *AttendanceHandler) GetSessionQR(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	if !session.IsOpen() {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is "+string(session.Status), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "QR code generated successfully", h.newSessionQR(session))
+}
+
+// newSessionQR returns the QR code of an open session for the current rotation interval. Every
+// screen gets the same code, and should ask again at rotates_at.
+func (h *AttendanceHandler) newSessionQR(session *models.AttendanceSession) gin.H {
+	payload, token := h.qrSigner.Current(session.ID)
+
+	return gin.H{
+		"session_id": session.ID,
+		"payload":    payload,
+		"expires_at": token.ExpiresAt,
+		"rotates_at": token.RotatesAt(),
+		"ttl":        int(qrtoken.DefaultTTL.Seconds()),
+	}
+}
+
+// ScopedTokenRequest is the optional request body for minting a display or kiosk token
+type ScopedTokenRequest struct {
+	TTLMinutes int `json:"ttl_minutes" binding:"omitempty,min=1"` // Defaults to 15, capped by SCOPED_TOKEN_MAX_TTL_<CAPABILITY>
+}
+
+// bindScopedTokenTTL reads the optional token lifetime of a mint request. It writes the error
+// response and returns false when the body is invalid.
+func bindScopedTokenTTL(c *gin.Context) (time.Duration, bool) {
+	var req ScopedTokenRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+			return 0, false
+		}
+	}
+	return time.Duration(req.TTLMinutes) * time.Minute, true
+}
+
+// CreateDisplayToken mints a short-lived token that lets a projector or shared screen show
+// the rotating QR code of a session without signing in as the lecturer
+func (h *AttendanceHandler) CreateDisplayToken(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	if !session.IsOpen() {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is "+string(session.Status), nil)
+		return
+	}
+
+	ttl, ok := bindScopedTokenTTL(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(c)
+	token, err := h.scopedTokens.Mint(auth.DisplayCapability, auth.SessionResource, session.ID, userID, ttl)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to mint display token: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Display token created successfully", gin.H{
+		"token":      token.Token,
+		"scope":      token.Scope,
+		"expires_at": token.ExpiresAt,
+		"path":       fmt.Sprintf("/api/v1/display/sessions/%d/qr?token=%s", session.ID, token.Token),
+	})
+}
+
+// GetDisplayQR returns the rotating QR code of a session to a display authorized by a
+// display token
+func (h *AttendanceHandler) GetDisplayQR(c *gin.Context) {
+	sessionID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	session, err := h.attendanceRepo.FindSessionByID(sessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return
+	}
+	if session == nil {
+		utils.NotFoundResponse(c, "Attendance session not found")
+		return
+	}
+	if !session.IsOpen() {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is "+string(session.Status), nil)
+		return
+	}
+
+	qr := h.newSessionQR(session)
+	qr["course_code"] = session.CourseCode
+	qr["course_name"] = session.CourseName
+	qr["class_name"] = session.ClassName
+
+	utils.SuccessResponse(c, http.StatusOK, "QR code generated successfully", qr)
+}
+
+// GetKioskSessions returns the QR codes of the sessions open in a room to a kiosk authorized
+// by a kiosk token
+func (h *AttendanceHandler) GetKioskSessions(c *gin.Context) {
+	roomID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	room, err := h.roomRepo.FindByID(roomID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room: "+err.Error())
+		return
+	}
+	if room == nil {
+		utils.NotFoundResponse(c, "Room not found")
+		return
+	}
+
+	sessions, err := h.attendanceRepo.FindOpenSessionsInRoom(room.Code)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance sessions: "+err.Error())
+		return
+	}
+
+	codes := make([]gin.H, 0, len(sessions))
+	for i := range sessions {
+		qr := h.newSessionQR(&sessions[i])
+		qr["course_code"] = sessions[i].CourseCode
+		qr["course_name"] = sessions[i].CourseName
+		qr["class_name"] = sessions[i].ClassName
+		codes = append(codes, qr)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Room sessions retrieved successfully", gin.H{
+		"room":     room.Code,
+		"sessions": codes,
+	})
+}
+
+// GetMySessions returns the attendance sessions opened by the current lecturer
+func (h *AttendanceHandler) GetMySessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.attendanceRepo.FindSessionsByLecturer(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance sessions: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance sessions retrieved successfully", sessions)
+}
+
+// sessionListOptions are the sort, filter and search parameters of the session list
+var sessionListOptions = utils.PageOptions{
+	Sorts: map[string]string{
+		"opened_at":      "opened_at",
+		"course_code":    "course_code",
+		"meeting_number": "meeting_number",
+		"status":         "status",
+	},
+	Filters: map[string]string{
+		"status":           "status",
+		"course_code":      "course_code",
+		"class_name":       "class_name",
+		"semester":         "semester",
+		"room":             "room",
+		"lecturer_user_id": "lecturer_user_id",
+	},
+	Search:      []string{"course_code", "course_name", "topic"},
+	DefaultSort: "-opened_at",
+}
+
+// ListSessions returns one page of the attendance sessions of every lecturer
+func (h *AttendanceHandler) ListSessions(c *gin.Context) {
+	query, err := utils.ParsePageQuery(c, sessionListOptions)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	sessions, total, err := h.attendanceRepo.FindSessions(query)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance sessions: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance sessions retrieved successfully", utils.NewPageResult(sessions, total, query))
+}
+
+// GetSessionRecords returns the students who checked in to one of the current lecturer's sessions
+func (h *AttendanceHandler) GetSessionRecords(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	records, err := h.attendanceRepo.FindRecordsBySession(session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance records: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance records retrieved successfully", gin.H{
+		"session": session,
+		"records": records,
+	})
+}
+
+// GetCheckInHealth returns how the check-ins of one of the current lecturer's sessions are
+// faring, so the app can warn the lecturer when the service rather than the students is failing
+func (h *AttendanceHandler) GetCheckInHealth(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	report, err := h.telemetry.SessionHealth(session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch check-in health: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Check-in health retrieved successfully", report)
+}
+
+// GetCheckInSLAReport returns the check-in success rate, latency and system failures of every
+// session with check-in attempts between from and to, with how many students each incident kept
+// from checking in
+func (h *AttendanceHandler) GetCheckInSLAReport(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	sessions, err := h.telemetry.SLAReport(from, to.Add(24*time.Hour))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build check-in SLA report: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Check-in SLA report generated successfully", sessions)
+}
+
+// GetCourseRecap returns the attendance of every student in every meeting of one of the
+// current lecturer's courses, identified by course code and optionally narrowed by semester
+// and class_name
+func (h *AttendanceHandler) GetCourseRecap(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	recap, err := h.attendanceRepo.CourseRecap(recapFilter(c, userID))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build attendance recap: "+err.Error())
+		return
+	}
+	if len(recap.Meetings) == 0 {
+		utils.NotFoundResponse(c, "No attendance sessions found for this course")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance recap retrieved successfully", recap)
+}
+
+// ExportCourseAttendance streams the attendance recap of one of the current lecturer's
+// courses as a file; format=xlsx (the default) gives an Excel workbook
+func (h *AttendanceHandler) ExportCourseAttendance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	format := c.DefaultQuery("format", "xlsx")
+	if format != "xlsx" {
+		utils.BadRequestResponse(c, "Unsupported export format: "+format)
+		return
+	}
+
+	recap, err := h.attendanceRepo.CourseRecap(recapFilter(c, userID))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build attendance recap: "+err.Error())
+		return
+	}
+	if len(recap.Meetings) == 0 {
+		utils.NotFoundResponse(c, "No attendance sessions found for this course")
+		return
+	}
+
+	workbook, err := h.exportService.AttendanceWorkbook(recap)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to export attendance: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Type", xlsx.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"attendance-%s.xlsx\"", exportFileName(recap)))
+	if err := workbook.Write(c.Writer); err != nil {
+		utils.LogError("AttendanceHandler", "ExportCourseAttendance", err)
+	}
+}
+
+// exportFileName builds a file name from the course, class and semester of a recap
+func exportFileName(recap *models.AttendanceRecap) string {
+	name := recap.CourseCode
+	for _, part := range []string{recap.ClassName, recap.Semester} {
+		if part != "" {
+			name += "-" + part
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' || r == '\\' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// recordEditRequest is the body of a manual attendance correction
+type recordEditRequest struct {
+	Status models.AttendanceStatus `json:"status" binding:"required"`
+	Credit *float64                `json:"credit" binding:"omitempty,min=0,max=1"` // Defaults to full credit
+	Reason string                  `json:"reason" binding:"max=500"`
+}
+
+// newEdit builds the edit the request makes to a student's attendance in a session
+func (req *recordEditRequest) newEdit(sessionID, studentUserID, editorUserID uint) *models.AttendanceEdit {
+	credit := 1.0
+	if req.Credit != nil {
+		credit = *req.Credit
+	}
+	return &models.AttendanceEdit{
+		SessionID:      sessionID,
+		StudentUserID:  studentUserID,
+		NewStatus:      req.Status,
+		NewCredit:      credit,
+		Reason:         strings.TrimSpace(req.Reason),
+		EditedByUserID: editorUserID,
+	}
+}
+
+// applyRecordEdit stores a manual attendance correction with its edit trail and writes the response
+func (h *AttendanceHandler) applyRecordEdit(c *gin.Context, edit *models.AttendanceEdit) {
+	record, err := h.attendanceRepo.EditRecord(edit)
+	if errors.Is(err, repository.ErrAttendanceUnchanged) {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance already has this status", nil)
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update attendance record: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance record updated successfully", gin.H{
+		"record": record,
+		"edit":   edit,
+	})
+}
+
+// UpdateRecord corrects the status of a student's check-in in one of the current lecturer's
+// sessions, or a session of a course the current assistant may edit records of
+func (h *AttendanceHandler) UpdateRecord(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	recordID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req recordEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	switch req.Status {
+	case models.AttendancePresent, models.AttendanceLate, models.AttendanceExcused:
+	default:
+		utils.BadRequestResponse(c, "status must be present, late or excused")
+		return
+	}
+
+	record, err := h.attendanceRepo.FindRecordByID(recordID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance record: "+err.Error())
+		return
+	}
+	if record == nil {
+		utils.NotFoundResponse(c, "Attendance record not found")
+		return
+	}
+	session, err := h.attendanceRepo.FindSessionByID(record.SessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return
+	}
+	if session == nil || (session.LecturerUserID != userID && !grantCovers(c, session)) {
+		utils.NotFoundResponse(c, "Attendance record not found")
+		return
+	}
+
+	h.applyRecordEdit(c, req.newEdit(record.SessionID, record.StudentUserID, userID))
+}
+
+// EditStudentRecord sets the attendance status of a student in one of the current lecturer's
+// sessions after the fact. Marking an absent student as attending creates their record and
+// marking a student absent removes it; every edit is kept with its editor and previous status.
+func (h *AttendanceHandler) EditStudentRecord(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+	if session.Status == models.SessionScheduled {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session has not been opened yet", nil)
+		return
+	}
+
+	studentUserID, err := parseIDParam(c, "studentId")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req recordEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	switch req.Status {
+	case models.AttendancePresent, models.AttendanceLate, models.AttendanceExcused, models.AttendanceAbsent:
+	default:
+		utils.BadRequestResponse(c, "status must be present, late, excused or absent")
+		return
+	}
+
+	edit := req.newEdit(session.ID, studentUserID, userID)
+	record, err := h.attendanceRepo.FindStudentRecord(session.ID, studentUserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance record: "+err.Error())
+		return
+	}
+	if record == nil && req.Status != models.AttendanceAbsent {
+		// Only enrolled students can be given a record they did not check in for
+		nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, studentUserID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+			return
+		}
+		enrolled, err := h.enrollmentRepo.IsEnrolled(nim, session.CourseCode, session.ClassName, session.Semester)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check enrollment: "+err.Error())
+			return
+		}
+		if !enrolled {
+			utils.NotFoundResponse(c, "Student is not enrolled in this session's course")
+			return
+		}
+		edit.Nim = nim
+	}
+
+	h.applyRecordEdit(c, edit)
+}
+
+// GetRecordEdits lists the manual attendance corrections made in one of the current lecturer's sessions
+func (h *AttendanceHandler) GetRecordEdits(c *gin.Context) {
+	session := h.findOwnSession(c)
+	if session == nil {
+		return
+	}
+
+	edits, err := h.attendanceRepo.FindEditsBySession(session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance edits: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance edits retrieved successfully", edits)
+}
+
+// CheckIn records the current student's attendance in an open session
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	start := time.Now()
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		SessionID uint   `json:"session_id"`
+		QRToken   string `json:"qr_token"` // Scanned value of the session's QR code
+		// GPS position of the student, required by geofenced sessions
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+		Accuracy  *float64 `json:"accuracy"` // Meters, as reported by the device
+		// Face embedding computed by the app, required when face verification is enabled
+		FaceEmbedding []float64 `json:"face_embedding"`
+		FaceModel     string    `json:"face_model"`
+		// Play Integrity or App Attest attestation, required when device attestation is enabled
+		Attestation *services.AttestationEvidence `json:"attestation"`
+		// Device the app runs on, only kept as check-in telemetry
+		DeviceID    string `json:"device_id"`
+		DeviceModel string `json:"device_model"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil || (req.SessionID == 0 && req.QRToken == "") {
+		utils.BadRequestResponse(c, "Session ID or QR token is required")
+		return
+	}
+
+	method := models.CheckInManual
+	if req.QRToken != "" {
+		token, err := h.qrSigner.Validate(req.QRToken)
+		if err != nil {
+			if errors.Is(err, qrtoken.ErrExpiredToken) {
+				utils.BadRequestResponse(c, "QR code has expired, scan the latest code")
+				return
+			}
+			utils.BadRequestResponse(c, "Invalid QR code")
+			return
+		}
+		req.SessionID = token.SessionID
+		method = models.CheckInQR
+	}
+
+	session, err := h.attendanceRepo.FindSessionByID(req.SessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return
+	}
+	if session == nil {
+		utils.NotFoundResponse(c, "Attendance session not found")
+		return
+	}
+
+	// The raw context of the attempt is kept whatever its outcome for fraud investigations
+	telemetry := &models.CheckInTelemetry{
+		SessionID:     session.ID,
+		StudentUserID: userID,
+		Method:        method,
+		Factors:       checkInFactors(method, req.Latitude != nil && req.Longitude != nil, len(req.FaceEmbedding) > 0, req.Attestation != nil),
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Accuracy:      req.Accuracy,
+		DeviceID:      truncate(req.DeviceID, 100),
+		DeviceModel:   truncate(req.DeviceModel, 100),
+		AppVersion:    truncate(c.GetHeader(utils.AppVersionHeader), 30),
+		UserAgent:     truncate(c.Request.UserAgent(), 255),
+		IPAddress:     c.ClientIP(),
+	}
+	// Factors in shadow mode are evaluated without turning anyone away; what they would have
+	// decided is kept to compare against the check-ins actually accepted
+	var shadowResults []models.ShadowFactorResult
+	// The step in progress when the attempt is turned away is kept as its failure reason
+	step := models.StepSession
+	defer func() {
+		telemetry.HTTPStatus = c.Writer.Status()
+		telemetry.LatencyMs = time.Since(start).Milliseconds()
+		if telemetry.RecordID == nil {
+			telemetry.FailedStep = step
+		}
+		h.telemetry.Record(*telemetry)
+		h.factorRollouts.RecordShadow(session, userID, shadowResults, telemetry.RecordID != nil)
+	}()
+
+	if !session.IsOpen() {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is closed", nil)
+		return
+	}
+	if session.RequireQR && method != models.CheckInQR {
+		utils.BadRequestResponse(c, "This session requires scanning the QR code")
+		return
+	}
+
+	factorModes, err := h.factorRollouts.Modes(session.CourseCode)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load check-in factors: "+err.Error())
+		return
+	}
+
+	step = models.StepAttestation
+	if !h.checkAttestation(c, userID, req.Attestation) {
+		return
+	}
+	step = models.StepGeofence
+	distance, ok := h.checkGeofence(c, session, req.Latitude, req.Longitude)
+	telemetry.Distance = distance
+	if !ok {
+		return
+	}
+
+	step = models.StepFace
+	var faceScore *float64
+	switch mode := factorModes[models.FaceFactor]; mode {
+	case models.FactorModeOff:
+	case models.FactorModeShadow:
+		result := h.factorRollouts.EvaluateFace(userID, req.FaceEmbedding, req.FaceModel)
+		telemetry.FaceScore = result.Score
+		shadowResults = append(shadowResults, result)
+	default:
+		faceScore, ok = h.checkFace(c, userID, req.FaceEmbedding, req.FaceModel, mode == models.FactorModeEnforce)
+		telemetry.FaceScore = faceScore
+		if !ok {
+			return
+		}
+	}
+
+	step = models.StepWifi
+	switch mode := factorModes[models.WifiFactor]; mode {
+	case models.FactorModeOff:
+	case models.FactorModeShadow:
+		shadowResults = append(shadowResults, h.factorRollouts.EvaluateWifi(c.ClientIP()))
+	default:
+		if !h.checkWifi(c, mode == models.FactorModeEnforce) {
+			return
+		}
+	}
+
+	step = models.StepEnrollment
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	enrolled, err := h.enrollmentRepo.IsEnrolled(nim, session.CourseCode, session.ClassName, session.Semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check enrollment: "+err.Error())
+		return
+	}
+	if !enrolled {
+		utils.ForbiddenResponse(c, "You are not enrolled in this course")
+		return
+	}
+
+	step = models.StepRecord
+	record := &models.AttendanceRecord{
+		SessionID:     session.ID,
+		StudentUserID: userID,
+		Nim:           nim,
+		Status:        models.AttendancePresent,
+		Method:        method,
+		Distance:      distance,
+		FaceScore:     faceScore,
+		CheckedInAt:   time.Now(),
+	}
+	if err := h.latePolicy.Grade(session, record); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to apply late policy: "+err.Error())
+		return
+	}
+
+	checkedIn := func() events.Event { return events.AttendanceCheckedIn{Actor: eventActor(c), Record: *record} }
+	created, err := h.attendanceRepo.CreateRecord(record, checkedIn)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save check-in: "+err.Error())
+		return
+	}
+
+	telemetry.RecordID = &record.ID
+	// Retries of a check-in that already went through get the stored record back
+	if !created {
+		utils.SuccessResponse(c, http.StatusOK, "You have already checked in to this session", record)
+		return
+	}
+	h.bus.PublishStaged(checkedIn())
+
+	if record.Overflow {
+		utils.SuccessResponse(c, http.StatusCreated, "Check-in recorded as overflow, the session is full", record)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, "Check-in recorded successfully", record)
+}
+
+// checkInFactors lists the verification factors a check-in attempt was made with
+func checkInFactors(method models.CheckInMethod, location, face, attested bool) string {
+	var factors []string
+	if method == models.CheckInQR {
+		factors = append(factors, "qr")
+	}
+	if location {
+		factors = append(factors, "location")
+	}
+	if face {
+		factors = append(factors, "face")
+	}
+	if attested {
+		factors = append(factors, "attestation")
+	}
+	return strings.Join(factors, ",")
+}
+
+// truncate shortens a client supplied value to fit its column
+func truncate(value string, n int) string {
+	if len(value) <= n {
+		return value
+	}
+	return value[:n]
+}
+
+// checkAttestation verifies that a check-in comes from a genuine app on a genuine device. An
+// attestation is always verified when sent for a platform the API supports, and required while
+// device attestation is enabled for the student. It writes the error response and returns
+// false otherwise.
+func (h *AttendanceHandler) checkAttestation(c *gin.Context, userID uint, evidence *services.AttestationEvidence) bool {
+	required := false
+	if caller, ok := featureCaller(c, h.prodiResolver); ok {
+		required = h.features.EnabledFor(features.DeviceAttestation, caller)
+	}
+
+	if evidence == nil || !h.attestation.Supports(evidence.Platform) {
+		if required {
+			utils.BadRequestResponse(c, "Device attestation is required to check in")
+			return false
+		}
+		return true
+	}
+
+	err := h.attestation.Verify(c.Request.Context(), userID, *evidence)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, attestation.ErrInvalidChallenge):
+		utils.BadRequestResponse(c, "Attestation challenge is invalid or expired, request a new one")
+		return false
+	case errors.Is(err, attestation.ErrRejected), errors.Is(err, services.ErrAppAttestKeyUnknown):
+		requestLog(c).Warnf("Rejected device attestation of user ID %d: %v", userID, err)
+		utils.ErrorResponse(c, http.StatusForbidden, "Device attestation failed", gin.H{"code": "attestation_failed"})
+		return false
+	case !required:
+		// No verdict could be obtained; only check-ins that require one are turned away
+		requestLog(c).Warnf("Could not verify device attestation of user ID %d: %v", userID, err)
+		return true
+	default:
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Device attestation is unavailable, please try again", err.Error())
+		return false
+	}
+}
+
+// checkGeofence verifies that the student is within the session's geofence and returns
+// their distance from it. It writes the error response and returns false otherwise; the
+// distance is still returned when the student is too far.
+func (h *AttendanceHandler) checkGeofence(c *gin.Context, session *models.AttendanceSession, latitude, longitude *float64) (*float64, bool) {
+	if !session.HasGeofence() && !session.AwaitingAnchor() {
+		return nil, true
+	}
+	if caller, ok := featureCaller(c, h.prodiResolver); ok && !h.features.EnabledFor(features.Geofence, caller) {
+		return nil, true
+	}
+	if session.AwaitingAnchor() {
+		utils.ErrorResponse(c, http.StatusConflict, "The lecturer has not checked in to this session yet, please try again shortly", nil)
+		return nil, false
+	}
+
+	if latitude == nil || longitude == nil {
+		utils.BadRequestResponse(c, "Your location is required to check in to this session")
+		return nil, false
+	}
+	if !utils.ValidCoordinates(*latitude, *longitude) {
+		utils.BadRequestResponse(c, "latitude or longitude is out of range")
+		return nil, false
+	}
+
+	distance := math.Round(utils.DistanceMeters(*session.Latitude, *session.Longitude, *latitude, *longitude))
+	if distance > float64(session.GeofenceRadius) {
+		utils.ErrorResponse(c, http.StatusForbidden, "You are too far from the classroom to check in", gin.H{
+			"distance":        distance,
+			"geofence_radius": session.GeofenceRadius,
+		})
+		return &distance, false
+	}
+
+	return &distance, true
+}
+
+// checkWifi verifies that a check-in comes from the campus Wi-Fi when the course enforces the
+// Wi-Fi factor or Wi-Fi verification is enabled for the student. It writes the error response
+// and returns false otherwise.
+func (h *AttendanceHandler) checkWifi(c *gin.Context, enforced bool) bool {
+	if !enforced {
+		caller, ok := featureCaller(c, h.prodiResolver)
+		if !ok || !h.features.EnabledFor(features.WifiVerification, caller) {
+			return true
+		}
+	}
+
+	result := h.factorRollouts.EvaluateWifi(c.ClientIP())
+	switch result.Outcome {
+	case models.FactorPassed:
+		return true
+	case models.FactorUnavailable:
+		// Without the campus ranges nobody could pass, so the factor is not applied
+		requestLog(c).Warnf("Skipping Wi-Fi verification: %s", result.Detail)
+		return true
+	default:
+		utils.ErrorResponse(c, http.StatusForbidden, "Connect to the campus Wi-Fi to check in", gin.H{"code": "wifi_required"})
+		return false
+	}
+}
+
+// checkFace verifies the submitted face embedding against the student's registered face.
+// An embedding is always verified when sent and required when the course enforces the face
+// factor or face verification is enabled for the student. It writes the error response and
+// returns false otherwise; the score is still returned when the face does not match.
+func (h *AttendanceHandler) checkFace(c *gin.Context, userID uint, embedding []float64, model string, enforced bool) (*float64, bool) {
+	if len(embedding) == 0 {
+		if enforced {
+			utils.BadRequestResponse(c, "Face verification is required to check in")
+			return nil, false
+		}
+		if caller, ok := featureCaller(c, h.prodiResolver); ok && h.features.EnabledFor(features.FaceVerification, caller) {
+			utils.BadRequestResponse(c, "Face verification is required to check in")
+			return nil, false
+		}
+		return nil, true
+	}
+
+	score, err := h.faceService.Verify(userID, embedding, model)
+	switch {
+	case err == nil:
+		return &score, true
+	case errors.Is(err, services.ErrFaceMismatch):
+		utils.ErrorResponse(c, http.StatusForbidden, "Face does not match the registered face", gin.H{
+			"score":     score,
+			"threshold": h.faceService.MatchThreshold(),
+		})
+		return &score, false
+	case errors.Is(err, services.ErrFaceNotRegistered):
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, "Register your face before checking in", nil)
+	case errors.Is(err, services.ErrFaceModelMismatch):
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, "Register your face again with the current app version", nil)
+	case errors.Is(err, services.ErrInvalidEmbedding):
+		utils.BadRequestResponse(c, err.Error())
+	default:
+		utils.InternalServerErrorResponse(c, "Failed to verify face: "+err.Error())
+	}
+	return nil, false
+}
+
+// GetMyAttendance returns the current student's attendance history
+func (h *AttendanceHandler) GetMyAttendance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	records, err := h.attendanceRepo.FindRecordsByStudent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance history: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance history retrieved successfully", records)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceScoreHandler exports the attendance component of final grades and manages how it
+// is calculated per course
+type AttendanceScoreHandler struct {
+	policyRepo   repository.AttendanceScoreRepository
+	scores       *services.AttendanceScoreService
+	auditService *services.AuditService
+}
+
+// NewAttendanceScoreHandler creates a new instance of AttendanceScoreHandler
+func NewAttendanceScoreHandler(policyRepo repository.AttendanceScoreRepository, scores *services.AttendanceScoreService, auditService *services.AuditService) *AttendanceScoreHandler {
+	return &AttendanceScoreHandler{
+		policyRepo:   policyRepo,
+		scores:       scores,
+		auditService: auditService,
+	}
+}
+
+// AttendanceScorePolicyRequest is the request body for setting the attendance score of a course
+type AttendanceScorePolicyRequest struct {
+	CourseCode string              `json:"course_code"` // Empty for the default policy
+	Weight     float64             `json:"weight" binding:"required"`
+	Formula    models.ScoreFormula `json:"formula" binding:"required"`
+	Tiers      []models.ScoreTier  `json:"tiers"`
+}
+
+// GetMyCourseScores returns the attendance score of every student of one of the current
+// lecturer's courses, narrowed by semester and class_name like the recap. format=csv gives a
+// file for the grading system.
+func (h *AttendanceScoreHandler) GetMyCourseScores(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	h.writeScores(c, recapFilter(c, userID))
+}
+
+// GetCourseScores returns the attendance score of every student of a course of any lecturer
+func (h *AttendanceScoreHandler) GetCourseScores(c *gin.Context) {
+	h.writeScores(c, models.AttendanceRecapFilter{
+		CourseCode: c.Param("id"),
+		Semester:   c.Query("semester"),
+		ClassName:  c.Query("class_name"),
+	})
+}
+
+// writeScores writes the attendance scores of a course as JSON or CSV
+func (h *AttendanceScoreHandler) writeScores(c *gin.Context, filter models.AttendanceRecapFilter) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		utils.BadRequestResponse(c, "Unsupported export format: "+format)
+		return
+	}
+
+	list, err := h.scores.ForCourse(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to calculate attendance scores: "+err.Error())
+		return
+	}
+	if list == nil {
+		utils.NotFoundResponse(c, "No attendance sessions found for this course")
+		return
+	}
+
+	if format == "json" {
+		utils.SuccessResponse(c, http.StatusOK, "Attendance scores calculated successfully", list)
+		return
+	}
+
+	// Admins who may not see students get the file with their NIMs pseudonymized
+	if pseudonymizer, ok := pseudonym.FromContext(c); ok {
+		for i := range list.Students {
+			list.Students[i].Nim = pseudonymizer.Nim(list.Students[i].Nim)
+		}
+	}
+
+	recap := &models.AttendanceRecap{CourseCode: list.CourseCode, Semester: list.Semester, ClassName: list.ClassName}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"attendance-score-%s.csv\"", exportFileName(recap)))
+
+	formatScore := func(value float64) string { return strconv.FormatFloat(value, 'f', 2, 64) }
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"course_code", "semester", "class_name", "nim", "attendance_percent", "score", "weight", "weighted_score"})
+	for _, student := range list.Students {
+		_ = writer.Write([]string{
+			list.CourseCode,
+			list.Semester,
+			list.ClassName,
+			student.Nim,
+			formatScore(student.WeightedPercent),
+			formatScore(student.Score),
+			formatScore(list.Weight),
+			formatScore(student.WeightedScore),
+		})
+	}
+	writer.Flush()
+}
+
+// ListPolicies lists the attendance score policies of every course with its own policy
+func (h *AttendanceScoreHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance score policies: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance score policies retrieved successfully", gin.H{
+		"policies":       policies,
+		"default_weight": models.DefaultAttendanceScoreWeight,
+	})
+}
+
+// SavePolicy creates or replaces the attendance score policy of a course, or the default for
+// every course without its own when course_code is empty
+func (h *AttendanceScoreHandler) SavePolicy(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req AttendanceScorePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	policy := &models.AttendanceScorePolicy{
+		CourseCode: req.CourseCode,
+		Weight:     req.Weight,
+		Formula:    req.Formula,
+		Tiers:      req.Tiers,
+		UpdatedBy:  adminID,
+	}
+	if err := policy.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err := h.policyRepo.Save(policy); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save attendance score policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "attendance_score_policy.save", "attendance_score_policy", policy.ID, map[string]interface{}{
+		"course_code": policy.CourseCode,
+		"weight":      policy.Weight,
+		"formula":     policy.Formula,
+		"tiers":       policy.Tiers,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance score policy saved successfully", policy)
+}
+
+// DeletePolicy removes the attendance score policy of a course; the default applies again
+func (h *AttendanceScoreHandler) DeletePolicy(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	policy, err := h.policyRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance score policy: "+err.Error())
+		return
+	}
+	if policy == nil {
+		utils.NotFoundResponse(c, "Attendance score policy not found")
+		return
+	}
+
+	if err := h.policyRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete attendance score policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "attendance_score_policy.delete", "attendance_score_policy", policy.ID, map[string]interface{}{
+		"course_code": policy.CourseCode,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance score policy deleted successfully", nil)
+}
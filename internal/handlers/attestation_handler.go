@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"delpresence-api/internal/attestation"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttestationHandler issues the challenges the mobile app attests check-ins with and registers
+// App Attest keys of iPhones
+type AttestationHandler struct {
+	attestationService *services.AttestationService
+	auditService       *services.AuditService
+}
+
+// NewAttestationHandler creates a new instance of AttestationHandler
+func NewAttestationHandler(attestationService *services.AttestationService, auditService *services.AuditService) *AttestationHandler {
+	return &AttestationHandler{
+		attestationService: attestationService,
+		auditService:       auditService,
+	}
+}
+
+// GetChallenge issues a challenge for the current student's next attestation, along with the
+// platforms whose attestations the API verifies
+func (h *AttestationHandler) GetChallenge(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	challenge, err := h.attestationService.Challenge(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to issue attestation challenge: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attestation challenge issued successfully", gin.H{
+		"challenge":  challenge,
+		"expires_in": int(attestation.ChallengeTTL.Seconds()),
+		"platforms": gin.H{
+			string(attestation.Android): h.attestationService.Supports(attestation.Android),
+			string(attestation.IOS):     h.attestationService.Supports(attestation.IOS),
+		},
+	})
+}
+
+// RegisterAppAttestKey verifies and stores an App Attest key generated on the current
+// student's iPhone
+func (h *AttestationHandler) RegisterAppAttestKey(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		KeyID       string `json:"key_id" binding:"required"`
+		Attestation string `json:"attestation" binding:"required"` // Base64 attestation object
+		Challenge   string `json:"challenge" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "key_id, attestation and challenge are required")
+		return
+	}
+
+	key, err := h.attestationService.RegisterAppAttestKey(userID, req.KeyID, req.Attestation, req.Challenge)
+	switch {
+	case errors.Is(err, services.ErrAttestationUnsupported):
+		utils.BadRequestResponse(c, "App Attest is not configured")
+		return
+	case errors.Is(err, attestation.ErrInvalidChallenge):
+		utils.BadRequestResponse(c, "Attestation challenge is invalid or expired, request a new one")
+		return
+	case errors.Is(err, attestation.ErrRejected):
+		utils.ErrorResponse(c, http.StatusForbidden, "App Attest key was rejected", err.Error())
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to register App Attest key: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "attestation.register_key", "app_attest_key", key.ID, map[string]interface{}{
+		"key_id": key.KeyID,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "App Attest key registered successfully", key)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,26 +10,41 @@ import (
 	"strings"
 	"time"
 
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
 	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
 	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AuthHandler handles authentication related requests
 type AuthHandler struct {
-	userRepo  *repository.UserRepository
-	tokenRepo *repository.TokenRepository
+	userRepo       *repository.UserRepository
+	tokenRepo      *repository.TokenRepository
+	userRoleRepo   repository.UserRoleRepository
+	credentialRepo repository.CampusCredentialRepository
+	auditService   *services.AuditService
 }
 
 // NewAuthHandler creates a new instance of AuthHandler
-func NewAuthHandler() *AuthHandler {
+func NewAuthHandler(userRoleRepo repository.UserRoleRepository, credentialRepo repository.CampusCredentialRepository, auditService *services.AuditService) *AuthHandler {
 	return &AuthHandler{
-		userRepo:  repository.NewUserRepository(),
-		tokenRepo: repository.NewTokenRepository(),
+		userRepo:       repository.NewUserRepository(),
+		tokenRepo:      repository.NewTokenRepository(),
+		userRoleRepo:   userRoleRepo,
+		credentialRepo: credentialRepo,
+		auditService:   auditService,
 	}
 }
 
+// RefreshTokenRequest is the request body for refreshing or revoking a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 // CampusLoginResponse represents the response from campus auth API
 type CampusLoginResponse struct {
 	Result       bool       `json:"result"`
@@ -55,7 +71,25 @@ type CampusJabatan struct {
 	Jabatan           string `json:"jabatan"`
 }
 
-// CampusLogin handles login through campus authentication system
+// CampusTokenResponse is returned by a successful campus login. It keeps the fields of the
+// CIS response the app reads, but token and refresh_token are issued by DelPresence; the
+// CIS tokens themselves never leave the server.
+type CampusTokenResponse struct {
+	Result           bool       `json:"result"`
+	Success          string     `json:"success"`
+	User             CampusUser `json:"user"`
+	Token            string     `json:"token"`
+	RefreshToken     string     `json:"refresh_token"`
+	TokenType        string     `json:"token_type"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at"`
+	ActiveRole       string     `json:"active_role"`
+	Roles            []string   `json:"roles"`
+}
+
+// CampusLogin handles login through campus authentication system. The CIS tokens are kept
+// on the server and exchanged for DelPresence tokens referencing the campus identity, so
+// sessions can be refreshed and revoked by DelPresence.
 func (h *AuthHandler) CampusLogin(c *gin.Context) {
 	// Get username and password from form data
 	username := c.PostForm("username")
@@ -66,6 +100,64 @@ func (h *AuthHandler) CampusLogin(c *gin.Context) {
 		return
 	}
 
+	campusResponse, err := campusAuthenticate(username, password)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	if !campusResponse.Result {
+		// Failed login
+		c.JSON(http.StatusUnauthorized, campusResponse)
+		return
+	}
+
+	if err := saveCampusCredential(h.credentialRepo, username, campusResponse); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to store campus credential: "+err.Error())
+		return
+	}
+
+	campusUserID := campusResponse.User.UserID
+	roles, activeRole, err := campusRoles(h.userRoleRepo, campusUserID, "")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+
+	accessToken, expiresAt, err := jwt.GenerateRoleToken(uint(campusUserID), campusUserID, campusResponse.User.Email, roles, activeRole)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate token")
+		return
+	}
+	refreshToken, refreshExpiresAt, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate refresh token")
+		return
+	}
+	if err := h.tokenRepo.CreateCampusRefreshToken(uint(campusUserID), jwt.HashRefreshToken(refreshToken), activeRole, refreshExpiresAt); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to store refresh token")
+		return
+	}
+
+	// Successful login, kept for fraud investigations
+	recordCampusLogin(h.auditService, c, campusResponse.User, "mobile")
+	c.JSON(http.StatusOK, CampusTokenResponse{
+		Result:           true,
+		Success:          campusResponse.Success,
+		User:             campusResponse.User,
+		Token:            accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+		ActiveRole:       activeRole,
+		Roles:            roles,
+	})
+}
+
+// campusAuthenticate exchanges campus credentials for a campus token with the CIS auth API.
+// Rejected credentials are not an error; they come back with Result false.
+func campusAuthenticate(username, password string) (*CampusLoginResponse, error) {
 	// Create form data for the campus API
 	formData := url.Values{}
 	formData.Add("username", username)
@@ -80,8 +172,7 @@ func (h *AuthHandler) CampusLogin(c *gin.Context) {
 	req, err := http.NewRequest("POST", "https://cis.del.ac.id/api/jwt-api/do-auth",
 		strings.NewReader(formData.Encode()))
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to create request")
-		return
+		return nil, errors.New("Failed to create request")
 	}
 
 	// Set required headers
@@ -92,46 +183,79 @@ func (h *AuthHandler) CampusLogin(c *gin.Context) {
 	// Send the request
 	resp, err := client.Do(req)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, fmt.Sprintf("Failed to reach campus API: %v", err))
-		return
+		return nil, fmt.Errorf("Failed to reach campus API: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to read response from campus API")
-		return
+		return nil, errors.New("Failed to read response from campus API")
 	}
 
 	// Check if we got a valid JSON response
 	var campusResponse CampusLoginResponse
 	if err := json.Unmarshal(body, &campusResponse); err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to parse response from campus API")
-		return
+		return nil, errors.New("Failed to parse response from campus API")
 	}
+	return &campusResponse, nil
+}
 
-	// Return the response directly to the client
-	if campusResponse.Result {
-		// Successful login
-		c.JSON(http.StatusOK, campusResponse)
-	} else {
-		// Failed login
-		c.JSON(http.StatusUnauthorized, campusResponse)
+// saveCampusCredential keeps the CIS tokens of a successful campus login on the server
+func saveCampusCredential(credentialRepo repository.CampusCredentialRepository, username string, campusResponse *CampusLoginResponse) error {
+	return credentialRepo.Save(&models.CampusCredential{
+		CampusUserID: campusResponse.User.UserID,
+		Username:     username,
+		Token:        campusResponse.Token,
+		RefreshToken: campusResponse.RefreshToken,
+		LastLoginAt:  time.Now(),
+	})
+}
+
+// campusRoles returns the roles linked to a campus user and the one to act as: requested
+// when it is held, the default role when requested is empty, and "" otherwise
+func campusRoles(userRoleRepo repository.UserRoleRepository, campusUserID int, requested string) ([]string, string, error) {
+	userRoles, err := userRoleRepo.FindByUserID(models.CampusIdentity, uint(campusUserID))
+	if err != nil {
+		return nil, "", err
+	}
+
+	roles := make([]string, 0, len(userRoles))
+	activeRole := ""
+	for _, userRole := range userRoles {
+		roles = append(roles, string(userRole.Role))
+		if (requested == "" && userRole.IsDefault) || string(userRole.Role) == requested {
+			activeRole = string(userRole.Role)
+		}
 	}
+	return roles, activeRole, nil
+}
+
+// recordCampusLogin audits a successful campus login; client tells the app and the
+// dashboard apart
+func recordCampusLogin(auditService *services.AuditService, c *gin.Context, user CampusUser, client string) {
+	auditService.Record(services.AuditEntry{
+		ActorUserID: uint(user.UserID),
+		ActorType:   strings.ToLower(user.Role),
+		Action:      services.CampusLoginAction,
+		EntityType:  "user",
+		EntityID:    user.UserID,
+		Details:     map[string]interface{}{"user_agent": c.Request.UserAgent(), "client": client},
+		IPAddress:   c.ClientIP(),
+	})
 }
 
 // GetCurrentUser handles getting the current user's information
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	// Get user ID from context
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		utils.UnauthorizedResponse(c, "User not authorized")
 		return
 	}
 
 	// Get user from database
-	user, err := h.userRepo.GetUserByID(userID.(uint))
+	user, err := h.userRepo.GetUserByID(principal.UserID)
 	if err != nil {
 		utils.NotFoundResponse(c, "User not found")
 		return
@@ -149,6 +273,220 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User information retrieved successfully", userResponse)
 }
 
+// RefreshToken exchanges a refresh token for a new access/refresh pair.
+// The presented refresh token is revoked, so each one can only be used once.
+// Refresh tokens of local accounts and of campus logins are both accepted.
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	hashed := jwt.HashRefreshToken(req.RefreshToken)
+	stored, err := h.tokenRepo.GetTokenByValue(hashed, models.RefreshToken)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		if campusStored, campusErr := h.tokenRepo.GetTokenByValue(hashed, models.CampusRefreshToken); !errors.Is(campusErr, repository.ErrTokenNotFound) {
+			stored, err = campusStored, campusErr
+		}
+	}
+	switch {
+	case errors.Is(err, repository.ErrTokenExpired):
+		utils.UnauthorizedResponse(c, "Refresh token has expired")
+		return
+	case errors.Is(err, repository.ErrTokenNotFound):
+		revokeReusedToken(h.tokenRepo, hashed, models.RefreshToken)
+		revokeReusedToken(h.tokenRepo, hashed, models.CampusRefreshToken)
+		utils.UnauthorizedResponse(c, "Invalid refresh token")
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to validate refresh token")
+		return
+	}
+
+	if stored.Type == models.CampusRefreshToken {
+		h.refreshCampusToken(c, stored, hashed)
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(stored.UserID)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "User not found")
+		return
+	}
+
+	userRoles, err := h.userRoleRepo.FindByUserID(models.LocalIdentity, user.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+	roles := make([]string, 0, len(userRoles))
+	held := stored.ActiveRole == ""
+	for _, userRole := range userRoles {
+		roles = append(roles, string(userRole.Role))
+		if string(userRole.Role) == stored.ActiveRole {
+			held = true
+		}
+	}
+	if !held {
+		// The role was unlinked after the token was issued
+		h.tokenRepo.DeleteToken(hashed)
+		utils.UnauthorizedResponse(c, "Role is no longer linked to this account")
+		return
+	}
+
+	accessToken, expiresAt, err := jwt.GenerateRoleToken(user.ID, 0, user.Email, roles, stored.ActiveRole)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate token")
+		return
+	}
+
+	refreshToken, refreshExpiresAt, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate refresh token")
+		return
+	}
+	if _, err := h.tokenRepo.RotateToken(stored, jwt.HashRefreshToken(refreshToken), refreshExpiresAt); err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) {
+			// Another request rotated the same token first
+			utils.UnauthorizedResponse(c, "Invalid refresh token")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to rotate refresh token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", gin.H{
+		"access_token":       accessToken,
+		"refresh_token":      refreshToken,
+		"token_type":         "Bearer",
+		"expires_at":         expiresAt,
+		"refresh_expires_at": refreshExpiresAt,
+		"active_role":        stored.ActiveRole,
+		"roles":              roles,
+	})
+}
+
+// refreshCampusToken issues a new access/refresh pair for a campus login. The CIS token is
+// not consulted, so the session lasts as long as DelPresence allows.
+func (h *AuthHandler) refreshCampusToken(c *gin.Context, stored *models.Token, hashed string) {
+	campusUserID := int(stored.UserID)
+	roles, activeRole, err := campusRoles(h.userRoleRepo, campusUserID, stored.ActiveRole)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+	if activeRole != stored.ActiveRole {
+		// The role was unlinked after the token was issued
+		h.tokenRepo.DeleteToken(hashed)
+		utils.UnauthorizedResponse(c, "Role is no longer linked to this account")
+		return
+	}
+
+	accessToken, expiresAt, err := jwt.GenerateRoleToken(uint(campusUserID), campusUserID, "", roles, activeRole)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate token")
+		return
+	}
+
+	refreshToken, refreshExpiresAt, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate refresh token")
+		return
+	}
+	if _, err := h.tokenRepo.RotateToken(stored, jwt.HashRefreshToken(refreshToken), refreshExpiresAt); err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) {
+			// Another request rotated the same token first
+			utils.UnauthorizedResponse(c, "Invalid refresh token")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to rotate refresh token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", gin.H{
+		"access_token":       accessToken,
+		"refresh_token":      refreshToken,
+		"token_type":         "Bearer",
+		"expires_at":         expiresAt,
+		"refresh_expires_at": refreshExpiresAt,
+		"active_role":        activeRole,
+		"roles":              roles,
+	})
+}
+
+// RevokeCampusTokens ends every session of a campus user: DelPresence tokens issued so far
+// stop working and their refresh tokens are revoked. The user can log in again.
+func (h *AuthHandler) RevokeCampusTokens(c *gin.Context) {
+	campusUserID, err := parseIDParam(c, "campusUserId")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	// Tokens carry their issue time in whole seconds
+	revokedAt := time.Now().Truncate(time.Second)
+	if err := h.credentialRepo.RevokeTokens(int(campusUserID), revokedAt); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to revoke tokens: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "auth.revoke_campus_tokens", "user", campusUserID, nil))
+	utils.SuccessResponse(c, http.StatusOK, "Campus user tokens revoked successfully", gin.H{
+		"campus_user_id":     campusUserID,
+		"tokens_valid_after": revokedAt,
+	})
+}
+
+// revokeReusedToken revokes every token of a type held by a user when one of them that was
+// already rotated is presented again, since that means the token chain has leaked
+func revokeReusedToken(tokenRepo *repository.TokenRepository, hashed string, tokenType models.TokenType) {
+	revoked, err := tokenRepo.GetRevokedToken(hashed, tokenType)
+	if err != nil || revoked == nil {
+		return
+	}
+
+	utils.LogWarning("AuthHandler", "RefreshToken", fmt.Sprintf("revoked %s token reused for user %d, revoking all of them", tokenType, revoked.UserID))
+	if err := tokenRepo.DeleteUserTokensByType(revoked.UserID, tokenType); err != nil {
+		utils.LogError("AuthHandler", "RevokeRefreshTokens", err)
+	}
+}
+
+// Logout revokes a refresh token. Unknown tokens are accepted so the call is idempotent.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.tokenRepo.DeleteToken(jwt.HashRefreshToken(req.RefreshToken)); err != nil && !errors.Is(err, repository.ErrTokenNotFound) {
+		utils.InternalServerErrorResponse(c, "Failed to revoke refresh token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// issueRefreshToken creates and stores a refresh token for the principal, of the campus kind
+// for campus-authenticated users
+func issueRefreshToken(tokenRepo *repository.TokenRepository, principal *auth.Principal, activeRole string) (string, time.Time, error) {
+	refreshToken, expiresAt, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	hashed := jwt.HashRefreshToken(refreshToken)
+	if principal.CampusUserID != 0 {
+		err = tokenRepo.CreateCampusRefreshToken(uint(principal.CampusUserID), hashed, activeRole, expiresAt)
+	} else {
+		err = tokenRepo.CreateRefreshToken(principal.UserID, hashed, activeRole, expiresAt)
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return refreshToken, expiresAt, nil
+}
+
 // Helper function to generate a random string
 func generateRandomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recentOperationsLimit caps how many backups and drills are listed
+const recentOperationsLimit = 50
+
+// BackupHandler handles backup and restore drill operations
+type BackupHandler struct {
+	backupRepo    repository.BackupRepository
+	backupService *services.BackupService
+	auditService  *services.AuditService
+}
+
+// NewBackupHandler creates a new BackupHandler
+func NewBackupHandler(backupRepo repository.BackupRepository, backupService *services.BackupService, auditService *services.AuditService) *BackupHandler {
+	return &BackupHandler{
+		backupRepo:    backupRepo,
+		backupService: backupService,
+		auditService:  auditService,
+	}
+}
+
+// RestoreDrillRequest is the request body for recording a restore drill
+type RestoreDrillRequest struct {
+	Environment     string                     `json:"environment" binding:"required"`
+	Outcome         models.RestoreDrillOutcome `json:"outcome" binding:"required"`
+	DurationSeconds int                        `json:"duration_seconds"`
+	Notes           string                     `json:"notes"`
+	PerformedAt     *time.Time                 `json:"performed_at"`
+}
+
+// ListBackups lists recent backups with their checksums
+func (h *BackupHandler) ListBackups(c *gin.Context) {
+	backups, err := h.backupRepo.FindRecent(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load backups")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Backups retrieved successfully", backups)
+}
+
+// TriggerBackup starts a new logical backup
+func (h *BackupHandler) TriggerBackup(c *gin.Context) {
+	triggeredBy, _ := currentUserID(c)
+	backup, err := h.backupService.Trigger(triggeredBy)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start backup: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "backup.trigger", "backup", backup.ID, nil))
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Backup started", backup)
+}
+
+// RecordRestoreDrill records the result of restoring a backup
+func (h *BackupHandler) RecordRestoreDrill(c *gin.Context) {
+	backupID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req RestoreDrillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if req.Outcome != models.RestoreDrillPassed && req.Outcome != models.RestoreDrillFailed {
+		utils.BadRequestResponse(c, "Outcome must be passed or failed")
+		return
+	}
+
+	backup, err := h.backupRepo.FindByID(backupID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load backup")
+		return
+	}
+	if backup == nil {
+		utils.NotFoundResponse(c, "Backup not found")
+		return
+	}
+	if backup.Status != models.BackupCompleted {
+		utils.BadRequestResponse(c, "Only completed backups can be restored")
+		return
+	}
+
+	performedBy, _ := currentUserID(c)
+	drill := &models.RestoreDrill{
+		BackupID:        backup.ID,
+		Environment:     req.Environment,
+		Outcome:         req.Outcome,
+		DurationSeconds: req.DurationSeconds,
+		Notes:           req.Notes,
+		PerformedBy:     performedBy,
+		PerformedAt:     time.Now(),
+	}
+	if req.PerformedAt != nil {
+		drill.PerformedAt = *req.PerformedAt
+	}
+	if err := h.backupRepo.CreateRestoreDrill(drill); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to record restore drill")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "backup.restore_drill", "backup", backup.ID, map[string]interface{}{
+		"environment": drill.Environment,
+		"outcome":     drill.Outcome,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Restore drill recorded", drill)
+}
+
+// ListRestoreDrills lists recent restore drills
+func (h *BackupHandler) ListRestoreDrills(c *gin.Context) {
+	drills, err := h.backupRepo.FindRestoreDrills(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load restore drills")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Restore drills retrieved successfully", drills)
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler manages holidays and exam weeks and the expected meetings of schedules
+type CalendarHandler struct {
+	calendarRepo    repository.CalendarRepository
+	scheduleRepo    repository.ScheduleRepository
+	sessionCalendar *services.SessionCalendar
+	auditService    *services.AuditService
+}
+
+// NewCalendarHandler creates a new instance of CalendarHandler
+func NewCalendarHandler(calendarRepo repository.CalendarRepository, scheduleRepo repository.ScheduleRepository, sessionCalendar *services.SessionCalendar, auditService *services.AuditService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarRepo:    calendarRepo,
+		scheduleRepo:    scheduleRepo,
+		sessionCalendar: sessionCalendar,
+		auditService:    auditService,
+	}
+}
+
+// parseDateRange parses the from and to query parameters (YYYY-MM-DD)
+func parseDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation("2006-01-02", c.Query("from"), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("from must use the YYYY-MM-DD format")
+	}
+	to, err := time.ParseInLocation("2006-01-02", c.Query("to"), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("to must use the YYYY-MM-DD format")
+	}
+	return from, to, nil
+}
+
+// ListEvents lists the calendar events between from and to
+func (h *CalendarHandler) ListEvents(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	calendarEvents, err := h.calendarRepo.FindBetween(from, to)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch calendar events: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Calendar events retrieved successfully", calendarEvents)
+}
+
+// CreateEvent adds a holiday or exam week
+func (h *CalendarHandler) CreateEvent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		Kind      models.CalendarEventKind `json:"kind" binding:"required"`
+		Name      string                   `json:"name" binding:"required,max=150"`
+		StartDate string                   `json:"start_date" binding:"required"`
+		EndDate   string                   `json:"end_date"` // Defaults to start_date
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if !req.Kind.IsValid() {
+		utils.BadRequestResponse(c, "kind must be holiday or exam_week")
+		return
+	}
+	if req.EndDate == "" {
+		req.EndDate = req.StartDate
+	}
+	start, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	if err != nil {
+		utils.BadRequestResponse(c, "start_date must use the YYYY-MM-DD format")
+		return
+	}
+	end, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+	if err != nil {
+		utils.BadRequestResponse(c, "end_date must use the YYYY-MM-DD format")
+		return
+	}
+	if end.Before(start) {
+		utils.BadRequestResponse(c, "end_date must not be before start_date")
+		return
+	}
+
+	event := &models.CalendarEvent{
+		Kind:      req.Kind,
+		Name:      req.Name,
+		StartDate: start,
+		EndDate:   end,
+		CreatedBy: userID,
+	}
+	if err := h.calendarRepo.Create(event); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create calendar event: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "calendar.create", "calendar_event", event.ID, map[string]interface{}{
+		"kind":       event.Kind,
+		"start_date": req.StartDate,
+		"end_date":   req.EndDate,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Calendar event created successfully", event)
+}
+
+// DeleteEvent removes a holiday or exam week
+func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	event, err := h.calendarRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch calendar event: "+err.Error())
+		return
+	}
+	if event == nil {
+		utils.NotFoundResponse(c, "Calendar event not found")
+		return
+	}
+
+	if err := h.calendarRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete calendar event: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "calendar.delete", "calendar_event", id, map[string]interface{}{
+		"kind": event.Kind,
+		"name": event.Name,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Calendar event deleted successfully", nil)
+}
+
+// GetExpectedSessions returns the dates between from and to on which a schedule meets
+func (h *CalendarHandler) GetExpectedSessions(c *gin.Context) {
+	h.expectedSessions(c, 0)
+}
+
+// GetMyExpectedSessions returns the expected meetings of one of the current lecturer's schedules
+func (h *CalendarHandler) GetMyExpectedSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	h.expectedSessions(c, userID)
+}
+
+// expectedSessions writes the expected meetings of the schedule in the id parameter. A
+// non-zero lecturerUserID restricts it to that lecturer's schedules.
+func (h *CalendarHandler) expectedSessions(c *gin.Context, lecturerUserID uint) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedule: "+err.Error())
+		return
+	}
+	if schedule == nil || (lecturerUserID != 0 && schedule.LecturerUserID != lecturerUserID) {
+		utils.NotFoundResponse(c, "Schedule not found")
+		return
+	}
+
+	sessions, err := h.sessionCalendar.ExpectedSessions(*schedule, from, to)
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to calculate expected sessions: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Expected sessions calculated successfully", sessions)
+}
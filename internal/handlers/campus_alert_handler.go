@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// campusAlertHistory is how many past alerts of the campus API are listed
+const campusAlertHistory = 50
+
+// CampusAlertHandler shows admins the health of the campus API and the alerts sent to campus IT
+type CampusAlertHandler struct {
+	alertRepo repository.IntegrationAlertRepository
+	monitor   *services.CampusHealthMonitor
+}
+
+// NewCampusAlertHandler creates a new CampusAlertHandler
+func NewCampusAlertHandler(alertRepo repository.IntegrationAlertRepository, monitor *services.CampusHealthMonitor) *CampusAlertHandler {
+	return &CampusAlertHandler{
+		alertRepo: alertRepo,
+		monitor:   monitor,
+	}
+}
+
+// GetCampusAlerts returns the current health of the campus API on this instance and its recent alerts
+func (h *CampusAlertHandler) GetCampusAlerts(c *gin.Context) {
+	alerts, err := h.alertRepo.FindRecent(models.CampusIntegration, campusAlertHistory)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch campus API alerts: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Campus API alerts retrieved successfully", gin.H{
+		"health": h.monitor.Health(),
+		"alerts": alerts,
+	})
+}
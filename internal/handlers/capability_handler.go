@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/features"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CapabilityHandler tells clients which attendance modes and features they can use
+type CapabilityHandler struct {
+	prodiResolver *services.ProdiResolver
+	features      *features.Flags
+	appVersion    utils.AppVersionPolicy
+}
+
+// NewCapabilityHandler creates a new CapabilityHandler
+func NewCapabilityHandler(prodiResolver *services.ProdiResolver, flags *features.Flags, appVersion utils.AppVersionPolicy) *CapabilityHandler {
+	return &CapabilityHandler{
+		prodiResolver: prodiResolver,
+		features:      flags,
+		appVersion:    appVersion,
+	}
+}
+
+// featureCaller describes the current user for feature flag evaluation
+func featureCaller(c *gin.Context, prodiResolver *services.ProdiResolver) (features.Caller, bool) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		return features.Caller{}, false
+	}
+
+	caller := features.Caller{
+		Role:  principal.ActiveRole,
+		Prodi: prodiResolver.Resolve(principal),
+	}
+	if caller.Role == "" {
+		caller.Role = string(principal.UserType)
+	}
+	return caller, true
+}
+
+// GetCapabilities returns the attendance modes and features enabled for the caller
+func (h *CapabilityHandler) GetCapabilities(c *gin.Context) {
+	caller, ok := featureCaller(c, h.prodiResolver)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	enabled := make(map[features.Feature]bool)
+	for _, feature := range features.All() {
+		enabled[feature] = h.features.EnabledFor(feature, caller)
+	}
+
+	modes := []models.CheckInMethod{models.CheckInManual}
+	if enabled[features.QRCheckIn] {
+		modes = append(modes, models.CheckInQR)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Capabilities retrieved successfully", gin.H{
+		"role":             caller.Role,
+		"attendance_modes": modes,
+		"features":         enabled,
+		"app_version":      h.appVersion,
+	})
+}
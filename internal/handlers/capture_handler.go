@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/capture"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCaptureDuration bounds how long a capture rule may run
+const maxCaptureDuration = 24 * time.Hour
+
+// CaptureHandler lets admins opt users or routes into request/response capturing
+type CaptureHandler struct {
+	store        *capture.Store
+	auditService *services.AuditService
+}
+
+// NewCaptureHandler creates a new CaptureHandler
+func NewCaptureHandler(store *capture.Store, auditService *services.AuditService) *CaptureHandler {
+	return &CaptureHandler{
+		store:        store,
+		auditService: auditService,
+	}
+}
+
+// CreateCaptureRuleRequest is the request body for starting a capture
+type CreateCaptureRuleRequest struct {
+	UserID          uint    `json:"user_id"`
+	PathPrefix      string  `json:"path_prefix"`
+	SampleRate      float64 `json:"sample_rate"`
+	DurationMinutes int     `json:"duration_minutes"`
+}
+
+// GetCaptureRules lists the active capture rules
+func (h *CaptureHandler) GetCaptureRules(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Capture rules retrieved successfully", h.store.Rules())
+}
+
+// CreateCaptureRule starts capturing requests of a user and/or route
+func (h *CaptureHandler) CreateCaptureRule(c *gin.Context) {
+	var req CreateCaptureRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if req.UserID == 0 && req.PathPrefix == "" {
+		utils.BadRequestResponse(c, "Either user_id or path_prefix is required")
+		return
+	}
+	if req.SampleRate == 0 {
+		req.SampleRate = 1
+	}
+	if req.SampleRate < 0 || req.SampleRate > 1 {
+		utils.BadRequestResponse(c, "sample_rate must be between 0 and 1")
+		return
+	}
+	if req.DurationMinutes <= 0 {
+		req.DurationMinutes = 30
+	}
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	if duration > maxCaptureDuration {
+		utils.BadRequestResponse(c, "duration_minutes must not exceed 1440")
+		return
+	}
+
+	createdBy, _ := currentUserID(c)
+	rule := h.store.AddRule(capture.Rule{
+		UserID:     req.UserID,
+		PathPrefix: req.PathPrefix,
+		SampleRate: req.SampleRate,
+		ExpiresAt:  time.Now().Add(duration),
+		CreatedBy:  createdBy,
+	})
+
+	h.auditService.Record(newAuditEntry(c, "capture.start", "capture_rule", rule.ID, map[string]interface{}{
+		"user_id":     rule.UserID,
+		"path_prefix": rule.PathPrefix,
+		"sample_rate": rule.SampleRate,
+		"expires_at":  rule.ExpiresAt,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Capture rule created successfully", rule)
+}
+
+// DeleteCaptureRule stops a capture rule
+func (h *CaptureHandler) DeleteCaptureRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(c, "invalid id")
+		return
+	}
+
+	if !h.store.RemoveRule(id) {
+		utils.NotFoundResponse(c, "Capture rule not found")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "capture.stop", "capture_rule", id, nil))
+
+	utils.SuccessResponse(c, http.StatusOK, "Capture rule deleted successfully", nil)
+}
+
+// GetCaptures lists the retained captures, optionally filtered by user_id
+func (h *CaptureHandler) GetCaptures(c *gin.Context) {
+	var userID uint
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "invalid user_id")
+			return
+		}
+		userID = uint(id)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Captures retrieved successfully", h.store.Recent(userID))
+}
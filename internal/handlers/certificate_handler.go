@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/pdf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CertificateHandler issues participation certificates for guest events and verifies them
+type CertificateHandler struct {
+	certificateService *services.CertificateService
+	certificateRepo    repository.CertificateRepository
+	guestEventRepo     repository.GuestEventRepository
+}
+
+// NewCertificateHandler creates a new instance of CertificateHandler
+func NewCertificateHandler(certificateService *services.CertificateService, certificateRepo repository.CertificateRepository, guestEventRepo repository.GuestEventRepository) *CertificateHandler {
+	return &CertificateHandler{
+		certificateService: certificateService,
+		certificateRepo:    certificateRepo,
+		guestEventRepo:     guestEventRepo,
+	}
+}
+
+// CertificateTemplateRequest is the request body for setting the certificate template of an event
+type CertificateTemplateRequest struct {
+	Title       string `json:"title" binding:"required,max=100"`
+	Body        string `json:"body" binding:"required"`
+	SignerName  string `json:"signer_name" binding:"max=150"`
+	SignerTitle string `json:"signer_title" binding:"max=150"`
+}
+
+// GetTemplate returns the certificate template of an event, or the default template
+func (h *CertificateHandler) GetTemplate(c *gin.Context) {
+	event := loadOwnGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+
+	template, err := h.certificateRepo.FindTemplate(event.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch certificate template: "+err.Error())
+		return
+	}
+	if template == nil {
+		defaultTemplate := services.DefaultCertificateTemplate
+		defaultTemplate.EventID = event.ID
+		template = &defaultTemplate
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Certificate template retrieved successfully", template)
+}
+
+// SaveTemplate sets the certificate template of an event. Title and body may use the
+// placeholders {name}, {event}, {date} and {location}.
+func (h *CertificateHandler) SaveTemplate(c *gin.Context) {
+	event := loadOwnGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+
+	var req CertificateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	template := &models.CertificateTemplate{
+		EventID:     event.ID,
+		Title:       req.Title,
+		Body:        req.Body,
+		SignerName:  req.SignerName,
+		SignerTitle: req.SignerTitle,
+	}
+	if err := h.certificateRepo.SaveTemplate(template); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save certificate template: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Certificate template saved successfully", template)
+}
+
+// IssueCertificates issues certificates to the guests of an event who checked in and do not
+// have one yet, and returns every certificate of the event
+func (h *CertificateHandler) IssueCertificates(c *gin.Context) {
+	event := loadOwnGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+
+	registrations, err := h.guestEventRepo.FindRegistrations(event.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendees: "+err.Error())
+		return
+	}
+
+	certificates, err := h.certificateService.Issue(event, registrations)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to issue certificates: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Certificates issued successfully", certificates)
+}
+
+// GetCertificates returns the certificates issued for an event
+func (h *CertificateHandler) GetCertificates(c *gin.Context) {
+	event := loadOwnGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+
+	certificates, err := h.certificateRepo.FindByEvent(event.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch certificates: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Certificates retrieved successfully", certificates)
+}
+
+// DownloadCertificate returns a certificate of an event as PDF for its organizer
+func (h *CertificateHandler) DownloadCertificate(c *gin.Context) {
+	event := loadOwnGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+	certificate := h.eventCertificate(c, event)
+	if certificate == nil {
+		return
+	}
+
+	h.writePDF(c, certificate, event)
+}
+
+// RevokeCertificate revokes a certificate issued by mistake; it no longer verifies as valid
+func (h *CertificateHandler) RevokeCertificate(c *gin.Context) {
+	event := loadOwnGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+	certificate := h.eventCertificate(c, event)
+	if certificate == nil {
+		return
+	}
+	if certificate.RevokedAt != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "Certificate has already been revoked", nil)
+		return
+	}
+
+	if err := h.certificateRepo.Revoke(certificate); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to revoke certificate: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Certificate revoked successfully", certificate)
+}
+
+// DownloadMyCertificate returns a guest's own certificate as PDF. Guests have no account, so
+// they prove who they are with the registration code from their QR.
+func (h *CertificateHandler) DownloadMyCertificate(c *gin.Context) {
+	event := loadGuestEvent(c, h.guestEventRepo)
+	if event == nil {
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		utils.BadRequestResponse(c, "code is required")
+		return
+	}
+	registration, err := h.guestEventRepo.FindRegistrationByCode(event.ID, code)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch registration: "+err.Error())
+		return
+	}
+	if registration == nil {
+		utils.NotFoundResponse(c, "Registration not found for this event")
+		return
+	}
+
+	certificate, err := h.certificateRepo.FindByRegistration(registration.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch certificate: "+err.Error())
+		return
+	}
+	if certificate == nil || certificate.RevokedAt != nil {
+		utils.NotFoundResponse(c, "No certificate has been issued for this registration")
+		return
+	}
+
+	h.writePDF(c, certificate, event)
+}
+
+// VerifyCertificate confirms whether a certificate code is authentic. It is public and only
+// returns what is printed on the certificate.
+func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
+	verification, err := h.certificateService.Verify(c.Param("code"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to verify certificate: "+err.Error())
+		return
+	}
+	if verification == nil {
+		utils.NotFoundResponse(c, "Certificate not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Certificate verified", verification)
+}
+
+// eventCertificate loads the certificate named by the :certificateId route parameter and
+// checks it belongs to event. It writes the error response and returns nil otherwise.
+func (h *CertificateHandler) eventCertificate(c *gin.Context, event *models.GuestEvent) *models.Certificate {
+	certificateID, err := parseIDParam(c, "certificateId")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil
+	}
+
+	certificate, err := h.certificateRepo.FindByID(certificateID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch certificate: "+err.Error())
+		return nil
+	}
+	if certificate == nil || certificate.EventID != event.ID {
+		utils.NotFoundResponse(c, "Certificate not found")
+		return nil
+	}
+	return certificate
+}
+
+// writePDF renders a certificate with its event's template and writes it as the response
+func (h *CertificateHandler) writePDF(c *gin.Context, certificate *models.Certificate, event *models.GuestEvent) {
+	template, err := h.certificateRepo.FindTemplate(event.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch certificate template: "+err.Error())
+		return
+	}
+
+	doc, err := h.certificateService.PDF(certificate, event, template)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to render certificate: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Type", pdf.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"certificate-%s.pdf\"", certificate.Code))
+	c.Status(http.StatusOK)
+	if err := doc.Write(c.Writer); err != nil {
+		utils.LogError("CertificateHandler", "writePDF", err)
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// globalBlackoutPath is the :eventType path segment of the global blackout policy
+const globalBlackoutPath = "global"
+
+// CheckInBlackoutHandler manages the hours in which check-ins are rejected
+type CheckInBlackoutHandler struct {
+	blackoutRepo repository.CheckInBlackoutRepository
+	auditService *services.AuditService
+}
+
+// NewCheckInBlackoutHandler creates a new instance of CheckInBlackoutHandler
+func NewCheckInBlackoutHandler(blackoutRepo repository.CheckInBlackoutRepository, auditService *services.AuditService) *CheckInBlackoutHandler {
+	return &CheckInBlackoutHandler{
+		blackoutRepo: blackoutRepo,
+		auditService: auditService,
+	}
+}
+
+// CheckInBlackoutRequest is the request body for setting the blackout windows of a policy
+type CheckInBlackoutRequest struct {
+	Windows []models.BlackoutWindow `json:"windows"` // Empty on an override to accept check-ins at any time
+}
+
+// parseBlackoutEventType reads the :eventType parameter, "global" for the global policy
+func parseBlackoutEventType(c *gin.Context) (models.CheckInEventType, bool) {
+	value := c.Param("eventType")
+	if value == globalBlackoutPath {
+		return "", true
+	}
+	eventType := models.CheckInEventType(value)
+	if !eventType.IsValid() {
+		utils.BadRequestResponse(c, fmt.Sprintf("Unknown event type: %s", value))
+		return "", false
+	}
+	return eventType, true
+}
+
+// ListBlackouts lists the global blackout policy and the overrides of event types
+func (h *CheckInBlackoutHandler) ListBlackouts(c *gin.Context) {
+	policies, err := h.blackoutRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch check-in blackout policies: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Check-in blackout policies retrieved successfully", policies)
+}
+
+// SaveBlackout sets the blackout windows of the global policy or of an event type's override
+func (h *CheckInBlackoutHandler) SaveBlackout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	eventType, ok := parseBlackoutEventType(c)
+	if !ok {
+		return
+	}
+
+	var req CheckInBlackoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	policy := &models.CheckInBlackoutPolicy{
+		EventType: eventType,
+		Windows:   req.Windows,
+		UpdatedBy: userID,
+	}
+	if policy.Windows == nil {
+		policy.Windows = []models.BlackoutWindow{}
+	}
+	if err := policy.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err := h.blackoutRepo.Save(policy); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save check-in blackout policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "check_in_blackout.save", "check_in_blackout_policy", c.Param("eventType"), map[string]interface{}{
+		"windows": policy.Windows,
+	}))
+	utils.SuccessResponse(c, http.StatusOK, "Check-in blackout policy saved successfully", policy)
+}
+
+// DeleteBlackout removes the global policy, or the override of an event type so the global
+// policy applies to it again
+func (h *CheckInBlackoutHandler) DeleteBlackout(c *gin.Context) {
+	eventType, ok := parseBlackoutEventType(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.blackoutRepo.Delete(eventType)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete check-in blackout policy: "+err.Error())
+		return
+	}
+	if !deleted {
+		utils.NotFoundResponse(c, "Check-in blackout policy not found")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "check_in_blackout.delete", "check_in_blackout_policy", c.Param("eventType"), nil))
+	utils.SuccessResponse(c, http.StatusOK, "Check-in blackout policy deleted successfully", nil)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DelegationHandler lets lecturers delegate their approval duties while out of office
+type DelegationHandler struct {
+	delegationRepo      repository.DelegationRepository
+	lecturerRepo        repository.LecturerRepository
+	auditService        *services.AuditService
+	notificationService *services.NotificationService
+}
+
+// NewDelegationHandler creates a new instance of DelegationHandler
+func NewDelegationHandler(delegationRepo repository.DelegationRepository, lecturerRepo repository.LecturerRepository, auditService *services.AuditService, notificationService *services.NotificationService) *DelegationHandler {
+	return &DelegationHandler{
+		delegationRepo:      delegationRepo,
+		lecturerRepo:        lecturerRepo,
+		auditService:        auditService,
+		notificationService: notificationService,
+	}
+}
+
+// GetMyDelegations returns the delegations given and received by the current lecturer
+func (h *DelegationHandler) GetMyDelegations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	given, err := h.delegationRepo.FindByDelegator(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch delegations: "+err.Error())
+		return
+	}
+	received, err := h.delegationRepo.FindByDelegate(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch delegations: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delegations retrieved successfully", gin.H{
+		"given":    given,
+		"received": received,
+	})
+}
+
+// CreateDelegation delegates the current lecturer's approvals to another lecturer for a date range
+func (h *DelegationHandler) CreateDelegation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		DelegateUserID uint                 `json:"delegate_user_id" binding:"required"`
+		Scope          models.ApprovalScope `json:"scope"`
+		StartDate      string               `json:"start_date" binding:"required"`
+		EndDate        string               `json:"end_date" binding:"required"`
+		Reason         string               `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Delegate, start date and end date are required")
+		return
+	}
+
+	if req.Scope == "" {
+		req.Scope = models.AllApprovals
+	}
+	if !req.Scope.IsValid() {
+		utils.BadRequestResponse(c, "scope must be one of all, supervision, leave or dispute")
+		return
+	}
+	if req.DelegateUserID == userID {
+		utils.BadRequestResponse(c, "You cannot delegate approvals to yourself")
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		utils.BadRequestResponse(c, "start_date must use the YYYY-MM-DD format")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		utils.BadRequestResponse(c, "end_date must use the YYYY-MM-DD format")
+		return
+	}
+	if endDate.Before(startDate) {
+		utils.BadRequestResponse(c, "end_date must not be before start_date")
+		return
+	}
+	if endDate.Format("2006-01-02") < time.Now().Format("2006-01-02") {
+		utils.BadRequestResponse(c, "end_date must not be in the past")
+		return
+	}
+
+	delegate, err := h.lecturerRepo.FindByUserID(req.DelegateUserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch delegate: "+err.Error())
+		return
+	}
+	if delegate == nil {
+		utils.BadRequestResponse(c, "Delegate must be a lecturer with a synced profile")
+		return
+	}
+
+	delegation := &models.ApprovalDelegation{
+		DelegatorUserID: userID,
+		DelegateUserID:  req.DelegateUserID,
+		Scope:           req.Scope,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		Reason:          req.Reason,
+	}
+
+	if err := h.delegationRepo.Create(delegation); err != nil {
+		if errors.Is(err, repository.ErrDelegationOverlap) {
+			utils.ErrorResponse(c, http.StatusConflict, "You already delegated these approvals for an overlapping period", nil)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to save delegation: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "delegation.create", "approval_delegation", delegation.ID, map[string]interface{}{
+		"delegate_user_id": delegation.DelegateUserID,
+		"scope":            delegation.Scope,
+		"start_date":       req.StartDate,
+		"end_date":         req.EndDate,
+	}))
+	h.notificationService.Notify(delegation.DelegateUserID, "delegation.received",
+		"Delegasi persetujuan",
+		fmt.Sprintf("Anda menerima delegasi persetujuan (%s) dari %s sampai %s", delegation.Scope, req.StartDate, req.EndDate))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Delegation created successfully", delegation)
+}
+
+// RevokeDelegation ends one of the current lecturer's delegations early
+func (h *DelegationHandler) RevokeDelegation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	delegation, err := h.delegationRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch delegation: "+err.Error())
+		return
+	}
+	if delegation == nil || delegation.DelegatorUserID != userID {
+		utils.NotFoundResponse(c, "Delegation not found")
+		return
+	}
+	if delegation.RevokedAt != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "Delegation has already been revoked", nil)
+		return
+	}
+
+	if err := h.delegationRepo.Revoke(delegation); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to revoke delegation: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "delegation.revoke", "approval_delegation", delegation.ID, nil))
+
+	utils.SuccessResponse(c, http.StatusOK, "Delegation revoked successfully", delegation)
+}
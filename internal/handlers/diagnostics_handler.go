@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiagnosticsHandler exposes runtime diagnostics to admins
+type DiagnosticsHandler struct{}
+
+// NewDiagnosticsHandler creates a new DiagnosticsHandler
+func NewDiagnosticsHandler() *DiagnosticsHandler {
+	return &DiagnosticsHandler{}
+}
+
+// GetSlowQueries lists recent slow queries with redacted parameters and their query plans
+func (h *DiagnosticsHandler) GetSlowQueries(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Slow queries retrieved successfully", database.SlowQueries.Recent())
+}
+
+// GetCacheStats lists the hits, misses and invalidations of every cache since startup
+func (h *DiagnosticsHandler) GetCacheStats(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Cache statistics retrieved successfully", cache.Stats())
+}
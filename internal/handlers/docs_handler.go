@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsHandler serves the machine-readable API contract for the frontend and mobile clients
+type DocsHandler struct{}
+
+// NewDocsHandler creates a new DocsHandler
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// GetOpenAPI returns the OpenAPI 3 document of the API
+func (h *DocsHandler) GetOpenAPI(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, docs.ContentType, docs.OpenAPI)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailBrandingHandler lets admins brand outgoing emails per faculty
+type EmailBrandingHandler struct {
+	brandingRepo repository.EmailBrandingRepository
+	emailService *services.EmailService
+	auditService *services.AuditService
+}
+
+// NewEmailBrandingHandler creates a new instance of EmailBrandingHandler
+func NewEmailBrandingHandler(brandingRepo repository.EmailBrandingRepository, emailService *services.EmailService, auditService *services.AuditService) *EmailBrandingHandler {
+	return &EmailBrandingHandler{
+		brandingRepo: brandingRepo,
+		emailService: emailService,
+		auditService: auditService,
+	}
+}
+
+// EmailBrandingRequest is the request body for setting the email branding of a faculty
+type EmailBrandingRequest struct {
+	Faculty        string `json:"faculty" binding:"max=150"` // Empty for every faculty without its own branding
+	LogoURL        string `json:"logo_url" binding:"max=500"`
+	AccentColor    string `json:"accent_color"`
+	FooterText     string `json:"footer_text" binding:"max=2000"`
+	SupportContact string `json:"support_contact" binding:"max=200"`
+}
+
+// ListBrandings returns the configured email brandings and the defaults used without one
+func (h *EmailBrandingHandler) ListBrandings(c *gin.Context) {
+	brandings, err := h.brandingRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch email brandings: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email brandings retrieved successfully", gin.H{
+		"brandings": brandings,
+		"defaults":  models.DefaultEmailBranding,
+	})
+}
+
+// SaveBranding creates or replaces the email branding of a faculty
+func (h *EmailBrandingHandler) SaveBranding(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req EmailBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	branding := &models.EmailBranding{
+		Faculty:        strings.TrimSpace(req.Faculty),
+		LogoURL:        strings.TrimSpace(req.LogoURL),
+		AccentColor:    strings.TrimSpace(req.AccentColor),
+		FooterText:     strings.TrimSpace(req.FooterText),
+		SupportContact: strings.TrimSpace(req.SupportContact),
+		UpdatedBy:      adminID,
+	}
+	if branding.LogoURL != "" {
+		parsed, err := url.Parse(branding.LogoURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			utils.BadRequestResponse(c, "logo_url must be an https link")
+			return
+		}
+	}
+	if branding.AccentColor != "" && !models.IsValidAccentColor(branding.AccentColor) {
+		utils.BadRequestResponse(c, "accent_color must be a hex color such as #1a4d8f")
+		return
+	}
+
+	if err := h.brandingRepo.Save(branding); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save email branding: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "email_branding.save", "email_branding", branding.ID, map[string]interface{}{
+		"faculty":         branding.Faculty,
+		"logo_url":        branding.LogoURL,
+		"accent_color":    branding.AccentColor,
+		"support_contact": branding.SupportContact,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Email branding saved successfully", branding)
+}
+
+// DeleteBranding removes the email branding of a faculty so it falls back to the general one
+func (h *EmailBrandingHandler) DeleteBranding(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	branding, err := h.brandingRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch email branding: "+err.Error())
+		return
+	}
+	if branding == nil {
+		utils.NotFoundResponse(c, "Email branding not found")
+		return
+	}
+
+	if err := h.brandingRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete email branding: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "email_branding.delete", "email_branding", branding.ID, map[string]interface{}{
+		"faculty": branding.Faculty,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Email branding deleted successfully", nil)
+}
+
+// PreviewBranding renders a sample email with the branding a faculty's emails get
+func (h *EmailBrandingHandler) PreviewBranding(c *gin.Context) {
+	body, err := h.emailService.Render("internship_confirmation", services.EmailData{
+		Subject:       "Konfirmasi Kehadiran Kerja Praktek",
+		RecipientName: "Mentor",
+		Faculty:       c.Query("faculty"),
+		Data: map[string]interface{}{
+			"nim":          "11S20001",
+			"student_name": "Mahasiswa Contoh",
+			"date":         time.Now().Format("2006-01-02"),
+			"notes":        "Contoh catatan kegiatan",
+			"confirm_url":  "#",
+			"expires_at":   time.Now().Add(72 * time.Hour).Format("2006-01-02 15:04"),
+		},
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to render email preview: "+err.Error())
+		return
+	}
+
+	// The preview only shows sample data, so it needs no pseudonymizing
+	pseudonym.FromContext(c)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(body))
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailChangeHandler lets users with a local account change their email with a confirmation
+// from both the current and the new address
+type EmailChangeHandler struct {
+	emailChanges *services.EmailChangeService
+	userRepo     *repository.UserRepository
+	auditService *services.AuditService
+}
+
+// NewEmailChangeHandler creates a new instance of EmailChangeHandler
+func NewEmailChangeHandler(emailChanges *services.EmailChangeService, userRepo *repository.UserRepository, auditService *services.AuditService) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		emailChanges: emailChanges,
+		userRepo:     userRepo,
+		auditService: auditService,
+	}
+}
+
+// EmailChangeRequest is the request body for changing the current user's email
+type EmailChangeRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required"` // Current password, re-entered
+}
+
+// RequestChange starts changing the current user's email and emails both addresses a
+// confirmation link
+func (h *EmailChangeHandler) RequestChange(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	if principal.CampusAuthenticated {
+		utils.ForbiddenResponse(c, "The email of a campus account is managed by the campus")
+		return
+	}
+
+	var req EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(principal.UserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch user: "+err.Error())
+		return
+	}
+	if !user.ComparePassword(req.Password) {
+		utils.ForbiddenResponse(c, "Password is incorrect")
+		return
+	}
+
+	change, err := h.emailChanges.Request(c.Request.Context(), user, req.NewEmail)
+	switch {
+	case errors.Is(err, services.ErrEmailUnchanged):
+		utils.BadRequestResponse(c, "New email is the same as the current email")
+		return
+	case errors.Is(err, services.ErrEmailTaken):
+		utils.ErrorResponse(c, http.StatusConflict, "Email is already used by another account", nil)
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to request email change: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "user.email_change_requested", "user", user.ID, map[string]interface{}{
+		"new_email": change.NewEmail,
+	}))
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Confirmation links have been sent to both email addresses", change)
+}
+
+// GetPendingChange returns the current user's pending email change, if any
+func (h *EmailChangeHandler) GetPendingChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	change, err := h.emailChanges.Pending(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch email change: "+err.Error())
+		return
+	}
+	if change == nil {
+		utils.NotFoundResponse(c, "No email change is pending")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email change retrieved successfully", change)
+}
+
+// CancelChange cancels the current user's pending email change
+func (h *EmailChangeHandler) CancelChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	cancelled, err := h.emailChanges.Cancel(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to cancel email change: "+err.Error())
+		return
+	}
+	if !cancelled {
+		utils.NotFoundResponse(c, "No email change is pending")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "user.email_change_cancelled", "user", userID, nil))
+
+	utils.SuccessResponse(c, http.StatusOK, "Email change cancelled", nil)
+}
+
+// ConfirmChange is opened from the link emailed to the current or the new address
+func (h *EmailChangeHandler) ConfirmChange(c *gin.Context) {
+	change, err := h.emailChanges.Confirm(c.Param("token"))
+	if errors.Is(err, services.ErrEmailChangeLinkInvalid) {
+		utils.NotFoundResponse(c, "Confirmation link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to confirm email change: "+err.Error())
+		return
+	}
+
+	message := "Email change confirmed, waiting for the other address to confirm"
+	if change != nil && change.EffectiveAt != nil {
+		message = "Email change confirmed, it takes effect after the cooling-off period"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, change)
+}
+
+// CancelByLink is opened from the cancel link emailed to the current address, for changes the
+// owner of the account did not request
+func (h *EmailChangeHandler) CancelByLink(c *gin.Context) {
+	token, err := h.emailChanges.CancelByLink(c.Param("token"))
+	if errors.Is(err, services.ErrEmailChangeLinkInvalid) {
+		utils.NotFoundResponse(c, "Cancel link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to cancel email change: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(services.AuditEntry{
+		ActorUserID: token.UserID,
+		Action:      "user.email_change_cancelled",
+		EntityType:  "user",
+		EntityID:    token.UserID,
+		Details:     map[string]interface{}{"via": "link", "new_email": token.PendingEmail},
+		IPAddress:   c.ClientIP(),
+	})
+
+	utils.SuccessResponse(c, http.StatusOK, "Email change cancelled", nil)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailQueueHandler lets admins inspect and retry emails the queue failed to send
+type EmailQueueHandler struct {
+	emailQueue   *services.EmailQueue
+	auditService *services.AuditService
+}
+
+// NewEmailQueueHandler creates a new EmailQueueHandler
+func NewEmailQueueHandler(emailQueue *services.EmailQueue, auditService *services.AuditService) *EmailQueueHandler {
+	return &EmailQueueHandler{
+		emailQueue:   emailQueue,
+		auditService: auditService,
+	}
+}
+
+// ListFailed lists the most recent emails whose every delivery attempt failed
+func (h *EmailQueueHandler) ListFailed(c *gin.Context) {
+	emails, err := h.emailQueue.Failed(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load failed emails: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Failed emails retrieved successfully", emails)
+}
+
+// RetryFailed requeues a failed email with a fresh set of delivery attempts
+func (h *EmailQueueHandler) RetryFailed(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	requeued, err := h.emailQueue.Retry(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to requeue email: "+err.Error())
+		return
+	}
+	if !requeued {
+		utils.NotFoundResponse(c, "Failed email not found")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "email.retry", "queued_email", id, nil))
+
+	utils.SuccessResponse(c, http.StatusOK, "Email requeued successfully", nil)
+}
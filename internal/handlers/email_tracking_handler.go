@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trackingPixel is a transparent 1x1 GIF
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// EmailTrackingHandler records opens and clicks of tracked emails, lets users opt out and
+// reports aggregate engagement to admins
+type EmailTrackingHandler struct {
+	tracker      *services.EmailTracker
+	trackingRepo repository.EmailTrackingRepository
+}
+
+// NewEmailTrackingHandler creates a new instance of EmailTrackingHandler
+func NewEmailTrackingHandler(tracker *services.EmailTracker, trackingRepo repository.EmailTrackingRepository) *EmailTrackingHandler {
+	return &EmailTrackingHandler{
+		tracker:      tracker,
+		trackingRepo: trackingRepo,
+	}
+}
+
+// EmailTrackingPreferenceRequest is the request body for opting in or out of email tracking
+type EmailTrackingPreferenceRequest struct {
+	OptOut *bool `json:"opt_out" binding:"required"`
+}
+
+// TrackOpen records that a tracked email was opened and returns an invisible image
+func (h *EmailTrackingHandler) TrackOpen(c *gin.Context) {
+	if err := h.tracker.Opened(c.Param("token")); err != nil {
+		utils.LogWarning("EmailTrackingHandler", "TrackOpen", "Failed to record email open: "+err.Error())
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/gif", trackingPixel)
+}
+
+// TrackClick records that a link in a tracked email was clicked and redirects to the link.
+// Only links signed by this API are followed.
+func (h *EmailTrackingHandler) TrackClick(c *gin.Context) {
+	token := c.Param("token")
+	target := c.Query("u")
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || !h.tracker.VerifyLink(token, target, c.Query("s")) {
+		utils.BadRequestResponse(c, "Invalid link")
+		return
+	}
+
+	if err := h.tracker.Clicked(token); err != nil {
+		utils.LogWarning("EmailTrackingHandler", "TrackClick", "Failed to record email click: "+err.Error())
+	}
+
+	c.Redirect(http.StatusFound, target)
+}
+
+// GetMyPreference returns whether the current user opted out of email tracking
+func (h *EmailTrackingHandler) GetMyPreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	optedOut, err := h.trackingRepo.IsOptedOut(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch email tracking preference: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email tracking preference retrieved successfully", gin.H{
+		"opt_out": optedOut,
+	})
+}
+
+// SetMyPreference opts the current user in or out of email tracking
+func (h *EmailTrackingHandler) SetMyPreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req EmailTrackingPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "opt_out is required")
+		return
+	}
+
+	if err := h.trackingRepo.SetOptOut(userID, *req.OptOut); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save email tracking preference: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email tracking preference saved successfully", gin.H{
+		"opt_out": *req.OptOut,
+	})
+}
+
+// GetEngagementReport returns how many tracked emails were sent, opened and clicked per
+// template per day between from and to
+func (h *EmailTrackingHandler) GetEngagementReport(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	engagement, err := h.trackingRepo.Engagement(from, to.Add(24*time.Hour))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch email engagement: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email engagement retrieved successfully", engagement)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBulkEnrollment bounds how many NIMs can be enrolled in one request
+const maxBulkEnrollment = 1000
+
+// EnrollmentHandler manages the students enrolled in each course offering
+type EnrollmentHandler struct {
+	enrollmentRepo repository.EnrollmentRepository
+	scheduleRepo   repository.ScheduleRepository
+	mahasiswaRepo  repository.MahasiswaRepository
+	auditService   *services.AuditService
+	campusClient   *utils.CampusClient
+}
+
+// NewEnrollmentHandler creates a new instance of EnrollmentHandler
+func NewEnrollmentHandler(enrollmentRepo repository.EnrollmentRepository, scheduleRepo repository.ScheduleRepository, mahasiswaRepo repository.MahasiswaRepository, auditService *services.AuditService, campusClient *utils.CampusClient) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		enrollmentRepo: enrollmentRepo,
+		scheduleRepo:   scheduleRepo,
+		mahasiswaRepo:  mahasiswaRepo,
+		auditService:   auditService,
+		campusClient:   campusClient,
+	}
+}
+
+// BulkEnrollRequest is the request body for enrolling a list of students into a course offering
+type BulkEnrollRequest struct {
+	CourseCode string   `json:"course_code" binding:"required"`
+	CourseName string   `json:"course_name"`
+	ClassName  string   `json:"class_name"`
+	Semester   string   `json:"semester" binding:"required"`
+	Nims       []string `json:"nims" binding:"required,min=1"`
+}
+
+// BulkEnroll enrolls students by NIM into a course offering, skipping those already enrolled
+func (h *EnrollmentHandler) BulkEnroll(c *gin.Context) {
+	var req BulkEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Course code, semester and at least one NIM are required")
+		return
+	}
+
+	// Trim and de-duplicate the pasted NIM list
+	seen := make(map[string]bool, len(req.Nims))
+	var nims []string
+	for _, nim := range req.Nims {
+		nim = strings.TrimSpace(nim)
+		if nim == "" || seen[nim] {
+			continue
+		}
+		seen[nim] = true
+		nims = append(nims, nim)
+	}
+	if len(nims) == 0 {
+		utils.BadRequestResponse(c, "At least one NIM is required")
+		return
+	}
+	if len(nims) > maxBulkEnrollment {
+		utils.BadRequestResponse(c, "At most 1000 NIMs can be enrolled at once")
+		return
+	}
+
+	offering := models.CourseOffering{
+		CourseCode: req.CourseCode,
+		CourseName: req.CourseName,
+		ClassName:  req.ClassName,
+		Semester:   req.Semester,
+	}
+	enrolledBy, _ := currentUserID(c)
+
+	created, err := h.enrollmentRepo.BulkEnroll(offering, nims, enrolledBy)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to enroll students: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "enrollment.bulk", "course_offering", req.CourseCode, map[string]interface{}{
+		"class_name": req.ClassName,
+		"semester":   req.Semester,
+		"requested":  len(nims),
+		"enrolled":   created,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Students enrolled successfully", gin.H{
+		"offering":         offering,
+		"requested":        len(nims),
+		"enrolled":         created,
+		"already_enrolled": int64(len(nims)) - created,
+	})
+}
+
+// ListEnrollments lists the students enrolled in a course offering
+func (h *EnrollmentHandler) ListEnrollments(c *gin.Context) {
+	offering := models.CourseOffering{
+		CourseCode: c.Query("course_code"),
+		ClassName:  c.Query("class_name"),
+		Semester:   c.Query("semester"),
+	}
+	if offering.CourseCode == "" {
+		utils.BadRequestResponse(c, "course_code is required")
+		return
+	}
+
+	enrollments, err := h.enrollmentRepo.FindByOffering(offering)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch enrollments: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Enrollments retrieved successfully", enrollments)
+}
+
+// DeleteEnrollment removes a student from a course offering
+func (h *EnrollmentHandler) DeleteEnrollment(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	enrollment, err := h.enrollmentRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch enrollment: "+err.Error())
+		return
+	}
+	if enrollment == nil {
+		utils.NotFoundResponse(c, "Enrollment not found")
+		return
+	}
+
+	if err := h.enrollmentRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete enrollment: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "enrollment.delete", "enrollment", id, map[string]interface{}{
+		"nim":         enrollment.Nim,
+		"course_code": enrollment.CourseCode,
+		"semester":    enrollment.Semester,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Enrollment deleted successfully", nil)
+}
+
+// GetMyCourses returns the courses the current student is enrolled in with their schedules
+func (h *EnrollmentHandler) GetMyCourses(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	enrollments, err := h.enrollmentRepo.FindByNim(nim, c.Query("semester"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch courses: "+err.Error())
+		return
+	}
+
+	courses := make([]models.EnrolledCourse, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+			Semester:   enrollment.Semester,
+			CourseCode: enrollment.CourseCode,
+			ClassName:  enrollment.ClassName,
+		})
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+			return
+		}
+		courses = append(courses, models.EnrolledCourse{Enrollment: enrollment, Schedules: schedules})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Courses retrieved successfully", courses)
+}
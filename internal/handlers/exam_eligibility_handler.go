@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/pdf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExamEligibilityHandler tells students whether they may sit their exams and gives admins the
+// eligibility lists for printing exam cards
+type ExamEligibilityHandler struct {
+	policyRepo    repository.ExamEligibilityRepository
+	mahasiswaRepo repository.MahasiswaRepository
+	scheduleRepo  repository.ScheduleRepository
+	eligibility   *services.ExamEligibilityService
+	reportService *services.ReportService
+	auditService  *services.AuditService
+	campusClient  *utils.CampusClient
+}
+
+// NewExamEligibilityHandler creates a new instance of ExamEligibilityHandler
+func NewExamEligibilityHandler(policyRepo repository.ExamEligibilityRepository, mahasiswaRepo repository.MahasiswaRepository, scheduleRepo repository.ScheduleRepository, eligibility *services.ExamEligibilityService, reportService *services.ReportService, auditService *services.AuditService, campusClient *utils.CampusClient) *ExamEligibilityHandler {
+	return &ExamEligibilityHandler{
+		policyRepo:    policyRepo,
+		mahasiswaRepo: mahasiswaRepo,
+		scheduleRepo:  scheduleRepo,
+		eligibility:   eligibility,
+		reportService: reportService,
+		auditService:  auditService,
+		campusClient:  campusClient,
+	}
+}
+
+// ExamEligibilityPolicyRequest is the request body for setting the minimum attendance for exams
+type ExamEligibilityPolicyRequest struct {
+	CourseCode string  `json:"course_code"` // Empty for the default policy
+	MinPercent float64 `json:"min_percent" binding:"required,gt=0,max=100"`
+}
+
+// GetMyEligibility returns whether the current student may sit the exams of each of their
+// courses, optionally for one semester
+func (h *ExamEligibilityHandler) GetMyEligibility(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	courses, err := h.eligibility.ForStudent(nim, c.Query("semester"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check exam eligibility: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exam eligibility retrieved successfully", gin.H{
+		"nim":     nim,
+		"courses": courses,
+	})
+}
+
+// GetCourseEligibility returns the exam eligibility of every student of a course offering,
+// identified by course code and semester and optionally narrowed by class_name. format=pdf
+// gives a printable list for exam cards.
+func (h *ExamEligibilityHandler) GetCourseEligibility(c *gin.Context) {
+	filter := models.AttendanceRecapFilter{
+		CourseCode: c.Param("id"),
+		Semester:   c.Query("semester"),
+		ClassName:  c.Query("class_name"),
+	}
+	if filter.Semester == "" {
+		utils.BadRequestResponse(c, "semester is required")
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "pdf" {
+		utils.BadRequestResponse(c, "Unsupported export format: "+format)
+		return
+	}
+
+	list, err := h.eligibility.ForCourse(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check exam eligibility: "+err.Error())
+		return
+	}
+	if len(list.Students) == 0 {
+		utils.NotFoundResponse(c, "No students found for this course")
+		return
+	}
+
+	if format == "json" {
+		utils.SuccessResponse(c, http.StatusOK, "Exam eligibility retrieved successfully", list)
+		return
+	}
+
+	// Admins who may not see students get the list with their NIMs pseudonymized
+	if pseudonymizer, ok := pseudonym.FromContext(c); ok {
+		for i := range list.Students {
+			list.Students[i].Nim = pseudonymizer.Nim(list.Students[i].Nim)
+		}
+	}
+
+	courseName := ""
+	schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+		Semester:   filter.Semester,
+		CourseCode: filter.CourseCode,
+		ClassName:  filter.ClassName,
+	})
+	if err != nil {
+		utils.LogError("ExamEligibilityHandler", "GetCourseEligibility", err)
+	}
+	if len(schedules) > 0 {
+		courseName = schedules[0].CourseName
+	}
+
+	doc := h.reportService.ExamEligibilityPDF(list, courseName)
+
+	recap := &models.AttendanceRecap{CourseCode: list.CourseCode, Semester: list.Semester, ClassName: list.ClassName}
+	c.Header("Content-Type", pdf.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"exam-eligibility-%s.pdf\"", exportFileName(recap)))
+	c.Status(http.StatusOK)
+	if err := doc.Write(c.Writer); err != nil {
+		utils.LogError("ExamEligibilityHandler", "GetCourseEligibility", err)
+	}
+}
+
+// ListPolicies lists the minimum attendance for exams of every course with its own policy
+func (h *ExamEligibilityHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch exam eligibility policies: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exam eligibility policies retrieved successfully", gin.H{
+		"policies":            policies,
+		"default_min_percent": models.DefaultExamMinPercent,
+	})
+}
+
+// SavePolicy creates or replaces the minimum attendance for exams of a course, or the
+// default for every course without its own when course_code is empty
+func (h *ExamEligibilityHandler) SavePolicy(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req ExamEligibilityPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	policy := &models.ExamEligibilityPolicy{
+		CourseCode: req.CourseCode,
+		MinPercent: req.MinPercent,
+		UpdatedBy:  adminID,
+	}
+	if err := h.policyRepo.Save(policy); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save exam eligibility policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "exam_eligibility_policy.save", "exam_eligibility_policy", policy.ID, map[string]interface{}{
+		"course_code": policy.CourseCode,
+		"min_percent": policy.MinPercent,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Exam eligibility policy saved successfully", policy)
+}
+
+// DeletePolicy removes the minimum attendance for exams of a course; the default applies again
+func (h *ExamEligibilityHandler) DeletePolicy(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	policy, err := h.policyRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch exam eligibility policy: "+err.Error())
+		return
+	}
+	if policy == nil {
+		utils.NotFoundResponse(c, "Exam eligibility policy not found")
+		return
+	}
+
+	if err := h.policyRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete exam eligibility policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "exam_eligibility_policy.delete", "exam_eligibility_policy", policy.ID, map[string]interface{}{
+		"course_code": policy.CourseCode,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Exam eligibility policy deleted successfully", nil)
+}
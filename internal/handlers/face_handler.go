@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FaceHandler lets students register the face embedding used to verify their check-ins
+type FaceHandler struct {
+	faceService   *services.FaceService
+	faceRepo      repository.FaceRepository
+	mahasiswaRepo repository.MahasiswaRepository
+	auditService  *services.AuditService
+	campusClient  *utils.CampusClient
+}
+
+// NewFaceHandler creates a new instance of FaceHandler
+func NewFaceHandler(faceService *services.FaceService, faceRepo repository.FaceRepository, mahasiswaRepo repository.MahasiswaRepository, auditService *services.AuditService, campusClient *utils.CampusClient) *FaceHandler {
+	return &FaceHandler{
+		faceService:   faceService,
+		faceRepo:      faceRepo,
+		mahasiswaRepo: mahasiswaRepo,
+		auditService:  auditService,
+		campusClient:  campusClient,
+	}
+}
+
+// RegisterFace stores the current student's face embedding, replacing any previous one
+func (h *FaceHandler) RegisterFace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		Embedding []float64 `json:"embedding" binding:"required"`
+		Model     string    `json:"model"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Face embedding is required")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	face, err := h.faceService.Register(userID, nim, req.Embedding, req.Model)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidEmbedding) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to register face: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "face.register", "face_data", userID, map[string]interface{}{
+		"nim":       nim,
+		"dimension": face.Dimension,
+		"model":     face.Model,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Face registered successfully", face)
+}
+
+// GetMyFace tells the current student whether a face is registered, without returning the embedding
+func (h *FaceHandler) GetMyFace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	face, err := h.faceRepo.FindByStudent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch face registration: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Face registration retrieved successfully", gin.H{
+		"registered": face != nil,
+		"face":       face,
+	})
+}
+
+// DeleteMyFace permanently removes the current student's face embedding
+func (h *FaceHandler) DeleteMyFace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	if err := h.faceRepo.Delete(userID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete face registration: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "face.delete", "face_data", userID, nil))
+
+	utils.SuccessResponse(c, http.StatusOK, "Face registration deleted successfully", nil)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FactorRolloutHandler manages per course how the face and Wi-Fi check-in factors apply and
+// reports how shadowed factors would have fared
+type FactorRolloutHandler struct {
+	rolloutRepo    repository.FactorRolloutRepository
+	factorRollouts *services.FactorRolloutService
+	auditService   *services.AuditService
+}
+
+// NewFactorRolloutHandler creates a new instance of FactorRolloutHandler
+func NewFactorRolloutHandler(rolloutRepo repository.FactorRolloutRepository, factorRollouts *services.FactorRolloutService, auditService *services.AuditService) *FactorRolloutHandler {
+	return &FactorRolloutHandler{
+		rolloutRepo:    rolloutRepo,
+		factorRollouts: factorRollouts,
+		auditService:   auditService,
+	}
+}
+
+// FactorRolloutRequest is the request body for setting how a factor applies to a course
+type FactorRolloutRequest struct {
+	CourseCode string               `json:"course_code" binding:"required,max=20"`
+	Factor     models.CheckInFactor `json:"factor" binding:"required"`
+	Mode       models.FactorMode    `json:"mode" binding:"required"`
+}
+
+// ListRollouts lists the factor rollouts of all courses
+func (h *FactorRolloutHandler) ListRollouts(c *gin.Context) {
+	rollouts, err := h.rolloutRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch factor rollouts: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Factor rollouts retrieved successfully", rollouts)
+}
+
+// SetRollout turns a factor off, into shadow mode or enforces it for a course
+func (h *FactorRolloutHandler) SetRollout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req FactorRolloutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if !models.IsValidCheckInFactor(req.Factor) {
+		utils.BadRequestResponse(c, "factor must be face or wifi")
+		return
+	}
+	if !models.IsValidFactorMode(req.Mode) {
+		utils.BadRequestResponse(c, "mode must be off, shadow or enforce")
+		return
+	}
+	if req.Factor == models.WifiFactor && req.Mode != models.FactorModeOff && !h.factorRollouts.WifiConfigured() {
+		utils.BadRequestResponse(c, "Campus Wi-Fi networks are not configured, set CAMPUS_WIFI_NETWORKS first")
+		return
+	}
+
+	rollout := &models.FactorRollout{
+		CourseCode: req.CourseCode,
+		Factor:     req.Factor,
+		Mode:       req.Mode,
+		UpdatedBy:  userID,
+	}
+	if err := h.rolloutRepo.Save(rollout); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save factor rollout: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "factor_rollout.set", "factor_rollout", rollout.ID, map[string]interface{}{
+		"course_code": rollout.CourseCode,
+		"factor":      rollout.Factor,
+		"mode":        rollout.Mode,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Factor rollout saved successfully", rollout)
+}
+
+// DeleteRollout removes a factor rollout so the course follows the factor's feature flag again
+func (h *FactorRolloutHandler) DeleteRollout(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	rollout, err := h.rolloutRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch factor rollout: "+err.Error())
+		return
+	}
+	if rollout == nil {
+		utils.NotFoundResponse(c, "Factor rollout not found")
+		return
+	}
+
+	if err := h.rolloutRepo.Delete(rollout.ID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete factor rollout: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "factor_rollout.delete", "factor_rollout", rollout.ID, map[string]interface{}{
+		"course_code": rollout.CourseCode,
+		"factor":      rollout.Factor,
+		"mode":        rollout.Mode,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Factor rollout deleted successfully", nil)
+}
+
+// GetShadowReport compares per course and factor how many check-in attempts between from and
+// to the shadowed factors passed, and how many enforcing them would have turned away
+func (h *FactorRolloutHandler) GetShadowReport(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	reports, err := h.rolloutRepo.ShadowReport(from, to.Add(24*time.Hour), c.Query("course_code"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build shadow factor report: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shadow factor report generated successfully", reports)
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestEventHandler handles guest registration and check-in for public campus events
+type GuestEventHandler struct {
+	guestEventRepo repository.GuestEventRepository
+}
+
+// NewGuestEventHandler creates a new instance of GuestEventHandler
+func NewGuestEventHandler(guestEventRepo repository.GuestEventRepository) *GuestEventHandler {
+	return &GuestEventHandler{
+		guestEventRepo: guestEventRepo,
+	}
+}
+
+// findEvent loads an event by the :id route parameter.
+// It writes the error response and returns nil when the event cannot be loaded.
+func (h *GuestEventHandler) findEvent(c *gin.Context) *models.GuestEvent {
+	return loadGuestEvent(c, h.guestEventRepo)
+}
+
+// findOwnEvent loads an event and checks the current user organizes it
+func (h *GuestEventHandler) findOwnEvent(c *gin.Context) *models.GuestEvent {
+	return loadOwnGuestEvent(c, h.guestEventRepo)
+}
+
+// loadGuestEvent loads an event by the :id route parameter.
+// It writes the error response and returns nil when the event cannot be loaded.
+func loadGuestEvent(c *gin.Context, guestEventRepo repository.GuestEventRepository) *models.GuestEvent {
+	eventID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil
+	}
+
+	event, err := guestEventRepo.FindByID(eventID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch event: "+err.Error())
+		return nil
+	}
+	if event == nil {
+		utils.NotFoundResponse(c, "Event not found")
+		return nil
+	}
+
+	return event
+}
+
+// loadOwnGuestEvent loads an event and checks the current user organizes it
+func loadOwnGuestEvent(c *gin.Context, guestEventRepo repository.GuestEventRepository) *models.GuestEvent {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return nil
+	}
+
+	event := loadGuestEvent(c, guestEventRepo)
+	if event == nil {
+		return nil
+	}
+	if event.OrganizerUserID != userID {
+		utils.ForbiddenResponse(c, "Only the organizer can manage this event")
+		return nil
+	}
+
+	return event
+}
+
+// CreateEvent creates a public event organized by the current user
+func (h *GuestEventHandler) CreateEvent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		Name        string    `json:"name" binding:"required"`
+		Description string    `json:"description"`
+		Location    string    `json:"location"`
+		StartsAt    time.Time `json:"starts_at" binding:"required"`
+		EndsAt      time.Time `json:"ends_at" binding:"required"`
+		Capacity    int       `json:"capacity" binding:"min=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		utils.BadRequestResponse(c, "ends_at must be after starts_at")
+		return
+	}
+
+	event := &models.GuestEvent{
+		Name:             req.Name,
+		Description:      req.Description,
+		Location:         req.Location,
+		StartsAt:         req.StartsAt,
+		EndsAt:           req.EndsAt,
+		Capacity:         req.Capacity,
+		OrganizerUserID:  userID,
+		RegistrationOpen: true,
+	}
+
+	if err := h.guestEventRepo.Create(event); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create event: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Event created successfully", event)
+}
+
+// GetMyEvents returns the events organized by the current user
+func (h *GuestEventHandler) GetMyEvents(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	events, err := h.guestEventRepo.FindByOrganizer(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch events: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Events retrieved successfully", events)
+}
+
+// GetPublicEvent returns the public information of an event
+func (h *GuestEventHandler) GetPublicEvent(c *gin.Context) {
+	event := h.findEvent(c)
+	if event == nil {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Event retrieved successfully", gin.H{
+		"id":                event.ID,
+		"name":              event.Name,
+		"description":       event.Description,
+		"location":          event.Location,
+		"starts_at":         event.StartsAt,
+		"ends_at":           event.EndsAt,
+		"registration_open": event.RegistrationOpen && time.Now().Before(event.EndsAt),
+	})
+}
+
+// RegisterGuest registers a guest without a campus account. The returned code is
+// shown as a QR code and scanned by the organizer at the venue.
+func (h *GuestEventHandler) RegisterGuest(c *gin.Context) {
+	event := h.findEvent(c)
+	if event == nil {
+		return
+	}
+
+	if !event.RegistrationOpen || time.Now().After(event.EndsAt) {
+		utils.ErrorResponse(c, http.StatusConflict, "Registration for this event is closed", nil)
+		return
+	}
+
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		Email       string `json:"email" binding:"required,email"`
+		Phone       string `json:"phone"`
+		Institution string `json:"institution"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Name and a valid email are required")
+		return
+	}
+
+	code, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate registration code: "+err.Error())
+		return
+	}
+
+	registration := &models.GuestRegistration{
+		EventID:          event.ID,
+		Name:             strings.TrimSpace(req.Name),
+		Email:            strings.ToLower(strings.TrimSpace(req.Email)),
+		Phone:            req.Phone,
+		Institution:      req.Institution,
+		RegistrationCode: code,
+	}
+
+	if err := h.guestEventRepo.Register(registration, event.Capacity); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAlreadyRegistered):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		case errors.Is(err, repository.ErrEventFull):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to register guest: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Registration successful", gin.H{
+		"event_id":          event.ID,
+		"name":              registration.Name,
+		"registration_code": registration.RegistrationCode,
+		"qr_payload":        fmt.Sprintf("delpresence:guest:%d:%s", event.ID, registration.RegistrationCode),
+	})
+}
+
+// CheckInGuest checks in a guest by the code scanned from their QR
+func (h *GuestEventHandler) CheckInGuest(c *gin.Context) {
+	event := h.findOwnEvent(c)
+	if event == nil {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "code is required")
+		return
+	}
+
+	// Accept both the bare code and the full QR payload
+	code := req.Code
+	if parts := strings.Split(code, ":"); len(parts) == 4 && parts[0] == "delpresence" && parts[1] == "guest" {
+		code = parts[3]
+	}
+
+	registration, err := h.guestEventRepo.FindRegistrationByCode(event.ID, code)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch registration: "+err.Error())
+		return
+	}
+	if registration == nil {
+		utils.NotFoundResponse(c, "Registration not found for this event")
+		return
+	}
+
+	if registration.CheckedInAt != nil {
+		utils.SuccessResponse(c, http.StatusOK, "Guest already checked in", registration)
+		return
+	}
+
+	now := time.Now()
+	registration.CheckedInAt = &now
+	if err := h.guestEventRepo.UpdateRegistration(registration); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check in guest: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Guest checked in successfully", registration)
+}
+
+// GetAttendees returns the registrations of an event
+func (h *GuestEventHandler) GetAttendees(c *gin.Context) {
+	event := h.findOwnEvent(c)
+	if event == nil {
+		return
+	}
+
+	registrations, err := h.guestEventRepo.FindRegistrations(event.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendees: "+err.Error())
+		return
+	}
+
+	checkedIn := 0
+	for _, registration := range registrations {
+		if registration.CheckedInAt != nil {
+			checkedIn++
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendees retrieved successfully", gin.H{
+		"event":      event,
+		"registered": len(registrations),
+		"checked_in": checkedIn,
+		"attendees":  registrations,
+	})
+}
+
+// ExportAttendeesCSV streams the attendee list of an event as CSV
+func (h *GuestEventHandler) ExportAttendeesCSV(c *gin.Context) {
+	event := h.findOwnEvent(c)
+	if event == nil {
+		return
+	}
+
+	registrations, err := h.guestEventRepo.FindRegistrations(event.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendees: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"event-%d-attendees.csv\"", event.ID))
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"name", "email", "phone", "institution", "registered_at", "checked_in_at"})
+	for _, registration := range registrations {
+		checkedInAt := ""
+		if registration.CheckedInAt != nil {
+			checkedInAt = registration.CheckedInAt.Format(time.RFC3339)
+		}
+		_ = writer.Write([]string{
+			registration.Name,
+			registration.Email,
+			registration.Phone,
+			registration.Institution,
+			registration.CreatedAt.Format(time.RFC3339),
+			checkedInAt,
+		})
+	}
+	writer.Flush()
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler answers the liveness and readiness probes of the orchestrator
+type HealthHandler struct {
+	health *services.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(health *services.HealthService) *HealthHandler {
+	return &HealthHandler{health: health}
+}
+
+// Live reports that the process is up and serving requests; it checks no dependency, so a
+// database outage does not get the API restarted
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "alive",
+	})
+}
+
+// Ready reports the status and latency of each dependency; 503 while a critical one is down
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.health.Ready()
+
+	status := http.StatusOK
+	if report.Status == services.ReadinessUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
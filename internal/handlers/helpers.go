@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/logging"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlerLog logs what handlers do while serving a request
+var handlerLog = logging.Module("handlers")
+
+// requestLog returns the handler logger tagged with the ID of the request being served
+func requestLog(c *gin.Context) *logging.Logger {
+	return handlerLog.Ctx(c.Request.Context())
+}
+
+// currentUserID returns the authenticated user ID set by the auth middleware
+func currentUserID(c *gin.Context) (uint, bool) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		return 0, false
+	}
+	return principal.UserID, true
+}
+
+// courseGrant returns the assignment that lets the current assistant act on the course of the
+// request, or nil when the route is not guarded by a course permission or the user owns the resource
+func courseGrant(c *gin.Context) *models.AssistantAssignment {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		return nil
+	}
+	return principal.CourseGrant
+}
+
+// parseIDParam parses a numeric route parameter
+func parseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return uint(id), nil
+}
+
+// resolveStudentInfo finds the basic info of a student by campus user ID, preferring
+// the locally synced snapshot and falling back to the campus API
+func resolveStudentInfo(ctx context.Context, mahasiswaRepo repository.MahasiswaRepository, campusClient *utils.CampusClient, userID uint) (*models.MahasiswaInfo, error) {
+	snapshot, err := mahasiswaRepo.FindSnapshotByUserID(userID)
+	if err != nil {
+		handlerLog.Ctx(ctx).Errorf("Error loading student snapshot for user ID %d: %v", userID, err)
+	}
+	if snapshot != nil && snapshot.Nim != "" {
+		if complete, err := snapshot.ToMahasiswaComplete(); err == nil {
+			return &complete.BasicInfo, nil
+		}
+	}
+
+	return campusClient.GetMahasiswaByUserID(ctx, int(userID))
+}
+
+// resolveStudentNIM finds the NIM of a student by campus user ID
+func resolveStudentNIM(ctx context.Context, mahasiswaRepo repository.MahasiswaRepository, campusClient *utils.CampusClient, userID uint) (string, error) {
+	mahasiswaInfo, err := resolveStudentInfo(ctx, mahasiswaRepo, campusClient, userID)
+	if err != nil {
+		return "", err
+	}
+	return mahasiswaInfo.Nim, nil
+}
+
+// newAuditEntry builds an audit entry with the actor and client IP taken from the request
+func newAuditEntry(c *gin.Context, action, entityType string, entityID interface{}, details map[string]interface{}) services.AuditEntry {
+	entry := services.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    details,
+		IPAddress:  c.ClientIP(),
+	}
+	if principal, ok := auth.FromContext(c); ok {
+		entry.ActorUserID = principal.UserID
+		entry.ActorType = string(principal.UserType)
+		if entry.ActorType == "" {
+			entry.ActorType = principal.ActiveRole
+		}
+		if principal.Elevated {
+			if entry.Details == nil {
+				entry.Details = map[string]interface{}{}
+			}
+			entry.Details["sudo"] = true
+		}
+	}
+	return entry
+}
+
+// eventActor describes the user making the request for published domain events
+func eventActor(c *gin.Context) events.Actor {
+	entry := newAuditEntry(c, "", "", nil, nil)
+	return events.Actor{
+		UserID:    entry.ActorUserID,
+		Type:      entry.ActorType,
+		IPAddress: entry.IPAddress,
+	}
+}
+
+// resolveConflictRequest is the request body for resolving a sync conflict of the user's profile
+type resolveConflictRequest struct {
+	Keep string `json:"keep" binding:"required,oneof=local campus"` // Side whose value the profile keeps
+}
+
+// listProfileConflicts responds with the open sync conflicts of the current user's profile
+func listProfileConflicts(c *gin.Context, conflicts *services.SyncConflictService, profileType models.UserType) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	open, err := conflicts.OpenConflicts(profileType, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch sync conflicts: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sync conflicts retrieved successfully", open)
+}
+
+// resolveProfileConflict closes a sync conflict of the current user's profile with the side
+// the user keeps
+func resolveProfileConflict(c *gin.Context, conflicts *services.SyncConflictService, profileType models.UserType) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	conflictID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid conflict ID")
+		return
+	}
+
+	var req resolveConflictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	conflict, err := conflicts.Resolve(profileType, userID, conflictID, req.Keep)
+	switch {
+	case errors.Is(err, services.ErrSyncConflictNotFound):
+		utils.NotFoundResponse(c, "Sync conflict not found")
+		return
+	case errors.Is(err, services.ErrSyncConflictClosed):
+		utils.ErrorResponse(c, http.StatusConflict, "Sync conflict was already resolved or superseded", nil)
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to resolve sync conflict: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sync conflict resolved successfully", conflict)
+}
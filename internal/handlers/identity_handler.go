@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentityHandler lets users manage the roles linked to their account
+type IdentityHandler struct {
+	userRoleRepo  repository.UserRoleRepository
+	lecturerRepo  repository.LecturerRepository
+	assistantRepo repository.AssistantRepository
+	mahasiswaRepo repository.MahasiswaRepository
+	tokenRepo     *repository.TokenRepository
+	campusClient  *utils.CampusClient
+}
+
+// NewIdentityHandler creates a new IdentityHandler
+func NewIdentityHandler(userRoleRepo repository.UserRoleRepository, lecturerRepo repository.LecturerRepository, assistantRepo repository.AssistantRepository, mahasiswaRepo repository.MahasiswaRepository, campusClient *utils.CampusClient) *IdentityHandler {
+	return &IdentityHandler{
+		userRoleRepo:  userRoleRepo,
+		lecturerRepo:  lecturerRepo,
+		assistantRepo: assistantRepo,
+		mahasiswaRepo: mahasiswaRepo,
+		tokenRepo:     repository.NewTokenRepository(),
+		campusClient:  campusClient,
+	}
+}
+
+// RoleRequest is the request body for linking a role or choosing the default role
+type RoleRequest struct {
+	Role models.UserType `json:"role" binding:"required"`
+}
+
+// GetMyRoles lists the roles linked to the current account
+func (h *IdentityHandler) GetMyRoles(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	roles, err := h.userRoleRepo.FindByUserID(principal.IdentitySource(), principal.UserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Roles retrieved successfully", gin.H{
+		"roles":       roles,
+		"active_role": principal.ActiveRole,
+	})
+}
+
+// LinkRole links a role to the current account once the matching profile is verified
+func (h *IdentityHandler) LinkRole(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	userID := principal.UserID
+
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	var profileID uint
+	switch req.Role {
+	case models.StudentType:
+		// Students are verified against the campus directory
+		if _, err := resolveStudentInfo(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID); err != nil {
+			utils.ForbiddenResponse(c, "No student record found for this account")
+			return
+		}
+	case models.LecturerType:
+		lecturer, err := h.lecturerRepo.FindByUserID(userID)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to verify lecturer profile")
+			return
+		}
+		if lecturer == nil {
+			utils.ForbiddenResponse(c, "No lecturer profile found for this account, sync it first")
+			return
+		}
+		profileID = lecturer.ID
+	case models.AssistantType:
+		assistant, err := h.assistantRepo.FindByUserID(userID)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to verify assistant profile")
+			return
+		}
+		if assistant == nil {
+			utils.ForbiddenResponse(c, "No assistant profile found for this account, sync it first")
+			return
+		}
+		profileID = assistant.ID
+	default:
+		utils.BadRequestResponse(c, "Role cannot be linked: "+string(req.Role))
+		return
+	}
+
+	userRole := &models.UserRole{
+		Source:    principal.IdentitySource(),
+		UserID:    userID,
+		Role:      req.Role,
+		ProfileID: profileID,
+	}
+	if err := h.userRoleRepo.Assign(userRole); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to link role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Role linked successfully", userRole)
+}
+
+// SetDefaultRole chooses the role used when a request does not ask for one
+func (h *IdentityHandler) SetDefaultRole(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	roles, err := h.userRoleRepo.FindByUserID(principal.IdentitySource(), principal.UserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+	held := false
+	for _, role := range roles {
+		if role.Role == req.Role {
+			held = true
+			break
+		}
+	}
+	if !held {
+		utils.ForbiddenResponse(c, "Role is not linked to this account")
+		return
+	}
+
+	if err := h.userRoleRepo.SetDefault(principal.IdentitySource(), principal.UserID, req.Role); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update default role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Default role updated successfully", nil)
+}
+
+// SwitchRole issues an access token scoped to one of the roles linked to the account
+func (h *IdentityHandler) SwitchRole(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	userRoles, err := h.userRoleRepo.FindByUserID(principal.IdentitySource(), principal.UserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+
+	roles := make([]string, 0, len(userRoles))
+	held := false
+	for _, userRole := range userRoles {
+		roles = append(roles, string(userRole.Role))
+		if userRole.Role == req.Role {
+			held = true
+		}
+	}
+	if !held {
+		utils.ForbiddenResponse(c, "Role is not linked to this account")
+		return
+	}
+
+	token, expiresAt, err := jwt.GenerateRoleToken(principal.UserID, principal.CampusUserID, principal.Email, roles, string(req.Role))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate token")
+		return
+	}
+
+	refreshToken, refreshExpiresAt, err := issueRefreshToken(h.tokenRepo, principal, string(req.Role))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate refresh token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Role switched successfully", gin.H{
+		"access_token":       token,
+		"refresh_token":      refreshToken,
+		"token_type":         "Bearer",
+		"expires_at":         expiresAt,
+		"refresh_expires_at": refreshExpiresAt,
+		"active_role":        req.Role,
+		"roles":              roles,
+	})
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mentorLinkValidity is how long a mentor confirmation link stays valid
+const mentorLinkValidity = 7 * 24 * time.Hour
+
+// InternshipHandler handles remote attendance of students on internship (kerja praktek)
+type InternshipHandler struct {
+	internshipRepo    repository.InternshipRepository
+	mahasiswaRepo     repository.MahasiswaRepository
+	internshipService *services.InternshipService
+	emailQueue        *services.EmailQueue
+	campusClient      *utils.CampusClient
+	publicBaseURL     string // Base of the supervisor confirmation links
+}
+
+// NewInternshipHandler creates a new instance of InternshipHandler
+func NewInternshipHandler(internshipRepo repository.InternshipRepository, mahasiswaRepo repository.MahasiswaRepository, internshipService *services.InternshipService, emailQueue *services.EmailQueue, publicBaseURL string, campusClient *utils.CampusClient) *InternshipHandler {
+	return &InternshipHandler{
+		internshipRepo:    internshipRepo,
+		mahasiswaRepo:     mahasiswaRepo,
+		internshipService: internshipService,
+		emailQueue:        emailQueue,
+		campusClient:      campusClient,
+		publicBaseURL:     publicBaseURL,
+	}
+}
+
+// findOwnInternship loads an internship and checks it belongs to the current student.
+// It writes the error response and returns nil otherwise.
+func (h *InternshipHandler) findOwnInternship(c *gin.Context) *models.Internship {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return nil
+	}
+
+	internshipID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil
+	}
+
+	internship, err := h.internshipRepo.FindByID(internshipID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch internship: "+err.Error())
+		return nil
+	}
+	if internship == nil || internship.StudentUserID != userID {
+		utils.NotFoundResponse(c, "Internship not found")
+		return nil
+	}
+
+	return internship
+}
+
+// RegisterInternship registers the current student's internship placement
+func (h *InternshipHandler) RegisterInternship(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		CompanyName     string `json:"company_name" binding:"required"`
+		CompanyAddress  string `json:"company_address"`
+		MentorName      string `json:"mentor_name" binding:"required"`
+		MentorEmail     string `json:"mentor_email" binding:"required,email"`
+		SupervisorName  string `json:"supervisor_name"`
+		SupervisorEmail string `json:"supervisor_email" binding:"required,email"`
+		StartDate       string `json:"start_date" binding:"required"`
+		EndDate         string `json:"end_date" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		utils.BadRequestResponse(c, "start_date must use the YYYY-MM-DD format")
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		utils.BadRequestResponse(c, "end_date must use the YYYY-MM-DD format")
+		return
+	}
+	if endDate.Before(startDate) {
+		utils.BadRequestResponse(c, "end_date must not be before start_date")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	internship := &models.Internship{
+		StudentUserID:   userID,
+		Nim:             nim,
+		CompanyName:     req.CompanyName,
+		CompanyAddress:  req.CompanyAddress,
+		MentorName:      req.MentorName,
+		MentorEmail:     req.MentorEmail,
+		SupervisorName:  req.SupervisorName,
+		SupervisorEmail: req.SupervisorEmail,
+		StartDate:       startDate,
+		EndDate:         endDate,
+	}
+
+	if err := h.internshipRepo.Create(internship); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save internship: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Internship registered successfully", internship)
+}
+
+// GetMyInternships returns the current student's internships
+func (h *InternshipHandler) GetMyInternships(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	internships, err := h.internshipRepo.FindByStudent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch internships: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Internships retrieved successfully", internships)
+}
+
+// CheckIn records the daily remote attendance and emails the mentor a confirmation link
+func (h *InternshipHandler) CheckIn(c *gin.Context) {
+	internship := h.findOwnInternship(c)
+	if internship == nil {
+		return
+	}
+
+	var req struct {
+		Latitude      *float64 `json:"latitude" binding:"required"`
+		Longitude     *float64 `json:"longitude" binding:"required"`
+		Accuracy      float64  `json:"accuracy"`
+		ActivityNotes string   `json:"activity_notes" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Latitude, longitude and activity notes are required")
+		return
+	}
+
+	now := time.Now()
+	if !internship.IsActiveOn(now) {
+		utils.BadRequestResponse(c, "Internship is not active today")
+		return
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate confirmation token: "+err.Error())
+		return
+	}
+
+	checkIn := &models.InternshipCheckIn{
+		InternshipID:      internship.ID,
+		Date:              now.Format("2006-01-02"),
+		CheckInAt:         now,
+		Latitude:          *req.Latitude,
+		Longitude:         *req.Longitude,
+		Accuracy:          req.Accuracy,
+		ActivityNotes:     strings.TrimSpace(req.ActivityNotes),
+		ConfirmationToken: token,
+		TokenExpiresAt:    now.Add(mentorLinkValidity),
+	}
+
+	if err := h.internshipRepo.CreateCheckIn(checkIn); err != nil {
+		if errors.Is(err, repository.ErrCheckInExists) {
+			utils.ErrorResponse(c, http.StatusConflict, "You have already checked in today", nil)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to save check-in: "+err.Error())
+		return
+	}
+
+	// Send the confirmation link to the external mentor
+	data := services.EmailData{
+		Subject:       "Konfirmasi Kehadiran Kerja Praktek " + internship.Nim,
+		RecipientName: internship.MentorName,
+		Data: map[string]interface{}{
+			"nim":          internship.Nim,
+			"student_name": internship.Nim,
+			"date":         checkIn.Date,
+			"notes":        checkIn.ActivityNotes,
+			"confirm_url":  h.publicBaseURL + "/api/v1/internships/confirm/" + token,
+			"expires_at":   checkIn.TokenExpiresAt.Format("2006-01-02 15:04"),
+		},
+	}
+	if info, err := resolveStudentInfo(c.Request.Context(), h.mahasiswaRepo, h.campusClient, internship.StudentUserID); err == nil {
+		data.Data["student_name"] = info.Nama
+		data.Faculty = info.Fakultas
+	}
+	if err := h.emailQueue.Enqueue(c.Request.Context(), internship.MentorEmail, "internship_confirmation", data); err != nil {
+		requestLog(c).Errorf("Failed to queue mentor confirmation for check-in %d: %v", checkIn.ID, err)
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Check-in recorded successfully", checkIn)
+}
+
+// GetCheckIns returns the check-ins of one of the current student's internships
+func (h *InternshipHandler) GetCheckIns(c *gin.Context) {
+	internship := h.findOwnInternship(c)
+	if internship == nil {
+		return
+	}
+
+	checkIns, err := h.internshipRepo.FindCheckIns(internship.ID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch check-ins: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Check-ins retrieved successfully", checkIns)
+}
+
+// ConfirmCheckIn is opened by the external mentor from the emailed link
+func (h *InternshipHandler) ConfirmCheckIn(c *gin.Context) {
+	checkIn, err := h.internshipRepo.FindCheckInByToken(c.Param("token"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch check-in: "+err.Error())
+		return
+	}
+	if checkIn == nil {
+		utils.NotFoundResponse(c, "Confirmation link is invalid")
+		return
+	}
+
+	if checkIn.ConfirmedAt != nil {
+		utils.SuccessResponse(c, http.StatusOK, "Attendance was already confirmed", gin.H{
+			"date":         checkIn.Date,
+			"confirmed_at": checkIn.ConfirmedAt,
+		})
+		return
+	}
+
+	if time.Now().After(checkIn.TokenExpiresAt) {
+		utils.ErrorResponse(c, http.StatusGone, "Confirmation link has expired", nil)
+		return
+	}
+
+	now := time.Now()
+	checkIn.ConfirmedAt = &now
+	if err := h.internshipRepo.UpdateCheckIn(checkIn); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to confirm check-in: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance confirmed, thank you", gin.H{
+		"date":         checkIn.Date,
+		"confirmed_at": checkIn.ConfirmedAt,
+	})
+}
+
+// SendWeeklySummaries emails the weekly summaries to academic supervisors on demand
+func (h *InternshipHandler) SendWeeklySummaries(c *gin.Context) {
+	sent, err := h.internshipService.SendWeeklySummaries(c.Request.Context(), time.Now())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to send weekly summaries: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Weekly summaries sent", gin.H{"sent": sent})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxInvestigationRange is the longest date range an investigation can cover at once
+const maxInvestigationRange = 366 * 24 * time.Hour
+
+// InvestigationHandler lets investigators look into suspected attendance fraud
+type InvestigationHandler struct {
+	investigationService *services.InvestigationService
+	auditService         *services.AuditService
+}
+
+// NewInvestigationHandler creates a new instance of InvestigationHandler
+func NewInvestigationHandler(investigationService *services.InvestigationService, auditService *services.AuditService) *InvestigationHandler {
+	return &InvestigationHandler{
+		investigationService: investigationService,
+		auditService:         auditService,
+	}
+}
+
+// parseInvestigationRange parses the from and to query parameters and checks the range length
+func parseInvestigationRange(c *gin.Context) (time.Time, time.Time, bool) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Before(from) {
+		utils.BadRequestResponse(c, "to must not be before from")
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Sub(from) > maxInvestigationRange {
+		utils.BadRequestResponse(c, "The date range cannot be longer than a year")
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// GetStudentTimeline returns the check-ins, devices, IP addresses, logins and permission
+// requests of a student between from and to as one timeline. Every lookup is audited.
+func (h *InvestigationHandler) GetStudentTimeline(c *gin.Context) {
+	studentUserID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	from, to, ok := parseInvestigationRange(c)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.investigationService.Timeline(studentUserID, from, to)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build investigation timeline: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "investigation.student", "user", studentUserID, map[string]interface{}{
+		"from": c.Query("from"),
+		"to":   c.Query("to"),
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Investigation timeline retrieved successfully", timeline)
+}
+
+// GetDeviceActivity returns the check-in attempts made from a device by any student between
+// from and to, to find devices used for several students. Every lookup is audited.
+func (h *InvestigationHandler) GetDeviceActivity(c *gin.Context) {
+	deviceID := strings.TrimSpace(c.Param("deviceId"))
+	if deviceID == "" {
+		utils.BadRequestResponse(c, "Device ID is required")
+		return
+	}
+	from, to, ok := parseInvestigationRange(c)
+	if !ok {
+		return
+	}
+
+	attempts, err := h.investigationService.DeviceActivity(deviceID, from, to)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch device activity: "+err.Error())
+		return
+	}
+
+	students := []uint{}
+	seen := make(map[uint]bool)
+	for _, attempt := range attempts {
+		if !seen[attempt.StudentUserID] {
+			seen[attempt.StudentUserID] = true
+			students = append(students, attempt.StudentUserID)
+		}
+	}
+
+	h.auditService.Record(newAuditEntry(c, "investigation.device", "device", deviceID, map[string]interface{}{
+		"from": c.Query("from"),
+		"to":   c.Query("to"),
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Device activity retrieved successfully", gin.H{
+		"device_id": deviceID,
+		"students":  students,
+		"attempts":  attempts,
+	})
+}
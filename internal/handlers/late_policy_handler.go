@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LatePolicyHandler manages how late check-ins are credited
+type LatePolicyHandler struct {
+	policyRepo   repository.LatePolicyRepository
+	latePolicy   *services.LatePolicyService
+	auditService *services.AuditService
+}
+
+// NewLatePolicyHandler creates a new instance of LatePolicyHandler
+func NewLatePolicyHandler(policyRepo repository.LatePolicyRepository, latePolicy *services.LatePolicyService, auditService *services.AuditService) *LatePolicyHandler {
+	return &LatePolicyHandler{
+		policyRepo:   policyRepo,
+		latePolicy:   latePolicy,
+		auditService: auditService,
+	}
+}
+
+// LatePolicyRequest is the request body for creating or updating a late policy
+type LatePolicyRequest struct {
+	CourseCode string            `json:"course_code"` // Empty for the default policy
+	Name       string            `json:"name" binding:"required,max=100"`
+	Tiers      []models.LateTier `json:"tiers" binding:"required"`
+	// GradeOptional applies the tiers to optional sessions too
+	GradeOptional bool `json:"grade_optional"`
+}
+
+// ListPolicies lists all late policies
+func (h *LatePolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch late policies: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Late policies retrieved successfully", policies)
+}
+
+// CreatePolicy creates the default late policy or the policy of a course and regrades
+// the check-ins it covers
+func (h *LatePolicyHandler) CreatePolicy(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req LatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	policy := &models.LatePolicy{
+		CourseCode:    req.CourseCode,
+		Name:          req.Name,
+		Tiers:         req.Tiers,
+		GradeOptional: req.GradeOptional,
+		UpdatedBy:     userID,
+	}
+	if err := policy.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	existing, err := h.policyRepo.FindForCourse(policy.CourseCode)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check late policies: "+err.Error())
+		return
+	}
+	if existing != nil && existing.CourseCode == policy.CourseCode {
+		utils.ErrorResponse(c, http.StatusConflict, "This course already has a late policy", nil)
+		return
+	}
+
+	if err := h.policyRepo.Create(policy); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create late policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "late_policy.create", "late_policy", policy.ID, map[string]interface{}{
+		"course_code":    policy.CourseCode,
+		"tiers":          policy.Tiers,
+		"grade_optional": policy.GradeOptional,
+	}))
+
+	h.respondRegraded(c, http.StatusCreated, "Late policy created successfully", policy, policy.CourseCode)
+}
+
+// UpdatePolicy changes the tiers of a late policy and regrades the check-ins it covers
+func (h *LatePolicyHandler) UpdatePolicy(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req LatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	policy, err := h.policyRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch late policy: "+err.Error())
+		return
+	}
+	if policy == nil {
+		utils.NotFoundResponse(c, "Late policy not found")
+		return
+	}
+
+	// The course of a policy is fixed; create a new policy for another course instead
+	policy.Name = req.Name
+	policy.Tiers = req.Tiers
+	policy.GradeOptional = req.GradeOptional
+	policy.UpdatedBy = userID
+	if err := policy.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.policyRepo.Update(policy); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update late policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "late_policy.update", "late_policy", policy.ID, map[string]interface{}{
+		"course_code":    policy.CourseCode,
+		"tiers":          policy.Tiers,
+		"grade_optional": policy.GradeOptional,
+	}))
+
+	h.respondRegraded(c, http.StatusOK, "Late policy updated successfully", policy, policy.CourseCode)
+}
+
+// DeletePolicy removes a late policy; its check-ins are regraded by the default policy
+func (h *LatePolicyHandler) DeletePolicy(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	policy, err := h.policyRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch late policy: "+err.Error())
+		return
+	}
+	if policy == nil {
+		utils.NotFoundResponse(c, "Late policy not found")
+		return
+	}
+
+	if err := h.policyRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete late policy: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "late_policy.delete", "late_policy", id, map[string]interface{}{
+		"course_code": policy.CourseCode,
+	}))
+
+	h.respondRegraded(c, http.StatusOK, "Late policy deleted successfully", nil, policy.CourseCode)
+}
+
+// respondRegraded regrades the check-ins covered by the policy of courseCode and writes
+// the response with the number of changed check-ins
+func (h *LatePolicyHandler) respondRegraded(c *gin.Context, status int, message string, policy *models.LatePolicy, courseCode string) {
+	regraded, err := h.latePolicy.Recalculate(courseCode)
+	if err != nil {
+		// The policy is saved; the next change or a retry regrades the rest
+		utils.LogError("LatePolicyHandler", "Recalculate", err)
+	}
+
+	utils.SuccessResponse(c, status, message, gin.H{
+		"policy":   policy,
+		"regraded": regraded,
+	})
+}
@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/events"
 	"delpresence-api/internal/models"
 	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
 	"delpresence-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -19,22 +21,37 @@ import (
 // LecturerHandler menangani request terkait dosen
 type LecturerHandler struct {
 	lecturerRepo repository.LecturerRepository
+	conflicts    *services.SyncConflictService
+	bus          *events.Bus
 	campusClient *utils.CampusClient
 }
 
 // NewLecturerHandler membuat instance baru LecturerHandler
-func NewLecturerHandler(lecturerRepo repository.LecturerRepository) *LecturerHandler {
+func NewLecturerHandler(lecturerRepo repository.LecturerRepository, conflicts *services.SyncConflictService, bus *events.Bus, campusClient *utils.CampusClient) *LecturerHandler {
 	return &LecturerHandler{
 		lecturerRepo: lecturerRepo,
-		campusClient: utils.NewCampusClient(),
+		conflicts:    conflicts,
+		bus:          bus,
+		campusClient: campusClient,
 	}
 }
 
+// publishSynced mengumumkan bahwa profil dosen baru saja disinkronkan dari API kampus
+func (h *LecturerHandler) publishSynced(c *gin.Context, lecturer *models.Lecturer) {
+	h.bus.Publish(events.ProfileSynced{
+		Actor:       eventActor(c),
+		UserID:      lecturer.LecturerUserID,
+		ProfileType: models.LecturerType,
+		ProfileID:   lecturer.ID,
+		SyncedAt:    lecturer.LastSyncAt,
+	})
+}
+
 // GetLecturerProfile mengembalikan detail profil dosen
 func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 	// Get user ID from JWT claim
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
 		})
@@ -42,7 +59,7 @@ func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 	}
 
 	// Find lecturer profile by user ID
-	lecturer, err := h.lecturerRepo.FindByUserID(userID.(uint))
+	lecturer, err := h.lecturerRepo.FindByUserID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch lecturer profile",
@@ -53,10 +70,10 @@ func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 	// If lecturer profile doesn't exist, try to fetch from campus API
 	if lecturer == nil {
 		// First check if campus_user_id is in the context (from JWT)
-		campusUserID, exists := c.Get("campus_user_id")
+		campusUserID := principal.CampusUserID
 
 		// If not in context, try to get from query parameter
-		if !exists {
+		if campusUserID == 0 {
 			campusUserIDStr := c.Query("campus_user_id")
 			if campusUserIDStr == "" {
 				c.JSON(http.StatusBadRequest, gin.H{
@@ -78,25 +95,14 @@ func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 			campusUserID = campusUserIDInt
 		}
 
-		// Log campus user ID type and value for debugging
-		log.Printf("Campus User ID: %v (type: %T)", campusUserID, campusUserID)
-
 		// Fetch lecturer details from campus API
-		var campusUserIDInt int
-		switch v := campusUserID.(type) {
-		case int:
-			campusUserIDInt = v
-		case float64:
-			campusUserIDInt = int(v)
-		default:
-			log.Printf("Unexpected campus user ID type: %T", campusUserID)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid campus user ID type",
+		newLecturer, err := h.fetchLecturerDetails(c, campusUserID)
+		if err != nil && h.campusClient.IsUnavailable(err) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Campus API is unavailable and no local lecturer profile has been synced yet",
 			})
 			return
 		}
-
-		newLecturer, err := h.fetchLecturerDetails(campusUserIDInt)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("Failed to fetch lecturer details from campus API: %v", err),
@@ -105,7 +111,7 @@ func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 		}
 
 		// Set user ID and save to database
-		newLecturer.LecturerUserID = userID.(uint)
+		newLecturer.LecturerUserID = principal.UserID
 		newLecturer.LastSyncAt = time.Now()
 		if err := h.lecturerRepo.Create(newLecturer); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -115,6 +121,7 @@ func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 		}
 
 		lecturer = newLecturer
+		h.publishSynced(c, lecturer)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -131,8 +138,8 @@ func (h *LecturerHandler) GetLecturerProfile(c *gin.Context) {
 // SyncLecturerProfile memperbarui data dosen dari API kampus
 func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 	// Get user ID from JWT claim
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
 		})
@@ -140,8 +147,8 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 	}
 
 	// Get campus user ID from JWT claim
-	campusUserID, exists := c.Get("campus_user_id")
-	if !exists {
+	campusUserID := principal.CampusUserID
+	if campusUserID == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Campus user ID not found",
 		})
@@ -149,7 +156,7 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 	}
 
 	// Find existing lecturer profile
-	existingLecturer, err := h.lecturerRepo.FindByUserID(userID.(uint))
+	existingLecturer, err := h.lecturerRepo.FindByUserID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch lecturer profile",
@@ -158,21 +165,23 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 	}
 
 	// Fetch updated lecturer details from campus API
-	var campusUserIDInt int
-	switch v := campusUserID.(type) {
-	case int:
-		campusUserIDInt = v
-	case float64:
-		campusUserIDInt = int(v)
-	default:
-		log.Printf("Unexpected campus user ID type in Sync: %T", campusUserID)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Invalid campus user ID type",
+	updatedLecturer, err := h.fetchLecturerDetails(c, campusUserID)
+	if err != nil && h.campusClient.IsUnavailable(err) && existingLecturer != nil {
+		// Degradation mode: keep serving the last-synced profile
+		requestLog(c).Warnf("Campus API unavailable, serving stale lecturer profile for user ID %d", existingLecturer.LecturerUserID)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Campus API is unavailable, returning last synced profile",
+			"stale":   true,
+			"lecturer": gin.H{
+				"editable_fields": existingLecturer.GetEditableFields(),
+				"readonly_fields": existingLecturer.GetReadOnlyFields(),
+				"id":              existingLecturer.ID,
+				"user_id":         existingLecturer.CampusUserID,
+				"last_sync_at":    existingLecturer.LastSyncAt,
+			},
 		})
 		return
 	}
-
-	updatedLecturer, err := h.fetchLecturerDetails(campusUserIDInt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to fetch lecturer details from campus API: %v", err),
@@ -181,7 +190,18 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 	}
 
 	// Update or create lecturer record
+	conflicts := []models.SyncConflict{}
 	if existingLecturer != nil {
+		// Merge the synced fields the lecturer may have edited by their conflict policy
+		merge, err := h.conflicts.Merge(models.LecturerType, existingLecturer.LecturerUserID, existingLecturer.LastSyncAt,
+			existingLecturer.SyncedFields(), updatedLecturer.SyncedFields())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to merge lecturer details",
+			})
+			return
+		}
+
 		// Preserve user-editable fields
 		updatedLecturer.ID = existingLecturer.ID
 		updatedLecturer.LecturerUserID = existingLecturer.LecturerUserID
@@ -198,11 +218,15 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 			})
 			return
 		}
+		if err := h.conflicts.Save(merge); err != nil {
+			requestLog(c).Errorf("Failed to record synced fields of lecturer %d: %v", updatedLecturer.LecturerUserID, err)
+		}
+		conflicts = merge.Conflicts()
 	} else {
 		// Create a new lecturer in the database
 		newLecturer := &models.Lecturer{
-			LecturerUserID:   uint(userID.(float64)),
-			CampusUserID:     uint(campusUserIDInt),
+			LecturerUserID:   principal.UserID,
+			CampusUserID:     uint(campusUserID),
 			EmployeeID:       updatedLecturer.EmployeeID,
 			LecturerID:       updatedLecturer.LecturerID,
 			IdentityNumber:   updatedLecturer.IdentityNumber,
@@ -227,6 +251,7 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 
 		updatedLecturer = newLecturer
 	}
+	h.publishSynced(c, updatedLecturer)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Lecturer profile synced successfully",
@@ -237,14 +262,15 @@ func (h *LecturerHandler) SyncLecturerProfile(c *gin.Context) {
 			"user_id":         updatedLecturer.CampusUserID,
 			"last_sync_at":    updatedLecturer.LastSyncAt,
 		},
+		"conflicts": conflicts,
 	})
 }
 
 // UpdateLecturerProfile memperbarui bagian profil dosen yang dapat diubah pengguna
 func (h *LecturerHandler) UpdateLecturerProfile(c *gin.Context) {
 	// Get user ID from JWT claim
-	userID, exists := c.Get("user_id")
-	if !exists {
+	principal, ok := auth.FromContext(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
 		})
@@ -252,7 +278,7 @@ func (h *LecturerHandler) UpdateLecturerProfile(c *gin.Context) {
 	}
 
 	// Find existing lecturer profile
-	lecturer, err := h.lecturerRepo.FindByUserID(userID.(uint))
+	lecturer, err := h.lecturerRepo.FindByUserID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch lecturer profile",
@@ -269,6 +295,8 @@ func (h *LecturerHandler) UpdateLecturerProfile(c *gin.Context) {
 
 	// Parse update request
 	var req struct {
+		FullName     *string `json:"full_name" binding:"omitempty,min=1,max=255"`
+		Email        *string `json:"email" binding:"omitempty,email,max=255"`
 		Avatar       *string `json:"avatar"`
 		Biography    *string `json:"biography"`
 		Publications *string `json:"publications"`
@@ -284,6 +312,13 @@ func (h *LecturerHandler) UpdateLecturerProfile(c *gin.Context) {
 	}
 
 	// Only update fields that are user-editable
+	before := services.FieldValues(lecturer.SyncedFields())
+	if req.FullName != nil && lecturer.IsUserEditable("full_name") {
+		lecturer.FullName = *req.FullName
+	}
+	if req.Email != nil && lecturer.IsUserEditable("email") {
+		lecturer.Email = *req.Email
+	}
 	if req.Avatar != nil && lecturer.IsUserEditable("avatar") {
 		lecturer.Avatar = *req.Avatar
 	}
@@ -307,6 +342,10 @@ func (h *LecturerHandler) UpdateLecturerProfile(c *gin.Context) {
 		})
 		return
 	}
+	// Remember the edit so the next sync does not take it for a stale value
+	if err := h.conflicts.RecordLocalEdits(models.LecturerType, lecturer.LecturerUserID, before, lecturer.SyncedFields()); err != nil {
+		requestLog(c).Errorf("Failed to record edited fields of lecturer %d: %v", lecturer.LecturerUserID, err)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Lecturer profile updated successfully",
@@ -317,16 +356,26 @@ func (h *LecturerHandler) UpdateLecturerProfile(c *gin.Context) {
 	})
 }
 
+// GetProfileConflicts mengembalikan konflik sinkronisasi profil dosen yang belum ditinjau
+func (h *LecturerHandler) GetProfileConflicts(c *gin.Context) {
+	listProfileConflicts(c, h.conflicts, models.LecturerType)
+}
+
+// ResolveProfileConflict menyelesaikan konflik sinkronisasi profil dosen dengan nilai yang dipilih
+func (h *LecturerHandler) ResolveProfileConflict(c *gin.Context) {
+	resolveProfileConflict(c, h.conflicts, models.LecturerType)
+}
+
 // fetchLecturerDetails retrieves lecturer details from the campus API
-func (h *LecturerHandler) fetchLecturerDetails(campusUserID int) (*models.Lecturer, error) {
-	url := fmt.Sprintf("https://cis.del.ac.id/api/library-api/dosen?userid=%d", campusUserID)
+func (h *LecturerHandler) fetchLecturerDetails(c *gin.Context, campusUserID int) (*models.Lecturer, error) {
+	url := h.campusClient.URL(fmt.Sprintf("/library-api/dosen?userid=%d", campusUserID))
 
-	log.Printf("Fetching lecturer details for campus user ID: %d from URL: %s", campusUserID, url)
+	requestLog(c).Debugf("Fetching lecturer details for campus user ID: %d from URL: %s", campusUserID, url)
 
 	// Use campus client to make authenticated request
-	response, err := h.campusClient.GetWithAuth(url)
+	response, err := h.campusClient.GetWithAuth(c.Request.Context(), url)
 	if err != nil {
-		log.Printf("Error fetching lecturer details: %v", err)
+		requestLog(c).Warnf("Error fetching lecturer details: %v", err)
 		return nil, fmt.Errorf("error fetching lecturer details: %w", err)
 	}
 	defer response.Body.Close()
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// livePingInterval is how often an idle stream is pinged, so proxies keep it open and
+	// screens that went away are noticed
+	livePingInterval = 30 * time.Second
+	// liveWriteTimeout bounds a write to a screen
+	liveWriteTimeout = 10 * time.Second
+)
+
+// LiveAttendanceHandler streams the check-ins of an attendance session over a WebSocket, so
+// the lecturer's screen updates while students scan the QR code
+type LiveAttendanceHandler struct {
+	attendanceRepo repository.AttendanceRepository
+	live           *services.LiveAttendanceService
+	allowedOrigins []string // Browser origins allowed to open a stream, besides the API's own
+}
+
+// NewLiveAttendanceHandler creates a new instance of LiveAttendanceHandler
+func NewLiveAttendanceHandler(attendanceRepo repository.AttendanceRepository, live *services.LiveAttendanceService, allowedOrigins []string) *LiveAttendanceHandler {
+	return &LiveAttendanceHandler{
+		attendanceRepo: attendanceRepo,
+		live:           live,
+		allowedOrigins: allowedOrigins,
+	}
+}
+
+// StreamSession upgrades the request to a WebSocket streaming the check-ins of an open session.
+// The stream starts with a snapshot of the session's records, then sends a check_in event per
+// check-in and a session_closed event before it ends. Lecturers connect with their own session;
+// classroom displays connect with a display token in the token query parameter.
+func (h *LiveAttendanceHandler) StreamSession(c *gin.Context) {
+	sessionID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	session, err := h.attendanceRepo.FindSessionByID(sessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return
+	}
+	if session == nil || !h.canWatch(c, session) {
+		utils.NotFoundResponse(c, "Attendance session not found")
+		return
+	}
+	if !session.IsOpen() {
+		utils.ErrorResponse(c, http.StatusConflict, "Attendance session is "+string(session.Status), nil)
+		return
+	}
+
+	// Watch before loading the records so no check-in falls between the snapshot and the
+	// stream; screens deduplicate on the record ID
+	events, stop := h.live.Watch(session.ID)
+	defer stop()
+
+	records, err := h.attendanceRepo.FindRecordsBySession(session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance records: "+err.Error())
+		return
+	}
+	snapshot := gin.H{
+		"type":          "snapshot",
+		"session_id":    session.ID,
+		"course_code":   session.CourseCode,
+		"course_name":   session.CourseName,
+		"meeting":       session.MeetingNumber,
+		"records":       records,
+		"present_count": len(records),
+		"at":            time.Now(),
+	}
+
+	server := websocket.Server{
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			return h.checkOrigin(r)
+		},
+		Handler: func(ws *websocket.Conn) {
+			h.stream(ws, snapshot, events)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// canWatch checks whether the request may watch the session: a display token is scoped to the
+// session by its middleware, and users must manage the session
+func (h *LiveAttendanceHandler) canWatch(c *gin.Context, session *models.AttendanceSession) bool {
+	if _, ok := auth.ScopedTokenFromContext(c); ok {
+		return true
+	}
+	userID, ok := currentUserID(c)
+	return ok && managesSession(c, userID, session)
+}
+
+// checkOrigin rejects streams opened by pages of other sites, which would otherwise ride on the
+// dashboard's session cookie. Clients that are not browsers send no Origin.
+func (h *LiveAttendanceHandler) checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	for _, allowed := range h.allowedOrigins {
+		if origin == allowed {
+			return nil
+		}
+	}
+	if parsed, err := url.Parse(origin); err == nil && parsed.Host == r.Host {
+		return nil
+	}
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+// stream sends the snapshot and then the session's events to a screen until it goes away or the
+// session is closed
+func (h *LiveAttendanceHandler) stream(ws *websocket.Conn, snapshot gin.H, events <-chan services.LiveAttendanceEvent) {
+	defer ws.Close()
+	if err := h.send(ws, snapshot); err != nil {
+		return
+	}
+
+	// Screens send nothing; reading only tells when one went away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	ping := time.NewTicker(livePingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-gone:
+			return
+		case event := <-events:
+			if err := h.send(ws, event); err != nil || event.Type == services.LiveSessionClosed {
+				return
+			}
+		case <-ping.C:
+			if err := h.send(ws, gin.H{"type": "ping", "at": time.Now()}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send writes one JSON message to a screen
+func (h *LiveAttendanceHandler) send(ws *websocket.Conn, message interface{}) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(ws, message)
+}
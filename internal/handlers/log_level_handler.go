@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/logging"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogLevelHandler lets admins change log verbosity at runtime
+type LogLevelHandler struct {
+	auditService *services.AuditService
+}
+
+// NewLogLevelHandler creates a new LogLevelHandler
+func NewLogLevelHandler(auditService *services.AuditService) *LogLevelHandler {
+	return &LogLevelHandler{auditService: auditService}
+}
+
+// UpdateLogLevelsRequest is the request body for changing log levels.
+// A module set to "default" follows the global level again.
+type UpdateLogLevelsRequest struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// GetLogLevels returns the global level, the module overrides and the known modules
+func (h *LogLevelHandler) GetLogLevels(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Log levels retrieved successfully", logging.Levels())
+}
+
+// UpdateLogLevels changes the global level and/or module levels without a restart
+func (h *LogLevelHandler) UpdateLogLevels(c *gin.Context) {
+	var req UpdateLogLevelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	// Validate everything before applying anything
+	var global *logging.Level
+	if req.Level != "" {
+		level, err := logging.ParseLevel(req.Level)
+		if err != nil {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		global = &level
+	}
+	modules := make(map[string]*logging.Level, len(req.Modules))
+	for module, value := range req.Modules {
+		if value == "default" {
+			modules[module] = nil
+			continue
+		}
+		level, err := logging.ParseLevel(value)
+		if err != nil {
+			utils.BadRequestResponse(c, err.Error()+" for module "+module)
+			return
+		}
+		modules[module] = &level
+	}
+
+	if global != nil {
+		logging.SetLevel(*global)
+	}
+	for module, level := range modules {
+		if level == nil {
+			logging.ResetModuleLevel(module)
+		} else {
+			logging.SetModuleLevel(module, *level)
+		}
+	}
+
+	h.auditService.Record(newAuditEntry(c, "log_level.update", "log_level", "", map[string]interface{}{
+		"level":   req.Level,
+		"modules": req.Modules,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Log levels updated successfully", logging.Levels())
+}
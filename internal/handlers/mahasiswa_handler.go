@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/events"
 	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
 	"delpresence-api/internal/utils"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,16 +16,53 @@ import (
 
 // MahasiswaHandler handles student-related requests
 type MahasiswaHandler struct {
-	campusClient *utils.CampusClient
+	mahasiswaRepo repository.MahasiswaRepository
+	bus           *events.Bus
+	campusClient  *utils.CampusClient
 }
 
 // NewMahasiswaHandler creates a new MahasiswaHandler
-func NewMahasiswaHandler() *MahasiswaHandler {
+func NewMahasiswaHandler(mahasiswaRepo repository.MahasiswaRepository, bus *events.Bus, campusClient *utils.CampusClient) *MahasiswaHandler {
 	return &MahasiswaHandler{
-		campusClient: utils.NewCampusClient(),
+		mahasiswaRepo: mahasiswaRepo,
+		bus:           bus,
+		campusClient:  campusClient,
 	}
 }
 
+// respondStale serves last-synced local data when the campus API cannot be reached.
+// It returns false when no local data is available.
+func (h *MahasiswaHandler) respondStale(c *gin.Context, snapshot *models.MahasiswaSnapshot, selector func(*models.MahasiswaComplete) interface{}) bool {
+	if snapshot == nil {
+		return false
+	}
+
+	complete, err := snapshot.ToMahasiswaComplete()
+	if err != nil {
+		requestLog(c).Errorf("Error decoding student snapshot for user ID %d: %v", snapshot.UserID, err)
+		return false
+	}
+
+	requestLog(c).Warnf("Campus API unavailable, serving stale data for user ID %d synced at %v", snapshot.UserID, snapshot.LastSyncAt)
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "success",
+		"data":         selector(complete),
+		"stale":        true,
+		"last_sync_at": snapshot.LastSyncAt,
+	})
+	return true
+}
+
+// findSnapshotByUserID looks up local student data, logging lookup errors
+func (h *MahasiswaHandler) findSnapshotByUserID(c *gin.Context, userID int) *models.MahasiswaSnapshot {
+	snapshot, err := h.mahasiswaRepo.FindSnapshotByUserID(uint(userID))
+	if err != nil {
+		requestLog(c).Errorf("Error loading student snapshot for user ID %d: %v", userID, err)
+		return nil
+	}
+	return snapshot
+}
+
 // GetMahasiswaByUserID fetches student information by user ID
 func (h *MahasiswaHandler) GetMahasiswaByUserID(c *gin.Context) {
 	// Parse user ID from query parameter
@@ -46,7 +85,12 @@ func (h *MahasiswaHandler) GetMahasiswaByUserID(c *gin.Context) {
 	}
 
 	// Fetch student information from the campus API
-	mahasiswaInfo, err := h.campusClient.GetMahasiswaByUserID(userID)
+	mahasiswaInfo, err := h.campusClient.GetMahasiswaByUserID(c.Request.Context(), userID)
+	if err != nil && h.campusClient.IsUnavailable(err) {
+		if h.respondStale(c, h.findSnapshotByUserID(c, userID), func(m *models.MahasiswaComplete) interface{} { return m.BasicInfo }) {
+			return
+		}
+	}
 	if err != nil {
 		// Check if this is a "no student found" error
 		if strings.Contains(err.Error(), "no student found") {
@@ -83,7 +127,16 @@ func (h *MahasiswaHandler) GetMahasiswaDetailByNIM(c *gin.Context) {
 	}
 
 	// Fetch detailed student information from the campus API
-	mahasiswaDetail, err := h.campusClient.GetMahasiswaDetailByNIM(nim)
+	mahasiswaDetail, err := h.campusClient.GetMahasiswaDetailByNIM(c.Request.Context(), nim)
+	if err != nil && h.campusClient.IsUnavailable(err) {
+		snapshot, findErr := h.mahasiswaRepo.FindSnapshotByNIM(nim)
+		if findErr != nil {
+			requestLog(c).Errorf("Error loading student snapshot for NIM %s: %v", nim, findErr)
+		}
+		if h.respondStale(c, snapshot, func(m *models.MahasiswaComplete) interface{} { return m.Details }) {
+			return
+		}
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -102,18 +155,17 @@ func (h *MahasiswaHandler) GetMahasiswaDetailByNIM(c *gin.Context) {
 // This is a convenience method that fetches both basic info and details
 func (h *MahasiswaHandler) GetMahasiswaComplete(c *gin.Context) {
 	// Get user ID from context if set by middleware
-	userIDFromContext, exists := c.Get("user_id")
+	principal, exists := auth.FromContext(c)
 
 	var userID int
 	if exists {
 		// Use the ID from the authenticated token
-		userID = int(userIDFromContext.(uint))
-		log.Printf("Using user ID from token: %d", userID)
+		userID = int(principal.UserID)
+		requestLog(c).Debugf("Using user ID from token: %d", userID)
 	} else {
 		// Parse user ID from query parameter as fallback
 		userIDStr := c.Query("user_id")
 		if userIDStr == "" {
-			log.Printf("Error: No user_id provided in request")
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
 				"message": "User ID is required",
@@ -124,27 +176,41 @@ func (h *MahasiswaHandler) GetMahasiswaComplete(c *gin.Context) {
 		var err error
 		userID, err = strconv.Atoi(userIDStr)
 		if err != nil {
-			log.Printf("Error: Invalid user_id format: %s", userIDStr)
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
 				"message": "Invalid user ID format",
 			})
 			return
 		}
-		log.Printf("Using user ID from query parameter: %d", userID)
+		requestLog(c).Debugf("Using user ID from query parameter: %d", userID)
 	}
 
 	// Check if this is a campus-authenticated request
-	campusAuth, campusAuthExists := c.Get("campus_authenticated")
-	isCampusAuth := campusAuthExists && campusAuth.(bool)
+	isCampusAuth := exists && principal.CampusAuthenticated
 
-	log.Printf("Processing complete student data request for user ID: %d (campus auth: %v)", userID, isCampusAuth)
+	requestLog(c).Debugf("Processing complete student data request for user ID: %d (campus auth: %v)", userID, isCampusAuth)
 
-	// Step 1: Fetch basic student information to get the NIM
-	log.Printf("Fetching basic student info for user ID: %d", userID)
-	mahasiswaInfo, err := h.campusClient.GetMahasiswaByUserID(userID)
+	// Serve local data right away while the circuit breaker reports the campus API as down
+	if h.campusClient.IsDegraded() {
+		if h.respondStale(c, h.findSnapshotByUserID(c, userID), func(m *models.MahasiswaComplete) interface{} { return m }) {
+			return
+		}
+	}
+
+	// Fetch basic info and details; runs both campus requests in parallel when the NIM is known
+	response, err := h.campusClient.GetMahasiswaComplete(c.Request.Context(), userID)
+	if err != nil && h.campusClient.IsUnavailable(err) {
+		if h.respondStale(c, h.findSnapshotByUserID(c, userID), func(m *models.MahasiswaComplete) interface{} { return m }) {
+			return
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "Campus API is unavailable and no local student data has been synced yet",
+		})
+		return
+	}
 	if err != nil {
-		log.Printf("Error fetching student info: %v", err)
+		requestLog(c).Warnf("Error fetching complete student data: %v", err)
 		// Check if this is a "no student found" error
 		if strings.Contains(err.Error(), "no student found") {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -161,26 +227,21 @@ func (h *MahasiswaHandler) GetMahasiswaComplete(c *gin.Context) {
 		return
 	}
 
-	log.Printf("Found student with NIM: %s, Name: %s", mahasiswaInfo.Nim, mahasiswaInfo.Nama)
+	requestLog(c).Debugf("Successfully retrieved complete data for student: %s (NIM: %s)", response.Details.Nama, response.BasicInfo.Nim)
 
-	// Step 2: Fetch detailed student information using the NIM
-	log.Printf("Fetching detailed student info for NIM: %s", mahasiswaInfo.Nim)
-	mahasiswaDetail, err := h.campusClient.GetMahasiswaDetailByNIM(mahasiswaInfo.Nim)
-	if err != nil {
-		log.Printf("Error fetching student details: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to fetch student details: " + err.Error(),
+	// Keep a local copy for degradation mode
+	if snapshot, err := models.NewMahasiswaSnapshot(response); err != nil {
+		requestLog(c).Errorf("Error encoding student snapshot: %v", err)
+	} else if err := h.mahasiswaRepo.SaveSnapshot(snapshot); err != nil {
+		requestLog(c).Errorf("Error saving student snapshot: %v", err)
+	} else {
+		h.bus.Publish(events.ProfileSynced{
+			Actor:       eventActor(c),
+			UserID:      snapshot.UserID,
+			ProfileType: models.StudentType,
+			ProfileID:   snapshot.ID,
+			SyncedAt:    snapshot.LastSyncAt,
 		})
-		return
-	}
-
-	log.Printf("Successfully retrieved details for student: %s", mahasiswaDetail.Nama)
-
-	// Step 3: Combine the information into a response
-	response := models.MahasiswaComplete{
-		BasicInfo: *mahasiswaInfo,
-		Details:   *mahasiswaDetail,
 	}
 
 	c.JSON(http.StatusOK, gin.H{
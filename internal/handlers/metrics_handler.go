@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"delpresence-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler exposes the metrics of the API to Prometheus
+type MetricsHandler struct {
+	registry *metrics.Registry
+	token    string
+}
+
+// NewMetricsHandler creates a new MetricsHandler. When token is set, scrapes must send it as a
+// bearer token.
+func NewMetricsHandler(registry *metrics.Registry, token string) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+		token:    token,
+	}
+}
+
+// GetMetrics writes every metric in the Prometheus text exposition format
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	if h.token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+h.token)) != 1 {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := h.registry.Write(c.Writer); err != nil {
+		requestLog(c).Warnf("Failed to write metrics: %v", err)
+	}
+}
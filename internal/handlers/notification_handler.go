@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles in-app notifications of the current user
+type NotificationHandler struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationHandler creates a new instance of NotificationHandler
+func NewNotificationHandler(notificationRepo repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// GetNotifications returns the current user's notifications
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	notifications, err := h.notificationRepo.FindByUser(userID, c.Query("unread") == "true")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch notifications: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// MarkAsRead marks one of the current user's notifications as read
+func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	updated, err := h.notificationRepo.MarkRead(id, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update notification: "+err.Error())
+		return
+	}
+	if !updated {
+		utils.NotFoundResponse(c, "Notification not found or already read")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification marked as read", nil)
+}
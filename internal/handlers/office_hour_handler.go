@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxOfficeHourRepeatWeeks bounds how many weekly copies of a slot are published at once
+const maxOfficeHourRepeatWeeks = 16
+
+// OfficeHourHandler handles the office hours lecturers publish and the slots students book
+type OfficeHourHandler struct {
+	officeHourRepo repository.OfficeHourRepository
+	bus            *events.Bus
+}
+
+// NewOfficeHourHandler creates a new instance of OfficeHourHandler
+func NewOfficeHourHandler(officeHourRepo repository.OfficeHourRepository, bus *events.Bus) *OfficeHourHandler {
+	return &OfficeHourHandler{
+		officeHourRepo: officeHourRepo,
+		bus:            bus,
+	}
+}
+
+// OfficeHourSlotRequest is the request body for publishing office hours
+type OfficeHourSlotRequest struct {
+	Date        string `json:"date" binding:"required"` // YYYY-MM-DD
+	StartTime   string `json:"start_time" binding:"required"`
+	EndTime     string `json:"end_time" binding:"required"`
+	Semester    string `json:"semester" binding:"required"`
+	Location    string `json:"location" binding:"required,max=100"`
+	Capacity    int    `json:"capacity" binding:"required,min=1"`
+	Note        string `json:"note"`
+	RepeatWeeks int    `json:"repeat_weeks" binding:"min=0"` // Extra weekly copies of the slot
+}
+
+// slots validates the request and builds the slot and its weekly repeats
+func (req *OfficeHourSlotRequest) slots(lecturerUserID uint) ([]models.OfficeHourSlot, error) {
+	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		return nil, errors.New("date must use the YYYY-MM-DD format")
+	}
+	start, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
+		return nil, errors.New("start_time must use the HH:MM format")
+	}
+	end, err := time.Parse("15:04", req.EndTime)
+	if err != nil {
+		return nil, errors.New("end_time must use the HH:MM format")
+	}
+	if !end.After(start) {
+		return nil, errors.New("end_time must be after start_time")
+	}
+	if req.RepeatWeeks > maxOfficeHourRepeatWeeks {
+		return nil, errors.New("repeat_weeks must be at most " + strconv.Itoa(maxOfficeHourRepeatWeeks))
+	}
+
+	slots := make([]models.OfficeHourSlot, 0, req.RepeatWeeks+1)
+	for week := 0; week <= req.RepeatWeeks; week++ {
+		slot := models.OfficeHourSlot{
+			LecturerUserID: lecturerUserID,
+			Date:           date.AddDate(0, 0, 7*week),
+			// Stored zero-padded so times compare correctly as strings, as for schedules
+			StartTime: start.Format("15:04"),
+			EndTime:   end.Format("15:04"),
+			Semester:  req.Semester,
+			Location:  req.Location,
+			Capacity:  req.Capacity,
+			Note:      req.Note,
+		}
+		if week == 0 && slot.StartsAt().Before(time.Now()) {
+			return nil, errors.New("office hours cannot be published in the past")
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// OfficeHourBookingRequest is the request body for booking an office-hour slot
+type OfficeHourBookingRequest struct {
+	Topic string `json:"topic" binding:"max=200"`
+}
+
+// today returns the start of the current day
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// GetMySlots returns the current lecturer's office-hour slots from today on
+func (h *OfficeHourHandler) GetMySlots(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	slots, err := h.officeHourRepo.FindSlotsByLecturer(userID, today())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office hours: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Office hours retrieved successfully", slots)
+}
+
+// CreateSlots publishes an office-hour slot for the current lecturer, repeated weekly when
+// repeat_weeks is set. Slots clashing with the lecturer's timetable are rejected together.
+func (h *OfficeHourHandler) CreateSlots(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req OfficeHourSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	slots, err := req.slots(userID)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.officeHourRepo.CreateSlots(slots); err != nil {
+		var conflictErr *repository.OfficeHourConflictError
+		if errors.As(err, &conflictErr) {
+			utils.ErrorResponse(c, http.StatusConflict, "Office hours clash with your timetable", conflictErr.Conflicts)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to save office hours: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Office hours published successfully", slots)
+}
+
+// GetSlotBookings returns the active bookings of one of the current lecturer's slots
+func (h *OfficeHourHandler) GetSlotBookings(c *gin.Context) {
+	slot, ok := h.lecturerSlot(c)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.officeHourRepo.FindBookingsBySlot(slot.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office-hour bookings: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Office-hour bookings retrieved successfully", bookings)
+}
+
+// CancelSlot cancels one of the current lecturer's slots along with its bookings; the booked
+// students are notified
+func (h *OfficeHourHandler) CancelSlot(c *gin.Context) {
+	slot, ok := h.lecturerSlot(c)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.officeHourRepo.CancelSlot(slot)
+	if errors.Is(err, repository.ErrOfficeHourUnavailable) {
+		utils.ErrorResponse(c, http.StatusConflict, "Office-hour slot has already been cancelled", nil)
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to cancel office hours: "+err.Error())
+		return
+	}
+
+	h.bus.Publish(events.OfficeHourSlotCancelled{Actor: eventActor(c), Slot: *slot, Bookings: bookings})
+
+	utils.SuccessResponse(c, http.StatusOK, "Office hours cancelled successfully", gin.H{
+		"slot":               slot,
+		"cancelled_bookings": len(bookings),
+	})
+}
+
+// lecturerSlot loads the slot named in the path and checks that it belongs to the current
+// lecturer. It writes the error response and returns false when it does not.
+func (h *OfficeHourHandler) lecturerSlot(c *gin.Context) (*models.OfficeHourSlot, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return nil, false
+	}
+
+	slotID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil, false
+	}
+
+	slot, err := h.officeHourRepo.FindSlotByID(slotID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office hours: "+err.Error())
+		return nil, false
+	}
+	if slot == nil || slot.LecturerUserID != userID {
+		utils.NotFoundResponse(c, "Office-hour slot not found")
+		return nil, false
+	}
+	return slot, true
+}
+
+// GetAvailableSlots returns the slots students can still book, optionally of one lecturer
+// (lecturer_user_id). Full slots are included so students can see when a lecturer is busy.
+func (h *OfficeHourHandler) GetAvailableSlots(c *gin.Context) {
+	var lecturerUserID uint
+	if value := c.Query("lecturer_user_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "lecturer_user_id must be a number")
+			return
+		}
+		lecturerUserID = uint(parsed)
+	}
+
+	slots, err := h.officeHourRepo.FindOpenSlots(lecturerUserID, today())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office hours: "+err.Error())
+		return
+	}
+
+	now := time.Now()
+	available := make([]models.OfficeHourSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.StartsAt().After(now) {
+			available = append(available, slot)
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Office hours retrieved successfully", available)
+}
+
+// BookSlot books a place in an office-hour slot for the current student
+func (h *OfficeHourHandler) BookSlot(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	slotID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	// The topic is optional, so an empty body is fine
+	var req OfficeHourBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	slot, err := h.officeHourRepo.FindSlotByID(slotID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office hours: "+err.Error())
+		return
+	}
+	if slot == nil {
+		utils.NotFoundResponse(c, "Office-hour slot not found")
+		return
+	}
+
+	booking := &models.OfficeHourBooking{
+		SlotID:        slotID,
+		StudentUserID: userID,
+		Topic:         req.Topic,
+		Status:        models.OfficeHourBooked,
+	}
+	err = h.officeHourRepo.Book(booking, time.Now())
+	switch {
+	case errors.Is(err, repository.ErrOfficeHourUnavailable):
+		utils.ErrorResponse(c, http.StatusConflict, "Office-hour slot is no longer available", nil)
+		return
+	case errors.Is(err, repository.ErrOfficeHourAlreadyBooked):
+		utils.ErrorResponse(c, http.StatusConflict, "You have already booked this office-hour slot", nil)
+		return
+	case errors.Is(err, repository.ErrOfficeHourFull):
+		utils.ErrorResponse(c, http.StatusConflict, "Office-hour slot is full", nil)
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to book office hours: "+err.Error())
+		return
+	}
+
+	h.bus.Publish(events.OfficeHourBooked{Actor: eventActor(c), Booking: *booking})
+
+	utils.SuccessResponse(c, http.StatusCreated, "Office hours booked successfully", booking)
+}
+
+// GetMyBookings returns the current student's office-hour bookings
+func (h *OfficeHourHandler) GetMyBookings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	bookings, err := h.officeHourRepo.FindBookingsByStudent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office-hour bookings: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Office-hour bookings retrieved successfully", bookings)
+}
+
+// CancelMyBooking cancels one of the current student's bookings before its slot starts
+func (h *OfficeHourHandler) CancelMyBooking(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	bookingID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	booking, err := h.officeHourRepo.FindBookingByID(bookingID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch office-hour booking: "+err.Error())
+		return
+	}
+	if booking == nil || booking.StudentUserID != userID {
+		utils.NotFoundResponse(c, "Office-hour booking not found")
+		return
+	}
+	if booking.Slot != nil && !booking.Slot.StartsAt().After(time.Now()) {
+		utils.ErrorResponse(c, http.StatusConflict, "Office-hour slot has already started", nil)
+		return
+	}
+
+	err = h.officeHourRepo.CancelBooking(booking)
+	if errors.Is(err, repository.ErrOfficeHourBookingClosed) {
+		utils.ErrorResponse(c, http.StatusConflict, "Office-hour booking has already been cancelled", nil)
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to cancel office-hour booking: "+err.Error())
+		return
+	}
+
+	h.bus.Publish(events.OfficeHourBookingCancelled{Actor: eventActor(c), Booking: *booking})
+
+	utils.SuccessResponse(c, http.StatusOK, "Office-hour booking cancelled successfully", booking)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OverviewHandler serves the home screen summaries of the mobile apps
+type OverviewHandler struct {
+	lecturerOverview *services.LecturerOverviewService
+}
+
+// NewOverviewHandler creates a new instance of OverviewHandler
+func NewOverviewHandler(lecturerOverview *services.LecturerOverviewService) *OverviewHandler {
+	return &OverviewHandler{
+		lecturerOverview: lecturerOverview,
+	}
+}
+
+// GetLecturerOverview returns today's schedules and sessions, yesterday's attendance rates,
+// pending approvals and sessions still missing their journal for the current lecturer
+func (h *OverviewHandler) GetLecturerOverview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	overview, err := h.lecturerOverview.Overview(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build lecturer overview: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Lecturer overview retrieved successfully", overview)
+}
@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPermissionDays is the longest date range a single permission request may cover
+const maxPermissionDays = 31
+
+// PermissionHandler handles permission (izin and sakit) requests by students and their
+// approval by lecturers
+type PermissionHandler struct {
+	permissionRepo  repository.PermissionRequestRepository
+	assignmentRepo  repository.AssistantAssignmentRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	scheduleRepo    repository.ScheduleRepository
+	mahasiswaRepo   repository.MahasiswaRepository
+	attachmentStore *services.AttachmentStore
+	approvalRouter  *services.ApprovalRouter
+	workflow        *services.WorkflowEngine
+	bus             *events.Bus
+	campusClient    *utils.CampusClient
+}
+
+// NewPermissionHandler creates a new instance of PermissionHandler
+func NewPermissionHandler(permissionRepo repository.PermissionRequestRepository, assignmentRepo repository.AssistantAssignmentRepository, enrollmentRepo repository.EnrollmentRepository, scheduleRepo repository.ScheduleRepository, mahasiswaRepo repository.MahasiswaRepository, attachmentStore *services.AttachmentStore, approvalRouter *services.ApprovalRouter, workflow *services.WorkflowEngine, bus *events.Bus, campusClient *utils.CampusClient) *PermissionHandler {
+	return &PermissionHandler{
+		permissionRepo:  permissionRepo,
+		assignmentRepo:  assignmentRepo,
+		enrollmentRepo:  enrollmentRepo,
+		scheduleRepo:    scheduleRepo,
+		mahasiswaRepo:   mahasiswaRepo,
+		attachmentStore: attachmentStore,
+		approvalRouter:  approvalRouter,
+		workflow:        workflow,
+		bus:             bus,
+		campusClient:    campusClient,
+	}
+}
+
+// PermissionRequestBody is the request body for asking to be excused. It is sent as JSON,
+// or as multipart form data when a supporting document is attached.
+type PermissionRequestBody struct {
+	Kind       models.PermissionKind `form:"kind" json:"kind" binding:"required"`
+	CourseCode string                `form:"course_code" json:"course_code" binding:"required"`
+	Semester   string                `form:"semester" json:"semester" binding:"required"`
+	StartDate  string                `form:"start_date" json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate    string                `form:"end_date" json:"end_date"`                        // Defaults to start_date
+	Reason     string                `form:"reason" json:"reason" binding:"required,max=1000"`
+}
+
+// apply validates the request and copies it onto a permission request
+func (req *PermissionRequestBody) apply(request *models.PermissionRequest) error {
+	if !req.Kind.IsValid() {
+		return errors.New("kind must be izin or sakit")
+	}
+	start, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	if err != nil {
+		return errors.New("start_date must use the YYYY-MM-DD format")
+	}
+	end := start
+	if req.EndDate != "" {
+		if end, err = time.ParseInLocation("2006-01-02", req.EndDate, time.Local); err != nil {
+			return errors.New("end_date must use the YYYY-MM-DD format")
+		}
+	}
+	if end.Before(start) {
+		return errors.New("end_date must not be before start_date")
+	}
+	if end.Sub(start) >= maxPermissionDays*24*time.Hour {
+		return errors.New("a permission request may cover at most 31 days")
+	}
+
+	request.Kind = req.Kind
+	request.CourseCode = req.CourseCode
+	request.Semester = req.Semester
+	request.StartDate = start
+	request.EndDate = end
+	request.Reason = strings.TrimSpace(req.Reason)
+	return nil
+}
+
+// permissionSubject identifies the workflow of a permission request
+func permissionSubject(request *models.PermissionRequest) services.WorkflowSubject {
+	return services.WorkflowSubject{
+		Type:            services.PermissionWorkflowType,
+		ID:              request.ID,
+		RequesterUserID: request.StudentUserID,
+		OwnerUserID:     request.LecturerUserID,
+	}
+}
+
+// CreateRequest submits a permission request for one of the current student's courses
+func (h *PermissionHandler) CreateRequest(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req PermissionRequestBody
+	if err := c.ShouldBind(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	request := &models.PermissionRequest{
+		StudentUserID: userID,
+		Status:        models.PermissionPending,
+	}
+	if err := req.apply(request); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+	request.Nim = nim
+
+	enrollments, err := h.enrollmentRepo.FindByNim(nim, request.Semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch enrollments: "+err.Error())
+		return
+	}
+	var enrollment *models.Enrollment
+	for i := range enrollments {
+		if enrollments[i].CourseCode == request.CourseCode {
+			enrollment = &enrollments[i]
+			break
+		}
+	}
+	if enrollment == nil {
+		utils.BadRequestResponse(c, "You are not enrolled in this course")
+		return
+	}
+	request.CourseName = enrollment.CourseName
+	request.ClassName = enrollment.ClassName
+
+	schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+		Semester:   request.Semester,
+		CourseCode: request.CourseCode,
+		ClassName:  request.ClassName,
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+		return
+	}
+	if len(schedules) == 0 {
+		utils.BadRequestResponse(c, "No lecturer is scheduled for this course")
+		return
+	}
+	request.LecturerUserID = schedules[0].LecturerUserID
+
+	if file, err := c.FormFile("attachment"); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			utils.BadRequestResponse(c, "Failed to read attachment")
+			return
+		}
+		request.AttachmentName, request.AttachmentType, err = h.attachmentStore.Save(src)
+		src.Close()
+		if errors.Is(err, services.ErrInvalidAttachment) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to store attachment: "+err.Error())
+			return
+		}
+	}
+
+	if err := h.permissionRepo.Create(request); err != nil {
+		if request.HasAttachment() {
+			_ = h.attachmentStore.Delete(request.AttachmentName)
+		}
+		utils.InternalServerErrorResponse(c, "Failed to save permission request: "+err.Error())
+		return
+	}
+
+	approver := h.approvalRouter.Approver(request.LecturerUserID, models.LeaveApprovals)
+	if instance, err := h.workflow.Start(permissionSubject(request)); err != nil {
+		// The workflow is started on the first decision instead
+		utils.LogWarning("PermissionHandler", "CreateRequest", "Failed to start workflow: "+err.Error())
+	} else {
+		approver = instance.AssigneeUserID
+	}
+
+	h.bus.Publish(events.PermissionRequested{Actor: eventActor(c), Request: *request, ApproverUserID: approver})
+
+	utils.SuccessResponse(c, http.StatusCreated, "Permission request submitted successfully", request)
+}
+
+// GetMyRequests returns the current student's permission requests
+func (h *PermissionHandler) GetMyRequests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	requests, err := h.permissionRepo.FindByStudent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch permission requests: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Permission requests retrieved successfully", requests)
+}
+
+// GetLecturerRequests returns the permission requests for the current lecturer's courses,
+// plus the pending requests of lecturers who delegated their approvals to them
+func (h *PermissionHandler) GetLecturerRequests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	status := models.PermissionRequestStatus(c.Query("status"))
+	requests, err := h.permissionRepo.FindByLecturers([]uint{userID}, status)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch permission requests: "+err.Error())
+		return
+	}
+
+	// Delegates only see what still needs a decision, not the delegator's history
+	if status == "" || status == models.PermissionPending {
+		if delegators := h.approvalRouter.DelegatorsOf(userID, models.LeaveApprovals); len(delegators) > 0 {
+			delegated, err := h.permissionRepo.FindByLecturers(delegators, models.PermissionPending)
+			if err != nil {
+				utils.InternalServerErrorResponse(c, "Failed to fetch delegated permission requests: "+err.Error())
+				return
+			}
+			requests = append(requests, delegated...)
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Permission requests retrieved successfully", requests)
+}
+
+// GetAssistantRequests returns the permission requests of the courses the current assistant
+// may approve excuses for
+func (h *PermissionHandler) GetAssistantRequests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	assignments, err := h.assignmentRepo.FindByAssistant(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch course assignments: "+err.Error())
+		return
+	}
+	var offerings []models.CourseOffering
+	for _, assignment := range assignments {
+		if assignment.HasPermission(models.ApproveExcusesPermission) {
+			offerings = append(offerings, models.CourseOffering{
+				CourseCode: assignment.CourseCode,
+				ClassName:  assignment.ClassName,
+				Semester:   assignment.Semester,
+			})
+		}
+	}
+
+	requests, err := h.permissionRepo.FindByOfferings(offerings, models.PermissionRequestStatus(c.Query("status")))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch permission requests: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Permission requests retrieved successfully", requests)
+}
+
+// GetAttachment downloads the supporting document of a permission request for its student,
+// a lecturer who may decide it, or an assistant who may approve excuses for its course
+func (h *PermissionHandler) GetAttachment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	requestID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	request, err := h.permissionRepo.FindByID(requestID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch permission request: "+err.Error())
+		return
+	}
+	if request == nil || (request.StudentUserID != userID && courseGrant(c) == nil && !h.approvalRouter.CanApprove(userID, request.LecturerUserID, models.LeaveApprovals)) {
+		utils.NotFoundResponse(c, "Permission request not found")
+		return
+	}
+	if !request.HasAttachment() {
+		utils.NotFoundResponse(c, "Permission request has no attachment")
+		return
+	}
+
+	c.Header("Content-Type", request.AttachmentType)
+	c.File(h.attachmentStore.Path(request.AttachmentName))
+}
+
+// CancelRequest withdraws one of the current student's pending requests
+func (h *PermissionHandler) CancelRequest(c *gin.Context) {
+	h.decideRequest(c, services.PermissionCancelAction)
+}
+
+// ApproveRequest approves a pending request and excuses the student from the closed
+// meetings it covers. Meetings closed later in its date range are excused when they close.
+func (h *PermissionHandler) ApproveRequest(c *gin.Context) {
+	h.decideRequest(c, services.PermissionApproveAction)
+}
+
+// RejectRequest rejects a pending request
+func (h *PermissionHandler) RejectRequest(c *gin.Context) {
+	h.decideRequest(c, services.PermissionRejectAction)
+}
+
+// decideRequest applies an action to a pending request through its workflow
+func (h *PermissionHandler) decideRequest(c *gin.Context, action string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	requestID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req struct {
+		Note string `json:"note"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	request, err := h.permissionRepo.FindByID(requestID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch permission request: "+err.Error())
+		return
+	}
+	if request == nil {
+		utils.NotFoundResponse(c, "Permission request not found")
+		return
+	}
+	if request.Status != models.PermissionPending {
+		utils.ErrorResponse(c, http.StatusConflict, "Permission request has already been "+string(request.Status), nil)
+		return
+	}
+
+	// The workflow decides who may take the action, including delegates of the lecturer.
+	// Assistants granted the permission decide on behalf of the lecturer.
+	act := h.workflow.Act
+	if courseGrant(c) != nil && action != services.PermissionCancelAction {
+		act = func(subject services.WorkflowSubject, actorUserID uint, action, note string) (*models.WorkflowInstance, error) {
+			return h.workflow.ActFor(subject, actorUserID, request.LecturerUserID, action, note)
+		}
+	}
+	if _, err := act(permissionSubject(request), userID, action, req.Note); err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotAssignee):
+			utils.NotFoundResponse(c, "Permission request not found")
+		case errors.Is(err, services.ErrInvalidTransition):
+			utils.ErrorResponse(c, http.StatusConflict, "Permission request has already been decided", nil)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to update permission workflow: "+err.Error())
+		}
+		return
+	}
+
+	var excused int64
+	switch action {
+	case services.PermissionApproveAction:
+		excused, err = h.permissionRepo.Approve(request, userID, req.Note)
+	case services.PermissionCancelAction:
+		err = h.permissionRepo.Close(request, models.PermissionCancelled, userID, req.Note)
+	default:
+		err = h.permissionRepo.Close(request, models.PermissionRejected, userID, req.Note)
+	}
+	switch {
+	case errors.Is(err, repository.ErrPermissionNotPending):
+		utils.ErrorResponse(c, http.StatusConflict, "Permission request has already been decided", nil)
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to update permission request: "+err.Error())
+		return
+	}
+
+	h.bus.Publish(events.PermissionDecided{Actor: eventActor(c), Request: *request, Note: req.Note, Excused: excused})
+
+	utils.SuccessResponse(c, http.StatusOK, "Permission request "+string(request.Status), gin.H{
+		"request": request,
+		"excused": excused,
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProctoringHandler handles requests from the online-exam proctoring system
+type ProctoringHandler struct {
+	examAttendanceRepo repository.ExamAttendanceRepository
+}
+
+// NewProctoringHandler creates a new instance of ProctoringHandler
+func NewProctoringHandler(examAttendanceRepo repository.ExamAttendanceRepository) *ProctoringHandler {
+	return &ProctoringHandler{
+		examAttendanceRepo: examAttendanceRepo,
+	}
+}
+
+// MarkExamAttendance records exam attendance for a batch of students.
+// Marking the same student again updates the existing record, so retries are safe.
+func (h *ProctoringHandler) MarkExamAttendance(c *gin.Context) {
+	var req struct {
+		CourseCode string `json:"course_code" binding:"required"`
+		ExamType   string `json:"exam_type" binding:"required"`
+		Records    []struct {
+			Nim         string                      `json:"nim" binding:"required"`
+			Status      models.ExamAttendanceStatus `json:"status" binding:"required"`
+			ExternalRef string                      `json:"external_ref"`
+			MarkedAt    *time.Time                  `json:"marked_at"`
+		} `json:"records" binding:"required,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	apiKeyID := c.GetUint("api_key_id")
+	examType := strings.ToUpper(strings.TrimSpace(req.ExamType))
+
+	for _, record := range req.Records {
+		if !record.Status.IsValid() {
+			utils.BadRequestResponse(c, "Invalid status for NIM "+record.Nim)
+			return
+		}
+	}
+
+	for _, record := range req.Records {
+		markedAt := time.Now()
+		if record.MarkedAt != nil {
+			markedAt = *record.MarkedAt
+		}
+
+		attendance := &models.ExamAttendance{
+			CourseCode:  req.CourseCode,
+			ExamType:    examType,
+			Nim:         record.Nim,
+			Status:      record.Status,
+			ExternalRef: record.ExternalRef,
+			APIKeyID:    apiKeyID,
+			MarkedAt:    markedAt,
+		}
+
+		if err := h.examAttendanceRepo.Upsert(attendance); err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to save exam attendance: "+err.Error())
+			return
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exam attendance recorded successfully", gin.H{
+		"course_code": req.CourseCode,
+		"exam_type":   examType,
+		"recorded":    len(req.Records),
+	})
+}
+
+// GetExamAttendance returns recorded exam attendance for a course
+func (h *ProctoringHandler) GetExamAttendance(c *gin.Context) {
+	courseCode := c.Param("courseCode")
+	examType := strings.ToUpper(strings.TrimSpace(c.Query("exam_type")))
+
+	attendances, err := h.examAttendanceRepo.FindByCourse(courseCode, examType)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch exam attendance: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exam attendance retrieved successfully", attendances)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PseudonymHandler lets super admins find the student behind a pseudonym
+type PseudonymHandler struct {
+	pseudonymService *services.PseudonymService
+	auditService     *services.AuditService
+}
+
+// NewPseudonymHandler creates a new PseudonymHandler
+func NewPseudonymHandler(pseudonymService *services.PseudonymService, auditService *services.AuditService) *PseudonymHandler {
+	return &PseudonymHandler{
+		pseudonymService: pseudonymService,
+		auditService:     auditService,
+	}
+}
+
+// ReidentifyRequest names the pseudonym to re-identify and why it is needed
+type ReidentifyRequest struct {
+	Pseudonym string `json:"pseudonym" binding:"required"`
+	Reason    string `json:"reason" binding:"required"`
+}
+
+// Reidentify returns the student behind a pseudonym. Every attempt is audited with its reason,
+// including those that match no student.
+func (h *PseudonymHandler) Reidentify(c *gin.Context) {
+	var req ReidentifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	req.Pseudonym = strings.TrimSpace(req.Pseudonym)
+	req.Reason = strings.TrimSpace(req.Reason)
+	if !pseudonym.IsPseudonym(req.Pseudonym) {
+		utils.BadRequestResponse(c, fmt.Sprintf("pseudonym must be %s followed by %d hexadecimal digits", pseudonym.Prefix, pseudonym.Digits))
+		return
+	}
+	if req.Reason == "" {
+		utils.BadRequestResponse(c, "reason is required")
+		return
+	}
+
+	result, err := h.pseudonymService.Reidentify(req.Pseudonym)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to re-identify pseudonym: "+err.Error())
+		return
+	}
+
+	details := map[string]interface{}{
+		"reason": req.Reason,
+		"found":  result != nil,
+	}
+	if result != nil {
+		details["kind"] = result.Kind
+		details["nim"] = result.Nim
+		details["user_id"] = result.UserID
+	}
+	h.auditService.Record(newAuditEntry(c, "pseudonym.reidentify", "pseudonym", req.Pseudonym, details))
+
+	if result == nil {
+		utils.NotFoundResponse(c, "No known student matches this pseudonym")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Pseudonym re-identified successfully", result)
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/pdf"
+	"delpresence-api/pkg/xlsx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler serves printable reports for admins
+type ReportHandler struct {
+	attendanceRepo repository.AttendanceRepository
+	scheduleRepo   repository.ScheduleRepository
+	lecturerRepo   repository.LecturerRepository
+	reportService  *services.ReportService
+	verification   *services.VerificationService
+	pddikti        *services.PDDIKTIExportService
+	statsRepo      repository.AttendanceStatsRepository
+}
+
+// NewReportHandler creates a new instance of ReportHandler
+func NewReportHandler(attendanceRepo repository.AttendanceRepository, scheduleRepo repository.ScheduleRepository, lecturerRepo repository.LecturerRepository, reportService *services.ReportService, verification *services.VerificationService, pddikti *services.PDDIKTIExportService, statsRepo repository.AttendanceStatsRepository) *ReportHandler {
+	return &ReportHandler{
+		attendanceRepo: attendanceRepo,
+		scheduleRepo:   scheduleRepo,
+		lecturerRepo:   lecturerRepo,
+		reportService:  reportService,
+		verification:   verification,
+		pddikti:        pddikti,
+		statsRepo:      statsRepo,
+	}
+}
+
+// GetAttendancePDF renders the attendance recap of a course as PDF. course_code is required;
+// semester, class_name and lecturer_user_id narrow it down, and the lecturer is named in
+// the signature block.
+func (h *ReportHandler) GetAttendancePDF(c *gin.Context) {
+	filter := models.AttendanceRecapFilter{
+		CourseCode: c.Query("course_code"),
+		Semester:   c.Query("semester"),
+		ClassName:  c.Query("class_name"),
+	}
+	if filter.CourseCode == "" {
+		utils.BadRequestResponse(c, "course_code is required")
+		return
+	}
+	if value := c.Query("lecturer_user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "invalid lecturer_user_id")
+			return
+		}
+		filter.LecturerUserID = uint(id)
+	}
+
+	recap, meetings, err := h.attendanceRecap(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build attendance recap: "+err.Error())
+		return
+	}
+	if meetings == 0 {
+		utils.NotFoundResponse(c, "No attendance sessions found for this course")
+		return
+	}
+
+	// Admins who may not see students get the report with their NIMs pseudonymized
+	if pseudonymizer, ok := pseudonym.FromContext(c); ok {
+		for i := range recap.Students {
+			recap.Students[i].Nim = pseudonymizer.Nim(recap.Students[i].Nim)
+		}
+	}
+
+	info := services.AttendanceReportInfo{Meetings: meetings}
+	schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+		Semester:       filter.Semester,
+		CourseCode:     filter.CourseCode,
+		ClassName:      filter.ClassName,
+		LecturerUserID: filter.LecturerUserID,
+	})
+	if err != nil {
+		utils.LogError("ReportHandler", "GetAttendancePDF", err)
+	}
+	if len(schedules) > 0 {
+		info.CourseName = schedules[0].CourseName
+	}
+	if filter.LecturerUserID != 0 {
+		lecturer, err := h.lecturerRepo.FindByUserID(filter.LecturerUserID)
+		if err != nil {
+			utils.LogError("ReportHandler", "GetAttendancePDF", err)
+		}
+		if lecturer != nil {
+			info.LecturerName = lecturer.FullName
+			info.LecturerNIP = lecturer.IdentityNumber
+		}
+	}
+
+	// The report is still served without a verification code if it cannot be recorded
+	userID, _ := currentUserID(c)
+	if document, err := h.verification.Issue(models.AttendanceReportDocument, attendanceReportSubject(recap, info), userID); err != nil {
+		utils.LogError("ReportHandler", "GetAttendancePDF", err)
+	} else {
+		info.VerificationCode = document.Code
+		info.VerificationURL = h.verification.URL(document.Code)
+	}
+
+	doc := h.reportService.AttendancePDF(recap, info)
+
+	c.Header("Content-Type", pdf.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"attendance-%s.pdf\"", exportFileName(recap)))
+	c.Status(http.StatusOK)
+	if err := doc.Write(c.Writer); err != nil {
+		utils.LogError("ReportHandler", "GetAttendancePDF", err)
+	}
+}
+
+// attendanceRecap returns the per-student totals of a report and its number of meetings. A
+// semester report of every lecturer is read from the precomputed attendance stats; other
+// reports, and offerings the stats do not have yet, are recapped from the records.
+func (h *ReportHandler) attendanceRecap(filter models.AttendanceRecapFilter) (*models.AttendanceRecap, int, error) {
+	if filter.Semester != "" && filter.LecturerUserID == 0 {
+		students, meetings, err := h.statsRepo.CourseSummary(filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if meetings > 0 {
+			return &models.AttendanceRecap{
+				CourseCode: filter.CourseCode,
+				Semester:   filter.Semester,
+				ClassName:  filter.ClassName,
+				Students:   students,
+			}, meetings, nil
+		}
+	}
+
+	recap, err := h.attendanceRepo.CourseRecap(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return recap, len(recap.Meetings), nil
+}
+
+// attendanceReportSubject describes an attendance report for verification without naming
+// any student
+func attendanceReportSubject(recap *models.AttendanceRecap, info services.AttendanceReportInfo) string {
+	subject := "Rekapitulasi kehadiran " + recap.CourseCode
+	if info.CourseName != "" {
+		subject += " " + info.CourseName
+	}
+	if recap.ClassName != "" {
+		subject += ", kelas " + recap.ClassName
+	}
+	if recap.Semester != "" {
+		subject += ", semester " + recap.Semester
+	}
+	return subject
+}
+
+// GetPDDIKTIExport streams the class activity of a course offering as a workbook in the
+// layout of the PDDIKTI semester report. The course code is in the path and semester is
+// required; class_name narrows it to one class.
+func (h *ReportHandler) GetPDDIKTIExport(c *gin.Context) {
+	filter := models.AttendanceRecapFilter{
+		CourseCode: c.Param("id"),
+		Semester:   c.Query("semester"),
+		ClassName:  c.Query("class_name"),
+	}
+	if filter.Semester == "" {
+		utils.BadRequestResponse(c, "semester is required")
+		return
+	}
+
+	// Admins who may not see students get the export with their NIMs pseudonymized
+	pseudonymizer, _ := pseudonym.FromContext(c)
+	workbook, err := h.pddikti.Workbook(filter, pseudonymizer)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build PDDIKTI export: "+err.Error())
+		return
+	}
+	if workbook == nil {
+		utils.NotFoundResponse(c, "Course is not scheduled in this semester")
+		return
+	}
+
+	recap := &models.AttendanceRecap{CourseCode: filter.CourseCode, Semester: filter.Semester, ClassName: filter.ClassName}
+	c.Header("Content-Type", xlsx.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"pddikti-%s.xlsx\"", exportFileName(recap)))
+	if err := workbook.Write(c.Writer); err != nil {
+		utils.LogError("ReportHandler", "GetPDDIKTIExport", err)
+	}
+}
+
+// GetStudentStats lists the precomputed attendance of every student per course offering of a
+// semester. course_code, class_name, nim and prodi_id narrow it down.
+func (h *ReportHandler) GetStudentStats(c *gin.Context) {
+	filter := models.AttendanceStatsFilter{
+		Semester:   c.Query("semester"),
+		CourseCode: c.Query("course_code"),
+		ClassName:  c.Query("class_name"),
+		Nim:        c.Query("nim"),
+	}
+	if filter.Semester == "" {
+		utils.BadRequestResponse(c, "semester is required")
+		return
+	}
+	if value := c.Query("prodi_id"); value != "" {
+		prodiID, err := strconv.Atoi(value)
+		if err != nil {
+			utils.BadRequestResponse(c, "invalid prodi_id")
+			return
+		}
+		filter.ProdiID = &prodiID
+	}
+
+	stats, err := h.statsRepo.FindStudentStats(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance stats: "+err.Error())
+		return
+	}
+	refreshedAt, err := h.statsRepo.LastRefreshedAt()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance stats: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance stats retrieved successfully", gin.H{
+		"students":     stats,
+		"refreshed_at": refreshedAt,
+	})
+}
+
+// GetProdiStats lists the precomputed attendance rollup of every prodi, optionally of one
+// semester
+func (h *ReportHandler) GetProdiStats(c *gin.Context) {
+	stats, err := h.statsRepo.FindProdiStats(c.Query("semester"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch prodi attendance stats: "+err.Error())
+		return
+	}
+	refreshedAt, err := h.statsRepo.LastRefreshedAt()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch prodi attendance stats: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prodi attendance stats retrieved successfully", gin.H{
+		"prodi":        stats,
+		"refreshed_at": refreshedAt,
+	})
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoomBookingHandler handles room booking requests by lecturers and assistants and their
+// approval by facilities admins
+type RoomBookingHandler struct {
+	bookingRepo repository.RoomBookingRepository
+	roomRepo    repository.RoomRepository
+	workflow    *services.WorkflowEngine
+	bus         *events.Bus
+}
+
+// NewRoomBookingHandler creates a new instance of RoomBookingHandler
+func NewRoomBookingHandler(bookingRepo repository.RoomBookingRepository, roomRepo repository.RoomRepository, workflow *services.WorkflowEngine, bus *events.Bus) *RoomBookingHandler {
+	return &RoomBookingHandler{
+		bookingRepo: bookingRepo,
+		roomRepo:    roomRepo,
+		workflow:    workflow,
+		bus:         bus,
+	}
+}
+
+// RoomBookingRequest is the request body for booking a room
+type RoomBookingRequest struct {
+	Kind          models.RoomBookingKind `json:"kind" binding:"required"`
+	Room          string                 `json:"room" binding:"required"`
+	Date          string                 `json:"date" binding:"required"` // YYYY-MM-DD
+	StartTime     string                 `json:"start_time" binding:"required"`
+	EndTime       string                 `json:"end_time" binding:"required"`
+	Semester      string                 `json:"semester" binding:"required"`
+	Purpose       string                 `json:"purpose" binding:"required,max=200"`
+	CourseCode    string                 `json:"course_code"`
+	CourseName    string                 `json:"course_name"`
+	ClassName     string                 `json:"class_name"`
+	MeetingNumber int                    `json:"meeting_number" binding:"min=0"`
+}
+
+// apply validates the request and copies it onto a booking
+func (req *RoomBookingRequest) apply(booking *models.RoomBooking) error {
+	if !req.Kind.IsValid() {
+		return errors.New("kind must be extra_session or student_activity")
+	}
+	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		return errors.New("date must use the YYYY-MM-DD format")
+	}
+	start, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
+		return errors.New("start_time must use the HH:MM format")
+	}
+	end, err := time.Parse("15:04", req.EndTime)
+	if err != nil {
+		return errors.New("end_time must use the HH:MM format")
+	}
+	if !end.After(start) {
+		return errors.New("end_time must be after start_time")
+	}
+	startsAt := date.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+	if startsAt.Before(time.Now()) {
+		return errors.New("rooms cannot be booked in the past")
+	}
+	if req.Kind == models.ExtraSessionBooking && (req.CourseCode == "" || req.MeetingNumber < 1) {
+		return errors.New("extra sessions require course_code and meeting_number")
+	}
+
+	booking.Kind = req.Kind
+	booking.Room = req.Room
+	booking.Date = date
+	// Stored zero-padded so times compare correctly as strings, as for schedules
+	booking.StartTime = start.Format("15:04")
+	booking.EndTime = end.Format("15:04")
+	booking.Semester = req.Semester
+	booking.Purpose = req.Purpose
+	booking.CourseCode = req.CourseCode
+	booking.CourseName = req.CourseName
+	booking.ClassName = req.ClassName
+	booking.MeetingNumber = req.MeetingNumber
+	return nil
+}
+
+// roomBookingSubject identifies the workflow of a room booking
+func roomBookingSubject(booking *models.RoomBooking) services.WorkflowSubject {
+	return services.WorkflowSubject{
+		Type:            services.RoomBookingWorkflowType,
+		ID:              booking.ID,
+		RequesterUserID: booking.RequesterUserID,
+	}
+}
+
+// respondBookingConflict writes the response for a booking that clashes with the timetable.
+// It returns false when err is not a conflict.
+func respondBookingConflict(c *gin.Context, err error) bool {
+	var conflictErr *repository.RoomBookingConflictError
+	if !errors.As(err, &conflictErr) {
+		return false
+	}
+	utils.ErrorResponse(c, http.StatusConflict, "Room is already in use at that time", conflictErr.Conflicts)
+	return true
+}
+
+// CreateBooking requests a room for the current lecturer or assistant
+func (h *RoomBookingHandler) CreateBooking(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req RoomBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	booking := &models.RoomBooking{
+		RequesterUserID: principal.UserID,
+		RequesterRole:   models.UserType(principal.ActiveRole),
+		Status:          models.BookingPending,
+	}
+	if booking.RequesterRole == "" {
+		booking.RequesterRole = principal.UserType
+	}
+	if err := req.apply(booking); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	room, err := h.roomRepo.FindByCode(booking.Room)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room: "+err.Error())
+		return
+	}
+	if room == nil {
+		utils.BadRequestResponse(c, "Unknown room: "+booking.Room)
+		return
+	}
+
+	if err := h.bookingRepo.Create(booking); err != nil {
+		if respondBookingConflict(c, err) {
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to save room booking: "+err.Error())
+		return
+	}
+
+	if _, err := h.workflow.Start(roomBookingSubject(booking)); err != nil {
+		// The workflow is started on the first decision instead
+		utils.LogWarning("RoomBookingHandler", "CreateBooking", "Failed to start workflow: "+err.Error())
+	}
+
+	h.bus.Publish(events.RoomBookingRequested{Actor: eventActor(c), Booking: *booking})
+
+	utils.SuccessResponse(c, http.StatusCreated, "Room booking requested successfully", booking)
+}
+
+// GetMyBookings returns the room bookings requested by the current user
+func (h *RoomBookingHandler) GetMyBookings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	bookings, err := h.bookingRepo.FindByRequester(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room bookings: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Room bookings retrieved successfully", bookings)
+}
+
+// CancelBooking withdraws one of the current user's pending bookings
+func (h *RoomBookingHandler) CancelBooking(c *gin.Context) {
+	h.decideBooking(c, services.RoomBookingCancelAction)
+}
+
+// ListBookings lists room bookings for facilities admins, optionally filtered by status
+func (h *RoomBookingHandler) ListBookings(c *gin.Context) {
+	bookings, err := h.bookingRepo.FindAll(models.RoomBookingStatus(c.Query("status")))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room bookings: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Room bookings retrieved successfully", bookings)
+}
+
+// ApproveBooking approves a pending booking. Extra sessions get a scheduled attendance
+// session that the requester opens when the meeting starts.
+func (h *RoomBookingHandler) ApproveBooking(c *gin.Context) {
+	h.decideBooking(c, services.RoomBookingApproveAction)
+}
+
+// RejectBooking rejects a pending booking
+func (h *RoomBookingHandler) RejectBooking(c *gin.Context) {
+	h.decideBooking(c, services.RoomBookingRejectAction)
+}
+
+// decideBooking applies an action to a pending booking through its workflow
+func (h *RoomBookingHandler) decideBooking(c *gin.Context, action string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	bookingID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req struct {
+		Note string `json:"note"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	booking, err := h.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room booking: "+err.Error())
+		return
+	}
+	if booking == nil || (action == services.RoomBookingCancelAction && booking.RequesterUserID != userID) {
+		utils.NotFoundResponse(c, "Room booking not found")
+		return
+	}
+	if booking.Status != models.BookingPending {
+		utils.ErrorResponse(c, http.StatusConflict, "Room booking has already been "+string(booking.Status), nil)
+		return
+	}
+
+	// Conflicts are rechecked before the workflow moves, so a clashing booking stays pending
+	var session *models.AttendanceSession
+	if action == services.RoomBookingApproveAction {
+		if booking.Kind == models.ExtraSessionBooking {
+			if session, err = h.bookingSession(booking); err != nil {
+				utils.InternalServerErrorResponse(c, "Failed to prepare attendance session: "+err.Error())
+				return
+			}
+		}
+		err = h.bookingRepo.Approve(booking, userID, req.Note, session)
+	} else {
+		status := models.BookingRejected
+		if action == services.RoomBookingCancelAction {
+			status = models.BookingCancelled
+		}
+		err = h.bookingRepo.Close(booking, status, userID, req.Note)
+	}
+	switch {
+	case respondBookingConflict(c, err):
+		return
+	case errors.Is(err, repository.ErrBookingNotPending):
+		utils.ErrorResponse(c, http.StatusConflict, "Room booking has already been decided", nil)
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to update room booking: "+err.Error())
+		return
+	}
+
+	if _, err := h.workflow.Act(roomBookingSubject(booking), userID, action, req.Note); err != nil {
+		// The booking itself is already decided; only SLA tracking is affected
+		utils.LogWarning("RoomBookingHandler", "decideBooking", "Failed to update workflow: "+err.Error())
+	}
+
+	h.bus.Publish(events.RoomBookingDecided{Actor: eventActor(c), Booking: *booking, Note: req.Note})
+
+	utils.SuccessResponse(c, http.StatusOK, "Room booking "+string(booking.Status), gin.H{
+		"booking": booking,
+		"session": session,
+	})
+}
+
+// bookingSession builds the scheduled attendance session of an approved extra session
+func (h *RoomBookingHandler) bookingSession(booking *models.RoomBooking) (*models.AttendanceSession, error) {
+	start, err := time.ParseInLocation("2006-01-02 15:04", booking.Date.Format("2006-01-02")+" "+booking.StartTime, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.AttendanceSession{
+		LecturerUserID: booking.RequesterUserID,
+		CourseCode:     booking.CourseCode,
+		CourseName:     booking.CourseName,
+		ClassName:      booking.ClassName,
+		Semester:       booking.Semester,
+		MeetingNumber:  booking.MeetingNumber,
+		Topic:          booking.Purpose,
+		Room:           booking.Room,
+		Status:         models.SessionScheduled,
+		ScheduledStart: &start,
+		OpenedAt:       start,
+	}
+	if err := applyRoomGeofence(h.roomRepo, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoomHandler manages rooms and their locations used for geofenced check-ins
+type RoomHandler struct {
+	roomRepo     repository.RoomRepository
+	scopedTokens *services.ScopedTokenService
+	auditService *services.AuditService
+}
+
+// NewRoomHandler creates a new instance of RoomHandler
+func NewRoomHandler(roomRepo repository.RoomRepository, scopedTokens *services.ScopedTokenService, auditService *services.AuditService) *RoomHandler {
+	return &RoomHandler{
+		roomRepo:     roomRepo,
+		scopedTokens: scopedTokens,
+		auditService: auditService,
+	}
+}
+
+// RoomRequest is the request body for creating or updating a room
+type RoomRequest struct {
+	Code           string   `json:"code" binding:"required"`
+	Name           string   `json:"name"`
+	Building       string   `json:"building"`
+	Capacity       int      `json:"capacity" binding:"min=0"`
+	Latitude       *float64 `json:"latitude"`
+	Longitude      *float64 `json:"longitude"`
+	GeofenceRadius int      `json:"geofence_radius" binding:"omitempty,min=10,max=5000"` // Meters
+}
+
+// apply validates the request and copies it onto a room
+func (req *RoomRequest) apply(room *models.Room) error {
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return errors.New("latitude and longitude must be set together")
+	}
+	if req.Latitude != nil && !utils.ValidCoordinates(*req.Latitude, *req.Longitude) {
+		return errors.New("latitude or longitude is out of range")
+	}
+
+	room.Code = req.Code
+	room.Name = req.Name
+	room.Building = req.Building
+	room.Capacity = req.Capacity
+	room.Latitude = req.Latitude
+	room.Longitude = req.Longitude
+	room.GeofenceRadius = req.GeofenceRadius
+	if room.GeofenceRadius == 0 {
+		room.GeofenceRadius = models.DefaultGeofenceRadius
+	}
+	return nil
+}
+
+// applyRoomGeofence centers the geofence of a session without a location on its room and
+// fills in the default radius
+func applyRoomGeofence(roomRepo repository.RoomRepository, session *models.AttendanceSession) error {
+	if session.Latitude == nil && session.Room != "" {
+		room, err := roomRepo.FindByCode(session.Room)
+		if err != nil {
+			return err
+		}
+		if room != nil && room.HasLocation() {
+			session.Latitude, session.Longitude = room.Latitude, room.Longitude
+			if session.GeofenceRadius == 0 {
+				session.GeofenceRadius = room.GeofenceRadius
+			}
+		}
+	}
+	if session.Latitude != nil && session.GeofenceRadius == 0 {
+		session.GeofenceRadius = models.DefaultGeofenceRadius
+	}
+	return nil
+}
+
+// ListRooms lists all rooms
+func (h *RoomHandler) ListRooms(c *gin.Context) {
+	rooms, err := h.roomRepo.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch rooms: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rooms retrieved successfully", rooms)
+}
+
+// CreateRoom creates a room
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	room := &models.Room{}
+	if err := req.apply(room); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	existing, err := h.roomRepo.FindByCode(room.Code)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check room code: "+err.Error())
+		return
+	}
+	if existing != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "A room with this code already exists", nil)
+		return
+	}
+
+	if err := h.roomRepo.Create(room); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create room: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "room.create", "room", room.ID, map[string]interface{}{
+		"code": room.Code,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Room created successfully", room)
+}
+
+// UpdateRoom updates a room
+func (h *RoomHandler) UpdateRoom(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	room, err := h.roomRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room: "+err.Error())
+		return
+	}
+	if room == nil {
+		utils.NotFoundResponse(c, "Room not found")
+		return
+	}
+
+	if req.Code != room.Code {
+		existing, err := h.roomRepo.FindByCode(req.Code)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check room code: "+err.Error())
+			return
+		}
+		if existing != nil {
+			utils.ErrorResponse(c, http.StatusConflict, "A room with this code already exists", nil)
+			return
+		}
+	}
+
+	if err := req.apply(room); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.roomRepo.Update(room); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update room: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "room.update", "room", room.ID, map[string]interface{}{
+		"code":            room.Code,
+		"latitude":        room.Latitude,
+		"longitude":       room.Longitude,
+		"geofence_radius": room.GeofenceRadius,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Room updated successfully", room)
+}
+
+// CreateKioskToken mints a short-lived token that lets a device mounted in a room show the QR
+// codes of the sessions open there without signing in
+func (h *RoomHandler) CreateKioskToken(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	ttl, ok := bindScopedTokenTTL(c)
+	if !ok {
+		return
+	}
+
+	room, err := h.roomRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch room: "+err.Error())
+		return
+	}
+	if room == nil {
+		utils.NotFoundResponse(c, "Room not found")
+		return
+	}
+
+	userID, _ := currentUserID(c)
+	token, err := h.scopedTokens.Mint(auth.KioskCapability, auth.RoomResource, room.ID, userID, ttl)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to mint kiosk token: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "room.kiosk_token", "room", room.ID, map[string]interface{}{
+		"code":       room.Code,
+		"expires_at": token.ExpiresAt,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Kiosk token created successfully", gin.H{
+		"token":      token.Token,
+		"scope":      token.Scope,
+		"expires_at": token.ExpiresAt,
+		"path":       fmt.Sprintf("/api/v1/kiosk/rooms/%d/sessions?token=%s", room.ID, token.Token),
+	})
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleHandler manages weekly class schedules
+type ScheduleHandler struct {
+	scheduleRepo repository.ScheduleRepository
+	auditService *services.AuditService
+}
+
+// NewScheduleHandler creates a new instance of ScheduleHandler
+func NewScheduleHandler(scheduleRepo repository.ScheduleRepository, auditService *services.AuditService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleRepo: scheduleRepo,
+		auditService: auditService,
+	}
+}
+
+// ScheduleRequest is the request body for creating or updating a schedule
+type ScheduleRequest struct {
+	CourseCode     string `json:"course_code" binding:"required"`
+	CourseName     string `json:"course_name"`
+	ClassName      string `json:"class_name"`
+	LecturerUserID uint   `json:"lecturer_user_id" binding:"required"`
+	Room           string `json:"room" binding:"required"`
+	DayOfWeek      int    `json:"day_of_week" binding:"required,min=1,max=7"`
+	StartTime      string `json:"start_time" binding:"required"`
+	EndTime        string `json:"end_time" binding:"required"`
+	Semester       string `json:"semester" binding:"required"`
+}
+
+// apply validates the request and copies it onto a schedule
+func (req *ScheduleRequest) apply(schedule *models.Schedule) error {
+	start, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
+		return errors.New("start_time must use the HH:MM format")
+	}
+	end, err := time.Parse("15:04", req.EndTime)
+	if err != nil {
+		return errors.New("end_time must use the HH:MM format")
+	}
+	if !end.After(start) {
+		return errors.New("end_time must be after start_time")
+	}
+
+	schedule.CourseCode = req.CourseCode
+	schedule.CourseName = req.CourseName
+	schedule.ClassName = req.ClassName
+	schedule.LecturerUserID = req.LecturerUserID
+	schedule.Room = req.Room
+	schedule.DayOfWeek = req.DayOfWeek
+	// Stored zero-padded so times compare correctly as strings
+	schedule.StartTime = start.Format("15:04")
+	schedule.EndTime = end.Format("15:04")
+	schedule.Semester = req.Semester
+	return nil
+}
+
+// respondScheduleSaveError writes the response for a failed create or update
+func respondScheduleSaveError(c *gin.Context, err error) {
+	var conflictErr *repository.ScheduleConflictError
+	if errors.As(err, &conflictErr) {
+		utils.ErrorResponse(c, http.StatusConflict, "Schedule conflicts with existing schedules", conflictErr.Conflicts)
+		return
+	}
+	utils.InternalServerErrorResponse(c, "Failed to save schedule: "+err.Error())
+}
+
+// ListSchedules lists schedules, filtered by semester, lecturer_user_id, room and day_of_week
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	filter := repository.ScheduleFilter{
+		Semester: c.Query("semester"),
+		Room:     c.Query("room"),
+	}
+	if value := c.Query("lecturer_user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "invalid lecturer_user_id")
+			return
+		}
+		filter.LecturerUserID = uint(id)
+	}
+	if value := c.Query("day_of_week"); value != "" {
+		day, err := strconv.Atoi(value)
+		if err != nil || day < 1 || day > 7 {
+			utils.BadRequestResponse(c, "day_of_week must be between 1 (Monday) and 7 (Sunday)")
+			return
+		}
+		filter.DayOfWeek = day
+	}
+
+	schedules, err := h.scheduleRepo.FindAll(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedules retrieved successfully", schedules)
+}
+
+// courseListOptions are the sort, filter and search parameters of the course list
+var courseListOptions = utils.PageOptions{
+	Sorts:       map[string]string{"course_code": "course_code", "course_name": "course_name", "semester": "semester", "classes": "classes"},
+	Filters:     map[string]string{"semester": "semester", "course_code": "course_code"},
+	Search:      []string{"course_code", "course_name"},
+	DefaultSort: "-semester,course_code",
+}
+
+// ListCourses returns one page of the courses that have class schedules, per semester
+func (h *ScheduleHandler) ListCourses(c *gin.Context) {
+	query, err := utils.ParsePageQuery(c, courseListOptions)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	courses, total, err := h.scheduleRepo.FindCourses(query)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch courses: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Courses retrieved successfully", utils.NewPageResult(courses, total, query))
+}
+
+// CreateSchedule creates a schedule, rejecting room and lecturer conflicts
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	schedule := &models.Schedule{}
+	if err := req.apply(schedule); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.scheduleRepo.Create(schedule); err != nil {
+		respondScheduleSaveError(c, err)
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "schedule.create", "schedule", schedule.ID, map[string]interface{}{
+		"course_code": schedule.CourseCode,
+		"room":        schedule.Room,
+		"day_of_week": schedule.DayOfWeek,
+		"start_time":  schedule.StartTime,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Schedule created successfully", schedule)
+}
+
+// UpdateSchedule updates a schedule, rejecting room and lecturer conflicts
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedule: "+err.Error())
+		return
+	}
+	if schedule == nil {
+		utils.NotFoundResponse(c, "Schedule not found")
+		return
+	}
+
+	if err := req.apply(schedule); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.scheduleRepo.Update(schedule); err != nil {
+		respondScheduleSaveError(c, err)
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "schedule.update", "schedule", schedule.ID, map[string]interface{}{
+		"room":        schedule.Room,
+		"day_of_week": schedule.DayOfWeek,
+		"start_time":  schedule.StartTime,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedule updated successfully", schedule)
+}
+
+// DeleteSchedule deletes a schedule
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedule: "+err.Error())
+		return
+	}
+	if schedule == nil {
+		utils.NotFoundResponse(c, "Schedule not found")
+		return
+	}
+
+	if err := h.scheduleRepo.Delete(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete schedule: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "schedule.delete", "schedule", id, nil))
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedule deleted successfully", nil)
+}
+
+// GetMySchedules returns the current lecturer's teaching schedule
+func (h *ScheduleHandler) GetMySchedules(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	schedules, err := h.scheduleRepo.FindAll(repository.ScheduleFilter{
+		Semester:       c.Query("semester"),
+		LecturerUserID: userID,
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch schedules: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Schedules retrieved successfully", schedules)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/scheduler"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchedulerHandler reports on the recurring background jobs
+type SchedulerHandler struct {
+	jobScheduler *scheduler.Scheduler
+}
+
+// NewSchedulerHandler creates a new SchedulerHandler
+func NewSchedulerHandler(jobScheduler *scheduler.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		jobScheduler: jobScheduler,
+	}
+}
+
+// GetJobs lists the scheduled jobs with their schedule, next run and latest outcome
+func (h *SchedulerHandler) GetJobs(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Scheduled jobs retrieved successfully", h.jobScheduler.Status())
+}
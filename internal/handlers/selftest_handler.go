@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SelfTestHandler exposes the startup self-test report to operators
+type SelfTestHandler struct {
+	selfTest *services.SelfTestService
+}
+
+// NewSelfTestHandler creates a new SelfTestHandler
+func NewSelfTestHandler(selfTest *services.SelfTestService) *SelfTestHandler {
+	return &SelfTestHandler{selfTest: selfTest}
+}
+
+// GetReadinessDetails returns the last self-test report; 503 while it is missing or failing
+func (h *SelfTestHandler) GetReadinessDetails(c *gin.Context) {
+	report := h.selfTest.LastReport()
+	if report == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "pending",
+			"message": "Self-test has not completed yet",
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Status != services.SelfTestOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SemesterArchiveHandler exports and imports complete semester datasets
+type SemesterArchiveHandler struct {
+	archiveService *services.SemesterArchiveService
+	auditService   *services.AuditService
+}
+
+// NewSemesterArchiveHandler creates a new SemesterArchiveHandler
+func NewSemesterArchiveHandler(archiveService *services.SemesterArchiveService, auditService *services.AuditService) *SemesterArchiveHandler {
+	return &SemesterArchiveHandler{
+		archiveService: archiveService,
+		auditService:   auditService,
+	}
+}
+
+// ExportSemester downloads the archive of a semester as a ZIP file
+func (h *SemesterArchiveHandler) ExportSemester(c *gin.Context) {
+	semester := strings.TrimSpace(c.Query("semester"))
+	if semester == "" {
+		utils.BadRequestResponse(c, "semester is required")
+		return
+	}
+
+	exists, err := h.archiveService.HasData(semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check semester: "+err.Error())
+		return
+	}
+	if !exists {
+		utils.NotFoundResponse(c, "No data found for this semester")
+		return
+	}
+
+	// The archive is built in a temporary file first, so a failure can still be reported as JSON
+	file, err := os.CreateTemp("", "semester-archive-*.zip")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create semester archive: "+err.Error())
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	manifest, err := h.archiveService.Export(semester, file)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create semester archive: "+err.Error())
+		return
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to read semester archive: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "semester_archive.export", "semester", 0, map[string]interface{}{
+		"semester": semester,
+		"counts":   manifest.Counts,
+	}))
+
+	fileName := fmt.Sprintf("semester-archive-%s.zip", strings.NewReplacer("/", "-", " ", "-").Replace(semester))
+	c.DataFromReader(http.StatusOK, size, "application/zip", file, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=\"%s\"", fileName),
+	})
+}
+
+// ImportSemester restores a semester archive uploaded as the archive form field. The
+// semester must not have any data yet.
+func (h *SemesterArchiveHandler) ImportSemester(c *gin.Context) {
+	upload, err := c.FormFile("archive")
+	if err != nil {
+		utils.BadRequestResponse(c, "archive file is required")
+		return
+	}
+	if upload.Size > services.MaxSemesterArchiveSize {
+		utils.BadRequestResponse(c, fmt.Sprintf("archive is larger than %d MB", services.MaxSemesterArchiveSize>>20))
+		return
+	}
+	file, err := upload.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read archive")
+		return
+	}
+	defer file.Close()
+
+	manifest, err := h.archiveService.Import(file, upload.Size)
+	if errors.Is(err, repository.ErrSemesterNotEmpty) {
+		utils.ErrorResponse(c, http.StatusConflict, "The semester already has data; import it into an environment without it", nil)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidSemesterArchive) || errors.Is(err, services.ErrInvalidAttachment) {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to import semester archive: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "semester_archive.import", "semester", 0, map[string]interface{}{
+		"semester":    manifest.Semester,
+		"exported_at": manifest.ExportedAt,
+		"counts":      manifest.Counts,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Semester archive imported successfully", manifest)
+}
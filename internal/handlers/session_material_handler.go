@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionMaterialHandler handles the notes and files lecturers attach to attendance sessions
+// and the session detail shown to enrolled students
+type SessionMaterialHandler struct {
+	materialRepo    repository.SessionMaterialRepository
+	attendanceRepo  repository.AttendanceRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	mahasiswaRepo   repository.MahasiswaRepository
+	attachmentStore *services.AttachmentStore
+	campusClient    *utils.CampusClient
+}
+
+// NewSessionMaterialHandler creates a new instance of SessionMaterialHandler
+func NewSessionMaterialHandler(materialRepo repository.SessionMaterialRepository, attendanceRepo repository.AttendanceRepository, enrollmentRepo repository.EnrollmentRepository, mahasiswaRepo repository.MahasiswaRepository, attachmentStore *services.AttachmentStore, campusClient *utils.CampusClient) *SessionMaterialHandler {
+	return &SessionMaterialHandler{
+		materialRepo:    materialRepo,
+		attendanceRepo:  attendanceRepo,
+		enrollmentRepo:  enrollmentRepo,
+		mahasiswaRepo:   mahasiswaRepo,
+		attachmentStore: attachmentStore,
+		campusClient:    campusClient,
+	}
+}
+
+// SessionMaterialRequest is the request body for attaching a note, link or file to a session.
+// It is sent as JSON, or as multipart form data when a file is attached.
+type SessionMaterialRequest struct {
+	Title string `form:"title" json:"title" binding:"required,max=200"`
+	Note  string `form:"note" json:"note" binding:"max=5000"`
+	URL   string `form:"url" json:"url" binding:"max=500"`
+}
+
+// findMaterial loads the session material in the id route parameter together with its session
+func (h *SessionMaterialHandler) findMaterial(c *gin.Context) (*models.SessionMaterial, *models.AttendanceSession) {
+	materialID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil, nil
+	}
+
+	material, err := h.materialRepo.FindByID(materialID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch session material: "+err.Error())
+		return nil, nil
+	}
+	if material == nil {
+		utils.NotFoundResponse(c, "Session material not found")
+		return nil, nil
+	}
+
+	session, err := h.attendanceRepo.FindSessionByID(material.SessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return nil, nil
+	}
+	if session == nil {
+		utils.NotFoundResponse(c, "Session material not found")
+		return nil, nil
+	}
+	return material, session
+}
+
+// isEnrolledIn checks whether the current student is enrolled in the course of a session.
+// It writes the error response when the enrollment cannot be checked.
+func (h *SessionMaterialHandler) isEnrolledIn(c *gin.Context, userID uint, session *models.AttendanceSession) (bool, bool) {
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return false, false
+	}
+	enrolled, err := h.enrollmentRepo.IsEnrolled(nim, session.CourseCode, session.ClassName, session.Semester)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check enrollment: "+err.Error())
+		return false, false
+	}
+	return enrolled, true
+}
+
+// GetMaterials returns the notes and files attached to a session of the current lecturer
+func (h *SessionMaterialHandler) GetMaterials(c *gin.Context) {
+	session := findManagedSession(c, h.attendanceRepo)
+	if session == nil {
+		return
+	}
+
+	materials, err := h.materialRepo.FindBySession(session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch session materials: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session materials retrieved successfully", materials)
+}
+
+// AddMaterial attaches a note, link or file to a session of the current lecturer. The file is
+// sent as the attachment field of a multipart form.
+func (h *SessionMaterialHandler) AddMaterial(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	session := findManagedSession(c, h.attendanceRepo)
+	if session == nil {
+		return
+	}
+
+	var req SessionMaterialRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	material := &models.SessionMaterial{
+		SessionID:       session.ID,
+		Title:           strings.TrimSpace(req.Title),
+		Note:            strings.TrimSpace(req.Note),
+		URL:             strings.TrimSpace(req.URL),
+		CreatedByUserID: userID,
+	}
+	if material.URL != "" {
+		parsed, err := url.Parse(material.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			utils.BadRequestResponse(c, "url must be an http or https link")
+			return
+		}
+	}
+
+	if file, err := c.FormFile("attachment"); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			utils.BadRequestResponse(c, "Failed to read attachment")
+			return
+		}
+		material.AttachmentName, material.AttachmentType, err = h.attachmentStore.Save(src)
+		src.Close()
+		if errors.Is(err, services.ErrInvalidAttachment) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to store attachment: "+err.Error())
+			return
+		}
+	}
+	if material.Note == "" && material.URL == "" && !material.HasAttachment() {
+		utils.BadRequestResponse(c, "A note, url or attachment is required")
+		return
+	}
+
+	if err := h.materialRepo.Create(material); err != nil {
+		if material.HasAttachment() {
+			_ = h.attachmentStore.Delete(material.AttachmentName)
+		}
+		utils.InternalServerErrorResponse(c, "Failed to save session material: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Session material added successfully", material)
+}
+
+// DeleteMaterial removes a note or file from a session of the current lecturer
+func (h *SessionMaterialHandler) DeleteMaterial(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	material, session := h.findMaterial(c)
+	if material == nil {
+		return
+	}
+	if !managesSession(c, userID, session) {
+		utils.NotFoundResponse(c, "Session material not found")
+		return
+	}
+
+	if err := h.materialRepo.Delete(material.ID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete session material: "+err.Error())
+		return
+	}
+	if material.HasAttachment() {
+		if err := h.attachmentStore.Delete(material.AttachmentName); err != nil {
+			utils.LogWarning("SessionMaterialHandler", "DeleteMaterial", "Failed to delete attachment: "+err.Error())
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session material deleted successfully", nil)
+}
+
+// GetMaterialFile downloads the file of a session material for the lecturer of the session or
+// an enrolled student
+func (h *SessionMaterialHandler) GetMaterialFile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	material, session := h.findMaterial(c)
+	if material == nil {
+		return
+	}
+	if !managesSession(c, userID, session) {
+		enrolled, ok := h.isEnrolledIn(c, userID, session)
+		if !ok {
+			return
+		}
+		if !enrolled {
+			utils.NotFoundResponse(c, "Session material not found")
+			return
+		}
+	}
+	if !material.HasAttachment() {
+		utils.NotFoundResponse(c, "Session material has no attachment")
+		return
+	}
+
+	c.Header("Content-Type", material.AttachmentType)
+	c.File(h.attachmentStore.Path(material.AttachmentName))
+}
+
+// GetSessionDetail returns a session of one of the current student's courses with its notes
+// and files and the student's attendance
+func (h *SessionMaterialHandler) GetSessionDetail(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	sessionID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	session, err := h.attendanceRepo.FindSessionByID(sessionID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance session: "+err.Error())
+		return
+	}
+	if session == nil {
+		utils.NotFoundResponse(c, "Attendance session not found")
+		return
+	}
+	enrolled, ok := h.isEnrolledIn(c, userID, session)
+	if !ok {
+		return
+	}
+	if !enrolled {
+		utils.NotFoundResponse(c, "Attendance session not found")
+		return
+	}
+
+	materials, err := h.materialRepo.FindBySession(session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch session materials: "+err.Error())
+		return
+	}
+	record, err := h.attendanceRepo.FindStudentRecord(session.ID, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch attendance: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance session retrieved successfully", models.SessionDetail{
+		Session:   *session,
+		Materials: materials,
+		Record:    record,
+	})
+}
@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
+	"delpresence-api/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ssoPath is where the SSO endpoints live; the refresh and state cookies are scoped to it
+const ssoPath = "/api/v1/auth/sso"
+
+// ssoStateCookie ties the login form to the browser that loaded it, so another site cannot
+// log a user in to an account of its choosing
+const ssoStateCookie = "dp_sso_state"
+
+// ssoLoginPage is the campus login form served by the API, so campus passwords are posted
+// straight to the API and never pass through the dashboard
+var ssoLoginPage = template.Must(template.New("sso_login").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Masuk - DelPresence</title>
+<style>
+body{font-family:sans-serif;background:#f3f4f6;display:flex;justify-content:center;padding-top:10vh;margin:0}
+form{background:#fff;padding:2rem;border-radius:8px;width:320px;box-shadow:0 1px 3px rgba(0,0,0,.15)}
+h1{font-size:1.25rem;margin:0 0 1rem}
+label{display:block;margin-top:.75rem;font-size:.875rem}
+input{width:100%;box-sizing:border-box;padding:.5rem;margin-top:.25rem}
+button{width:100%;margin-top:1.25rem;padding:.6rem;background:#1d4ed8;color:#fff;border:0;border-radius:4px}
+.error{color:#b91c1c;font-size:.875rem}
+</style>
+</head>
+<body>
+<form method="post" action="{{.Action}}">
+<h1>Masuk dengan akun CIS</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<input type="hidden" name="return_to" value="{{.ReturnTo}}">
+<input type="hidden" name="state" value="{{.State}}">
+<label>Username<input name="username" value="{{.Username}}" autocomplete="username" required autofocus></label>
+<label>Password<input type="password" name="password" autocomplete="current-password" required></label>
+<button type="submit">Masuk</button>
+</form>
+</body>
+</html>
+`))
+
+// ssoLoginView is the data of the login form
+type ssoLoginView struct {
+	Action   string
+	ReturnTo string
+	State    string
+	Username string
+	Error    string
+}
+
+// SSOHandler runs the campus login of the web dashboard. The API performs the CIS login
+// itself and keeps the session in HttpOnly cookies, refreshing it server-side, so the
+// dashboard never handles campus passwords or tokens.
+type SSOHandler struct {
+	tokenRepo      *repository.TokenRepository
+	userRoleRepo   repository.UserRoleRepository
+	credentialRepo repository.CampusCredentialRepository
+	auditService   *services.AuditService
+	allowedOrigins []string
+	cookies        config.SessionConfig
+}
+
+// NewSSOHandler creates a new instance of SSOHandler. Logins only return to the dashboard
+// origins in allowedOrigins.
+func NewSSOHandler(userRoleRepo repository.UserRoleRepository, credentialRepo repository.CampusCredentialRepository, auditService *services.AuditService, allowedOrigins []string, cookies config.SessionConfig) *SSOHandler {
+	return &SSOHandler{
+		tokenRepo:      repository.NewTokenRepository(),
+		userRoleRepo:   userRoleRepo,
+		credentialRepo: credentialRepo,
+		auditService:   auditService,
+		allowedOrigins: allowedOrigins,
+		cookies:        cookies,
+	}
+}
+
+// SSORoleRequest is the request body for switching the active role of a dashboard session
+type SSORoleRequest struct {
+	Role models.UserType `json:"role" binding:"required"`
+}
+
+// LoginPage serves the campus login form. return_to is the dashboard page to go back to.
+func (h *SSOHandler) LoginPage(c *gin.Context) {
+	returnTo, ok := h.returnTo(c.Query("return_to"))
+	if !ok {
+		utils.BadRequestResponse(c, "return_to must be a page of the dashboard")
+		return
+	}
+
+	h.renderLogin(c, http.StatusOK, ssoLoginView{ReturnTo: returnTo})
+}
+
+// Login performs the CIS login with the submitted form, starts the dashboard session and
+// redirects back to the dashboard. Failed logins show the form again.
+func (h *SSOHandler) Login(c *gin.Context) {
+	returnTo, ok := h.returnTo(c.PostForm("return_to"))
+	if !ok {
+		utils.BadRequestResponse(c, "return_to must be a page of the dashboard")
+		return
+	}
+
+	state, err := c.Cookie(ssoStateCookie)
+	if err != nil || !hmac.Equal([]byte(state), []byte(c.PostForm("state"))) {
+		// The form expired or was not loaded by this browser
+		h.renderLogin(c, http.StatusForbidden, ssoLoginView{ReturnTo: returnTo, Error: "Sesi login kedaluwarsa, silakan coba lagi."})
+		return
+	}
+
+	username := strings.TrimSpace(c.PostForm("username"))
+	password := c.PostForm("password")
+	if username == "" || password == "" {
+		h.renderLogin(c, http.StatusBadRequest, ssoLoginView{ReturnTo: returnTo, Username: username, Error: "Username dan password wajib diisi."})
+		return
+	}
+
+	campusResponse, err := campusAuthenticate(username, password)
+	if err != nil {
+		utils.LogError("SSOHandler", "Login", err)
+		h.renderLogin(c, http.StatusBadGateway, ssoLoginView{ReturnTo: returnTo, Username: username, Error: "CIS tidak dapat dihubungi, silakan coba lagi."})
+		return
+	}
+	if !campusResponse.Result {
+		h.renderLogin(c, http.StatusUnauthorized, ssoLoginView{ReturnTo: returnTo, Username: username, Error: "Username atau password salah."})
+		return
+	}
+
+	if err := saveCampusCredential(h.credentialRepo, username, campusResponse); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to store campus credential: "+err.Error())
+		return
+	}
+
+	campusUserID := campusResponse.User.UserID
+	roles, activeRole, err := campusRoles(h.userRoleRepo, campusUserID, "")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+
+	refreshToken, refreshExpiresAt, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate refresh token")
+		return
+	}
+	if err := h.tokenRepo.CreateCampusSessionToken(uint(campusUserID), jwt.HashRefreshToken(refreshToken), activeRole, refreshExpiresAt); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to store refresh token")
+		return
+	}
+	if _, err := h.setSession(c, campusUserID, campusResponse.User.Email, roles, activeRole, refreshToken, refreshExpiresAt); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate token")
+		return
+	}
+	h.clearCookie(c, ssoStateCookie, ssoPath)
+
+	recordCampusLogin(h.auditService, c, campusResponse.User, "dashboard")
+	c.Redirect(http.StatusSeeOther, returnTo)
+}
+
+// Session returns who the dashboard session belongs to and the CSRF token its unsafe
+// requests must send in the X-CSRF-Token header
+func (h *SSOHandler) Session(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	sessionToken, err := c.Cookie(auth.SessionCookie)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "No dashboard session")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session retrieved successfully", gin.H{
+		"campus_user_id": principal.CampusUserID,
+		"active_role":    principal.ActiveRole,
+		"roles":          principal.Roles,
+		"csrf_token":     jwt.CSRFToken(sessionToken),
+	})
+}
+
+// Refresh renews the session cookies with the refresh cookie. The refresh token is rotated,
+// so each one can only be used once.
+func (h *SSOHandler) Refresh(c *gin.Context) {
+	h.renew(c, "")
+}
+
+// SwitchRole renews the session cookies for another role linked to the account
+func (h *SSOHandler) SwitchRole(c *gin.Context) {
+	var req SSORoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	h.renew(c, string(req.Role))
+}
+
+// Logout revokes the refresh token of the session and clears its cookies. Requests without
+// a session are accepted so the call is idempotent.
+func (h *SSOHandler) Logout(c *gin.Context) {
+	if refreshToken, err := c.Cookie(auth.SessionRefreshCookie); err == nil {
+		if err := h.tokenRepo.DeleteToken(jwt.HashRefreshToken(refreshToken)); err != nil && !errors.Is(err, repository.ErrTokenNotFound) {
+			utils.InternalServerErrorResponse(c, "Failed to revoke refresh token")
+			return
+		}
+	}
+
+	h.clearCookie(c, auth.SessionCookie, "/")
+	h.clearCookie(c, auth.SessionRefreshCookie, ssoPath)
+	utils.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// renew rotates the refresh cookie and issues a new session for role, or for the role of
+// the current session when role is empty
+func (h *SSOHandler) renew(c *gin.Context, role string) {
+	refreshToken, err := c.Cookie(auth.SessionRefreshCookie)
+	if err != nil {
+		utils.UnauthorizedResponse(c, "No dashboard session")
+		return
+	}
+
+	hashed := jwt.HashRefreshToken(refreshToken)
+	stored, err := h.tokenRepo.GetTokenByValue(hashed, models.CampusSessionToken)
+	switch {
+	case errors.Is(err, repository.ErrTokenExpired):
+		utils.UnauthorizedResponse(c, "Session has expired")
+		return
+	case errors.Is(err, repository.ErrTokenNotFound):
+		revokeReusedToken(h.tokenRepo, hashed, models.CampusSessionToken)
+		utils.UnauthorizedResponse(c, "Invalid session")
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to validate session")
+		return
+	}
+
+	if role == "" {
+		role = stored.ActiveRole
+	}
+	campusUserID := int(stored.UserID)
+	roles, activeRole, err := campusRoles(h.userRoleRepo, campusUserID, role)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load roles")
+		return
+	}
+	if role != "" && activeRole != role {
+		if role == stored.ActiveRole {
+			// The role was unlinked after the session started
+			h.tokenRepo.DeleteToken(hashed)
+			utils.UnauthorizedResponse(c, "Role is no longer linked to this account")
+			return
+		}
+		utils.ForbiddenResponse(c, "Role is not linked to this account")
+		return
+	}
+
+	newRefreshToken, refreshExpiresAt, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate refresh token")
+		return
+	}
+	stored.ActiveRole = activeRole
+	if _, err := h.tokenRepo.RotateToken(stored, jwt.HashRefreshToken(newRefreshToken), refreshExpiresAt); err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) {
+			// Another request rotated the same token first
+			utils.UnauthorizedResponse(c, "Invalid session")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to rotate refresh token")
+		return
+	}
+
+	sessionToken, err := h.setSession(c, campusUserID, "", roles, activeRole, newRefreshToken, refreshExpiresAt)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session refreshed successfully", gin.H{
+		"campus_user_id": campusUserID,
+		"active_role":    activeRole,
+		"roles":          roles,
+		"csrf_token":     jwt.CSRFToken(sessionToken),
+	})
+}
+
+// setSession issues an access token for a campus user and sets it and the refresh token as
+// the session cookies. It returns the access token.
+func (h *SSOHandler) setSession(c *gin.Context, campusUserID int, email string, roles []string, activeRole, refreshToken string, refreshExpiresAt time.Time) (string, error) {
+	accessToken, expiresAt, err := jwt.GenerateRoleToken(uint(campusUserID), campusUserID, email, roles, activeRole)
+	if err != nil {
+		return "", err
+	}
+
+	h.setCookie(c, auth.SessionCookie, accessToken, "/", expiresAt)
+	h.setCookie(c, auth.SessionRefreshCookie, refreshToken, ssoPath, refreshExpiresAt)
+	return accessToken, nil
+}
+
+// renderLogin writes the login form with a fresh state
+func (h *SSOHandler) renderLogin(c *gin.Context, status int, view ssoLoginView) {
+	state := make([]byte, 16)
+	if _, err := rand.Read(state); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start login")
+		return
+	}
+	view.Action = ssoPath + "/login"
+	view.State = hex.EncodeToString(state)
+	h.setCookie(c, ssoStateCookie, view.State, ssoPath, time.Now().Add(15*time.Minute))
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	// The form must not be framed by other sites, which could capture what is typed into it
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	if err := ssoLoginPage.Execute(c.Writer, view); err != nil {
+		utils.LogError("SSOHandler", "renderLogin", err)
+	}
+}
+
+// returnTo checks that a return address is on one of the dashboard origins. An empty
+// address returns to the first dashboard origin.
+func (h *SSOHandler) returnTo(raw string) (string, bool) {
+	if raw == "" {
+		if len(h.allowedOrigins) == 0 {
+			return "", false
+		}
+		return strings.TrimSpace(h.allowedOrigins[0]), true
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", false
+	}
+	origin := parsed.Scheme + "://" + parsed.Host
+	for _, allowed := range h.allowedOrigins {
+		if strings.TrimRight(strings.TrimSpace(allowed), "/") == origin {
+			return raw, true
+		}
+	}
+	return "", false
+}
+
+// setCookie sets an HttpOnly cookie until expiresAt
+func (h *SSOHandler) setCookie(c *gin.Context, name, value, path string, expiresAt time.Time) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(name, value, int(time.Until(expiresAt).Seconds()), path, h.cookies.CookieDomain, h.cookies.SecureCookie, true)
+}
+
+// clearCookie removes a cookie set by setCookie
+func (h *SSOHandler) clearCookie(c *gin.Context, name, path string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(name, "", -1, path, h.cookies.CookieDomain, h.cookies.SecureCookie, true)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusHandler serves the outage and maintenance banners of the clients and lets admins
+// publish them
+type StatusHandler struct {
+	statusRepo    repository.StatusMessageRepository
+	statusService *services.StatusService
+	auditService  *services.AuditService
+}
+
+// NewStatusHandler creates a new StatusHandler
+func NewStatusHandler(statusRepo repository.StatusMessageRepository, statusService *services.StatusService, auditService *services.AuditService) *StatusHandler {
+	return &StatusHandler{
+		statusRepo:    statusRepo,
+		statusService: statusService,
+		auditService:  auditService,
+	}
+}
+
+// StatusMessageRequest is the request body for publishing or changing a status message
+type StatusMessageRequest struct {
+	Kind     models.StatusKind     `json:"kind" binding:"required"`
+	Severity models.StatusSeverity `json:"severity" binding:"required"`
+	Title    string                `json:"title" binding:"required,max=150"`
+	Message  string                `json:"message"`
+	StartsAt *time.Time            `json:"starts_at"` // Defaults to now
+	EndsAt   *time.Time            `json:"ends_at"`   // Empty until the message is ended
+}
+
+// apply copies the request onto message
+func (req *StatusMessageRequest) apply(message *models.StatusMessage) {
+	message.Kind = req.Kind
+	message.Severity = req.Severity
+	message.Title = req.Title
+	message.Message = req.Message
+	message.StartsAt = time.Now()
+	if req.StartsAt != nil {
+		message.StartsAt = *req.StartsAt
+	}
+	message.EndsAt = req.EndsAt
+}
+
+// GetStatus returns the status messages clients show as banners. It does not require a login
+// and is polled by the apps, so it is served from a short-lived cache.
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	board, err := h.statusService.Board()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load status messages: "+err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=15")
+	utils.SuccessResponse(c, http.StatusOK, "Status retrieved successfully", board)
+}
+
+// ListMessages lists recent status messages, past ones included
+func (h *StatusHandler) ListMessages(c *gin.Context) {
+	messages, err := h.statusRepo.FindRecent(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch status messages: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Status messages retrieved successfully", messages)
+}
+
+// CreateMessage publishes a status message, shown right away or from its scheduled start
+func (h *StatusHandler) CreateMessage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req StatusMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	message := &models.StatusMessage{CreatedBy: userID, UpdatedBy: userID}
+	req.apply(message)
+	if err := message.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.statusRepo.Create(message); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create status message: "+err.Error())
+		return
+	}
+	h.statusService.Invalidate()
+
+	h.auditService.Record(newAuditEntry(c, "status_message.create", "status_message", message.ID, map[string]interface{}{
+		"kind":     message.Kind,
+		"severity": message.Severity,
+		"title":    message.Title,
+	}))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Status message created successfully", message)
+}
+
+// UpdateMessage changes a status message, e.g. to end an incident by setting ends_at
+func (h *StatusHandler) UpdateMessage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var req StatusMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	message, err := h.statusRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch status message: "+err.Error())
+		return
+	}
+	if message == nil {
+		utils.NotFoundResponse(c, "Status message not found")
+		return
+	}
+
+	// A message already shown keeps its start unless a new one is given
+	startsAt := message.StartsAt
+	req.apply(message)
+	if req.StartsAt == nil {
+		message.StartsAt = startsAt
+	}
+	message.UpdatedBy = userID
+	if err := message.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.statusRepo.Update(message); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update status message: "+err.Error())
+		return
+	}
+	h.statusService.Invalidate()
+
+	h.auditService.Record(newAuditEntry(c, "status_message.update", "status_message", message.ID, map[string]interface{}{
+		"severity": message.Severity,
+		"ends_at":  message.EndsAt,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Status message updated successfully", message)
+}
+
+// DeleteMessage removes a status message
+func (h *StatusHandler) DeleteMessage(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	message, err := h.statusRepo.FindByID(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch status message: "+err.Error())
+		return
+	}
+	if message == nil {
+		utils.NotFoundResponse(c, "Status message not found")
+		return
+	}
+
+	if err := h.statusRepo.Delete(message.ID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete status message: "+err.Error())
+		return
+	}
+	h.statusService.Invalidate()
+
+	h.auditService.Record(newAuditEntry(c, "status_message.delete", "status_message", message.ID, map[string]interface{}{
+		"title": message.Title,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Status message deleted successfully", nil)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SudoHandler handles re-authentication of admins before destructive actions
+type SudoHandler struct {
+	adminRepo    *repository.AdminRepository
+	auditService *services.AuditService
+}
+
+// NewSudoHandler creates a new SudoHandler
+func NewSudoHandler(auditService *services.AuditService) *SudoHandler {
+	return &SudoHandler{
+		adminRepo:    repository.NewAdminRepository(),
+		auditService: auditService,
+	}
+}
+
+// SudoRequest is the request body for entering sudo mode
+type SudoRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// Elevate re-checks the admin's password and issues a short-lived elevation token
+func (h *SudoHandler) Elevate(c *gin.Context) {
+	principal, ok := auth.FromContext(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User tidak terautentikasi")
+		return
+	}
+
+	var req SudoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Password wajib diisi")
+		return
+	}
+
+	adminWithUser, err := h.adminRepo.GetAdminByUserID(principal.UserID)
+	if err != nil {
+		utils.NotFoundResponse(c, "Profil admin tidak ditemukan")
+		return
+	}
+
+	if !adminWithUser.User.ComparePassword(req.Password) {
+		h.auditService.Record(newAuditEntry(c, "sudo.denied", "user", principal.UserID, nil))
+		utils.UnauthorizedResponse(c, "Password salah")
+		return
+	}
+
+	token, expiresAt, err := jwt.GenerateSudoToken(principal.UserID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Gagal membuat token sudo")
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "sudo.granted", "user", principal.UserID, map[string]interface{}{
+		"expires_at": expiresAt,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Sudo mode aktif", gin.H{
+		"sudo_token": token,
+		"header":     "X-Sudo-Token",
+		"expires_at": expiresAt,
+	})
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SupervisionHandler handles thesis supervision meeting logs
+type SupervisionHandler struct {
+	supervisionRepo repository.SupervisionRepository
+	mahasiswaRepo   repository.MahasiswaRepository
+	approvalRouter  *services.ApprovalRouter
+	workflow        *services.WorkflowEngine
+	bus             *events.Bus
+	campusClient    *utils.CampusClient
+}
+
+// NewSupervisionHandler creates a new instance of SupervisionHandler
+func NewSupervisionHandler(supervisionRepo repository.SupervisionRepository, mahasiswaRepo repository.MahasiswaRepository, approvalRouter *services.ApprovalRouter, workflow *services.WorkflowEngine, bus *events.Bus, campusClient *utils.CampusClient) *SupervisionHandler {
+	return &SupervisionHandler{
+		supervisionRepo: supervisionRepo,
+		mahasiswaRepo:   mahasiswaRepo,
+		approvalRouter:  approvalRouter,
+		workflow:        workflow,
+		bus:             bus,
+		campusClient:    campusClient,
+	}
+}
+
+// LogMeeting records a supervision meeting by the current student
+func (h *SupervisionHandler) LogMeeting(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req struct {
+		SupervisorUserID uint   `json:"supervisor_user_id" binding:"required"`
+		MeetingDate      string `json:"meeting_date" binding:"required"`
+		Topic            string `json:"topic" binding:"required"`
+		Notes            string `json:"notes"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	meetingDate, err := time.Parse("2006-01-02", req.MeetingDate)
+	if err != nil {
+		utils.BadRequestResponse(c, "meeting_date must use the YYYY-MM-DD format")
+		return
+	}
+	if meetingDate.After(time.Now()) {
+		utils.BadRequestResponse(c, "meeting_date cannot be in the future")
+		return
+	}
+
+	nim, err := resolveStudentNIM(c.Request.Context(), h.mahasiswaRepo, h.campusClient, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to resolve student NIM", err.Error())
+		return
+	}
+
+	meeting := &models.SupervisionMeeting{
+		StudentUserID:    userID,
+		Nim:              nim,
+		SupervisorUserID: req.SupervisorUserID,
+		MeetingDate:      meetingDate,
+		Topic:            req.Topic,
+		Notes:            req.Notes,
+		Status:           models.SupervisionPending,
+	}
+
+	if err := h.supervisionRepo.Create(meeting); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save supervision meeting: "+err.Error())
+		return
+	}
+
+	approver := h.approvalRouter.Approver(meeting.SupervisorUserID, models.SupervisionApprovals)
+	if instance, err := h.workflow.Start(supervisionSubject(meeting)); err != nil {
+		// The workflow is started on the first decision instead
+		requestLog(c).Errorf("Failed to start workflow for meeting %d: %v", meeting.ID, err)
+	} else {
+		approver = instance.AssigneeUserID
+	}
+
+	h.bus.Publish(events.SupervisionMeetingLogged{
+		Actor:          eventActor(c),
+		Meeting:        *meeting,
+		ApproverUserID: approver,
+	})
+
+	utils.SuccessResponse(c, http.StatusCreated, "Supervision meeting logged successfully", meeting)
+}
+
+// GetMyMeetings returns the current student's supervision meetings
+func (h *SupervisionHandler) GetMyMeetings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	meetings, err := h.supervisionRepo.FindByStudent(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch supervision meetings: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Supervision meetings retrieved successfully", meetings)
+}
+
+// GetSupervisedMeetings returns the meetings logged with the current lecturer as supervisor,
+// plus the pending meetings of lecturers who delegated their approvals to them
+func (h *SupervisionHandler) GetSupervisedMeetings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	status := models.SupervisionStatus(c.Query("status"))
+	meetings, err := h.supervisionRepo.FindBySupervisors([]uint{userID}, status)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch supervision meetings: "+err.Error())
+		return
+	}
+
+	// Delegates only see what still needs a decision, not the delegator's history
+	if status == "" || status == models.SupervisionPending {
+		if delegators := h.approvalRouter.DelegatorsOf(userID, models.SupervisionApprovals); len(delegators) > 0 {
+			delegated, err := h.supervisionRepo.FindBySupervisors(delegators, models.SupervisionPending)
+			if err != nil {
+				utils.InternalServerErrorResponse(c, "Failed to fetch delegated supervision meetings: "+err.Error())
+				return
+			}
+			meetings = append(meetings, delegated...)
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Supervision meetings retrieved successfully", meetings)
+}
+
+// ConfirmMeeting confirms a pending meeting with one click
+func (h *SupervisionHandler) ConfirmMeeting(c *gin.Context) {
+	h.decideMeeting(c, services.SupervisionConfirmAction)
+}
+
+// RejectMeeting rejects a pending meeting
+func (h *SupervisionHandler) RejectMeeting(c *gin.Context) {
+	h.decideMeeting(c, services.SupervisionRejectAction)
+}
+
+// supervisionSubject identifies the workflow of a supervision meeting
+func supervisionSubject(meeting *models.SupervisionMeeting) services.WorkflowSubject {
+	return services.WorkflowSubject{
+		Type:            services.SupervisionWorkflowType,
+		ID:              meeting.ID,
+		RequesterUserID: meeting.StudentUserID,
+		OwnerUserID:     meeting.SupervisorUserID,
+	}
+}
+
+// decideMeeting applies the supervisor's decision to a pending meeting through its workflow
+func (h *SupervisionHandler) decideMeeting(c *gin.Context, action string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	meetingID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req struct {
+		Note string `json:"note"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	meeting, err := h.supervisionRepo.FindByID(meetingID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch supervision meeting: "+err.Error())
+		return
+	}
+	if meeting == nil {
+		utils.NotFoundResponse(c, "Supervision meeting not found")
+		return
+	}
+	if meeting.Status != models.SupervisionPending {
+		utils.ErrorResponse(c, http.StatusConflict, "Supervision meeting has already been "+string(meeting.Status), nil)
+		return
+	}
+
+	instance, err := h.workflow.Act(supervisionSubject(meeting), userID, action, req.Note)
+	switch {
+	case errors.Is(err, services.ErrNotAssignee):
+		utils.NotFoundResponse(c, "Supervision meeting not found")
+		return
+	case errors.Is(err, services.ErrInvalidTransition):
+		utils.ErrorResponse(c, http.StatusConflict, "Supervision meeting has already been decided", nil)
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to update supervision workflow: "+err.Error())
+		return
+	}
+
+	meeting.Status = models.SupervisionStatus(instance.State)
+	meeting.SupervisorNote = req.Note
+	meeting.DecidedAt = instance.CompletedAt
+
+	if err := h.supervisionRepo.Update(meeting); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update supervision meeting: "+err.Error())
+		return
+	}
+
+	h.bus.Publish(events.SupervisionMeetingDecided{Actor: eventActor(c), Meeting: *meeting, Note: req.Note})
+
+	utils.SuccessResponse(c, http.StatusOK, "Supervision meeting "+string(meeting.Status), meeting)
+}
+
+// GetFrequencyReport returns supervision frequency per lecturer for a prodi
+func (h *SupervisionHandler) GetFrequencyReport(c *gin.Context) {
+	var departmentID uint
+	if prodiID := c.Query("prodi_id"); prodiID != "" {
+		id, err := strconv.ParseUint(prodiID, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid prodi_id")
+			return
+		}
+		departmentID = uint(id)
+	}
+
+	rows, err := h.supervisionRepo.FrequencyByLecturer(departmentID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build supervision report: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Supervision frequency report generated successfully", gin.H{
+		"prodi_id": departmentID,
+		"from":     c.Query("from"),
+		"to":       c.Query("to"),
+		"rows":     rows,
+	})
+}
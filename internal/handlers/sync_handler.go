@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHandler handles bulk syncs of profiles from the campus API
+type SyncHandler struct {
+	lecturerSync *services.LecturerSyncService
+	prodiSync    *services.ProdiSyncService
+	auditService *services.AuditService
+}
+
+// NewSyncHandler creates a new SyncHandler
+func NewSyncHandler(lecturerSync *services.LecturerSyncService, prodiSync *services.ProdiSyncService, auditService *services.AuditService) *SyncHandler {
+	return &SyncHandler{
+		lecturerSync: lecturerSync,
+		prodiSync:    prodiSync,
+		auditService: auditService,
+	}
+}
+
+// ListLecturerSyncs lists recent lecturer syncs with their counts
+func (h *SyncHandler) ListLecturerSyncs(c *gin.Context) {
+	runs, err := h.lecturerSync.Recent(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load lecturer syncs: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Lecturer syncs retrieved successfully", runs)
+}
+
+// TriggerLecturerSync starts importing every lecturer of the campus API
+func (h *SyncHandler) TriggerLecturerSync(c *gin.Context) {
+	triggeredBy, _ := currentUserID(c)
+	run, err := h.lecturerSync.Trigger(triggeredBy)
+	if errors.Is(err, services.ErrSyncRunning) {
+		utils.ErrorResponse(c, http.StatusConflict, "A lecturer sync is already running", nil)
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start lecturer sync: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "sync.lecturers", "sync_run", run.ID, nil))
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Lecturer sync started", run)
+}
+
+// ListProdiSyncs lists recent prodi re-syncs with their counts
+func (h *SyncHandler) ListProdiSyncs(c *gin.Context) {
+	runs, err := h.prodiSync.Recent(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load prodi syncs: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prodi syncs retrieved successfully", runs)
+}
+
+// TriggerProdiSync queues every local lecturer, assistant and student profile of a prodi for
+// a re-sync from the campus API
+func (h *SyncHandler) TriggerProdiSync(c *gin.Context) {
+	prodiID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	triggeredBy, _ := currentUserID(c)
+	run, err := h.prodiSync.Trigger(prodiID, triggeredBy)
+	switch {
+	case errors.Is(err, services.ErrSyncRunning):
+		utils.ErrorResponse(c, http.StatusConflict, "A sync of this prodi is already running", nil)
+		return
+	case errors.Is(err, services.ErrNoProdiProfiles):
+		utils.NotFoundResponse(c, "No synced profiles found in this prodi")
+		return
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to start prodi sync: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "sync.prodi", "sync_run", run.ID, map[string]interface{}{
+		"prodi_id": prodiID,
+		"profiles": run.Fetched,
+	}))
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Prodi sync started", run)
+}
+
+// GetProdiSyncProgress returns the counts of a prodi re-sync and how many profiles are left
+func (h *SyncHandler) GetProdiSyncProgress(c *gin.Context) {
+	run := h.findProdiSync(c)
+	if run == nil {
+		return
+	}
+
+	progress := 100.0
+	if run.Fetched > 0 {
+		progress = float64(run.Fetched-run.Pending()) * 100 / float64(run.Fetched)
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Prodi sync retrieved successfully", gin.H{
+		"run":      run,
+		"pending":  run.Pending(),
+		"progress": progress,
+	})
+}
+
+// GetProdiSyncErrors lists the profiles of a prodi re-sync that could not be synced
+func (h *SyncHandler) GetProdiSyncErrors(c *gin.Context) {
+	run := h.findProdiSync(c)
+	if run == nil {
+		return
+	}
+
+	items, err := h.prodiSync.Errors(run.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load prodi sync errors: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prodi sync errors retrieved successfully", items)
+}
+
+// findProdiSync loads the prodi re-sync in the id route parameter, writing the error response
+// and returning nil when there is none
+func (h *SyncHandler) findProdiSync(c *gin.Context) *models.SyncRun {
+	runID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return nil
+	}
+
+	run, err := h.prodiSync.Progress(runID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load prodi sync: "+err.Error())
+		return nil
+	}
+	if run == nil {
+		utils.NotFoundResponse(c, "Prodi sync not found")
+		return nil
+	}
+	return run
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/metrics"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultUsageGrouping is used when no group_by is given
+	defaultUsageGrouping = "route,api_key,prodi"
+	// defaultRollupGrouping is used when no group_by is given for the usage rollup
+	defaultRollupGrouping = "route,role"
+	// usageRollupLimit caps the rows of a usage rollup report
+	usageRollupLimit = 500
+)
+
+// UsageHandler reports API usage recorded by the usage middleware and manages endpoint quotas
+type UsageHandler struct {
+	registry     *metrics.UsageRegistry
+	usageService *services.UsageService
+	auditService *services.AuditService
+}
+
+// NewUsageHandler creates a new UsageHandler
+func NewUsageHandler(registry *metrics.UsageRegistry, usageService *services.UsageService, auditService *services.AuditService) *UsageHandler {
+	return &UsageHandler{
+		registry:     registry,
+		usageService: usageService,
+		auditService: auditService,
+	}
+}
+
+// GetUsageSummary returns request counts grouped by the dimensions in group_by
+// (route, method, status, api_key, prodi)
+func (h *UsageHandler) GetUsageSummary(c *gin.Context) {
+	var groupBy []string
+	for _, dimension := range strings.Split(c.DefaultQuery("group_by", defaultUsageGrouping), ",") {
+		dimension = strings.TrimSpace(dimension)
+		if dimension == "" {
+			continue
+		}
+		if !metrics.IsUsageDimension(dimension) {
+			utils.BadRequestResponse(c, "Unknown group_by dimension: "+dimension)
+			return
+		}
+		groupBy = append(groupBy, dimension)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Usage summary retrieved successfully", gin.H{
+		"since":    h.registry.StartedAt(),
+		"group_by": groupBy,
+		"usage":    h.registry.Summary(groupBy),
+	})
+}
+
+// GetUsage returns the per-endpoint, per-user request counts and latencies recorded between
+// from and to (YYYY-MM-DD, inclusive), grouped by the dimensions in group_by
+// (route, method, user, role)
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	var groupBy []string
+	for _, dimension := range strings.Split(c.DefaultQuery("group_by", defaultRollupGrouping), ",") {
+		dimension = strings.TrimSpace(dimension)
+		if dimension == "" {
+			continue
+		}
+		if _, ok := models.UsageDimensions[dimension]; !ok {
+			utils.BadRequestResponse(c, "Unknown group_by dimension: "+dimension)
+			return
+		}
+		groupBy = append(groupBy, dimension)
+	}
+
+	usage, err := h.usageService.Summary(from, to.Add(24*time.Hour), groupBy, usageRollupLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load API usage: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API usage retrieved successfully", gin.H{
+		"group_by": groupBy,
+		"usage":    usage,
+	})
+}
+
+// ListQuotas lists the request quotas of expensive endpoints
+func (h *UsageHandler) ListQuotas(c *gin.Context) {
+	quotas, err := h.usageService.Quotas()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load quotas: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quotas retrieved successfully", quotas)
+}
+
+// UsageQuotaRequest is the request body for setting the quota of an endpoint
+type UsageQuotaRequest struct {
+	Route       string             `json:"route" binding:"required"` // Route pattern, e.g. /api/v1/mahasiswa/complete
+	Role        string             `json:"role"`                     // Empty for every role without its own quota
+	MaxRequests int                `json:"max_requests" binding:"required"`
+	Window      models.QuotaWindow `json:"window" binding:"required"`
+}
+
+// SetQuota creates or replaces the quota of an endpoint for a role
+func (h *UsageHandler) SetQuota(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req UsageQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+	if req.Role != "" && !models.UserType(req.Role).IsValid() {
+		utils.BadRequestResponse(c, "Unknown role: "+req.Role)
+		return
+	}
+
+	quota := &models.UsageQuota{
+		Route:       req.Route,
+		Role:        req.Role,
+		MaxRequests: req.MaxRequests,
+		Window:      req.Window,
+		UpdatedBy:   userID,
+	}
+	if err := quota.Validate(); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	if err := h.usageService.SaveQuota(quota); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save quota: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "usage_quota.set", "usage_quota", quota.ID, map[string]interface{}{
+		"route":        quota.Route,
+		"role":         quota.Role,
+		"max_requests": quota.MaxRequests,
+		"window":       quota.Window,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Quota saved successfully", quota)
+}
+
+// DeleteQuota removes the quota of an endpoint
+func (h *UsageHandler) DeleteQuota(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	quota, err := h.usageService.FindQuota(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch quota: "+err.Error())
+		return
+	}
+	if quota == nil {
+		utils.NotFoundResponse(c, "Quota not found")
+		return
+	}
+
+	if err := h.usageService.DeleteQuota(id); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete quota: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "usage_quota.delete", "usage_quota", quota.ID, map[string]interface{}{
+		"route": quota.Route,
+		"role":  quota.Role,
+	}))
+
+	utils.SuccessResponse(c, http.StatusOK, "Quota deleted successfully", nil)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler lets admins browse local user accounts
+type UserHandler struct {
+	userRepo *repository.UserRepository
+}
+
+// NewUserHandler creates a new instance of UserHandler
+func NewUserHandler() *UserHandler {
+	return &UserHandler{
+		userRepo: repository.NewUserRepository(),
+	}
+}
+
+// userListOptions are the sort, filter and search parameters of the user list
+var userListOptions = utils.PageOptions{
+	Sorts: map[string]string{
+		"id":         "id",
+		"name":       "first_name",
+		"email":      "email",
+		"user_type":  "user_type",
+		"last_login": "last_login",
+		"created_at": "created_at",
+	},
+	Filters: map[string]string{
+		"user_type": "user_type",
+		"active":    "active",
+		"verified":  "verified",
+	},
+	Search:      []string{"first_name", "last_name", "email", "username"},
+	DefaultSort: "-created_at",
+}
+
+// ListUsers returns one page of user accounts
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	query, err := utils.ParsePageQuery(c, userListOptions)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	users, total, err := h.userRepo.ListUsers(query)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch users: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", utils.NewPageResult(users, total, query))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerificationHandler lets anyone check the verification code printed on a certificate or
+// exported report
+type VerificationHandler struct {
+	verification *services.VerificationService
+}
+
+// NewVerificationHandler creates a new instance of VerificationHandler
+func NewVerificationHandler(verification *services.VerificationService) *VerificationHandler {
+	return &VerificationHandler{
+		verification: verification,
+	}
+}
+
+// Verify returns the issuer, subject and issue date of the document with a code. It is public,
+// so nothing beyond what is printed on the document is returned.
+func (h *VerificationHandler) Verify(c *gin.Context) {
+	verification, err := h.verification.Verify(c.Param("code"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to verify document: "+err.Error())
+		return
+	}
+	if verification == nil {
+		utils.NotFoundResponse(c, "No document was issued with this code")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Document verified", verification)
+}
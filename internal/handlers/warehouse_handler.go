@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WarehouseHandler handles the daily snapshots for the data warehouse
+type WarehouseHandler struct {
+	warehouseRepo    repository.WarehouseRepository
+	warehouseService *services.WarehouseExportService
+	auditService     *services.AuditService
+}
+
+// NewWarehouseHandler creates a new WarehouseHandler
+func NewWarehouseHandler(warehouseRepo repository.WarehouseRepository, warehouseService *services.WarehouseExportService, auditService *services.AuditService) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseRepo:    warehouseRepo,
+		warehouseService: warehouseService,
+		auditService:     auditService,
+	}
+}
+
+// WarehouseSnapshotRequest is the request body for writing a warehouse snapshot again
+type WarehouseSnapshotRequest struct {
+	Date string `json:"date"` // YYYY-MM-DD; yesterday when empty
+}
+
+// ListSnapshots lists recent warehouse snapshot runs
+func (h *WarehouseHandler) ListSnapshots(c *gin.Context) {
+	snapshots, err := h.warehouseRepo.FindRecent(recentOperationsLimit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load warehouse snapshots")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Warehouse snapshots retrieved successfully", snapshots)
+}
+
+// TriggerSnapshot writes the snapshot of a day in the background, replacing earlier files of
+// that day
+func (h *WarehouseHandler) TriggerSnapshot(c *gin.Context) {
+	var req WarehouseSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		utils.BadRequestResponse(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	day := services.Yesterday(time.Now())
+	if req.Date != "" {
+		var err error
+		if day, err = services.ParseWarehouseDate(req.Date); err != nil {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+	}
+
+	triggeredBy, _ := currentUserID(c)
+	snapshot, err := h.warehouseService.Trigger(day, triggeredBy)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start warehouse snapshot: "+err.Error())
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, "warehouse_snapshot.trigger", "warehouse_snapshot", snapshot.ID, map[string]interface{}{
+		"snapshot_date": snapshot.SnapshotDate,
+	}))
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Warehouse snapshot started", snapshot)
+}
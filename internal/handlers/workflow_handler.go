@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/services"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkflowHandler reports on approval workflows
+type WorkflowHandler struct {
+	workflow *services.WorkflowEngine
+}
+
+// NewWorkflowHandler creates a new instance of WorkflowHandler
+func NewWorkflowHandler(workflow *services.WorkflowEngine) *WorkflowHandler {
+	return &WorkflowHandler{workflow: workflow}
+}
+
+// GetSLAReport returns SLA compliance per approval type for approvals started between
+// from and to (YYYY-MM-DD, inclusive), defaulting to the last 30 days
+func (h *WorkflowHandler) GetSLAReport(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			utils.BadRequestResponse(c, "from must use the YYYY-MM-DD format")
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			utils.BadRequestResponse(c, "to must use the YYYY-MM-DD format")
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		utils.BadRequestResponse(c, "to must not be before from")
+		return
+	}
+
+	rows, err := h.workflow.SLAReport(c.Query("type"), from, to)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build SLA report: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "SLA compliance report generated successfully", gin.H{
+		"type": c.Query("type"),
+		"from": from.Format("2006-01-02"),
+		"to":   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"rows": rows,
+	})
+}
@@ -0,0 +1,33 @@
+package logging
+
+import "context"
+
+// RequestIDHeader carries the ID that ties a request to its log messages, both on API
+// responses and on calls made to other services while serving the request
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log messages are tagged with a request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of a context, or "" outside a request
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Ctx returns the logger tagged with the request ID of a context, so messages of every module
+// written while serving one request can be correlated
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	id := RequestID(ctx)
+	if id == "" {
+		return l
+	}
+	return l.With("request_id", id)
+}
@@ -0,0 +1,260 @@
+// Package logging provides leveled, structured loggers per module whose verbosity can be
+// changed at runtime, e.g. to turn on campusclient=debug while investigating an incident.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"delpresence-api/pkg/config"
+)
+
+// Level is the minimum severity a logger writes
+type Level int
+
+const (
+	// Debug is for detailed tracing, off by default
+	Debug Level = iota
+	// Info is for normal operation
+	Info
+	// Warn is for recoverable problems
+	Warn
+	// Error is for failed operations
+	Error
+)
+
+var levelNames = map[Level]string{
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warn",
+	Error: "error",
+}
+
+// String returns the lowercase level name
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// slogLevel returns the matching log/slog level
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case Debug:
+		return slog.LevelDebug
+	case Warn:
+		return slog.LevelWarn
+	case Error:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// ParseLevel parses a level name such as "debug" or "warn"
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		name = "warn"
+	}
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return Info, fmt.Errorf("unknown log level %q", name)
+}
+
+// levels holds the global level and the per-module overrides
+var levels = struct {
+	sync.RWMutex
+	global  Level
+	modules map[string]Level
+}{global: Info, modules: make(map[string]Level)}
+
+// output writes the records of every module; levels are filtered before records reach it
+var output = newOutput("")
+
+// newOutput returns a JSON writer for format "json" and a key=value text writer otherwise
+func newOutput(format string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if strings.EqualFold(strings.TrimSpace(format), "json") {
+		return slog.New(slog.NewJSONHandler(os.Stderr, options))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, options))
+}
+
+// Configure applies the log format, the global level and the per-module levels
+func Configure(cfg config.LogConfig) {
+	output = newOutput(cfg.Format)
+
+	if value := cfg.Level; value != "" {
+		level, err := ParseLevel(value)
+		if err != nil {
+			log.Printf("Warning: %v, using info", err)
+		}
+		SetLevel(level)
+	}
+
+	for _, part := range strings.Split(cfg.Modules, ",") {
+		module, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			log.Printf("Warning: %v for module %s", err, module)
+			continue
+		}
+		SetModuleLevel(module, level)
+	}
+}
+
+// SetLevel changes the level of every module without an override
+func SetLevel(level Level) {
+	levels.Lock()
+	defer levels.Unlock()
+	levels.global = level
+}
+
+// SetModuleLevel overrides the level of a single module
+func SetModuleLevel(module string, level Level) {
+	levels.Lock()
+	defer levels.Unlock()
+	levels.modules[strings.ToLower(module)] = level
+}
+
+// ResetModuleLevel removes a module override so the module follows the global level again
+func ResetModuleLevel(module string) {
+	levels.Lock()
+	defer levels.Unlock()
+	delete(levels.modules, strings.ToLower(module))
+}
+
+// Snapshot describes the current levels
+type Snapshot struct {
+	Global  string            `json:"global"`
+	Modules map[string]string `json:"modules"`
+	Known   []string          `json:"known_modules"`
+}
+
+// Levels returns the current global level, module overrides and known modules
+func Levels() Snapshot {
+	levels.RLock()
+	snapshot := Snapshot{Global: levels.global.String(), Modules: make(map[string]string)}
+	for module, level := range levels.modules {
+		snapshot.Modules[module] = level.String()
+	}
+	levels.RUnlock()
+
+	known.RLock()
+	for module := range known.modules {
+		snapshot.Known = append(snapshot.Known, module)
+	}
+	known.RUnlock()
+	sort.Strings(snapshot.Known)
+
+	return snapshot
+}
+
+// known records the modules that created a logger, so admins can see what can be tuned
+var known = struct {
+	sync.RWMutex
+	modules map[string]struct{}
+}{modules: make(map[string]struct{})}
+
+// Logger writes messages of one module, each with the attributes the logger carries
+type Logger struct {
+	module string
+	attrs  []any
+}
+
+// Module returns the logger of a module
+func Module(name string) *Logger {
+	name = strings.ToLower(name)
+	known.Lock()
+	known.modules[name] = struct{}{}
+	known.Unlock()
+	return &Logger{module: name}
+}
+
+// Enabled reports whether messages at level are currently written
+func (l *Logger) Enabled(level Level) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+	min, ok := levels.modules[l.module]
+	if !ok {
+		min = levels.global
+	}
+	return level >= min
+}
+
+// Debugf logs a debug message
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(Debug, format, args...)
+}
+
+// Infof logs an informational message
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(Info, format, args...)
+}
+
+// Warnf logs a warning
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(Warn, format, args...)
+}
+
+// Errorf logs an error
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(Error, format, args...)
+}
+
+// Info logs an informational message with key-value attributes
+func (l *Logger) Info(msg string, attrs ...any) {
+	l.log(Info, msg, attrs...)
+}
+
+// Warn logs a warning with key-value attributes
+func (l *Logger) Warn(msg string, attrs ...any) {
+	l.log(Warn, msg, attrs...)
+}
+
+// Error logs an error with key-value attributes
+func (l *Logger) Error(msg string, attrs ...any) {
+	l.log(Error, msg, attrs...)
+}
+
+// With returns a logger of the same module that adds key-value attributes to every message
+func (l *Logger) With(attrs ...any) *Logger {
+	if len(attrs) == 0 {
+		return l
+	}
+	combined := make([]any, 0, len(l.attrs)+len(attrs))
+	combined = append(append(combined, l.attrs...), attrs...)
+	return &Logger{module: l.module, attrs: combined}
+}
+
+func (l *Logger) logf(level Level, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.write(level, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) log(level Level, msg string, attrs ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.write(level, msg, attrs...)
+}
+
+func (l *Logger) write(level Level, msg string, attrs ...any) {
+	record := make([]any, 0, 2+len(l.attrs)+len(attrs))
+	record = append(record, "module", l.module)
+	record = append(append(record, l.attrs...), attrs...)
+	output.Log(context.Background(), level.slogLevel(), msg, record...)
+}
@@ -0,0 +1,15 @@
+package metrics
+
+// Metrics of the API recorded as requests and calls happen. Metrics read from elsewhere, such as
+// the database pool, are registered at startup with NewGaugeFunc and NewCounterFunc.
+var (
+	// HTTPRequests counts requests by method, route and status code
+	HTTPRequests = Default.NewCounterVec("delpresence_http_requests_total",
+		"HTTP requests by method, route and status code.", "method", "route", "status")
+	// HTTPRequestDuration is the latency of requests by method and route
+	HTTPRequestDuration = Default.NewHistogramVec("delpresence_http_request_duration_seconds",
+		"Latency of HTTP requests by method and route.", DefaultBuckets, "method", "route")
+	// CampusRequestDuration is the duration of calls to the campus API by path and outcome
+	CampusRequestDuration = Default.NewHistogramVec("delpresence_campus_api_request_duration_seconds",
+		"Duration of calls to the campus API by path and outcome (success or error).", DefaultBuckets, "path", "outcome")
+)
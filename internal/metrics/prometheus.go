@@ -0,0 +1,227 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histograms
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample is one labeled value reported by a function collector
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// collector writes the series of one metric family in the Prometheus text format
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metric families and writes them in the Prometheus text exposition format
+type Registry struct {
+	mutex      sync.Mutex
+	collectors []collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default is the registry scraped at /metrics
+var Default = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Write writes every metric family in the order they were registered
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mutex.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// CounterVec is a counter per label set
+type CounterVec struct {
+	name, help string
+	labels     []string
+	mutex      sync.Mutex
+	values     map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec registers a counter with the given label names
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	counter := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]*counterSeries)}
+	r.register(counter)
+	return counter
+}
+
+// Inc adds one to the counter of the label values, given in the order of the label names
+func (c *CounterVec) Inc(labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	series, ok := c.values[key]
+	if !ok {
+		series = &counterSeries{labelValues: labelValues}
+		c.values[key] = series
+	}
+	series.value++
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		series := c.values[key]
+		writeSample(w, c.name, c.labels, series.labelValues, series.value)
+	}
+}
+
+// HistogramVec is a histogram per label set
+type HistogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+	mutex      sync.Mutex
+	values     map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // Per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec registers a histogram with the given buckets and label names
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	histogram := &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, values: make(map[string]*histogramSeries)}
+	r.register(histogram)
+	return histogram
+}
+
+// Observe records a value in the histogram of the label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	series, ok := h.values[key]
+	if !ok {
+		series = &histogramSeries{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.values[key] = series
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+			break
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+	for _, key := range sortedKeys(h.values) {
+		series := h.values[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			writeSample(w, h.name+"_bucket", bucketLabels, append(append([]string(nil), series.labelValues...), formatValue(bound)), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", bucketLabels, append(append([]string(nil), series.labelValues...), "+Inf"), float64(series.count))
+		writeSample(w, h.name+"_sum", h.labels, series.labelValues, series.sum)
+		writeSample(w, h.name+"_count", h.labels, series.labelValues, float64(series.count))
+	}
+}
+
+// funcCollector reads its samples when scraped, for values kept elsewhere
+type funcCollector struct {
+	name, help, kind string
+	labels           []string
+	collect          func() []Sample
+}
+
+// NewGaugeFunc registers a gauge whose samples are read from collect on every scrape
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func() []Sample) {
+	r.register(&funcCollector{name: name, help: help, kind: "gauge", labels: labels, collect: collect})
+}
+
+// NewCounterFunc registers a counter whose samples are read from collect on every scrape
+func (r *Registry) NewCounterFunc(name, help string, labels []string, collect func() []Sample) {
+	r.register(&funcCollector{name: name, help: help, kind: "counter", labels: labels, collect: collect})
+}
+
+func (f *funcCollector) write(w *bufio.Writer) {
+	writeHeader(w, f.name, f.help, f.kind)
+	for _, sample := range f.collect() {
+		writeSample(w, f.name, f.labels, sample.LabelValues, sample.Value)
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	w.WriteString("# HELP " + name + " " + strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help) + "\n")
+	w.WriteString("# TYPE " + name + " " + kind + "\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeSample(w *bufio.Writer, name string, labels, labelValues []string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + labelEscaper.Replace(labelValues[i]) + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteString(" " + formatValue(value) + "\n")
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// UsageKey labels a group of requests
+type UsageKey struct {
+	Route       string `json:"route"`
+	Method      string `json:"method"`
+	StatusClass string `json:"status_class"` // e.g. "2xx"
+	APIKey      string `json:"api_key"`      // Name of the API key, empty for user requests
+	Prodi       string `json:"prodi"`        // Prodi of the user, empty when unknown
+}
+
+// UsageCount is the aggregated usage of one label set
+type UsageCount struct {
+	UsageKey
+	Requests        int64   `json:"requests"`
+	TotalDurationMs float64 `json:"total_duration_ms"`
+	AvgDurationMs   float64 `json:"avg_duration_ms"`
+}
+
+type usageValue struct {
+	requests int64
+	duration time.Duration
+}
+
+// UsageRegistry keeps labeled request counters in memory since the process started
+type UsageRegistry struct {
+	mutex     sync.Mutex
+	counters  map[UsageKey]*usageValue
+	startedAt time.Time
+}
+
+// NewUsageRegistry creates an empty registry
+func NewUsageRegistry() *UsageRegistry {
+	return &UsageRegistry{
+		counters:  make(map[UsageKey]*usageValue),
+		startedAt: time.Now(),
+	}
+}
+
+// DefaultUsage is the registry the API records request usage in
+var DefaultUsage = NewUsageRegistry()
+
+// Record counts one request
+func (r *UsageRegistry) Record(key UsageKey, duration time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	value, exists := r.counters[key]
+	if !exists {
+		value = &usageValue{}
+		r.counters[key] = value
+	}
+	value.requests++
+	value.duration += duration
+}
+
+// StartedAt returns when counting started
+func (r *UsageRegistry) StartedAt() time.Time {
+	return r.startedAt
+}
+
+// Summary aggregates the counters by the given dimensions ("route", "method", "status",
+// "api_key", "prodi"); labels not grouped by are left empty. Results are sorted by
+// request count, highest first.
+func (r *UsageRegistry) Summary(groupBy []string) []UsageCount {
+	r.mutex.Lock()
+	grouped := make(map[UsageKey]*usageValue)
+	for key, value := range r.counters {
+		groupKey := projectKey(key, groupBy)
+		total, exists := grouped[groupKey]
+		if !exists {
+			total = &usageValue{}
+			grouped[groupKey] = total
+		}
+		total.requests += value.requests
+		total.duration += value.duration
+	}
+	r.mutex.Unlock()
+
+	summary := make([]UsageCount, 0, len(grouped))
+	for key, value := range grouped {
+		totalMs := float64(value.duration) / float64(time.Millisecond)
+		summary = append(summary, UsageCount{
+			UsageKey:        key,
+			Requests:        value.requests,
+			TotalDurationMs: totalMs,
+			AvgDurationMs:   totalMs / float64(value.requests),
+		})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Requests > summary[j].Requests
+	})
+	return summary
+}
+
+// projectKey keeps only the grouped dimensions of a key
+func projectKey(key UsageKey, groupBy []string) UsageKey {
+	var projected UsageKey
+	for _, dimension := range groupBy {
+		switch dimension {
+		case "route":
+			projected.Route = key.Route
+		case "method":
+			projected.Method = key.Method
+		case "status":
+			projected.StatusClass = key.StatusClass
+		case "api_key":
+			projected.APIKey = key.APIKey
+		case "prodi":
+			projected.Prodi = key.Prodi
+		}
+	}
+	return projected
+}
+
+// IsUsageDimension checks whether dimension can be grouped by
+func IsUsageDimension(dimension string) bool {
+	switch dimension {
+	case "route", "method", "status", "api_key", "prodi":
+		return true
+	}
+	return false
+}
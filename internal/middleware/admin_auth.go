@@ -2,17 +2,22 @@ package middleware
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
 	"delpresence-api/internal/utils"
+	tokens "delpresence-api/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// AdminAuth middleware untuk memverifikasi token JWT admin
-func AdminAuth() gin.HandlerFunc {
+// AdminAuth middleware untuk memverifikasi token JWT admin. Access level dibaca dari database
+// pada setiap request, sehingga penurunan level atau penonaktifan admin langsung berlaku
+// tanpa menunggu token kedaluwarsa.
+func AdminAuth(adminRepo *repository.AdminRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -40,11 +45,7 @@ func AdminAuth() gin.HandlerFunc {
 			}
 
 			// Return secret key for verification
-			secretKey := os.Getenv("JWT_SECRET_KEY")
-			if secretKey == "" {
-				secretKey = "default_secret_key"
-			}
-			return []byte(secretKey), nil
+			return tokens.AdminSecretKey(), nil
 		})
 
 		if err != nil {
@@ -66,12 +67,26 @@ func AdminAuth() gin.HandlerFunc {
 			// Set claims to context
 			userID, _ := claims["user_id"].(float64)
 			adminID, _ := claims["admin_id"].(float64)
-			accessLevel, _ := claims["access_level"].(string)
 
-			c.Set("user_id", uint(userID))
-			c.Set("admin_id", uint(adminID))
-			c.Set("access_level", accessLevel)
-			c.Set("user_type", userType)
+			// The access_level claim is only what the admin held at login
+			admin, err := adminRepo.FindByID(uint(adminID))
+			if err != nil {
+				utils.InternalServerErrorResponse(c, "Gagal memeriksa admin: "+err.Error())
+				c.Abort()
+				return
+			}
+			if admin == nil || !admin.IsActive || admin.UserID != uint(userID) {
+				utils.UnauthorizedResponse(c, "Admin tidak lagi aktif")
+				c.Abort()
+				return
+			}
+
+			auth.SetPrincipal(c, &auth.Principal{
+				UserID:      admin.UserID,
+				AdminID:     admin.ID,
+				AccessLevel: string(admin.AccessLevel),
+				UserType:    models.UserType(userType),
+			})
 
 			c.Next()
 		} else {
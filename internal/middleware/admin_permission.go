@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAccessLevel only lets admins through whose access level is at least min, whatever
+// permissions their level was granted. It guards actions that could lock admins out or
+// destroy data. It must run after AdminAuth.
+func RequireAccessLevel(min models.AccessLevel) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok || !principal.IsAdmin() {
+			utils.UnauthorizedResponse(c, "Admin tidak terautentikasi")
+			c.Abort()
+			return
+		}
+
+		if !models.AccessLevel(principal.AccessLevel).AtLeast(min) {
+			utils.ForbiddenResponse(c, "Tindakan ini memerlukan access level "+string(min))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission only lets admins through whose access level grants the permission.
+// It must run after AdminAuth.
+func RequirePermission(accessLevelRepo repository.AccessLevelRepository, permission models.AdminPermission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok || !principal.IsAdmin() {
+			utils.UnauthorizedResponse(c, "Admin tidak terautentikasi")
+			c.Abort()
+			return
+		}
+
+		permissions, err := accessLevelRepo.FindPermissions(models.AccessLevel(principal.AccessLevel))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Gagal memeriksa hak akses: "+err.Error())
+			c.Abort()
+			return
+		}
+
+		if !permissions.HasPermission(permission) {
+			utils.ForbiddenResponse(c, "Access level "+principal.AccessLevel+" tidak memiliki izin "+string(permission))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAuth authenticates external systems by the X-API-Key header and only lets
+// the request through when the key's allowlist contains the given operation
+func APIKeyAuth(apiKeyRepo repository.APIKeyRepository, operation models.APIOperation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			utils.UnauthorizedResponse(c, "X-API-Key header is missing")
+			c.Abort()
+			return
+		}
+
+		apiKey, err := apiKeyRepo.FindByHash(utils.HashAPIKey(key))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to validate API key: "+err.Error())
+			c.Abort()
+			return
+		}
+
+		if apiKey == nil || !apiKey.IsUsable() {
+			utils.UnauthorizedResponse(c, "Invalid, revoked or expired API key")
+			c.Abort()
+			return
+		}
+
+		if !apiKey.Allows(operation) {
+			utils.ForbiddenResponse(c, "API key is not allowed to call "+string(operation))
+			c.Abort()
+			return
+		}
+
+		if err := apiKeyRepo.TouchLastUsed(apiKey.ID); err != nil {
+			log.Printf("Failed to update last use of API key %d: %v", apiKey.ID, err)
+		}
+
+		c.Set("api_key_id", apiKey.ID)
+		c.Set("api_key_name", apiKey.Name)
+		c.Next()
+	}
+}
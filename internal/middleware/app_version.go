@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpgradeRequiredCode identifies the upgrade response so the app can show its update screen
+const UpgradeRequiredCode = "upgrade_required"
+
+// AppVersionGate rejects requests from app versions below the policy's minimum version with 426
+// Upgrade Required. Requests without the header (web dashboard, integrations) are let through.
+func AppVersionGate(policy utils.AppVersionPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := c.GetHeader(utils.AppVersionHeader)
+		if version == "" {
+			c.Next()
+			return
+		}
+
+		if policy.RequiresUpgrade(version) {
+			utils.ErrorResponse(c, http.StatusUpgradeRequired, "Please update the app to continue", gin.H{
+				"code":            UpgradeRequiredCode,
+				"current_version": version,
+				"minimum_version": policy.MinimumVersion,
+				"latest_version":  policy.LatestVersion,
+				"update_url":      policy.UpdateURL,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,32 +1,50 @@
 package middleware
 
 import (
+	"crypto/hmac"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
+	"delpresence-api/internal/auth"
 	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/database"
 	"delpresence-api/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware handles JWT authentication
+// CSRFHeader must carry the CSRF token of a dashboard session on unsafe requests
+// authenticated by the session cookie
+const CSRFHeader = "X-CSRF-Token"
+
+// AuthMiddleware handles JWT authentication.
+//
+// Only tokens signed by this API are accepted; campus logins are exchanged for them, so a
+// token issued by the campus API is rejected. The active role comes from the signed claims.
+// Which roles may use a route is declared on the route group with RequireRole, never
+// inferred from the path.
+// Requests without an Authorization header may authenticate with the session cookie of the
+// web dashboard instead; unsafe ones must then send the session's CSRF token.
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		path := c.Request.URL.Path
-
-		// Skip authentication for certain paths if needed
-		if strings.HasPrefix(path, "/api/v1/health") {
-			c.Next()
-			return
-		}
-
 		// Get the authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing"})
-			c.Abort()
-			return
+			sessionToken, err := c.Cookie(auth.SessionCookie)
+			if err != nil || sessionToken == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing"})
+				c.Abort()
+				return
+			}
+			if !safeMethod(c.Request.Method) &&
+				!hmac.Equal([]byte(c.GetHeader(CSRFHeader)), []byte(jwt.CSRFToken(sessionToken))) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Missing or invalid CSRF token"})
+				c.Abort()
+				return
+			}
+			authHeader = "Bearer " + sessionToken
 		}
 
 		// Check if the header has the Bearer prefix
@@ -45,74 +63,28 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// For student-related API endpoints, priorities student token validation
-		isMahasiswaEndpoint := strings.HasPrefix(path, "/api/v1/mahasiswa")
-		isAssistantEndpoint := strings.HasPrefix(path, "/api/v1/assistant")
-		var userID uint
-
-		// CASE 1: For mahasiswa or assistant endpoints, try campus token validation first
-		if isMahasiswaEndpoint || isAssistantEndpoint {
-			// Try to validate as campus token
-			campusUserID, campusErr := jwt.ValidateCampusToken(tokenString)
-			if campusErr == nil {
-				// Campus token validation succeeded
-				userID = uint(campusUserID)
-
-				// Set user info in the context
-				c.Set("user_id", userID)
-				c.Set("campus_user_id", campusUserID)
-				c.Set("campus_authenticated", true)
-				c.Next()
-				return
-			}
-		}
-
-		// CASE 2: Try regular JWT validation for all endpoints
+		// Only tokens issued by this API are accepted; campus tokens stay on the server
 		claims, err := jwt.ValidateToken(tokenString)
 		if err == nil {
-			// Regular token validation succeeded
-			userID = claims.UserID
-
-			// Check if user exists in our database
-			userRepo := repository.NewUserRepository()
-			user, dbErr := userRepo.GetUserByID(userID)
-			if dbErr != nil {
-				if dbErr == repository.ErrUserNotFound {
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-				} else {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			switch claims.TokenType {
+			case jwt.CampusTokenType:
+				var issuedAt time.Time
+				if claims.IssuedAt != nil {
+					issuedAt = claims.IssuedAt.Time
 				}
+				authenticateCampusUser(c, int(claims.UserID), claims.ActiveRole, issuedAt)
+			case jwt.LocalTokenType:
+				authenticateLocalUser(c, claims)
+			default:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Unknown token type"})
 				c.Abort()
-				return
 			}
-
-			// Set user info in the context
-			c.Set("user_id", user.ID)
-			c.Set("user_type", user.UserType)
-			c.Set("email", user.Email)
-			c.Set("first_name", user.FirstName)
-			c.Set("middle_name", user.MiddleName)
-			c.Set("last_name", user.LastName)
-
-			c.Next()
 			return
 		}
-
-		// CASE 3: If we're not on a mahasiswa endpoint and the regular token failed,
-		// try campus token as last resort
-		if !isMahasiswaEndpoint && !isAssistantEndpoint {
-			campusUserID, campusErr := jwt.ValidateCampusToken(tokenString)
-			if campusErr == nil {
-				// Campus token validation succeeded for non-mahasiswa/non-assistant endpoint
-				userID = uint(campusUserID)
-
-				// Set user info in the context
-				c.Set("user_id", userID)
-				c.Set("campus_user_id", campusUserID)
-				c.Set("campus_authenticated", true)
-				c.Next()
-				return
-			}
+		if errors.Is(err, jwt.ErrExpiredToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has expired"})
+			c.Abort()
+			return
 		}
 
 		// If we reach here, authentication failed
@@ -120,3 +92,66 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Abort()
 	}
 }
+
+// safeMethod reports whether an HTTP method only reads, so it needs no CSRF token
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// authenticateLocalUser sets the context for a user with a local account
+func authenticateLocalUser(c *gin.Context, claims *jwt.CustomClaims) {
+	// Check if user exists in our database
+	userRepo := repository.NewUserRepository()
+	user, dbErr := userRepo.GetUserByID(claims.UserID)
+	if dbErr != nil {
+		if dbErr == repository.ErrUserNotFound {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		c.Abort()
+		return
+	}
+
+	principal := &auth.Principal{
+		UserID:     user.ID,
+		UserType:   user.UserType,
+		Email:      user.Email,
+		FirstName:  user.FirstName,
+		MiddleName: user.MiddleName,
+		LastName:   user.LastName,
+	}
+
+	if !setRoleContext(c, principal, claims.ActiveRole) {
+		return
+	}
+	c.Next()
+}
+
+// authenticateCampusUser sets the context for a campus-authenticated user whose token was
+// issued at issuedAt, unless the user's tokens were revoked since
+func authenticateCampusUser(c *gin.Context, campusUserID int, activeRole string, issuedAt time.Time) {
+	credentialRepo := repository.NewCampusCredentialRepository(database.GetDB())
+	credential, err := credentialRepo.FindByCampusUserID(campusUserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		c.Abort()
+		return
+	}
+	if credential != nil && credential.RevokesTokenIssuedAt(issuedAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return
+	}
+
+	principal := &auth.Principal{
+		UserID:              uint(campusUserID),
+		CampusUserID:        campusUserID,
+		CampusAuthenticated: true,
+	}
+
+	if !setRoleContext(c, principal, activeRole) {
+		return
+	}
+	c.Next()
+}
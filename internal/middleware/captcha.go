@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"delpresence-api/internal/captcha"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// CaptchaTokenHeader carries the token of the CAPTCHA the user solved
+	CaptchaTokenHeader = "X-Captcha-Token"
+	// ClientTokenHeader identifies a client on the CAPTCHA bypass list
+	ClientTokenHeader = "X-Client-Token"
+)
+
+// Captcha requires a solved CAPTCHA before the request is handled, unless the client is on the
+// bypass list. It does nothing while CAPTCHA is off (verifier is nil).
+func Captcha(verifier captcha.Verifier, bypass *captcha.BypassList) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil || bypass.Allows(c.GetHeader(ClientTokenHeader)) {
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(CaptchaTokenHeader)
+		if token == "" {
+			utils.ErrorResponse(c, http.StatusBadRequest, "CAPTCHA is required", gin.H{"code": "captcha_required"})
+			c.Abort()
+			return
+		}
+
+		if err := verifier.Verify(c.Request.Context(), token, c.ClientIP()); err != nil {
+			if errors.Is(err, captcha.ErrFailed) {
+				utils.ErrorResponse(c, http.StatusForbidden, "CAPTCHA verification failed", gin.H{"code": "captcha_failed"})
+			} else {
+				utils.LogWarning("Captcha", "Verify", err.Error())
+				utils.ErrorResponse(c, http.StatusServiceUnavailable, "CAPTCHA verification is unavailable, please try again", gin.H{"code": "captcha_unavailable"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"log"
+
+	"delpresence-api/internal/chaos"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChaosHeader selects the faults to inject while the request runs,
+// e.g. "campus=error, db=latency:300ms"
+const ChaosHeader = "X-Chaos"
+
+// Chaos injects the faults requested in the X-Chaos header into the dependency calls made
+// with the request context. Only register it when chaos.Enabled() is true.
+func Chaos() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		spec := c.GetHeader(ChaosHeader)
+		if spec == "" {
+			c.Next()
+			return
+		}
+
+		faults, err := chaos.ParseFaults(spec)
+		if err != nil {
+			utils.BadRequestResponse(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		log.Printf("[CHAOS] Injecting %q into %s %s", spec, c.Request.Method, c.Request.URL.Path)
+		c.Request = c.Request.WithContext(chaos.WithFaults(c.Request.Context(), faults))
+		c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckInEventLocator finds the event type a check-in request is recorded for; an empty type
+// lets the request through, e.g. when its activity does not exist and the handler answers 404
+type CheckInEventLocator func(c *gin.Context) (models.CheckInEventType, error)
+
+// FixedCheckInEvent locates the check-ins of a route that always records the same event type
+func FixedCheckInEvent(eventType models.CheckInEventType) CheckInEventLocator {
+	return func(c *gin.Context) (models.CheckInEventType, error) {
+		return eventType, nil
+	}
+}
+
+// CheckInEventFromActivity locates the participants recorded for the activity in the :id
+// parameter by the activity's category
+func CheckInEventFromActivity(activityRepo repository.ActivityRepository) CheckInEventLocator {
+	return func(c *gin.Context) (models.CheckInEventType, error) {
+		id, err := parseID(c, "id")
+		if err != nil {
+			return "", nil
+		}
+		activity, err := activityRepo.FindByID(id)
+		if err != nil || activity == nil {
+			return "", err
+		}
+		return models.CheckInEventType(activity.Category), nil
+	}
+}
+
+// RejectCheckInBlackout rejects check-ins made during a blackout window of their event type
+// outright, before the handler records anything. Windows are wall-clock times in location, the
+// campus time zone, whatever zone the server runs in.
+func RejectCheckInBlackout(blackoutRepo repository.CheckInBlackoutRepository, locate CheckInEventLocator, location *time.Location) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventType, err := locate(c)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check the check-in hours: "+err.Error())
+			c.Abort()
+			return
+		}
+		if eventType == "" {
+			c.Next()
+			return
+		}
+
+		policy, err := blackoutRepo.FindForEventType(eventType)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check the check-in hours: "+err.Error())
+			c.Abort()
+			return
+		}
+		if window := policy.ActiveWindow(time.Now().In(location)); window != nil {
+			utils.ErrorResponse(c, http.StatusForbidden, "Check-ins are not accepted between "+window.Start+" and "+window.End, gin.H{
+				"code":       "check_in_blackout",
+				"event_type": eventType,
+				"window":     window,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
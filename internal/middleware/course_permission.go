@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errCourseNotFound is returned by a CourseLocator when the resource of the request does not exist
+var errCourseNotFound = errors.New("resource not found")
+
+// invalidCourseRequest is returned by a CourseLocator when the request does not identify a course
+type invalidCourseRequest string
+
+// Error implements the error interface
+func (e invalidCourseRequest) Error() string {
+	return string(e)
+}
+
+// CourseLocator finds the course offering a request acts on, and the user who owns the
+// resource when it has an owner (e.g. the lecturer or assistant who opened a session)
+type CourseLocator func(c *gin.Context) (offering models.CourseOffering, ownerUserID uint, err error)
+
+// RequireCoursePermission only lets assistants through who own the resource of the request or
+// whose assignment to its course grants the permission. The assignment is stored as the
+// principal's CourseGrant. It must run after AuthMiddleware.
+func RequireCoursePermission(assignmentRepo repository.AssistantAssignmentRepository, permission models.AssistantPermission, locate CourseLocator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			utils.UnauthorizedResponse(c, "User not authenticated")
+			c.Abort()
+			return
+		}
+
+		offering, ownerUserID, err := locate(c)
+		var invalid invalidCourseRequest
+		switch {
+		case errors.Is(err, errCourseNotFound):
+			utils.NotFoundResponse(c, "Resource not found")
+			c.Abort()
+			return
+		case errors.As(err, &invalid):
+			utils.BadRequestResponse(c, invalid.Error())
+			c.Abort()
+			return
+		case err != nil:
+			utils.InternalServerErrorResponse(c, "Failed to check course permissions: "+err.Error())
+			c.Abort()
+			return
+		}
+		if ownerUserID != 0 && ownerUserID == principal.UserID {
+			c.Next()
+			return
+		}
+
+		assignment, err := assignmentRepo.FindForOffering(principal.UserID, offering)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check course permissions: "+err.Error())
+			c.Abort()
+			return
+		}
+		if assignment == nil || !assignment.HasPermission(permission) {
+			utils.ForbiddenResponse(c, "You do not have the "+string(permission)+" permission for course "+offering.CourseCode)
+			c.Abort()
+			return
+		}
+
+		principal.CourseGrant = assignment
+		c.Next()
+	}
+}
+
+// parseID parses a numeric route parameter for a locator
+func parseID(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, invalidCourseRequest("invalid " + name)
+	}
+	return uint(id), nil
+}
+
+// sessionOffering returns the course offering of an attendance session
+func sessionOffering(session *models.AttendanceSession) models.CourseOffering {
+	return models.CourseOffering{
+		CourseCode: session.CourseCode,
+		CourseName: session.CourseName,
+		ClassName:  session.ClassName,
+		Semester:   session.Semester,
+	}
+}
+
+// CourseFromPath locates the course by the course code in a route parameter and the
+// semester and class_name query parameters
+func CourseFromPath(param string) CourseLocator {
+	return func(c *gin.Context) (models.CourseOffering, uint, error) {
+		offering := models.CourseOffering{
+			CourseCode: c.Param(param),
+			ClassName:  c.Query("class_name"),
+			Semester:   c.Query("semester"),
+		}
+		if offering.Semester == "" {
+			return offering, 0, invalidCourseRequest("semester is required")
+		}
+		return offering, 0, nil
+	}
+}
+
+// CourseFromBody locates the course by the course_code, class_name and semester of a JSON
+// body. The body is kept so the handler can still bind it.
+func CourseFromBody() CourseLocator {
+	return func(c *gin.Context) (models.CourseOffering, uint, error) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return models.CourseOffering{}, 0, invalidCourseRequest("failed to read request body")
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var offering models.CourseOffering
+		if err := json.Unmarshal(body, &offering); err != nil || offering.CourseCode == "" {
+			return offering, 0, invalidCourseRequest("course_code is required")
+		}
+		if offering.Semester == "" {
+			return offering, 0, invalidCourseRequest("semester is required")
+		}
+		return offering, 0, nil
+	}
+}
+
+// CourseFromSession locates the course of the attendance session in the id route parameter
+func CourseFromSession(attendanceRepo repository.AttendanceRepository) CourseLocator {
+	return func(c *gin.Context) (models.CourseOffering, uint, error) {
+		id, err := parseID(c, "id")
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		session, err := attendanceRepo.FindSessionByID(id)
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		if session == nil {
+			return models.CourseOffering{}, 0, errCourseNotFound
+		}
+		return sessionOffering(session), session.LecturerUserID, nil
+	}
+}
+
+// CourseFromRecord locates the course of the attendance record in the id route parameter
+func CourseFromRecord(attendanceRepo repository.AttendanceRepository) CourseLocator {
+	return func(c *gin.Context) (models.CourseOffering, uint, error) {
+		id, err := parseID(c, "id")
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		record, err := attendanceRepo.FindRecordByID(id)
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		if record == nil {
+			return models.CourseOffering{}, 0, errCourseNotFound
+		}
+		session, err := attendanceRepo.FindSessionByID(record.SessionID)
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		if session == nil {
+			return models.CourseOffering{}, 0, errCourseNotFound
+		}
+		return sessionOffering(session), session.LecturerUserID, nil
+	}
+}
+
+// CourseFromSessionMaterial locates the course of the session a material in the id route
+// parameter is attached to
+func CourseFromSessionMaterial(materialRepo repository.SessionMaterialRepository, attendanceRepo repository.AttendanceRepository) CourseLocator {
+	return func(c *gin.Context) (models.CourseOffering, uint, error) {
+		id, err := parseID(c, "id")
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		material, err := materialRepo.FindByID(id)
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		if material == nil {
+			return models.CourseOffering{}, 0, errCourseNotFound
+		}
+		session, err := attendanceRepo.FindSessionByID(material.SessionID)
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		if session == nil {
+			return models.CourseOffering{}, 0, errCourseNotFound
+		}
+		return sessionOffering(session), session.LecturerUserID, nil
+	}
+}
+
+// CourseFromPermissionRequest locates the course of the permission request in the id route parameter
+func CourseFromPermissionRequest(permissionRepo repository.PermissionRequestRepository) CourseLocator {
+	return func(c *gin.Context) (models.CourseOffering, uint, error) {
+		id, err := parseID(c, "id")
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		request, err := permissionRepo.FindByID(id)
+		if err != nil {
+			return models.CourseOffering{}, 0, err
+		}
+		if request == nil {
+			return models.CourseOffering{}, 0, errCourseNotFound
+		}
+		return models.CourseOffering{
+			CourseCode: request.CourseCode,
+			CourseName: request.CourseName,
+			ClassName:  request.ClassName,
+			Semester:   request.Semester,
+		}, 0, nil
+	}
+}
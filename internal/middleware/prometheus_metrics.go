@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrometheusMetrics records the count and latency of every request for /metrics, labeled by
+// route template so paths with IDs do not each get their own series
+func PrometheusMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+		metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), c.Request.Method, route)
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter holds back a response so it can be rewritten before it is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+// WriteHeaderNow implements gin.ResponseWriter; the header is written once the response is released
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// Write implements io.Writer
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString implements io.StringWriter
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Status implements gin.ResponseWriter
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Size implements gin.ResponseWriter
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+// Written implements gin.ResponseWriter
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0 || w.body.Len() > 0
+}
+
+// PseudonymizeStudents replaces the identifiers of students in the responses of admins whose
+// access level is not granted students:deanonymize. JSON responses are rewritten here; other
+// responses are only sent when their handler pseudonymized them itself, so a new export cannot
+// leak students by default. It must run after AdminAuth.
+func PseudonymizeStudents(accessLevelRepo repository.AccessLevelRepository, pseudonymizer *pseudonym.Pseudonymizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok || !principal.IsAdmin() {
+			c.Next()
+			return
+		}
+
+		permissions, err := accessLevelRepo.FindPermissions(models.AccessLevel(principal.AccessLevel))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Gagal memeriksa hak akses: "+err.Error())
+			c.Abort()
+			return
+		}
+		if permissions.HasPermission(models.DeanonymizeStudentsPermission) {
+			c.Next()
+			return
+		}
+
+		pseudonym.Require(c, pseudonymizer)
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		body := writer.body.Bytes()
+		switch {
+		case len(body) == 0 || pseudonym.Handled(c):
+		case strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json"):
+			body, err = pseudonymizer.JSON(body)
+			if err != nil {
+				writer.Header().Del("Content-Type")
+				utils.InternalServerErrorResponse(c, "Gagal menyamarkan data mahasiswa: "+err.Error())
+				return
+			}
+		default:
+			writer.Header().Del("Content-Type")
+			writer.Header().Del("Content-Disposition")
+			utils.ForbiddenResponse(c, "Respons ini tidak tersedia dengan data mahasiswa yang disamarkan")
+			return
+		}
+
+		writer.Header().Del("Content-Length")
+		original.WriteHeader(writer.Status())
+		original.Write(body)
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/capture"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureWriter keeps a copy of the response body up to the capture limit
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write implements io.Writer
+func (w *captureWriter) Write(data []byte) (int, error) {
+	if remaining := capture.MaxBodyBytes - w.body.Len(); remaining > 0 {
+		if len(data) < remaining {
+			remaining = len(data)
+		}
+		w.body.Write(data[:remaining])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// RequestCapture stores sampled request/response pairs for the users and routes admins
+// opted in. Requests are only buffered while a capture rule is active.
+func RequestCapture(store *capture.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !store.Active() {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		var requestBody []byte
+		if c.Request.Body != nil {
+			requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, capture.MaxBodyBytes))
+			// Put back what was read followed by anything past the limit
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(requestBody), c.Request.Body))
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		var userID uint
+		if principal, ok := auth.FromContext(c); ok {
+			userID = principal.UserID
+		}
+		rule := store.Sample(userID, c.Request.URL.Path)
+		if rule == nil {
+			return
+		}
+
+		store.Record(capture.Capture{
+			RuleID:          rule.ID,
+			UserID:          userID,
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Route:           c.FullPath(),
+			Query:           c.Request.URL.RawQuery,
+			Status:          writer.Status(),
+			DurationMs:      float64(time.Since(start).Microseconds()) / 1000,
+			RequestHeaders:  capture.RedactHeaders(c.Request.Header),
+			RequestBody:     string(requestBody),
+			ResponseHeaders: capture.RedactHeaders(writer.Header()),
+			ResponseBody:    writer.body.String(),
+			CapturedAt:      time.Now(),
+		})
+	}
+}
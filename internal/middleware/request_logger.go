@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLog logs one line per served request
+var httpLog = logging.Module("http")
+
+// RequestLogger tags every request with an ID, makes it available to loggers through the
+// request context and logs the method, path, status and latency once the request is served.
+// A client may send its own ID to correlate with its logs; the ID used is always echoed in the
+// response.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(logging.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(logging.RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		logger := httpLog.Ctx(c.Request.Context())
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Error("request served", attrs...)
+		case status >= http.StatusBadRequest:
+			logger.Warn("request served", attrs...)
+		default:
+			logger.Info("request served", attrs...)
+		}
+	}
+}
+
+// validRequestID accepts client IDs that are short and printable so they cannot forge log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit ID
+func newRequestID() string {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(raw)
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setRoleContext loads the roles of the principal, picks the active role and stores the
+// principal in the context. requested is the role in the token's claims; when empty the user's
+// default role is used. It aborts the request and returns false when the role is not held.
+func setRoleContext(c *gin.Context, principal *auth.Principal, requested string) bool {
+	roleRepo := repository.NewUserRoleRepository(database.GetDB())
+	userRoles, err := roleRepo.FindByUserID(principal.IdentitySource(), principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		c.Abort()
+		return false
+	}
+
+	roles := make([]string, 0, len(userRoles))
+	activeRole := ""
+	for _, userRole := range userRoles {
+		roles = append(roles, string(userRole.Role))
+		if requested == "" && userRole.IsDefault {
+			activeRole = string(userRole.Role)
+		}
+		if requested != "" && string(userRole.Role) == requested {
+			activeRole = requested
+		}
+	}
+
+	if requested != "" && activeRole == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not hold the requested role: " + requested})
+		c.Abort()
+		return false
+	}
+
+	principal.Roles = roles
+	principal.ActiveRole = activeRole
+	auth.SetPrincipal(c, principal)
+	return true
+}
+
+// RequireRole only lets requests through whose active role is one of the given roles.
+// Accounts without any linked role are judged by their account type; campus users have
+// none, so they are rejected until a role is linked to them.
+func RequireRole(allowed ...models.UserType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		activeRole := principal.ActiveRole
+		if activeRole == "" {
+			activeRole = string(principal.UserType)
+		}
+
+		for _, role := range allowed {
+			if string(role) == activeRole {
+				c.Next()
+				return
+			}
+		}
+
+		if activeRole == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "No role is linked to this account"})
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint is not available for the active role: " + activeRole})
+		c.Abort()
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScopedTokenQuery is the query parameter displays and kiosks pass their scoped token in,
+// for clients that cannot set the Authorization header (e.g. a URL opened on a projector)
+const ScopedTokenQuery = "token"
+
+// RequireScopedToken authorizes a request with a scoped token granting capability on the
+// resource whose ID is in the param route parameter. User access tokens are not accepted, so
+// a leaked display or kiosk URL exposes nothing beyond that one resource.
+func RequireScopedToken(capability, resource, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.Query(ScopedTokenQuery)
+		if tokenString == "" {
+			tokenString = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Scoped token is missing"})
+			c.Abort()
+			return
+		}
+
+		claims, err := jwt.ValidateScopedToken(tokenString)
+		if err != nil {
+			message := "Invalid scoped token"
+			if errors.Is(err, jwt.ErrExpiredToken) {
+				message = "Scoped token has expired"
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil || claims.Scope != auth.Scope(capability, resource, uint(id)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Scoped token does not grant access to this resource"})
+			c.Abort()
+			return
+		}
+
+		auth.SetScopedToken(c, &auth.ScopedToken{Scope: claims.Scope, IssuedBy: claims.IssuedBy})
+		c.Next()
+	}
+}
+
+// RequireDisplayToken authorizes a display of the session whose ID is in the param route parameter
+func RequireDisplayToken(param string) gin.HandlerFunc {
+	return RequireScopedToken(auth.DisplayCapability, auth.SessionResource, param)
+}
+
+// RequireKioskToken authorizes a kiosk of the room whose ID is in the param route parameter
+func RequireKioskToken(param string) gin.HandlerFunc {
+	return RequireScopedToken(auth.KioskCapability, auth.RoomResource, param)
+}
+
+// RequireDisplayTokenOrAuth authorizes a display of the session whose ID is in the param route
+// parameter like RequireDisplayToken when a scoped token is passed in the query, and a signed-in
+// user like AuthMiddleware otherwise. Handlers check that the user may access the session.
+func RequireDisplayTokenOrAuth(param string) gin.HandlerFunc {
+	display := RequireDisplayToken(param)
+	user := AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.Query(ScopedTokenQuery) != "" {
+			display(c)
+			return
+		}
+		user(c)
+	}
+}
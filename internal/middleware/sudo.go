@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SudoTokenHeader carries the elevation token obtained by re-authenticating
+const SudoTokenHeader = "X-Sudo-Token"
+
+// RequireSudo only lets destructive admin actions through when the admin re-authenticated
+// recently. It must run after AdminAuth.
+func RequireSudo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok || !principal.IsAdmin() {
+			utils.UnauthorizedResponse(c, "Admin tidak terautentikasi")
+			c.Abort()
+			return
+		}
+
+		tokenString := c.GetHeader(SudoTokenHeader)
+		if tokenString == "" {
+			utils.ErrorResponse(c, http.StatusForbidden, "Tindakan ini memerlukan autentikasi ulang (sudo mode)", gin.H{"sudo_required": true})
+			c.Abort()
+			return
+		}
+
+		claims, err := jwt.ValidateSudoToken(tokenString)
+		if err != nil || claims.UserID != principal.UserID {
+			utils.ErrorResponse(c, http.StatusForbidden, "Token sudo tidak valid atau sudah kedaluwarsa", gin.H{"sudo_required": true})
+			c.Abort()
+			return
+		}
+
+		principal.Elevated = true
+		c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageMetrics counts requests per route, status, API key and prodi so usage can be
+// attributed. The labels are read after the request ran, once auth middleware set them.
+func UsageMetrics(registry *metrics.UsageRegistry, prodiOf func(*auth.Principal) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		key := metrics.UsageKey{
+			Route:       route,
+			Method:      c.Request.Method,
+			StatusClass: fmt.Sprintf("%dxx", c.Writer.Status()/100),
+			APIKey:      c.GetString("api_key_name"),
+		}
+		if principal, ok := auth.FromContext(c); ok {
+			key.Prodi = prodiOf(principal)
+		}
+
+		registry.Record(key, time.Since(start))
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageRecorder stores the usage of one request
+type UsageRecorder interface {
+	Record(route, method string, userID uint, role string, status int, duration time.Duration)
+}
+
+// QuotaChecker reports where a user stands against the quota of a route, or nil when the
+// route has no quota for the role
+type QuotaChecker interface {
+	CheckQuota(route, role string, userID uint) (*models.QuotaStatus, error)
+}
+
+// RecordUsage records the route, user, role, status and latency of every request for the
+// usage rollup. Like UsageMetrics, the user is read after the request ran.
+func RecordUsage(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		var userID uint
+		var role string
+		if principal, ok := auth.FromContext(c); ok {
+			userID, role = principal.UserID, usageRole(principal)
+		}
+		recorder.Record(route, c.Request.Method, userID, role, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// EnforceQuota rejects requests with 429 once the user used up the quota admins set on the
+// route for the user's role. It must run after the authentication middleware. Quotas that
+// cannot be checked let the request through.
+func EnforceQuota(checker QuotaChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		status, err := checker.CheckQuota(c.FullPath(), usageRole(principal), principal.UserID)
+		if err != nil {
+			log.Printf("[USAGE] Failed to check quota of %s: %v", c.FullPath(), err)
+			c.Next()
+			return
+		}
+		if status == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-Quota-Limit", strconv.Itoa(status.Quota.MaxRequests))
+		c.Header("X-Quota-Reset", strconv.FormatInt(status.ResetAt.Unix(), 10))
+		if status.Exceeded() {
+			c.Header("X-Quota-Remaining", "0")
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(status.ResetAt).Seconds())+1))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "Quota for this endpoint exceeded, try again later", gin.H{
+				"limit":    status.Quota.MaxRequests,
+				"window":   status.Quota.Window,
+				"reset_at": status.ResetAt,
+			})
+			c.Abort()
+			return
+		}
+
+		// This request uses one of the remaining calls
+		c.Header("X-Quota-Remaining", strconv.FormatInt(status.Remaining-1, 10))
+		c.Next()
+	}
+}
+
+// usageRole is the role usage and quotas are attributed to
+func usageRole(principal *auth.Principal) string {
+	if principal.IsAdmin() {
+		return string(models.AdminType)
+	}
+	if principal.ActiveRole != "" {
+		return principal.ActiveRole
+	}
+	return string(principal.UserType)
+}
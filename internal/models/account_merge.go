@@ -0,0 +1,21 @@
+package models
+
+// AccountMergeRequest is the request body for merging a duplicate user into another
+type AccountMergeRequest struct {
+	SourceUserID uint `json:"source_user_id" binding:"required"` // Duplicate account, soft-deleted after the merge
+	TargetUserID uint `json:"target_user_id" binding:"required"` // Surviving account
+}
+
+// AccountMergeResult reports what was moved to the surviving account
+type AccountMergeResult struct {
+	SourceUserID uint `json:"source_user_id"`
+	TargetUserID uint `json:"target_user_id"`
+	// Moved counts the rows re-pointed per table.column
+	Moved map[string]int64 `json:"moved"`
+	// Dropped counts rows of the duplicate that were removed because the surviving
+	// account already had its own, e.g. a check-in to the same session
+	Dropped map[string]int64 `json:"dropped"`
+	// Skipped lists the table.column pairs left untouched because one of the accounts shares
+	// its ID with a campus user; their rows cannot be told apart from that campus user's
+	Skipped []string `json:"skipped,omitempty"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// Badge identifies an achievement a student can earn in a semester
+type Badge string
+
+const (
+	// FirstCheckInBadge is earned with the first attended meeting of a semester
+	FirstCheckInBadge Badge = "first_check_in"
+	// Streak5Badge is earned by attending 5 meetings in a row
+	Streak5Badge Badge = "streak_5"
+	// Streak10Badge is earned by attending 10 meetings in a row
+	Streak10Badge Badge = "streak_10"
+	// GoalReachedBadge is earned by meeting the semester's attendance goal
+	GoalReachedBadge Badge = "goal_reached"
+	// PerfectAttendanceBadge is earned by attending every meeting, with at least 10 meetings
+	PerfectAttendanceBadge Badge = "perfect_attendance"
+)
+
+// AttendanceGoal is the attendance percentage students should reach in a semester
+type AttendanceGoal struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Prodi         string    `gorm:"size:100;uniqueIndex:idx_goal_scope" json:"prodi"`   // Empty for every prodi
+	Semester      string    `gorm:"size:30;uniqueIndex:idx_goal_scope" json:"semester"` // Empty for every semester
+	TargetPercent float64   `gorm:"not null" json:"target_percent"`
+	UpdatedBy     uint      `json:"updated_by"` // Admin user ID
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the AttendanceGoal model
+func (AttendanceGoal) TableName() string {
+	return "attendance_goals"
+}
+
+// StudentAchievement holds a student's streaks and goal progress in a semester, computed nightly
+type StudentAchievement struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Nim             string    `gorm:"size:20;not null;uniqueIndex:idx_achievement_student" json:"nim"`
+	Semester        string    `gorm:"size:30;not null;uniqueIndex:idx_achievement_student" json:"semester"`
+	Meetings        int       `gorm:"not null" json:"meetings"`         // Closed meetings of the student's courses
+	Attended        int       `gorm:"not null" json:"attended"`         // Present or late
+	WeightedPercent float64   `gorm:"not null" json:"weighted_percent"` // Attendance credit as a percentage
+	CurrentStreak   int       `gorm:"not null" json:"current_streak"`   // Meetings attended in a row up to the latest
+	LongestStreak   int       `gorm:"not null" json:"longest_streak"`
+	GoalPercent     float64   `json:"goal_percent"` // Zero when no goal applies
+	GoalMet         bool      `gorm:"not null;default:false" json:"goal_met"`
+	ComputedAt      time.Time `gorm:"not null" json:"computed_at"`
+}
+
+// TableName sets the table name for the StudentAchievement model
+func (StudentAchievement) TableName() string {
+	return "student_achievements"
+}
+
+// StudentBadge is a badge a student earned in a semester
+type StudentBadge struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Nim       string    `gorm:"size:20;not null;uniqueIndex:idx_badge_student" json:"nim"`
+	Semester  string    `gorm:"size:30;not null;uniqueIndex:idx_badge_student" json:"semester"`
+	Badge     Badge     `gorm:"type:VARCHAR(30);not null;uniqueIndex:idx_badge_student" json:"badge"`
+	AwardedAt time.Time `gorm:"not null" json:"awarded_at"`
+}
+
+// TableName sets the table name for the StudentBadge model
+func (StudentBadge) TableName() string {
+	return "student_badges"
+}
+
+// MeetingAttendance is whether a student attended one closed meeting of their courses
+type MeetingAttendance struct {
+	Nim      string
+	Semester string
+	Status   AttendanceStatus // Empty when the student did not check in
+	Credit   float64
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ActivityCategory represents a kind of mandatory non-academic activity
+type ActivityCategory string
+
+const (
+	// ChapelActivity is a chapel service (ibadah)
+	ChapelActivity ActivityCategory = "chapel"
+	// CharacterBuildingActivity is a character-building session
+	CharacterBuildingActivity ActivityCategory = "character_building"
+	// DormitoryActivity is a dormitory (asrama) activity
+	DormitoryActivity ActivityCategory = "dormitory"
+)
+
+// IsValid checks whether the category is one of the known categories
+func (c ActivityCategory) IsValid() bool {
+	switch c {
+	case ChapelActivity, CharacterBuildingActivity, DormitoryActivity:
+		return true
+	}
+	return false
+}
+
+// Activity represents a single occurrence of a non-academic activity
+type Activity struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Name        string           `gorm:"size:150;not null" json:"name"`
+	Category    ActivityCategory `gorm:"type:VARCHAR(30);not null;index" json:"category"`
+	Semester    string           `gorm:"size:20;not null;index" json:"semester"` // e.g. 2024/2025-1
+	Location    string           `gorm:"size:150" json:"location"`
+	StartsAt    time.Time        `gorm:"not null" json:"starts_at"`
+	EndsAt      time.Time        `gorm:"not null" json:"ends_at"`
+	CreatedBy   uint             `gorm:"not null" json:"created_by"` // Coordinator user ID
+	Description string           `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the Activity model
+func (Activity) TableName() string {
+	return "activities"
+}
+
+// ActivityQuota is the number of activities of a category a student must attend in a semester
+type ActivityQuota struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	Category      ActivityCategory `gorm:"type:VARCHAR(30);not null;uniqueIndex:idx_activity_quota" json:"category"`
+	Semester      string           `gorm:"size:20;not null;uniqueIndex:idx_activity_quota" json:"semester"`
+	RequiredCount int              `gorm:"not null" json:"required_count"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// TableName sets the table name for the ActivityQuota model
+func (ActivityQuota) TableName() string {
+	return "activity_quotas"
+}
+
+// ActivityParticipation records that a student attended an activity
+type ActivityParticipation struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActivityID uint      `gorm:"not null;uniqueIndex:idx_activity_participant" json:"activity_id"`
+	Activity   Activity  `gorm:"foreignKey:ActivityID;constraint:OnDelete:CASCADE" json:"-"`
+	Nim        string    `gorm:"size:20;not null;uniqueIndex:idx_activity_participant;index" json:"nim"`
+	RecordedBy uint      `gorm:"not null" json:"recorded_by"` // Coordinator user ID
+	RecordedAt time.Time `gorm:"not null" json:"recorded_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName sets the table name for the ActivityParticipation model
+func (ActivityParticipation) TableName() string {
+	return "activity_participations"
+}
+
+// ActivityCoordinator grants a user the right to manage activities of a category
+type ActivityCoordinator struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"not null;uniqueIndex:idx_activity_coordinator" json:"user_id"`
+	Category  ActivityCategory `gorm:"type:VARCHAR(30);not null;uniqueIndex:idx_activity_coordinator" json:"category"`
+	CreatedBy uint             `gorm:"not null" json:"created_by"` // Admin user ID
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// TableName sets the table name for the ActivityCoordinator model
+func (ActivityCoordinator) TableName() string {
+	return "activity_coordinators"
+}
+
+// ActivityProgress summarizes a student's participation in one category for a semester
+type ActivityProgress struct {
+	Category      ActivityCategory `json:"category"`
+	Semester      string           `json:"semester"`
+	RequiredCount int              `json:"required_count"`
+	AttendedCount int              `json:"attended_count"`
+	Remaining     int              `json:"remaining"`
+	Completed     bool             `json:"completed"`
+}
@@ -33,6 +33,9 @@ const (
 	StandardAdminAccess AccessLevel = "standard"
 	// LimitedAdminAccess has restricted access
 	LimitedAdminAccess AccessLevel = "limited"
+	// ViewerAccess can only read reports, such as the institutional research office, and sees
+	// students pseudonymized unless granted students:deanonymize
+	ViewerAccess AccessLevel = "viewer"
 )
 
 // AdminResponse represents the admin data returned in API responses
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// AdminPermission identifies an admin feature that can be granted to an access level
+type AdminPermission string
+
+const (
+	// ManageAPIKeysPermission allows issuing and revoking API keys
+	ManageAPIKeysPermission AdminPermission = "api_keys:manage"
+	// ManageActivitiesPermission allows configuring activity quotas and coordinators
+	ManageActivitiesPermission AdminPermission = "activities:manage"
+	// ManageInternshipsPermission allows running internship jobs
+	ManageInternshipsPermission AdminPermission = "internships:manage"
+	// ViewReportsPermission allows reading reports
+	ViewReportsPermission AdminPermission = "reports:view"
+	// ViewUsersPermission allows listing user accounts
+	ViewUsersPermission AdminPermission = "users:view"
+	// RevokeSessionsPermission allows ending every session of a campus user
+	RevokeSessionsPermission AdminPermission = "sessions:revoke"
+	// MergeUsersPermission allows merging duplicate user accounts
+	MergeUsersPermission AdminPermission = "users:merge"
+	// ManagePermissionsPermission allows changing the permissions of access levels
+	ManagePermissionsPermission AdminPermission = "permissions:manage"
+	// ManageOperationsPermission allows running backups, recording restore drills, publishing
+	// status banners and retrying failed emails
+	ManageOperationsPermission AdminPermission = "operations:manage"
+	// ManageSchedulesPermission allows creating and changing class schedules
+	ManageSchedulesPermission AdminPermission = "schedules:manage"
+	// ManageEnrollmentsPermission allows enrolling students into courses
+	ManageEnrollmentsPermission AdminPermission = "enrollments:manage"
+	// ManageBookingsPermission allows approving room bookings as facilities admin
+	ManageBookingsPermission AdminPermission = "bookings:manage"
+	// ManageAttendancePoliciesPermission allows changing how late check-ins are credited and the
+	// hours in which check-ins are rejected
+	ManageAttendancePoliciesPermission AdminPermission = "attendance_policies:manage"
+	// ManageBrandingPermission allows changing the logo, colors and footer of outgoing emails
+	ManageBrandingPermission AdminPermission = "branding:manage"
+	// SyncCampusDataPermission allows importing profiles in bulk from the campus API
+	SyncCampusDataPermission AdminPermission = "campus_sync:run"
+	// InvestigateStudentsPermission allows reading the check-in, device and login history of
+	// students when investigating suspected fraud. No access level other than super admin
+	// holds it until it is granted explicitly.
+	InvestigateStudentsPermission AdminPermission = "investigations:view"
+	// DeanonymizeStudentsPermission allows seeing the NIM, user ID and name of students in
+	// reports and exports; without it they are replaced with pseudonyms
+	DeanonymizeStudentsPermission AdminPermission = "students:deanonymize"
+)
+
+// KnownAdminPermissions lists every permission that can be granted to an access level
+var KnownAdminPermissions = []AdminPermission{
+	ManageAPIKeysPermission,
+	ManageActivitiesPermission,
+	ManageInternshipsPermission,
+	ViewReportsPermission,
+	ViewUsersPermission,
+	RevokeSessionsPermission,
+	MergeUsersPermission,
+	ManagePermissionsPermission,
+	ManageOperationsPermission,
+	ManageSchedulesPermission,
+	ManageEnrollmentsPermission,
+	ManageBookingsPermission,
+	ManageAttendancePoliciesPermission,
+	ManageBrandingPermission,
+	SyncCampusDataPermission,
+	InvestigateStudentsPermission,
+	DeanonymizeStudentsPermission,
+}
+
+// IsKnownAdminPermission checks whether permission is a grantable permission
+func IsKnownAdminPermission(permission AdminPermission) bool {
+	for _, known := range KnownAdminPermissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAccessLevel checks whether level is one of the defined access levels
+func IsValidAccessLevel(level AccessLevel) bool {
+	switch level {
+	case SuperAdminAccess, StandardAdminAccess, LimitedAdminAccess, ViewerAccess:
+		return true
+	}
+	return false
+}
+
+// AtLeast reports whether level is as high as min; viewers and unknown levels rank below every
+// other level
+func (level AccessLevel) AtLeast(min AccessLevel) bool {
+	rank := map[AccessLevel]int{LimitedAdminAccess: 1, StandardAdminAccess: 2, SuperAdminAccess: 3}
+	return rank[level] > 0 && rank[level] >= rank[min]
+}
+
+// DefaultAccessLevelPermissions are used for access levels that have not been customized.
+// Super admins always hold every permission so they cannot lock themselves out.
+var DefaultAccessLevelPermissions = map[AccessLevel][]AdminPermission{
+	SuperAdminAccess: KnownAdminPermissions,
+	StandardAdminAccess: {
+		ManageAPIKeysPermission,
+		ManageActivitiesPermission,
+		ManageInternshipsPermission,
+		ViewReportsPermission,
+		ViewUsersPermission,
+		RevokeSessionsPermission,
+		ManageSchedulesPermission,
+		ManageEnrollmentsPermission,
+		ManageBookingsPermission,
+		ManageAttendancePoliciesPermission,
+		ManageBrandingPermission,
+		SyncCampusDataPermission,
+		DeanonymizeStudentsPermission,
+	},
+	LimitedAdminAccess: {
+		ViewReportsPermission,
+		DeanonymizeStudentsPermission,
+	},
+	ViewerAccess: {
+		ViewReportsPermission,
+	},
+}
+
+// AccessLevelPolicy stores the customized permissions of an access level
+type AccessLevelPolicy struct {
+	AccessLevel AccessLevel `gorm:"type:VARCHAR(20);primaryKey" json:"access_level"`
+	Permissions string      `gorm:"type:text;not null" json:"-"` // Comma-separated list of permissions
+	UpdatedBy   uint        `json:"updated_by"`                  // Admin user ID
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TableName sets the table name for the AccessLevelPolicy model
+func (AccessLevelPolicy) TableName() string {
+	return "access_level_policies"
+}
+
+// PermissionList returns the permissions granted by the policy
+func (p *AccessLevelPolicy) PermissionList() []AdminPermission {
+	permissions := []AdminPermission{}
+	for _, permission := range strings.Split(p.Permissions, ",") {
+		permission = strings.TrimSpace(permission)
+		if permission != "" {
+			permissions = append(permissions, AdminPermission(permission))
+		}
+	}
+	return permissions
+}
+
+// SetPermissions stores the permissions granted by the policy
+func (p *AccessLevelPolicy) SetPermissions(permissions []AdminPermission) {
+	values := make([]string, len(permissions))
+	for i, permission := range permissions {
+		values[i] = string(permission)
+	}
+	p.Permissions = strings.Join(values, ",")
+}
+
+// AccessLevelPermissions is the effective permission set of an access level
+type AccessLevelPermissions struct {
+	AccessLevel AccessLevel       `json:"access_level"`
+	Permissions []AdminPermission `json:"permissions"`
+	Customized  bool              `json:"customized"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+}
+
+// HasPermission checks whether the permission set contains permission
+func (p *AccessLevelPermissions) HasPermission(permission AdminPermission) bool {
+	if p.AccessLevel == SuperAdminAccess {
+		return true
+	}
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIOperation identifies an operation an API key may be allowed to call
+type APIOperation string
+
+const (
+	// ExamAttendanceWriteOperation allows marking exam attendance
+	ExamAttendanceWriteOperation APIOperation = "exam_attendance:write"
+	// ExamAttendanceReadOperation allows reading recorded exam attendance
+	ExamAttendanceReadOperation APIOperation = "exam_attendance:read"
+)
+
+// KnownAPIOperations lists every operation that can be granted to an API key
+var KnownAPIOperations = []APIOperation{
+	ExamAttendanceWriteOperation,
+	ExamAttendanceReadOperation,
+}
+
+// IsKnownAPIOperation checks whether op is a grantable operation
+func IsKnownAPIOperation(op APIOperation) bool {
+	for _, known := range KnownAPIOperations {
+		if known == op {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey represents a credential issued to an external system (e.g. the exam proctoring tool)
+type APIKey struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Name       string         `gorm:"size:100;not null" json:"name"`
+	Prefix     string         `gorm:"size:12;index;not null" json:"prefix"`  // First characters of the key, for identification
+	KeyHash    string         `gorm:"size:64;uniqueIndex;not null" json:"-"` // SHA-256 of the full key
+	Operations string         `gorm:"type:text;not null" json:"-"`           // Comma-separated allowlist of operations
+	CreatedBy  uint           `gorm:"not null" json:"created_by"`            // Admin user ID
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// OperationList returns the allowlisted operations of the key
+func (k *APIKey) OperationList() []APIOperation {
+	var ops []APIOperation
+	for _, op := range strings.Split(k.Operations, ",") {
+		op = strings.TrimSpace(op)
+		if op != "" {
+			ops = append(ops, APIOperation(op))
+		}
+	}
+	return ops
+}
+
+// SetOperations stores the allowlisted operations of the key
+func (k *APIKey) SetOperations(ops []APIOperation) {
+	values := make([]string, len(ops))
+	for i, op := range ops {
+		values[i] = string(op)
+	}
+	k.Operations = strings.Join(values, ",")
+}
+
+// Allows checks whether the key may call the given operation
+func (k *APIKey) Allows(op APIOperation) bool {
+	for _, allowed := range k.OperationList() {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUsable checks that the key is neither revoked nor expired
+func (k *APIKey) IsUsable() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
+
+// APIKeyResponse represents the API key data returned in API responses
+type APIKeyResponse struct {
+	ID         uint           `json:"id"`
+	Name       string         `json:"name"`
+	Prefix     string         `json:"prefix"`
+	Operations []APIOperation `json:"operations"`
+	CreatedBy  uint           `json:"created_by"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time     `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// ToAPIKeyResponse converts an APIKey to APIKeyResponse
+func (k *APIKey) ToAPIKeyResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Operations: k.OperationList(),
+		CreatedBy:  k.CreatedBy,
+		LastUsedAt: k.LastUsedAt,
+		ExpiresAt:  k.ExpiresAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
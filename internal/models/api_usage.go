@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// APIUsageRollup counts the requests one user made to one endpoint within an hour
+type APIUsageRollup struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Bucket          time.Time `gorm:"not null;uniqueIndex:idx_api_usage_rollup,priority:1" json:"bucket"` // Start of the hour
+	Route           string    `gorm:"size:200;not null;uniqueIndex:idx_api_usage_rollup,priority:2" json:"route"`
+	Method          string    `gorm:"size:10;not null;uniqueIndex:idx_api_usage_rollup,priority:3" json:"method"`
+	UserID          uint      `gorm:"not null;default:0;uniqueIndex:idx_api_usage_rollup,priority:4" json:"user_id"` // Zero for anonymous requests
+	Role            string    `gorm:"size:20;not null;default:'';uniqueIndex:idx_api_usage_rollup,priority:5" json:"role"`
+	Requests        int64     `gorm:"not null;default:0" json:"requests"`
+	Errors          int64     `gorm:"not null;default:0" json:"errors"`    // 5xx responses
+	Throttled       int64     `gorm:"not null;default:0" json:"throttled"` // Rejected by a quota, not counted against it
+	TotalDurationMs float64   `gorm:"not null;default:0" json:"total_duration_ms"`
+	MaxDurationMs   float64   `gorm:"not null;default:0" json:"max_duration_ms"`
+}
+
+// TableName sets the table name for the APIUsageRollup model
+func (APIUsageRollup) TableName() string {
+	return "api_usage_rollups"
+}
+
+// APIUsageSummary is the usage of the endpoints, users or roles grouped by in a usage report.
+// Dimensions that were not grouped by are left empty.
+type APIUsageSummary struct {
+	Route         string  `json:"route,omitempty"`
+	Method        string  `json:"method,omitempty"`
+	UserID        uint    `json:"user_id,omitempty"`
+	Role          string  `json:"role,omitempty"`
+	Requests      int64   `json:"requests"`
+	Errors        int64   `json:"errors"`
+	Throttled     int64   `json:"throttled"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+	MaxDurationMs float64 `json:"max_duration_ms"`
+}
+
+// UsageDimensions maps the dimensions a usage report can be grouped by to their columns
+var UsageDimensions = map[string]string{
+	"route":  "route",
+	"method": "method",
+	"user":   "user_id",
+	"role":   "role",
+}
+
+// QuotaWindow is the fixed period a usage quota counts requests in
+type QuotaWindow string
+
+const (
+	// QuotaHourly resets at the start of every hour
+	QuotaHourly QuotaWindow = "hour"
+	// QuotaDaily resets at midnight
+	QuotaDaily QuotaWindow = "day"
+)
+
+// Bounds returns the start and end of the window containing t
+func (w QuotaWindow) Bounds(t time.Time) (time.Time, time.Time) {
+	if w == QuotaDaily {
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 1)
+	}
+	start := t.Truncate(time.Hour)
+	return start, start.Add(time.Hour)
+}
+
+// UsageQuota limits how often each user of a role may call an expensive endpoint
+type UsageQuota struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	Route       string      `gorm:"size:200;not null;uniqueIndex:idx_usage_quota_route_role,priority:1" json:"route"`          // Route pattern, e.g. /api/v1/mahasiswa/complete
+	Role        string      `gorm:"size:20;not null;default:'';uniqueIndex:idx_usage_quota_route_role,priority:2" json:"role"` // Empty for every role without its own quota
+	MaxRequests int         `gorm:"not null" json:"max_requests"`
+	Window      QuotaWindow `gorm:"column:quota_window;type:VARCHAR(10);not null" json:"window"`
+	UpdatedBy   uint        `json:"updated_by"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TableName sets the table name for the UsageQuota model
+func (UsageQuota) TableName() string {
+	return "usage_quotas"
+}
+
+// Validate checks the quota before it is saved
+func (q *UsageQuota) Validate() error {
+	if q.Route == "" || q.Route[0] != '/' {
+		return errors.New("route must be a route pattern starting with /")
+	}
+	if q.MaxRequests < 1 {
+		return errors.New("max_requests must be at least 1")
+	}
+	if q.Window != QuotaHourly && q.Window != QuotaDaily {
+		return errors.New("window must be hour or day")
+	}
+	return nil
+}
+
+// QuotaStatus is where a user stands against the quota of an endpoint
+type QuotaStatus struct {
+	Quota     *UsageQuota
+	Used      int64
+	Remaining int64
+	ResetAt   time.Time
+}
+
+// Exceeded reports whether the user used up the quota
+func (s *QuotaStatus) Exceeded() bool {
+	return s.Remaining <= 0
+}
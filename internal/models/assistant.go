@@ -12,8 +12,8 @@ type Assistant struct {
 	AssistantUserID uint   `gorm:"unique;not null" json:"assistant_user_id"` // Local app user ID
 	EmployeeID      uint   `json:"pegawai_id"`                               // From campus API - pegawai_id
 	IdentityNumber  string `json:"nip"`                                      // From campus API - nip
-	FullName        string `json:"nama"`                                     // From campus API - nama
-	Email           string `json:"email"`                                    // From campus API - email
+	FullName        string `json:"nama"`                                     // From campus API - nama, can be edited by user
+	Email           string `json:"email"`                                    // From campus API - email, can be edited by user
 	Username        string `json:"user_name"`                                // From campus API - user_name
 	CampusUserID    uint   `json:"user_id"`                                  // Campus UserID from API - user_id
 	Alias           string `json:"alias"`                                    // From campus API - alias (with space)
@@ -71,10 +71,12 @@ func AutoMigrateAssistant(db *gorm.DB) error {
 }
 
 // IsUserEditable checks if a field can be edited by the user
-// Fields from the campus API should not be editable by the user
+// Fields from the campus API should not be editable by the user, except the synced fields
 func (a *Assistant) IsUserEditable(fieldName string) bool {
 	// List of fields that can be edited by the user
 	editableFields := map[string]bool{
+		"full_name":    true,
+		"email":        true,
 		"avatar":       true,
 		"biography":    true,
 		"phone_number": true,
@@ -88,6 +90,8 @@ func (a *Assistant) IsUserEditable(fieldName string) bool {
 // GetEditableFields returns a map of all user-editable fields
 func (a *Assistant) GetEditableFields() map[string]interface{} {
 	return map[string]interface{}{
+		"full_name":    a.FullName,
+		"email":        a.Email,
 		"avatar":       a.Avatar,
 		"biography":    a.Biography,
 		"phone_number": a.PhoneNumber,
@@ -116,8 +120,6 @@ func (a *Assistant) GetReadOnlyFields() map[string]interface{} {
 
 	return map[string]interface{}{
 		"identity_number":      a.IdentityNumber,
-		"full_name":            a.FullName,
-		"email":                a.Email,
 		"username":             a.Username,
 		"department":           a.Department,
 		"position":             a.Position,
@@ -129,3 +131,12 @@ func (a *Assistant) GetReadOnlyFields() map[string]interface{} {
 		"alias":                a.Alias,
 	}
 }
+
+// SyncedFields returns the fields both the campus API and the user can change, by field name.
+// Sync resolves changes from both sides with the conflict policy of the field.
+func (a *Assistant) SyncedFields() map[string]*string {
+	return map[string]*string{
+		"full_name": &a.FullName,
+		"email":     &a.Email,
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssistantPermission identifies what an assistant may do in a course they are assigned to
+type AssistantPermission string
+
+const (
+	// OpenSessionsPermission allows opening, closing and showing the QR code of sessions
+	OpenSessionsPermission AssistantPermission = "sessions:open"
+	// EditRecordsPermission allows correcting the attendance status of students
+	EditRecordsPermission AssistantPermission = "records:edit"
+	// ViewCourseReportsPermission allows reading session records and the course recap
+	ViewCourseReportsPermission AssistantPermission = "reports:view"
+	// ApproveExcusesPermission allows deciding permission (izin and sakit) requests
+	ApproveExcusesPermission AssistantPermission = "excuses:approve"
+)
+
+// KnownAssistantPermissions lists every permission a lecturer can grant to an assistant
+var KnownAssistantPermissions = []AssistantPermission{
+	OpenSessionsPermission,
+	EditRecordsPermission,
+	ViewCourseReportsPermission,
+	ApproveExcusesPermission,
+}
+
+// IsKnownAssistantPermission checks whether permission can be granted to an assistant
+func IsKnownAssistantPermission(permission AssistantPermission) bool {
+	for _, known := range KnownAssistantPermissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AssistantAssignment assigns an assistant to a course offering with the permissions granted
+// by the coordinating lecturer
+type AssistantAssignment struct {
+	ID              uint                  `gorm:"primaryKey" json:"id"`
+	AssistantUserID uint                  `gorm:"not null;uniqueIndex:idx_assistant_offering" json:"assistant_user_id"`
+	CourseCode      string                `gorm:"size:20;not null;uniqueIndex:idx_assistant_offering" json:"course_code"`
+	ClassName       string                `gorm:"size:50;uniqueIndex:idx_assistant_offering" json:"class_name"` // Empty for every class of the course
+	Semester        string                `gorm:"size:30;not null;uniqueIndex:idx_assistant_offering" json:"semester"`
+	Permissions     string                `gorm:"type:text;not null" json:"-"` // Comma-separated list of permissions
+	Granted         []AssistantPermission `gorm:"-" json:"permissions"`
+	AssignedBy      uint                  `gorm:"not null" json:"assigned_by"` // Lecturer user ID
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// TableName sets the table name for the AssistantAssignment model
+func (AssistantAssignment) TableName() string {
+	return "assistant_assignments"
+}
+
+// AfterFind exposes the stored permissions as a list
+func (a *AssistantAssignment) AfterFind(tx *gorm.DB) error {
+	a.Granted = a.PermissionList()
+	return nil
+}
+
+// PermissionList returns the permissions granted by the assignment
+func (a *AssistantAssignment) PermissionList() []AssistantPermission {
+	permissions := []AssistantPermission{}
+	for _, permission := range strings.Split(a.Permissions, ",") {
+		permission = strings.TrimSpace(permission)
+		if permission != "" {
+			permissions = append(permissions, AssistantPermission(permission))
+		}
+	}
+	return permissions
+}
+
+// SetPermissions stores the permissions granted by the assignment
+func (a *AssistantAssignment) SetPermissions(permissions []AssistantPermission) {
+	values := make([]string, len(permissions))
+	for i, permission := range permissions {
+		values[i] = string(permission)
+	}
+	a.Permissions = strings.Join(values, ",")
+	a.Granted = permissions
+}
+
+// HasPermission checks whether the assignment grants permission
+func (a *AssistantAssignment) HasPermission(permission AssistantPermission) bool {
+	for _, granted := range a.PermissionList() {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers checks whether the assignment applies to a course offering
+func (a *AssistantAssignment) Covers(offering CourseOffering) bool {
+	return a.CourseCode == offering.CourseCode &&
+		(offering.Semester == "" || a.Semester == offering.Semester) &&
+		(a.ClassName == "" || a.ClassName == offering.ClassName)
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceSessionStatus represents whether students can still check in to a session
+type AttendanceSessionStatus string
+
+const (
+	// SessionScheduled was created ahead of time, e.g. from a room booking, and is not open yet
+	SessionScheduled AttendanceSessionStatus = "scheduled"
+	// SessionOpen accepts check-ins
+	SessionOpen AttendanceSessionStatus = "open"
+	// SessionClosed no longer accepts check-ins
+	SessionClosed AttendanceSessionStatus = "closed"
+)
+
+// AttendanceStatus represents a student's attendance in a session
+type AttendanceStatus string
+
+const (
+	// AttendancePresent means the student checked in
+	AttendancePresent AttendanceStatus = "present"
+	// AttendanceLate means the student checked in after the session started
+	AttendanceLate AttendanceStatus = "late"
+	// AttendanceExcused means the student was excused from the meeting
+	AttendanceExcused AttendanceStatus = "excused"
+	// AttendanceAbsent is reported for enrolled students without a record; it is never stored
+	AttendanceAbsent AttendanceStatus = "absent"
+)
+
+// CheckInMethod records how a student checked in
+type CheckInMethod string
+
+const (
+	// CheckInManual is a check-in by session ID
+	CheckInManual CheckInMethod = "manual"
+	// CheckInQR is a check-in by scanning the session's rotating QR code
+	CheckInQR CheckInMethod = "qr"
+	// CheckInPermission is an excused record created from an approved permission request
+	CheckInPermission CheckInMethod = "permission"
+)
+
+// AttendanceSession is a class meeting opened by a lecturer for students to check in to
+type AttendanceSession struct {
+	ID             uint     `gorm:"primaryKey" json:"id"`
+	LecturerUserID uint     `gorm:"not null;index" json:"lecturer_user_id"` // Lecturer user ID
+	CourseCode     string   `gorm:"size:20;not null;index" json:"course_code"`
+	CourseName     string   `gorm:"size:150" json:"course_name"`
+	ClassName      string   `gorm:"size:50" json:"class_name"` // e.g. 12IF1
+	Semester       string   `gorm:"size:30" json:"semester"`   // Matched against enrollments when set
+	MeetingNumber  int      `gorm:"not null" json:"meeting_number"`
+	Topic          string   `gorm:"size:200" json:"topic"`
+	Room           string   `gorm:"size:50" json:"room"`
+	Latitude       *float64 `json:"latitude"` // Center of the geofence, taken from the room unless given
+	Longitude      *float64 `json:"longitude"`
+	GeofenceRadius int      `gorm:"not null;default:0" json:"geofence_radius"` // Meters; zero disables the geofence
+	// AnchorToLecturer centers the geofence on the location of the lecturer's device instead of
+	// the room, for sessions held somewhere else than planned
+	AnchorToLecturer bool                    `gorm:"default:false" json:"anchor_to_lecturer"`
+	AnchoredAt       *time.Time              `json:"anchored_at,omitempty"`                  // When the lecturer's device last checked in
+	RequireQR        bool                    `gorm:"default:false" json:"require_qr"`        // Only accept check-ins by QR code
+	Capacity         int                     `gorm:"not null;default:0" json:"capacity"`     // Check-ins beyond it are recorded as overflow; zero means no limit
+	Optional         bool                    `gorm:"not null;default:false" json:"optional"` // Recorded but left out of attendance percentages, e.g. guest lectures
+	Status           AttendanceSessionStatus `gorm:"type:VARCHAR(20);not null;default:'open';index" json:"status"`
+	ScheduledStart   *time.Time              `json:"scheduled_start"` // Set on sessions created ahead of time
+	OpenedAt         time.Time               `gorm:"not null" json:"opened_at"`
+	ClosedAt         *time.Time              `json:"closed_at"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the AttendanceSession model
+func (AttendanceSession) TableName() string {
+	return "attendance_sessions"
+}
+
+// IsOpen checks whether the session still accepts check-ins
+func (s *AttendanceSession) IsOpen() bool {
+	return s.Status == SessionOpen
+}
+
+// HasGeofence checks whether check-ins must be made near the session's location
+func (s *AttendanceSession) HasGeofence() bool {
+	return s.Latitude != nil && s.Longitude != nil && s.GeofenceRadius > 0
+}
+
+// AwaitingAnchor checks whether the session's geofence waits for the lecturer's device check-in
+func (s *AttendanceSession) AwaitingAnchor() bool {
+	return s.AnchorToLecturer && s.AnchoredAt == nil
+}
+
+// AttendanceRecord is a student's attendance in a session
+type AttendanceRecord struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	SessionID     uint              `gorm:"not null;uniqueIndex:idx_attendance_student" json:"session_id"`
+	Session       AttendanceSession `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"-"`
+	StudentUserID uint              `gorm:"not null;uniqueIndex:idx_attendance_student;index" json:"student_user_id"` // Campus user ID of the student
+	Nim           string            `gorm:"size:20;not null;index" json:"nim"`
+	Status        AttendanceStatus  `gorm:"type:VARCHAR(20);not null" json:"status"`
+	Method        CheckInMethod     `gorm:"type:VARCHAR(20);not null;default:'manual'" json:"method"`
+	Distance      *float64          `json:"distance,omitempty"`   // Meters from the session's location when geofenced
+	FaceScore     *float64          `json:"face_score,omitempty"` // Similarity to the registered face when verified
+	LateMinutes   int               `gorm:"not null;default:0" json:"late_minutes"`
+	Credit        float64           `gorm:"not null;default:1" json:"credit"`       // Attendance credit from the late policy, 0 to 1
+	Overflow      bool              `gorm:"not null;default:false" json:"overflow"` // Checked in after the session was full; counts until the lecturer removes it
+	CheckedInAt   time.Time         `gorm:"not null" json:"checked_in_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// TableName sets the table name for the AttendanceRecord model
+func (AttendanceRecord) TableName() string {
+	return "attendance_records"
+}
+
+// AttendanceRecapFilter selects the sessions of a course recap
+type AttendanceRecapFilter struct {
+	LecturerUserID uint // Optional; lecturers only see their own sessions
+	CourseCode     string
+	Semester       string // Optional
+	ClassName      string // Optional
+}
+
+// AttendanceRecapMeeting is one meeting (column) of a course recap
+type AttendanceRecapMeeting struct {
+	SessionID     uint      `json:"session_id"`
+	MeetingNumber int       `json:"meeting_number"`
+	Optional      bool      `json:"optional"` // Shown but not counted in the totals
+	OpenedAt      time.Time `json:"opened_at"`
+}
+
+// AttendanceRecapCell is a student's status in one meeting of a course recap
+type AttendanceRecapCell struct {
+	SessionID     uint             `json:"session_id"`
+	MeetingNumber int              `json:"meeting_number"`
+	Status        AttendanceStatus `json:"status"`
+	Credit        float64          `json:"credit"`
+}
+
+// AttendanceRecapStudent is one student (row) of a course recap. The totals only count
+// meetings that are not optional.
+type AttendanceRecapStudent struct {
+	Nim             string                `json:"nim"`
+	Present         int                   `json:"present"`
+	Late            int                   `json:"late"`
+	Excused         int                   `json:"excused"`
+	Absent          int                   `json:"absent"`
+	Credit          float64               `json:"credit"`           // Sum of the credit of every counted meeting
+	WeightedPercent float64               `json:"weighted_percent"` // Credit as a percentage of the counted meetings
+	Meetings        []AttendanceRecapCell `gorm:"-" json:"meetings"`
+}
+
+// AttendanceRecap is the attendance of every student in every meeting of a course
+type AttendanceRecap struct {
+	CourseCode string                   `json:"course_code"`
+	Semester   string                   `json:"semester"`
+	ClassName  string                   `json:"class_name"`
+	Meetings   []AttendanceRecapMeeting `json:"meetings"`
+	Students   []AttendanceRecapStudent `json:"students"`
+}
+
+// LecturerMeetingCount is how many counted meetings of a course a lecturer held
+type LecturerMeetingCount struct {
+	LecturerUserID uint `json:"lecturer_user_id"`
+	Meetings       int  `json:"meetings"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// CheckInLecturerEdit is a record created by a lecturer marking an absent student as attending
+const CheckInLecturerEdit CheckInMethod = "lecturer_edit"
+
+// AttendanceEdit is a manual correction of a student's attendance in a session. Edits are
+// never changed or deleted, so they form the trail of every status set by hand.
+type AttendanceEdit struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	SessionID      uint             `gorm:"not null;index" json:"session_id"`
+	StudentUserID  uint             `gorm:"not null;index" json:"student_user_id"` // Campus user ID of the student
+	Nim            string           `gorm:"size:20;not null" json:"nim"`
+	RecordID       *uint            `json:"record_id,omitempty"` // Nil when the edit marked the student absent
+	PreviousStatus AttendanceStatus `gorm:"type:VARCHAR(20);not null" json:"previous_status"`
+	PreviousCredit float64          `gorm:"not null" json:"previous_credit"`
+	NewStatus      AttendanceStatus `gorm:"type:VARCHAR(20);not null" json:"new_status"`
+	NewCredit      float64          `gorm:"not null" json:"new_credit"`
+	Reason         string           `gorm:"size:500" json:"reason,omitempty"`
+	EditedByUserID uint             `gorm:"not null" json:"edited_by_user_id"`
+	EditedAt       time.Time        `gorm:"not null" json:"edited_at"`
+}
+
+// TableName sets the table name for the AttendanceEdit model
+func (AttendanceEdit) TableName() string {
+	return "attendance_edits"
+}
+
+// Changed checks whether the edit changes the student's status or credit
+func (e *AttendanceEdit) Changed() bool {
+	return e.PreviousStatus != e.NewStatus || e.PreviousCredit != e.NewCredit
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultAttendanceScoreWeight is the share of the final grade attendance counts for when no
+// policy is set, in percent
+const DefaultAttendanceScoreWeight = 10.0
+
+// ScoreFormula is how an attendance percentage is turned into a score from 0 to 100
+type ScoreFormula string
+
+const (
+	// LinearScore takes the attendance percentage as the score
+	LinearScore ScoreFormula = "linear"
+	// TieredScore gives the score of the highest tier the attendance percentage reaches
+	TieredScore ScoreFormula = "tiered"
+)
+
+// ScoreTier scores attendance of at least MinPercent
+type ScoreTier struct {
+	MinPercent float64 `json:"min_percent"`
+	Score      float64 `json:"score"` // 0 to 100
+}
+
+// AttendanceScorePolicy turns the attendance of a course into a component of the final grade,
+// e.g. attendance counts for 10% of the grade and 90% attendance scores 100
+type AttendanceScorePolicy struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	CourseCode string       `gorm:"size:20;uniqueIndex" json:"course_code"` // Empty for the default policy
+	Weight     float64      `gorm:"not null" json:"weight"`                 // Share of the final grade in percent
+	Formula    ScoreFormula `gorm:"type:VARCHAR(20);not null;default:'linear'" json:"formula"`
+	Tiers      []ScoreTier  `gorm:"serializer:json;type:text" json:"tiers"` // Only used by the tiered formula
+	UpdatedBy  uint         `json:"updated_by"`                             // Admin user ID
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// TableName sets the table name for the AttendanceScorePolicy model
+func (AttendanceScorePolicy) TableName() string {
+	return "attendance_score_policies"
+}
+
+// Validate checks the weight, the formula and that tiers are in ascending order of attendance
+func (p *AttendanceScorePolicy) Validate() error {
+	if p.Weight <= 0 || p.Weight > 100 {
+		return errors.New("weight must be above 0 and at most 100")
+	}
+	switch p.Formula {
+	case LinearScore:
+		p.Tiers = nil
+		return nil
+	case TieredScore:
+	default:
+		return errors.New("formula must be linear or tiered")
+	}
+	if len(p.Tiers) == 0 {
+		return errors.New("a tiered formula needs at least one tier")
+	}
+	for i, tier := range p.Tiers {
+		if tier.MinPercent < 0 || tier.MinPercent > 100 {
+			return errors.New("tier min_percent must be between 0 and 100")
+		}
+		if i > 0 && tier.MinPercent <= p.Tiers[i-1].MinPercent {
+			return errors.New("tiers must be sorted by min_percent without duplicates")
+		}
+		if tier.Score < 0 || tier.Score > 100 {
+			return errors.New("tier score must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// Score returns the score from 0 to 100 of an attendance percentage. Without a policy the
+// percentage is the score; attendance below the first tier scores 0.
+func (p *AttendanceScorePolicy) Score(weightedPercent float64) float64 {
+	if p == nil || p.Formula != TieredScore {
+		return weightedPercent
+	}
+	score := 0.0
+	for _, tier := range p.Tiers {
+		if weightedPercent < tier.MinPercent {
+			break
+		}
+		score = tier.Score
+	}
+	return score
+}
+
+// WeightOrDefault returns the share of the final grade of the policy, or the default without one
+func (p *AttendanceScorePolicy) WeightOrDefault() float64 {
+	if p == nil {
+		return DefaultAttendanceScoreWeight
+	}
+	return p.Weight
+}
+
+// AttendanceScoreStudent is the attendance score of one student of a course
+type AttendanceScoreStudent struct {
+	Nim             string  `json:"nim"`
+	WeightedPercent float64 `json:"weighted_percent"`
+	Score           float64 `json:"score"`          // 0 to 100
+	WeightedScore   float64 `json:"weighted_score"` // Points of the final grade, score times weight
+}
+
+// AttendanceScoreList is the attendance component of the final grade of every student of a
+// course, exported to the grading system
+type AttendanceScoreList struct {
+	CourseCode string                   `json:"course_code"`
+	Semester   string                   `json:"semester"`
+	ClassName  string                   `json:"class_name"`
+	Formula    ScoreFormula             `json:"formula"`
+	Weight     float64                  `json:"weight"`
+	Students   []AttendanceScoreStudent `json:"students"`
+}
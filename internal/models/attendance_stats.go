@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// AttendanceStudentStat is the attendance of a student in a course offering (course, class
+// and semester), precomputed by the attendance_stats job so reports do not scan every record.
+// It counts like the course recap: optional meetings are left out of every count.
+type AttendanceStudentStat struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	CourseCode      string    `gorm:"size:20;not null;uniqueIndex:idx_attendance_student_stat" json:"course_code"`
+	Semester        string    `gorm:"size:30;not null;uniqueIndex:idx_attendance_student_stat;index:idx_attendance_student_stat_prodi" json:"semester"`
+	ClassName       string    `gorm:"size:50;not null;uniqueIndex:idx_attendance_student_stat" json:"class_name"`
+	Nim             string    `gorm:"size:20;not null;uniqueIndex:idx_attendance_student_stat;index" json:"nim"`
+	ProdiID         int       `gorm:"not null;default:0;index:idx_attendance_student_stat_prodi" json:"prodi_id"` // Zero when the student was never synced
+	ProdiName       string    `gorm:"size:150" json:"prodi_name"`
+	Meetings        int       `gorm:"not null" json:"meetings"`         // Meetings of the offering, optional ones included
+	CountedMeetings int       `gorm:"not null" json:"counted_meetings"` // Meetings that count towards the percentage
+	Present         int       `gorm:"not null" json:"present"`
+	Late            int       `gorm:"not null" json:"late"`
+	Excused         int       `gorm:"not null" json:"excused"`
+	Absent          int       `gorm:"not null" json:"absent"`
+	Credit          float64   `gorm:"not null" json:"credit"`
+	WeightedPercent float64   `gorm:"not null" json:"weighted_percent"`
+	RefreshedAt     time.Time `gorm:"not null" json:"refreshed_at"`
+}
+
+// TableName sets the table name for the AttendanceStudentStat model
+func (AttendanceStudentStat) TableName() string {
+	return "attendance_student_stats"
+}
+
+// AttendanceProdiStat rolls the attendance of the students of a prodi up per semester
+type AttendanceProdiStat struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ProdiID        int       `gorm:"not null;uniqueIndex:idx_attendance_prodi_stat" json:"prodi_id"` // Zero for students who were never synced
+	ProdiName      string    `gorm:"size:150" json:"prodi_name"`
+	Semester       string    `gorm:"size:30;not null;uniqueIndex:idx_attendance_prodi_stat" json:"semester"`
+	Students       int       `gorm:"not null" json:"students"`
+	Enrollments    int       `gorm:"not null" json:"enrollments"` // Students times the course offerings they attend
+	Present        int       `gorm:"not null" json:"present"`
+	Late           int       `gorm:"not null" json:"late"`
+	Excused        int       `gorm:"not null" json:"excused"`
+	Absent         int       `gorm:"not null" json:"absent"`
+	AveragePercent float64   `gorm:"not null" json:"average_percent"` // Mean weighted percent of the enrollments with counted meetings
+	BelowMinimum   int       `gorm:"not null" json:"below_minimum"`   // Enrollments below the exam attendance minimum of their course
+	RefreshedAt    time.Time `gorm:"not null" json:"refreshed_at"`
+}
+
+// TableName sets the table name for the AttendanceProdiStat model
+func (AttendanceProdiStat) TableName() string {
+	return "attendance_prodi_stats"
+}
+
+// AttendanceStatsFilter selects precomputed student attendance
+type AttendanceStatsFilter struct {
+	Semester   string
+	CourseCode string // Optional
+	ClassName  string // Optional
+	Nim        string // Optional
+	ProdiID    *int   // Optional
+}
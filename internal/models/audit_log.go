@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLog records a sensitive action performed by a user
+type AuditLog struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ActorUserID uint      `gorm:"index" json:"actor_user_id"`
+	ActorType   string    `gorm:"size:20" json:"actor_type"`
+	Action      string    `gorm:"size:100;not null;index" json:"action"` // e.g. supervision.confirm
+	EntityType  string    `gorm:"size:50;index:idx_audit_entity" json:"entity_type"`
+	EntityID    string    `gorm:"size:50;index:idx_audit_entity" json:"entity_id"`
+	Details     string    `gorm:"type:text" json:"details"` // JSON encoded details
+	IPAddress   string    `gorm:"size:45" json:"ip_address"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName sets the table name for the AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
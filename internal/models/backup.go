@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// BackupStatus represents the state of a logical backup
+type BackupStatus string
+
+const (
+	// BackupRunning means the dump is still being written
+	BackupRunning BackupStatus = "running"
+	// BackupCompleted means the dump finished and its checksum was recorded
+	BackupCompleted BackupStatus = "completed"
+	// BackupFailed means the dump could not be created
+	BackupFailed BackupStatus = "failed"
+)
+
+// Backup represents a logical database backup (pg_dump)
+type Backup struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	FileName    string       `gorm:"size:255;not null" json:"file_name"`
+	SizeBytes   int64        `json:"size_bytes"`
+	Checksum    string       `gorm:"size:64" json:"checksum"` // SHA-256 of the dump file
+	Status      BackupStatus `gorm:"type:VARCHAR(20);not null;index" json:"status"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	TriggeredBy uint         `json:"triggered_by"` // Admin user ID
+	StartedAt   time.Time    `json:"started_at"`
+	CompletedAt *time.Time   `json:"completed_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TableName sets the table name for the Backup model
+func (Backup) TableName() string {
+	return "backups"
+}
+
+// RestoreDrillOutcome represents the result of a restore drill
+type RestoreDrillOutcome string
+
+const (
+	// RestoreDrillPassed means the backup restored and the data checked out
+	RestoreDrillPassed RestoreDrillOutcome = "passed"
+	// RestoreDrillFailed means the backup could not be restored or the data was wrong
+	RestoreDrillFailed RestoreDrillOutcome = "failed"
+)
+
+// RestoreDrill records an exercise of restoring a backup into another environment
+type RestoreDrill struct {
+	ID              uint                `gorm:"primaryKey" json:"id"`
+	BackupID        uint                `gorm:"not null;index" json:"backup_id"`
+	Backup          *Backup             `gorm:"foreignKey:BackupID" json:"backup,omitempty"`
+	Environment     string              `gorm:"size:100" json:"environment"` // Where the backup was restored, e.g. "staging"
+	Outcome         RestoreDrillOutcome `gorm:"type:VARCHAR(20);not null" json:"outcome"`
+	DurationSeconds int                 `json:"duration_seconds"`
+	Notes           string              `gorm:"type:text" json:"notes"`
+	PerformedBy     uint                `json:"performed_by"` // Admin user ID
+	PerformedAt     time.Time           `json:"performed_at"`
+	CreatedAt       time.Time           `json:"created_at"`
+}
+
+// TableName sets the table name for the RestoreDrill model
+func (RestoreDrill) TableName() string {
+	return "restore_drills"
+}
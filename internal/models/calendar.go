@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// CalendarEventKind tells why classes do not meet during a calendar event
+type CalendarEventKind string
+
+const (
+	// HolidayEvent is a public or campus holiday
+	HolidayEvent CalendarEventKind = "holiday"
+	// ExamWeekEvent is a midterm or final exam week
+	ExamWeekEvent CalendarEventKind = "exam_week"
+)
+
+// IsValid checks whether the kind is one of the known kinds
+func (k CalendarEventKind) IsValid() bool {
+	return k == HolidayEvent || k == ExamWeekEvent
+}
+
+// CalendarEvent is a period of the academic calendar without regular class meetings
+type CalendarEvent struct {
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	Kind      CalendarEventKind `gorm:"type:VARCHAR(20);not null;index" json:"kind"`
+	Name      string            `gorm:"size:150;not null" json:"name"`
+	StartDate time.Time         `gorm:"type:date;not null;index" json:"start_date"`
+	EndDate   time.Time         `gorm:"type:date;not null;index" json:"end_date"` // Inclusive
+	CreatedBy uint              `json:"created_by"`                               // Admin user ID
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// TableName sets the table name for the CalendarEvent model
+func (CalendarEvent) TableName() string {
+	return "calendar_events"
+}
+
+// Covers checks whether a date falls within the event
+func (e *CalendarEvent) Covers(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return day >= e.StartDate.Format("2006-01-02") && day <= e.EndDate.Format("2006-01-02")
+}
+
+// ExpectedSession is a date on which a schedule is expected to meet
+type ExpectedSession struct {
+	Date      string `json:"date"` // YYYY-MM-DD
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// SkippedSession is a schedule date dropped because of a calendar event
+type SkippedSession struct {
+	Date   string            `json:"date"` // YYYY-MM-DD
+	Reason CalendarEventKind `json:"reason"`
+	Name   string            `json:"name"`
+}
+
+// ExpectedSessions lists the meetings of a schedule within a date range
+type ExpectedSessions struct {
+	ScheduleID uint              `json:"schedule_id"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Sessions   []ExpectedSession `json:"sessions"`
+	Skipped    []SkippedSession  `json:"skipped"`
+}
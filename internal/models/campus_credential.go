@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// CampusCredential keeps the CIS tokens of a campus user on the server. Clients only ever
+// receive tokens signed by DelPresence that reference the campus identity, so DelPresence
+// decides how long a session lasts and can end it regardless of the CIS token policy.
+type CampusCredential struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	CampusUserID int    `gorm:"uniqueIndex;not null" json:"campus_user_id"`
+	Username     string `gorm:"size:100" json:"username"`
+	Token        string `gorm:"type:text;not null;default:''" json:"-"`
+	RefreshToken string `gorm:"type:text;not null;default:''" json:"-"`
+	// TokensValidAfter ends every DelPresence token issued for the user before it; zero until
+	// the user's sessions are revoked for the first time
+	TokensValidAfter time.Time `json:"tokens_valid_after"`
+	LastLoginAt      time.Time `json:"last_login_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the CampusCredential model
+func (CampusCredential) TableName() string {
+	return "campus_credentials"
+}
+
+// RevokesTokenIssuedAt reports whether a DelPresence token issued at issuedAt was revoked
+func (c *CampusCredential) RevokesTokenIssuedAt(issuedAt time.Time) bool {
+	return !c.TokensValidAfter.IsZero() && issuedAt.Before(c.TokensValidAfter)
+}
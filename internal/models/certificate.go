@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// CertificateTemplate is the wording of the participation certificates of a guest event.
+// Title and Body may use the placeholders {name}, {event}, {date} and {location}.
+type CertificateTemplate struct {
+	EventID     uint      `gorm:"primaryKey" json:"event_id"`
+	Title       string    `gorm:"size:100;not null" json:"title"`
+	Body        string    `gorm:"type:text;not null" json:"body"`
+	SignerName  string    `gorm:"size:150" json:"signer_name"`
+	SignerTitle string    `gorm:"size:150" json:"signer_title"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the CertificateTemplate model
+func (CertificateTemplate) TableName() string {
+	return "certificate_templates"
+}
+
+// Certificate is a participation certificate issued to a guest who attended an event. The
+// recipient and event details are copied at issue time so the certificate verifies the same
+// way even if the event is edited later.
+type Certificate struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Code           string     `gorm:"size:32;not null;uniqueIndex" json:"code"` // Printed and encoded in the certificate's QR code
+	EventID        uint       `gorm:"not null;index" json:"event_id"`
+	RegistrationID uint       `gorm:"not null;uniqueIndex" json:"registration_id"`
+	RecipientName  string     `gorm:"size:150;not null" json:"recipient_name"`
+	EventName      string     `gorm:"size:150;not null" json:"event_name"`
+	EventDate      time.Time  `gorm:"type:date;not null" json:"event_date"`
+	Signature      string     `gorm:"size:64;not null" json:"-"` // HMAC of the details above
+	IssuedAt       time.Time  `gorm:"not null" json:"issued_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+}
+
+// TableName sets the table name for the Certificate model
+func (Certificate) TableName() string {
+	return "certificates"
+}
+
+// CertificateVerification is what the public verification endpoint tells about a certificate
+type CertificateVerification struct {
+	Valid         bool       `json:"valid"`
+	Code          string     `json:"code"`
+	RecipientName string     `json:"recipient_name"`
+	EventName     string     `json:"event_name"`
+	EventDate     string     `json:"event_date"`
+	Issuer        string     `json:"issuer"`
+	IssuedAt      time.Time  `json:"issued_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CheckInEventType names the kind of event a check-in is recorded for, so blackout windows can
+// differ per kind. Non-academic activities use their ActivityCategory.
+type CheckInEventType string
+
+const (
+	// ClassCheckInEvent is a student checking in to a class session
+	ClassCheckInEvent CheckInEventType = "class"
+	// InternshipCheckInEvent is a student checking in at their internship
+	InternshipCheckInEvent CheckInEventType = "internship"
+	// ExamCheckInEvent is exam attendance reported by the proctoring system
+	ExamCheckInEvent CheckInEventType = "exam"
+	// GuestCheckInEvent is an organizer checking in a guest of a public event
+	GuestCheckInEvent CheckInEventType = "guest_event"
+)
+
+// IsValid checks whether the event type is one of the known types
+func (t CheckInEventType) IsValid() bool {
+	switch t {
+	case ClassCheckInEvent, InternshipCheckInEvent, ExamCheckInEvent, GuestCheckInEvent:
+		return true
+	}
+	return ActivityCategory(t).IsValid()
+}
+
+// BlackoutWindow is a daily period in campus local time, e.g. 00:00 to 05:00. A window whose
+// end is not after its start runs past midnight.
+type BlackoutWindow struct {
+	Start string `json:"start"` // HH:MM, inclusive
+	End   string `json:"end"`   // HH:MM, exclusive
+}
+
+// minutes parses an HH:MM time of day into minutes after midnight
+func minutes(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day in HH:MM format", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// Contains checks whether t falls in the window, in t's location
+func (w BlackoutWindow) Contains(t time.Time) bool {
+	start, errStart := minutes(w.Start)
+	end, errEnd := minutes(w.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// CheckInBlackoutPolicy holds the daily windows in which check-ins of an event type are
+// rejected. The global policy applies to every event type without a policy of its own; an
+// override without windows exempts its event type, e.g. dormitory roll call at night.
+type CheckInBlackoutPolicy struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	EventType CheckInEventType `gorm:"type:VARCHAR(30);uniqueIndex" json:"event_type"` // Empty for the global policy
+	Windows   []BlackoutWindow `gorm:"serializer:json;type:text;not null" json:"windows"`
+	UpdatedBy uint             `json:"updated_by"` // Admin user ID
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// TableName sets the table name for the CheckInBlackoutPolicy model
+func (CheckInBlackoutPolicy) TableName() string {
+	return "check_in_blackout_policies"
+}
+
+// Validate checks that every window has valid times and is not empty
+func (p *CheckInBlackoutPolicy) Validate() error {
+	if p.EventType != "" && !p.EventType.IsValid() {
+		return fmt.Errorf("unknown event type %q", p.EventType)
+	}
+	if p.EventType == "" && len(p.Windows) == 0 {
+		return errors.New("the global policy needs at least one window; delete it to allow check-ins at any time")
+	}
+	for _, window := range p.Windows {
+		start, err := minutes(window.Start)
+		if err != nil {
+			return err
+		}
+		end, err := minutes(window.End)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return errors.New("a window must not start and end at the same time")
+		}
+	}
+	return nil
+}
+
+// ActiveWindow returns the window t falls in, or nil when check-ins are allowed at t
+func (p *CheckInBlackoutPolicy) ActiveWindow(t time.Time) *BlackoutWindow {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Windows {
+		if p.Windows[i].Contains(t) {
+			return &p.Windows[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// CheckInStep is a step of a check-in that can turn the attempt away
+type CheckInStep string
+
+const (
+	// StepSession checks that the session is open and how it must be checked in to
+	StepSession CheckInStep = "session"
+	// StepAttestation verifies the device attestation
+	StepAttestation CheckInStep = "attestation"
+	// StepGeofence verifies the student's location
+	StepGeofence CheckInStep = "geofence"
+	// StepFace verifies the student's face
+	StepFace CheckInStep = "face"
+	// StepWifi verifies that the attempt comes from the campus Wi-Fi
+	StepWifi CheckInStep = "wifi"
+	// StepEnrollment resolves the student's NIM and checks their enrollment
+	StepEnrollment CheckInStep = "enrollment"
+	// StepRecord grades and stores the attendance record
+	StepRecord CheckInStep = "record"
+)
+
+// CheckInTelemetry is the raw context of a check-in attempt, accepted or not. It is kept in
+// its own narrow table apart from the attendance record and purged after a shorter
+// retention, so suspected fraud can be investigated without keeping it forever.
+type CheckInTelemetry struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	SessionID     uint          `gorm:"not null;index" json:"session_id"`
+	StudentUserID uint          `gorm:"not null;index" json:"student_user_id"`
+	RecordID      *uint         `json:"record_id"`                   // Set when the check-in was accepted
+	HTTPStatus    int           `gorm:"not null" json:"http_status"` // Response the attempt got
+	FailedStep    CheckInStep   `gorm:"size:20" json:"failed_step"`  // Step that turned the attempt away
+	LatencyMs     int64         `json:"latency_ms"`                  // Time the API took to answer
+	Method        CheckInMethod `gorm:"type:VARCHAR(20);not null" json:"method"`
+	Factors       string        `gorm:"size:50" json:"factors"` // Comma-separated verification factors sent: qr, location, face
+	Latitude      *float64      `json:"latitude"`
+	Longitude     *float64      `json:"longitude"`
+	Accuracy      *float64      `json:"accuracy"` // Meters, as reported by the device
+	Distance      *float64      `json:"distance"` // Meters from the session's location when geofenced
+	FaceScore     *float64      `json:"face_score"`
+	DeviceID      string        `gorm:"size:100;index" json:"device_id"`
+	DeviceModel   string        `gorm:"size:100" json:"device_model"`
+	AppVersion    string        `gorm:"size:30" json:"app_version"`
+	UserAgent     string        `gorm:"size:255" json:"user_agent"`
+	IPAddress     string        `gorm:"size:45;index" json:"ip_address"`
+	CreatedAt     time.Time     `gorm:"not null;index" json:"created_at"`
+}
+
+// TableName sets the table name for the CheckInTelemetry model
+func (CheckInTelemetry) TableName() string {
+	return "check_in_telemetry"
+}
+
+// Accepted checks whether the attempt resulted in an attendance record
+func (t *CheckInTelemetry) Accepted() bool {
+	return t.RecordID != nil
+}
+
+// IsSystemFailure checks whether the attempt failed because of the service or one of its
+// dependencies rather than the student
+func (t *CheckInTelemetry) IsSystemFailure() bool {
+	return t.HTTPStatus >= 500
+}
+
+// CheckInHealth summarizes the check-in attempts of a session over a period
+type CheckInHealth struct {
+	Attempts       int64 `json:"attempts"`
+	Accepted       int64 `json:"accepted"`
+	StudentErrors  int64 `json:"student_errors"`  // Turned away because of the student, e.g. too far or not enrolled
+	SystemFailures int64 `json:"system_failures"` // Turned away because of the service or a dependency
+	// SuccessRate is the share of attempts that were accepted
+	SuccessRate float64 `json:"success_rate"`
+	// SystemFailureRate is the share of attempts the service failed
+	SystemFailureRate float64    `json:"system_failure_rate"`
+	MedianLatencyMs   float64    `json:"median_latency_ms"`
+	P95LatencyMs      float64    `json:"p95_latency_ms"`
+	LastAttemptAt     *time.Time `json:"last_attempt_at"`
+}
+
+// ComputeRates fills the success and system failure rates from the counts
+func (h *CheckInHealth) ComputeRates() {
+	if h.Attempts == 0 {
+		return
+	}
+	h.SuccessRate = float64(h.Accepted) / float64(h.Attempts)
+	h.SystemFailureRate = float64(h.SystemFailures) / float64(h.Attempts)
+}
+
+// CheckInFailureReason counts the failed check-in attempts of a session by step and response
+type CheckInFailureReason struct {
+	FailedStep  CheckInStep `json:"failed_step"`
+	HTTPStatus  int         `json:"http_status"`
+	Count       int64       `json:"count"`
+	SystemFault bool        `json:"system_fault"`
+}
+
+// SessionCheckInSLA is the check-in service level of a session over a period, with how many
+// students were kept from checking in by system failures
+type SessionCheckInSLA struct {
+	SessionID  uint      `json:"session_id"`
+	CourseCode string    `json:"course_code"`
+	ClassName  string    `json:"class_name"`
+	OpenedAt   time.Time `json:"opened_at"`
+	CheckInHealth
+	// AffectedStudents counts students who hit a system failure and never got checked in
+	AffectedStudents int64 `json:"affected_students"`
+}
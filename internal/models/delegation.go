@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// ApprovalScope identifies which approval duties a delegation covers
+type ApprovalScope string
+
+const (
+	// AllApprovals covers every approval duty
+	AllApprovals ApprovalScope = "all"
+	// SupervisionApprovals covers confirming supervision meetings
+	SupervisionApprovals ApprovalScope = "supervision"
+	// LeaveApprovals covers deciding leave requests
+	LeaveApprovals ApprovalScope = "leave"
+	// DisputeApprovals covers resolving attendance disputes
+	DisputeApprovals ApprovalScope = "dispute"
+)
+
+// IsValid checks whether the scope is one of the known scopes
+func (s ApprovalScope) IsValid() bool {
+	switch s {
+	case AllApprovals, SupervisionApprovals, LeaveApprovals, DisputeApprovals:
+		return true
+	}
+	return false
+}
+
+// ApprovalDelegation hands a lecturer's approval duties to another lecturer for a date
+// range, e.g. while out of office. Routing falls back to the delegator once it ends.
+type ApprovalDelegation struct {
+	ID              uint          `gorm:"primaryKey" json:"id"`
+	DelegatorUserID uint          `gorm:"not null;index" json:"delegator_user_id"` // Lecturer handing over the duties
+	DelegateUserID  uint          `gorm:"not null;index" json:"delegate_user_id"`  // Lecturer taking them over
+	Scope           ApprovalScope `gorm:"type:VARCHAR(20);not null;default:'all'" json:"scope"`
+	StartDate       time.Time     `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time     `gorm:"type:date;not null" json:"end_date"`
+	Reason          string        `gorm:"size:200" json:"reason"`
+	RevokedAt       *time.Time    `json:"revoked_at"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// TableName sets the table name for the ApprovalDelegation model
+func (ApprovalDelegation) TableName() string {
+	return "approval_delegations"
+}
+
+// Covers checks whether the delegation routes approvals of scope on the given day
+func (d *ApprovalDelegation) Covers(scope ApprovalScope, day time.Time) bool {
+	if d.RevokedAt != nil || (d.Scope != AllApprovals && d.Scope != scope) {
+		return false
+	}
+	date := day.Format("2006-01-02")
+	return date >= d.StartDate.Format("2006-01-02") && date <= d.EndDate.Format("2006-01-02")
+}
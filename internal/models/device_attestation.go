@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AppAttestKey is an App Attest key a student's iPhone registered for signing check-ins
+type AppAttestKey struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	StudentUserID uint       `gorm:"not null;index" json:"student_user_id"` // Campus user ID of the student
+	KeyID         string     `gorm:"size:100;not null;uniqueIndex" json:"key_id"`
+	PublicKey     []byte     `gorm:"not null" json:"-"`           // PKIX encoded
+	Counter       uint32     `gorm:"not null;default:0" json:"-"` // Counter of the last accepted assertion
+	CreatedAt     time.Time  `json:"created_at"`
+	LastUsedAt    *time.Time `json:"last_used_at"`
+}
+
+// TableName sets the table name for the AppAttestKey model
+func (AppAttestKey) TableName() string {
+	return "app_attest_keys"
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// accentColorPattern matches the #rgb and #rrggbb colors accepted as accent color
+var accentColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// EmailBranding is the logo, color, footer and support contact used in outgoing emails
+type EmailBranding struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Faculty        string    `gorm:"size:150;uniqueIndex" json:"faculty"` // Empty for every faculty without its own branding
+	LogoURL        string    `gorm:"size:500" json:"logo_url"`
+	AccentColor    string    `gorm:"size:7" json:"accent_color"`
+	FooterText     string    `gorm:"type:text" json:"footer_text"`
+	SupportContact string    `gorm:"size:200" json:"support_contact"` // Email address or phone number shown in the footer
+	UpdatedBy      uint      `json:"updated_by"`                      // Admin user ID
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the EmailBranding model
+func (EmailBranding) TableName() string {
+	return "email_brandings"
+}
+
+// DefaultEmailBranding is used when no branding is configured
+var DefaultEmailBranding = EmailBranding{
+	AccentColor: "#333333",
+	FooterText:  "Institut Teknologi Del",
+}
+
+// IsValidAccentColor checks whether color is a #rgb or #rrggbb hex color
+func IsValidAccentColor(color string) bool {
+	return accentColorPattern.MatchString(color)
+}
+
+// WithDefaults fills the unset values from DefaultEmailBranding
+func (b EmailBranding) WithDefaults() EmailBranding {
+	if b.AccentColor == "" {
+		b.AccentColor = DefaultEmailBranding.AccentColor
+	}
+	if b.FooterText == "" {
+		b.FooterText = DefaultEmailBranding.FooterText
+	}
+	return b
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// EmailTracking is the anonymous tracking token of one tracked email. It records whether the
+// email was opened or a link in it clicked, but not who it was sent to, so only aggregate
+// numbers can be reported.
+type EmailTracking struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Token     string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Template  string     `gorm:"size:100;not null;index" json:"template"`
+	SentAt    time.Time  `gorm:"not null;index" json:"sent_at"`
+	OpenedAt  *time.Time `json:"opened_at"`
+	ClickedAt *time.Time `json:"clicked_at"`
+}
+
+// TableName sets the table name for the EmailTracking model
+func (EmailTracking) TableName() string {
+	return "email_trackings"
+}
+
+// EmailTrackingOptOut marks a user whose emails are never tracked
+type EmailTrackingOptOut struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName sets the table name for the EmailTrackingOptOut model
+func (EmailTrackingOptOut) TableName() string {
+	return "email_tracking_opt_outs"
+}
+
+// EmailEngagement is the number of tracked emails of a template sent on a day and how many
+// of them were opened or clicked
+type EmailEngagement struct {
+	Template string `json:"template"`
+	Day      string `json:"day"`
+	Sent     int64  `json:"sent"`
+	Opened   int64  `json:"opened"`
+	Clicked  int64  `json:"clicked"`
+}
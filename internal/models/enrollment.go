@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Enrollment registers a student in a course offering (course, class and semester)
+type Enrollment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Nim        string    `gorm:"size:20;not null;uniqueIndex:idx_enrollment_student" json:"nim"`
+	CourseCode string    `gorm:"size:20;not null;uniqueIndex:idx_enrollment_student;index:idx_enrollment_offering" json:"course_code"`
+	CourseName string    `gorm:"size:150" json:"course_name"`
+	ClassName  string    `gorm:"size:50;index:idx_enrollment_offering" json:"class_name"` // Empty when the course has a single class
+	Semester   string    `gorm:"size:30;not null;uniqueIndex:idx_enrollment_student;index:idx_enrollment_offering" json:"semester"`
+	EnrolledBy uint      `json:"enrolled_by"` // Admin user ID
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the Enrollment model
+func (Enrollment) TableName() string {
+	return "enrollments"
+}
+
+// CourseOffering identifies a course taught in a semester, optionally to one class
+type CourseOffering struct {
+	CourseCode string `json:"course_code"`
+	CourseName string `json:"course_name"`
+	ClassName  string `json:"class_name"`
+	Semester   string `json:"semester"`
+}
+
+// EnrolledCourse is a course a student is enrolled in together with its weekly schedule
+type EnrolledCourse struct {
+	Enrollment
+	Schedules []Schedule `json:"schedules"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// ExamAttendanceStatus represents the attendance status of a student in an exam
+type ExamAttendanceStatus string
+
+const (
+	// ExamPresent means the student took the exam
+	ExamPresent ExamAttendanceStatus = "present"
+	// ExamAbsent means the student did not show up
+	ExamAbsent ExamAttendanceStatus = "absent"
+	// ExamDisqualified means the proctor terminated the exam session
+	ExamDisqualified ExamAttendanceStatus = "disqualified"
+)
+
+// IsValid checks whether the status is one of the known statuses
+func (s ExamAttendanceStatus) IsValid() bool {
+	switch s {
+	case ExamPresent, ExamAbsent, ExamDisqualified:
+		return true
+	}
+	return false
+}
+
+// ExamAttendance represents a student's attendance in an exam as reported by the proctoring system
+type ExamAttendance struct {
+	ID          uint                 `gorm:"primaryKey" json:"id"`
+	CourseCode  string               `gorm:"size:20;not null;uniqueIndex:idx_exam_attendance_student" json:"course_code"`
+	ExamType    string               `gorm:"size:20;not null;uniqueIndex:idx_exam_attendance_student" json:"exam_type"` // e.g. UTS, UAS, quiz
+	Nim         string               `gorm:"size:20;not null;uniqueIndex:idx_exam_attendance_student" json:"nim"`
+	Status      ExamAttendanceStatus `gorm:"type:VARCHAR(20);not null" json:"status"`
+	ExternalRef string               `gorm:"size:100" json:"external_ref"` // Session ID in the proctoring system
+	APIKeyID    uint                 `gorm:"not null" json:"api_key_id"`   // Key that reported the attendance
+	MarkedAt    time.Time            `gorm:"not null" json:"marked_at"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// TableName sets the table name for the ExamAttendance model
+func (ExamAttendance) TableName() string {
+	return "exam_attendances"
+}
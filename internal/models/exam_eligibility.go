@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// DefaultExamMinPercent is the minimum attendance for exams when no policy is set
+const DefaultExamMinPercent = 75.0
+
+// ExamEligibilityPolicy is the minimum attendance percentage students need to sit the
+// exams of a course
+type ExamEligibilityPolicy struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CourseCode string    `gorm:"size:20;uniqueIndex" json:"course_code"` // Empty for the default policy
+	MinPercent float64   `gorm:"not null" json:"min_percent"`
+	UpdatedBy  uint      `json:"updated_by"` // Admin user ID
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the ExamEligibilityPolicy model
+func (ExamEligibilityPolicy) TableName() string {
+	return "exam_eligibility_policies"
+}
+
+// MinPercentOrDefault returns the minimum attendance of the policy, or the default without one
+func (p *ExamEligibilityPolicy) MinPercentOrDefault() float64 {
+	if p == nil {
+		return DefaultExamMinPercent
+	}
+	return p.MinPercent
+}
+
+// ExamEligibility is whether a student may sit the exams of one of their courses
+type ExamEligibility struct {
+	CourseCode      string  `json:"course_code"`
+	CourseName      string  `json:"course_name"`
+	ClassName       string  `json:"class_name"`
+	Semester        string  `json:"semester"`
+	Meetings        int     `json:"meetings"`         // Meetings counted so far, leaving out optional ones
+	WeightedPercent float64 `json:"weighted_percent"` // Attendance credit as a percentage of the counted meetings
+	MinPercent      float64 `json:"min_percent"`
+	Eligible        bool    `json:"eligible"` // Always true before any meeting is counted
+}
+
+// ExamEligibilityStudent is one student of a course eligibility list
+type ExamEligibilityStudent struct {
+	Nim             string  `json:"nim"`
+	WeightedPercent float64 `json:"weighted_percent"`
+	Eligible        bool    `json:"eligible"`
+}
+
+// ExamEligibilityList is the exam eligibility of every student of a course, used to print
+// exam cards
+type ExamEligibilityList struct {
+	CourseCode string                   `json:"course_code"`
+	Semester   string                   `json:"semester"`
+	ClassName  string                   `json:"class_name"`
+	Meetings   int                      `json:"meetings"`
+	MinPercent float64                  `json:"min_percent"`
+	Eligible   int                      `json:"eligible"` // Number of eligible students
+	Students   []ExamEligibilityStudent `json:"students"`
+}
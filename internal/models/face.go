@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// FaceData is the face embedding a student registered for verifying check-ins.
+// Embeddings are biometric data, so they are never returned by the API and are
+// deleted for good rather than soft deleted.
+type FaceData struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	StudentUserID uint      `gorm:"not null;uniqueIndex" json:"student_user_id"` // Campus user ID of the student
+	Nim           string    `gorm:"size:20;not null;index" json:"nim"`
+	Embedding     []float64 `gorm:"serializer:json;type:text;not null" json:"-"`
+	Dimension     int       `gorm:"not null" json:"dimension"`
+	Model         string    `gorm:"size:50" json:"model"` // Embedding model used by the app, e.g. mobilefacenet-v2
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the FaceData model
+func (FaceData) TableName() string {
+	return "face_data"
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+)
+
+// CheckInFactor identifies a verification factor of check-ins that can be rolled out per course
+type CheckInFactor string
+
+const (
+	// FaceFactor verifies the student's face against their registered face
+	FaceFactor CheckInFactor = "face"
+	// WifiFactor verifies that the check-in comes from the campus Wi-Fi
+	WifiFactor CheckInFactor = "wifi"
+)
+
+// IsValidCheckInFactor checks whether factor can be rolled out per course
+func IsValidCheckInFactor(factor CheckInFactor) bool {
+	return factor == FaceFactor || factor == WifiFactor
+}
+
+// FactorMode is how a check-in factor applies to the check-ins of a course
+type FactorMode string
+
+const (
+	// FactorModeOff does not evaluate the factor
+	FactorModeOff FactorMode = "off"
+	// FactorModeShadow evaluates and records the factor without turning anyone away
+	FactorModeShadow FactorMode = "shadow"
+	// FactorModeEnforce requires the factor to pass
+	FactorModeEnforce FactorMode = "enforce"
+)
+
+// IsValidFactorMode checks whether mode is a known factor mode
+func IsValidFactorMode(mode FactorMode) bool {
+	switch mode {
+	case FactorModeOff, FactorModeShadow, FactorModeEnforce:
+		return true
+	}
+	return false
+}
+
+// FactorRollout sets how a factor applies to one course. Courses without a rollout follow the
+// factor's feature flag.
+type FactorRollout struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	CourseCode string        `gorm:"size:20;not null;uniqueIndex:idx_factor_rollout" json:"course_code"`
+	Factor     CheckInFactor `gorm:"type:VARCHAR(20);not null;uniqueIndex:idx_factor_rollout" json:"factor"`
+	Mode       FactorMode    `gorm:"type:VARCHAR(20);not null" json:"mode"`
+	UpdatedBy  uint          `json:"updated_by"` // Admin user ID
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// TableName sets the table name for the FactorRollout model
+func (FactorRollout) TableName() string {
+	return "factor_rollouts"
+}
+
+// FactorOutcome is the result a factor had, or would have had, on a check-in attempt
+type FactorOutcome string
+
+const (
+	// FactorPassed means the factor was verified
+	FactorPassed FactorOutcome = "pass"
+	// FactorFailed means the factor was checked and did not match
+	FactorFailed FactorOutcome = "fail"
+	// FactorMissing means the attempt did not carry what the factor needs
+	FactorMissing FactorOutcome = "missing"
+	// FactorUnavailable means the factor could not be evaluated, e.g. no face registered yet
+	FactorUnavailable FactorOutcome = "unavailable"
+)
+
+// ShadowFactorResult is the outcome a factor in shadow mode would have had on a check-in
+// attempt. Together they show how many check-ins enforcing the factor would turn away.
+type ShadowFactorResult struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	SessionID     uint          `gorm:"not null;index" json:"session_id"`
+	CourseCode    string        `gorm:"size:20;not null;index" json:"course_code"`
+	StudentUserID uint          `gorm:"not null" json:"student_user_id"`
+	Factor        CheckInFactor `gorm:"type:VARCHAR(20);not null" json:"factor"`
+	Outcome       FactorOutcome `gorm:"type:VARCHAR(20);not null" json:"outcome"`
+	Score         *float64      `json:"score"`                  // Face similarity, when evaluated
+	Detail        string        `gorm:"size:255" json:"detail"` // Why the factor did not pass
+	Accepted      bool          `json:"accepted"`               // Whether the check-in was recorded
+	CreatedAt     time.Time     `gorm:"not null;index" json:"created_at"`
+}
+
+// TableName sets the table name for the ShadowFactorResult model
+func (ShadowFactorResult) TableName() string {
+	return "shadow_factor_results"
+}
+
+// WouldFail reports whether enforcing the factor would have turned the attempt away
+func (r *ShadowFactorResult) WouldFail() bool {
+	return r.Outcome != FactorPassed
+}
+
+// ShadowFactorReport compares how check-ins of a course fared against a factor in shadow mode
+type ShadowFactorReport struct {
+	CourseCode string        `json:"course_code"`
+	Factor     CheckInFactor `json:"factor"`
+	Attempts   int64         `json:"attempts"`
+	Passed     int64         `json:"passed"`
+	Failed     int64         `json:"failed"`
+	Missing    int64         `json:"missing"`
+	// Unavailable counts attempts the factor could not be evaluated for
+	Unavailable int64 `json:"unavailable"`
+	// WouldFailRate is the share of attempts enforcing the factor would have turned away
+	WouldFailRate float64 `json:"would_fail_rate"`
+	// AcceptedWouldFail counts recorded check-ins that enforcing the factor would have rejected
+	AcceptedWouldFail int64 `json:"accepted_would_fail"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GuestEvent is a public campus event that guests without a campus account can attend.
+// Guest data is kept in its own tables and never joined with student data.
+type GuestEvent struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	Name             string         `gorm:"size:150;not null" json:"name"`
+	Description      string         `gorm:"type:text" json:"description"`
+	Location         string         `gorm:"size:150" json:"location"`
+	StartsAt         time.Time      `gorm:"not null" json:"starts_at"`
+	EndsAt           time.Time      `gorm:"not null" json:"ends_at"`
+	Capacity         int            `gorm:"default:0" json:"capacity"` // 0 means unlimited
+	OrganizerUserID  uint           `gorm:"not null;index" json:"organizer_user_id"`
+	RegistrationOpen bool           `gorm:"default:true" json:"registration_open"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the GuestEvent model
+func (GuestEvent) TableName() string {
+	return "guest_events"
+}
+
+// GuestRegistration is a guest's registration for an event
+type GuestRegistration struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	EventID          uint       `gorm:"not null;index;uniqueIndex:idx_guest_event_email" json:"event_id"`
+	Event            GuestEvent `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"-"`
+	Name             string     `gorm:"size:150;not null" json:"name"`
+	Email            string     `gorm:"size:150;not null;uniqueIndex:idx_guest_event_email" json:"email"`
+	Phone            string     `gorm:"size:30" json:"phone"`
+	Institution      string     `gorm:"size:150" json:"institution"`
+	RegistrationCode string     `gorm:"size:32;not null;uniqueIndex" json:"registration_code"` // Encoded in the guest's QR code
+	CheckedInAt      *time.Time `json:"checked_in_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name for the GuestRegistration model
+func (GuestRegistration) TableName() string {
+	return "guest_registrations"
+}
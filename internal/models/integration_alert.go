@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// IntegrationAlertCondition identifies what made an integration unhealthy
+type IntegrationAlertCondition string
+
+const (
+	// AlertCircuitOpen means the circuit breaker stopped calling the integration
+	AlertCircuitOpen IntegrationAlertCondition = "circuit_open"
+	// AlertErrorRate means too many calls to the integration failed recently
+	AlertErrorRate IntegrationAlertCondition = "error_rate"
+)
+
+// CampusIntegration names the campus API in integration alerts
+const CampusIntegration = "campus_api"
+
+// IntegrationAlert is one period an integration was unhealthy for one condition, from the
+// first time it was seen until it was reported resolved. Only one alert per integration and
+// condition is open at a time, which keeps instances from notifying it twice.
+type IntegrationAlert struct {
+	ID            uint                      `gorm:"primaryKey" json:"id"`
+	Integration   string                    `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_open_integration_alert,where:resolved_at IS NULL" json:"integration"`
+	Condition     IntegrationAlertCondition `gorm:"type:VARCHAR(30);not null;uniqueIndex:idx_open_integration_alert,where:resolved_at IS NULL" json:"condition"`
+	Details       string                    `gorm:"type:text" json:"details"` // JSON measurements at the start of the alert
+	StartedAt     time.Time                 `gorm:"not null;index" json:"started_at"`
+	NotifiedAt    time.Time                 `json:"notified_at"`                             // Last notification, the first or a reminder
+	Notifications int                       `gorm:"not null;default:0" json:"notifications"` // Notifications sent while firing
+	ResolvedAt    *time.Time                `json:"resolved_at"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
+
+// TableName sets the table name for the IntegrationAlert model
+func (IntegrationAlert) TableName() string {
+	return "integration_alerts"
+}
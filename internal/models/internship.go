@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Internship represents a student's internship (kerja praktek) placement
+type Internship struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	StudentUserID   uint           `gorm:"not null;index" json:"student_user_id"` // Campus user ID of the student
+	Nim             string         `gorm:"size:20;not null;index" json:"nim"`
+	CompanyName     string         `gorm:"size:150;not null" json:"company_name"`
+	CompanyAddress  string         `gorm:"type:text" json:"company_address"`
+	MentorName      string         `gorm:"size:100;not null" json:"mentor_name"`
+	MentorEmail     string         `gorm:"size:150;not null" json:"mentor_email"`
+	SupervisorName  string         `gorm:"size:100" json:"supervisor_name"`
+	SupervisorEmail string         `gorm:"size:150;not null" json:"supervisor_email"` // Academic supervisor
+	StartDate       time.Time      `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time      `gorm:"type:date;not null" json:"end_date"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the Internship model
+func (Internship) TableName() string {
+	return "internships"
+}
+
+// IsActiveOn checks whether the internship period covers the given day
+func (i *Internship) IsActiveOn(day time.Time) bool {
+	d := day.Format("2006-01-02")
+	return d >= i.StartDate.Format("2006-01-02") && d <= i.EndDate.Format("2006-01-02")
+}
+
+// InternshipCheckIn is a student's daily remote attendance during an internship
+type InternshipCheckIn struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	InternshipID      uint       `gorm:"not null;uniqueIndex:idx_internship_day" json:"internship_id"`
+	Internship        Internship `gorm:"foreignKey:InternshipID;constraint:OnDelete:CASCADE" json:"-"`
+	Date              string     `gorm:"size:10;not null;uniqueIndex:idx_internship_day" json:"date"` // YYYY-MM-DD
+	CheckInAt         time.Time  `gorm:"not null" json:"check_in_at"`
+	Latitude          float64    `json:"latitude"`
+	Longitude         float64    `json:"longitude"`
+	Accuracy          float64    `json:"accuracy"` // Reported GPS accuracy in meters
+	ActivityNotes     string     `gorm:"type:text;not null" json:"activity_notes"`
+	ConfirmationToken string     `gorm:"size:64;uniqueIndex" json:"-"`
+	TokenExpiresAt    time.Time  `json:"-"`
+	ConfirmedAt       *time.Time `json:"confirmed_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for the InternshipCheckIn model
+func (InternshipCheckIn) TableName() string {
+	return "internship_check_ins"
+}
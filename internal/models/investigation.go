@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// InvestigationEventKind identifies the source of an event on an investigation timeline
+type InvestigationEventKind string
+
+const (
+	// InvestigationCheckIn is a check-in attempt from the raw telemetry, accepted or not
+	InvestigationCheckIn InvestigationEventKind = "check_in"
+	// InvestigationAttendance is an attendance record, including manual and excused ones
+	InvestigationAttendance InvestigationEventKind = "attendance"
+	// InvestigationLogin is a successful login through the campus account
+	InvestigationLogin InvestigationEventKind = "login"
+	// InvestigationPermissionSubmitted is a permission (izin or sakit) request being submitted
+	InvestigationPermissionSubmitted InvestigationEventKind = "permission_submitted"
+	// InvestigationPermissionDecided is a permission request being approved, rejected or cancelled
+	InvestigationPermissionDecided InvestigationEventKind = "permission_decided"
+)
+
+// InvestigationEvent is one entry of the fused timeline of a student
+type InvestigationEvent struct {
+	At        time.Time              `json:"at"`
+	Kind      InvestigationEventKind `json:"kind"`
+	Summary   string                 `json:"summary"`
+	IPAddress string                 `json:"ip_address,omitempty"`
+	DeviceID  string                 `json:"device_id,omitempty"`
+	Data      interface{}            `json:"data"` // Telemetry, attendance record, audit log or permission request
+}
+
+// InvestigationDevice is a device a student checked in from
+type InvestigationDevice struct {
+	DeviceID    string    `json:"device_id"`
+	DeviceModel string    `json:"device_model"`
+	Attempts    int       `json:"attempts"`
+	Rejected    int       `json:"rejected"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	// SharedWith lists the other students who checked in from the same device in the period
+	SharedWith []uint `json:"shared_with"`
+}
+
+// InvestigationIPAddress is an address a student logged in or checked in from
+type InvestigationIPAddress struct {
+	IPAddress string    `json:"ip_address"`
+	Logins    int       `json:"logins"`
+	CheckIns  int       `json:"check_ins"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// InvestigationTimeline is everything known about a student's activity in a date range
+type InvestigationTimeline struct {
+	StudentUserID uint                     `json:"student_user_id"`
+	From          time.Time                `json:"from"`
+	To            time.Time                `json:"to"`
+	Events        []InvestigationEvent     `json:"events"` // Oldest first
+	Devices       []InvestigationDevice    `json:"devices"`
+	IPAddresses   []InvestigationIPAddress `json:"ip_addresses"`
+}
+
+// DeviceStudent pairs a device with a student who checked in from it
+type DeviceStudent struct {
+	DeviceID      string
+	StudentUserID uint
+}
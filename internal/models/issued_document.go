@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// IssuedDocumentKind tells what kind of exported document was issued
+type IssuedDocumentKind string
+
+const (
+	// AttendanceReportDocument is a printed attendance recap of a course
+	AttendanceReportDocument IssuedDocumentKind = "attendance_report"
+	// CertificateDocument is a participation certificate of a guest event; certificates are
+	// kept in their own table and only use this kind in verification results
+	CertificateDocument IssuedDocumentKind = "certificate"
+)
+
+// IssuedDocument records an exported report so the verification code printed on it can be
+// checked by third parties later
+type IssuedDocument struct {
+	ID             uint               `gorm:"primaryKey" json:"id"`
+	Code           string             `gorm:"size:32;not null;uniqueIndex" json:"code"`
+	Kind           IssuedDocumentKind `gorm:"type:VARCHAR(30);not null" json:"kind"`
+	Subject        string             `gorm:"size:255;not null" json:"subject"` // What the document is about, as printed on it
+	IssuedByUserID uint               `gorm:"not null;index" json:"issued_by_user_id"`
+	Signature      string             `gorm:"size:64;not null" json:"-"` // HMAC of the details above
+	IssuedAt       time.Time          `gorm:"not null" json:"issued_at"`
+}
+
+// TableName sets the table name for the IssuedDocument model
+func (IssuedDocument) TableName() string {
+	return "issued_documents"
+}
+
+// DocumentVerification is what the public verification endpoint tells about a certificate or
+// report: who issued it, what it is about and when, and nothing else
+type DocumentVerification struct {
+	Valid    bool               `json:"valid"`
+	Code     string             `json:"code"`
+	Kind     IssuedDocumentKind `json:"kind"`
+	Issuer   string             `json:"issuer"`
+	Subject  string             `json:"subject"`
+	IssuedAt time.Time          `json:"issued_at"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// LateTier credits check-ins made at least AfterMinutes after the session started
+type LateTier struct {
+	AfterMinutes int              `json:"after_minutes"`
+	Status       AttendanceStatus `json:"status"` // present or late
+	Credit       float64          `json:"credit"` // Share of a meeting the check-in counts for, 0 to 1
+}
+
+// LatePolicy maps lateness to attendance status and credit, e.g. up to 10 minutes is
+// present, 10 to 30 minutes is late and counts 0.5, later counts nothing
+type LatePolicy struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	CourseCode string     `gorm:"size:20;uniqueIndex" json:"course_code"` // Empty for the default policy
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Tiers      []LateTier `gorm:"serializer:json;type:text;not null" json:"tiers"`
+	// GradeOptional applies the tiers to optional sessions too; otherwise check-ins to them are
+	// present with full credit
+	GradeOptional bool      `gorm:"not null;default:false" json:"grade_optional"`
+	UpdatedBy     uint      `json:"updated_by"` // Admin user ID
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the LatePolicy model
+func (LatePolicy) TableName() string {
+	return "late_policies"
+}
+
+// Validate checks that tiers are in ascending order of lateness with valid statuses and credits
+func (p *LatePolicy) Validate() error {
+	if len(p.Tiers) == 0 {
+		return errors.New("a late policy needs at least one tier")
+	}
+	for i, tier := range p.Tiers {
+		if tier.AfterMinutes < 0 {
+			return errors.New("after_minutes must not be negative")
+		}
+		if i > 0 && tier.AfterMinutes <= p.Tiers[i-1].AfterMinutes {
+			return errors.New("tiers must be sorted by after_minutes without duplicates")
+		}
+		if tier.Status != AttendancePresent && tier.Status != AttendanceLate {
+			return errors.New("tier status must be present or late")
+		}
+		if tier.Credit < 0 || tier.Credit > 1 {
+			return errors.New("tier credit must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
+// Apply returns the status and credit of a check-in lateMinutes after the session started.
+// Check-ins before the first tier, or without a policy, are present with full credit.
+func (p *LatePolicy) Apply(lateMinutes int) (AttendanceStatus, float64) {
+	status, credit := AttendancePresent, 1.0
+	if p == nil {
+		return status, credit
+	}
+	for _, tier := range p.Tiers {
+		if lateMinutes < tier.AfterMinutes {
+			break
+		}
+		status, credit = tier.Status, tier.Credit
+	}
+	return status, credit
+}
+
+// ApplyToSession is Apply for a check-in to a session that may be optional
+func (p *LatePolicy) ApplyToSession(optional bool, lateMinutes int) (AttendanceStatus, float64) {
+	if optional && (p == nil || !p.GradeOptional) {
+		return AttendancePresent, 1
+	}
+	return p.Apply(lateMinutes)
+}
+
+// CheckInTiming is a check-in together with the start of its session, used to regrade
+// lateness when a late policy changes
+type CheckInTiming struct {
+	RecordID     uint
+	Status       AttendanceStatus
+	LateMinutes  int
+	Credit       float64
+	CheckedInAt  time.Time
+	SessionStart time.Time
+	Optional     bool // Whether the session is optional
+}
@@ -13,8 +13,8 @@ type Lecturer struct {
 	EmployeeID       uint   `json:"pegawai_id"`            // From campus API
 	LecturerID       uint   `json:"dosen_id"`              // From campus API
 	IdentityNumber   string `json:"nip"`                   // From campus API
-	FullName         string `json:"nama"`                  // From campus API
-	Email            string `json:"email"`                 // From campus API
+	FullName         string `json:"nama"`                  // From campus API, can be edited by user
+	Email            string `json:"email"`                 // From campus API, can be edited by user
 	DepartmentID     uint   `json:"prodi_id"`              // From campus API
 	Department       string `json:"prodi"`                 // From campus API
 	AcademicRank     string `json:"jabatan_akademik"`      // From campus API
@@ -114,10 +114,12 @@ func AutoMigrateLecturer(db *gorm.DB) error {
 }
 
 // IsUserEditable checks if a field can be edited by the user
-// Fields from the campus API should not be editable by the user
+// Fields from the campus API should not be editable by the user, except the synced fields
 func (l *Lecturer) IsUserEditable(fieldName string) bool {
 	// List of fields that can be edited by the user
 	editableFields := map[string]bool{
+		"full_name":    true,
+		"email":        true,
 		"avatar":       true,
 		"biography":    true,
 		"publications": true,
@@ -132,6 +134,8 @@ func (l *Lecturer) IsUserEditable(fieldName string) bool {
 // GetEditableFields returns a map of all user-editable fields
 func (l *Lecturer) GetEditableFields() map[string]interface{} {
 	return map[string]interface{}{
+		"full_name":    l.FullName,
+		"email":        l.Email,
 		"avatar":       l.Avatar,
 		"biography":    l.Biography,
 		"publications": l.Publications,
@@ -145,8 +149,6 @@ func (l *Lecturer) GetReadOnlyFields() map[string]interface{} {
 	return map[string]interface{}{
 		"identity_number":    l.IdentityNumber,
 		"lecturer_number":    l.LecturerNumber,
-		"full_name":          l.FullName,
-		"email":              l.Email,
 		"department":         l.Department,
 		"academic_rank":      l.AcademicRank,
 		"academic_rank_desc": l.AcademicRankDesc,
@@ -154,3 +156,12 @@ func (l *Lecturer) GetReadOnlyFields() map[string]interface{} {
 		"status":             l.Status,
 	}
 }
+
+// SyncedFields returns the fields both the campus API and the user can change, by field name.
+// Sync resolves changes from both sides with the conflict policy of the field.
+func (l *Lecturer) SyncedFields() map[string]*string {
+	return map[string]*string{
+		"full_name": &l.FullName,
+		"email":     &l.Email,
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// SessionAttendanceRate is how many of the enrolled students attended a session
+type SessionAttendanceRate struct {
+	SessionID     uint    `json:"session_id"`
+	CourseCode    string  `json:"course_code"`
+	CourseName    string  `json:"course_name"`
+	ClassName     string  `json:"class_name"`
+	MeetingNumber int     `json:"meeting_number"`
+	Optional      bool    `json:"optional"`
+	Enrolled      int     `json:"enrolled"`
+	Attended      int     `json:"attended"` // Present or late
+	Excused       int     `json:"excused"`
+	RatePercent   float64 `json:"rate_percent"` // Attended as a percentage of enrolled
+}
+
+// LecturerOverview is the summary shown on the home screen of the lecturer app
+type LecturerOverview struct {
+	Date                string                  `json:"date"`     // YYYY-MM-DD
+	Semester            string                  `json:"semester"` // Semester of today's schedules
+	Closure             *CalendarEvent          `json:"closure"`  // Holiday or exam week covering today, if any
+	TodaySchedules      []Schedule              `json:"today_schedules"`
+	TodaySessions       []AttendanceSession     `json:"today_sessions"`
+	YesterdayRates      []SessionAttendanceRate `json:"yesterday_rates"`
+	PendingApprovals    []PendingApprovalCount  `json:"pending_approvals"`
+	UnsubmittedJournals []AttendanceSession     `json:"unsubmitted_journals"` // Closed sessions without a topic
+	GeneratedAt         time.Time               `json:"generated_at"`
+}
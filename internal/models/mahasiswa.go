@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MahasiswaSnapshot stores the last successfully synced campus data of a student
+// so it can be served when the campus API is unreachable
+type MahasiswaSnapshot struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"uniqueIndex;not null" json:"user_id"` // Campus user ID
+	Nim        string    `gorm:"size:20;index" json:"nim"`
+	BasicInfo  string    `gorm:"type:text" json:"-"` // JSON encoded MahasiswaInfo
+	Details    string    `gorm:"type:text" json:"-"` // JSON encoded MahasiswaDetail
+	LastSyncAt time.Time `json:"last_sync_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for the MahasiswaSnapshot model
+func (MahasiswaSnapshot) TableName() string {
+	return "mahasiswa_snapshots"
+}
+
+// NewMahasiswaSnapshot builds a snapshot from campus API data
+func NewMahasiswaSnapshot(complete *MahasiswaComplete) (*MahasiswaSnapshot, error) {
+	basicInfo, err := json.Marshal(complete.BasicInfo)
+	if err != nil {
+		return nil, err
+	}
+	details, err := json.Marshal(complete.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MahasiswaSnapshot{
+		UserID:     uint(complete.BasicInfo.UserID),
+		Nim:        complete.BasicInfo.Nim,
+		BasicInfo:  string(basicInfo),
+		Details:    string(details),
+		LastSyncAt: time.Now(),
+	}, nil
+}
+
+// ToMahasiswaComplete decodes the stored campus data
+func (s *MahasiswaSnapshot) ToMahasiswaComplete() (*MahasiswaComplete, error) {
+	var complete MahasiswaComplete
+	if err := json.Unmarshal([]byte(s.BasicInfo), &complete.BasicInfo); err != nil {
+		return nil, err
+	}
+	if s.Details != "" {
+		if err := json.Unmarshal([]byte(s.Details), &complete.Details); err != nil {
+			return nil, err
+		}
+	}
+	return &complete, nil
+}
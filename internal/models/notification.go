@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification is an in-app message for a user
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Type      string     `gorm:"size:50;not null" json:"type"` // e.g. supervision.requested
+	Title     string     `gorm:"size:200;not null" json:"title"`
+	Message   string     `gorm:"type:text" json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName sets the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+)
+
+// OfficeHourBookingStatus represents the state of a student's office-hour booking
+type OfficeHourBookingStatus string
+
+const (
+	// OfficeHourBooked holds a place in the slot
+	OfficeHourBooked OfficeHourBookingStatus = "booked"
+	// OfficeHourCancelled was withdrawn by the student or cancelled with its slot
+	OfficeHourCancelled OfficeHourBookingStatus = "cancelled"
+)
+
+// OfficeHourSlot is a period in which a lecturer is available for students to book
+type OfficeHourSlot struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	LecturerUserID uint       `gorm:"not null;index:idx_office_hour_lecturer_date" json:"lecturer_user_id"`
+	Date           time.Time  `gorm:"type:date;not null;index:idx_office_hour_lecturer_date" json:"date"`
+	StartTime      string     `gorm:"size:5;not null" json:"start_time"` // HH:MM
+	EndTime        string     `gorm:"size:5;not null" json:"end_time"`   // HH:MM
+	Semester       string     `gorm:"size:30;not null" json:"semester"`  // Semester whose schedules the slot is checked against
+	Location       string     `gorm:"size:100;not null" json:"location"`
+	Capacity       int        `gorm:"not null" json:"capacity"`
+	Note           string     `gorm:"type:text" json:"note"`
+	CancelledAt    *time.Time `json:"cancelled_at"`
+	ReminderSentAt *time.Time `json:"-"`
+	Booked         int        `gorm:"-:all" json:"booked"` // Active bookings, filled in by listings
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for the OfficeHourSlot model
+func (OfficeHourSlot) TableName() string {
+	return "office_hour_slots"
+}
+
+// DayOfWeek returns the slot's day in the numbering used by schedules (1 = Monday ... 7 = Sunday)
+func (s *OfficeHourSlot) DayOfWeek() int {
+	day := int(s.Date.Weekday())
+	if day == 0 {
+		return 7
+	}
+	return day
+}
+
+// StartsAt returns when the slot begins
+func (s *OfficeHourSlot) StartsAt() time.Time {
+	start, err := time.ParseInLocation("2006-01-02 15:04", s.Date.Format("2006-01-02")+" "+s.StartTime, time.Local)
+	if err != nil {
+		return s.Date
+	}
+	return start
+}
+
+// OfficeHourBooking is a student's place in an office-hour slot
+type OfficeHourBooking struct {
+	ID            uint                    `gorm:"primaryKey" json:"id"`
+	SlotID        uint                    `gorm:"not null;index" json:"slot_id"`
+	Slot          *OfficeHourSlot         `gorm:"foreignKey:SlotID" json:"slot,omitempty"`
+	StudentUserID uint                    `gorm:"not null;index" json:"student_user_id"`
+	Topic         string                  `gorm:"size:200" json:"topic"`
+	Status        OfficeHourBookingStatus `gorm:"type:VARCHAR(20);not null;default:'booked'" json:"status"`
+	CancelledAt   *time.Time              `json:"cancelled_at"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// TableName sets the table name for the OfficeHourBooking model
+func (OfficeHourBooking) TableName() string {
+	return "office_hour_bookings"
+}
+
+// OfficeHourConflicts lists what an office-hour slot clashes with in the lecturer's timetable
+type OfficeHourConflicts struct {
+	Schedules []Schedule       `json:"schedules"`
+	Bookings  []RoomBooking    `json:"bookings"`
+	Slots     []OfficeHourSlot `json:"slots"`
+}
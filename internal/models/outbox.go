@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEvent is a domain event waiting to be delivered to the streaming backend.
+// Events are written here first so delivery survives restarts and broker outages.
+type OutboxEvent struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	EventName   string     `gorm:"size:100;not null;index" json:"event_name"`
+	Payload     string     `gorm:"type:text;not null" json:"payload"` // JSON envelope as streamed
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	PublishedAt *time.Time `gorm:"index" json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name for the OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PermissionKind tells why a student asks to be excused
+type PermissionKind string
+
+const (
+	// PermissionLeave is an absence with permission (izin), e.g. a family matter
+	PermissionLeave PermissionKind = "izin"
+	// PermissionSick is an absence because of illness (sakit)
+	PermissionSick PermissionKind = "sakit"
+)
+
+// IsValid checks whether the kind is one of the known kinds
+func (k PermissionKind) IsValid() bool {
+	return k == PermissionLeave || k == PermissionSick
+}
+
+// PermissionRequestStatus represents the approval state of a permission request
+type PermissionRequestStatus string
+
+const (
+	// PermissionPending is waiting for the lecturer
+	PermissionPending PermissionRequestStatus = "pending"
+	// PermissionApproved excuses the student from the meetings in its date range
+	PermissionApproved PermissionRequestStatus = "approved"
+	// PermissionRejected was turned down by the lecturer
+	PermissionRejected PermissionRequestStatus = "rejected"
+	// PermissionCancelled was withdrawn by the student
+	PermissionCancelled PermissionRequestStatus = "cancelled"
+)
+
+// PermissionRequest is a student's request to be excused from the meetings of a course
+// between two dates
+type PermissionRequest struct {
+	ID              uint                    `gorm:"primaryKey" json:"id"`
+	StudentUserID   uint                    `gorm:"not null;index" json:"student_user_id"` // Campus user ID of the student
+	Nim             string                  `gorm:"size:20;not null;index" json:"nim"`
+	Kind            PermissionKind          `gorm:"type:VARCHAR(20);not null" json:"kind"`
+	CourseCode      string                  `gorm:"size:20;not null;index:idx_permission_offering" json:"course_code"`
+	CourseName      string                  `gorm:"size:150" json:"course_name"`
+	ClassName       string                  `gorm:"size:50;index:idx_permission_offering" json:"class_name"`
+	Semester        string                  `gorm:"size:30;not null;index:idx_permission_offering" json:"semester"`
+	StartDate       time.Time               `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time               `gorm:"type:date;not null" json:"end_date"`
+	Reason          string                  `gorm:"type:text;not null" json:"reason"`
+	AttachmentName  string                  `gorm:"size:100" json:"-"` // Stored file name of the supporting document
+	AttachmentType  string                  `gorm:"size:50" json:"attachment_type,omitempty"`
+	LecturerUserID  uint                    `gorm:"not null;index" json:"lecturer_user_id"` // Lecturer of the course who decides the request
+	Status          PermissionRequestStatus `gorm:"type:VARCHAR(20);not null;default:'pending';index" json:"status"`
+	DecidedByUserID *uint                   `json:"decided_by_user_id"`
+	DecisionNote    string                  `gorm:"type:text" json:"decision_note"`
+	DecidedAt       *time.Time              `json:"decided_at"`
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the PermissionRequest model
+func (PermissionRequest) TableName() string {
+	return "permission_requests"
+}
+
+// HasAttachment checks whether a supporting document was uploaded with the request
+func (p *PermissionRequest) HasAttachment() bool {
+	return p.AttachmentName != ""
+}
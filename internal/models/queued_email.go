@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// QueuedEmailStatus represents the delivery state of a queued email
+type QueuedEmailStatus string
+
+const (
+	// EmailPending means the email waits for its next delivery attempt
+	EmailPending QueuedEmailStatus = "pending"
+	// EmailSending means a worker claimed the email and is sending it
+	EmailSending QueuedEmailStatus = "sending"
+	// EmailSent means the SMTP server accepted the email
+	EmailSent QueuedEmailStatus = "sent"
+	// EmailFailed means every delivery attempt failed; an admin can requeue it
+	EmailFailed QueuedEmailStatus = "failed"
+)
+
+// QueuedEmail is an email rendered when it was queued and sent by the email queue worker,
+// which retries failed SMTP sends with exponential backoff
+type QueuedEmail struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	Recipient     string            `gorm:"type:VARCHAR(255);not null" json:"recipient"`
+	Template      string            `gorm:"type:VARCHAR(100);not null" json:"template"`
+	Subject       string            `gorm:"type:VARCHAR(255);not null" json:"subject"`
+	Body          string            `gorm:"type:text;not null" json:"-"` // May hold confirmation links, never exposed
+	Status        QueuedEmailStatus `gorm:"type:VARCHAR(20);not null;index:idx_email_queue_due,priority:1" json:"status"`
+	Attempts      int               `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time         `gorm:"not null;index:idx_email_queue_due,priority:2" json:"next_attempt_at"`
+	LastError     string            `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time        `json:"sent_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// TableName sets the table name for the QueuedEmail model
+func (QueuedEmail) TableName() string {
+	return "email_queue"
+}
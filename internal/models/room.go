@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultGeofenceRadius is the check-in radius in meters when a room or session sets none
+const DefaultGeofenceRadius = 100
+
+// Room is a classroom or lab that schedules and attendance sessions refer to by code
+type Room struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Code           string         `gorm:"size:50;not null;uniqueIndex" json:"code"` // Matches Schedule.Room and AttendanceSession.Room
+	Name           string         `gorm:"size:150" json:"name"`
+	Building       string         `gorm:"size:100" json:"building"`
+	Capacity       int            `json:"capacity"`
+	Latitude       *float64       `json:"latitude"`
+	Longitude      *float64       `json:"longitude"`
+	GeofenceRadius int            `gorm:"not null;default:100" json:"geofence_radius"` // Meters
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the Room model
+func (Room) TableName() string {
+	return "rooms"
+}
+
+// HasLocation checks whether the room's coordinates are known
+func (r *Room) HasLocation() bool {
+	return r.Latitude != nil && r.Longitude != nil
+}
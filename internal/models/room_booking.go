@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RoomBookingStatus represents the approval state of a room booking
+type RoomBookingStatus string
+
+const (
+	// BookingPending is waiting for a facilities admin
+	BookingPending RoomBookingStatus = "pending"
+	// BookingApproved reserves the room
+	BookingApproved RoomBookingStatus = "approved"
+	// BookingRejected was turned down by a facilities admin
+	BookingRejected RoomBookingStatus = "rejected"
+	// BookingCancelled was withdrawn by the requester
+	BookingCancelled RoomBookingStatus = "cancelled"
+)
+
+// RoomBookingKind tells what a room is booked for
+type RoomBookingKind string
+
+const (
+	// ExtraSessionBooking is an extra class meeting; approval creates its attendance session
+	ExtraSessionBooking RoomBookingKind = "extra_session"
+	// StudentActivityBooking is a student activity outside of classes
+	StudentActivityBooking RoomBookingKind = "student_activity"
+)
+
+// IsValid checks whether the kind is one of the known kinds
+func (k RoomBookingKind) IsValid() bool {
+	return k == ExtraSessionBooking || k == StudentActivityBooking
+}
+
+// RoomBooking is a request by a lecturer or assistant to use a room outside the class schedule
+type RoomBooking struct {
+	ID              uint              `gorm:"primaryKey" json:"id"`
+	RequesterUserID uint              `gorm:"not null;index" json:"requester_user_id"`
+	RequesterRole   UserType          `gorm:"type:VARCHAR(20);not null" json:"requester_role"`
+	Kind            RoomBookingKind   `gorm:"type:VARCHAR(30);not null" json:"kind"`
+	Room            string            `gorm:"size:50;not null;index:idx_booking_room_date" json:"room"`
+	Date            time.Time         `gorm:"type:date;not null;index:idx_booking_room_date" json:"date"`
+	StartTime       string            `gorm:"size:5;not null" json:"start_time"` // HH:MM
+	EndTime         string            `gorm:"size:5;not null" json:"end_time"`   // HH:MM
+	Semester        string            `gorm:"size:30;not null" json:"semester"`  // Semester whose schedules the booking is checked against
+	Purpose         string            `gorm:"size:200;not null" json:"purpose"`
+	CourseCode      string            `gorm:"size:20" json:"course_code"` // Extra sessions only
+	CourseName      string            `gorm:"size:150" json:"course_name"`
+	ClassName       string            `gorm:"size:50" json:"class_name"`
+	MeetingNumber   int               `json:"meeting_number"`
+	Status          RoomBookingStatus `gorm:"type:VARCHAR(20);not null;default:'pending';index" json:"status"`
+	DecidedByUserID *uint             `json:"decided_by_user_id"`
+	DecisionNote    string            `gorm:"type:text" json:"decision_note"`
+	DecidedAt       *time.Time        `json:"decided_at"`
+	SessionID       *uint             `json:"session_id"` // Attendance session created on approval
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the RoomBooking model
+func (RoomBooking) TableName() string {
+	return "room_bookings"
+}
+
+// DayOfWeek returns the booking's day in the numbering used by schedules (1 = Monday ... 7 = Sunday)
+func (b *RoomBooking) DayOfWeek() int {
+	day := int(b.Date.Weekday())
+	if day == 0 {
+		return 7
+	}
+	return day
+}
+
+// RoomBookingConflicts lists what a booking clashes with
+type RoomBookingConflicts struct {
+	Schedules []Schedule    `json:"schedules"`
+	Bookings  []RoomBooking `json:"bookings"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Schedule is a weekly class slot mapping a course to a lecturer, room and time
+type Schedule struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	CourseCode     string         `gorm:"size:20;not null;index" json:"course_code"`
+	CourseName     string         `gorm:"size:150" json:"course_name"`
+	ClassName      string         `gorm:"size:50" json:"class_name"`                                    // e.g. 12IF1
+	LecturerUserID uint           `gorm:"not null;index:idx_schedule_lecturer" json:"lecturer_user_id"` // Lecturer user ID
+	Room           string         `gorm:"size:50;not null;index:idx_schedule_room" json:"room"`
+	DayOfWeek      int            `gorm:"not null;index:idx_schedule_lecturer;index:idx_schedule_room" json:"day_of_week"` // 1 = Monday ... 7 = Sunday
+	StartTime      string         `gorm:"size:5;not null" json:"start_time"`                                               // HH:MM
+	EndTime        string         `gorm:"size:5;not null" json:"end_time"`                                                 // HH:MM
+	Semester       string         `gorm:"size:30;not null;index" json:"semester"`                                          // e.g. 2024/2025 Ganjil
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the Schedule model
+func (Schedule) TableName() string {
+	return "schedules"
+}
+
+// Course is a course taught in a semester, derived from its class schedules
+type Course struct {
+	CourseCode string `json:"course_code"`
+	CourseName string `json:"course_name"`
+	Semester   string `json:"semester"`
+	Classes    int    `json:"classes"` // Number of classes the course is scheduled for
+}
+
+// Schedule conflict reasons
+const (
+	RoomConflict     = "room"
+	LecturerConflict = "lecturer"
+)
+
+// ScheduleConflict is an existing schedule that clashes with a new or changed one
+type ScheduleConflict struct {
+	Reason   string   `json:"reason"` // room or lecturer
+	Schedule Schedule `json:"schedule"`
+}
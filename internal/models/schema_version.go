@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// SchemaVersion records each schema version applied to the database, so instances
+// running older code can tell the schema has moved past them during rolling deploys
+type SchemaVersion struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Version   int       `gorm:"uniqueIndex;not null" json:"version"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+// TableName sets the table name for the SchemaVersion model
+func (SchemaVersion) TableName() string {
+	return "schema_versions"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// SemesterArchiveFormat identifies semester archives in their manifest
+const SemesterArchiveFormat = "delpresence-semester-archive"
+
+// SemesterArchiveVersion is the layout version of semester archives this build writes and reads
+const SemesterArchiveVersion = 1
+
+// SemesterArchiveManifest describes a semester archive; it is stored as manifest.json next to
+// one JSONL file per table
+type SemesterArchiveManifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	Semester   string         `json:"semester"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Counts     map[string]int `json:"counts"` // Rows per JSONL file, and archived attachments
+}
+
+// ArchivedMaterial is a session material as stored in a semester archive, with the name of
+// its attachment inside the archive
+type ArchivedMaterial struct {
+	SessionMaterial
+	AttachmentName string `json:"attachment_name,omitempty"`
+}
+
+// SemesterArchive is the content of a semester archive
+type SemesterArchive struct {
+	Manifest    SemesterArchiveManifest
+	Schedules   []Schedule
+	Enrollments []Enrollment
+	Sessions    []AttendanceSession
+	Records     []AttendanceRecord
+	Edits       []AttendanceEdit
+	Materials   []ArchivedMaterial
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionMaterial is a note, link or file a lecturer attaches to an attendance session,
+// such as the slides link or a handout for the meeting
+type SessionMaterial struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	SessionID       uint           `gorm:"not null;index" json:"session_id"`
+	Title           string         `gorm:"size:200;not null" json:"title"`
+	Note            string         `gorm:"type:text" json:"note,omitempty"`
+	URL             string         `gorm:"size:500" json:"url,omitempty"` // e.g. the slides link
+	AttachmentName  string         `gorm:"size:100" json:"-"`             // Stored file name of the handout
+	AttachmentType  string         `gorm:"size:50" json:"attachment_type,omitempty"`
+	CreatedByUserID uint           `gorm:"not null" json:"created_by_user_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the SessionMaterial model
+func (SessionMaterial) TableName() string {
+	return "session_materials"
+}
+
+// HasAttachment checks whether a file was uploaded with the material
+func (m *SessionMaterial) HasAttachment() bool {
+	return m.AttachmentName != ""
+}
+
+// SessionDetail is what an enrolled student sees of an attendance session
+type SessionDetail struct {
+	Session   AttendanceSession `json:"session"`
+	Materials []SessionMaterial `json:"materials"`
+	Record    *AttendanceRecord `json:"record"` // The student's attendance, nil when absent
+}
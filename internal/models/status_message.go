@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// StatusKind is what a status message announces
+type StatusKind string
+
+const (
+	// StatusIncident announces an ongoing outage or degradation
+	StatusIncident StatusKind = "incident"
+	// StatusMaintenance announces planned maintenance
+	StatusMaintenance StatusKind = "maintenance"
+	// StatusNotice is any other announcement
+	StatusNotice StatusKind = "notice"
+)
+
+// StatusSeverity decides how prominently clients show a status message
+type StatusSeverity string
+
+const (
+	// SeverityInfo is shown as an informational banner
+	SeverityInfo StatusSeverity = "info"
+	// SeverityWarning is shown as a warning banner
+	SeverityWarning StatusSeverity = "warning"
+	// SeverityCritical is shown as a blocking banner, e.g. when check-ins are down
+	SeverityCritical StatusSeverity = "critical"
+)
+
+// StatusMessage is an outage or maintenance banner admins publish for the mobile and web
+// clients. It is shown from StartsAt until EndsAt, or until it is ended when EndsAt is empty.
+type StatusMessage struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Kind      StatusKind     `gorm:"type:VARCHAR(20);not null" json:"kind"`
+	Severity  StatusSeverity `gorm:"type:VARCHAR(20);not null" json:"severity"`
+	Title     string         `gorm:"size:150;not null" json:"title"`
+	Message   string         `gorm:"type:text" json:"message"`
+	StartsAt  time.Time      `gorm:"not null;index" json:"starts_at"`
+	EndsAt    *time.Time     `gorm:"index" json:"ends_at"`
+	CreatedBy uint           `json:"created_by"` // Admin user ID
+	UpdatedBy uint           `json:"updated_by"` // Admin user ID
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TableName sets the table name for the StatusMessage model
+func (StatusMessage) TableName() string {
+	return "status_messages"
+}
+
+// Validate checks the kind, severity and schedule of the message
+func (m *StatusMessage) Validate() error {
+	switch m.Kind {
+	case StatusIncident, StatusMaintenance, StatusNotice:
+	default:
+		return errors.New("kind must be incident, maintenance or notice")
+	}
+	switch m.Severity {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return errors.New("severity must be info, warning or critical")
+	}
+	if m.StartsAt.IsZero() {
+		return errors.New("starts_at is required")
+	}
+	if m.EndsAt != nil && !m.EndsAt.After(m.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// IsActive checks whether the message is shown at t
+func (m *StatusMessage) IsActive(t time.Time) bool {
+	return !m.StartsAt.After(t) && (m.EndsAt == nil || t.Before(*m.EndsAt))
+}
+
+// Rank orders severities from the least to the most prominent
+func (s StatusSeverity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
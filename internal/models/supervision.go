@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SupervisionStatus represents the confirmation state of a supervision meeting
+type SupervisionStatus string
+
+const (
+	// SupervisionPending is waiting for the supervisor's confirmation
+	SupervisionPending SupervisionStatus = "pending"
+	// SupervisionConfirmed was confirmed by the supervisor
+	SupervisionConfirmed SupervisionStatus = "confirmed"
+	// SupervisionRejected was rejected by the supervisor
+	SupervisionRejected SupervisionStatus = "rejected"
+)
+
+// SupervisionMeeting is a thesis supervision meeting logged by a student
+type SupervisionMeeting struct {
+	ID               uint              `gorm:"primaryKey" json:"id"`
+	StudentUserID    uint              `gorm:"not null;index" json:"student_user_id"` // Campus user ID of the student
+	Nim              string            `gorm:"size:20;not null;index" json:"nim"`
+	SupervisorUserID uint              `gorm:"not null;index" json:"supervisor_user_id"` // Lecturer user ID
+	MeetingDate      time.Time         `gorm:"type:date;not null" json:"meeting_date"`
+	Topic            string            `gorm:"size:200;not null" json:"topic"`
+	Notes            string            `gorm:"type:text" json:"notes"`
+	Status           SupervisionStatus `gorm:"type:VARCHAR(20);not null;default:'pending';index" json:"status"`
+	SupervisorNote   string            `gorm:"type:text" json:"supervisor_note"`
+	DecidedAt        *time.Time        `json:"decided_at"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+// TableName sets the table name for the SupervisionMeeting model
+func (SupervisionMeeting) TableName() string {
+	return "supervision_meetings"
+}
+
+// SupervisionFrequency summarizes confirmed supervision meetings of one lecturer
+type SupervisionFrequency struct {
+	SupervisorUserID uint   `json:"supervisor_user_id"`
+	LecturerName     string `json:"lecturer_name"`
+	DepartmentID     uint   `json:"prodi_id"`
+	Department       string `json:"prodi"`
+	MeetingCount     int    `json:"meeting_count"`
+	StudentCount     int    `json:"student_count"`
+	PendingCount     int    `json:"pending_count"`
+}
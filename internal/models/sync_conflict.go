@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+// ProfileFieldState remembers, for one profile field the campus API and the profile's owner can
+// both change, the value the campus API had at the last sync. A sync compares both sides with it
+// to tell which of them changed the field since.
+type ProfileFieldState struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ProfileType   UserType   `gorm:"type:VARCHAR(20);not null;uniqueIndex:idx_profile_field_state" json:"profile_type"`
+	UserID        uint       `gorm:"not null;uniqueIndex:idx_profile_field_state" json:"user_id"`
+	Field         string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_profile_field_state" json:"field"`
+	CampusValue   string     `gorm:"type:text" json:"campus_value"` // Value of the campus API at the last sync
+	LocalEditedAt *time.Time `json:"local_edited_at,omitempty"`     // When the owner last edited the field
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for the ProfileFieldState model
+func (ProfileFieldState) TableName() string {
+	return "profile_field_states"
+}
+
+// SyncConflictStatus represents the state of a sync conflict
+type SyncConflictStatus string
+
+const (
+	// SyncConflictOpen waits for the owner of the profile to review it
+	SyncConflictOpen SyncConflictStatus = "open"
+	// SyncConflictResolved was reviewed by the owner of the profile
+	SyncConflictResolved SyncConflictStatus = "resolved"
+	// SyncConflictSuperseded was replaced by a later conflict on the same field
+	SyncConflictSuperseded SyncConflictStatus = "superseded"
+)
+
+// SyncConflict sides, as applied by the policy of the field or chosen by the owner
+const (
+	SyncConflictLocal  = "local"
+	SyncConflictCampus = "campus"
+)
+
+// SyncConflict is a profile field both the campus API and the profile's owner changed since the
+// last sync. The field's policy already picked a value; the owner can review it and keep the
+// other one instead.
+type SyncConflict struct {
+	ID          uint               `gorm:"primaryKey" json:"id"`
+	ProfileType UserType           `gorm:"type:VARCHAR(20);not null;index:idx_sync_conflict_profile" json:"profile_type"`
+	UserID      uint               `gorm:"not null;index:idx_sync_conflict_profile" json:"user_id"`
+	Field       string             `gorm:"type:VARCHAR(50);not null" json:"field"`
+	BaseValue   string             `gorm:"type:text" json:"base_value"`   // Value both sides changed from
+	LocalValue  string             `gorm:"type:text" json:"local_value"`  // Value edited by the owner
+	CampusValue string             `gorm:"type:text" json:"campus_value"` // Value of the campus API
+	Policy      string             `gorm:"type:VARCHAR(20);not null" json:"policy"`
+	Applied     string             `gorm:"type:VARCHAR(10);not null" json:"applied"` // Side the policy applied
+	Status      SyncConflictStatus `gorm:"type:VARCHAR(20);not null;index" json:"status"`
+	Kept        string             `gorm:"type:VARCHAR(10)" json:"kept,omitempty"` // Side the owner kept
+	DetectedAt  time.Time          `json:"detected_at"`
+	ResolvedAt  *time.Time         `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// TableName sets the table name for the SyncConflict model
+func (SyncConflict) TableName() string {
+	return "sync_conflicts"
+}
+
+// Value returns the value of one side of the conflict
+func (c *SyncConflict) Value(side string) string {
+	if side == SyncConflictLocal {
+		return c.LocalValue
+	}
+	return c.CampusValue
+}
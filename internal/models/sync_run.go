@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+// SyncKind identifies what a bulk sync from the campus API imports
+type SyncKind string
+
+const (
+	// SyncLecturers imports every lecturer listed by the campus API
+	SyncLecturers SyncKind = "lecturers"
+	// SyncProdi re-syncs every local lecturer, assistant and student profile of one prodi
+	SyncProdi SyncKind = "prodi"
+)
+
+// SyncStatus represents the state of a bulk sync run
+type SyncStatus string
+
+const (
+	// SyncRunning means the sync is still importing
+	SyncRunning SyncStatus = "running"
+	// SyncCompleted means every batch was imported
+	SyncCompleted SyncStatus = "completed"
+	// SyncFailed means the sync stopped early; batches imported before the failure are kept
+	SyncFailed SyncStatus = "failed"
+)
+
+// SyncRun is one run of a bulk sync from the campus API, triggered by an admin or on schedule
+type SyncRun struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Kind        SyncKind   `gorm:"type:VARCHAR(30);not null;index" json:"kind"`
+	Status      SyncStatus `gorm:"type:VARCHAR(20);not null;index" json:"status"`
+	ProdiID     *uint      `gorm:"index" json:"prodi_id,omitempty"` // Set for prodi syncs
+	Fetched     int        `json:"fetched"`                         // Rows returned by the campus API, or profiles queued by a prodi sync
+	Created     int        `json:"created"`
+	Updated     int        `json:"updated"`
+	Skipped     int        `json:"skipped"` // Rows without a campus user ID
+	Failed      int        `json:"failed"`  // Profiles of a prodi sync that could not be synced
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	TriggeredBy uint       `json:"triggered_by"` // Admin user ID; zero for scheduled runs
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for the SyncRun model
+func (SyncRun) TableName() string {
+	return "sync_runs"
+}
+
+// Pending returns how many queued profiles of a prodi sync are still to be synced
+func (r *SyncRun) Pending() int {
+	return r.Fetched - r.Updated - r.Failed
+}
+
+// SyncItemStatus represents the state of one profile queued by a prodi sync
+type SyncItemStatus string
+
+const (
+	// SyncItemPending is waiting for its turn against the campus API
+	SyncItemPending SyncItemStatus = "pending"
+	// SyncItemSynced was refreshed from the campus API
+	SyncItemSynced SyncItemStatus = "synced"
+	// SyncItemFailed could not be refreshed; Error tells why
+	SyncItemFailed SyncItemStatus = "failed"
+)
+
+// SyncRunItem is one profile queued by a prodi sync
+type SyncRunItem struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	RunID       uint           `gorm:"not null;index:idx_sync_item_run_status" json:"run_id"`
+	Run         SyncRun        `gorm:"foreignKey:RunID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID      uint           `gorm:"not null" json:"user_id"` // User ID of the profile
+	ProfileType UserType       `gorm:"type:VARCHAR(20);not null" json:"profile_type"`
+	Status      SyncItemStatus `gorm:"type:VARCHAR(20);not null;index:idx_sync_item_run_status" json:"status"`
+	Error       string         `gorm:"type:text" json:"error,omitempty"`
+	ProcessedAt *time.Time     `json:"processed_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// TableName sets the table name for the SyncRunItem model
+func (SyncRunItem) TableName() string {
+	return "sync_run_items"
+}
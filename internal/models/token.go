@@ -12,18 +12,32 @@ type TokenType string
 const (
 	// RefreshToken represents a refresh token for JWT authentication
 	RefreshToken TokenType = "refresh"
+	// CampusSessionToken refreshes the cookie session of a campus user on the web dashboard
+	CampusSessionToken TokenType = "campus_session"
+	// CampusRefreshToken refreshes the exchanged tokens of a campus user in the mobile app
+	CampusRefreshToken TokenType = "campus_refresh"
+	// EmailChangeCurrentToken confirms a pending email change from the user's current address
+	EmailChangeCurrentToken TokenType = "email_change_current"
+	// EmailChangeNewToken confirms a pending email change from the requested address
+	EmailChangeNewToken TokenType = "email_change_new"
 )
 
 // Token represents a stored token in the database
 type Token struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	UserID    uint           `gorm:"not null" json:"user_id"`
-	Token     string         `gorm:"not null;unique" json:"token"`
-	Type      TokenType      `gorm:"not null;type:VARCHAR(20)" json:"type"`
-	ExpiresAt time.Time      `gorm:"not null" json:"expires_at"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID     uint      `gorm:"primaryKey" json:"id"`
+	UserID uint      `gorm:"not null" json:"user_id"`
+	Token  string    `gorm:"not null;unique" json:"token"`
+	Type   TokenType `gorm:"not null;type:VARCHAR(20)" json:"type"`
+	// ActiveRole is the role a refresh token re-issues access tokens for
+	ActiveRole string `gorm:"type:VARCHAR(20)" json:"active_role,omitempty"`
+	// PendingEmail is the address an email change token switches the user to
+	PendingEmail string `gorm:"size:255" json:"pending_email,omitempty"`
+	// ConfirmedAt is when the link of an email change token was opened
+	ConfirmedAt *time.Time     `json:"confirmed_at,omitempty"`
+	ExpiresAt   time.Time      `gorm:"not null" json:"expires_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // IsExpired checks if the token is expired
@@ -31,6 +45,16 @@ func (t *Token) IsExpired() bool {
 	return time.Now().After(t.ExpiresAt)
 }
 
+// EmailChange is the state of a user's pending email change. The change is applied once both
+// addresses confirmed it and the cooling-off period after the last confirmation has passed.
+type EmailChange struct {
+	NewEmail         string     `json:"new_email"`
+	CurrentConfirmed bool       `json:"current_confirmed"`
+	NewConfirmed     bool       `json:"new_confirmed"`
+	ExpiresAt        time.Time  `json:"expires_at"`             // Links stop working after this
+	EffectiveAt      *time.Time `json:"effective_at,omitempty"` // Set once both addresses confirmed
+}
+
 // TokenResponse represents the token data returned in API responses
 type TokenResponse struct {
 	Token     string    `json:"token"`
@@ -17,24 +17,37 @@ const (
 	LecturerType UserType = "lecturer"
 	// AdminType represents an admin user
 	AdminType UserType = "admin"
+	// AssistantType represents a teaching assistant user
+	AssistantType UserType = "assistant"
 )
 
+// IsValid checks whether t is one of the known user types
+func (t UserType) IsValid() bool {
+	switch t {
+	case StudentType, LecturerType, AdminType, AssistantType:
+		return true
+	}
+	return false
+}
+
 // User represents the user model in the database
 type User struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	FirstName  string         `gorm:"not null" json:"first_name"`
-	MiddleName string         `json:"middle_name"`
-	LastName   string         `json:"last_name"`
-	Email      string         `gorm:"unique;not null" json:"email"`
-	Username   string         `gorm:"unique;not null" json:"username"`
-	Password   string         `gorm:"not null" json:"-"` // Password is not included in JSON responses
-	UserType   UserType       `gorm:"not null;type:VARCHAR(20)" json:"user_type"`
-	Verified   bool           `gorm:"default:true" json:"verified"`
-	Active     bool           `gorm:"default:true" json:"active"`
-	LastLogin  *time.Time     `json:"last_login"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	FirstName  string     `gorm:"not null" json:"first_name"`
+	MiddleName string     `json:"middle_name"`
+	LastName   string     `json:"last_name"`
+	Email      string     `gorm:"unique;not null" json:"email"`
+	Username   string     `gorm:"unique;not null" json:"username"`
+	Password   string     `gorm:"not null" json:"-"` // Password is not included in JSON responses
+	UserType   UserType   `gorm:"not null;type:VARCHAR(20)" json:"user_type"`
+	Verified   bool       `gorm:"default:true" json:"verified"`
+	Active     bool       `gorm:"default:true" json:"active"`
+	LastLogin  *time.Time `json:"last_login"`
+	// MergedIntoID points to the surviving account when this one was merged as a duplicate
+	MergedIntoID *uint          `gorm:"index" json:"merged_into_id,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // BeforeSave hashes the password before saving to database
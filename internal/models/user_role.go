@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// IdentitySource tells which ID space the UserID of a UserRole belongs to. Campus user IDs
+// and local user IDs overlap, so the same number can name two different people.
+type IdentitySource string
+
+const (
+	// CampusIdentity is a campus user ID of a campus-authenticated user
+	CampusIdentity IdentitySource = "campus"
+	// LocalIdentity is the users.id of a local account
+	LocalIdentity IdentitySource = "local"
+)
+
+// UserRole links one account to one of the roles (profiles) it can act as.
+// A student assistant, for example, holds both the student and the assistant role.
+type UserRole struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Source    IdentitySource `gorm:"type:VARCHAR(10);not null;default:'campus';uniqueIndex:idx_user_role_source" json:"source"`
+	UserID    uint           `gorm:"not null;uniqueIndex:idx_user_role_source" json:"user_id"` // Campus user ID or users.id, depending on Source
+	Role      UserType       `gorm:"type:VARCHAR(20);not null;uniqueIndex:idx_user_role_source" json:"role"`
+	ProfileID uint           `json:"profile_id"` // ID of the lecturer/assistant profile, if any
+	IsDefault bool           `gorm:"default:false" json:"is_default"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TableName sets the table name for the UserRole model
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// IsValidRole checks whether the role can be held through a UserRole
+func IsValidRole(role UserType) bool {
+	switch role {
+	case StudentType, LecturerType, AssistantType, AdminType:
+		return true
+	}
+	return false
+}
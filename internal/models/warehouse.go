@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// WarehouseDateFormat is the layout of snapshot dates, which name the snapshot directories
+const WarehouseDateFormat = "2006-01-02"
+
+// WarehouseSnapshotStatus represents the state of a warehouse snapshot run
+type WarehouseSnapshotStatus string
+
+const (
+	// WarehouseSnapshotRunning means the files are still being written
+	WarehouseSnapshotRunning WarehouseSnapshotStatus = "running"
+	// WarehouseSnapshotCompleted means every file of the snapshot was written
+	WarehouseSnapshotCompleted WarehouseSnapshotStatus = "completed"
+	// WarehouseSnapshotFailed means the snapshot could not be written; earlier files of the
+	// same date are kept
+	WarehouseSnapshotFailed WarehouseSnapshotStatus = "failed"
+)
+
+// WarehouseSnapshot records a run of the daily export for the institutional data warehouse
+type WarehouseSnapshot struct {
+	ID           uint                    `gorm:"primaryKey" json:"id"`
+	SnapshotDate string                  `gorm:"size:10;not null;index" json:"snapshot_date"` // Day of the attendance facts, YYYY-MM-DD
+	Status       WarehouseSnapshotStatus `gorm:"type:VARCHAR(20);not null;index" json:"status"`
+	Rows         map[string]int          `gorm:"serializer:json;type:text" json:"rows"` // Rows per file
+	Error        string                  `gorm:"type:text" json:"error,omitempty"`
+	TriggeredBy  uint                    `json:"triggered_by"` // Admin user ID; zero for the nightly job and the CLI
+	StartedAt    time.Time               `json:"started_at"`
+	CompletedAt  *time.Time              `json:"completed_at"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// TableName sets the table name for the WarehouseSnapshot model
+func (WarehouseSnapshot) TableName() string {
+	return "warehouse_snapshots"
+}
+
+// WarehouseAttendanceFact is the attendance of one student in one session. Enrolled students
+// without a record are reported as absent.
+type WarehouseAttendanceFact struct {
+	SessionID      uint
+	OpenedAt       time.Time
+	CourseCode     string
+	ClassName      string
+	Semester       string
+	MeetingNumber  int
+	Optional       bool
+	LecturerUserID uint
+	Nim            string
+	Status         AttendanceStatus
+	Method         *CheckInMethod
+	LateMinutes    int
+	Credit         float64
+	Overflow       bool
+	CheckedInAt    *time.Time
+}
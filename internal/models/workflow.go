@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkflowInstance tracks one item moving through an approval workflow, such as a
+// supervision meeting waiting for its supervisor
+type WorkflowInstance struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	Type            string     `gorm:"size:50;not null;uniqueIndex:idx_workflow_subject" json:"type"`
+	SubjectID       uint       `gorm:"not null;uniqueIndex:idx_workflow_subject" json:"subject_id"` // ID of the item being approved
+	State           string     `gorm:"size:30;not null;index" json:"state"`
+	RequesterUserID uint       `gorm:"not null;index" json:"requester_user_id"`
+	OwnerUserID     uint       `gorm:"not null;index" json:"owner_user_id"`    // Whose duty the approval is
+	AssigneeUserID  uint       `gorm:"not null;index" json:"assignee_user_id"` // Owner or their delegate when the state was entered
+	StateEnteredAt  time.Time  `gorm:"not null" json:"state_entered_at"`
+	DueAt           *time.Time `gorm:"index" json:"due_at"`
+	RemindAt        *time.Time `gorm:"index" json:"remind_at"` // When the assignee is reminded before the SLA is breached
+	RemindedAt      *time.Time `json:"reminded_at"`
+	EscalatedAt     *time.Time `json:"escalated_at"`
+	Breached        bool       `gorm:"not null;default:false" json:"breached"` // Set once any state passed its SLA
+	CompletedAt     *time.Time `json:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName sets the table name for the WorkflowInstance model
+func (WorkflowInstance) TableName() string {
+	return "workflow_instances"
+}
+
+// IsOverdue checks whether the current state has passed its SLA
+func (w *WorkflowInstance) IsOverdue(now time.Time) bool {
+	return w.CompletedAt == nil && w.DueAt != nil && now.After(*w.DueAt)
+}
+
+// WorkflowTransition is one step taken by a workflow instance
+type WorkflowTransition struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	InstanceID  uint      `gorm:"not null;index" json:"instance_id"`
+	FromState   string    `gorm:"size:30" json:"from_state"` // Empty for the initial state
+	ToState     string    `gorm:"size:30;not null" json:"to_state"`
+	Action      string    `gorm:"size:30;not null" json:"action"`
+	ActorUserID uint      `gorm:"not null" json:"actor_user_id"`
+	Note        string    `gorm:"type:text" json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName sets the table name for the WorkflowTransition model
+func (WorkflowTransition) TableName() string {
+	return "workflow_transitions"
+}
+
+// SLAComplianceRow summarizes how well approvals of one workflow type met their SLAs
+type SLAComplianceRow struct {
+	Type              string  `json:"type"`
+	Total             int     `json:"total"`
+	Completed         int     `json:"completed"`
+	CompletedInSLA    int     `gorm:"column:completed_in_sla" json:"completed_in_sla"`
+	Breached          int     `json:"breached"`
+	OpenOverdue       int     `json:"open_overdue"` // Still undecided and past their SLA
+	CompliancePercent float64 `json:"compliance_percent"`
+	AvgDecisionHours  float64 `json:"avg_decision_hours"`
+}
+
+// PendingApprovalCount is how many undecided approvals of one workflow type wait for a user
+type PendingApprovalCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,165 @@
+// Package pseudonym replaces the identifiers of students with stable pseudonyms for admins who
+// may read reports but not learn who the students are. The same identifier always maps to the
+// same pseudonym, so pseudonymized reports can still be joined and counted per student.
+package pseudonym
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"delpresence-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Prefix starts every pseudonym so it cannot be mistaken for a real NIM or user ID
+const Prefix = "anon-"
+
+// contextKey is the gin context key the pseudonymizer of a request is stored under
+const contextKey = "pseudonym.pseudonymizer"
+
+// Digits is the number of hexadecimal digits after the prefix. 128 bits keep pseudonyms of
+// different students from colliding even across every NIM and user ID ever exported, and are
+// too many to brute-force against the HMAC without the key.
+const Digits = 32
+
+// handledKey marks requests whose handler pseudonymized a response that is not JSON
+const handledKey = "pseudonym.handled"
+
+// Pseudonymizer derives pseudonyms from student identifiers with a keyed hash
+type Pseudonymizer struct {
+	key []byte
+}
+
+// ErrMissingKey is returned when a Pseudonymizer is created without a key
+var ErrMissingKey = errors.New("pseudonym key is required")
+
+// New creates a Pseudonymizer keyed with secret
+func New(secret string) (*Pseudonymizer, error) {
+	if secret == "" {
+		return nil, ErrMissingKey
+	}
+	return &Pseudonymizer{key: []byte(secret)}, nil
+}
+
+// Nim returns the pseudonym of a NIM
+func (p *Pseudonymizer) Nim(nim string) string {
+	if nim == "" {
+		return ""
+	}
+	return p.hash("nim:" + nim)
+}
+
+// UserID returns the pseudonym of a student's campus user ID
+func (p *Pseudonymizer) UserID(userID uint) string {
+	if userID == 0 {
+		return ""
+	}
+	return p.hash("user:" + strconv.FormatUint(uint64(userID), 10))
+}
+
+// IsPseudonym reports whether value has the form of a pseudonym
+func IsPseudonym(value string) bool {
+	digits := strings.TrimPrefix(value, Prefix)
+	if len(digits) != Digits || digits == value {
+		return false
+	}
+	_, err := hex.DecodeString(digits)
+	return err == nil
+}
+
+func (p *Pseudonymizer) hash(value string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(value))
+	return Prefix + hex.EncodeToString(mac.Sum(nil))[:Digits]
+}
+
+// identityFields are removed from objects that describe a student, since a name or email
+// identifies them as well as their NIM does
+var identityFields = []string{"nama", "name", "full_name", "email"}
+
+// JSON pseudonymizes a JSON document. Every nim and student_user_id is replaced with its
+// pseudonym; in objects that describe a student, those with a nim or a profile_type of
+// student, user_id is pseudonymized too and the student's name and email are removed.
+func (p *Pseudonymizer) JSON(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return json.Marshal(p.value(document))
+}
+
+func (p *Pseudonymizer) value(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return p.object(typed)
+	case []interface{}:
+		for i := range typed {
+			typed[i] = p.value(typed[i])
+		}
+	}
+	return value
+}
+
+func (p *Pseudonymizer) object(object map[string]interface{}) map[string]interface{} {
+	for key, value := range object {
+		object[key] = p.value(value)
+	}
+
+	nim, hasNim := object["nim"].(string)
+	if hasNim || object["profile_type"] == string(models.StudentType) {
+		if hasNim {
+			object["nim"] = p.Nim(nim)
+		}
+		if userID, ok := userIDOf(object["user_id"]); ok {
+			object["user_id"] = p.UserID(userID)
+		}
+		for _, field := range identityFields {
+			delete(object, field)
+		}
+	}
+	if userID, ok := userIDOf(object["student_user_id"]); ok {
+		object["student_user_id"] = p.UserID(userID)
+	}
+	return object
+}
+
+// userIDOf reads a user ID decoded as a JSON number
+func userIDOf(value interface{}) (uint, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(number.String(), 10, 32)
+	return uint(id), err == nil
+}
+
+// Require marks a request as one whose responses must not reveal students
+func Require(c *gin.Context, p *Pseudonymizer) {
+	c.Set(contextKey, p)
+}
+
+// FromContext returns the pseudonymizer of a request that must not reveal students. Handlers
+// writing responses other than JSON, such as PDF exports, call it and pseudonymize their data
+// themselves; calling it tells the middleware the response was taken care of.
+func FromContext(c *gin.Context) (*Pseudonymizer, bool) {
+	value, exists := c.Get(contextKey)
+	if !exists {
+		return nil, false
+	}
+	c.Set(handledKey, true)
+	return value.(*Pseudonymizer), true
+}
+
+// Handled reports whether the handler pseudonymized its response itself
+func Handled(c *gin.Context) bool {
+	return c.GetBool(handledKey)
+}
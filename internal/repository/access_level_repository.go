@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccessLevelRepository adalah interface untuk operasi repository hak akses admin
+type AccessLevelRepository interface {
+	FindPermissions(level models.AccessLevel) (*models.AccessLevelPermissions, error)
+	FindAllPermissions() ([]models.AccessLevelPermissions, error)
+	SavePolicy(policy *models.AccessLevelPolicy) error
+	ResetPolicy(level models.AccessLevel) error
+}
+
+// accessLevelRepository implementasi dari AccessLevelRepository
+type accessLevelRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessLevelRepository membuat instance baru dari AccessLevelRepository
+func NewAccessLevelRepository(db *gorm.DB) AccessLevelRepository {
+	return &accessLevelRepository{
+		db: db,
+	}
+}
+
+// FindPermissions mengambil hak akses efektif sebuah access level,
+// menggunakan bawaan jika belum pernah diubah admin
+func (r *accessLevelRepository) FindPermissions(level models.AccessLevel) (*models.AccessLevelPermissions, error) {
+	var policy models.AccessLevelPolicy
+	err := r.db.Where("access_level = ?", level).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.AccessLevelPermissions{
+				AccessLevel: level,
+				Permissions: models.DefaultAccessLevelPermissions[level],
+			}, nil
+		}
+		return nil, err
+	}
+
+	permissions := policy.PermissionList()
+	if level == models.SuperAdminAccess {
+		permissions = models.KnownAdminPermissions
+	}
+	return &models.AccessLevelPermissions{
+		AccessLevel: level,
+		Permissions: permissions,
+		Customized:  true,
+		UpdatedAt:   &policy.UpdatedAt,
+	}, nil
+}
+
+// FindAllPermissions mengambil hak akses efektif semua access level
+func (r *accessLevelRepository) FindAllPermissions() ([]models.AccessLevelPermissions, error) {
+	levels := []models.AccessLevel{models.SuperAdminAccess, models.StandardAdminAccess, models.LimitedAdminAccess, models.ViewerAccess}
+	result := make([]models.AccessLevelPermissions, 0, len(levels))
+	for _, level := range levels {
+		permissions, err := r.FindPermissions(level)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *permissions)
+	}
+	return result, nil
+}
+
+// SavePolicy menyimpan hak akses kustom sebuah access level
+func (r *accessLevelRepository) SavePolicy(policy *models.AccessLevelPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "access_level"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permissions", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// ResetPolicy menghapus kustomisasi sehingga access level kembali ke hak akses bawaan
+func (r *accessLevelRepository) ResetPolicy(level models.AccessLevel) error {
+	return r.db.Where("access_level = ?", level).Delete(&models.AccessLevelPolicy{}).Error
+}
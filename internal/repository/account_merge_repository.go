@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrMergeSameUser dikembalikan ketika akun sumber dan tujuan sama
+var ErrMergeSameUser = errors.New("source and target user are the same")
+
+// identitySpace menyatakan jenis ID user yang disimpan sebuah kolom
+type identitySpace int
+
+const (
+	// localIDs: kolom hanya pernah berisi users.id akun lokal
+	localIDs identitySpace = iota
+	// principalIDs: kolom berisi ID user yang membuat request, yaitu users.id untuk login lokal
+	// tetapi campus user ID untuk login kampus, sehingga angka yang sama bisa berarti dua orang
+	principalIDs
+)
+
+// mergedColumn adalah kolom yang merujuk ke user dan dipindahkan ke akun tujuan saat merge
+type mergedColumn struct {
+	table  string
+	column string
+	model  interface{}
+	space  identitySpace
+	// keys adalah kolom lain dari unique index yang memuat kolom user. Baris akun sumber yang
+	// bentrok dengan baris akun tujuan pada kolom ini dihapus, bukan dipindahkan. Slice kosong
+	// yang tidak nil berarti tabel hanya menyimpan satu baris per user.
+	keys []string
+	// scope membatasi baris yang ikut, misalnya hanya token dari login lokal
+	scope func(*gorm.DB) *gorm.DB
+}
+
+// onePerUser menandai tabel yang menyimpan paling banyak satu baris per user
+var onePerUser = []string{}
+
+// localTokens membatasi token ke token milik login lokal; token sesi kampus disimpan dengan
+// campus user ID
+func localTokens(db *gorm.DB) *gorm.DB {
+	return db.Where("type NOT IN ?", []models.TokenType{models.CampusRefreshToken, models.CampusSessionToken})
+}
+
+// mergedColumns adalah semua kolom yang dipindahkan ke akun tujuan. user_roles ditangani
+// tersendiri karena peran default akun sumber tidak ikut dipindahkan.
+var mergedColumns = []mergedColumn{
+	{table: "admins", column: "user_id", model: &models.Admin{}, space: localIDs, keys: onePerUser},
+	{table: "tokens", column: "user_id", model: &models.Token{}, space: localIDs, scope: localTokens},
+	{table: "face_data", column: "student_user_id", model: &models.FaceData{}, space: principalIDs, keys: onePerUser},
+	// Check-ins to a session the surviving account also attended are dropped
+	{table: "attendance_records", column: "student_user_id", model: &models.AttendanceRecord{}, space: principalIDs, keys: []string{"session_id"}},
+	{table: "activity_coordinators", column: "user_id", model: &models.ActivityCoordinator{}, space: principalIDs, keys: []string{"category"}},
+	{table: "assistant_assignments", column: "assistant_user_id", model: &models.AssistantAssignment{}, space: principalIDs, keys: []string{"course_code", "class_name", "semester"}},
+	{table: "email_tracking_opt_outs", column: "user_id", model: &models.EmailTrackingOptOut{}, space: principalIDs, keys: onePerUser},
+	{table: "notifications", column: "user_id", model: &models.Notification{}, space: principalIDs},
+	{table: "internships", column: "student_user_id", model: &models.Internship{}, space: principalIDs},
+	{table: "supervision_meetings", column: "student_user_id", model: &models.SupervisionMeeting{}, space: principalIDs},
+	{table: "supervision_meetings", column: "supervisor_user_id", model: &models.SupervisionMeeting{}, space: principalIDs},
+	{table: "attendance_sessions", column: "lecturer_user_id", model: &models.AttendanceSession{}, space: principalIDs},
+	{table: "schedules", column: "lecturer_user_id", model: &models.Schedule{}, space: principalIDs},
+	{table: "approval_delegations", column: "delegator_user_id", model: &models.ApprovalDelegation{}, space: principalIDs},
+	{table: "approval_delegations", column: "delegate_user_id", model: &models.ApprovalDelegation{}, space: principalIDs},
+	{table: "workflow_instances", column: "requester_user_id", model: &models.WorkflowInstance{}, space: principalIDs},
+	{table: "workflow_instances", column: "owner_user_id", model: &models.WorkflowInstance{}, space: principalIDs},
+	{table: "workflow_instances", column: "assignee_user_id", model: &models.WorkflowInstance{}, space: principalIDs},
+	{table: "room_bookings", column: "requester_user_id", model: &models.RoomBooking{}, space: principalIDs},
+	{table: "permission_requests", column: "student_user_id", model: &models.PermissionRequest{}, space: principalIDs},
+	{table: "permission_requests", column: "lecturer_user_id", model: &models.PermissionRequest{}, space: principalIDs},
+	{table: "check_in_telemetry", column: "student_user_id", model: &models.CheckInTelemetry{}, space: principalIDs},
+	{table: "office_hour_slots", column: "lecturer_user_id", model: &models.OfficeHourSlot{}, space: principalIDs},
+	{table: "office_hour_bookings", column: "student_user_id", model: &models.OfficeHourBooking{}, space: principalIDs},
+	{table: "attendance_edits", column: "student_user_id", model: &models.AttendanceEdit{}, space: principalIDs},
+	{table: "guest_events", column: "organizer_user_id", model: &models.GuestEvent{}, space: principalIDs},
+	{table: "app_attest_keys", column: "student_user_id", model: &models.AppAttestKey{}, space: principalIDs},
+	{table: "shadow_factor_results", column: "student_user_id", model: &models.ShadowFactorResult{}, space: principalIDs},
+}
+
+// campusIdentityColumns adalah kolom yang berisi campus user ID. Akun lokal yang ID-nya muncul
+// di salah satu kolom ini memakai angka yang sama dengan seorang user kampus.
+var campusIdentityColumns = []struct {
+	model  interface{}
+	column string
+	scope  func(*gorm.DB) *gorm.DB
+}{
+	{&models.UserRole{}, "user_id", func(db *gorm.DB) *gorm.DB { return db.Where("source = ?", models.CampusIdentity) }},
+	{&models.Token{}, "user_id", func(db *gorm.DB) *gorm.DB {
+		return db.Where("type IN ?", []models.TokenType{models.CampusRefreshToken, models.CampusSessionToken})
+	}},
+	{&models.CampusCredential{}, "campus_user_id", nil},
+	{&models.MahasiswaSnapshot{}, "user_id", nil},
+	{&models.Lecturer{}, "lecturer_user_id", nil},
+	{&models.Lecturer{}, "campus_user_id", nil},
+	{&models.Assistant{}, "assistant_user_id", nil},
+	{&models.Assistant{}, "campus_user_id", nil},
+}
+
+// AccountMergeRepository adalah interface untuk penggabungan akun duplikat
+type AccountMergeRepository interface {
+	Merge(sourceUserID, targetUserID uint) (*models.AccountMergeResult, error)
+}
+
+// accountMergeRepository implementasi dari AccountMergeRepository
+type accountMergeRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountMergeRepository membuat instance baru dari AccountMergeRepository
+func NewAccountMergeRepository(db *gorm.DB) AccountMergeRepository {
+	return &accountMergeRepository{
+		db: db,
+	}
+}
+
+// Merge memindahkan data milik akun sumber ke akun tujuan dalam satu transaksi,
+// lalu menandai akun sumber sebagai hasil merge dan menghapusnya secara soft delete
+func (r *accountMergeRepository) Merge(sourceUserID, targetUserID uint) (*models.AccountMergeResult, error) {
+	if sourceUserID == targetUserID {
+		return nil, ErrMergeSameUser
+	}
+
+	result := &models.AccountMergeResult{
+		SourceUserID: sourceUserID,
+		TargetUserID: targetUserID,
+		Moved:        map[string]int64{},
+		Dropped:      map[string]int64{},
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source, target models.User
+		if err := tx.First(&source, sourceUserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if err := tx.First(&target, targetUserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		// Columns keyed by whoever made the request are only moved when neither account shares
+		// its ID with a campus user; otherwise their rows may belong to that campus user
+		ambiguous, err := sharesCampusID(tx, sourceUserID, targetUserID)
+		if err != nil {
+			return err
+		}
+
+		for _, merged := range mergedColumns {
+			name := merged.table + "." + merged.column
+			if merged.space == principalIDs && ambiguous {
+				result.Skipped = append(result.Skipped, name)
+				continue
+			}
+			moved, dropped, err := mergeColumn(tx, merged, sourceUserID, targetUserID)
+			if err != nil {
+				return err
+			}
+			result.Moved[name] = moved
+			if dropped > 0 {
+				result.Dropped[name] = dropped
+			}
+		}
+
+		// Roles already held by the surviving account are dropped, the rest are moved
+		res := tx.Where("source = ? AND user_id = ? AND role IN (?)", models.LocalIdentity, sourceUserID,
+			tx.Model(&models.UserRole{}).Select("role").Where("source = ? AND user_id = ?", models.LocalIdentity, targetUserID)).
+			Delete(&models.UserRole{})
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Dropped["user_roles.user_id"] = res.RowsAffected
+		res = tx.Model(&models.UserRole{}).Where("source = ? AND user_id = ?", models.LocalIdentity, sourceUserID).
+			Updates(map[string]interface{}{"user_id": targetUserID, "is_default": false})
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Moved["user_roles.user_id"] = res.RowsAffected
+
+		// Keep the duplicate around for reference, pointing at the surviving account
+		if err := tx.Model(&source).Update("merged_into_id", targetUserID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// sharesCampusID memeriksa apakah salah satu ID juga dipakai sebagai campus user ID
+func sharesCampusID(tx *gorm.DB, userIDs ...uint) (bool, error) {
+	for _, identity := range campusIdentityColumns {
+		query := tx.Model(identity.model).Where(identity.column+" IN ?", userIDs)
+		if identity.scope != nil {
+			query = identity.scope(query)
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mergeColumn memindahkan baris akun sumber pada satu kolom ke akun tujuan. Baris yang bentrok
+// dengan baris akun tujuan pada unique index kolom tersebut dihapus.
+func mergeColumn(tx *gorm.DB, merged mergedColumn, sourceUserID, targetUserID uint) (moved, dropped int64, err error) {
+	rows := func() *gorm.DB {
+		query := tx.Model(merged.model).Where(merged.column+" = ?", sourceUserID)
+		if merged.scope != nil {
+			query = merged.scope(query)
+		}
+		return query
+	}
+
+	if merged.keys != nil {
+		var clashing *gorm.DB
+		if len(merged.keys) == 0 {
+			// One row per user: keep the surviving account's row if it has one
+			var count int64
+			if err := tx.Unscoped().Model(merged.model).Where(merged.column+" = ?", targetUserID).Count(&count).Error; err != nil {
+				return 0, 0, err
+			}
+			if count > 0 {
+				clashing = rows()
+			}
+		} else {
+			targets := tx.Unscoped().Model(merged.model).Select(merged.keys).Where(merged.column+" = ?", targetUserID)
+			clashing = rows().Where("("+strings.Join(merged.keys, ", ")+") IN (?)", targets)
+		}
+		if clashing != nil {
+			res := clashing.Delete(merged.model)
+			if res.Error != nil {
+				return 0, 0, res.Error
+			}
+			dropped = res.RowsAffected
+		}
+	}
+
+	res := rows().Update(merged.column, targetUserID)
+	if res.Error != nil {
+		return 0, 0, res.Error
+	}
+	return res.RowsAffected, dropped, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"delpresence-api/pkg/database"
+
+	"gorm.io/gorm/schema"
+)
+
+// notMerged lists the user columns that Merge leaves alone on purpose, with the reason
+var notMerged = map[string]string{
+	"users.merged_into_id":               "set by the merge itself",
+	"user_roles.user_id":                 "merged separately so the duplicate's default role is not carried over",
+	"tokens.user_id":                     "campus session tokens keep the campus user ID",
+	"lecturers.lecturer_user_id":         "campus-synced profile keyed by campus user ID",
+	"lecturers.campus_user_id":           "campus-synced profile keyed by campus user ID",
+	"assistants.assistant_user_id":       "campus-synced profile keyed by campus user ID",
+	"assistants.campus_user_id":          "campus-synced profile keyed by campus user ID",
+	"mahasiswa_snapshots.user_id":        "campus-synced profile keyed by campus user ID",
+	"campus_credentials.campus_user_id":  "campus-synced credential keyed by campus user ID",
+	"profile_field_states.user_id":       "belongs to the campus-synced profile",
+	"sync_conflicts.user_id":             "belongs to the campus-synced profile",
+	"sync_run_items.user_id":             "history of a sync run",
+	"api_usage_rollups.user_id":          "usage history",
+	"audit_logs.actor_user_id":           "audit history records who acted at the time",
+	"workflow_transitions.actor_user_id": "audit history records who acted at the time",
+}
+
+// isUserColumn reports whether a column name refers to a user
+func isUserColumn(column string) bool {
+	return strings.HasSuffix(column, "user_id") || strings.HasSuffix(column, "_by") || column == "merged_into_id"
+}
+
+// isActorColumn reports whether a column records who made a change; these stay as history
+func isActorColumn(column string) bool {
+	return strings.HasSuffix(column, "_by") || strings.HasSuffix(column, "_by_user_id")
+}
+
+func TestMergeCoversEveryUserColumn(t *testing.T) {
+	merged := map[string]bool{}
+	for _, column := range mergedColumns {
+		merged[column.table+"."+column.column] = true
+	}
+
+	seen := map[string]bool{}
+	cache := &sync.Map{}
+	for _, model := range database.Models() {
+		parsed, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("parse %T: %v", model, err)
+		}
+		for _, field := range parsed.Fields {
+			if field.DBName == "" || !isUserColumn(field.DBName) {
+				continue
+			}
+			name := parsed.Table + "." + field.DBName
+			seen[name] = true
+			if merged[name] || isActorColumn(field.DBName) {
+				continue
+			}
+			if _, ok := notMerged[name]; !ok {
+				t.Errorf("%s references a user but is neither merged nor listed in notMerged", name)
+			}
+		}
+	}
+
+	for name := range merged {
+		if !seen[name] {
+			t.Errorf("merged column %s does not exist in any migrated model", name)
+		}
+	}
+	for name := range notMerged {
+		if !seen[name] {
+			t.Errorf("notMerged column %s does not exist in any migrated model", name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AchievementRepository adalah interface untuk operasi repository gamifikasi presensi
+type AchievementRepository interface {
+	FindMeetingAttendance() ([]models.MeetingAttendance, error)
+	FindGoals() ([]models.AttendanceGoal, error)
+	FindGoalByID(id uint) (*models.AttendanceGoal, error)
+	SaveGoal(goal *models.AttendanceGoal) error
+	DeleteGoal(id uint) error
+	SaveAchievement(achievement *models.StudentAchievement) error
+	AwardBadge(badge *models.StudentBadge) error
+	FindAchievements(nim string) ([]models.StudentAchievement, error)
+	FindBadges(nim string) ([]models.StudentBadge, error)
+}
+
+// achievementRepository implementasi dari AchievementRepository
+type achievementRepository struct {
+	db *gorm.DB
+}
+
+// NewAchievementRepository membuat instance baru dari AchievementRepository
+func NewAchievementRepository(db *gorm.DB) AchievementRepository {
+	return &achievementRepository{
+		db: db,
+	}
+}
+
+// FindMeetingAttendance mengambil kehadiran setiap mahasiswa terdaftar pada setiap sesi yang
+// sudah ditutup dan tidak opsional, urut per mahasiswa, semester, dan waktu sesi
+func (r *achievementRepository) FindMeetingAttendance() ([]models.MeetingAttendance, error) {
+	var rows []models.MeetingAttendance
+	err := r.db.Raw(`SELECT e.nim, s.semester, COALESCE(r.status, '') AS status, COALESCE(r.credit, 0) AS credit
+		FROM attendance_sessions s
+		JOIN enrollments e ON e.course_code = s.course_code AND e.semester = s.semester
+			AND (s.class_name = '' OR e.class_name = '' OR e.class_name = s.class_name)
+		LEFT JOIN attendance_records r ON r.session_id = s.id AND r.nim = e.nim
+		WHERE s.deleted_at IS NULL AND s.status = ? AND s.semester <> '' AND NOT s.optional
+		ORDER BY e.nim, s.semester, COALESCE(s.scheduled_start, s.opened_at), s.id`,
+		models.SessionClosed).Scan(&rows).Error
+	return rows, err
+}
+
+// FindGoals mengambil semua target kehadiran
+func (r *achievementRepository) FindGoals() ([]models.AttendanceGoal, error) {
+	var goals []models.AttendanceGoal
+	err := r.db.Order("prodi ASC, semester ASC").Find(&goals).Error
+	return goals, err
+}
+
+// FindGoalByID mencari target kehadiran berdasarkan ID
+func (r *achievementRepository) FindGoalByID(id uint) (*models.AttendanceGoal, error) {
+	var goal models.AttendanceGoal
+	if err := r.db.Where("id = ?", id).First(&goal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// SaveGoal menyimpan target kehadiran, menggantikan target dengan prodi dan semester yang sama
+func (r *achievementRepository) SaveGoal(goal *models.AttendanceGoal) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "prodi"}, {Name: "semester"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_percent", "updated_by", "updated_at"}),
+	}).Create(goal).Error
+}
+
+// DeleteGoal menghapus target kehadiran
+func (r *achievementRepository) DeleteGoal(id uint) error {
+	return r.db.Delete(&models.AttendanceGoal{}, id).Error
+}
+
+// SaveAchievement menyimpan hasil perhitungan gamifikasi mahasiswa untuk satu semester
+func (r *achievementRepository) SaveAchievement(achievement *models.StudentAchievement) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "nim"}, {Name: "semester"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"meetings", "attended", "weighted_percent", "current_streak", "longest_streak",
+			"goal_percent", "goal_met", "computed_at",
+		}),
+	}).Create(achievement).Error
+}
+
+// AwardBadge memberikan lencana; lencana yang sudah dimiliki tidak diberikan ulang
+func (r *achievementRepository) AwardBadge(badge *models.StudentBadge) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(badge).Error
+}
+
+// FindAchievements mengambil hasil gamifikasi mahasiswa per semester, terbaru lebih dulu
+func (r *achievementRepository) FindAchievements(nim string) ([]models.StudentAchievement, error) {
+	var achievements []models.StudentAchievement
+	err := r.db.Where("nim = ?", nim).Order("semester DESC").Find(&achievements).Error
+	return achievements, err
+}
+
+// FindBadges mengambil lencana yang dimiliki mahasiswa
+func (r *achievementRepository) FindBadges(nim string) ([]models.StudentBadge, error) {
+	var badges []models.StudentBadge
+	err := r.db.Where("nim = ?", nim).Order("awarded_at ASC").Find(&badges).Error
+	return badges, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ActivityRepository adalah interface untuk operasi repository kegiatan non-akademik
+type ActivityRepository interface {
+	FindByID(id uint) (*models.Activity, error)
+	FindAll(category models.ActivityCategory, semester string) ([]models.Activity, error)
+	Create(activity *models.Activity) error
+	AddParticipants(participations []models.ActivityParticipation) (int64, error)
+	FindParticipants(activityID uint) ([]models.ActivityParticipation, error)
+	CountAttendedByCategory(nim, semester string) (map[models.ActivityCategory]int, error)
+	FindQuotas(semester string) ([]models.ActivityQuota, error)
+	SaveQuota(quota *models.ActivityQuota) error
+	IsCoordinator(userID uint, category models.ActivityCategory) (bool, error)
+	AddCoordinator(coordinator *models.ActivityCoordinator) error
+	RemoveCoordinator(userID uint, category models.ActivityCategory) error
+}
+
+// activityRepository implementasi dari ActivityRepository
+type activityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository membuat instance baru dari ActivityRepository
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari kegiatan berdasarkan ID
+func (r *activityRepository) FindByID(id uint) (*models.Activity, error) {
+	var activity models.Activity
+	if err := r.db.Where("id = ?", id).First(&activity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// FindAll mengambil kegiatan, opsional difilter berdasarkan kategori dan semester
+func (r *activityRepository) FindAll(category models.ActivityCategory, semester string) ([]models.Activity, error) {
+	var activities []models.Activity
+	query := r.db.Model(&models.Activity{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if semester != "" {
+		query = query.Where("semester = ?", semester)
+	}
+	if err := query.Order("starts_at DESC").Find(&activities).Error; err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// Create menyimpan kegiatan baru
+func (r *activityRepository) Create(activity *models.Activity) error {
+	return r.db.Create(activity).Error
+}
+
+// AddParticipants mencatat kehadiran mahasiswa, mengabaikan yang sudah tercatat
+func (r *activityRepository) AddParticipants(participations []models.ActivityParticipation) (int64, error) {
+	if len(participations) == 0 {
+		return 0, nil
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&participations)
+	return result.RowsAffected, result.Error
+}
+
+// FindParticipants mengambil daftar mahasiswa yang hadir pada suatu kegiatan
+func (r *activityRepository) FindParticipants(activityID uint) ([]models.ActivityParticipation, error) {
+	var participations []models.ActivityParticipation
+	if err := r.db.Where("activity_id = ?", activityID).Order("nim ASC").Find(&participations).Error; err != nil {
+		return nil, err
+	}
+	return participations, nil
+}
+
+// CountAttendedByCategory menghitung jumlah kegiatan yang dihadiri mahasiswa per kategori
+func (r *activityRepository) CountAttendedByCategory(nim, semester string) (map[models.ActivityCategory]int, error) {
+	var rows []struct {
+		Category models.ActivityCategory
+		Total    int
+	}
+	err := r.db.Table("activity_participations").
+		Select("activities.category AS category, COUNT(*) AS total").
+		Joins("JOIN activities ON activities.id = activity_participations.activity_id AND activities.deleted_at IS NULL").
+		Where("activity_participations.nim = ? AND activities.semester = ?", nim, semester).
+		Group("activities.category").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.ActivityCategory]int, len(rows))
+	for _, row := range rows {
+		counts[row.Category] = row.Total
+	}
+	return counts, nil
+}
+
+// FindQuotas mengambil kuota kegiatan untuk satu semester
+func (r *activityRepository) FindQuotas(semester string) ([]models.ActivityQuota, error) {
+	var quotas []models.ActivityQuota
+	if err := r.db.Where("semester = ?", semester).Order("category ASC").Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	return quotas, nil
+}
+
+// SaveQuota menyimpan atau memperbarui kuota kegiatan
+func (r *activityRepository) SaveQuota(quota *models.ActivityQuota) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category"}, {Name: "semester"}},
+		DoUpdates: clause.AssignmentColumns([]string{"required_count", "updated_at"}),
+	}).Create(quota).Error
+}
+
+// IsCoordinator memeriksa apakah user merupakan koordinator suatu kategori kegiatan
+func (r *activityRepository) IsCoordinator(userID uint, category models.ActivityCategory) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.ActivityCoordinator{}).
+		Where("user_id = ? AND category = ?", userID, category).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddCoordinator menambahkan koordinator kegiatan
+func (r *activityRepository) AddCoordinator(coordinator *models.ActivityCoordinator) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(coordinator).Error
+}
+
+// RemoveCoordinator menghapus koordinator kegiatan
+func (r *activityRepository) RemoveCoordinator(userID uint, category models.ActivityCategory) error {
+	return r.db.Where("user_id = ? AND category = ?", userID, category).Delete(&models.ActivityCoordinator{}).Error
+}
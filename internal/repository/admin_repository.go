@@ -2,13 +2,14 @@ package repository
 
 import (
 	"errors"
-	"os"
 	"time"
 
 	"delpresence-api/internal/models"
 	"delpresence-api/pkg/database"
+	tokens "delpresence-api/pkg/jwt"
 
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // AdminRepository menangani operasi terkait admin
@@ -40,6 +41,32 @@ func (r *AdminRepository) GetAdminByUserID(userID uint) (*models.AdminWithUser,
 	}, nil
 }
 
+// FindByID mencari admin berdasarkan ID, mengembalikan nil jika tidak ditemukan
+func (r *AdminRepository) FindByID(id uint) (*models.Admin, error) {
+	var admin models.Admin
+	if err := database.DB.First(&admin, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &admin, nil
+}
+
+// FindActiveByDepartment mengambil admin aktif dari sebuah prodi atau unit
+func (r *AdminRepository) FindActiveByDepartment(department string) ([]models.Admin, error) {
+	var admins []models.Admin
+	err := database.DB.Where("is_active = ? AND LOWER(department) = LOWER(?)", true, department).Find(&admins).Error
+	return admins, err
+}
+
+// FindActiveByAccessLevel mengambil admin aktif dengan level akses tertentu
+func (r *AdminRepository) FindActiveByAccessLevel(level models.AccessLevel) ([]models.Admin, error) {
+	var admins []models.Admin
+	err := database.DB.Where("is_active = ? AND access_level = ?", true, level).Find(&admins).Error
+	return admins, err
+}
+
 // GetAdminByUsername mendapatkan admin berdasarkan username user
 func (r *AdminRepository) GetAdminByUsername(username string) (*models.AdminWithUser, error) {
 	var user models.User
@@ -63,6 +90,19 @@ func (r *AdminRepository) GetAdminByUsername(username string) (*models.AdminWith
 	}, nil
 }
 
+// CreateAdmin menyimpan user admin baru beserta profil admin-nya dalam satu transaksi. Password
+// user di-hash oleh hook model.
+func (r *AdminRepository) CreateAdmin(user *models.User, admin *models.Admin) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		user.UserType = models.AdminType
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		admin.UserID = user.ID
+		return tx.Create(admin).Error
+	})
+}
+
 // LoginAdmin menangani proses login admin
 func (r *AdminRepository) LoginAdmin(username, password string, clientIP string) (*models.AdminLoginResponse, error) {
 	// Dapatkan admin by username
@@ -152,12 +192,8 @@ func (r *AdminRepository) LoginAdmin(username, password string, clientIP string)
 
 // generateAdminTokens membuat token JWT untuk admin
 func generateAdminTokens(user models.User, admin models.Admin) (string, string, error) {
-	// Secret key dari environment variable
-	secretKey := []byte(os.Getenv("JWT_SECRET_KEY"))
-	if len(secretKey) == 0 {
-		// Fallback to default key if env var not set
-		secretKey = []byte("your-secret-key-here")
-	}
+	// Secret key yang sama dengan yang diverifikasi AdminAuth
+	secretKey := tokens.AdminSecretKey()
 
 	// Ekspirasi token (8 jam)
 	expirationTime := time.Now().Add(8 * time.Hour)
@@ -165,6 +201,8 @@ func generateAdminTokens(user models.User, admin models.Admin) (string, string,
 	// Buat claims (payload)
 	claims := jwt.MapClaims{
 		"uid":          user.ID,
+		"user_id":      user.ID,
+		"user_type":    string(models.AdminType),
 		"username":     user.Username,
 		"email":        user.Email,
 		"role":         "Admin",
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository adalah interface untuk operasi repository API key
+type APIKeyRepository interface {
+	FindByID(id uint) (*models.APIKey, error)
+	FindByHash(keyHash string) (*models.APIKey, error)
+	FindAll() ([]models.APIKey, error)
+	Create(apiKey *models.APIKey) error
+	Revoke(id uint) error
+	TouchLastUsed(id uint) error
+}
+
+// apiKeyRepository implementasi dari APIKeyRepository
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository membuat instance baru dari APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari API key berdasarkan ID
+func (r *apiKeyRepository) FindByID(id uint) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := r.db.Where("id = ?", id).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// FindByHash mencari API key berdasarkan hash kunci
+func (r *apiKeyRepository) FindByHash(keyHash string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// FindAll mengambil semua API key
+func (r *apiKeyRepository) FindAll() ([]models.APIKey, error) {
+	var apiKeys []models.APIKey
+	if err := r.db.Order("created_at DESC").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+// Create menyimpan API key baru
+func (r *apiKeyRepository) Create(apiKey *models.APIKey) error {
+	return r.db.Create(apiKey).Error
+}
+
+// Revoke mencabut API key sehingga tidak dapat digunakan lagi
+func (r *apiKeyRepository) Revoke(id uint) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("revoked_at", time.Now()).Error
+}
+
+// TouchLastUsed mencatat waktu terakhir API key digunakan
+func (r *apiKeyRepository) TouchLastUsed(id uint) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", time.Now()).Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AppAttestKeyRepository adalah interface untuk operasi repository kunci App Attest
+type AppAttestKeyRepository interface {
+	Create(key *models.AppAttestKey) error
+	FindByKeyID(keyID string) (*models.AppAttestKey, error)
+	AdvanceCounter(id uint, from, to uint32) (bool, error)
+}
+
+// appAttestKeyRepository implementasi dari AppAttestKeyRepository
+type appAttestKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAppAttestKeyRepository membuat instance baru dari AppAttestKeyRepository
+func NewAppAttestKeyRepository(db *gorm.DB) AppAttestKeyRepository {
+	return &appAttestKeyRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan kunci App Attest baru
+func (r *appAttestKeyRepository) Create(key *models.AppAttestKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindByKeyID mencari kunci App Attest berdasarkan key ID
+func (r *appAttestKeyRepository) FindByKeyID(keyID string) (*models.AppAttestKey, error) {
+	var key models.AppAttestKey
+	err := r.db.Where("key_id = ?", keyID).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// AdvanceCounter menaikkan counter kunci dari from ke to; mengembalikan false jika counter sudah
+// diubah oleh assertion lain, sehingga assertion yang sama tidak dapat dipakai dua kali
+func (r *appAttestKeyRepository) AdvanceCounter(id uint, from, to uint32) (bool, error) {
+	result := r.db.Model(&models.AppAttestKey{}).
+		Where("id = ? AND counter = ?", id, from).
+		Updates(map[string]interface{}{"counter": to, "last_used_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
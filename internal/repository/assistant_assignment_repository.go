@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssistantAssignmentRepository adalah interface untuk operasi repository penugasan asisten per mata kuliah
+type AssistantAssignmentRepository interface {
+	FindByID(id uint) (*models.AssistantAssignment, error)
+	FindByAssistant(assistantUserID uint) ([]models.AssistantAssignment, error)
+	FindByCoordinator(lecturerUserID uint) ([]models.AssistantAssignment, error)
+	FindForOffering(assistantUserID uint, offering models.CourseOffering) (*models.AssistantAssignment, error)
+	Save(assignment *models.AssistantAssignment) error
+	Delete(id uint) error
+}
+
+// assistantAssignmentRepository implementasi dari AssistantAssignmentRepository
+type assistantAssignmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAssistantAssignmentRepository membuat instance baru dari AssistantAssignmentRepository
+func NewAssistantAssignmentRepository(db *gorm.DB) AssistantAssignmentRepository {
+	return &assistantAssignmentRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari penugasan asisten berdasarkan ID
+func (r *assistantAssignmentRepository) FindByID(id uint) (*models.AssistantAssignment, error) {
+	var assignment models.AssistantAssignment
+	if err := r.db.Where("id = ?", id).First(&assignment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// FindByAssistant mengambil mata kuliah yang ditugaskan kepada seorang asisten
+func (r *assistantAssignmentRepository) FindByAssistant(assistantUserID uint) ([]models.AssistantAssignment, error) {
+	var assignments []models.AssistantAssignment
+	err := r.db.Where("assistant_user_id = ?", assistantUserID).Order("semester DESC, course_code, class_name").Find(&assignments).Error
+	return assignments, err
+}
+
+// FindByCoordinator mengambil penugasan asisten pada mata kuliah yang dijadwalkan untuk dosen
+func (r *assistantAssignmentRepository) FindByCoordinator(lecturerUserID uint) ([]models.AssistantAssignment, error) {
+	var assignments []models.AssistantAssignment
+	err := r.db.Where(`EXISTS (SELECT 1 FROM schedules s
+			WHERE s.lecturer_user_id = ? AND s.deleted_at IS NULL
+				AND s.course_code = assistant_assignments.course_code
+				AND s.semester = assistant_assignments.semester
+				AND (assistant_assignments.class_name = '' OR s.class_name = assistant_assignments.class_name))`, lecturerUserID).
+		Order("semester DESC, course_code, class_name, assistant_user_id").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// FindForOffering mencari penugasan asisten yang berlaku untuk sebuah mata kuliah, mengutamakan
+// penugasan untuk kelas tersebut dibanding penugasan untuk semua kelas
+func (r *assistantAssignmentRepository) FindForOffering(assistantUserID uint, offering models.CourseOffering) (*models.AssistantAssignment, error) {
+	query := r.db.Where("assistant_user_id = ? AND course_code = ?", assistantUserID, offering.CourseCode).
+		Where("class_name = '' OR class_name = ?", offering.ClassName)
+	if offering.Semester != "" {
+		query = query.Where("semester = ?", offering.Semester)
+	}
+
+	var assignment models.AssistantAssignment
+	if err := query.Order("class_name DESC, semester DESC").First(&assignment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// Save menyimpan penugasan asisten, mengganti izin penugasan yang sudah ada untuk mata kuliah yang sama
+func (r *assistantAssignmentRepository) Save(assignment *models.AssistantAssignment) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "assistant_user_id"}, {Name: "course_code"}, {Name: "class_name"}, {Name: "semester"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permissions", "assigned_by", "updated_at"}),
+	}).Create(assignment).Error
+}
+
+// Delete menghapus penugasan asisten
+func (r *assistantAssignmentRepository) Delete(id uint) error {
+	return r.db.Delete(&models.AssistantAssignment{}, id).Error
+}
@@ -0,0 +1,535 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAttendanceUnchanged dikembalikan ketika perubahan presensi tidak mengubah status maupun kredit
+var ErrAttendanceUnchanged = errors.New("attendance already has this status and credit")
+
+// AttendanceRepository adalah interface untuk operasi repository presensi perkuliahan
+type AttendanceRepository interface {
+	FindSessionByID(id uint) (*models.AttendanceSession, error)
+	FindSessionsByLecturer(lecturerUserID uint) ([]models.AttendanceSession, error)
+	FindOpenSessionsInRoom(room string) ([]models.AttendanceSession, error)
+	FindSessions(query utils.PageQuery) ([]models.AttendanceSession, int64, error)
+	FindSessionsBetween(lecturerUserID uint, from, to time.Time) ([]models.AttendanceSession, error)
+	FindSessionsWithoutTopic(lecturerUserID uint, since time.Time) ([]models.AttendanceSession, error)
+	SessionRates(lecturerUserID uint, from, to time.Time) ([]models.SessionAttendanceRate, error)
+	CreateSession(session *models.AttendanceSession, event func() events.Event) error
+	OpenScheduledSession(session *models.AttendanceSession, event func() events.Event) error
+	CloseSession(session *models.AttendanceSession, event func(presentCount int) events.Event) error
+	AnchorSession(session *models.AttendanceSession, latitude, longitude float64) error
+	SetSessionOptional(session *models.AttendanceSession, optional bool) error
+	CreateRecord(record *models.AttendanceRecord, event func() events.Event) (bool, error)
+	FindRecordByID(id uint) (*models.AttendanceRecord, error)
+	FindRecordsBySession(sessionID uint) ([]models.AttendanceRecord, error)
+	FindStudentRecord(sessionID, studentUserID uint) (*models.AttendanceRecord, error)
+	FindRecordsByStudent(studentUserID uint) ([]models.AttendanceRecord, error)
+	FindRecordsByStudentBetween(studentUserID uint, from, to time.Time) ([]models.AttendanceRecord, error)
+	CourseRecap(filter models.AttendanceRecapFilter) (*models.AttendanceRecap, error)
+	LecturerMeetingCounts(filter models.AttendanceRecapFilter) ([]models.LecturerMeetingCount, error)
+	FindCheckInTimings(courseCode string) ([]models.CheckInTiming, error)
+	UpdateRecordGrade(recordID uint, status models.AttendanceStatus, lateMinutes int, credit float64) error
+	EditRecord(edit *models.AttendanceEdit) (*models.AttendanceRecord, error)
+	FindEditsBySession(sessionID uint) ([]models.AttendanceEdit, error)
+}
+
+// attendanceRepository implementasi dari AttendanceRepository
+type attendanceRepository struct {
+	db     *gorm.DB
+	outbox events.Stager
+}
+
+// NewAttendanceRepository membuat instance baru dari AttendanceRepository. Event sesi dan
+// check-in ditulis ke outbox di dalam transaksi perubahan datanya.
+func NewAttendanceRepository(db *gorm.DB, outbox events.Stager) AttendanceRepository {
+	return &attendanceRepository{
+		db:     db,
+		outbox: outbox,
+	}
+}
+
+// FindSessionByID mencari sesi presensi berdasarkan ID
+func (r *attendanceRepository) FindSessionByID(id uint) (*models.AttendanceSession, error) {
+	var session models.AttendanceSession
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindSessionsByLecturer mengambil semua sesi presensi yang dibuka oleh dosen
+func (r *attendanceRepository) FindSessionsByLecturer(lecturerUserID uint) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	if err := r.db.Where("lecturer_user_id = ?", lecturerUserID).Order("opened_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// FindOpenSessionsInRoom mengambil sesi presensi yang sedang dibuka di sebuah ruangan
+func (r *attendanceRepository) FindOpenSessionsInRoom(room string) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	if err := r.db.Where("room = ? AND status = ?", room, models.SessionOpen).Order("opened_at").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// FindSessions mengambil satu halaman sesi presensi seluruh dosen beserta jumlah seluruh sesi
+// yang cocok
+func (r *attendanceRepository) FindSessions(query utils.PageQuery) ([]models.AttendanceSession, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.AttendanceSession{}).Scopes(query.Filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []models.AttendanceSession
+	if err := r.db.Scopes(utils.Paginate(query)).Find(&sessions).Error; err != nil {
+		return nil, 0, err
+	}
+	return sessions, total, nil
+}
+
+// FindSessionsBetween mengambil sesi dosen yang dijadwalkan atau dibuka dalam rentang waktu [from, to)
+func (r *attendanceRepository) FindSessionsBetween(lecturerUserID uint, from, to time.Time) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	err := r.db.Where("lecturer_user_id = ? AND COALESCE(scheduled_start, opened_at) >= ? AND COALESCE(scheduled_start, opened_at) < ?", lecturerUserID, from, to).
+		Order("COALESCE(scheduled_start, opened_at) ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindSessionsWithoutTopic mengambil sesi dosen yang sudah ditutup sejak since tetapi belum
+// diisi topiknya (jurnal perkuliahan)
+func (r *attendanceRepository) FindSessionsWithoutTopic(lecturerUserID uint, since time.Time) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	err := r.db.Where("lecturer_user_id = ? AND status = ? AND opened_at >= ? AND TRIM(topic) = ''", lecturerUserID, models.SessionClosed, since).
+		Order("opened_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// SessionRates menghitung kehadiran mahasiswa terdaftar pada sesi dosen yang dibuka dalam
+// rentang waktu [from, to)
+func (r *attendanceRepository) SessionRates(lecturerUserID uint, from, to time.Time) ([]models.SessionAttendanceRate, error) {
+	var rates []models.SessionAttendanceRate
+	err := r.db.Raw(`SELECT s.id AS session_id, s.course_code, s.course_name, s.class_name, s.meeting_number, s.optional,
+			(SELECT COUNT(*) FROM enrollments e
+				WHERE e.course_code = s.course_code
+					AND (s.class_name = '' OR e.class_name = s.class_name)
+					AND (s.semester = '' OR e.semester = s.semester)) AS enrolled,
+			COUNT(r.id) FILTER (WHERE r.status IN ?) AS attended,
+			COUNT(r.id) FILTER (WHERE r.status = ?) AS excused
+		FROM attendance_sessions s
+		LEFT JOIN attendance_records r ON r.session_id = s.id
+		WHERE s.lecturer_user_id = ? AND s.deleted_at IS NULL AND s.status <> ?
+			AND s.opened_at >= ? AND s.opened_at < ?
+		GROUP BY s.id
+		ORDER BY s.opened_at ASC`,
+		[]models.AttendanceStatus{models.AttendancePresent, models.AttendanceLate}, models.AttendanceExcused,
+		lecturerUserID, models.SessionScheduled, from, to).
+		Scan(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rates {
+		if rates[i].Enrolled > 0 {
+			rates[i].RatePercent = float64(int(float64(rates[i].Attended)*10000/float64(rates[i].Enrolled)+0.5)) / 100
+		}
+	}
+	return rates, nil
+}
+
+// CreateSession menyimpan sesi presensi baru beserta event-nya di outbox
+func (r *attendanceRepository) CreateSession(session *models.AttendanceSession, event func() events.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return r.outbox.Stage(tx, event())
+	})
+}
+
+// OpenScheduledSession membuka sesi terjadwal sehingga mahasiswa dapat check-in
+func (r *attendanceRepository) OpenScheduledSession(session *models.AttendanceSession, event func() events.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		session.Status = models.SessionOpen
+		session.OpenedAt = time.Now()
+		if err := tx.Model(session).Updates(map[string]interface{}{
+			"status":    session.Status,
+			"opened_at": session.OpenedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return r.outbox.Stage(tx, event())
+	})
+}
+
+// CloseSession menutup sesi presensi sehingga tidak menerima check-in lagi. Event penutupan
+// dibuat dengan jumlah mahasiswa yang hadir saat sesi ditutup.
+func (r *attendanceRepository) CloseSession(session *models.AttendanceSession, event func(presentCount int) events.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		session.Status = models.SessionClosed
+		session.ClosedAt = &now
+		if err := tx.Model(session).Updates(map[string]interface{}{
+			"status":    session.Status,
+			"closed_at": session.ClosedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		var present int64
+		if err := tx.Model(&models.AttendanceRecord{}).Where("session_id = ?", session.ID).Count(&present).Error; err != nil {
+			return err
+		}
+		return r.outbox.Stage(tx, event(int(present)))
+	})
+}
+
+// AnchorSession memusatkan geofence sesi pada lokasi perangkat dosen
+func (r *attendanceRepository) AnchorSession(session *models.AttendanceSession, latitude, longitude float64) error {
+	now := time.Now()
+	session.AnchorToLecturer = true
+	session.AnchoredAt = &now
+	session.Latitude, session.Longitude = &latitude, &longitude
+	if session.GeofenceRadius == 0 {
+		session.GeofenceRadius = models.DefaultGeofenceRadius
+	}
+	return r.db.Model(session).Updates(map[string]interface{}{
+		"anchor_to_lecturer": session.AnchorToLecturer,
+		"anchored_at":        session.AnchoredAt,
+		"latitude":           session.Latitude,
+		"longitude":          session.Longitude,
+		"geofence_radius":    session.GeofenceRadius,
+	}).Error
+}
+
+// SetSessionOptional menandai sesi sebagai opsional atau wajib
+func (r *attendanceRepository) SetSessionOptional(session *models.AttendanceSession, optional bool) error {
+	session.Optional = optional
+	return r.db.Model(session).Update("optional", optional).Error
+}
+
+// CreateRecord menyimpan check-in mahasiswa beserta event-nya di outbox. Check-in kedua pada
+// sesi yang sama tidak membuat presensi baru: record diisi dengan presensi yang sudah ada dan
+// hasilnya false, sehingga retry dari aplikasi mendapat jawaban yang sama. Check-in setelah
+// kapasitas sesi terpenuhi ditandai overflow.
+func (r *attendanceRepository) CreateRecord(record *models.AttendanceRecord, event func() events.Event) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Check-ins to the same session are serialized so the capacity cannot be overshot
+		var session models.AttendanceSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, record.SessionID).Error; err != nil {
+			return err
+		}
+
+		if session.Capacity > 0 {
+			var checkedIn int64
+			if err := tx.Model(&models.AttendanceRecord{}).
+				Where("session_id = ? AND status IN ?", record.SessionID, []models.AttendanceStatus{models.AttendancePresent, models.AttendanceLate}).
+				Count(&checkedIn).Error; err != nil {
+				return err
+			}
+			record.Overflow = checkedIn >= int64(session.Capacity)
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return tx.Where("session_id = ? AND student_user_id = ?", record.SessionID, record.StudentUserID).First(record).Error
+		}
+
+		created = true
+		return r.outbox.Stage(tx, event())
+	})
+	return created, err
+}
+
+// FindRecordByID mencari presensi mahasiswa berdasarkan ID
+func (r *attendanceRepository) FindRecordByID(id uint) (*models.AttendanceRecord, error) {
+	var record models.AttendanceRecord
+	if err := r.db.Where("id = ?", id).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindRecordsBySession mengambil semua presensi pada sebuah sesi
+func (r *attendanceRepository) FindRecordsBySession(sessionID uint) ([]models.AttendanceRecord, error) {
+	var records []models.AttendanceRecord
+	if err := r.db.Where("session_id = ?", sessionID).Order("checked_in_at").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// FindStudentRecord mencari presensi seorang mahasiswa pada sebuah sesi
+func (r *attendanceRepository) FindStudentRecord(sessionID, studentUserID uint) (*models.AttendanceRecord, error) {
+	var record models.AttendanceRecord
+	if err := r.db.Where("session_id = ? AND student_user_id = ?", sessionID, studentUserID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindRecordsByStudent mengambil riwayat presensi mahasiswa
+func (r *attendanceRepository) FindRecordsByStudent(studentUserID uint) ([]models.AttendanceRecord, error) {
+	var records []models.AttendanceRecord
+	if err := r.db.Where("student_user_id = ?", studentUserID).Order("checked_in_at DESC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// FindRecordsByStudentBetween mengambil presensi mahasiswa yang dicatat antara from dan to
+func (r *attendanceRepository) FindRecordsByStudentBetween(studentUserID uint, from, to time.Time) ([]models.AttendanceRecord, error) {
+	var records []models.AttendanceRecord
+	err := r.db.Where("student_user_id = ? AND checked_in_at >= ? AND checked_in_at < ?", studentUserID, from, to).
+		Order("checked_in_at ASC").Find(&records).Error
+	return records, err
+}
+
+// FindCheckInTimings mengambil check-in berstatus present atau late beserta waktu mulai
+// sesinya. courseCode kosong berarti semua mata kuliah tanpa kebijakan keterlambatan sendiri.
+func (r *attendanceRepository) FindCheckInTimings(courseCode string) ([]models.CheckInTiming, error) {
+	query := r.db.Table("attendance_records r").
+		Select("r.id AS record_id, r.status, r.late_minutes, r.credit, r.checked_in_at, COALESCE(s.scheduled_start, s.opened_at) AS session_start, s.optional").
+		Joins("JOIN attendance_sessions s ON s.id = r.session_id AND s.deleted_at IS NULL").
+		Where("r.status IN ?", []models.AttendanceStatus{models.AttendancePresent, models.AttendanceLate})
+	if courseCode != "" {
+		query = query.Where("s.course_code = ?", courseCode)
+	} else {
+		query = query.Where("s.course_code NOT IN (?)", r.db.Model(&models.LatePolicy{}).Select("course_code").Where("course_code <> ''"))
+	}
+
+	var timings []models.CheckInTiming
+	err := query.Scan(&timings).Error
+	return timings, err
+}
+
+// UpdateRecordGrade menyimpan status, keterlambatan, dan kredit presensi hasil kebijakan keterlambatan
+func (r *attendanceRepository) UpdateRecordGrade(recordID uint, status models.AttendanceStatus, lateMinutes int, credit float64) error {
+	return r.db.Model(&models.AttendanceRecord{}).Where("id = ?", recordID).Updates(map[string]interface{}{
+		"status":       status,
+		"late_minutes": lateMinutes,
+		"credit":       credit,
+	}).Error
+}
+
+// EditRecord menerapkan perubahan manual presensi seorang mahasiswa dan mencatatnya dalam satu
+// transaksi. Status dan kredit sebelumnya diisi dari presensi yang ada; status absent menghapus
+// presensi dan presensi baru dibuat bila mahasiswa sebelumnya absent. Mengembalikan presensi
+// setelah perubahan, nil bila mahasiswa kini absent.
+func (r *attendanceRepository) EditRecord(edit *models.AttendanceEdit) (*models.AttendanceRecord, error) {
+	var result *models.AttendanceRecord
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var record models.AttendanceRecord
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("session_id = ? AND student_user_id = ?", edit.SessionID, edit.StudentUserID).
+			First(&record).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		edit.PreviousStatus, edit.PreviousCredit = models.AttendanceAbsent, 0
+		if exists {
+			edit.PreviousStatus, edit.PreviousCredit = record.Status, record.Credit
+			edit.Nim = record.Nim
+		}
+		if edit.NewStatus == models.AttendanceAbsent {
+			edit.NewCredit = 0
+		}
+		if !edit.Changed() {
+			return ErrAttendanceUnchanged
+		}
+		edit.EditedAt = time.Now()
+
+		switch {
+		case edit.NewStatus == models.AttendanceAbsent:
+			if err := tx.Delete(&record).Error; err != nil {
+				return err
+			}
+		case exists:
+			if edit.NewStatus != models.AttendanceLate {
+				record.LateMinutes = 0
+			}
+			record.Status, record.Credit = edit.NewStatus, edit.NewCredit
+			if err := tx.Model(&record).Updates(map[string]interface{}{
+				"status":       record.Status,
+				"late_minutes": record.LateMinutes,
+				"credit":       record.Credit,
+			}).Error; err != nil {
+				return err
+			}
+			result = &record
+		default:
+			record = models.AttendanceRecord{
+				SessionID:     edit.SessionID,
+				StudentUserID: edit.StudentUserID,
+				Nim:           edit.Nim,
+				Status:        edit.NewStatus,
+				Method:        models.CheckInLecturerEdit,
+				Credit:        edit.NewCredit,
+				CheckedInAt:   edit.EditedAt,
+			}
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+			result = &record
+		}
+
+		if result != nil {
+			edit.RecordID = &result.ID
+		}
+		return tx.Create(edit).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindEditsBySession mengambil riwayat perubahan manual presensi pada sebuah sesi, terbaru dahulu
+func (r *attendanceRepository) FindEditsBySession(sessionID uint) ([]models.AttendanceEdit, error) {
+	var edits []models.AttendanceEdit
+	err := r.db.Where("session_id = ?", sessionID).Order("edited_at DESC, id DESC").Find(&edits).Error
+	return edits, err
+}
+
+// CourseRecap merekap status presensi setiap mahasiswa pada setiap pertemuan mata kuliah.
+// Mahasiswa yang terdaftar pada mata kuliah tetapi tidak memiliki presensi dihitung absent.
+// Pertemuan opsional tetap ditampilkan tetapi tidak dihitung dalam jumlah dan persentase.
+func (r *attendanceRepository) CourseRecap(filter models.AttendanceRecapFilter) (*models.AttendanceRecap, error) {
+	recap := &models.AttendanceRecap{
+		CourseCode: filter.CourseCode,
+		Semester:   filter.Semester,
+		ClassName:  filter.ClassName,
+	}
+
+	// Scheduled sessions have not taken place yet
+	sessions := r.db.Model(&models.AttendanceSession{}).
+		Select("id, meeting_number, optional, opened_at").
+		Where("course_code = ? AND status <> ?", filter.CourseCode, models.SessionScheduled)
+	enrolled := r.db.Model(&models.Enrollment{}).Select("nim").Where("course_code = ?", filter.CourseCode)
+	if filter.LecturerUserID != 0 {
+		sessions = sessions.Where("lecturer_user_id = ?", filter.LecturerUserID)
+	}
+	if filter.Semester != "" {
+		sessions = sessions.Where("semester = ?", filter.Semester)
+		enrolled = enrolled.Where("semester = ?", filter.Semester)
+	}
+	if filter.ClassName != "" {
+		sessions = sessions.Where("class_name = ?", filter.ClassName)
+		enrolled = enrolled.Where("class_name = ?", filter.ClassName)
+	}
+
+	if err := sessions.Session(&gorm.Session{}).
+		Select("id AS session_id, meeting_number, optional, opened_at").
+		Order("meeting_number ASC, id ASC").
+		Scan(&recap.Meetings).Error; err != nil {
+		return nil, err
+	}
+	if len(recap.Meetings) == 0 {
+		return recap, nil
+	}
+
+	// The roster is every enrolled student plus anyone who checked in without being enrolled
+	const matrix = `WITH sessions AS (?),
+		roster AS (
+			? UNION
+			SELECT r.nim FROM attendance_records r JOIN sessions s ON s.id = r.session_id
+		),
+		cells AS (
+			SELECT roster.nim, s.id AS session_id, s.meeting_number, s.optional,
+				COALESCE(r.status, 'absent') AS status, COALESCE(r.credit, 0) AS credit
+			FROM roster
+			CROSS JOIN sessions s
+			LEFT JOIN attendance_records r ON r.session_id = s.id AND r.nim = roster.nim
+		)`
+
+	var students []models.AttendanceRecapStudent
+	if err := r.db.Raw(matrix+`
+		SELECT nim,
+			COUNT(*) FILTER (WHERE status = ? AND NOT optional) AS present,
+			COUNT(*) FILTER (WHERE status = ? AND NOT optional) AS late,
+			COUNT(*) FILTER (WHERE status = ? AND NOT optional) AS excused,
+			COUNT(*) FILTER (WHERE status = ? AND NOT optional) AS absent,
+			COALESCE(SUM(credit) FILTER (WHERE NOT optional), 0) AS credit,
+			COALESCE(ROUND((SUM(credit) FILTER (WHERE NOT optional) * 100 /
+				NULLIF(COUNT(*) FILTER (WHERE NOT optional), 0))::numeric, 2), 0) AS weighted_percent
+		FROM cells
+		GROUP BY nim
+		ORDER BY nim ASC`,
+		sessions, enrolled,
+		models.AttendancePresent, models.AttendanceLate, models.AttendanceExcused, models.AttendanceAbsent).
+		Scan(&students).Error; err != nil {
+		return nil, err
+	}
+
+	var cells []struct {
+		Nim string
+		models.AttendanceRecapCell
+	}
+	if err := r.db.Raw(matrix+`
+		SELECT nim, session_id, meeting_number, status, credit
+		FROM cells
+		ORDER BY nim ASC, meeting_number ASC, session_id ASC`,
+		sessions, enrolled).
+		Scan(&cells).Error; err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(students))
+	for i, student := range students {
+		index[student.Nim] = i
+	}
+	for _, cell := range cells {
+		if i, ok := index[cell.Nim]; ok {
+			students[i].Meetings = append(students[i].Meetings, cell.AttendanceRecapCell)
+		}
+	}
+	recap.Students = students
+	return recap, nil
+}
+
+// LecturerMeetingCounts menghitung pertemuan yang sudah berlangsung per dosen pada sebuah mata
+// kuliah, tanpa pertemuan opsional
+func (r *attendanceRepository) LecturerMeetingCounts(filter models.AttendanceRecapFilter) ([]models.LecturerMeetingCount, error) {
+	query := r.db.Model(&models.AttendanceSession{}).
+		Select("lecturer_user_id, COUNT(*) AS meetings").
+		Where("course_code = ? AND status <> ? AND NOT optional", filter.CourseCode, models.SessionScheduled)
+	if filter.Semester != "" {
+		query = query.Where("semester = ?", filter.Semester)
+	}
+	if filter.ClassName != "" {
+		query = query.Where("class_name = ?", filter.ClassName)
+	}
+
+	var counts []models.LecturerMeetingCount
+	err := query.Group("lecturer_user_id").Order("lecturer_user_id ASC").Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AttendanceScoreRepository adalah interface untuk operasi repository kebijakan nilai kehadiran
+type AttendanceScoreRepository interface {
+	FindByID(id uint) (*models.AttendanceScorePolicy, error)
+	FindAll() ([]models.AttendanceScorePolicy, error)
+	FindForCourse(courseCode string) (*models.AttendanceScorePolicy, error)
+	Save(policy *models.AttendanceScorePolicy) error
+	Delete(id uint) error
+}
+
+// attendanceScoreRepository implementasi dari AttendanceScoreRepository
+type attendanceScoreRepository struct {
+	db *gorm.DB
+}
+
+// NewAttendanceScoreRepository membuat instance baru dari AttendanceScoreRepository
+func NewAttendanceScoreRepository(db *gorm.DB) AttendanceScoreRepository {
+	return &attendanceScoreRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari kebijakan nilai kehadiran berdasarkan ID
+func (r *attendanceScoreRepository) FindByID(id uint) (*models.AttendanceScorePolicy, error) {
+	var policy models.AttendanceScorePolicy
+	if err := r.db.Where("id = ?", id).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindAll mengambil semua kebijakan nilai kehadiran, kebijakan default lebih dulu
+func (r *attendanceScoreRepository) FindAll() ([]models.AttendanceScorePolicy, error) {
+	var policies []models.AttendanceScorePolicy
+	err := r.db.Order("course_code ASC").Find(&policies).Error
+	return policies, err
+}
+
+// FindForCourse mencari kebijakan mata kuliah, atau kebijakan default jika mata kuliah
+// tidak memiliki kebijakan sendiri
+func (r *attendanceScoreRepository) FindForCourse(courseCode string) (*models.AttendanceScorePolicy, error) {
+	var policy models.AttendanceScorePolicy
+	if err := r.db.Where("course_code IN (?, '')", courseCode).Order("course_code DESC").First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Save menyimpan kebijakan nilai kehadiran, menggantikan kebijakan mata kuliah yang sama
+func (r *attendanceScoreRepository) Save(policy *models.AttendanceScorePolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weight", "formula", "tiers", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// Delete menghapus kebijakan nilai kehadiran
+func (r *attendanceScoreRepository) Delete(id uint) error {
+	return r.db.Delete(&models.AttendanceScorePolicy{}, id).Error
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceStatsRepository adalah interface untuk operasi repository ringkasan presensi
+type AttendanceStatsRepository interface {
+	Refresh() error
+	LastRefreshedAt() (*time.Time, error)
+	FindStudentStats(filter models.AttendanceStatsFilter) ([]models.AttendanceStudentStat, error)
+	FindProdiStats(semester string) ([]models.AttendanceProdiStat, error)
+	CourseSummary(filter models.AttendanceRecapFilter) ([]models.AttendanceRecapStudent, int, error)
+}
+
+// attendanceStatsRepository implementasi dari AttendanceStatsRepository
+type attendanceStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewAttendanceStatsRepository membuat instance baru dari AttendanceStatsRepository
+func NewAttendanceStatsRepository(db *gorm.DB) AttendanceStatsRepository {
+	return &attendanceStatsRepository{
+		db: db,
+	}
+}
+
+// Refresh menghitung ulang seluruh ringkasan presensi per mahasiswa per kelas dan per prodi
+// dalam satu transaksi, sehingga laporan tetap membaca ringkasan lama sampai selesai
+func (r *attendanceStatsRepository) Refresh() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM attendance_student_stats").Error; err != nil {
+			return err
+		}
+		// Each offering is recapped like CourseRecap with course, class and semester given:
+		// its enrolled students plus anyone who checked in, across its held sessions
+		if err := tx.Exec(`INSERT INTO attendance_student_stats (course_code, semester, class_name, nim, prodi_id, prodi_name,
+				meetings, counted_meetings, present, late, excused, absent, credit, weighted_percent, refreshed_at)
+			WITH sessions AS (
+				SELECT id, course_code, semester, class_name, optional FROM attendance_sessions
+				WHERE deleted_at IS NULL AND status <> ?
+			),
+			offerings AS (
+				SELECT course_code, semester, class_name, COUNT(*) AS meetings,
+					COUNT(*) FILTER (WHERE NOT optional) AS counted_meetings
+				FROM sessions
+				GROUP BY course_code, semester, class_name
+			),
+			roster AS (
+				SELECT o.course_code, o.semester, o.class_name, e.nim
+				FROM offerings o
+				JOIN enrollments e ON e.course_code = o.course_code AND e.semester = o.semester AND e.class_name = o.class_name
+				UNION
+				SELECT s.course_code, s.semester, s.class_name, r.nim
+				FROM attendance_records r JOIN sessions s ON s.id = r.session_id
+			),
+			cells AS (
+				SELECT roster.course_code, roster.semester, roster.class_name, roster.nim, s.optional,
+					COALESCE(r.status, ?) AS status, COALESCE(r.credit, 0) AS credit
+				FROM roster
+				JOIN sessions s ON s.course_code = roster.course_code AND s.semester = roster.semester AND s.class_name = roster.class_name
+				LEFT JOIN attendance_records r ON r.session_id = s.id AND r.nim = roster.nim
+			),
+			students AS (
+				SELECT DISTINCT ON (nim) nim,
+					COALESCE((NULLIF(basic_info, '')::jsonb->>'prodi_id')::int, 0) AS prodi_id,
+					COALESCE(NULLIF(basic_info, '')::jsonb->>'prodi_name', '') AS prodi_name
+				FROM mahasiswa_snapshots
+				WHERE nim <> ''
+				ORDER BY nim, last_sync_at DESC
+			)
+			SELECT c.course_code, c.semester, c.class_name, c.nim,
+				COALESCE(st.prodi_id, 0), COALESCE(st.prodi_name, ''),
+				o.meetings, o.counted_meetings,
+				COUNT(*) FILTER (WHERE c.status = ? AND NOT c.optional),
+				COUNT(*) FILTER (WHERE c.status = ? AND NOT c.optional),
+				COUNT(*) FILTER (WHERE c.status = ? AND NOT c.optional),
+				COUNT(*) FILTER (WHERE c.status = ? AND NOT c.optional),
+				COALESCE(SUM(c.credit) FILTER (WHERE NOT c.optional), 0),
+				COALESCE(ROUND((SUM(c.credit) FILTER (WHERE NOT c.optional) * 100 / NULLIF(o.counted_meetings, 0))::numeric, 2), 0),
+				NOW()
+			FROM cells c
+			JOIN offerings o ON o.course_code = c.course_code AND o.semester = c.semester AND o.class_name = c.class_name
+			LEFT JOIN students st ON st.nim = c.nim
+			GROUP BY c.course_code, c.semester, c.class_name, c.nim, st.prodi_id, st.prodi_name, o.meetings, o.counted_meetings`,
+			models.SessionScheduled, models.AttendanceAbsent,
+			models.AttendancePresent, models.AttendanceLate, models.AttendanceExcused, models.AttendanceAbsent).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM attendance_prodi_stats").Error; err != nil {
+			return err
+		}
+		// The exam minimum of a course is its own policy, else the default policy, else the built-in default
+		return tx.Exec(`INSERT INTO attendance_prodi_stats (prodi_id, prodi_name, semester, students, enrollments,
+				present, late, excused, absent, average_percent, below_minimum, refreshed_at)
+			SELECT s.prodi_id, MAX(s.prodi_name), s.semester, COUNT(DISTINCT s.nim), COUNT(*),
+				SUM(s.present), SUM(s.late), SUM(s.excused), SUM(s.absent),
+				COALESCE(ROUND((AVG(s.weighted_percent) FILTER (WHERE s.counted_meetings > 0))::numeric, 2), 0),
+				COUNT(*) FILTER (WHERE s.counted_meetings > 0 AND s.weighted_percent < COALESCE(
+					(SELECT p.min_percent FROM exam_eligibility_policies p
+						WHERE p.course_code IN (s.course_code, '') ORDER BY p.course_code DESC LIMIT 1), ?)),
+				NOW()
+			FROM attendance_student_stats s
+			GROUP BY s.prodi_id, s.semester`,
+			models.DefaultExamMinPercent).Error
+	})
+}
+
+// LastRefreshedAt mengambil waktu ringkasan terakhir dihitung, atau nil jika belum pernah
+func (r *attendanceStatsRepository) LastRefreshedAt() (*time.Time, error) {
+	var result struct {
+		RefreshedAt *time.Time
+	}
+	err := r.db.Model(&models.AttendanceStudentStat{}).Select("MAX(refreshed_at) AS refreshed_at").Scan(&result).Error
+	return result.RefreshedAt, err
+}
+
+// FindStudentStats mengambil ringkasan presensi mahasiswa per kelas sebuah semester
+func (r *attendanceStatsRepository) FindStudentStats(filter models.AttendanceStatsFilter) ([]models.AttendanceStudentStat, error) {
+	query := r.db.Where("semester = ?", filter.Semester)
+	if filter.CourseCode != "" {
+		query = query.Where("course_code = ?", filter.CourseCode)
+	}
+	if filter.ClassName != "" {
+		query = query.Where("class_name = ?", filter.ClassName)
+	}
+	if filter.Nim != "" {
+		query = query.Where("nim = ?", filter.Nim)
+	}
+	if filter.ProdiID != nil {
+		query = query.Where("prodi_id = ?", *filter.ProdiID)
+	}
+
+	var stats []models.AttendanceStudentStat
+	err := query.Order("course_code ASC, class_name ASC, nim ASC").Find(&stats).Error
+	return stats, err
+}
+
+// FindProdiStats mengambil ringkasan presensi per prodi; semester kosong berarti semua semester
+func (r *attendanceStatsRepository) FindProdiStats(semester string) ([]models.AttendanceProdiStat, error) {
+	query := r.db.Model(&models.AttendanceProdiStat{})
+	if semester != "" {
+		query = query.Where("semester = ?", semester)
+	}
+
+	var stats []models.AttendanceProdiStat
+	err := query.Order("semester DESC, prodi_name ASC, prodi_id ASC").Find(&stats).Error
+	return stats, err
+}
+
+// CourseSummary mengambil total presensi setiap mahasiswa sebuah mata kuliah dari ringkasan,
+// dijumlahkan per kelas bila kelas tidak diisi, beserta jumlah pertemuannya. Filter dosen
+// diabaikan karena ringkasan tidak dipisah per dosen.
+func (r *attendanceStatsRepository) CourseSummary(filter models.AttendanceRecapFilter) ([]models.AttendanceRecapStudent, int, error) {
+	query := r.db.Model(&models.AttendanceStudentStat{}).Where("course_code = ?", filter.CourseCode)
+	if filter.Semester != "" {
+		query = query.Where("semester = ?", filter.Semester)
+	}
+	if filter.ClassName != "" {
+		query = query.Where("class_name = ?", filter.ClassName)
+	}
+
+	var meetings int
+	if err := r.db.Raw("SELECT COALESCE(SUM(meetings), 0) FROM (?) offerings",
+		query.Session(&gorm.Session{}).Distinct("course_code", "semester", "class_name", "meetings")).
+		Scan(&meetings).Error; err != nil {
+		return nil, 0, err
+	}
+	if meetings == 0 {
+		return nil, 0, nil
+	}
+
+	var students []models.AttendanceRecapStudent
+	err := query.Session(&gorm.Session{}).
+		Select(`nim, SUM(present) AS present, SUM(late) AS late, SUM(excused) AS excused, SUM(absent) AS absent,
+			SUM(credit) AS credit,
+			COALESCE(ROUND((SUM(credit) * 100 / NULLIF(SUM(counted_meetings), 0))::numeric, 2), 0) AS weighted_percent`).
+		Group("nim").
+		Order("nim ASC").
+		Scan(&students).Error
+	return students, meetings, err
+}
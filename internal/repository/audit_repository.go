@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter berisi kriteria pencarian audit log
+type AuditLogFilter struct {
+	ActorUserID uint
+	Action      string
+	EntityType  string
+	EntityID    string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+}
+
+// AuditRepository adalah interface untuk operasi repository audit log
+type AuditRepository interface {
+	Create(entry *models.AuditLog) error
+	Find(filter AuditLogFilter) ([]models.AuditLog, error)
+}
+
+// auditRepository implementasi dari AuditRepository
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository membuat instance baru dari AuditRepository
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan entri audit log baru
+func (r *auditRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// Find mengambil audit log sesuai filter, terbaru terlebih dahulu
+func (r *auditRepository) Find(filter AuditLogFilter) ([]models.AuditLog, error) {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.ActorUserID != 0 {
+		query = query.Where("actor_user_id = ?", filter.ActorUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var entries []models.AuditLog
+	if err := query.Order("created_at DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
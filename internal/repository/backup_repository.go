@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BackupRepository adalah interface untuk operasi repository backup database
+type BackupRepository interface {
+	FindByID(id uint) (*models.Backup, error)
+	FindRecent(limit int) ([]models.Backup, error)
+	Create(backup *models.Backup) error
+	Update(backup *models.Backup) error
+	CreateRestoreDrill(drill *models.RestoreDrill) error
+	FindRestoreDrills(limit int) ([]models.RestoreDrill, error)
+}
+
+// backupRepository implementasi dari BackupRepository
+type backupRepository struct {
+	db *gorm.DB
+}
+
+// NewBackupRepository membuat instance baru dari BackupRepository
+func NewBackupRepository(db *gorm.DB) BackupRepository {
+	return &backupRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari backup berdasarkan ID
+func (r *backupRepository) FindByID(id uint) (*models.Backup, error) {
+	var backup models.Backup
+	if err := r.db.First(&backup, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// FindRecent mengambil backup terbaru
+func (r *backupRepository) FindRecent(limit int) ([]models.Backup, error) {
+	var backups []models.Backup
+	if err := r.db.Order("started_at DESC").Limit(limit).Find(&backups).Error; err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+// Create menyimpan backup baru
+func (r *backupRepository) Create(backup *models.Backup) error {
+	return r.db.Create(backup).Error
+}
+
+// Update memperbarui data backup
+func (r *backupRepository) Update(backup *models.Backup) error {
+	return r.db.Save(backup).Error
+}
+
+// CreateRestoreDrill menyimpan catatan latihan restore
+func (r *backupRepository) CreateRestoreDrill(drill *models.RestoreDrill) error {
+	return r.db.Create(drill).Error
+}
+
+// FindRestoreDrills mengambil latihan restore terbaru beserta backup-nya
+func (r *backupRepository) FindRestoreDrills(limit int) ([]models.RestoreDrill, error) {
+	var drills []models.RestoreDrill
+	if err := r.db.Preload("Backup").Order("performed_at DESC").Limit(limit).Find(&drills).Error; err != nil {
+		return nil, err
+	}
+	return drills, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CalendarRepository adalah interface untuk operasi repository kalender akademik
+type CalendarRepository interface {
+	FindByID(id uint) (*models.CalendarEvent, error)
+	FindBetween(from, to time.Time) ([]models.CalendarEvent, error)
+	Create(event *models.CalendarEvent) error
+	Delete(id uint) error
+}
+
+// calendarRepository implementasi dari CalendarRepository
+type calendarRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarRepository membuat instance baru dari CalendarRepository
+func NewCalendarRepository(db *gorm.DB) CalendarRepository {
+	return &calendarRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari event kalender berdasarkan ID
+func (r *calendarRepository) FindByID(id uint) (*models.CalendarEvent, error) {
+	var event models.CalendarEvent
+	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindBetween mengambil event kalender yang beririsan dengan rentang tanggal (inklusif)
+func (r *calendarRepository) FindBetween(from, to time.Time) ([]models.CalendarEvent, error) {
+	var events []models.CalendarEvent
+	err := r.db.Where("start_date <= ? AND end_date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("start_date ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// Create menyimpan event kalender baru
+func (r *calendarRepository) Create(event *models.CalendarEvent) error {
+	return r.db.Create(event).Error
+}
+
+// Delete menghapus event kalender
+func (r *calendarRepository) Delete(id uint) error {
+	return r.db.Delete(&models.CalendarEvent{}, id).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CampusCredentialRepository adalah interface untuk operasi repository token CIS pengguna kampus
+type CampusCredentialRepository interface {
+	Save(credential *models.CampusCredential) error
+	FindByCampusUserID(campusUserID int) (*models.CampusCredential, error)
+	RevokeTokens(campusUserID int, at time.Time) error
+}
+
+// campusCredentialRepository implementasi dari CampusCredentialRepository
+type campusCredentialRepository struct {
+	db *gorm.DB
+}
+
+// NewCampusCredentialRepository membuat instance baru dari CampusCredentialRepository
+func NewCampusCredentialRepository(db *gorm.DB) CampusCredentialRepository {
+	return &campusCredentialRepository{
+		db: db,
+	}
+}
+
+// Save menyimpan token CIS terbaru dari login pengguna kampus. Batas pencabutan token
+// yang sudah ada tidak diubah.
+func (r *campusCredentialRepository) Save(credential *models.CampusCredential) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "campus_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"username", "token", "refresh_token", "last_login_at", "updated_at"}),
+	}).Create(credential).Error
+}
+
+// FindByCampusUserID mencari token CIS berdasarkan ID user kampus
+func (r *campusCredentialRepository) FindByCampusUserID(campusUserID int) (*models.CampusCredential, error) {
+	var credential models.CampusCredential
+	if err := r.db.Where("campus_user_id = ?", campusUserID).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// RevokeTokens mencabut semua token DelPresence milik pengguna kampus yang diterbitkan
+// sebelum at, beserta token refresh aplikasi dan dashboard-nya
+func (r *campusCredentialRepository) RevokeTokens(campusUserID int, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		credential := &models.CampusCredential{CampusUserID: campusUserID, TokensValidAfter: at}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "campus_user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tokens_valid_after", "updated_at"}),
+		}).Create(credential).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND type IN ?", campusUserID,
+			[]models.TokenType{models.CampusRefreshToken, models.CampusSessionToken}).
+			Delete(&models.Token{}).Error
+	})
+}
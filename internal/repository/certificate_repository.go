@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CertificateRepository adalah interface untuk operasi repository sertifikat keikutsertaan acara
+type CertificateRepository interface {
+	FindTemplate(eventID uint) (*models.CertificateTemplate, error)
+	SaveTemplate(template *models.CertificateTemplate) error
+	FindByEvent(eventID uint) ([]models.Certificate, error)
+	FindByID(id uint) (*models.Certificate, error)
+	FindByCode(code string) (*models.Certificate, error)
+	FindByRegistration(registrationID uint) (*models.Certificate, error)
+	Create(certificate *models.Certificate) error
+	Revoke(certificate *models.Certificate) error
+}
+
+// certificateRepository implementasi dari CertificateRepository
+type certificateRepository struct {
+	db *gorm.DB
+}
+
+// NewCertificateRepository membuat instance baru dari CertificateRepository
+func NewCertificateRepository(db *gorm.DB) CertificateRepository {
+	return &certificateRepository{
+		db: db,
+	}
+}
+
+// FindTemplate mencari template sertifikat sebuah acara
+func (r *certificateRepository) FindTemplate(eventID uint) (*models.CertificateTemplate, error) {
+	var template models.CertificateTemplate
+	if err := r.db.Where("event_id = ?", eventID).First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SaveTemplate menyimpan atau memperbarui template sertifikat sebuah acara
+func (r *certificateRepository) SaveTemplate(template *models.CertificateTemplate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "body", "signer_name", "signer_title", "updated_at"}),
+	}).Create(template).Error
+}
+
+// FindByEvent mengambil sertifikat yang diterbitkan untuk sebuah acara
+func (r *certificateRepository) FindByEvent(eventID uint) ([]models.Certificate, error) {
+	var certificates []models.Certificate
+	err := r.db.Where("event_id = ?", eventID).Order("recipient_name ASC").Find(&certificates).Error
+	return certificates, err
+}
+
+// FindByID mencari sertifikat berdasarkan ID
+func (r *certificateRepository) FindByID(id uint) (*models.Certificate, error) {
+	return r.findOne("id = ?", id)
+}
+
+// FindByCode mencari sertifikat berdasarkan kode verifikasinya
+func (r *certificateRepository) FindByCode(code string) (*models.Certificate, error) {
+	return r.findOne("code = ?", code)
+}
+
+// FindByRegistration mencari sertifikat milik pendaftaran tamu
+func (r *certificateRepository) FindByRegistration(registrationID uint) (*models.Certificate, error) {
+	return r.findOne("registration_id = ?", registrationID)
+}
+
+// Create menyimpan sertifikat baru; pendaftaran yang sudah memiliki sertifikat dilewati
+func (r *certificateRepository) Create(certificate *models.Certificate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "registration_id"}},
+		DoNothing: true,
+	}).Create(certificate).Error
+}
+
+// Revoke mencabut sertifikat sehingga verifikasinya tidak lagi valid
+func (r *certificateRepository) Revoke(certificate *models.Certificate) error {
+	now := time.Now()
+	if err := r.db.Model(&models.Certificate{}).Where("id = ? AND revoked_at IS NULL", certificate.ID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	certificate.RevokedAt = &now
+	return nil
+}
+
+// findOne mencari satu sertifikat dengan kondisi yang diberikan
+func (r *certificateRepository) findOne(query string, arg interface{}) (*models.Certificate, error) {
+	var certificate models.Certificate
+	if err := r.db.Where(query, arg).First(&certificate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &certificate, nil
+}
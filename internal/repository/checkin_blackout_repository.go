@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckInBlackoutRepository adalah interface untuk operasi repository jam larangan check-in
+type CheckInBlackoutRepository interface {
+	FindAll() ([]models.CheckInBlackoutPolicy, error)
+	FindForEventType(eventType models.CheckInEventType) (*models.CheckInBlackoutPolicy, error)
+	Save(policy *models.CheckInBlackoutPolicy) error
+	Delete(eventType models.CheckInEventType) (bool, error)
+}
+
+// checkInBlackoutRepository implementasi dari CheckInBlackoutRepository
+type checkInBlackoutRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckInBlackoutRepository membuat instance baru dari CheckInBlackoutRepository
+func NewCheckInBlackoutRepository(db *gorm.DB) CheckInBlackoutRepository {
+	return &checkInBlackoutRepository{
+		db: db,
+	}
+}
+
+// FindAll mengambil semua kebijakan jam larangan check-in, kebijakan global lebih dulu
+func (r *checkInBlackoutRepository) FindAll() ([]models.CheckInBlackoutPolicy, error) {
+	var policies []models.CheckInBlackoutPolicy
+	err := r.db.Order("event_type ASC").Find(&policies).Error
+	return policies, err
+}
+
+// FindForEventType mencari kebijakan jenis kegiatan, atau kebijakan global jika jenis kegiatan
+// tidak memiliki kebijakan sendiri
+func (r *checkInBlackoutRepository) FindForEventType(eventType models.CheckInEventType) (*models.CheckInBlackoutPolicy, error) {
+	var policy models.CheckInBlackoutPolicy
+	if err := r.db.Where("event_type IN (?, '')", eventType).Order("event_type DESC").First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Save menyimpan atau menggantikan kebijakan sebuah jenis kegiatan
+func (r *checkInBlackoutRepository) Save(policy *models.CheckInBlackoutPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"windows", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// Delete menghapus kebijakan sebuah jenis kegiatan dan melaporkan apakah kebijakan itu ada
+func (r *checkInBlackoutRepository) Delete(eventType models.CheckInEventType) (bool, error) {
+	result := r.db.Where("event_type = ?", eventType).Delete(&models.CheckInBlackoutPolicy{})
+	return result.RowsAffected > 0, result.Error
+}
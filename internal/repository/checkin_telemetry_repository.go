@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CheckInTelemetryRepository adalah interface untuk operasi repository telemetri check-in
+type CheckInTelemetryRepository interface {
+	Create(telemetry *models.CheckInTelemetry) error
+	FindByStudent(studentUserID uint, from, to time.Time) ([]models.CheckInTelemetry, error)
+	FindByDevice(deviceID string, from, to time.Time) ([]models.CheckInTelemetry, error)
+	FindDeviceStudents(deviceIDs []string, excludeStudentUserID uint, from, to time.Time) ([]models.DeviceStudent, error)
+	DeleteBefore(cutoff time.Time, limit int) (int64, error)
+	SessionHealth(sessionID uint, since time.Time) (*models.CheckInHealth, error)
+	FailureReasons(sessionID uint, since time.Time) ([]models.CheckInFailureReason, error)
+	SessionSLA(from, to time.Time) ([]models.SessionCheckInSLA, error)
+}
+
+// checkInHealthColumns menghitung ringkasan CheckInHealth dari telemetri t
+const checkInHealthColumns = `COUNT(*) AS attempts,
+	COUNT(*) FILTER (WHERE t.record_id IS NOT NULL) AS accepted,
+	COUNT(*) FILTER (WHERE t.record_id IS NULL AND t.http_status < 500) AS student_errors,
+	COUNT(*) FILTER (WHERE t.http_status >= 500) AS system_failures,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY t.latency_ms), 0) AS median_latency_ms,
+	COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY t.latency_ms), 0) AS p95_latency_ms,
+	MAX(t.created_at) AS last_attempt_at`
+
+// checkInTelemetryRepository implementasi dari CheckInTelemetryRepository
+type checkInTelemetryRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckInTelemetryRepository membuat instance baru dari CheckInTelemetryRepository
+func NewCheckInTelemetryRepository(db *gorm.DB) CheckInTelemetryRepository {
+	return &checkInTelemetryRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan telemetri sebuah percobaan check-in
+func (r *checkInTelemetryRepository) Create(telemetry *models.CheckInTelemetry) error {
+	return r.db.Create(telemetry).Error
+}
+
+// FindByStudent mengambil telemetri check-in seorang mahasiswa antara from dan to
+func (r *checkInTelemetryRepository) FindByStudent(studentUserID uint, from, to time.Time) ([]models.CheckInTelemetry, error) {
+	var telemetry []models.CheckInTelemetry
+	err := r.db.Where("student_user_id = ? AND created_at >= ? AND created_at < ?", studentUserID, from, to).
+		Order("created_at ASC, id ASC").Find(&telemetry).Error
+	return telemetry, err
+}
+
+// FindByDevice mengambil telemetri check-in dari sebuah perangkat antara from dan to
+func (r *checkInTelemetryRepository) FindByDevice(deviceID string, from, to time.Time) ([]models.CheckInTelemetry, error) {
+	var telemetry []models.CheckInTelemetry
+	err := r.db.Where("device_id = ? AND created_at >= ? AND created_at < ?", deviceID, from, to).
+		Order("created_at ASC, id ASC").Find(&telemetry).Error
+	return telemetry, err
+}
+
+// FindDeviceStudents mengambil mahasiswa lain yang check-in dari perangkat-perangkat tersebut
+// antara from dan to
+func (r *checkInTelemetryRepository) FindDeviceStudents(deviceIDs []string, excludeStudentUserID uint, from, to time.Time) ([]models.DeviceStudent, error) {
+	students := []models.DeviceStudent{}
+	if len(deviceIDs) == 0 {
+		return students, nil
+	}
+	err := r.db.Model(&models.CheckInTelemetry{}).Distinct("device_id", "student_user_id").
+		Where("device_id IN ? AND student_user_id <> ? AND created_at >= ? AND created_at < ?", deviceIDs, excludeStudentUserID, from, to).
+		Order("device_id, student_user_id").Scan(&students).Error
+	return students, err
+}
+
+// DeleteBefore menghapus paling banyak limit telemetri yang dibuat sebelum cutoff
+func (r *checkInTelemetryRepository) DeleteBefore(cutoff time.Time, limit int) (int64, error) {
+	res := r.db.Where("id IN (?)", r.db.Model(&models.CheckInTelemetry{}).Select("id").
+		Where("created_at < ?", cutoff).Limit(limit)).
+		Delete(&models.CheckInTelemetry{})
+	return res.RowsAffected, res.Error
+}
+
+// SessionHealth meringkas percobaan check-in sebuah sesi sejak since
+func (r *checkInTelemetryRepository) SessionHealth(sessionID uint, since time.Time) (*models.CheckInHealth, error) {
+	var health models.CheckInHealth
+	err := r.db.Table("check_in_telemetry AS t").Select(checkInHealthColumns).
+		Where("t.session_id = ? AND t.created_at >= ?", sessionID, since).
+		Scan(&health).Error
+	if err != nil {
+		return nil, err
+	}
+	health.ComputeRates()
+	return &health, nil
+}
+
+// FailureReasons menghitung percobaan check-in yang gagal pada sebuah sesi sejak since per
+// langkah dan status respons
+func (r *checkInTelemetryRepository) FailureReasons(sessionID uint, since time.Time) ([]models.CheckInFailureReason, error) {
+	reasons := []models.CheckInFailureReason{}
+	err := r.db.Model(&models.CheckInTelemetry{}).
+		Select("failed_step, http_status, COUNT(*) AS count, http_status >= 500 AS system_fault").
+		Where("session_id = ? AND created_at >= ? AND record_id IS NULL", sessionID, since).
+		Group("failed_step, http_status").Order("count DESC, http_status ASC").
+		Scan(&reasons).Error
+	return reasons, err
+}
+
+// SessionSLA meringkas percobaan check-in antara from dan to per sesi, dimulai dari sesi
+// dengan kegagalan sistem terbanyak
+func (r *checkInTelemetryRepository) SessionSLA(from, to time.Time) ([]models.SessionCheckInSLA, error) {
+	rows := []models.SessionCheckInSLA{}
+	err := r.db.Table("check_in_telemetry AS t").
+		Select("t.session_id, s.course_code, s.class_name, s.opened_at, "+checkInHealthColumns+`,
+			COUNT(DISTINCT t.student_user_id) FILTER (WHERE t.http_status >= 500 AND NOT EXISTS (
+				SELECT 1 FROM attendance_records ar WHERE ar.session_id = t.session_id AND ar.student_user_id = t.student_user_id
+			)) AS affected_students`).
+		Joins("JOIN attendance_sessions s ON s.id = t.session_id").
+		Where("t.created_at >= ? AND t.created_at < ?", from, to).
+		Group("t.session_id, s.course_code, s.class_name, s.opened_at").
+		Order("system_failures DESC, t.session_id DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].ComputeRates()
+	}
+	return rows, nil
+}
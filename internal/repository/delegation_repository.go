@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrDelegationOverlap dikembalikan ketika dosen sudah mendelegasikan tugas yang sama pada rentang tanggal tersebut
+var ErrDelegationOverlap = errors.New("an active delegation already covers this period")
+
+// DelegationRepository adalah interface untuk operasi repository delegasi persetujuan
+type DelegationRepository interface {
+	FindByID(id uint) (*models.ApprovalDelegation, error)
+	FindByDelegator(delegatorUserID uint) ([]models.ApprovalDelegation, error)
+	FindByDelegate(delegateUserID uint) ([]models.ApprovalDelegation, error)
+	FindActive(day time.Time) ([]models.ApprovalDelegation, error)
+	Create(delegation *models.ApprovalDelegation) error
+	Revoke(delegation *models.ApprovalDelegation) error
+}
+
+// delegationRepository implementasi dari DelegationRepository
+type delegationRepository struct {
+	db *gorm.DB
+}
+
+// NewDelegationRepository membuat instance baru dari DelegationRepository
+func NewDelegationRepository(db *gorm.DB) DelegationRepository {
+	return &delegationRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari delegasi berdasarkan ID
+func (r *delegationRepository) FindByID(id uint) (*models.ApprovalDelegation, error) {
+	var delegation models.ApprovalDelegation
+	if err := r.db.Where("id = ?", id).First(&delegation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// FindByDelegator mengambil delegasi yang dibuat oleh dosen
+func (r *delegationRepository) FindByDelegator(delegatorUserID uint) ([]models.ApprovalDelegation, error) {
+	var delegations []models.ApprovalDelegation
+	if err := r.db.Where("delegator_user_id = ?", delegatorUserID).Order("start_date DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// FindByDelegate mengambil delegasi yang diterima oleh dosen
+func (r *delegationRepository) FindByDelegate(delegateUserID uint) ([]models.ApprovalDelegation, error) {
+	var delegations []models.ApprovalDelegation
+	if err := r.db.Where("delegate_user_id = ?", delegateUserID).Order("start_date DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// FindActive mengambil delegasi yang berlaku pada tanggal tertentu dan belum dicabut
+func (r *delegationRepository) FindActive(day time.Time) ([]models.ApprovalDelegation, error) {
+	date := day.Format("2006-01-02")
+	var delegations []models.ApprovalDelegation
+	if err := r.db.Where("start_date <= ? AND end_date >= ? AND revoked_at IS NULL", date, date).Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// Create menyimpan delegasi baru, menolak delegasi yang beririsan dengan delegasi aktif lain
+func (r *delegationRepository) Create(delegation *models.ApprovalDelegation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.ApprovalDelegation{}).
+			Where("delegator_user_id = ? AND revoked_at IS NULL", delegation.DelegatorUserID).
+			Where("start_date <= ? AND end_date >= ?", delegation.EndDate, delegation.StartDate)
+		if delegation.Scope != models.AllApprovals {
+			query = query.Where("scope IN ?", []models.ApprovalScope{models.AllApprovals, delegation.Scope})
+		}
+
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDelegationOverlap
+		}
+		return tx.Create(delegation).Error
+	})
+}
+
+// Revoke mencabut delegasi sehingga persetujuan kembali ke dosen pemberi delegasi
+func (r *delegationRepository) Revoke(delegation *models.ApprovalDelegation) error {
+	now := time.Now()
+	delegation.RevokedAt = &now
+	return r.db.Model(delegation).Update("revoked_at", now).Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailBrandingRepository adalah interface untuk operasi repository branding email
+type EmailBrandingRepository interface {
+	FindAll() ([]models.EmailBranding, error)
+	FindByID(id uint) (*models.EmailBranding, error)
+	FindForFaculty(faculty string) (*models.EmailBranding, error)
+	Save(branding *models.EmailBranding) error
+	Delete(id uint) error
+}
+
+// emailBrandingRepository implementasi dari EmailBrandingRepository
+type emailBrandingRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailBrandingRepository membuat instance baru dari EmailBrandingRepository
+func NewEmailBrandingRepository(db *gorm.DB) EmailBrandingRepository {
+	return &emailBrandingRepository{
+		db: db,
+	}
+}
+
+// FindAll mengambil semua branding email
+func (r *emailBrandingRepository) FindAll() ([]models.EmailBranding, error) {
+	var brandings []models.EmailBranding
+	err := r.db.Order("faculty ASC").Find(&brandings).Error
+	return brandings, err
+}
+
+// FindByID mencari branding email berdasarkan ID
+func (r *emailBrandingRepository) FindByID(id uint) (*models.EmailBranding, error) {
+	var branding models.EmailBranding
+	if err := r.db.Where("id = ?", id).First(&branding).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &branding, nil
+}
+
+// FindForFaculty mengambil branding fakultas tersebut, atau branding umum bila fakultas tidak
+// memiliki branding sendiri
+func (r *emailBrandingRepository) FindForFaculty(faculty string) (*models.EmailBranding, error) {
+	var branding models.EmailBranding
+	err := r.db.Where("faculty IN ?", []string{faculty, ""}).
+		Order("faculty DESC").First(&branding).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &branding, nil
+}
+
+// Save menyimpan branding email, menggantikan branding dengan fakultas yang sama
+func (r *emailBrandingRepository) Save(branding *models.EmailBranding) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "faculty"}},
+		DoUpdates: clause.AssignmentColumns([]string{"logo_url", "accent_color", "footer_text", "support_contact", "updated_by", "updated_at"}),
+	}).Create(branding).Error
+}
+
+// Delete menghapus branding email
+func (r *emailBrandingRepository) Delete(id uint) error {
+	return r.db.Delete(&models.EmailBranding{}, id).Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EmailQueueRepository adalah interface untuk operasi repository antrean email
+type EmailQueueRepository interface {
+	Enqueue(email *models.QueuedEmail) error
+	ClaimDue(now, staleBefore time.Time, limit int) ([]models.QueuedEmail, error)
+	Update(email *models.QueuedEmail) error
+	FindFailed(limit int) ([]models.QueuedEmail, error)
+	Requeue(id uint, now time.Time) (bool, error)
+	CountByStatus(statuses ...models.QueuedEmailStatus) (map[models.QueuedEmailStatus]int64, error)
+}
+
+// emailQueueRepository implementasi dari EmailQueueRepository
+type emailQueueRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailQueueRepository membuat instance baru dari EmailQueueRepository
+func NewEmailQueueRepository(db *gorm.DB) EmailQueueRepository {
+	return &emailQueueRepository{
+		db: db,
+	}
+}
+
+// Enqueue menyimpan email baru ke antrean
+func (r *emailQueueRepository) Enqueue(email *models.QueuedEmail) error {
+	return r.db.Create(email).Error
+}
+
+// ClaimDue menandai email yang sudah jatuh tempo sebagai sedang dikirim dan mengembalikannya.
+// Email yang macet di status sending sejak sebelum staleBefore (mis. karena proses berhenti)
+// ikut diambil kembali. SKIP LOCKED mencegah dua instance mengirim email yang sama.
+func (r *emailQueueRepository) ClaimDue(now, staleBefore time.Time, limit int) ([]models.QueuedEmail, error) {
+	var emails []models.QueuedEmail
+	err := r.db.Raw(`
+		UPDATE email_queue SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM email_queue
+			WHERE (status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.EmailSending, now,
+		models.EmailPending, now, models.EmailSending, staleBefore,
+		limit,
+	).Scan(&emails).Error
+	return emails, err
+}
+
+// Update menyimpan hasil percobaan pengiriman
+func (r *emailQueueRepository) Update(email *models.QueuedEmail) error {
+	return r.db.Save(email).Error
+}
+
+// FindFailed mengambil email gagal terbaru
+func (r *emailQueueRepository) FindFailed(limit int) ([]models.QueuedEmail, error) {
+	var emails []models.QueuedEmail
+	err := r.db.Where("status = ?", models.EmailFailed).Order("updated_at DESC").Limit(limit).Find(&emails).Error
+	return emails, err
+}
+
+// Requeue mengembalikan email gagal ke antrean dengan jatah percobaan baru.
+// Mengembalikan false jika email tidak ditemukan atau tidak berstatus gagal.
+func (r *emailQueueRepository) Requeue(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.QueuedEmail{}).
+		Where("id = ? AND status = ?", id, models.EmailFailed).
+		Updates(map[string]interface{}{
+			"status":          models.EmailPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountByStatus menghitung email dalam antrean per status
+func (r *emailQueueRepository) CountByStatus(statuses ...models.QueuedEmailStatus) (map[models.QueuedEmailStatus]int64, error) {
+	var rows []struct {
+		Status models.QueuedEmailStatus
+		Count  int64
+	}
+	err := r.db.Model(&models.QueuedEmail{}).
+		Select("status, COUNT(*) AS count").
+		Where("status IN ?", statuses).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.QueuedEmailStatus]int64, len(statuses))
+	for _, status := range statuses {
+		counts[status] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailTrackingRepository adalah interface untuk operasi repository pelacakan email
+type EmailTrackingRepository interface {
+	Create(tracking *models.EmailTracking) error
+	MarkOpened(token string) error
+	MarkClicked(token string) error
+	Engagement(from, to time.Time) ([]models.EmailEngagement, error)
+	IsOptedOut(userID uint) (bool, error)
+	SetOptOut(userID uint, optOut bool) error
+}
+
+// emailTrackingRepository implementasi dari EmailTrackingRepository
+type emailTrackingRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailTrackingRepository membuat instance baru dari EmailTrackingRepository
+func NewEmailTrackingRepository(db *gorm.DB) EmailTrackingRepository {
+	return &emailTrackingRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan token pelacakan email baru
+func (r *emailTrackingRepository) Create(tracking *models.EmailTracking) error {
+	return r.db.Create(tracking).Error
+}
+
+// MarkOpened mencatat waktu email pertama kali dibuka
+func (r *emailTrackingRepository) MarkOpened(token string) error {
+	return r.db.Model(&models.EmailTracking{}).
+		Where("token = ? AND opened_at IS NULL", token).
+		Update("opened_at", time.Now()).Error
+}
+
+// MarkClicked mencatat waktu tautan pertama kali diklik; klik juga berarti email sudah dibuka
+// walaupun gambar pelacak diblokir
+func (r *emailTrackingRepository) MarkClicked(token string) error {
+	now := time.Now()
+	return r.db.Model(&models.EmailTracking{}).
+		Where("token = ? AND clicked_at IS NULL", token).
+		Updates(map[string]interface{}{
+			"clicked_at": now,
+			"opened_at":  gorm.Expr("COALESCE(opened_at, ?)", now),
+		}).Error
+}
+
+// Engagement menghitung email terlacak yang dikirim, dibuka, dan diklik per template per hari
+// antara from dan to
+func (r *emailTrackingRepository) Engagement(from, to time.Time) ([]models.EmailEngagement, error) {
+	rows := []models.EmailEngagement{}
+	err := r.db.Model(&models.EmailTracking{}).
+		Select(`template, TO_CHAR(sent_at, 'YYYY-MM-DD') AS day, COUNT(*) AS sent,
+			COUNT(opened_at) AS opened, COUNT(clicked_at) AS clicked`).
+		Where("sent_at >= ? AND sent_at < ?", from, to).
+		Group("template, day").
+		Order("day ASC, template ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// IsOptedOut memeriksa apakah pengguna menolak pelacakan email
+func (r *emailTrackingRepository) IsOptedOut(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.EmailTrackingOptOut{}).Where("user_id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// SetOptOut menyimpan atau menghapus penolakan pelacakan email seorang pengguna
+func (r *emailTrackingRepository) SetOptOut(userID uint, optOut bool) error {
+	if !optOut {
+		return r.db.Where("user_id = ?", userID).Delete(&models.EmailTrackingOptOut{}).Error
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.EmailTrackingOptOut{UserID: userID}).Error
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnrollmentRepository adalah interface untuk operasi repository pendaftaran mata kuliah
+type EnrollmentRepository interface {
+	FindByID(id uint) (*models.Enrollment, error)
+	FindByNim(nim, semester string) ([]models.Enrollment, error)
+	FindByOffering(offering models.CourseOffering) ([]models.Enrollment, error)
+	IsEnrolled(nim, courseCode, className, semester string) (bool, error)
+	BulkEnroll(offering models.CourseOffering, nims []string, enrolledBy uint) (int64, error)
+	Delete(id uint) error
+}
+
+// enrollmentRepository implementasi dari EnrollmentRepository
+type enrollmentRepository struct {
+	db *gorm.DB
+}
+
+// NewEnrollmentRepository membuat instance baru dari EnrollmentRepository
+func NewEnrollmentRepository(db *gorm.DB) EnrollmentRepository {
+	return &enrollmentRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari pendaftaran berdasarkan ID
+func (r *enrollmentRepository) FindByID(id uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	if err := r.db.Where("id = ?", id).First(&enrollment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// FindByNim mengambil mata kuliah yang diambil mahasiswa, opsional untuk satu semester
+func (r *enrollmentRepository) FindByNim(nim, semester string) ([]models.Enrollment, error) {
+	query := r.db.Where("nim = ?", nim)
+	if semester != "" {
+		query = query.Where("semester = ?", semester)
+	}
+
+	var enrollments []models.Enrollment
+	if err := query.Order("semester DESC, course_code").Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// FindByOffering mengambil mahasiswa yang terdaftar pada sebuah mata kuliah
+func (r *enrollmentRepository) FindByOffering(offering models.CourseOffering) ([]models.Enrollment, error) {
+	query := r.db.Where("course_code = ?", offering.CourseCode)
+	if offering.Semester != "" {
+		query = query.Where("semester = ?", offering.Semester)
+	}
+	if offering.ClassName != "" {
+		query = query.Where("class_name = ?", offering.ClassName)
+	}
+
+	var enrollments []models.Enrollment
+	if err := query.Order("nim").Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// IsEnrolled memeriksa apakah mahasiswa terdaftar pada mata kuliah. Kelas dan semester
+// hanya dicocokkan bila diisi.
+func (r *enrollmentRepository) IsEnrolled(nim, courseCode, className, semester string) (bool, error) {
+	query := r.db.Model(&models.Enrollment{}).Where("nim = ? AND course_code = ?", nim, courseCode)
+	if className != "" {
+		query = query.Where("(class_name = ? OR class_name = '')", className)
+	}
+	if semester != "" {
+		query = query.Where("semester = ?", semester)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// BulkEnroll mendaftarkan banyak mahasiswa sekaligus; mahasiswa yang sudah terdaftar dilewati.
+// Mengembalikan jumlah pendaftaran baru.
+func (r *enrollmentRepository) BulkEnroll(offering models.CourseOffering, nims []string, enrolledBy uint) (int64, error) {
+	if len(nims) == 0 {
+		return 0, nil
+	}
+
+	enrollments := make([]models.Enrollment, 0, len(nims))
+	for _, nim := range nims {
+		enrollments = append(enrollments, models.Enrollment{
+			Nim:        nim,
+			CourseCode: offering.CourseCode,
+			CourseName: offering.CourseName,
+			ClassName:  offering.ClassName,
+			Semester:   offering.Semester,
+			EnrolledBy: enrolledBy,
+		})
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&enrollments, 500)
+	return result.RowsAffected, result.Error
+}
+
+// Delete menghapus pendaftaran
+func (r *enrollmentRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Enrollment{}, id).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExamAttendanceRepository adalah interface untuk operasi repository kehadiran ujian
+type ExamAttendanceRepository interface {
+	Upsert(attendance *models.ExamAttendance) error
+	FindByCourse(courseCode, examType string) ([]models.ExamAttendance, error)
+}
+
+// examAttendanceRepository implementasi dari ExamAttendanceRepository
+type examAttendanceRepository struct {
+	db *gorm.DB
+}
+
+// NewExamAttendanceRepository membuat instance baru dari ExamAttendanceRepository
+func NewExamAttendanceRepository(db *gorm.DB) ExamAttendanceRepository {
+	return &examAttendanceRepository{
+		db: db,
+	}
+}
+
+// Upsert menyimpan kehadiran ujian, memperbarui data yang sudah ada untuk mahasiswa yang sama
+func (r *examAttendanceRepository) Upsert(attendance *models.ExamAttendance) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_code"}, {Name: "exam_type"}, {Name: "nim"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "external_ref", "api_key_id", "marked_at", "updated_at"}),
+	}).Create(attendance).Error
+}
+
+// FindByCourse mengambil kehadiran ujian untuk satu mata kuliah
+func (r *examAttendanceRepository) FindByCourse(courseCode, examType string) ([]models.ExamAttendance, error) {
+	var attendances []models.ExamAttendance
+	query := r.db.Where("course_code = ?", courseCode)
+	if examType != "" {
+		query = query.Where("exam_type = ?", examType)
+	}
+	if err := query.Order("nim ASC").Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExamEligibilityRepository adalah interface untuk operasi repository kebijakan syarat ujian
+type ExamEligibilityRepository interface {
+	FindByID(id uint) (*models.ExamEligibilityPolicy, error)
+	FindAll() ([]models.ExamEligibilityPolicy, error)
+	FindForCourse(courseCode string) (*models.ExamEligibilityPolicy, error)
+	Save(policy *models.ExamEligibilityPolicy) error
+	Delete(id uint) error
+}
+
+// examEligibilityRepository implementasi dari ExamEligibilityRepository
+type examEligibilityRepository struct {
+	db *gorm.DB
+}
+
+// NewExamEligibilityRepository membuat instance baru dari ExamEligibilityRepository
+func NewExamEligibilityRepository(db *gorm.DB) ExamEligibilityRepository {
+	return &examEligibilityRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari kebijakan syarat ujian berdasarkan ID
+func (r *examEligibilityRepository) FindByID(id uint) (*models.ExamEligibilityPolicy, error) {
+	var policy models.ExamEligibilityPolicy
+	if err := r.db.Where("id = ?", id).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindAll mengambil semua kebijakan syarat ujian, kebijakan default lebih dulu
+func (r *examEligibilityRepository) FindAll() ([]models.ExamEligibilityPolicy, error) {
+	var policies []models.ExamEligibilityPolicy
+	err := r.db.Order("course_code ASC").Find(&policies).Error
+	return policies, err
+}
+
+// FindForCourse mencari kebijakan mata kuliah, atau kebijakan default jika mata kuliah
+// tidak memiliki kebijakan sendiri
+func (r *examEligibilityRepository) FindForCourse(courseCode string) (*models.ExamEligibilityPolicy, error) {
+	var policy models.ExamEligibilityPolicy
+	if err := r.db.Where("course_code IN (?, '')", courseCode).Order("course_code DESC").First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Save menyimpan kebijakan syarat ujian, menggantikan kebijakan mata kuliah yang sama
+func (r *examEligibilityRepository) Save(policy *models.ExamEligibilityPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_percent", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// Delete menghapus kebijakan syarat ujian
+func (r *examEligibilityRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ExamEligibilityPolicy{}, id).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FaceRepository adalah interface untuk operasi repository data wajah mahasiswa
+type FaceRepository interface {
+	FindByStudent(studentUserID uint) (*models.FaceData, error)
+	Save(face *models.FaceData) error
+	Delete(studentUserID uint) error
+}
+
+// faceRepository implementasi dari FaceRepository
+type faceRepository struct {
+	db *gorm.DB
+}
+
+// NewFaceRepository membuat instance baru dari FaceRepository
+func NewFaceRepository(db *gorm.DB) FaceRepository {
+	return &faceRepository{
+		db: db,
+	}
+}
+
+// FindByStudent mencari data wajah milik mahasiswa
+func (r *faceRepository) FindByStudent(studentUserID uint) (*models.FaceData, error) {
+	var face models.FaceData
+	if err := r.db.Where("student_user_id = ?", studentUserID).First(&face).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &face, nil
+}
+
+// Save menyimpan data wajah, menggantikan data yang sudah terdaftar sebelumnya
+func (r *faceRepository) Save(face *models.FaceData) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"nim", "embedding", "dimension", "model", "updated_at"}),
+	}).Create(face).Error
+}
+
+// Delete menghapus data wajah mahasiswa secara permanen
+func (r *faceRepository) Delete(studentUserID uint) error {
+	return r.db.Where("student_user_id = ?", studentUserID).Delete(&models.FaceData{}).Error
+}
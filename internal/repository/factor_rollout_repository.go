@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FactorRolloutRepository adalah interface untuk operasi repository rollout faktor check-in
+type FactorRolloutRepository interface {
+	FindAll() ([]models.FactorRollout, error)
+	FindByCourse(courseCode string) ([]models.FactorRollout, error)
+	FindByID(id uint) (*models.FactorRollout, error)
+	Save(rollout *models.FactorRollout) error
+	Delete(id uint) error
+	CreateShadowResults(results []models.ShadowFactorResult) error
+	ShadowReport(from, to time.Time, courseCode string) ([]models.ShadowFactorReport, error)
+}
+
+// factorRolloutRepository implementasi dari FactorRolloutRepository
+type factorRolloutRepository struct {
+	db *gorm.DB
+}
+
+// NewFactorRolloutRepository membuat instance baru dari FactorRolloutRepository
+func NewFactorRolloutRepository(db *gorm.DB) FactorRolloutRepository {
+	return &factorRolloutRepository{
+		db: db,
+	}
+}
+
+// FindAll mengambil semua rollout faktor, diurutkan per mata kuliah
+func (r *factorRolloutRepository) FindAll() ([]models.FactorRollout, error) {
+	var rollouts []models.FactorRollout
+	err := r.db.Order("course_code ASC, factor ASC").Find(&rollouts).Error
+	return rollouts, err
+}
+
+// FindByCourse mengambil rollout faktor sebuah mata kuliah
+func (r *factorRolloutRepository) FindByCourse(courseCode string) ([]models.FactorRollout, error) {
+	var rollouts []models.FactorRollout
+	err := r.db.Where("course_code = ?", courseCode).Find(&rollouts).Error
+	return rollouts, err
+}
+
+// FindByID mencari rollout faktor berdasarkan ID
+func (r *factorRolloutRepository) FindByID(id uint) (*models.FactorRollout, error) {
+	var rollout models.FactorRollout
+	err := r.db.First(&rollout, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+// Save membuat rollout faktor atau mengganti mode rollout yang sudah ada untuk mata kuliah
+// dan faktor yang sama
+func (r *factorRolloutRepository) Save(rollout *models.FactorRollout) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_code"}, {Name: "factor"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode", "updated_by", "updated_at"}),
+	}).Create(rollout).Error
+}
+
+// Delete menghapus rollout faktor sehingga mata kuliah kembali mengikuti feature flag
+func (r *factorRolloutRepository) Delete(id uint) error {
+	return r.db.Delete(&models.FactorRollout{}, id).Error
+}
+
+// CreateShadowResults menyimpan hasil faktor mode shadow dari sebuah percobaan check-in
+func (r *factorRolloutRepository) CreateShadowResults(results []models.ShadowFactorResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	return r.db.Create(&results).Error
+}
+
+// ShadowReport menghitung hasil faktor mode shadow per mata kuliah dan faktor antara from
+// dan to, opsional untuk satu mata kuliah
+func (r *factorRolloutRepository) ShadowReport(from, to time.Time, courseCode string) ([]models.ShadowFactorReport, error) {
+	query := r.db.Model(&models.ShadowFactorResult{}).
+		Select(`course_code, factor, COUNT(*) AS attempts,
+			COUNT(*) FILTER (WHERE outcome = ?) AS passed,
+			COUNT(*) FILTER (WHERE outcome = ?) AS failed,
+			COUNT(*) FILTER (WHERE outcome = ?) AS missing,
+			COUNT(*) FILTER (WHERE outcome = ?) AS unavailable,
+			COUNT(*) FILTER (WHERE accepted AND outcome <> ?) AS accepted_would_fail`,
+			models.FactorPassed, models.FactorFailed, models.FactorMissing, models.FactorUnavailable, models.FactorPassed).
+		Where("created_at >= ? AND created_at < ?", from, to)
+	if courseCode != "" {
+		query = query.Where("course_code = ?", courseCode)
+	}
+
+	reports := []models.ShadowFactorReport{}
+	if err := query.Group("course_code, factor").Order("course_code ASC, factor ASC").Scan(&reports).Error; err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		if reports[i].Attempts > 0 {
+			reports[i].WouldFailRate = float64(reports[i].Attempts-reports[i].Passed) / float64(reports[i].Attempts)
+		}
+	}
+	return reports, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrAlreadyRegistered dikembalikan ketika email sudah terdaftar pada acara yang sama
+	ErrAlreadyRegistered = errors.New("email is already registered for this event")
+	// ErrEventFull dikembalikan ketika kapasitas acara sudah penuh
+	ErrEventFull = errors.New("event has reached its capacity")
+)
+
+// GuestEventRepository adalah interface untuk operasi repository acara tamu
+type GuestEventRepository interface {
+	FindByID(id uint) (*models.GuestEvent, error)
+	FindByOrganizer(organizerUserID uint) ([]models.GuestEvent, error)
+	Create(event *models.GuestEvent) error
+	Register(registration *models.GuestRegistration, capacity int) error
+	FindRegistrationByCode(eventID uint, code string) (*models.GuestRegistration, error)
+	UpdateRegistration(registration *models.GuestRegistration) error
+	FindRegistrations(eventID uint) ([]models.GuestRegistration, error)
+}
+
+// guestEventRepository implementasi dari GuestEventRepository
+type guestEventRepository struct {
+	db *gorm.DB
+}
+
+// NewGuestEventRepository membuat instance baru dari GuestEventRepository
+func NewGuestEventRepository(db *gorm.DB) GuestEventRepository {
+	return &guestEventRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari acara berdasarkan ID
+func (r *guestEventRepository) FindByID(id uint) (*models.GuestEvent, error) {
+	var event models.GuestEvent
+	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindByOrganizer mengambil acara milik penyelenggara
+func (r *guestEventRepository) FindByOrganizer(organizerUserID uint) ([]models.GuestEvent, error) {
+	var events []models.GuestEvent
+	if err := r.db.Where("organizer_user_id = ?", organizerUserID).Order("starts_at DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Create menyimpan acara baru
+func (r *guestEventRepository) Create(event *models.GuestEvent) error {
+	return r.db.Create(event).Error
+}
+
+// Register mendaftarkan tamu dalam transaksi agar kapasitas tidak terlampaui
+func (r *guestEventRepository) Register(registration *models.GuestRegistration, capacity int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the event row so concurrent registrations are counted one at a time
+		var event models.GuestEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, registration.EventID).Error; err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.GuestRegistration{}).
+			Where("event_id = ? AND email = ?", registration.EventID, registration.Email).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrAlreadyRegistered
+		}
+
+		if capacity > 0 {
+			var total int64
+			if err := tx.Model(&models.GuestRegistration{}).
+				Where("event_id = ?", registration.EventID).
+				Count(&total).Error; err != nil {
+				return err
+			}
+			if int(total) >= capacity {
+				return ErrEventFull
+			}
+		}
+
+		return tx.Create(registration).Error
+	})
+}
+
+// FindRegistrationByCode mencari pendaftaran tamu berdasarkan kode QR
+func (r *guestEventRepository) FindRegistrationByCode(eventID uint, code string) (*models.GuestRegistration, error) {
+	var registration models.GuestRegistration
+	if err := r.db.Where("event_id = ? AND registration_code = ?", eventID, code).First(&registration).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// UpdateRegistration memperbarui data pendaftaran tamu
+func (r *guestEventRepository) UpdateRegistration(registration *models.GuestRegistration) error {
+	return r.db.Save(registration).Error
+}
+
+// FindRegistrations mengambil daftar tamu suatu acara
+func (r *guestEventRepository) FindRegistrations(eventID uint) ([]models.GuestRegistration, error) {
+	var registrations []models.GuestRegistration
+	if err := r.db.Where("event_id = ?", eventID).Order("name ASC").Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IntegrationAlertRepository adalah interface untuk operasi repository alert integrasi
+type IntegrationAlertRepository interface {
+	Open(alert *models.IntegrationAlert) (bool, error)
+	FindOpen(integration string, condition models.IntegrationAlertCondition) (*models.IntegrationAlert, error)
+	MarkNotified(alert *models.IntegrationAlert, notifiedAt time.Time) (bool, error)
+	Resolve(integration string, condition models.IntegrationAlertCondition, resolvedAt time.Time) (*models.IntegrationAlert, error)
+	FindRecent(integration string, limit int) ([]models.IntegrationAlert, error)
+}
+
+// integrationAlertRepository implementasi dari IntegrationAlertRepository
+type integrationAlertRepository struct {
+	db *gorm.DB
+}
+
+// NewIntegrationAlertRepository membuat instance baru dari IntegrationAlertRepository
+func NewIntegrationAlertRepository(db *gorm.DB) IntegrationAlertRepository {
+	return &integrationAlertRepository{
+		db: db,
+	}
+}
+
+// Open menyimpan alert baru kecuali alert dengan kondisi yang sama masih terbuka, dan
+// mengembalikan apakah alert tersebut yang disimpan
+func (r *integrationAlertRepository) Open(alert *models.IntegrationAlert) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "integration"}, {Name: "condition"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "resolved_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindOpen mencari alert yang masih terbuka untuk sebuah kondisi
+func (r *integrationAlertRepository) FindOpen(integration string, condition models.IntegrationAlertCondition) (*models.IntegrationAlert, error) {
+	var alert models.IntegrationAlert
+	err := r.db.Where("integration = ? AND condition = ? AND resolved_at IS NULL", integration, condition).First(&alert).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// MarkNotified mencatat pengingat alert, kecuali instance lain sudah mengirimnya lebih dulu
+func (r *integrationAlertRepository) MarkNotified(alert *models.IntegrationAlert, notifiedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.IntegrationAlert{}).
+		Where("id = ? AND notified_at = ? AND resolved_at IS NULL", alert.ID, alert.NotifiedAt).
+		Updates(map[string]interface{}{
+			"notified_at":   notifiedAt,
+			"notifications": gorm.Expr("notifications + 1"),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	alert.NotifiedAt = notifiedAt
+	alert.Notifications++
+	return true, nil
+}
+
+// Resolve menutup alert yang masih terbuka untuk sebuah kondisi dan mengembalikannya, atau nil
+// bila tidak ada atau instance lain sudah menutupnya
+func (r *integrationAlertRepository) Resolve(integration string, condition models.IntegrationAlertCondition, resolvedAt time.Time) (*models.IntegrationAlert, error) {
+	var resolved *models.IntegrationAlert
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var alert models.IntegrationAlert
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("integration = ? AND condition = ? AND resolved_at IS NULL", integration, condition).
+			First(&alert).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		alert.ResolvedAt = &resolvedAt
+		if err := tx.Model(&alert).Update("resolved_at", resolvedAt).Error; err != nil {
+			return err
+		}
+		resolved = &alert
+		return nil
+	})
+	return resolved, err
+}
+
+// FindRecent mengambil alert terbaru sebuah integrasi
+func (r *integrationAlertRepository) FindRecent(integration string, limit int) ([]models.IntegrationAlert, error) {
+	var alerts []models.IntegrationAlert
+	err := r.db.Where("integration = ?", integration).Order("started_at DESC").Limit(limit).Find(&alerts).Error
+	return alerts, err
+}
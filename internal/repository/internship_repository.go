@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrCheckInExists dikembalikan ketika mahasiswa sudah melakukan check-in pada hari yang sama
+var ErrCheckInExists = errors.New("check-in for this day already exists")
+
+// InternshipRepository adalah interface untuk operasi repository kerja praktek
+type InternshipRepository interface {
+	FindByID(id uint) (*models.Internship, error)
+	FindByStudent(studentUserID uint) ([]models.Internship, error)
+	FindActive(date string) ([]models.Internship, error)
+	Create(internship *models.Internship) error
+	CreateCheckIn(checkIn *models.InternshipCheckIn) error
+	FindCheckIns(internshipID uint, from, to string) ([]models.InternshipCheckIn, error)
+	FindCheckInByToken(token string) (*models.InternshipCheckIn, error)
+	UpdateCheckIn(checkIn *models.InternshipCheckIn) error
+}
+
+// internshipRepository implementasi dari InternshipRepository
+type internshipRepository struct {
+	db *gorm.DB
+}
+
+// NewInternshipRepository membuat instance baru dari InternshipRepository
+func NewInternshipRepository(db *gorm.DB) InternshipRepository {
+	return &internshipRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari kerja praktek berdasarkan ID
+func (r *internshipRepository) FindByID(id uint) (*models.Internship, error) {
+	var internship models.Internship
+	if err := r.db.Where("id = ?", id).First(&internship).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &internship, nil
+}
+
+// FindByStudent mengambil semua kerja praktek milik mahasiswa
+func (r *internshipRepository) FindByStudent(studentUserID uint) ([]models.Internship, error) {
+	var internships []models.Internship
+	if err := r.db.Where("student_user_id = ?", studentUserID).Order("start_date DESC").Find(&internships).Error; err != nil {
+		return nil, err
+	}
+	return internships, nil
+}
+
+// FindActive mengambil kerja praktek yang berlangsung pada tanggal tertentu
+func (r *internshipRepository) FindActive(date string) ([]models.Internship, error) {
+	var internships []models.Internship
+	if err := r.db.Where("start_date <= ? AND end_date >= ?", date, date).Find(&internships).Error; err != nil {
+		return nil, err
+	}
+	return internships, nil
+}
+
+// Create menyimpan kerja praktek baru
+func (r *internshipRepository) Create(internship *models.Internship) error {
+	return r.db.Create(internship).Error
+}
+
+// CreateCheckIn menyimpan check-in harian, menolak check-in kedua pada hari yang sama
+func (r *internshipRepository) CreateCheckIn(checkIn *models.InternshipCheckIn) error {
+	var count int64
+	if err := r.db.Model(&models.InternshipCheckIn{}).
+		Where("internship_id = ? AND date = ?", checkIn.InternshipID, checkIn.Date).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCheckInExists
+	}
+	return r.db.Create(checkIn).Error
+}
+
+// FindCheckIns mengambil check-in dalam rentang tanggal (inklusif)
+func (r *internshipRepository) FindCheckIns(internshipID uint, from, to string) ([]models.InternshipCheckIn, error) {
+	var checkIns []models.InternshipCheckIn
+	query := r.db.Where("internship_id = ?", internshipID)
+	if from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("date <= ?", to)
+	}
+	if err := query.Order("date ASC").Find(&checkIns).Error; err != nil {
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+// FindCheckInByToken mencari check-in berdasarkan token konfirmasi mentor
+func (r *internshipRepository) FindCheckInByToken(token string) (*models.InternshipCheckIn, error) {
+	var checkIn models.InternshipCheckIn
+	if err := r.db.Where("confirmation_token = ?", token).First(&checkIn).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &checkIn, nil
+}
+
+// UpdateCheckIn memperbarui data check-in
+func (r *internshipRepository) UpdateCheckIn(checkIn *models.InternshipCheckIn) error {
+	return r.db.Save(checkIn).Error
+}
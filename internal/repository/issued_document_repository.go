@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// IssuedDocumentRepository adalah interface untuk operasi repository dokumen yang diterbitkan
+type IssuedDocumentRepository interface {
+	Create(document *models.IssuedDocument) error
+	FindByCode(code string) (*models.IssuedDocument, error)
+}
+
+// issuedDocumentRepository implementasi dari IssuedDocumentRepository
+type issuedDocumentRepository struct {
+	db *gorm.DB
+}
+
+// NewIssuedDocumentRepository membuat instance baru dari IssuedDocumentRepository
+func NewIssuedDocumentRepository(db *gorm.DB) IssuedDocumentRepository {
+	return &issuedDocumentRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan dokumen yang baru diterbitkan
+func (r *issuedDocumentRepository) Create(document *models.IssuedDocument) error {
+	return r.db.Create(document).Error
+}
+
+// FindByCode mencari dokumen berdasarkan kode verifikasinya
+func (r *issuedDocumentRepository) FindByCode(code string) (*models.IssuedDocument, error) {
+	var document models.IssuedDocument
+	if err := r.db.Where("code = ?", code).First(&document).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &document, nil
+}
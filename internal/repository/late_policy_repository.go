@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LatePolicyRepository adalah interface untuk operasi repository kebijakan keterlambatan
+type LatePolicyRepository interface {
+	FindByID(id uint) (*models.LatePolicy, error)
+	FindAll() ([]models.LatePolicy, error)
+	FindForCourse(courseCode string) (*models.LatePolicy, error)
+	Create(policy *models.LatePolicy) error
+	Update(policy *models.LatePolicy) error
+	Delete(id uint) error
+}
+
+// latePolicyRepository implementasi dari LatePolicyRepository
+type latePolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewLatePolicyRepository membuat instance baru dari LatePolicyRepository
+func NewLatePolicyRepository(db *gorm.DB) LatePolicyRepository {
+	return &latePolicyRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari kebijakan keterlambatan berdasarkan ID
+func (r *latePolicyRepository) FindByID(id uint) (*models.LatePolicy, error) {
+	var policy models.LatePolicy
+	if err := r.db.Where("id = ?", id).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindAll mengambil semua kebijakan keterlambatan, kebijakan default lebih dulu
+func (r *latePolicyRepository) FindAll() ([]models.LatePolicy, error) {
+	var policies []models.LatePolicy
+	err := r.db.Order("course_code ASC").Find(&policies).Error
+	return policies, err
+}
+
+// FindForCourse mencari kebijakan mata kuliah, atau kebijakan default jika mata kuliah
+// tidak memiliki kebijakan sendiri
+func (r *latePolicyRepository) FindForCourse(courseCode string) (*models.LatePolicy, error) {
+	var policy models.LatePolicy
+	if err := r.db.Where("course_code IN (?, '')", courseCode).Order("course_code DESC").First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Create menyimpan kebijakan keterlambatan baru
+func (r *latePolicyRepository) Create(policy *models.LatePolicy) error {
+	return r.db.Create(policy).Error
+}
+
+// Update menyimpan perubahan kebijakan keterlambatan
+func (r *latePolicyRepository) Update(policy *models.LatePolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// Delete menghapus kebijakan keterlambatan
+func (r *latePolicyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.LatePolicy{}, id).Error
+}
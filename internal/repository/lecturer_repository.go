@@ -6,6 +6,7 @@ import (
 	"delpresence-api/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // LecturerRepository adalah interface untuk operasi repository dosen
@@ -13,8 +14,11 @@ type LecturerRepository interface {
 	FindByID(id uint) (*models.Lecturer, error)
 	FindByCampusUserID(campusUserID uint) (*models.Lecturer, error)
 	FindByUserID(userID uint) (*models.Lecturer, error)
+	FindExistingUserIDs(userIDs []uint) (map[uint]bool, error)
+	FindByUserIDs(userIDs []uint) ([]models.Lecturer, error)
 	Create(lecturer *models.Lecturer) error
 	Update(lecturer *models.Lecturer) error
+	UpsertFromCampus(lecturers []models.Lecturer) error
 	Delete(id uint) error
 }
 
@@ -66,6 +70,32 @@ func (r *lecturerRepository) FindByUserID(userID uint) (*models.Lecturer, error)
 	return &lecturer, nil
 }
 
+// FindExistingUserIDs mengambil user ID dari daftar yang sudah memiliki data dosen, termasuk
+// yang sudah dihapus
+func (r *lecturerRepository) FindExistingUserIDs(userIDs []uint) (map[uint]bool, error) {
+	existing := make(map[uint]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return existing, nil
+	}
+	var found []uint
+	err := r.db.Unscoped().Model(&models.Lecturer{}).Where("lecturer_user_id IN ?", userIDs).
+		Pluck("lecturer_user_id", &found).Error
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, err
+}
+
+// FindByUserIDs mengambil data dosen dari daftar user ID
+func (r *lecturerRepository) FindByUserIDs(userIDs []uint) ([]models.Lecturer, error) {
+	var lecturers []models.Lecturer
+	if len(userIDs) == 0 {
+		return lecturers, nil
+	}
+	err := r.db.Where("lecturer_user_id IN ?", userIDs).Find(&lecturers).Error
+	return lecturers, err
+}
+
 // Create membuat record dosen baru
 func (r *lecturerRepository) Create(lecturer *models.Lecturer) error {
 	return r.db.Create(lecturer).Error
@@ -76,6 +106,23 @@ func (r *lecturerRepository) Update(lecturer *models.Lecturer) error {
 	return r.db.Save(lecturer).Error
 }
 
+// UpsertFromCampus membuat atau memperbarui data dosen dari API kampus sekaligus. Kolom yang
+// hanya dapat diubah pengguna tidak ditimpa; field yang disinkronkan harus sudah digabungkan
+// dengan perubahan pengguna.
+func (r *lecturerRepository) UpsertFromCampus(lecturers []models.Lecturer) error {
+	if len(lecturers) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "lecturer_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"employee_id", "lecturer_id", "identity_number", "full_name", "email", "department_id",
+			"department", "academic_rank", "academic_rank_desc", "education_level", "lecturer_number",
+			"campus_user_id", "last_sync_at", "updated_at",
+		}),
+	}).Create(&lecturers).Error
+}
+
 // Delete menghapus data dosen berdasarkan ID
 func (r *lecturerRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Lecturer{}, id).Error
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MahasiswaRepository adalah interface untuk operasi repository data mahasiswa lokal
+type MahasiswaRepository interface {
+	FindSnapshotByUserID(userID uint) (*models.MahasiswaSnapshot, error)
+	FindSnapshotByNIM(nim string) (*models.MahasiswaSnapshot, error)
+	SaveSnapshot(snapshot *models.MahasiswaSnapshot) error
+	FindKnownNIMs() ([]string, error)
+	FindKnownUserIDs() ([]uint, error)
+}
+
+// mahasiswaRepository implementasi dari MahasiswaRepository
+type mahasiswaRepository struct {
+	db *gorm.DB
+}
+
+// NewMahasiswaRepository membuat instance baru dari MahasiswaRepository
+func NewMahasiswaRepository(db *gorm.DB) MahasiswaRepository {
+	return &mahasiswaRepository{
+		db: db,
+	}
+}
+
+// FindSnapshotByUserID mencari snapshot mahasiswa berdasarkan campus user ID
+func (r *mahasiswaRepository) FindSnapshotByUserID(userID uint) (*models.MahasiswaSnapshot, error) {
+	var snapshot models.MahasiswaSnapshot
+	if err := r.db.Where("user_id = ?", userID).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// FindSnapshotByNIM mencari snapshot mahasiswa berdasarkan NIM
+func (r *mahasiswaRepository) FindSnapshotByNIM(nim string) (*models.MahasiswaSnapshot, error) {
+	var snapshot models.MahasiswaSnapshot
+	if err := r.db.Where("nim = ?", nim).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot menyimpan atau memperbarui snapshot mahasiswa
+func (r *mahasiswaRepository) SaveSnapshot(snapshot *models.MahasiswaSnapshot) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"nim", "basic_info", "details", "last_sync_at", "updated_at"}),
+	}).Create(snapshot).Error
+}
+
+// FindKnownNIMs mengambil semua NIM yang tercatat pada snapshot, enrollment, presensi, pengajuan
+// izin, dan bimbingan, yaitu data yang NIM-nya dapat muncul tersamar di respons atau stream event
+func (r *mahasiswaRepository) FindKnownNIMs() ([]string, error) {
+	var nims []string
+	err := r.db.Raw(`SELECT nim FROM mahasiswa_snapshots WHERE nim <> ''
+		UNION SELECT nim FROM enrollments
+		UNION SELECT nim FROM attendance_records
+		UNION SELECT nim FROM permission_requests
+		UNION SELECT nim FROM supervision_meetings`).
+		Scan(&nims).Error
+	return nims, err
+}
+
+// FindKnownUserIDs mengambil semua campus user ID mahasiswa yang tercatat pada snapshot, presensi,
+// pengajuan izin, bimbingan, dan booking jam konsultasi
+func (r *mahasiswaRepository) FindKnownUserIDs() ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Raw(`SELECT user_id FROM mahasiswa_snapshots
+		UNION SELECT student_user_id FROM attendance_records
+		UNION SELECT student_user_id FROM permission_requests
+		UNION SELECT student_user_id FROM supervision_meetings
+		UNION SELECT student_user_id FROM office_hour_bookings`).
+		Scan(&userIDs).Error
+	return userIDs, err
+}
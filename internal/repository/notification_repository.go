@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository adalah interface untuk operasi repository notifikasi
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	FindByUser(userID uint, unreadOnly bool) ([]models.Notification, error)
+	MarkRead(id, userID uint) (bool, error)
+}
+
+// notificationRepository implementasi dari NotificationRepository
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository membuat instance baru dari NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan notifikasi baru
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// FindByUser mengambil notifikasi milik user, terbaru terlebih dahulu
+func (r *notificationRepository) FindByUser(userID uint, unreadOnly bool) ([]models.Notification, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Limit(100).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkRead menandai notifikasi milik user sebagai sudah dibaca
+func (r *notificationRepository) MarkRead(id, userID uint) (bool, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
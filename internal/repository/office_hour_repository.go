@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrOfficeHourFull dikembalikan ketika kapasitas slot jam konsultasi sudah penuh
+	ErrOfficeHourFull = errors.New("office-hour slot has reached its capacity")
+	// ErrOfficeHourAlreadyBooked dikembalikan ketika mahasiswa sudah memesan slot yang sama
+	ErrOfficeHourAlreadyBooked = errors.New("office-hour slot is already booked by this student")
+	// ErrOfficeHourUnavailable dikembalikan ketika slot sudah dibatalkan atau sudah dimulai
+	ErrOfficeHourUnavailable = errors.New("office-hour slot is no longer available")
+	// ErrOfficeHourBookingClosed dikembalikan ketika pemesanan sudah dibatalkan
+	ErrOfficeHourBookingClosed = errors.New("office-hour booking is no longer active")
+)
+
+// OfficeHourConflictError dikembalikan ketika slot bentrok dengan jadwal mengajar, peminjaman
+// ruangan, atau slot lain milik dosen yang sama
+type OfficeHourConflictError struct {
+	Conflicts models.OfficeHourConflicts
+}
+
+// Error implements the error interface
+func (e *OfficeHourConflictError) Error() string {
+	return fmt.Sprintf("office-hour slot conflicts with %d schedule(s), %d booking(s) and %d slot(s)",
+		len(e.Conflicts.Schedules), len(e.Conflicts.Bookings), len(e.Conflicts.Slots))
+}
+
+// OfficeHourRepository adalah interface untuk operasi repository jam konsultasi dosen
+type OfficeHourRepository interface {
+	FindSlotByID(id uint) (*models.OfficeHourSlot, error)
+	FindSlotsByLecturer(lecturerUserID uint, from time.Time) ([]models.OfficeHourSlot, error)
+	FindOpenSlots(lecturerUserID uint, from time.Time) ([]models.OfficeHourSlot, error)
+	CreateSlots(slots []models.OfficeHourSlot) error
+	CancelSlot(slot *models.OfficeHourSlot) ([]models.OfficeHourBooking, error)
+	FindBookingByID(id uint) (*models.OfficeHourBooking, error)
+	FindBookingsBySlot(slotID uint) ([]models.OfficeHourBooking, error)
+	FindBookingsByStudent(studentUserID uint) ([]models.OfficeHourBooking, error)
+	Book(booking *models.OfficeHourBooking, now time.Time) error
+	CancelBooking(booking *models.OfficeHourBooking) error
+	FindUnremindedSlots(from, to time.Time) ([]models.OfficeHourSlot, error)
+	MarkReminded(slotID uint) error
+}
+
+// officeHourRepository implementasi dari OfficeHourRepository
+type officeHourRepository struct {
+	db *gorm.DB
+}
+
+// NewOfficeHourRepository membuat instance baru dari OfficeHourRepository
+func NewOfficeHourRepository(db *gorm.DB) OfficeHourRepository {
+	return &officeHourRepository{
+		db: db,
+	}
+}
+
+// FindSlotByID mencari slot jam konsultasi berdasarkan ID
+func (r *officeHourRepository) FindSlotByID(id uint) (*models.OfficeHourSlot, error) {
+	var slot models.OfficeHourSlot
+	if err := r.db.Where("id = ?", id).First(&slot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.countBookings([]*models.OfficeHourSlot{&slot}); err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// FindSlotsByLecturer mengambil slot seorang dosen mulai tanggal from, termasuk yang dibatalkan
+func (r *officeHourRepository) FindSlotsByLecturer(lecturerUserID uint, from time.Time) ([]models.OfficeHourSlot, error) {
+	var slots []models.OfficeHourSlot
+	if err := r.db.Where("lecturer_user_id = ? AND date >= ?", lecturerUserID, from.Format("2006-01-02")).
+		Order("date ASC, start_time ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, r.countBookings(slotPointers(slots))
+}
+
+// FindOpenSlots mengambil slot yang belum dibatalkan mulai tanggal from, difilter dosen jika diisi
+func (r *officeHourRepository) FindOpenSlots(lecturerUserID uint, from time.Time) ([]models.OfficeHourSlot, error) {
+	query := r.db.Where("cancelled_at IS NULL AND date >= ?", from.Format("2006-01-02"))
+	if lecturerUserID != 0 {
+		query = query.Where("lecturer_user_id = ?", lecturerUserID)
+	}
+
+	var slots []models.OfficeHourSlot
+	if err := query.Order("date ASC, start_time ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, r.countBookings(slotPointers(slots))
+}
+
+// CreateSlots menyimpan slot dalam satu transaksi, menolak semuanya bila salah satu bentrok
+func (r *officeHourRepository) CreateSlots(slots []models.OfficeHourSlot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range slots {
+			if err := checkOfficeHourConflicts(tx, &slots[i]); err != nil {
+				return err
+			}
+			if err := tx.Create(&slots[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CancelSlot membatalkan slot beserta pemesanannya dan mengembalikan pemesanan yang masih aktif
+func (r *officeHourRepository) CancelSlot(slot *models.OfficeHourSlot) ([]models.OfficeHourBooking, error) {
+	var bookings []models.OfficeHourBooking
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.OfficeHourSlot{}).Where("id = ? AND cancelled_at IS NULL", slot.ID).Update("cancelled_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrOfficeHourUnavailable
+		}
+
+		if err := tx.Where("slot_id = ? AND status = ?", slot.ID, models.OfficeHourBooked).Find(&bookings).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.OfficeHourBooking{}).Where("slot_id = ? AND status = ?", slot.ID, models.OfficeHourBooked).
+			Updates(map[string]interface{}{
+				"status":       models.OfficeHourCancelled,
+				"cancelled_at": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slot.CancelledAt = &now
+	return bookings, nil
+}
+
+// FindBookingByID mencari pemesanan jam konsultasi berdasarkan ID beserta slotnya
+func (r *officeHourRepository) FindBookingByID(id uint) (*models.OfficeHourBooking, error) {
+	var booking models.OfficeHourBooking
+	if err := r.db.Preload("Slot").Where("id = ?", id).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// FindBookingsBySlot mengambil pemesanan aktif pada sebuah slot
+func (r *officeHourRepository) FindBookingsBySlot(slotID uint) ([]models.OfficeHourBooking, error) {
+	var bookings []models.OfficeHourBooking
+	err := r.db.Where("slot_id = ? AND status = ?", slotID, models.OfficeHourBooked).Order("created_at ASC").Find(&bookings).Error
+	return bookings, err
+}
+
+// FindBookingsByStudent mengambil pemesanan seorang mahasiswa beserta slotnya, terbaru dahulu
+func (r *officeHourRepository) FindBookingsByStudent(studentUserID uint) ([]models.OfficeHourBooking, error) {
+	var bookings []models.OfficeHourBooking
+	err := r.db.Preload("Slot").Where("student_user_id = ?", studentUserID).Order("created_at DESC").Find(&bookings).Error
+	return bookings, err
+}
+
+// Book memesan tempat pada slot dalam transaksi agar kapasitas tidak terlampaui
+func (r *officeHourRepository) Book(booking *models.OfficeHourBooking, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the slot row so concurrent bookings are counted one at a time
+		var slot models.OfficeHourSlot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&slot, booking.SlotID).Error; err != nil {
+			return err
+		}
+		if slot.CancelledAt != nil || !slot.StartsAt().After(now) {
+			return ErrOfficeHourUnavailable
+		}
+
+		var existing int64
+		if err := tx.Model(&models.OfficeHourBooking{}).
+			Where("slot_id = ? AND student_user_id = ? AND status = ?", booking.SlotID, booking.StudentUserID, models.OfficeHourBooked).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrOfficeHourAlreadyBooked
+		}
+
+		var total int64
+		if err := tx.Model(&models.OfficeHourBooking{}).
+			Where("slot_id = ? AND status = ?", booking.SlotID, models.OfficeHourBooked).
+			Count(&total).Error; err != nil {
+			return err
+		}
+		if int(total) >= slot.Capacity {
+			return ErrOfficeHourFull
+		}
+
+		if err := tx.Create(booking).Error; err != nil {
+			return err
+		}
+		slot.Booked = int(total) + 1
+		booking.Slot = &slot
+		return nil
+	})
+}
+
+// CancelBooking membatalkan pemesanan yang masih aktif
+func (r *officeHourRepository) CancelBooking(booking *models.OfficeHourBooking) error {
+	now := time.Now()
+	res := r.db.Model(&models.OfficeHourBooking{}).Where("id = ? AND status = ?", booking.ID, models.OfficeHourBooked).
+		Updates(map[string]interface{}{
+			"status":       models.OfficeHourCancelled,
+			"cancelled_at": now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrOfficeHourBookingClosed
+	}
+
+	booking.Status = models.OfficeHourCancelled
+	booking.CancelledAt = &now
+	return nil
+}
+
+// FindUnremindedSlots mengambil slot aktif yang belum diingatkan pada tanggal from sampai to.
+// Jam mulai disaring oleh pemanggil karena tanggal dan jam disimpan terpisah.
+func (r *officeHourRepository) FindUnremindedSlots(from, to time.Time) ([]models.OfficeHourSlot, error) {
+	var slots []models.OfficeHourSlot
+	err := r.db.Where("cancelled_at IS NULL AND reminder_sent_at IS NULL AND date >= ? AND date <= ?",
+		from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC, start_time ASC").Find(&slots).Error
+	return slots, err
+}
+
+// MarkReminded mencatat bahwa pengingat slot sudah dikirim
+func (r *officeHourRepository) MarkReminded(slotID uint) error {
+	return r.db.Model(&models.OfficeHourSlot{}).Where("id = ?", slotID).Update("reminder_sent_at", time.Now()).Error
+}
+
+// countBookings mengisi jumlah pemesanan aktif setiap slot
+func (r *officeHourRepository) countBookings(slots []*models.OfficeHourSlot) error {
+	if len(slots) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(slots))
+	for i, slot := range slots {
+		ids[i] = slot.ID
+	}
+
+	var counts []struct {
+		SlotID uint
+		Total  int
+	}
+	if err := r.db.Model(&models.OfficeHourBooking{}).
+		Select("slot_id, COUNT(*) AS total").
+		Where("slot_id IN ? AND status = ?", ids, models.OfficeHourBooked).
+		Group("slot_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	booked := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		booked[count.SlotID] = count.Total
+	}
+	for _, slot := range slots {
+		slot.Booked = booked[slot.ID]
+	}
+	return nil
+}
+
+// slotPointers returns pointers to the elements of slots so they can be filled in place
+func slotPointers(slots []models.OfficeHourSlot) []*models.OfficeHourSlot {
+	pointers := make([]*models.OfficeHourSlot, len(slots))
+	for i := range slots {
+		pointers[i] = &slots[i]
+	}
+	return pointers
+}
+
+// checkOfficeHourConflicts mencari jadwal mengajar dosen pada hari yang sama, peminjaman ruangan
+// dosen yang disetujui, dan slot jam konsultasi lain milik dosen pada tanggal yang sama yang
+// beririsan dengan slot
+func checkOfficeHourConflicts(tx *gorm.DB, slot *models.OfficeHourSlot) error {
+	var conflicts models.OfficeHourConflicts
+
+	if err := tx.Where("semester = ? AND lecturer_user_id = ? AND day_of_week = ?", slot.Semester, slot.LecturerUserID, slot.DayOfWeek()).
+		Where("start_time < ? AND end_time > ?", slot.EndTime, slot.StartTime).
+		Find(&conflicts.Schedules).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("requester_user_id = ? AND date = ? AND status = ?", slot.LecturerUserID, slot.Date, models.BookingApproved).
+		Where("start_time < ? AND end_time > ?", slot.EndTime, slot.StartTime).
+		Find(&conflicts.Bookings).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("lecturer_user_id = ? AND date = ? AND cancelled_at IS NULL AND id <> ?", slot.LecturerUserID, slot.Date, slot.ID).
+		Where("start_time < ? AND end_time > ?", slot.EndTime, slot.StartTime).
+		Find(&conflicts.Slots).Error; err != nil {
+		return err
+	}
+
+	if len(conflicts.Schedules) > 0 || len(conflicts.Bookings) > 0 || len(conflicts.Slots) > 0 {
+		return &OfficeHourConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository adalah interface untuk operasi repository outbox event
+type OutboxRepository interface {
+	Create(event *models.OutboxEvent) error
+	CreateInTx(tx *gorm.DB, event *models.OutboxEvent) error
+	FindPending(limit int) ([]models.OutboxEvent, error)
+	MarkPublished(id uint) error
+	MarkFailed(id uint, reason string) error
+}
+
+// outboxRepository implementasi dari OutboxRepository
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository membuat instance baru dari OutboxRepository
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan event baru ke outbox
+func (r *outboxRepository) Create(event *models.OutboxEvent) error {
+	return r.db.Create(event).Error
+}
+
+// CreateInTx menyimpan event baru ke outbox di dalam transaksi tx, sehingga event hanya ada
+// jika perubahan yang dijelaskannya ikut tersimpan
+func (r *outboxRepository) CreateInTx(tx *gorm.DB, event *models.OutboxEvent) error {
+	return tx.Create(event).Error
+}
+
+// FindPending mengambil event yang belum terkirim, urut dari yang terlama
+func (r *outboxRepository) FindPending(limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	if err := r.db.Where("published_at IS NULL").Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// MarkPublished menandai event sudah terkirim
+func (r *outboxRepository) MarkPublished(id uint) error {
+	return r.db.Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"published_at": time.Now(),
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   "",
+	}).Error
+}
+
+// MarkFailed mencatat percobaan pengiriman yang gagal
+func (r *outboxRepository) MarkFailed(id uint, reason string) error {
+	return r.db.Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}).Error
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrPermissionNotPending dikembalikan ketika pengajuan izin sudah diputuskan atau dibatalkan
+var ErrPermissionNotPending = errors.New("permission request is no longer pending")
+
+// PermissionRequestRepository adalah interface untuk operasi repository pengajuan izin dan sakit
+type PermissionRequestRepository interface {
+	FindByID(id uint) (*models.PermissionRequest, error)
+	FindByStudent(studentUserID uint) ([]models.PermissionRequest, error)
+	FindByLecturers(lecturerUserIDs []uint, status models.PermissionRequestStatus) ([]models.PermissionRequest, error)
+	FindByOfferings(offerings []models.CourseOffering, status models.PermissionRequestStatus) ([]models.PermissionRequest, error)
+	Create(request *models.PermissionRequest) error
+	Approve(request *models.PermissionRequest, deciderUserID uint, note string) (int64, error)
+	Close(request *models.PermissionRequest, status models.PermissionRequestStatus, deciderUserID uint, note string) error
+	ExcuseSession(sessionID uint) (int64, error)
+}
+
+// permissionRequestRepository implementasi dari PermissionRequestRepository
+type permissionRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewPermissionRequestRepository membuat instance baru dari PermissionRequestRepository
+func NewPermissionRequestRepository(db *gorm.DB) PermissionRequestRepository {
+	return &permissionRequestRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari pengajuan izin berdasarkan ID
+func (r *permissionRequestRepository) FindByID(id uint) (*models.PermissionRequest, error) {
+	var request models.PermissionRequest
+	if err := r.db.Where("id = ?", id).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindByStudent mengambil pengajuan izin seorang mahasiswa
+func (r *permissionRequestRepository) FindByStudent(studentUserID uint) ([]models.PermissionRequest, error) {
+	var requests []models.PermissionRequest
+	err := r.db.Where("student_user_id = ?", studentUserID).Order("start_date DESC, id DESC").Find(&requests).Error
+	return requests, err
+}
+
+// FindByLecturers mengambil pengajuan izin yang diputuskan oleh dosen-dosen tersebut,
+// difilter status jika diisi
+func (r *permissionRequestRepository) FindByLecturers(lecturerUserIDs []uint, status models.PermissionRequestStatus) ([]models.PermissionRequest, error) {
+	query := r.db.Where("lecturer_user_id IN ?", lecturerUserIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.PermissionRequest
+	err := query.Order("start_date ASC, id ASC").Find(&requests).Error
+	return requests, err
+}
+
+// FindByOfferings mengambil pengajuan izin pada mata kuliah tersebut, difilter status jika diisi.
+// Kelas kosong pada sebuah mata kuliah berarti semua kelasnya.
+func (r *permissionRequestRepository) FindByOfferings(offerings []models.CourseOffering, status models.PermissionRequestStatus) ([]models.PermissionRequest, error) {
+	requests := []models.PermissionRequest{}
+	if len(offerings) == 0 {
+		return requests, nil
+	}
+
+	matches := r.db.Where("1 = 0")
+	for _, offering := range offerings {
+		match := r.db.Where("course_code = ? AND semester = ?", offering.CourseCode, offering.Semester)
+		if offering.ClassName != "" {
+			match = match.Where("class_name = ?", offering.ClassName)
+		}
+		matches = matches.Or(match)
+	}
+
+	query := r.db.Where(matches)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("start_date ASC, id ASC").Find(&requests).Error
+	return requests, err
+}
+
+// Create menyimpan pengajuan izin baru
+func (r *permissionRequestRepository) Create(request *models.PermissionRequest) error {
+	return r.db.Create(request).Error
+}
+
+// Approve menyetujui pengajuan yang masih menunggu lalu mengubah ketidakhadiran mahasiswa
+// pada sesi yang sudah ditutup dalam rentang tanggalnya menjadi excused
+func (r *permissionRequestRepository) Approve(request *models.PermissionRequest, deciderUserID uint, note string) (int64, error) {
+	var excused int64
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.PermissionRequest{}).Where("id = ? AND status = ?", request.ID, models.PermissionPending).
+			Updates(map[string]interface{}{
+				"status":             models.PermissionApproved,
+				"decided_by_user_id": deciderUserID,
+				"decision_note":      note,
+				"decided_at":         now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrPermissionNotPending
+		}
+
+		var err error
+		excused, err = excuseAbsences(tx, "p.id = ?", request.ID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	request.Status = models.PermissionApproved
+	request.DecidedByUserID = &deciderUserID
+	request.DecisionNote = note
+	request.DecidedAt = &now
+	return excused, nil
+}
+
+// Close menolak atau membatalkan pengajuan yang masih menunggu
+func (r *permissionRequestRepository) Close(request *models.PermissionRequest, status models.PermissionRequestStatus, deciderUserID uint, note string) error {
+	now := time.Now()
+	res := r.db.Model(&models.PermissionRequest{}).Where("id = ? AND status = ?", request.ID, models.PermissionPending).
+		Updates(map[string]interface{}{
+			"status":             status,
+			"decided_by_user_id": deciderUserID,
+			"decision_note":      note,
+			"decided_at":         now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrPermissionNotPending
+	}
+
+	request.Status = status
+	request.DecidedByUserID = &deciderUserID
+	request.DecisionNote = note
+	request.DecidedAt = &now
+	return nil
+}
+
+// ExcuseSession mencatat excused untuk mahasiswa tanpa presensi pada sesi yang baru ditutup
+// bila izinnya yang mencakup tanggal sesi sudah disetujui
+func (r *permissionRequestRepository) ExcuseSession(sessionID uint) (int64, error) {
+	return excuseAbsences(r.db, "s.id = ?", sessionID)
+}
+
+// excuseAbsences membuat presensi excused pada setiap sesi tertutup yang dicakup izin yang
+// disetujui dan cocok dengan kondisi, kecuali mahasiswa sudah memiliki presensi pada sesi itu.
+// Kelas dan semester sesi yang kosong berlaku untuk semua kelas dan semester.
+func excuseAbsences(tx *gorm.DB, condition string, value interface{}) (int64, error) {
+	res := tx.Exec(`INSERT INTO attendance_records
+			(session_id, student_user_id, nim, status, method, late_minutes, credit, checked_in_at, created_at, updated_at)
+		SELECT s.id, p.student_user_id, p.nim, ?, ?, 0, 1, NOW(), NOW(), NOW()
+		FROM permission_requests p
+		JOIN attendance_sessions s ON s.course_code = p.course_code
+			AND (s.class_name = '' OR s.class_name = p.class_name)
+			AND (s.semester = '' OR s.semester = p.semester)
+			AND s.deleted_at IS NULL
+		WHERE p.status = ? AND p.deleted_at IS NULL AND s.status = ?
+			AND CAST(COALESCE(s.scheduled_start, s.opened_at) AS date) BETWEEN p.start_date AND p.end_date
+			AND `+condition+`
+		ON CONFLICT (session_id, student_user_id) DO NOTHING`,
+		models.AttendanceExcused, models.CheckInPermission,
+		models.PermissionApproved, models.SessionClosed, value)
+	return res.RowsAffected, res.Error
+}
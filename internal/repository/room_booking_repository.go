@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrBookingNotPending dikembalikan ketika peminjaman ruangan sudah diputuskan atau dibatalkan
+var ErrBookingNotPending = errors.New("room booking is no longer pending")
+
+// RoomBookingConflictError dikembalikan ketika peminjaman bentrok dengan jadwal kuliah atau peminjaman lain
+type RoomBookingConflictError struct {
+	Conflicts models.RoomBookingConflicts
+}
+
+// Error implements the error interface
+func (e *RoomBookingConflictError) Error() string {
+	return fmt.Sprintf("room booking conflicts with %d schedule(s) and %d booking(s)", len(e.Conflicts.Schedules), len(e.Conflicts.Bookings))
+}
+
+// RoomBookingRepository adalah interface untuk operasi repository peminjaman ruangan
+type RoomBookingRepository interface {
+	FindByID(id uint) (*models.RoomBooking, error)
+	FindByRequester(requesterUserID uint) ([]models.RoomBooking, error)
+	FindAll(status models.RoomBookingStatus) ([]models.RoomBooking, error)
+	Create(booking *models.RoomBooking) error
+	Approve(booking *models.RoomBooking, deciderUserID uint, note string, session *models.AttendanceSession) error
+	Close(booking *models.RoomBooking, status models.RoomBookingStatus, deciderUserID uint, note string) error
+}
+
+// roomBookingRepository implementasi dari RoomBookingRepository
+type roomBookingRepository struct {
+	db *gorm.DB
+}
+
+// NewRoomBookingRepository membuat instance baru dari RoomBookingRepository
+func NewRoomBookingRepository(db *gorm.DB) RoomBookingRepository {
+	return &roomBookingRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari peminjaman ruangan berdasarkan ID
+func (r *roomBookingRepository) FindByID(id uint) (*models.RoomBooking, error) {
+	var booking models.RoomBooking
+	if err := r.db.Where("id = ?", id).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// FindByRequester mengambil peminjaman ruangan yang diajukan seorang pengguna
+func (r *roomBookingRepository) FindByRequester(requesterUserID uint) ([]models.RoomBooking, error) {
+	var bookings []models.RoomBooking
+	err := r.db.Where("requester_user_id = ?", requesterUserID).Order("date DESC, start_time DESC").Find(&bookings).Error
+	return bookings, err
+}
+
+// FindAll mengambil peminjaman ruangan, difilter status jika diisi
+func (r *roomBookingRepository) FindAll(status models.RoomBookingStatus) ([]models.RoomBooking, error) {
+	query := r.db.Model(&models.RoomBooking{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var bookings []models.RoomBooking
+	err := query.Order("date ASC, start_time ASC").Find(&bookings).Error
+	return bookings, err
+}
+
+// Create menyimpan pengajuan peminjaman, menolak pengajuan yang bentrok
+func (r *roomBookingRepository) Create(booking *models.RoomBooking) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkRoomBookingConflicts(tx, booking); err != nil {
+			return err
+		}
+		return tx.Create(booking).Error
+	})
+}
+
+// Approve menyetujui peminjaman yang masih menunggu setelah memeriksa ulang bentrokan,
+// lalu membuat sesi presensinya jika session diisi
+func (r *roomBookingRepository) Approve(booking *models.RoomBooking, deciderUserID uint, note string, session *models.AttendanceSession) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkRoomBookingConflicts(tx, booking); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":             models.BookingApproved,
+			"decided_by_user_id": deciderUserID,
+			"decision_note":      note,
+			"decided_at":         now,
+		}
+		if session != nil {
+			if err := tx.Create(session).Error; err != nil {
+				return err
+			}
+			updates["session_id"] = session.ID
+			booking.SessionID = &session.ID
+		}
+
+		res := tx.Model(&models.RoomBooking{}).Where("id = ? AND status = ?", booking.ID, models.BookingPending).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrBookingNotPending
+		}
+
+		booking.Status = models.BookingApproved
+		booking.DecidedByUserID = &deciderUserID
+		booking.DecisionNote = note
+		booking.DecidedAt = &now
+		return nil
+	})
+}
+
+// Close menolak atau membatalkan peminjaman yang masih menunggu
+func (r *roomBookingRepository) Close(booking *models.RoomBooking, status models.RoomBookingStatus, deciderUserID uint, note string) error {
+	now := time.Now()
+	res := r.db.Model(&models.RoomBooking{}).Where("id = ? AND status = ?", booking.ID, models.BookingPending).
+		Updates(map[string]interface{}{
+			"status":             status,
+			"decided_by_user_id": deciderUserID,
+			"decision_note":      note,
+			"decided_at":         now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrBookingNotPending
+	}
+
+	booking.Status = status
+	booking.DecidedByUserID = &deciderUserID
+	booking.DecisionNote = note
+	booking.DecidedAt = &now
+	return nil
+}
+
+// checkRoomBookingConflicts mencari jadwal kuliah pada hari yang sama dan peminjaman yang
+// sudah disetujui pada tanggal yang sama yang memakai ruangan tersebut pada jam yang beririsan
+func checkRoomBookingConflicts(tx *gorm.DB, booking *models.RoomBooking) error {
+	var conflicts models.RoomBookingConflicts
+
+	if err := tx.Where("semester = ? AND room = ? AND day_of_week = ?", booking.Semester, booking.Room, booking.DayOfWeek()).
+		Where("start_time < ? AND end_time > ?", booking.EndTime, booking.StartTime).
+		Find(&conflicts.Schedules).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("room = ? AND date = ? AND status = ? AND id <> ?", booking.Room, booking.Date, models.BookingApproved, booking.ID).
+		Where("start_time < ? AND end_time > ?", booking.EndTime, booking.StartTime).
+		Find(&conflicts.Bookings).Error; err != nil {
+		return err
+	}
+
+	if len(conflicts.Schedules) > 0 || len(conflicts.Bookings) > 0 {
+		return &RoomBookingConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RoomRepository adalah interface untuk operasi repository ruangan
+type RoomRepository interface {
+	FindByID(id uint) (*models.Room, error)
+	FindByCode(code string) (*models.Room, error)
+	FindAll() ([]models.Room, error)
+	Create(room *models.Room) error
+	Update(room *models.Room) error
+}
+
+// roomRepository implementasi dari RoomRepository
+type roomRepository struct {
+	db *gorm.DB
+}
+
+// NewRoomRepository membuat instance baru dari RoomRepository
+func NewRoomRepository(db *gorm.DB) RoomRepository {
+	return &roomRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari ruangan berdasarkan ID
+func (r *roomRepository) FindByID(id uint) (*models.Room, error) {
+	var room models.Room
+	if err := r.db.Where("id = ?", id).First(&room).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+// FindByCode mencari ruangan berdasarkan kode
+func (r *roomRepository) FindByCode(code string) (*models.Room, error) {
+	var room models.Room
+	if err := r.db.Where("code = ?", code).First(&room).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+// FindAll mengambil semua ruangan
+func (r *roomRepository) FindAll() ([]models.Room, error) {
+	var rooms []models.Room
+	err := r.db.Order("code ASC").Find(&rooms).Error
+	return rooms, err
+}
+
+// Create membuat ruangan baru
+func (r *roomRepository) Create(room *models.Room) error {
+	return r.db.Create(room).Error
+}
+
+// Update memperbarui data ruangan
+func (r *roomRepository) Update(room *models.Room) error {
+	return r.db.Save(room).Error
+}
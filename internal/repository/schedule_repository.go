@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleConflictError dikembalikan ketika jadwal bentrok dengan jadwal lain
+type ScheduleConflictError struct {
+	Conflicts []models.ScheduleConflict
+}
+
+// Error implements the error interface
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("schedule conflicts with %d existing schedule(s)", len(e.Conflicts))
+}
+
+// ScheduleFilter membatasi jadwal yang diambil; field kosong tidak memfilter
+type ScheduleFilter struct {
+	Semester       string
+	CourseCode     string
+	ClassName      string
+	LecturerUserID uint
+	Room           string
+	DayOfWeek      int
+}
+
+// ScheduleRepository adalah interface untuk operasi repository jadwal perkuliahan
+type ScheduleRepository interface {
+	FindByID(id uint) (*models.Schedule, error)
+	FindAll(filter ScheduleFilter) ([]models.Schedule, error)
+	FindCourses(query utils.PageQuery) ([]models.Course, int64, error)
+	Create(schedule *models.Schedule) error
+	Update(schedule *models.Schedule) error
+	Delete(id uint) error
+}
+
+// scheduleRepository implementasi dari ScheduleRepository
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository membuat instance baru dari ScheduleRepository
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari jadwal berdasarkan ID
+func (r *scheduleRepository) FindByID(id uint) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if err := r.db.Where("id = ?", id).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// FindAll mengambil jadwal sesuai filter, diurutkan per hari dan jam mulai
+func (r *scheduleRepository) FindAll(filter ScheduleFilter) ([]models.Schedule, error) {
+	query := r.db.Model(&models.Schedule{})
+	if filter.Semester != "" {
+		query = query.Where("semester = ?", filter.Semester)
+	}
+	if filter.CourseCode != "" {
+		query = query.Where("course_code = ?", filter.CourseCode)
+	}
+	if filter.ClassName != "" {
+		query = query.Where("class_name = ?", filter.ClassName)
+	}
+	if filter.LecturerUserID != 0 {
+		query = query.Where("lecturer_user_id = ?", filter.LecturerUserID)
+	}
+	if filter.Room != "" {
+		query = query.Where("room = ?", filter.Room)
+	}
+	if filter.DayOfWeek != 0 {
+		query = query.Where("day_of_week = ?", filter.DayOfWeek)
+	}
+
+	var schedules []models.Schedule
+	if err := query.Order("day_of_week, start_time, room").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// FindCourses mengambil satu halaman mata kuliah yang memiliki jadwal, per semester, beserta
+// jumlah seluruh mata kuliah yang cocok
+func (r *scheduleRepository) FindCourses(query utils.PageQuery) ([]models.Course, int64, error) {
+	courses := func() *gorm.DB {
+		grouped := r.db.Model(&models.Schedule{}).
+			Select("course_code, MAX(course_name) AS course_name, semester, COUNT(DISTINCT class_name) AS classes").
+			Group("course_code, semester")
+		return r.db.Table("(?) AS courses", grouped)
+	}
+
+	var total int64
+	if err := courses().Scopes(query.Filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []models.Course
+	if err := courses().Scopes(utils.Paginate(query)).Find(&result).Error; err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+// Create menyimpan jadwal baru, menolak jadwal yang bentrok ruangan atau dosennya
+func (r *scheduleRepository) Create(schedule *models.Schedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkScheduleConflicts(tx, schedule); err != nil {
+			return err
+		}
+		return tx.Create(schedule).Error
+	})
+}
+
+// Update memperbarui jadwal, menolak perubahan yang membuatnya bentrok
+func (r *scheduleRepository) Update(schedule *models.Schedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkScheduleConflicts(tx, schedule); err != nil {
+			return err
+		}
+		return tx.Save(schedule).Error
+	})
+}
+
+// Delete menghapus jadwal
+func (r *scheduleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Schedule{}, id).Error
+}
+
+// checkScheduleConflicts mencari jadwal lain pada semester dan hari yang sama yang
+// memakai ruangan atau dosen yang sama pada jam yang beririsan
+func checkScheduleConflicts(tx *gorm.DB, schedule *models.Schedule) error {
+	var candidates []models.Schedule
+	if err := tx.Where("semester = ? AND day_of_week = ? AND id <> ?", schedule.Semester, schedule.DayOfWeek, schedule.ID).
+		Where("(room = ? OR lecturer_user_id = ?)", schedule.Room, schedule.LecturerUserID).
+		Where("start_time < ? AND end_time > ?", schedule.EndTime, schedule.StartTime).
+		Find(&candidates).Error; err != nil {
+		return err
+	}
+
+	var conflicts []models.ScheduleConflict
+	for _, candidate := range candidates {
+		if candidate.Room == schedule.Room {
+			conflicts = append(conflicts, models.ScheduleConflict{Reason: models.RoomConflict, Schedule: candidate})
+		}
+		if candidate.LecturerUserID == schedule.LecturerUserID {
+			conflicts = append(conflicts, models.ScheduleConflict{Reason: models.LecturerConflict, Schedule: candidate})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ScheduleConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
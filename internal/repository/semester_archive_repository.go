@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// semesterArchiveBatchSize is how many rows are read or written at a time
+const semesterArchiveBatchSize = 1000
+
+// ErrSemesterNotEmpty dikembalikan ketika arsip diimpor ke semester yang sudah memiliki data
+var ErrSemesterNotEmpty = errors.New("the semester already has schedules, enrollments or sessions")
+
+// SemesterArchiveRepository adalah interface untuk operasi repository arsip semester
+type SemesterArchiveRepository interface {
+	HasSemesterData(semester string) (bool, error)
+	FindSchedules(semester string) ([]models.Schedule, error)
+	FindEnrollments(semester string) ([]models.Enrollment, error)
+	FindSessions(semester string) ([]models.AttendanceSession, error)
+	EachRecords(semester string, fn func([]models.AttendanceRecord) error) error
+	EachEdits(semester string, fn func([]models.AttendanceEdit) error) error
+	FindMaterials(semester string) ([]models.SessionMaterial, error)
+	Import(archive *models.SemesterArchive) error
+}
+
+// semesterArchiveRepository implementasi dari SemesterArchiveRepository
+type semesterArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewSemesterArchiveRepository membuat instance baru dari SemesterArchiveRepository
+func NewSemesterArchiveRepository(db *gorm.DB) SemesterArchiveRepository {
+	return &semesterArchiveRepository{
+		db: db,
+	}
+}
+
+// createInBatches menyimpan baris-baris arsip; slice kosong dilewati
+func createInBatches(tx *gorm.DB, rows interface{}, count int) error {
+	if count == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(rows, semesterArchiveBatchSize).Error
+}
+
+// hasSemesterData memeriksa apakah semester sudah memiliki jadwal, mahasiswa terdaftar, atau sesi
+func hasSemesterData(db *gorm.DB, semester string) (bool, error) {
+	for _, model := range []interface{}{&models.Schedule{}, &models.Enrollment{}, &models.AttendanceSession{}} {
+		var count int64
+		if err := db.Model(model).Where("semester = ?", semester).Limit(1).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HasSemesterData memeriksa apakah semester sudah memiliki jadwal, mahasiswa terdaftar, atau sesi
+func (r *semesterArchiveRepository) HasSemesterData(semester string) (bool, error) {
+	return hasSemesterData(r.db, semester)
+}
+
+// FindSchedules mengambil jadwal kuliah sebuah semester
+func (r *semesterArchiveRepository) FindSchedules(semester string) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := r.db.Where("semester = ?", semester).Order("id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// FindEnrollments mengambil mahasiswa terdaftar pada mata kuliah sebuah semester
+func (r *semesterArchiveRepository) FindEnrollments(semester string) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	err := r.db.Where("semester = ?", semester).Order("id ASC").Find(&enrollments).Error
+	return enrollments, err
+}
+
+// FindSessions mengambil sesi presensi sebuah semester
+func (r *semesterArchiveRepository) FindSessions(semester string) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	err := r.db.Where("semester = ?", semester).Order("id ASC").Find(&sessions).Error
+	return sessions, err
+}
+
+// semesterSessionIDs adalah subquery ID sesi presensi sebuah semester
+func (r *semesterArchiveRepository) semesterSessionIDs(semester string) *gorm.DB {
+	return r.db.Model(&models.AttendanceSession{}).Select("id").Where("semester = ?", semester)
+}
+
+// EachRecords mengambil presensi sesi sebuah semester per batch
+func (r *semesterArchiveRepository) EachRecords(semester string, fn func([]models.AttendanceRecord) error) error {
+	var batch []models.AttendanceRecord
+	return r.db.Where("session_id IN (?)", r.semesterSessionIDs(semester)).
+		FindInBatches(&batch, semesterArchiveBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// EachEdits mengambil riwayat koreksi presensi sesi sebuah semester per batch
+func (r *semesterArchiveRepository) EachEdits(semester string, fn func([]models.AttendanceEdit) error) error {
+	var batch []models.AttendanceEdit
+	return r.db.Where("session_id IN (?)", r.semesterSessionIDs(semester)).
+		FindInBatches(&batch, semesterArchiveBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// FindMaterials mengambil catatan dan lampiran sesi sebuah semester
+func (r *semesterArchiveRepository) FindMaterials(semester string) ([]models.SessionMaterial, error) {
+	var materials []models.SessionMaterial
+	err := r.db.Where("session_id IN (?)", r.semesterSessionIDs(semester)).Order("id ASC").Find(&materials).Error
+	return materials, err
+}
+
+// Import menyimpan isi arsip semester dalam satu transaksi. Semua baris mendapat ID baru dan
+// referensi ke sesi dan presensi disesuaikan; semester yang sudah memiliki data ditolak dengan
+// ErrSemesterNotEmpty.
+func (r *semesterArchiveRepository) Import(archive *models.SemesterArchive) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		exists, err := hasSemesterData(tx, archive.Manifest.Semester)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrSemesterNotEmpty
+		}
+
+		for i := range archive.Schedules {
+			archive.Schedules[i].ID = 0
+		}
+		if err := createInBatches(tx, archive.Schedules, len(archive.Schedules)); err != nil {
+			return err
+		}
+		for i := range archive.Enrollments {
+			archive.Enrollments[i].ID = 0
+		}
+		if err := createInBatches(tx, archive.Enrollments, len(archive.Enrollments)); err != nil {
+			return err
+		}
+
+		oldSessionIDs := make([]uint, len(archive.Sessions))
+		for i := range archive.Sessions {
+			oldSessionIDs[i] = archive.Sessions[i].ID
+			archive.Sessions[i].ID = 0
+		}
+		if err := createInBatches(tx, archive.Sessions, len(archive.Sessions)); err != nil {
+			return err
+		}
+		sessionIDs := make(map[uint]uint, len(oldSessionIDs))
+		for i, oldID := range oldSessionIDs {
+			sessionIDs[oldID] = archive.Sessions[i].ID
+		}
+
+		oldRecordIDs := make([]uint, len(archive.Records))
+		for i := range archive.Records {
+			record := &archive.Records[i]
+			newSessionID, ok := sessionIDs[record.SessionID]
+			if !ok {
+				return fmt.Errorf("record %d refers to session %d, which is not in the archive", record.ID, record.SessionID)
+			}
+			oldRecordIDs[i] = record.ID
+			record.ID, record.SessionID = 0, newSessionID
+		}
+		if err := createInBatches(tx.Omit(clause.Associations), archive.Records, len(archive.Records)); err != nil {
+			return err
+		}
+		recordIDs := make(map[uint]uint, len(oldRecordIDs))
+		for i, oldID := range oldRecordIDs {
+			recordIDs[oldID] = archive.Records[i].ID
+		}
+
+		for i := range archive.Edits {
+			edit := &archive.Edits[i]
+			newSessionID, ok := sessionIDs[edit.SessionID]
+			if !ok {
+				return fmt.Errorf("edit %d refers to session %d, which is not in the archive", edit.ID, edit.SessionID)
+			}
+			edit.ID, edit.SessionID = 0, newSessionID
+			// Records removed after the edit are not archived, so the edit points to no record
+			if edit.RecordID != nil {
+				if newRecordID, ok := recordIDs[*edit.RecordID]; ok {
+					edit.RecordID = &newRecordID
+				} else {
+					edit.RecordID = nil
+				}
+			}
+		}
+		if err := createInBatches(tx, archive.Edits, len(archive.Edits)); err != nil {
+			return err
+		}
+
+		materials := make([]models.SessionMaterial, 0, len(archive.Materials))
+		for _, archived := range archive.Materials {
+			material := archived.SessionMaterial
+			newSessionID, ok := sessionIDs[material.SessionID]
+			if !ok {
+				return fmt.Errorf("material %d refers to session %d, which is not in the archive", material.ID, material.SessionID)
+			}
+			material.ID, material.SessionID = 0, newSessionID
+			material.AttachmentName = archived.AttachmentName
+			materials = append(materials, material)
+		}
+		return createInBatches(tx, materials, len(materials))
+	})
+}
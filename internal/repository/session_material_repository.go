@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SessionMaterialRepository adalah interface untuk operasi repository catatan dan lampiran sesi presensi
+type SessionMaterialRepository interface {
+	FindByID(id uint) (*models.SessionMaterial, error)
+	FindBySession(sessionID uint) ([]models.SessionMaterial, error)
+	Create(material *models.SessionMaterial) error
+	Delete(id uint) error
+}
+
+// sessionMaterialRepository implementasi dari SessionMaterialRepository
+type sessionMaterialRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionMaterialRepository membuat instance baru dari SessionMaterialRepository
+func NewSessionMaterialRepository(db *gorm.DB) SessionMaterialRepository {
+	return &sessionMaterialRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari catatan atau lampiran sesi berdasarkan ID
+func (r *sessionMaterialRepository) FindByID(id uint) (*models.SessionMaterial, error) {
+	var material models.SessionMaterial
+	if err := r.db.Where("id = ?", id).First(&material).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &material, nil
+}
+
+// FindBySession mengambil catatan dan lampiran sebuah sesi sesuai urutan ditambahkan
+func (r *sessionMaterialRepository) FindBySession(sessionID uint) ([]models.SessionMaterial, error) {
+	materials := []models.SessionMaterial{}
+	err := r.db.Where("session_id = ?", sessionID).Order("id").Find(&materials).Error
+	return materials, err
+}
+
+// Create menyimpan catatan atau lampiran sesi baru
+func (r *sessionMaterialRepository) Create(material *models.SessionMaterial) error {
+	return r.db.Create(material).Error
+}
+
+// Delete menghapus catatan atau lampiran sesi
+func (r *sessionMaterialRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SessionMaterial{}, id).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StatusMessageRepository adalah interface untuk operasi repository pesan status
+type StatusMessageRepository interface {
+	FindRecent(limit int) ([]models.StatusMessage, error)
+	FindShownBetween(from, to time.Time) ([]models.StatusMessage, error)
+	FindByID(id uint) (*models.StatusMessage, error)
+	Create(message *models.StatusMessage) error
+	Update(message *models.StatusMessage) error
+	Delete(id uint) error
+}
+
+// statusMessageRepository implementasi dari StatusMessageRepository
+type statusMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewStatusMessageRepository membuat instance baru dari StatusMessageRepository
+func NewStatusMessageRepository(db *gorm.DB) StatusMessageRepository {
+	return &statusMessageRepository{
+		db: db,
+	}
+}
+
+// FindRecent mengambil pesan status terbaru berdasarkan waktu mulai
+func (r *statusMessageRepository) FindRecent(limit int) ([]models.StatusMessage, error) {
+	var messages []models.StatusMessage
+	err := r.db.Order("starts_at DESC, id DESC").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// FindShownBetween mengambil pesan status yang tampil pada suatu waktu antara from dan to
+func (r *statusMessageRepository) FindShownBetween(from, to time.Time) ([]models.StatusMessage, error) {
+	var messages []models.StatusMessage
+	err := r.db.Where("starts_at < ? AND (ends_at IS NULL OR ends_at > ?)", to, from).
+		Order("starts_at ASC, id ASC").Find(&messages).Error
+	return messages, err
+}
+
+// FindByID mencari pesan status berdasarkan ID
+func (r *statusMessageRepository) FindByID(id uint) (*models.StatusMessage, error) {
+	var message models.StatusMessage
+	if err := r.db.First(&message, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
+// Create menyimpan pesan status baru
+func (r *statusMessageRepository) Create(message *models.StatusMessage) error {
+	return r.db.Create(message).Error
+}
+
+// Update memperbarui pesan status
+func (r *statusMessageRepository) Update(message *models.StatusMessage) error {
+	return r.db.Save(message).Error
+}
+
+// Delete menghapus pesan status
+func (r *statusMessageRepository) Delete(id uint) error {
+	return r.db.Delete(&models.StatusMessage{}, id).Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SupervisionRepository adalah interface untuk operasi repository bimbingan tugas akhir
+type SupervisionRepository interface {
+	FindByID(id uint) (*models.SupervisionMeeting, error)
+	FindByStudent(studentUserID uint) ([]models.SupervisionMeeting, error)
+	FindBySupervisors(supervisorUserIDs []uint, status models.SupervisionStatus) ([]models.SupervisionMeeting, error)
+	Create(meeting *models.SupervisionMeeting) error
+	Update(meeting *models.SupervisionMeeting) error
+	FrequencyByLecturer(departmentID uint, from, to string) ([]models.SupervisionFrequency, error)
+}
+
+// supervisionRepository implementasi dari SupervisionRepository
+type supervisionRepository struct {
+	db *gorm.DB
+}
+
+// NewSupervisionRepository membuat instance baru dari SupervisionRepository
+func NewSupervisionRepository(db *gorm.DB) SupervisionRepository {
+	return &supervisionRepository{
+		db: db,
+	}
+}
+
+// FindByID mencari pertemuan bimbingan berdasarkan ID
+func (r *supervisionRepository) FindByID(id uint) (*models.SupervisionMeeting, error) {
+	var meeting models.SupervisionMeeting
+	if err := r.db.Where("id = ?", id).First(&meeting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+// FindByStudent mengambil pertemuan bimbingan milik mahasiswa
+func (r *supervisionRepository) FindByStudent(studentUserID uint) ([]models.SupervisionMeeting, error) {
+	var meetings []models.SupervisionMeeting
+	if err := r.db.Where("student_user_id = ?", studentUserID).Order("meeting_date DESC").Find(&meetings).Error; err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// FindBySupervisors mengambil pertemuan bimbingan untuk para dosen pembimbing, opsional difilter status
+func (r *supervisionRepository) FindBySupervisors(supervisorUserIDs []uint, status models.SupervisionStatus) ([]models.SupervisionMeeting, error) {
+	query := r.db.Where("supervisor_user_id IN ?", supervisorUserIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var meetings []models.SupervisionMeeting
+	if err := query.Order("meeting_date DESC").Find(&meetings).Error; err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// Create menyimpan pertemuan bimbingan baru
+func (r *supervisionRepository) Create(meeting *models.SupervisionMeeting) error {
+	return r.db.Create(meeting).Error
+}
+
+// Update memperbarui pertemuan bimbingan
+func (r *supervisionRepository) Update(meeting *models.SupervisionMeeting) error {
+	return r.db.Save(meeting).Error
+}
+
+// FrequencyByLecturer menghitung frekuensi bimbingan per dosen dalam suatu prodi dan rentang tanggal
+func (r *supervisionRepository) FrequencyByLecturer(departmentID uint, from, to string) ([]models.SupervisionFrequency, error) {
+	query := r.db.Table("supervision_meetings AS sm").
+		Select(`sm.supervisor_user_id AS supervisor_user_id,
+			COALESCE(l.full_name, '') AS lecturer_name,
+			COALESCE(l.department_id, 0) AS department_id,
+			COALESCE(l.department, '') AS department,
+			COUNT(*) FILTER (WHERE sm.status = 'confirmed') AS meeting_count,
+			COUNT(DISTINCT sm.student_user_id) FILTER (WHERE sm.status = 'confirmed') AS student_count,
+			COUNT(*) FILTER (WHERE sm.status = 'pending') AS pending_count`).
+		Joins("LEFT JOIN lecturers AS l ON l.lecturer_user_id = sm.supervisor_user_id AND l.deleted_at IS NULL").
+		Where("sm.deleted_at IS NULL")
+
+	if departmentID != 0 {
+		query = query.Where("l.department_id = ?", departmentID)
+	}
+	if from != "" {
+		query = query.Where("sm.meeting_date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("sm.meeting_date <= ?", to)
+	}
+
+	var rows []models.SupervisionFrequency
+	err := query.Group("sm.supervisor_user_id, l.full_name, l.department_id, l.department").
+		Order("meeting_count DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncConflictRepository adalah interface untuk operasi repository status field yang
+// disinkronkan dan konflik sinkronisasi
+type SyncConflictRepository interface {
+	FindFieldStates(profileType models.UserType, userID uint) ([]models.ProfileFieldState, error)
+	SaveMerge(states []models.ProfileFieldState, conflicts []models.SyncConflict) error
+	RecordLocalEdits(profileType models.UserType, userID uint, previous map[string]string, editedAt time.Time) error
+	FindOpenConflicts(profileType models.UserType, userID uint) ([]models.SyncConflict, error)
+	FindConflictByID(id uint) (*models.SyncConflict, error)
+	UpdateConflict(conflict *models.SyncConflict) error
+}
+
+// syncConflictRepository implementasi dari SyncConflictRepository
+type syncConflictRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncConflictRepository membuat instance baru dari SyncConflictRepository
+func NewSyncConflictRepository(db *gorm.DB) SyncConflictRepository {
+	return &syncConflictRepository{
+		db: db,
+	}
+}
+
+// FindFieldStates mengambil status field yang disinkronkan dari sebuah profil
+func (r *syncConflictRepository) FindFieldStates(profileType models.UserType, userID uint) ([]models.ProfileFieldState, error) {
+	var states []models.ProfileFieldState
+	err := r.db.Where("profile_type = ? AND user_id = ?", profileType, userID).Find(&states).Error
+	return states, err
+}
+
+// SaveMerge menyimpan hasil penggabungan sebuah sinkronisasi: status field yang berubah dan
+// konflik baru, yang menggantikan konflik terbuka sebelumnya pada field yang sama
+func (r *syncConflictRepository) SaveMerge(states []models.ProfileFieldState, conflicts []models.SyncConflict) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(states) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "profile_type"}, {Name: "user_id"}, {Name: "field"}},
+				DoUpdates: clause.AssignmentColumns([]string{"campus_value", "local_edited_at", "updated_at"}),
+			}).Create(&states).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := range conflicts {
+			err := tx.Model(&models.SyncConflict{}).
+				Where("profile_type = ? AND user_id = ? AND field = ? AND status = ?",
+					conflicts[i].ProfileType, conflicts[i].UserID, conflicts[i].Field, models.SyncConflictOpen).
+				Update("status", models.SyncConflictSuperseded).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&conflicts[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordLocalEdits mencatat waktu pengguna mengubah field yang disinkronkan. Field yang belum
+// memiliki status dicatat dengan nilai sebelum diubah sebagai nilai terakhir dari API kampus.
+func (r *syncConflictRepository) RecordLocalEdits(profileType models.UserType, userID uint, previous map[string]string, editedAt time.Time) error {
+	if len(previous) == 0 {
+		return nil
+	}
+	states := make([]models.ProfileFieldState, 0, len(previous))
+	for field, value := range previous {
+		states = append(states, models.ProfileFieldState{
+			ProfileType:   profileType,
+			UserID:        userID,
+			Field:         field,
+			CampusValue:   value,
+			LocalEditedAt: &editedAt,
+		})
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "profile_type"}, {Name: "user_id"}, {Name: "field"}},
+		DoUpdates: clause.AssignmentColumns([]string{"local_edited_at", "updated_at"}),
+	}).Create(&states).Error
+}
+
+// FindOpenConflicts mengambil konflik sebuah profil yang belum ditinjau, terbaru dahulu
+func (r *syncConflictRepository) FindOpenConflicts(profileType models.UserType, userID uint) ([]models.SyncConflict, error) {
+	var conflicts []models.SyncConflict
+	err := r.db.Where("profile_type = ? AND user_id = ? AND status = ?", profileType, userID, models.SyncConflictOpen).
+		Order("detected_at DESC").Find(&conflicts).Error
+	return conflicts, err
+}
+
+// FindConflictByID mencari konflik berdasarkan ID
+func (r *syncConflictRepository) FindConflictByID(id uint) (*models.SyncConflict, error) {
+	var conflict models.SyncConflict
+	if err := r.db.Where("id = ?", id).First(&conflict).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &conflict, nil
+}
+
+// UpdateConflict memperbarui konflik
+func (r *syncConflictRepository) UpdateConflict(conflict *models.SyncConflict) error {
+	return r.db.Save(conflict).Error
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"errors"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SyncRunRepository adalah interface untuk operasi repository sinkronisasi massal
+type SyncRunRepository interface {
+	FindRecent(kind models.SyncKind, limit int) ([]models.SyncRun, error)
+	Create(run *models.SyncRun) error
+	Update(run *models.SyncRun) error
+	FindByID(id uint) (*models.SyncRun, error)
+	FindRunning(kind models.SyncKind) ([]models.SyncRun, error)
+	FindProdiProfiles(prodiID uint) ([]models.SyncRunItem, error)
+	CreateWithItems(run *models.SyncRun, items []models.SyncRunItem) error
+	FindPendingItems(runID uint, limit int) ([]models.SyncRunItem, error)
+	FinishItem(run *models.SyncRun, item *models.SyncRunItem) error
+	FindFailedItems(runID uint) ([]models.SyncRunItem, error)
+}
+
+// syncRunRepository implementasi dari SyncRunRepository
+type syncRunRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRunRepository membuat instance baru dari SyncRunRepository
+func NewSyncRunRepository(db *gorm.DB) SyncRunRepository {
+	return &syncRunRepository{
+		db: db,
+	}
+}
+
+// FindRecent mengambil sinkronisasi terbaru dari satu jenis
+func (r *syncRunRepository) FindRecent(kind models.SyncKind, limit int) ([]models.SyncRun, error) {
+	var runs []models.SyncRun
+	err := r.db.Where("kind = ?", kind).Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// Create menyimpan sinkronisasi baru
+func (r *syncRunRepository) Create(run *models.SyncRun) error {
+	return r.db.Create(run).Error
+}
+
+// Update memperbarui hasil sinkronisasi
+func (r *syncRunRepository) Update(run *models.SyncRun) error {
+	return r.db.Save(run).Error
+}
+
+// FindByID mencari sinkronisasi berdasarkan ID
+func (r *syncRunRepository) FindByID(id uint) (*models.SyncRun, error) {
+	var run models.SyncRun
+	if err := r.db.Where("id = ?", id).First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindRunning mengambil sinkronisasi dari satu jenis yang masih berjalan, terlama dahulu
+func (r *syncRunRepository) FindRunning(kind models.SyncKind) ([]models.SyncRun, error) {
+	var runs []models.SyncRun
+	err := r.db.Where("kind = ? AND status = ?", kind, models.SyncRunning).Order("started_at, id").Find(&runs).Error
+	return runs, err
+}
+
+// FindProdiProfiles mengambil profil lokal pada sebuah prodi yang perlu disinkronkan ulang: dosen
+// dengan prodi tersebut, asisten yang ditugaskan oleh dosen prodi tersebut, dan mahasiswa yang
+// snapshot-nya tercatat pada prodi tersebut. Item yang dikembalikan belum disimpan.
+func (r *syncRunRepository) FindProdiProfiles(prodiID uint) ([]models.SyncRunItem, error) {
+	var items []models.SyncRunItem
+	err := r.db.Raw(`SELECT lecturer_user_id AS user_id, ? AS profile_type
+			FROM lecturers WHERE department_id = ? AND deleted_at IS NULL
+		UNION
+		SELECT s.assistant_user_id, ?
+			FROM assistants s
+			JOIN assistant_assignments a ON a.assistant_user_id = s.assistant_user_id
+			JOIN lecturers l ON l.lecturer_user_id = a.assigned_by AND l.deleted_at IS NULL
+			WHERE l.department_id = ? AND s.deleted_at IS NULL
+		UNION
+		SELECT user_id, ?
+			FROM mahasiswa_snapshots WHERE basic_info <> '' AND (basic_info::jsonb->>'prodi_id')::int = ?
+		ORDER BY profile_type, user_id`,
+		models.LecturerType, prodiID, models.AssistantType, prodiID, models.StudentType, prodiID).
+		Scan(&items).Error
+	return items, err
+}
+
+// CreateWithItems menyimpan sinkronisasi baru beserta profil yang diantrekan
+func (r *syncRunRepository) CreateWithItems(run *models.SyncRun, items []models.SyncRunItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].RunID = run.ID
+			items[i].Status = models.SyncItemPending
+		}
+		return tx.CreateInBatches(items, 500).Error
+	})
+}
+
+// FindPendingItems mengambil profil yang belum disinkronkan pada sebuah sinkronisasi
+func (r *syncRunRepository) FindPendingItems(runID uint, limit int) ([]models.SyncRunItem, error) {
+	var items []models.SyncRunItem
+	err := r.db.Where("run_id = ? AND status = ?", runID, models.SyncItemPending).
+		Order("id").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// FinishItem menyimpan hasil sinkronisasi satu profil dan menambah hitungan pada sinkronisasinya
+// dalam satu transaksi
+func (r *syncRunRepository) FinishItem(run *models.SyncRun, item *models.SyncRunItem) error {
+	counter := "updated"
+	if item.Status == models.SyncItemFailed {
+		counter = "failed"
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(item).Updates(map[string]interface{}{
+			"status":       item.Status,
+			"error":        item.Error,
+			"processed_at": item.ProcessedAt,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(run).UpdateColumn(counter, gorm.Expr(counter+" + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", run.ID).First(run).Error
+	})
+}
+
+// FindFailedItems mengambil profil yang gagal disinkronkan pada sebuah sinkronisasi
+func (r *syncRunRepository) FindFailedItems(runID uint) ([]models.SyncRunItem, error) {
+	var items []models.SyncRunItem
+	err := r.db.Where("run_id = ? AND status = ?", runID, models.SyncItemFailed).Order("id").Find(&items).Error
+	return items, err
+}
@@ -8,6 +8,7 @@ import (
 	"delpresence-api/pkg/database"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -45,6 +46,85 @@ func (r *TokenRepository) CreateToken(userID uint, token string, tokenType model
 	return nil
 }
 
+// CreateRefreshToken stores a refresh token bound to the role it was issued for
+func (r *TokenRepository) CreateRefreshToken(userID uint, token string, activeRole string, expiry time.Time) error {
+	return r.createRoleToken(userID, token, models.RefreshToken, activeRole, expiry)
+}
+
+// CreateCampusSessionToken stores the refresh token of a campus user's dashboard session,
+// bound to the role it was issued for
+func (r *TokenRepository) CreateCampusSessionToken(campusUserID uint, token string, activeRole string, expiry time.Time) error {
+	return r.createRoleToken(campusUserID, token, models.CampusSessionToken, activeRole, expiry)
+}
+
+// CreateCampusRefreshToken stores the refresh token of a campus user's exchanged app tokens,
+// bound to the role it was issued for
+func (r *TokenRepository) CreateCampusRefreshToken(campusUserID uint, token string, activeRole string, expiry time.Time) error {
+	return r.createRoleToken(campusUserID, token, models.CampusRefreshToken, activeRole, expiry)
+}
+
+// createRoleToken stores a token of the given type bound to a role
+func (r *TokenRepository) createRoleToken(userID uint, token string, tokenType models.TokenType, activeRole string, expiry time.Time) error {
+	newToken := &models.Token{
+		UserID:     userID,
+		Token:      token,
+		Type:       tokenType,
+		ActiveRole: activeRole,
+		ExpiresAt:  expiry,
+	}
+
+	if err := r.DB.Create(newToken).Error; err != nil {
+		return ErrTokenCreateFail
+	}
+	return nil
+}
+
+// RotateToken revokes a token and stores its replacement in one transaction.
+// It returns ErrTokenNotFound when the token was already revoked by a concurrent request.
+func (r *TokenRepository) RotateToken(old *models.Token, newTokenStr string, expiry time.Time) (*models.Token, error) {
+	newToken := &models.Token{
+		UserID:     old.UserID,
+		Token:      newTokenStr,
+		Type:       old.Type,
+		ActiveRole: old.ActiveRole,
+		ExpiresAt:  expiry,
+	}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", old.ID).Delete(&models.Token{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTokenNotFound
+		}
+		if err := tx.Create(newToken).Error; err != nil {
+			return ErrTokenCreateFail
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newToken, nil
+}
+
+// GetRevokedToken retrieves a token that was revoked before it expired, if any
+func (r *TokenRepository) GetRevokedToken(tokenStr string, tokenType models.TokenType) (*models.Token, error) {
+	var token models.Token
+	result := r.DB.Unscoped().
+		Where("token = ? AND type = ? AND deleted_at IS NOT NULL AND expires_at > ?", tokenStr, tokenType, time.Now()).
+		First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
 // GetTokenByValue retrieves a token by its value and type
 func (r *TokenRepository) GetTokenByValue(tokenStr string, tokenType models.TokenType) (*models.Token, error) {
 	var token models.Token
@@ -104,3 +184,88 @@ func (r *TokenRepository) DeleteExpiredTokens() error {
 	}
 	return nil
 }
+
+// emailChangeTypes are the token types of a pending email change
+var emailChangeTypes = []models.TokenType{models.EmailChangeCurrentToken, models.EmailChangeNewToken}
+
+// CreateEmailChange stores the confirmation tokens of an email change for the user's current
+// and requested address, replacing any change the user still had pending
+func (r *TokenRepository) CreateEmailChange(userID uint, newEmail, currentToken, newToken string, expiry time.Time) error {
+	tokens := []models.Token{
+		{UserID: userID, Token: currentToken, Type: models.EmailChangeCurrentToken, PendingEmail: newEmail, ExpiresAt: expiry},
+		{UserID: userID, Token: newToken, Type: models.EmailChangeNewToken, PendingEmail: newEmail, ExpiresAt: expiry},
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND type IN ?", userID, emailChangeTypes).Delete(&models.Token{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&tokens).Error; err != nil {
+			return ErrTokenCreateFail
+		}
+		return nil
+	})
+}
+
+// FindEmailChange retrieves the unexpired confirmation tokens of the user's pending email change
+func (r *TokenRepository) FindEmailChange(userID uint) ([]models.Token, error) {
+	var tokens []models.Token
+	err := r.DB.Where("user_id = ? AND type IN ? AND expires_at > ?", userID, emailChangeTypes, time.Now()).
+		Order("id").Find(&tokens).Error
+	return tokens, err
+}
+
+// ConfirmEmailChange marks an email change token as confirmed. When both tokens of the change
+// are confirmed their expiry is moved to keepUntil, so they outlive the cooling-off period.
+// It reports whether both addresses have now confirmed the change.
+func (r *TokenRepository) ConfirmEmailChange(token *models.Token, keepUntil time.Time) (bool, error) {
+	bothConfirmed := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var tokens []models.Token
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND type IN ?", token.UserID, emailChangeTypes).
+			Find(&tokens).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		confirmed := 0
+		for i := range tokens {
+			if tokens[i].ID == token.ID && tokens[i].ConfirmedAt == nil {
+				if err := tx.Model(&tokens[i]).Update("confirmed_at", now).Error; err != nil {
+					return err
+				}
+			}
+			if tokens[i].ConfirmedAt != nil || tokens[i].ID == token.ID {
+				confirmed++
+			}
+		}
+		if confirmed < len(emailChangeTypes) {
+			return nil
+		}
+
+		bothConfirmed = true
+		return tx.Model(&models.Token{}).
+			Where("user_id = ? AND type IN ?", token.UserID, emailChangeTypes).
+			Update("expires_at", keepUntil).Error
+	})
+	return bothConfirmed, err
+}
+
+// FindConfirmedEmailChanges retrieves the requested-address tokens of the email changes both
+// addresses confirmed before the given time
+func (r *TokenRepository) FindConfirmedEmailChanges(confirmedBefore time.Time) ([]models.Token, error) {
+	var tokens []models.Token
+	err := r.DB.Where("type = ? AND confirmed_at <= ?", models.EmailChangeNewToken, confirmedBefore).
+		Where("EXISTS (SELECT 1 FROM tokens c WHERE c.user_id = tokens.user_id AND c.type = ? AND c.confirmed_at <= ? AND c.deleted_at IS NULL)",
+			models.EmailChangeCurrentToken, confirmedBefore).
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// DeleteEmailChange deletes the tokens of the user's pending email change, reporting whether
+// there was one
+func (r *TokenRepository) DeleteEmailChange(userID uint) (bool, error) {
+	result := r.DB.Where("user_id = ? AND type IN ?", userID, emailChangeTypes).Delete(&models.Token{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageRepository adalah interface untuk operasi repository rekap penggunaan API dan kuota
+type UsageRepository interface {
+	AddRollups(rollups []models.APIUsageRollup) error
+	Summary(from, to time.Time, groupBy []string, limit int) ([]models.APIUsageSummary, error)
+	CountRequests(route string, userID uint, since time.Time) (int64, error)
+	FindQuotas() ([]models.UsageQuota, error)
+	FindQuotaByID(id uint) (*models.UsageQuota, error)
+	SaveQuota(quota *models.UsageQuota) error
+	DeleteQuota(id uint) error
+}
+
+// usageRepository implementasi dari UsageRepository
+type usageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository membuat instance baru dari UsageRepository
+func NewUsageRepository(db *gorm.DB) UsageRepository {
+	return &usageRepository{
+		db: db,
+	}
+}
+
+// AddRollups menambahkan hitungan ke rekap per jam, membuat baris baru bila belum ada
+func (r *usageRepository) AddRollups(rollups []models.APIUsageRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "bucket"}, {Name: "route"}, {Name: "method"}, {Name: "user_id"}, {Name: "role"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"requests":          gorm.Expr("api_usage_rollups.requests + excluded.requests"),
+			"errors":            gorm.Expr("api_usage_rollups.errors + excluded.errors"),
+			"throttled":         gorm.Expr("api_usage_rollups.throttled + excluded.throttled"),
+			"total_duration_ms": gorm.Expr("api_usage_rollups.total_duration_ms + excluded.total_duration_ms"),
+			"max_duration_ms":   gorm.Expr("GREATEST(api_usage_rollups.max_duration_ms, excluded.max_duration_ms)"),
+		}),
+	}).CreateInBatches(rollups, 200).Error
+}
+
+// Summary menjumlahkan rekap dalam rentang waktu, dikelompokkan menurut dimensi groupBy
+// (lihat models.UsageDimensions) dan diurutkan dari jumlah request terbanyak
+func (r *usageRepository) Summary(from, to time.Time, groupBy []string, limit int) ([]models.APIUsageSummary, error) {
+	columns := make([]string, 0, len(groupBy))
+	for _, dimension := range groupBy {
+		column, ok := models.UsageDimensions[dimension]
+		if !ok {
+			return nil, errors.New("unknown usage dimension: " + dimension)
+		}
+		columns = append(columns, column)
+	}
+
+	selects := append(append([]string{}, columns...),
+		"SUM(requests) AS requests",
+		"SUM(errors) AS errors",
+		"SUM(throttled) AS throttled",
+		"COALESCE(SUM(total_duration_ms) / NULLIF(SUM(requests), 0), 0) AS avg_duration_ms",
+		"MAX(max_duration_ms) AS max_duration_ms",
+	)
+	query := r.db.Model(&models.APIUsageRollup{}).
+		Select(strings.Join(selects, ", ")).
+		Where("bucket >= ? AND bucket < ?", from, to)
+	if len(columns) > 0 {
+		query = query.Group(strings.Join(columns, ", "))
+	}
+
+	var summary []models.APIUsageSummary
+	err := query.Order("requests DESC").Limit(limit).Scan(&summary).Error
+	return summary, err
+}
+
+// CountRequests menghitung request seorang pengguna ke sebuah endpoint sejak waktu tertentu,
+// tanpa request yang ditolak kuota
+func (r *usageRepository) CountRequests(route string, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.APIUsageRollup{}).
+		Select("COALESCE(SUM(requests - throttled), 0)").
+		Where("route = ? AND user_id = ? AND bucket >= ?", route, userID, since).
+		Scan(&count).Error
+	return count, err
+}
+
+// FindQuotas mengambil semua kuota endpoint
+func (r *usageRepository) FindQuotas() ([]models.UsageQuota, error) {
+	var quotas []models.UsageQuota
+	err := r.db.Order("route, role").Find(&quotas).Error
+	return quotas, err
+}
+
+// FindQuotaByID mencari kuota berdasarkan ID
+func (r *usageRepository) FindQuotaByID(id uint) (*models.UsageQuota, error) {
+	var quota models.UsageQuota
+	if err := r.db.Where("id = ?", id).First(&quota).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// SaveQuota menyimpan kuota sebuah endpoint untuk sebuah peran, menggantikan kuota yang ada
+func (r *usageRepository) SaveQuota(quota *models.UsageQuota) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "route"}, {Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_requests", "quota_window", "updated_by", "updated_at"}),
+	}).Create(quota).Error
+}
+
+// DeleteQuota menghapus kuota endpoint
+func (r *usageRepository) DeleteQuota(id uint) error {
+	return r.db.Delete(&models.UsageQuota{}, id).Error
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"delpresence-api/internal/models"
+	"delpresence-api/internal/utils"
 	"delpresence-api/pkg/database"
 
 	"gorm.io/gorm"
@@ -52,6 +53,20 @@ func (r *UserRepository) GetUserByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+// ListUsers retrieves one page of users and the number of users matching the request
+func (r *UserRepository) ListUsers(query utils.PageQuery) ([]models.User, int64, error) {
+	var total int64
+	if err := r.DB.Model(&models.User{}).Scopes(query.Filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	if err := r.DB.Scopes(utils.Paginate(query)).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 // GetUserByEmail retrieves a user by email
 func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserRoleRepository adalah interface untuk operasi repository peran pengguna
+type UserRoleRepository interface {
+	FindByUserID(source models.IdentitySource, userID uint) ([]models.UserRole, error)
+	Assign(role *models.UserRole) error
+	SetDefault(source models.IdentitySource, userID uint, role models.UserType) error
+	Remove(source models.IdentitySource, userID uint, role models.UserType) error
+}
+
+// userRoleRepository implementasi dari UserRoleRepository
+type userRoleRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRoleRepository membuat instance baru dari UserRoleRepository
+func NewUserRoleRepository(db *gorm.DB) UserRoleRepository {
+	return &userRoleRepository{
+		db: db,
+	}
+}
+
+// FindByUserID mengambil semua peran yang dimiliki user dari sumber identitas tertentu
+func (r *userRoleRepository) FindByUserID(source models.IdentitySource, userID uint) ([]models.UserRole, error) {
+	var roles []models.UserRole
+	if err := r.db.Where("source = ? AND user_id = ?", source, userID).Order("is_default DESC, created_at ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Assign menambahkan peran ke user, memperbarui profil jika peran sudah ada.
+// Peran pertama yang dimiliki user otomatis menjadi peran default.
+func (r *userRoleRepository) Assign(role *models.UserRole) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.UserRole{}).Where("source = ? AND user_id = ?", role.Source, role.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			role.IsDefault = true
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source"}, {Name: "user_id"}, {Name: "role"}},
+			DoUpdates: clause.AssignmentColumns([]string{"profile_id", "updated_at"}),
+		}).Create(role).Error
+	})
+}
+
+// SetDefault menjadikan salah satu peran user sebagai peran default
+func (r *userRoleRepository) SetDefault(source models.IdentitySource, userID uint, role models.UserType) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserRole{}).Where("source = ? AND user_id = ?", source, userID).Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.UserRole{}).Where("source = ? AND user_id = ? AND role = ?", source, userID, role).Update("is_default", true).Error
+	})
+}
+
+// Remove menghapus peran dari user
+func (r *userRoleRepository) Remove(source models.IdentitySource, userID uint, role models.UserType) error {
+	return r.db.Where("source = ? AND user_id = ? AND role = ?", source, userID, role).Delete(&models.UserRole{}).Error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// warehouseBatchSize is how many snapshots of students are read at a time
+const warehouseBatchSize = 1000
+
+// WarehouseRepository adalah interface untuk operasi repository snapshot data warehouse
+type WarehouseRepository interface {
+	FindRecent(limit int) ([]models.WarehouseSnapshot, error)
+	Create(snapshot *models.WarehouseSnapshot) error
+	Update(snapshot *models.WarehouseSnapshot) error
+	EachFact(from, to time.Time, fn func(models.WarehouseAttendanceFact) error) error
+	EachStudent(fn func([]models.MahasiswaSnapshot) error) error
+	FindCourses() ([]models.Schedule, error)
+}
+
+// warehouseRepository implementasi dari WarehouseRepository
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+// NewWarehouseRepository membuat instance baru dari WarehouseRepository
+func NewWarehouseRepository(db *gorm.DB) WarehouseRepository {
+	return &warehouseRepository{
+		db: db,
+	}
+}
+
+// FindRecent mengambil snapshot terbaru
+func (r *warehouseRepository) FindRecent(limit int) ([]models.WarehouseSnapshot, error) {
+	var snapshots []models.WarehouseSnapshot
+	if err := r.db.Order("started_at DESC").Limit(limit).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// Create menyimpan snapshot baru
+func (r *warehouseRepository) Create(snapshot *models.WarehouseSnapshot) error {
+	return r.db.Create(snapshot).Error
+}
+
+// Update memperbarui data snapshot
+func (r *warehouseRepository) Update(snapshot *models.WarehouseSnapshot) error {
+	return r.db.Save(snapshot).Error
+}
+
+// EachFact membaca presensi setiap mahasiswa pada sesi yang dibuka dalam rentang [from, to),
+// termasuk mahasiswa terdaftar yang tidak hadir, satu baris per panggilan fn
+func (r *warehouseRepository) EachFact(from, to time.Time, fn func(models.WarehouseAttendanceFact) error) error {
+	rows, err := r.db.Raw(`WITH sessions AS (
+			SELECT * FROM attendance_sessions
+			WHERE deleted_at IS NULL AND status <> ? AND opened_at >= ? AND opened_at < ?
+		),
+		roster AS (
+			SELECT s.id AS session_id, e.nim
+			FROM sessions s
+			JOIN enrollments e ON e.course_code = s.course_code
+				AND (s.class_name = '' OR e.class_name = s.class_name)
+				AND (s.semester = '' OR e.semester = s.semester)
+			UNION
+			SELECT r.session_id, r.nim FROM attendance_records r JOIN sessions s ON s.id = r.session_id
+		)
+		SELECT s.id AS session_id, s.opened_at, s.course_code, s.class_name, s.semester, s.meeting_number,
+			s.optional, s.lecturer_user_id, roster.nim, COALESCE(r.status, ?) AS status, r.method,
+			COALESCE(r.late_minutes, 0) AS late_minutes, COALESCE(r.credit, 0) AS credit,
+			COALESCE(r.overflow, false) AS overflow, r.checked_in_at
+		FROM roster
+		JOIN sessions s ON s.id = roster.session_id
+		LEFT JOIN attendance_records r ON r.session_id = s.id AND r.nim = roster.nim
+		ORDER BY s.opened_at ASC, s.id ASC, roster.nim ASC`,
+		models.SessionScheduled, from, to, models.AttendanceAbsent).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fact models.WarehouseAttendanceFact
+		if err := r.db.ScanRows(rows, &fact); err != nil {
+			return err
+		}
+		if err := fn(fact); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// EachStudent membaca snapshot data kampus semua mahasiswa per batch
+func (r *warehouseRepository) EachStudent(fn func([]models.MahasiswaSnapshot) error) error {
+	var batch []models.MahasiswaSnapshot
+	return r.db.FindInBatches(&batch, warehouseBatchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// FindCourses mengambil semua jadwal kelas sebagai dimensi mata kuliah
+func (r *warehouseRepository) FindCourses() ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := r.db.Order("semester ASC, course_code ASC, class_name ASC, id ASC").Find(&schedules).Error
+	return schedules, err
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrWorkflowStateChanged dikembalikan ketika workflow sudah berpindah state oleh permintaan lain
+var ErrWorkflowStateChanged = errors.New("workflow state was changed by another request")
+
+// WorkflowRepository adalah interface untuk operasi repository workflow persetujuan
+type WorkflowRepository interface {
+	FindBySubject(workflowType string, subjectID uint) (*models.WorkflowInstance, error)
+	FindOverdue(now time.Time) ([]models.WorkflowInstance, error)
+	FindDueForReminder(now time.Time) ([]models.WorkflowInstance, error)
+	FindTransitions(instanceID uint) ([]models.WorkflowTransition, error)
+	Create(instance *models.WorkflowInstance, transition *models.WorkflowTransition) error
+	Transition(instance *models.WorkflowInstance, fromState string, transition *models.WorkflowTransition) error
+	MarkReminded(instanceID uint, at time.Time) error
+	MarkEscalated(instanceID uint, at time.Time) error
+	SLACompliance(workflowType string, from, to time.Time) ([]models.SLAComplianceRow, error)
+	CountPendingByAssignee(assigneeUserID uint) ([]models.PendingApprovalCount, error)
+}
+
+// workflowRepository implementasi dari WorkflowRepository
+type workflowRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkflowRepository membuat instance baru dari WorkflowRepository
+func NewWorkflowRepository(db *gorm.DB) WorkflowRepository {
+	return &workflowRepository{
+		db: db,
+	}
+}
+
+// FindBySubject mencari workflow dari item yang sedang disetujui
+func (r *workflowRepository) FindBySubject(workflowType string, subjectID uint) (*models.WorkflowInstance, error) {
+	var instance models.WorkflowInstance
+	if err := r.db.Where("type = ? AND subject_id = ?", workflowType, subjectID).First(&instance).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// FindOverdue mengambil workflow yang melewati SLA dan belum dieskalasi
+func (r *workflowRepository) FindOverdue(now time.Time) ([]models.WorkflowInstance, error) {
+	var instances []models.WorkflowInstance
+	err := r.db.Where("completed_at IS NULL AND escalated_at IS NULL AND due_at < ?", now).
+		Order("due_at ASC").
+		Find(&instances).Error
+	return instances, err
+}
+
+// FindDueForReminder mengambil workflow yang sudah waktunya diingatkan dan belum diingatkan
+func (r *workflowRepository) FindDueForReminder(now time.Time) ([]models.WorkflowInstance, error) {
+	var instances []models.WorkflowInstance
+	err := r.db.Where("completed_at IS NULL AND reminded_at IS NULL AND escalated_at IS NULL AND remind_at < ?", now).
+		Order("remind_at ASC").
+		Find(&instances).Error
+	return instances, err
+}
+
+// FindTransitions mengambil riwayat perpindahan state sebuah workflow
+func (r *workflowRepository) FindTransitions(instanceID uint) ([]models.WorkflowTransition, error) {
+	var transitions []models.WorkflowTransition
+	err := r.db.Where("instance_id = ?", instanceID).Order("created_at ASC, id ASC").Find(&transitions).Error
+	return transitions, err
+}
+
+// Create menyimpan workflow baru beserta perpindahan ke state awalnya
+func (r *workflowRepository) Create(instance *models.WorkflowInstance, transition *models.WorkflowTransition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(instance).Error; err != nil {
+			return err
+		}
+		transition.InstanceID = instance.ID
+		return tx.Create(transition).Error
+	})
+}
+
+// Transition memindahkan workflow dari fromState dan mencatat riwayatnya.
+// Jika state sudah berubah sejak dibaca, ErrWorkflowStateChanged dikembalikan.
+func (r *workflowRepository) Transition(instance *models.WorkflowInstance, fromState string, transition *models.WorkflowTransition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.WorkflowInstance{}).
+			Where("id = ? AND state = ?", instance.ID, fromState).
+			Updates(map[string]interface{}{
+				"state":            instance.State,
+				"assignee_user_id": instance.AssigneeUserID,
+				"state_entered_at": instance.StateEnteredAt,
+				"due_at":           instance.DueAt,
+				"remind_at":        instance.RemindAt,
+				"reminded_at":      instance.RemindedAt,
+				"escalated_at":     instance.EscalatedAt,
+				"breached":         instance.Breached,
+				"completed_at":     instance.CompletedAt,
+				"updated_at":       time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrWorkflowStateChanged
+		}
+		transition.InstanceID = instance.ID
+		return tx.Create(transition).Error
+	})
+}
+
+// MarkReminded menandai workflow sudah diingatkan agar tidak diingatkan ulang
+func (r *workflowRepository) MarkReminded(instanceID uint, at time.Time) error {
+	return r.db.Model(&models.WorkflowInstance{}).Where("id = ?", instanceID).Update("reminded_at", at).Error
+}
+
+// MarkEscalated menandai workflow sudah dieskalasi dan melanggar SLA agar tidak dieskalasi ulang
+func (r *workflowRepository) MarkEscalated(instanceID uint, at time.Time) error {
+	return r.db.Model(&models.WorkflowInstance{}).Where("id = ?", instanceID).
+		Updates(map[string]interface{}{"escalated_at": at, "breached": true}).Error
+}
+
+// SLACompliance merangkum kepatuhan SLA per jenis workflow yang dimulai dalam rentang waktu.
+// workflowType kosong berarti semua jenis.
+func (r *workflowRepository) SLACompliance(workflowType string, from, to time.Time) ([]models.SLAComplianceRow, error) {
+	var rows []models.SLAComplianceRow
+
+	query := r.db.Model(&models.WorkflowInstance{}).
+		Select(`type,
+			COUNT(*) AS total,
+			COUNT(completed_at) AS completed,
+			COUNT(*) FILTER (WHERE completed_at IS NOT NULL AND NOT breached) AS completed_in_sla,
+			COUNT(*) FILTER (WHERE breached) AS breached,
+			COUNT(*) FILTER (WHERE completed_at IS NULL AND due_at < ?) AS open_overdue,
+			COALESCE(AVG(EXTRACT(EPOCH FROM (completed_at - created_at)) / 3600), 0) AS avg_decision_hours`, time.Now()).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("type").
+		Order("type ASC")
+	if workflowType != "" {
+		query = query.Where("type = ?", workflowType)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		if rows[i].Completed > 0 {
+			rows[i].CompliancePercent = float64(rows[i].CompletedInSLA) * 100 / float64(rows[i].Completed)
+		}
+	}
+	return rows, nil
+}
+
+// CountPendingByAssignee menghitung workflow yang belum selesai per jenis untuk seorang penanggung jawab
+func (r *workflowRepository) CountPendingByAssignee(assigneeUserID uint) ([]models.PendingApprovalCount, error) {
+	var counts []models.PendingApprovalCount
+	err := r.db.Model(&models.WorkflowInstance{}).
+		Select("type, COUNT(*) AS count").
+		Where("assignee_user_id = ? AND completed_at IS NULL", assigneeUserID).
+		Group("type").
+		Order("type").
+		Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// anyDom and anyDow are set when a day field is "*"; days then match on the other field
+	// alone instead of on either of them
+	anyDom bool
+	anyDow bool
+}
+
+// field describes the range of one cron field
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // Both 0 and 7 are Sunday
+}
+
+// shorthands maps the named schedules to their cron expressions
+var shorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@nightly": "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Parse parses a five-field cron expression (minute, hour, day of month, month, day of week)
+// or one of @hourly, @daily, @weekly and @monthly. Fields accept "*", numbers, ranges such as
+// "1-5", lists such as "1,15" and steps such as "*/15" or "8-18/2".
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	expr := spec
+	if expanded, ok := shorthands[expr]; ok {
+		expr = expanded
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected %d fields", spec, len(fields))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be written as 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Schedule{
+		spec:   spec,
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDom: parts[2] == "*",
+		anyDow: parts[4] == "*",
+	}, nil
+}
+
+// parseField parses one comma-separated cron field into a bit set of the values it matches
+func parseField(value string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(value, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			parsed, err := strconv.Atoi(item[i+1:])
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, item)
+			}
+			rangePart, step = item[:i], parsed
+		}
+
+		start, end := f.min, f.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid %s field %q", f.name, item)
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid %s field %q", f.name, item)
+				}
+			} else if step > 1 {
+				// "5/15" means from 5 to the end of the range every 15
+				end = f.max
+			}
+		}
+		if start < f.min || end > f.max || start > end {
+			return 0, fmt.Errorf("%s field %q is out of range %d-%d", f.name, item, f.min, f.max)
+		}
+
+		for v := start; v <= end; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t the schedule matches, to the minute, in t's location
+func (s *Schedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	// Every valid expression matches within a few years; the bound only guards against
+	// expressions such as February 30th that never match
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if s.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// matchesDay applies cron's day rule: when both day fields are restricted, a day matching
+// either of them matches
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dow
+	case s.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
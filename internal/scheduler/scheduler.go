@@ -0,0 +1,203 @@
+// Package scheduler runs the recurring background jobs of the API, such as purging expired
+// tokens and nightly syncs, on cron-style schedules. Each job's schedule can be overridden or
+// turned off with a SCHEDULE_<NAME> variable, e.g. SCHEDULE_TOKEN_PURGE="*/30 * * * *" or "off".
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job is the work run on each tick of a schedule. ctx is cancelled when the scheduler stops.
+type Job func(ctx context.Context) error
+
+// JobStatus describes a registered job and its latest run
+type JobStatus struct {
+	Name         string     `json:"name"`
+	Schedule     string     `json:"schedule"`
+	NextRunAt    *time.Time `json:"next_run_at"`
+	LastRunAt    *time.Time `json:"last_run_at"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	Running      bool       `json:"running"`
+}
+
+// entry is a registered job with its schedule and latest run
+type entry struct {
+	name     string
+	schedule *Schedule
+	job      Job
+	status   JobStatus
+}
+
+// Scheduler runs registered jobs on their schedules. A job never overlaps itself: a tick
+// that comes while the previous run is still going is skipped.
+type Scheduler struct {
+	mutex     sync.Mutex
+	entries   map[string]*entry
+	overrides map[string]string
+	now       func() time.Time
+}
+
+// New creates an empty Scheduler. overrides replaces the default schedule of a job, by its
+// name (from SCHEDULE_<NAME>).
+func New(overrides map[string]string) *Scheduler {
+	return &Scheduler{
+		entries:   make(map[string]*entry),
+		overrides: overrides,
+		now:       time.Now,
+	}
+}
+
+// Add registers a job under a snake_case name with its default schedule, which the configured
+// override for the name replaces. A job whose schedule is "off" is not registered.
+func (s *Scheduler) Add(name, defaultSpec string, job Job) error {
+	spec := defaultSpec
+	if value := strings.TrimSpace(s.overrides[name]); value != "" {
+		spec = value
+	}
+	if strings.EqualFold(spec, "off") {
+		log.Printf("[SCHEDULER] Job %s is turned off", name)
+		return nil
+	}
+
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, exists := s.entries[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+	s.entries[name] = &entry{
+		name:     name,
+		schedule: schedule,
+		job:      job,
+		status:   JobStatus{Name: name, Schedule: schedule.String()},
+	}
+	return nil
+}
+
+// Run runs the registered jobs until stop is closed, then cancels the context of the runs in
+// progress and waits for them to return
+func (s *Scheduler) Run(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var running sync.WaitGroup
+	defer running.Wait()
+	defer cancel()
+
+	for {
+		now := s.now()
+		next := s.dueAt(now)
+		if next.IsZero() {
+			// Nothing to run; wait for shutdown
+			<-stop
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for _, e := range s.due(s.now()) {
+			running.Add(1)
+			go func(e *entry) {
+				defer running.Done()
+				s.runEntry(ctx, e)
+			}(e)
+		}
+	}
+}
+
+// dueAt returns when the next job is due, scheduling the jobs that have no next run yet
+func (s *Scheduler) dueAt(now time.Time) time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var earliest time.Time
+	for _, e := range s.entries {
+		if e.status.NextRunAt == nil {
+			next := e.schedule.Next(now)
+			if next.IsZero() {
+				continue
+			}
+			e.status.NextRunAt = &next
+		}
+		if earliest.IsZero() || e.status.NextRunAt.Before(earliest) {
+			earliest = *e.status.NextRunAt
+		}
+	}
+	return earliest
+}
+
+// due returns the jobs whose next run has come and schedules their following run. Jobs still
+// running are skipped until their following run.
+func (s *Scheduler) due(now time.Time) []*entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var due []*entry
+	for _, e := range s.entries {
+		if e.status.NextRunAt == nil || e.status.NextRunAt.After(now) {
+			continue
+		}
+		next := e.schedule.Next(now)
+		e.status.NextRunAt = &next
+		if next.IsZero() {
+			e.status.NextRunAt = nil
+		}
+		if e.status.Running {
+			log.Printf("[SCHEDULER] Skipping %s, the previous run has not finished", e.name)
+			continue
+		}
+		e.status.Running = true
+		due = append(due, e)
+	}
+	return due
+}
+
+// runEntry runs a job once and records the outcome
+func (s *Scheduler) runEntry(ctx context.Context, e *entry) {
+	started := s.now()
+	err := e.job(ctx)
+	duration := s.now().Sub(started)
+	if err != nil {
+		log.Printf("[SCHEDULER] Job %s failed after %s: %v", e.name, duration, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	e.status.Running = false
+	e.status.LastRunAt = &started
+	e.status.LastDuration = duration.Round(time.Millisecond).String()
+	e.status.LastError = ""
+	if err != nil {
+		e.status.LastError = err.Error()
+	}
+}
+
+// Status lists the registered jobs by name with their latest run
+func (s *Scheduler) Status() []JobStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.entries))
+	for _, e := range s.entries {
+		statuses = append(statuses, e.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
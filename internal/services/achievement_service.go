@@ -0,0 +1,201 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"delpresence-api/internal/features"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// perfectAttendanceMinMeetings is how many meetings a semester needs before attending all
+// of them earns the perfect attendance badge
+const perfectAttendanceMinMeetings = 10
+
+// AchievementService computes attendance streaks, goal progress and badges for students
+// in prodi where gamification is enabled
+type AchievementService struct {
+	achievementRepo repository.AchievementRepository
+	mahasiswaRepo   repository.MahasiswaRepository
+	features        *features.Flags
+	hour            int
+}
+
+// NewAchievementService creates a new AchievementService whose nightly computation runs at
+// hour (0-23) local time
+func NewAchievementService(achievementRepo repository.AchievementRepository, mahasiswaRepo repository.MahasiswaRepository, flags *features.Flags, hour int) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		mahasiswaRepo:   mahasiswaRepo,
+		features:        flags,
+		hour:            hour,
+	}
+}
+
+// GoalFor returns the attendance goal of a prodi in a semester, preferring the most specific
+// goal, or nil when none applies
+func GoalFor(goals []models.AttendanceGoal, prodi, semester string) *models.AttendanceGoal {
+	var best *models.AttendanceGoal
+	bestScore := -1
+	for i, goal := range goals {
+		if (goal.Prodi != "" && goal.Prodi != prodi) || (goal.Semester != "" && goal.Semester != semester) {
+			continue
+		}
+		score := 0
+		if goal.Prodi != "" {
+			score += 2
+		}
+		if goal.Semester != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = &goals[i], score
+		}
+	}
+	return best
+}
+
+// studentProdi returns the prodi of a student from their synced profile
+func (s *AchievementService) studentProdi(nim string) string {
+	snapshot, err := s.mahasiswaRepo.FindSnapshotByNIM(nim)
+	if err != nil || snapshot == nil {
+		return ""
+	}
+	complete, err := snapshot.ToMahasiswaComplete()
+	if err != nil {
+		return ""
+	}
+	return complete.BasicInfo.ProdiName
+}
+
+// Compute recalculates the achievements of every student and awards new badges
+func (s *AchievementService) Compute() {
+	rows, err := s.achievementRepo.FindMeetingAttendance()
+	if err != nil {
+		log.Printf("[ACHIEVEMENTS] Failed to load attendance: %v", err)
+		return
+	}
+	goals, err := s.achievementRepo.FindGoals()
+	if err != nil {
+		log.Printf("[ACHIEVEMENTS] Failed to load attendance goals: %v", err)
+		return
+	}
+
+	now := time.Now()
+	prodis := map[string]string{}
+	computed := 0
+
+	// Rows are ordered by student, semester and meeting time
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].Nim == rows[start].Nim && rows[end].Semester == rows[start].Semester {
+			end++
+		}
+		meetings := rows[start:end]
+		start = end
+
+		nim, semester := meetings[0].Nim, meetings[0].Semester
+		prodi, ok := prodis[nim]
+		if !ok {
+			prodi = s.studentProdi(nim)
+			prodis[nim] = prodi
+		}
+		if !s.features.EnabledFor(features.Gamification, features.Caller{Role: string(models.StudentType), Prodi: prodi}) {
+			continue
+		}
+
+		achievement := summarizeMeetings(meetings)
+		achievement.ComputedAt = now
+		if goal := GoalFor(goals, prodi, semester); goal != nil {
+			achievement.GoalPercent = goal.TargetPercent
+			achievement.GoalMet = achievement.WeightedPercent >= goal.TargetPercent
+		}
+		if err := s.achievementRepo.SaveAchievement(achievement); err != nil {
+			log.Printf("[ACHIEVEMENTS] Failed to save achievements of %s: %v", nim, err)
+			continue
+		}
+
+		for _, badge := range earnedBadges(achievement) {
+			if err := s.achievementRepo.AwardBadge(&models.StudentBadge{Nim: nim, Semester: semester, Badge: badge, AwardedAt: now}); err != nil {
+				log.Printf("[ACHIEVEMENTS] Failed to award %s to %s: %v", badge, nim, err)
+			}
+		}
+		computed++
+	}
+
+	log.Printf("[ACHIEVEMENTS] Computed achievements for %d student semesters", computed)
+}
+
+// summarizeMeetings counts attendance and streaks over a student's meetings of one semester.
+// Excused meetings neither extend nor break a streak.
+func summarizeMeetings(meetings []models.MeetingAttendance) *models.StudentAchievement {
+	achievement := &models.StudentAchievement{
+		Nim:      meetings[0].Nim,
+		Semester: meetings[0].Semester,
+		Meetings: len(meetings),
+	}
+
+	credit := 0.0
+	for _, meeting := range meetings {
+		credit += meeting.Credit
+		switch meeting.Status {
+		case models.AttendancePresent, models.AttendanceLate:
+			achievement.Attended++
+			achievement.CurrentStreak++
+			if achievement.CurrentStreak > achievement.LongestStreak {
+				achievement.LongestStreak = achievement.CurrentStreak
+			}
+		case models.AttendanceExcused:
+		default:
+			achievement.CurrentStreak = 0
+		}
+	}
+	achievement.WeightedPercent = float64(int(credit*10000/float64(len(meetings))+0.5)) / 100
+	return achievement
+}
+
+// earnedBadges lists the badges an achievement qualifies for
+func earnedBadges(achievement *models.StudentAchievement) []models.Badge {
+	var badges []models.Badge
+	if achievement.Attended > 0 {
+		badges = append(badges, models.FirstCheckInBadge)
+	}
+	if achievement.LongestStreak >= 5 {
+		badges = append(badges, models.Streak5Badge)
+	}
+	if achievement.LongestStreak >= 10 {
+		badges = append(badges, models.Streak10Badge)
+	}
+	if achievement.GoalMet {
+		badges = append(badges, models.GoalReachedBadge)
+	}
+	if achievement.Meetings >= perfectAttendanceMinMeetings && achievement.Attended == achievement.Meetings {
+		badges = append(badges, models.PerfectAttendanceBadge)
+	}
+	return badges
+}
+
+// nextAchievementRun returns the next time after now at which achievements are computed
+func (s *AchievementService) nextAchievementRun(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunNightly computes achievements once a night until stop is closed; a nil stop runs for
+// the lifetime of the process
+func (s *AchievementService) RunNightly(stop <-chan struct{}) {
+	for {
+		timer := time.NewTimer(time.Until(s.nextAchievementRun(time.Now())))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.Compute()
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// ApprovalRouter decides who handles an approval, following active delegations. Routing is
+// evaluated on every call, so it returns to the delegator as soon as a delegation ends.
+type ApprovalRouter struct {
+	delegationRepo repository.DelegationRepository
+}
+
+// NewApprovalRouter creates a new ApprovalRouter
+func NewApprovalRouter(delegationRepo repository.DelegationRepository) *ApprovalRouter {
+	return &ApprovalRouter{delegationRepo: delegationRepo}
+}
+
+// activeDelegations loads today's delegations covering scope
+func (r *ApprovalRouter) activeDelegations(scope models.ApprovalScope) []models.ApprovalDelegation {
+	now := time.Now()
+	delegations, err := r.delegationRepo.FindActive(now)
+	if err != nil {
+		log.Printf("[APPROVALS] Failed to load delegations: %v", err)
+		return nil
+	}
+
+	active := delegations[:0]
+	for _, delegation := range delegations {
+		if delegation.Covers(scope, now) {
+			active = append(active, delegation)
+		}
+	}
+	return active
+}
+
+// Approver returns who currently handles approvals of scope owned by ownerUserID
+func (r *ApprovalRouter) Approver(ownerUserID uint, scope models.ApprovalScope) uint {
+	for _, delegation := range r.activeDelegations(scope) {
+		if delegation.DelegatorUserID == ownerUserID {
+			return delegation.DelegateUserID
+		}
+	}
+	return ownerUserID
+}
+
+// DelegatorsOf returns the lecturers whose approvals of scope are currently routed to userID
+func (r *ApprovalRouter) DelegatorsOf(userID uint, scope models.ApprovalScope) []uint {
+	var delegators []uint
+	for _, delegation := range r.activeDelegations(scope) {
+		if delegation.DelegateUserID == userID {
+			delegators = append(delegators, delegation.DelegatorUserID)
+		}
+	}
+	return delegators
+}
+
+// CanApprove checks whether userID may decide an approval of scope owned by ownerUserID.
+// Owners keep their own duties while delegated, so they can still act when back early.
+func (r *ApprovalRouter) CanApprove(userID, ownerUserID uint, scope models.ApprovalScope) bool {
+	return userID == ownerUserID || r.Approver(ownerUserID, scope) == userID
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// MaxAttachmentSize is the largest supporting document accepted, in bytes
+const MaxAttachmentSize = 5 << 20
+
+// attachmentExtensions maps the accepted document types to their file extension
+var attachmentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// ErrInvalidAttachment is returned for a document that is too large or of an unsupported type
+var ErrInvalidAttachment = errors.New("attachment must be a PDF, JPEG or PNG file of at most 5 MB")
+
+// AttachmentStore keeps uploaded documents, such as sick notes and session handouts, on local disk
+type AttachmentStore struct {
+	dir string
+}
+
+// NewAttachmentStore creates a new AttachmentStore writing to dir
+func NewAttachmentStore(dir string) *AttachmentStore {
+	return &AttachmentStore{dir: dir}
+}
+
+// Save stores a document under a random name and returns the name and detected content type
+func (s *AttachmentStore) Save(r io.Reader) (string, string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxAttachmentSize+1))
+	if err != nil {
+		return "", "", err
+	}
+	if len(data) == 0 || len(data) > MaxAttachmentSize {
+		return "", "", ErrInvalidAttachment
+	}
+
+	// The declared type is ignored; only the content decides what was uploaded
+	contentType := http.DetectContentType(data)
+	extension, ok := attachmentExtensions[contentType]
+	if !ok {
+		return "", "", ErrInvalidAttachment
+	}
+
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return "", "", fmt.Errorf("failed to create attachment directory: %v", err)
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+	name := hex.EncodeToString(random) + extension
+
+	file, err := os.OpenFile(filepath.Join(s.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, bytes.NewReader(data)); err != nil {
+		return "", "", err
+	}
+	return name, contentType, nil
+}
+
+// Path returns where a stored document is kept
+func (s *AttachmentStore) Path(name string) string {
+	return filepath.Join(s.dir, filepath.Base(name))
+}
+
+// Delete removes a stored document, e.g. when saving the request it belongs to failed
+func (s *AttachmentStore) Delete(name string) error {
+	return os.Remove(s.Path(name))
+}
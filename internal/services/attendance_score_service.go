@@ -0,0 +1,64 @@
+package services
+
+import (
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// AttendanceScoreService turns the attendance of a course into the attendance component of the
+// final grade according to the course's score policy
+type AttendanceScoreService struct {
+	policyRepo     repository.AttendanceScoreRepository
+	attendanceRepo repository.AttendanceRepository
+}
+
+// NewAttendanceScoreService creates a new AttendanceScoreService
+func NewAttendanceScoreService(policyRepo repository.AttendanceScoreRepository, attendanceRepo repository.AttendanceRepository) *AttendanceScoreService {
+	return &AttendanceScoreService{
+		policyRepo:     policyRepo,
+		attendanceRepo: attendanceRepo,
+	}
+}
+
+// roundScore rounds a score to two decimals
+func roundScore(score float64) float64 {
+	return float64(int(score*100+0.5)) / 100
+}
+
+// ForCourse returns the attendance score of every student of a course from the weighted
+// percentage of its recap, or nil when the course has no sessions yet
+func (s *AttendanceScoreService) ForCourse(filter models.AttendanceRecapFilter) (*models.AttendanceScoreList, error) {
+	policy, err := s.policyRepo.FindForCourse(filter.CourseCode)
+	if err != nil {
+		return nil, err
+	}
+	recap, err := s.attendanceRepo.CourseRecap(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(recap.Meetings) == 0 {
+		return nil, nil
+	}
+
+	list := &models.AttendanceScoreList{
+		CourseCode: recap.CourseCode,
+		Semester:   recap.Semester,
+		ClassName:  recap.ClassName,
+		Formula:    models.LinearScore,
+		Weight:     policy.WeightOrDefault(),
+		Students:   make([]models.AttendanceScoreStudent, 0, len(recap.Students)),
+	}
+	if policy != nil {
+		list.Formula = policy.Formula
+	}
+	for _, student := range recap.Students {
+		score := roundScore(policy.Score(student.WeightedPercent))
+		list.Students = append(list.Students, models.AttendanceScoreStudent{
+			Nim:             student.Nim,
+			WeightedPercent: student.WeightedPercent,
+			Score:           score,
+			WeightedScore:   roundScore(score * list.Weight / 100),
+		})
+	}
+	return list, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"delpresence-api/internal/attestation"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/config"
+)
+
+var (
+	// ErrAttestationUnsupported is returned for platforms whose attestation is not configured
+	ErrAttestationUnsupported = errors.New("device attestation is not configured for this platform")
+	// ErrAppAttestKeyUnknown is returned for assertions of a key the student did not register
+	ErrAppAttestKeyUnknown = errors.New("App Attest key is not registered")
+)
+
+// AttestationEvidence is what the app sends to prove a request comes from a genuine build on a
+// genuine device
+type AttestationEvidence struct {
+	Platform       attestation.Platform `json:"platform"`
+	Challenge      string               `json:"challenge"`       // Issued by GET /mahasiswa/attestation/challenge
+	IntegrityToken string               `json:"integrity_token"` // Android: Play Integrity token requested with the challenge as nonce
+	KeyID          string               `json:"key_id"`          // iOS: registered App Attest key
+	Assertion      string               `json:"assertion"`       // iOS: base64 assertion of the challenge
+}
+
+// AttestationService issues attestation challenges and verifies attestations made with them
+type AttestationService struct {
+	keyRepo       repository.AppAttestKeyRepository
+	challenges    *attestation.Challenges
+	playIntegrity *attestation.PlayIntegrity // nil when Android attestation is not configured
+	appAttest     *attestation.AppAttest     // nil when iOS attestation is not configured
+}
+
+// NewAttestationService creates a new AttestationService. Challenges are signed with secret;
+// each platform is verified once its settings are configured.
+func NewAttestationService(cfg config.AttestationConfig, secret string, keyRepo repository.AppAttestKeyRepository) (*AttestationService, error) {
+	service := &AttestationService{
+		keyRepo:    keyRepo,
+		challenges: attestation.NewChallenges(secret),
+	}
+
+	var err error
+	if cfg.AndroidPackage != "" && cfg.PlayIntegrityCredentials != "" {
+		if service.playIntegrity, err = attestation.NewPlayIntegrity(cfg.AndroidPackage, cfg.PlayIntegrityCredentials); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.AppleAppID != "" && cfg.AppAttestRootCA != "" {
+		if service.appAttest, err = attestation.NewAppAttest(cfg.AppleAppID, cfg.AppAttestRootCA, cfg.AppAttestDevelopment); err != nil {
+			return nil, err
+		}
+	}
+	return service, nil
+}
+
+// Supports reports whether attestations of a platform can be verified
+func (s *AttestationService) Supports(platform attestation.Platform) bool {
+	switch platform {
+	case attestation.Android:
+		return s.playIntegrity != nil
+	case attestation.IOS:
+		return s.appAttest != nil
+	}
+	return false
+}
+
+// Challenge issues a challenge for the next attestation of a student
+func (s *AttestationService) Challenge(userID uint) (string, error) {
+	return s.challenges.Issue(userID)
+}
+
+// RegisterAppAttestKey verifies the attestation of a new App Attest key made with a challenge
+// issued to the student and stores the key for verifying the student's check-ins
+func (s *AttestationService) RegisterAppAttestKey(userID uint, keyID, encodedAttestation, challenge string) (*models.AppAttestKey, error) {
+	if s.appAttest == nil {
+		return nil, ErrAttestationUnsupported
+	}
+	if err := s.challenges.Validate(challenge, userID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.keyRepo.FindByKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: key is already registered", attestation.ErrRejected)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encodedAttestation)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestation is not valid base64", attestation.ErrRejected)
+	}
+	publicKey, err := s.appAttest.VerifyAttestation(keyID, raw, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.AppAttestKey{
+		StudentUserID: userID,
+		KeyID:         keyID,
+		PublicKey:     publicKey,
+	}
+	if err := s.keyRepo.Create(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Verify checks an attestation made by a student with a challenge issued to them
+func (s *AttestationService) Verify(ctx context.Context, userID uint, evidence AttestationEvidence) error {
+	if !s.Supports(evidence.Platform) {
+		return ErrAttestationUnsupported
+	}
+	if err := s.challenges.Validate(evidence.Challenge, userID); err != nil {
+		return err
+	}
+
+	if evidence.Platform == attestation.Android {
+		return s.playIntegrity.Verify(ctx, evidence.IntegrityToken, evidence.Challenge)
+	}
+
+	key, err := s.keyRepo.FindByKeyID(evidence.KeyID)
+	if err != nil {
+		return err
+	}
+	if key == nil || key.StudentUserID != userID {
+		return ErrAppAttestKeyUnknown
+	}
+
+	assertion, err := base64.StdEncoding.DecodeString(evidence.Assertion)
+	if err != nil {
+		return fmt.Errorf("%w: assertion is not valid base64", attestation.ErrRejected)
+	}
+	counter, err := s.appAttest.VerifyAssertion(key.PublicKey, key.Counter, assertion, evidence.Challenge)
+	if err != nil {
+		return err
+	}
+
+	// Another request may have used a newer assertion of the key in the meantime
+	advanced, err := s.keyRepo.AdvanceCounter(key.ID, key.Counter, counter)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return fmt.Errorf("%w: assertion was replayed", attestation.ErrRejected)
+	}
+	return nil
+}
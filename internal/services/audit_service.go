@@ -0,0 +1,65 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// AuditEntry describes an action to be written to the audit log
+type AuditEntry struct {
+	ActorUserID uint
+	ActorType   string
+	Action      string
+	EntityType  string
+	EntityID    interface{}
+	Details     map[string]interface{}
+	IPAddress   string
+}
+
+// AuditService writes audit log entries
+type AuditService struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(auditRepo repository.AuditRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record writes an entry to the audit log. Failures are logged but never block the caller.
+func (s *AuditService) Record(entry AuditEntry) {
+	details := ""
+	if len(entry.Details) > 0 {
+		encoded, err := json.Marshal(entry.Details)
+		if err != nil {
+			log.Printf("[AUDIT] Failed to encode details for %s: %v", entry.Action, err)
+		} else {
+			details = string(encoded)
+		}
+	}
+
+	entityID := ""
+	if entry.EntityID != nil {
+		entityID = fmt.Sprint(entry.EntityID)
+	}
+
+	auditLog := &models.AuditLog{
+		ActorUserID: entry.ActorUserID,
+		ActorType:   entry.ActorType,
+		Action:      entry.Action,
+		EntityType:  entry.EntityType,
+		EntityID:    entityID,
+		Details:     details,
+		IPAddress:   entry.IPAddress,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Printf("[AUDIT] Failed to record %s on %s %s: %v", entry.Action, entry.EntityType, entityID, err)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/config"
+)
+
+// backupTimeout bounds how long a single pg_dump may run
+const backupTimeout = 30 * time.Minute
+
+// BackupService creates logical database backups with pg_dump
+type BackupService struct {
+	backupRepo repository.BackupRepository
+	database   config.DatabaseConfig
+	dir        string
+}
+
+// NewBackupService creates a new BackupService writing dumps to dir
+func NewBackupService(backupRepo repository.BackupRepository, database config.DatabaseConfig, dir string) *BackupService {
+	return &BackupService{
+		backupRepo: backupRepo,
+		database:   database,
+		dir:        dir,
+	}
+}
+
+// Trigger records a new backup and runs pg_dump in the background.
+// The returned backup is still running; poll the backup list for the result.
+func (s *BackupService) Trigger(triggeredBy uint) (*models.Backup, error) {
+	if _, err := exec.LookPath("pg_dump"); err != nil {
+		return nil, fmt.Errorf("pg_dump is not installed on this server")
+	}
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %v", err)
+	}
+
+	now := time.Now()
+	backup := &models.Backup{
+		FileName:    fmt.Sprintf("delpresence_%s.dump", now.Format("20060102_150405")),
+		Status:      models.BackupRunning,
+		TriggeredBy: triggeredBy,
+		StartedAt:   now,
+	}
+	if err := s.backupRepo.Create(backup); err != nil {
+		return nil, err
+	}
+
+	go s.run(*backup)
+
+	return backup, nil
+}
+
+// run writes the dump and records its size and checksum
+func (s *BackupService) run(backup models.Backup) {
+	path := filepath.Join(s.dir, backup.FileName)
+
+	err := s.dump(path)
+	if err == nil {
+		backup.SizeBytes, backup.Checksum, err = checksumFile(path)
+	}
+
+	completedAt := time.Now()
+	backup.CompletedAt = &completedAt
+	if err != nil {
+		log.Printf("[BACKUP] Backup %d failed: %v", backup.ID, err)
+		backup.Status = models.BackupFailed
+		backup.Error = err.Error()
+		os.Remove(path)
+	} else {
+		log.Printf("[BACKUP] Backup %d completed (%d bytes)", backup.ID, backup.SizeBytes)
+		backup.Status = models.BackupCompleted
+	}
+
+	if err := s.backupRepo.Update(&backup); err != nil {
+		log.Printf("[BACKUP] Failed to record result of backup %d: %v", backup.ID, err)
+	}
+}
+
+// dump runs pg_dump against the database the API is connected to
+func (s *BackupService) dump(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "pg_dump",
+		"--format=custom",
+		"--no-owner",
+		"--file", path,
+		"--host", s.database.Host,
+		"--port", s.database.Port,
+		"--username", s.database.User,
+		s.database.Name,
+	)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+s.database.Password)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pg_dump: %v: %s", err, output)
+	}
+	return nil
+}
+
+// checksumFile returns the size and SHA-256 of a file
+func checksumFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
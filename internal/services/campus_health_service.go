@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
+)
+
+// campusHealthInterval is how often the health of the campus API is checked
+const campusHealthInterval = 30 * time.Second
+
+// Events of the campus IT webhook
+const (
+	CampusAlertFiring   = "campus_api.alert.firing"
+	CampusAlertReminder = "campus_api.alert.reminder"
+	CampusAlertResolved = "campus_api.alert.resolved"
+)
+
+// CampusHealth is the current health of the campus API as seen by this instance
+type CampusHealth struct {
+	CircuitState utils.CircuitState `json:"circuit_state"`
+	Window       string             `json:"window"`
+	Calls        uint64             `json:"calls"`  // Calls made over the window
+	Failed       uint64             `json:"failed"` // Calls of the window that failed
+	ErrorRate    float64            `json:"error_rate"`
+	Threshold    float64            `json:"threshold"`
+}
+
+// campusCallSample is the breaker's call counters at one check
+type campusCallSample struct {
+	at     time.Time
+	calls  uint64
+	failed uint64
+}
+
+// CampusHealthMonitor notifies campus IT by webhook and email when the circuit breaker to the
+// campus API opens or too many of its calls fail, reminds them while it lasts and tells them once
+// it recovered. Alerts are stored so instances notify each incident once.
+type CampusHealthMonitor struct {
+	alertRepo    repository.IntegrationAlertRepository
+	emailQueue   *EmailQueue
+	cfg          config.CampusAlertConfig
+	breaker      *utils.CircuitBreaker
+	client       *http.Client
+	samples      []campusCallSample
+	healthySince map[models.IntegrationAlertCondition]time.Time
+}
+
+// NewCampusHealthMonitor creates a CampusHealthMonitor watching the campus API circuit breaker
+func NewCampusHealthMonitor(alertRepo repository.IntegrationAlertRepository, emailQueue *EmailQueue, cfg config.CampusAlertConfig, breaker *utils.CircuitBreaker) *CampusHealthMonitor {
+	return &CampusHealthMonitor{
+		alertRepo:    alertRepo,
+		emailQueue:   emailQueue,
+		cfg:          cfg,
+		breaker:      breaker,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		healthySince: make(map[models.IntegrationAlertCondition]time.Time),
+	}
+}
+
+// Run checks the health of the campus API until stop is closed
+func (m *CampusHealthMonitor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(campusHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.check(time.Now())
+		}
+	}
+}
+
+// Health reports the circuit state and the error rate over the alert window. The counters are
+// those of this instance only.
+func (m *CampusHealthMonitor) Health() CampusHealth {
+	calls, failed := m.breaker.Counts()
+	return m.health(time.Now(), calls, failed)
+}
+
+// health compares the counters with the oldest sample still inside the window
+func (m *CampusHealthMonitor) health(now time.Time, calls, failed uint64) CampusHealth {
+	health := CampusHealth{
+		CircuitState: m.breaker.State(),
+		Window:       m.cfg.Window.String(),
+		Threshold:    m.cfg.ErrorRate,
+	}
+	for _, sample := range m.samples {
+		if now.Sub(sample.at) <= m.cfg.Window {
+			health.Calls = calls - sample.calls
+			health.Failed = failed - sample.failed
+			break
+		}
+	}
+	if health.Calls > 0 {
+		health.ErrorRate = float64(health.Failed) / float64(health.Calls)
+	}
+	return health
+}
+
+// check samples the breaker's counters and raises, reminds of or resolves each condition
+func (m *CampusHealthMonitor) check(now time.Time) {
+	calls, failed := m.breaker.Counts()
+	m.samples = append(m.samples, campusCallSample{at: now, calls: calls, failed: failed})
+	for len(m.samples) > 1 && now.Sub(m.samples[0].at) > m.cfg.Window {
+		m.samples = m.samples[1:]
+	}
+
+	health := m.health(now, calls, failed)
+	m.evaluate(now, models.AlertCircuitOpen, health.CircuitState != utils.CircuitClosed, health)
+	m.evaluate(now, models.AlertErrorRate,
+		health.Calls >= uint64(m.cfg.MinCalls) && health.ErrorRate >= m.cfg.ErrorRate, health)
+}
+
+// evaluate handles one condition. A firing condition opens an alert unless one is open, and
+// repeats its notification every Repeat; an open alert is resolved once its condition stayed
+// healthy for Recovery, so a flapping circuit does not notify on every trial request.
+func (m *CampusHealthMonitor) evaluate(now time.Time, condition models.IntegrationAlertCondition, firing bool, health CampusHealth) {
+	if firing {
+		delete(m.healthySince, condition)
+		m.fire(now, condition, health)
+		return
+	}
+
+	since, ok := m.healthySince[condition]
+	if !ok {
+		m.healthySince[condition] = now
+		return
+	}
+	if now.Sub(since) < m.cfg.Recovery {
+		return
+	}
+
+	alert, err := m.alertRepo.Resolve(models.CampusIntegration, condition, now)
+	if err != nil {
+		log.Printf("[CAMPUS ALERT] Failed to resolve %s alert: %v", condition, err)
+		return
+	}
+	if alert != nil {
+		log.Printf("[CAMPUS ALERT] Campus API recovered from %s", condition)
+		m.notify(CampusAlertResolved, alert, health)
+	}
+}
+
+// fire opens an alert for a firing condition or reminds of the open one
+func (m *CampusHealthMonitor) fire(now time.Time, condition models.IntegrationAlertCondition, health CampusHealth) {
+	alert, err := m.alertRepo.FindOpen(models.CampusIntegration, condition)
+	if err != nil {
+		log.Printf("[CAMPUS ALERT] Failed to load %s alert: %v", condition, err)
+		return
+	}
+
+	if alert == nil {
+		details, _ := json.Marshal(health)
+		alert = &models.IntegrationAlert{
+			Integration:   models.CampusIntegration,
+			Condition:     condition,
+			Details:       string(details),
+			StartedAt:     now,
+			NotifiedAt:    now,
+			Notifications: 1,
+		}
+		created, err := m.alertRepo.Open(alert)
+		if err != nil {
+			log.Printf("[CAMPUS ALERT] Failed to open %s alert: %v", condition, err)
+			return
+		}
+		if created {
+			log.Printf("[CAMPUS ALERT] Campus API unhealthy: %s", condition)
+			m.notify(CampusAlertFiring, alert, health)
+		}
+		return
+	}
+
+	if now.Sub(alert.NotifiedAt) < m.cfg.Repeat {
+		return
+	}
+	reminded, err := m.alertRepo.MarkNotified(alert, now)
+	if err != nil {
+		log.Printf("[CAMPUS ALERT] Failed to record reminder of alert %d: %v", alert.ID, err)
+		return
+	}
+	if reminded {
+		m.notify(CampusAlertReminder, alert, health)
+	}
+}
+
+// campusAlertPayload is the body of the campus IT webhook
+type campusAlertPayload struct {
+	Event         string                           `json:"event"`
+	AlertID       uint                             `json:"alert_id"`
+	Integration   string                           `json:"integration"`
+	Condition     models.IntegrationAlertCondition `json:"condition"`
+	StartedAt     time.Time                        `json:"started_at"`
+	ResolvedAt    *time.Time                       `json:"resolved_at,omitempty"`
+	Notifications int                              `json:"notifications"`
+	Health        CampusHealth                     `json:"health"`
+	SentAt        time.Time                        `json:"sent_at"`
+}
+
+// notify sends an alert event to the webhook and the configured emails. Failed deliveries are
+// logged; a firing alert is delivered again with its next reminder.
+func (m *CampusHealthMonitor) notify(event string, alert *models.IntegrationAlert, health CampusHealth) {
+	payload := campusAlertPayload{
+		Event:         event,
+		AlertID:       alert.ID,
+		Integration:   alert.Integration,
+		Condition:     alert.Condition,
+		StartedAt:     alert.StartedAt,
+		ResolvedAt:    alert.ResolvedAt,
+		Notifications: alert.Notifications,
+		Health:        health,
+		SentAt:        time.Now(),
+	}
+
+	if m.cfg.WebhookURL != "" {
+		if err := m.post(payload); err != nil {
+			log.Printf("[CAMPUS ALERT] Failed to deliver %s of alert %d to the webhook: %v", event, alert.ID, err)
+		}
+	}
+
+	subject := "Gangguan integrasi API kampus: " + string(alert.Condition)
+	if event == CampusAlertResolved {
+		subject = "Integrasi API kampus pulih: " + string(alert.Condition)
+	}
+	for _, to := range m.cfg.Emails {
+		err := m.emailQueue.Enqueue(context.Background(), to, "campus_alert", EmailData{
+			Subject:       subject,
+			RecipientName: "Tim IT Kampus",
+			Data: map[string]interface{}{
+				"resolved":   event == CampusAlertResolved,
+				"reminder":   event == CampusAlertReminder,
+				"alert_id":   alert.ID,
+				"condition":  string(alert.Condition),
+				"started_at": alert.StartedAt.Format("02 Jan 2006 15:04 MST"),
+				"circuit":    string(health.CircuitState),
+				"error_rate": fmt.Sprintf("%.0f%%", health.ErrorRate*100),
+				"calls":      health.Calls,
+				"failed":     health.Failed,
+				"window":     health.Window,
+			},
+		})
+		if err != nil {
+			log.Printf("[CAMPUS ALERT] Failed to queue %s of alert %d to %s: %v", event, alert.ID, to, err)
+		}
+	}
+}
+
+// post delivers a payload to the webhook, signed with the webhook secret when one is set
+func (m *CampusHealthMonitor) post(payload campusAlertPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-DelPresence-Event", payload.Event)
+	if m.cfg.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(m.cfg.WebhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-DelPresence-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, message)
+	}
+	return nil
+}
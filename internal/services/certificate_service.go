@@ -0,0 +1,163 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/pdf"
+	"delpresence-api/pkg/qrcode"
+)
+
+// DefaultCertificateTemplate is used for events whose organizer has not set a template
+var DefaultCertificateTemplate = models.CertificateTemplate{
+	Title: "SERTIFIKAT",
+	Body:  "Diberikan kepada\n{name}\nsebagai peserta {event} yang diselenggarakan pada {date} di {location}.",
+}
+
+// CertificateService issues participation certificates to guests who attended an event,
+// renders them as PDF and verifies them by code. Every certificate is signed with an HMAC of
+// its details, so a certificate whose stored details were altered no longer verifies.
+type CertificateService struct {
+	certificateRepo repository.CertificateRepository
+	baseURL         string
+	signingKey      []byte
+	institution     string
+}
+
+// NewCertificateService creates a new CertificateService. Verification links printed on
+// certificates point at baseURL.
+func NewCertificateService(certificateRepo repository.CertificateRepository, baseURL, signingKey, institution string) *CertificateService {
+	return &CertificateService{
+		certificateRepo: certificateRepo,
+		baseURL:         baseURL,
+		signingKey:      []byte(signingKey),
+		institution:     institution,
+	}
+}
+
+// Issue creates the missing certificates of the guests of an event who checked in and returns
+// every certificate of the event
+func (s *CertificateService) Issue(event *models.GuestEvent, registrations []models.GuestRegistration) ([]models.Certificate, error) {
+	existing, err := s.certificateRepo.FindByEvent(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	issued := make(map[uint]bool, len(existing))
+	for _, certificate := range existing {
+		issued[certificate.RegistrationID] = true
+	}
+
+	// Stored as a plain date, so kept at midnight UTC to round-trip to the same signed value
+	year, month, day := event.StartsAt.Local().Date()
+	eventDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	for _, registration := range registrations {
+		if registration.CheckedInAt == nil || issued[registration.ID] {
+			continue
+		}
+		code, err := newDocumentCode()
+		if err != nil {
+			return nil, err
+		}
+		certificate := &models.Certificate{
+			Code:           code,
+			EventID:        event.ID,
+			RegistrationID: registration.ID,
+			RecipientName:  registration.Name,
+			EventName:      event.Name,
+			EventDate:      eventDate,
+			IssuedAt:       time.Now(),
+		}
+		certificate.Signature = s.sign(certificate)
+		if err := s.certificateRepo.Create(certificate); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.certificateRepo.FindByEvent(event.ID)
+}
+
+// Verify looks up a certificate by code. It returns nil when no certificate has the code.
+func (s *CertificateService) Verify(code string) (*models.CertificateVerification, error) {
+	certificate, err := s.certificateRepo.FindByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil || certificate == nil {
+		return nil, err
+	}
+
+	signed := hmac.Equal([]byte(certificate.Signature), []byte(s.sign(certificate)))
+	return &models.CertificateVerification{
+		Valid:         signed && certificate.RevokedAt == nil,
+		Code:          certificate.Code,
+		RecipientName: certificate.RecipientName,
+		EventName:     certificate.EventName,
+		EventDate:     certificate.EventDate.Format("2006-01-02"),
+		Issuer:        s.institution,
+		IssuedAt:      certificate.IssuedAt,
+		RevokedAt:     certificate.RevokedAt,
+	}, nil
+}
+
+// VerificationURL returns the public address that verifies a certificate
+func (s *CertificateService) VerificationURL(code string) string {
+	return verificationURL(s.baseURL, code)
+}
+
+// PDF renders a certificate with the event's template, or the default template when template
+// is nil, and a QR code of its verification link
+func (s *CertificateService) PDF(certificate *models.Certificate, event *models.GuestEvent, template *models.CertificateTemplate) (*pdf.Document, error) {
+	if template == nil {
+		template = &DefaultCertificateTemplate
+	}
+	modules, err := qrcode.Encode(s.VerificationURL(certificate.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	fill := strings.NewReplacer(
+		"{name}", certificate.RecipientName,
+		"{event}", certificate.EventName,
+		"{date}", certificate.EventDate.Format("02-01-2006"),
+		"{location}", event.Location,
+	)
+
+	doc := pdf.New()
+	doc.Text(s.institution, 14, true, "center")
+	doc.Rule()
+	doc.Space(60)
+	doc.Text(fill.Replace(template.Title), 28, true, "center")
+	doc.Text("No. "+certificate.Code, 10, false, "center")
+	doc.Space(30)
+	doc.Paragraph(fill.Replace(template.Body), 13, false, "center")
+
+	doc.Space(50)
+	doc.Text(certificate.IssuedAt.Format("02-01-2006"), 10, false, "right")
+	if template.SignerTitle != "" {
+		doc.Text(template.SignerTitle+",", 10, false, "right")
+	}
+	doc.Space(50)
+	if template.SignerName != "" {
+		doc.Text(template.SignerName, 10, true, "right")
+	}
+
+	doc.Space(40)
+	doc.Matrix(modules, 90, "left")
+	doc.Space(6)
+	doc.Text("Verifikasi keaslian sertifikat: "+s.VerificationURL(certificate.Code), 8, false, "left")
+	doc.Text("Tanda tangan digital: "+certificate.Signature[:16], 8, false, "left")
+
+	return doc, nil
+}
+
+// sign returns the signature of a certificate's details
+func (s *CertificateService) sign(certificate *models.Certificate) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%d\n%d\n%s\n%s\n%s", certificate.Code, certificate.EventID, certificate.RegistrationID,
+		certificate.RecipientName, certificate.EventName, certificate.EventDate.Format("2006-01-02"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/jwt"
+)
+
+const (
+	// emailChangeLinkValidity is how long the confirmation links of an email change stay valid
+	emailChangeLinkValidity = 48 * time.Hour
+	// EmailChangeCoolingOff is how long a confirmed email change waits before it is applied, so
+	// the owner of the current address can still cancel a change they did not make
+	EmailChangeCoolingOff = 24 * time.Hour
+	// EmailChangedAction is the audit action of an applied email change
+	EmailChangedAction = "user.email_changed"
+)
+
+var (
+	// ErrEmailUnchanged is returned when the requested email is the user's current email
+	ErrEmailUnchanged = errors.New("new email is the current email")
+	// ErrEmailTaken is returned when the requested email belongs to another account
+	ErrEmailTaken = errors.New("email is already used by another account")
+	// ErrEmailChangeLinkInvalid is returned for unknown, expired or cancelled confirmation links
+	ErrEmailChangeLinkInvalid = errors.New("email change link is invalid or has expired")
+)
+
+// EmailChangeService changes the email of local accounts. A change must be confirmed from both
+// the current and the new address and is only applied after a cooling-off period, during which
+// either address can cancel it.
+type EmailChangeService struct {
+	tokenRepo     *repository.TokenRepository
+	userRepo      *repository.UserRepository
+	emailQueue    *EmailQueue
+	auditService  *AuditService
+	publicBaseURL string // Base of the confirmation links
+}
+
+// NewEmailChangeService creates a new EmailChangeService
+func NewEmailChangeService(tokenRepo *repository.TokenRepository, userRepo *repository.UserRepository, emailQueue *EmailQueue, auditService *AuditService, publicBaseURL string) *EmailChangeService {
+	return &EmailChangeService{
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		emailQueue:    emailQueue,
+		auditService:  auditService,
+		publicBaseURL: publicBaseURL,
+	}
+}
+
+// Request starts changing the user's email to newEmail, replacing any change still pending,
+// and sends a confirmation link to both addresses
+func (s *EmailChangeService) Request(ctx context.Context, user *models.User, newEmail string) (*models.EmailChange, error) {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, ErrEmailUnchanged
+	}
+	if err := s.checkAvailable(user.ID, newEmail); err != nil {
+		return nil, err
+	}
+
+	currentToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	newToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(emailChangeLinkValidity)
+	if err := s.tokenRepo.CreateEmailChange(user.ID, newEmail, jwt.HashRefreshToken(currentToken), jwt.HashRefreshToken(newToken), expiresAt); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"new_email":         newEmail,
+		"expires_at":        expiresAt.Format("2006-01-02 15:04"),
+		"cooling_off_hours": int(EmailChangeCoolingOff.Hours()),
+	}
+	s.sendConfirmation(ctx, user, user.Email, currentToken, true, data)
+	s.sendConfirmation(ctx, user, newEmail, newToken, false, data)
+
+	return &models.EmailChange{NewEmail: newEmail, ExpiresAt: expiresAt}, nil
+}
+
+// sendConfirmation queues the confirmation link of an email change to one of its addresses.
+// Only the current address is offered a link to cancel the change.
+func (s *EmailChangeService) sendConfirmation(ctx context.Context, user *models.User, to, token string, current bool, shared map[string]interface{}) {
+	data := map[string]interface{}{
+		"current":     current,
+		"confirm_url": s.publicBaseURL + "/api/v1/auth/email-change/confirm/" + token,
+	}
+	if current {
+		data["cancel_url"] = s.publicBaseURL + "/api/v1/auth/email-change/cancel/" + token
+	}
+	for key, value := range shared {
+		data[key] = value
+	}
+
+	email := EmailData{
+		Subject:         "Konfirmasi Perubahan Email",
+		RecipientName:   user.FullName(),
+		RecipientUserID: user.ID,
+		Track:           true,
+		Data:            data,
+	}
+	if err := s.emailQueue.Enqueue(ctx, to, "email_change_confirmation", email); err != nil {
+		emailLog.Ctx(ctx).Errorf("Failed to queue email change confirmation for user %d: %v", user.ID, err)
+	}
+}
+
+// checkAvailable checks that no other account uses the email
+func (s *EmailChangeService) checkAvailable(userID uint, email string) error {
+	owner, err := s.userRepo.GetUserByEmail(email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if owner.ID != userID {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// Pending returns the user's pending email change, or nil when there is none
+func (s *EmailChangeService) Pending(userID uint) (*models.EmailChange, error) {
+	tokens, err := s.tokenRepo.FindEmailChange(userID)
+	if err != nil {
+		return nil, err
+	}
+	return emailChangeOf(tokens), nil
+}
+
+// emailChangeOf builds the state of an email change from its tokens
+func emailChangeOf(tokens []models.Token) *models.EmailChange {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	change := &models.EmailChange{NewEmail: tokens[0].PendingEmail, ExpiresAt: tokens[0].ExpiresAt}
+	var lastConfirmed time.Time
+	for _, token := range tokens {
+		if token.ConfirmedAt == nil {
+			continue
+		}
+		switch token.Type {
+		case models.EmailChangeCurrentToken:
+			change.CurrentConfirmed = true
+		case models.EmailChangeNewToken:
+			change.NewConfirmed = true
+		}
+		if token.ConfirmedAt.After(lastConfirmed) {
+			lastConfirmed = *token.ConfirmedAt
+		}
+	}
+	if change.CurrentConfirmed && change.NewConfirmed {
+		effectiveAt := lastConfirmed.Add(EmailChangeCoolingOff)
+		change.EffectiveAt = &effectiveAt
+	}
+	return change
+}
+
+// Confirm confirms an email change from the address its emailed link was sent to
+func (s *EmailChangeService) Confirm(token string) (*models.EmailChange, error) {
+	stored, err := s.findToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	keepUntil := time.Now().Add(EmailChangeCoolingOff + emailChangeLinkValidity)
+	if _, err := s.tokenRepo.ConfirmEmailChange(stored, keepUntil); err != nil {
+		return nil, err
+	}
+	return s.Pending(stored.UserID)
+}
+
+// CancelByLink cancels an email change from the link emailed to the user's current address
+func (s *EmailChangeService) CancelByLink(token string) (*models.Token, error) {
+	stored, err := s.findToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Type != models.EmailChangeCurrentToken {
+		return nil, ErrEmailChangeLinkInvalid
+	}
+
+	if _, err := s.tokenRepo.DeleteEmailChange(stored.UserID); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// Cancel cancels the user's pending email change, reporting whether there was one
+func (s *EmailChangeService) Cancel(userID uint) (bool, error) {
+	return s.tokenRepo.DeleteEmailChange(userID)
+}
+
+// findToken looks up the unexpired token of an emailed email change link
+func (s *EmailChangeService) findToken(token string) (*models.Token, error) {
+	hashed := jwt.HashRefreshToken(token)
+	for _, tokenType := range []models.TokenType{models.EmailChangeCurrentToken, models.EmailChangeNewToken} {
+		stored, err := s.tokenRepo.GetTokenByValue(hashed, tokenType)
+		if err == nil {
+			return stored, nil
+		}
+		if !errors.Is(err, repository.ErrTokenNotFound) && !errors.Is(err, repository.ErrTokenExpired) {
+			return nil, err
+		}
+	}
+	return nil, ErrEmailChangeLinkInvalid
+}
+
+// ApplyDue applies the email changes whose cooling-off period has passed and returns how many
+// were applied. Changes to an address another account took in the meantime are dropped.
+func (s *EmailChangeService) ApplyDue(ctx context.Context) (int, error) {
+	tokens, err := s.tokenRepo.FindConfirmedEmailChanges(time.Now().Add(-EmailChangeCoolingOff))
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	var errs []error
+	for _, token := range tokens {
+		if err := s.apply(ctx, token); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", token.UserID, err))
+			continue
+		}
+		applied++
+	}
+	return applied, errors.Join(errs...)
+}
+
+// apply switches a user to the email of a confirmed change and notifies both addresses
+func (s *EmailChangeService) apply(ctx context.Context, token models.Token) error {
+	user, err := s.userRepo.GetUserByID(token.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		_, err = s.tokenRepo.DeleteEmailChange(token.UserID)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.checkAvailable(user.ID, token.PendingEmail); err != nil {
+		if errors.Is(err, ErrEmailTaken) {
+			emailLog.Ctx(ctx).Warnf("Dropping email change of user %d: %s is now used by another account", user.ID, token.PendingEmail)
+			_, err = s.tokenRepo.DeleteEmailChange(user.ID)
+		}
+		return err
+	}
+
+	oldEmail := user.Email
+	user.Email = token.PendingEmail
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+	if _, err := s.tokenRepo.DeleteEmailChange(user.ID); err != nil {
+		return err
+	}
+
+	s.auditService.Record(AuditEntry{
+		ActorUserID: user.ID,
+		ActorType:   string(user.UserType),
+		Action:      EmailChangedAction,
+		EntityType:  "user",
+		EntityID:    user.ID,
+		Details:     map[string]interface{}{"old_email": oldEmail, "new_email": user.Email},
+	})
+
+	for _, to := range []string{oldEmail, user.Email} {
+		email := EmailData{
+			Subject:         "Email Akun Anda Telah Diubah",
+			RecipientName:   user.FullName(),
+			RecipientUserID: user.ID,
+			Data:            map[string]interface{}{"old_email": oldEmail, "new_email": user.Email},
+		}
+		if err := s.emailQueue.Enqueue(ctx, to, "email_changed", email); err != nil {
+			emailLog.Ctx(ctx).Errorf("Failed to queue email change notice for user %d: %v", user.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+const (
+	// emailQueuePollInterval is how often the worker looks for due emails when nothing is queued
+	emailQueuePollInterval = 30 * time.Second
+	// emailQueueBatch is how many emails the worker claims at once
+	emailQueueBatch = 20
+	// emailMaxAttempts is how many times an email is sent before it is marked failed
+	emailMaxAttempts = 6
+	// emailRetryBase is the delay before the first retry; it doubles after every failure
+	emailRetryBase = time.Minute
+	// emailRetryMax caps the delay between two attempts
+	emailRetryMax = 2 * time.Hour
+	// emailStaleClaim is how long an email may stay claimed before another worker takes it
+	// over, e.g. after the instance sending it stopped
+	emailStaleClaim = 10 * time.Minute
+)
+
+// EmailQueue stores outgoing emails and sends them from a background worker, retrying
+// failed SMTP sends with exponential backoff so an SMTP outage does not lose emails
+type EmailQueue struct {
+	emailService *EmailService
+	queueRepo    repository.EmailQueueRepository
+	wake         chan struct{}
+}
+
+// NewEmailQueue creates a new EmailQueue
+func NewEmailQueue(emailService *EmailService, queueRepo repository.EmailQueueRepository) *EmailQueue {
+	return &EmailQueue{
+		emailService: emailService,
+		queueRepo:    queueRepo,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Enqueue renders a template and queues the email for the worker. When SMTP is not
+// configured the email is only logged, as with EmailService.SendEmail.
+func (q *EmailQueue) Enqueue(ctx context.Context, to, templateName string, data EmailData) error {
+	if !q.emailService.IsConfigured() {
+		emailLog.Ctx(ctx).Infof("SMTP not configured, skipping email %q to %s", data.Subject, to)
+		return nil
+	}
+
+	body, err := q.emailService.compose(ctx, templateName, data)
+	if err != nil {
+		return err
+	}
+
+	email := &models.QueuedEmail{
+		Recipient:     to,
+		Template:      templateName,
+		Subject:       data.Subject,
+		Body:          body,
+		Status:        models.EmailPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := q.queueRepo.Enqueue(email); err != nil {
+		return err
+	}
+
+	emailLog.Ctx(ctx).Info("Email queued", "id", email.ID, "subject", data.Subject, "to", to, "template", templateName)
+	q.notify()
+	return nil
+}
+
+// Failed returns the most recent emails whose every attempt failed
+func (q *EmailQueue) Failed(limit int) ([]models.QueuedEmail, error) {
+	return q.queueRepo.FindFailed(limit)
+}
+
+// Retry requeues a failed email for immediate delivery with a fresh set of attempts.
+// It returns false when no failed email has the given ID.
+func (q *EmailQueue) Retry(id uint) (bool, error) {
+	requeued, err := q.queueRepo.Requeue(id, time.Now())
+	if requeued {
+		q.notify()
+	}
+	return requeued, err
+}
+
+// Run sends due emails until stop is closed, waking up early when an email is queued
+func (q *EmailQueue) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(emailQueuePollInterval)
+	defer ticker.Stop()
+	for {
+		q.processDue(stop)
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// notify wakes the worker without blocking when it is already awake
+func (q *EmailQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// processDue claims and sends due emails batch by batch until none are left
+func (q *EmailQueue) processDue(stop <-chan struct{}) {
+	for {
+		now := time.Now()
+		emails, err := q.queueRepo.ClaimDue(now, now.Add(-emailStaleClaim), emailQueueBatch)
+		if err != nil {
+			log.Printf("[EMAIL QUEUE] Failed to claim due emails: %v", err)
+			return
+		}
+
+		for i := range emails {
+			q.send(&emails[i])
+		}
+
+		if len(emails) < emailQueueBatch {
+			return
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
+}
+
+// send delivers one claimed email and records the outcome, scheduling a retry on failure
+func (q *EmailQueue) send(email *models.QueuedEmail) {
+	err := q.emailService.deliver(context.Background(), email.Recipient, email.Subject, email.Body)
+	now := time.Now()
+	switch {
+	case err == nil:
+		email.Status = models.EmailSent
+		email.SentAt = &now
+		email.LastError = ""
+	case email.Attempts >= emailMaxAttempts:
+		email.Status = models.EmailFailed
+		email.LastError = err.Error()
+		log.Printf("[EMAIL QUEUE] Giving up on email %d to %s after %d attempts: %v", email.ID, email.Recipient, email.Attempts, err)
+	default:
+		email.Status = models.EmailPending
+		email.NextAttemptAt = now.Add(emailRetryDelay(email.Attempts))
+		email.LastError = err.Error()
+		log.Printf("[EMAIL QUEUE] Attempt %d of email %d to %s failed, retrying at %s: %v", email.Attempts, email.ID, email.Recipient, email.NextAttemptAt.Format(time.RFC3339), err)
+	}
+
+	if err := q.queueRepo.Update(email); err != nil {
+		log.Printf("[EMAIL QUEUE] Failed to save outcome of email %d: %v", email.ID, err)
+	}
+}
+
+// emailRetryDelay is the backoff after the given number of failed attempts
+func emailRetryDelay(attempts int) time.Duration {
+	delay := emailRetryBase
+	for i := 1; i < attempts && delay < emailRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, emailRetryMax)
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/chaos"
+	"delpresence-api/internal/logging"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/templates"
+	"delpresence-api/pkg/config"
+)
+
+// emailLog logs rendering and delivery of emails
+var emailLog = logging.Module("email")
+
+// brandingTemplate holds the shared header and footer parsed with every email template
+const brandingTemplate = "branding.html"
+
+// EmailData holds the values available to every email template
+type EmailData struct {
+	Subject       string
+	RecipientName string
+	AppName       string
+	Faculty       string               // Selects the faculty's branding; empty for the general branding
+	Branding      models.EmailBranding // Filled by Render from the configured branding
+	// RecipientUserID is the user the email is sent to, or zero for people without an account
+	RecipientUserID uint
+	// Track opts a critical email into open and click tracking; it only applies when tracking
+	// is enabled and the recipient is a user who has not opted out
+	Track            bool
+	TrackingPixelURL string // Filled by SendEmail for tracked emails
+	Data             map[string]interface{}
+}
+
+// EmailService sends templated emails over SMTP
+type EmailService struct {
+	host         string
+	port         string
+	username     string
+	password     string
+	from         string
+	templates    fs.FS
+	parsedMutex  sync.Mutex
+	parsed       map[string]*template.Template // By template name; cloned before every execution
+	brandingRepo repository.EmailBrandingRepository
+	tracker      *EmailTracker
+}
+
+// NewEmailService creates a new EmailService sending through the configured SMTP server.
+// brandingRepo and tracker may be nil when the service is only used to check the SMTP
+// connection.
+func NewEmailService(cfg config.SMTPConfig, brandingRepo repository.EmailBrandingRepository, tracker *EmailTracker) *EmailService {
+	return &EmailService{
+		host:         cfg.Host,
+		port:         cfg.Port,
+		username:     cfg.Username,
+		password:     cfg.Password,
+		from:         cfg.From,
+		templates:    emailTemplates(cfg.TemplateDir),
+		parsed:       make(map[string]*template.Template),
+		brandingRepo: brandingRepo,
+		tracker:      tracker,
+	}
+}
+
+// emailTemplates returns the templates emails are rendered from: the embedded ones, each
+// replaced by the file of the same name in dir when dir is set and has one
+func emailTemplates(dir string) fs.FS {
+	embedded, err := fs.Sub(templates.Email, "email")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+	if dir == "" {
+		return embedded
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		emailLog.Warnf("Email template directory %s not found, using the embedded templates", dir)
+		return embedded
+	}
+	return overlayFS{override: os.DirFS(dir), base: embedded}
+}
+
+// overlayFS opens files from override, falling back to base for files override does not have
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+// Open implements fs.FS
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.override.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.base.Open(name)
+	}
+	return file, err
+}
+
+// IsConfigured reports whether SMTP settings are present
+func (s *EmailService) IsConfigured() bool {
+	return s.host != ""
+}
+
+// Render renders an email template with the given data
+func (s *EmailService) Render(templateName string, data EmailData) (string, error) {
+	return s.render(templateName, data, nil)
+}
+
+// render renders an email template, pointing its links through the tracker when tracking is set.
+// Templates wrap link addresses in {{link ...}} so they can be tracked.
+func (s *EmailService) render(templateName string, data EmailData, tracking *models.EmailTracking) (string, error) {
+	if data.AppName == "" {
+		data.AppName = "DelPresence"
+	}
+	data.Branding = s.branding(data.Faculty)
+
+	link := func(target string) string {
+		return target
+	}
+	if tracking != nil {
+		data.TrackingPixelURL = s.tracker.PixelURL(tracking.Token)
+		link = func(target string) string {
+			return s.tracker.LinkURL(tracking.Token, target)
+		}
+	}
+
+	parsed, err := s.parse(templateName)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := parsed.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare email template %s: %w", templateName, err)
+	}
+	tmpl.Funcs(template.FuncMap{"link": link})
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("failed to render email template %s: %w", templateName, err)
+	}
+
+	return body.String(), nil
+}
+
+// parse returns a template parsed with the shared branding template, parsing it the first
+// time it is used. The result must be cloned before it is executed.
+func (s *EmailService) parse(templateName string) (*template.Template, error) {
+	s.parsedMutex.Lock()
+	defer s.parsedMutex.Unlock()
+	if tmpl, ok := s.parsed[templateName]; ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := template.New(templateName+".html").Funcs(template.FuncMap{"link": func(target string) string { return target }}).
+		ParseFS(s.templates, templateName+".html", brandingTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email template %s: %w", templateName, err)
+	}
+	s.parsed[templateName] = tmpl
+	return tmpl, nil
+}
+
+// branding returns the branding of a faculty, falling back to the defaults when none is
+// configured or it cannot be loaded
+func (s *EmailService) branding(faculty string) models.EmailBranding {
+	if s.brandingRepo == nil {
+		return models.DefaultEmailBranding
+	}
+	branding, err := s.brandingRepo.FindForFaculty(faculty)
+	if err != nil {
+		emailLog.Warnf("Failed to load branding for faculty %q, using defaults: %v", faculty, err)
+		return models.DefaultEmailBranding
+	}
+	if branding == nil {
+		return models.DefaultEmailBranding
+	}
+	return branding.WithDefaults()
+}
+
+// SendEmail renders a template and sends it to a single recipient.
+// When SMTP is not configured the email is only logged. ctx ties the log messages to the
+// request that triggered the email.
+func (s *EmailService) SendEmail(ctx context.Context, to, templateName string, data EmailData) error {
+	logger := emailLog.Ctx(ctx)
+	if !s.IsConfigured() {
+		logger.Infof("SMTP not configured, skipping email %q to %s", data.Subject, to)
+		return nil
+	}
+
+	body, err := s.compose(ctx, templateName, data)
+	if err != nil {
+		return err
+	}
+	if err := s.deliver(ctx, to, data.Subject, body); err != nil {
+		return err
+	}
+
+	logger.Info("Email sent", "subject", data.Subject, "to", to, "template", templateName)
+	return nil
+}
+
+// compose renders a template into the body of an email, starting its tracking when the email
+// is tracked. Only call it for emails that will be sent.
+func (s *EmailService) compose(ctx context.Context, templateName string, data EmailData) (string, error) {
+	var tracking *models.EmailTracking
+	if data.Track {
+		var err error
+		if tracking, err = s.tracker.start(templateName, data.RecipientUserID); err != nil {
+			emailLog.Ctx(ctx).Warnf("Failed to start tracking %q, sending it untracked: %v", data.Subject, err)
+			tracking = nil
+		}
+	}
+	return s.render(templateName, data, tracking)
+}
+
+// deliver sends a composed email over SMTP
+func (s *EmailService) deliver(ctx context.Context, to, subject, body string) error {
+	if err := chaos.Inject(ctx, chaos.Email); err != nil {
+		return err
+	}
+
+	headers := []string{
+		"From: " + s.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/html; charset=\"UTF-8\"",
+	}
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" + body
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	if err := smtp.SendMail(s.host+":"+s.port, auth, s.senderAddress(), []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to, err)
+	}
+	return nil
+}
+
+// Ping performs an SMTP handshake with the configured server without sending anything
+func (s *EmailService) Ping(timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", s.host+":"+s.port, timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return fmt.Errorf("SMTP EHLO failed: %w", err)
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	return client.Quit()
+}
+
+// senderAddress extracts the bare address from the From header value
+func (s *EmailService) senderAddress() string {
+	if start := strings.Index(s.from, "<"); start >= 0 {
+		if end := strings.Index(s.from[start:], ">"); end > 0 {
+			return s.from[start+1 : start+end]
+		}
+	}
+	return s.from
+}
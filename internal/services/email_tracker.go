@@ -0,0 +1,96 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// EmailTracker adds open and click tracking to critical emails. Tracking is anonymous: the
+// token of an email is not linked to its recipient, users can opt out, and only aggregate
+// counts are reported.
+type EmailTracker struct {
+	trackingRepo repository.EmailTrackingRepository
+	baseURL      string
+	signingKey   []byte
+	enabled      bool
+}
+
+// NewEmailTracker creates a new EmailTracker. Links in emails point at baseURL and are signed
+// with signingKey so the click endpoint cannot be used as an open redirect. When enabled is
+// false no new email is tracked, but links in emails already sent keep working.
+func NewEmailTracker(trackingRepo repository.EmailTrackingRepository, baseURL, signingKey string, enabled bool) *EmailTracker {
+	return &EmailTracker{
+		trackingRepo: trackingRepo,
+		baseURL:      baseURL,
+		signingKey:   []byte(signingKey),
+		enabled:      enabled,
+	}
+}
+
+// start creates the tracking token of an email unless tracking is disabled, the recipient is
+// not a user who could opt out, or the recipient opted out
+func (t *EmailTracker) start(templateName string, recipientUserID uint) (*models.EmailTracking, error) {
+	if t == nil || !t.enabled || recipientUserID == 0 {
+		return nil, nil
+	}
+	optedOut, err := t.trackingRepo.IsOptedOut(recipientUserID)
+	if err != nil || optedOut {
+		return nil, err
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	tracking := &models.EmailTracking{
+		Token:    hex.EncodeToString(raw),
+		Template: templateName,
+		SentAt:   time.Now(),
+	}
+	if err := t.trackingRepo.Create(tracking); err != nil {
+		return nil, err
+	}
+	return tracking, nil
+}
+
+// PixelURL returns the address of the invisible image that records an email being opened
+func (t *EmailTracker) PixelURL(token string) string {
+	return t.baseURL + "/api/v1/email/o/" + token
+}
+
+// LinkURL returns the tracked address of a link that redirects to target
+func (t *EmailTracker) LinkURL(token, target string) string {
+	query := url.Values{}
+	query.Set("u", target)
+	query.Set("s", t.sign(token, target))
+	return t.baseURL + "/api/v1/email/c/" + token + "?" + query.Encode()
+}
+
+// VerifyLink checks that a tracked link was created by this API
+func (t *EmailTracker) VerifyLink(token, target, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(t.sign(token, target)))
+}
+
+// Opened records that the email of a token was opened
+func (t *EmailTracker) Opened(token string) error {
+	return t.trackingRepo.MarkOpened(token)
+}
+
+// Clicked records that a link in the email of a token was clicked
+func (t *EmailTracker) Clicked(token string) error {
+	return t.trackingRepo.MarkClicked(token)
+}
+
+// sign returns the signature of a tracked link
+func (t *EmailTracker) sign(token, target string) string {
+	mac := hmac.New(sha256.New, t.signingKey)
+	mac.Write([]byte(token + "\n" + target))
+	return hex.EncodeToString(mac.Sum(nil))
+}
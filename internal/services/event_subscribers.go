@@ -0,0 +1,241 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// auditEntryFor builds an audit entry attributed to the actor of an event
+func auditEntryFor(actor events.Actor, action, entityType string, entityID interface{}, details map[string]interface{}) AuditEntry {
+	return AuditEntry{
+		ActorUserID: actor.UserID,
+		ActorType:   actor.Type,
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Details:     details,
+		IPAddress:   actor.IPAddress,
+	}
+}
+
+// Subscribe records domain events in the audit log
+func (s *AuditService) Subscribe(bus *events.Bus) {
+	bus.Subscribe(events.ProfileSyncedEvent, func(event events.Event) {
+		e := event.(events.ProfileSynced)
+		s.Record(auditEntryFor(e.Actor, "profile.synced", string(e.ProfileType), e.ProfileID, map[string]interface{}{
+			"user_id": e.UserID,
+		}))
+	})
+
+	bus.Subscribe(events.SupervisionMeetingDecidedEvent, func(event events.Event) {
+		e := event.(events.SupervisionMeetingDecided)
+		s.Record(auditEntryFor(e.Actor, "supervision."+string(e.Meeting.Status), "supervision_meeting", e.Meeting.ID, map[string]interface{}{
+			"nim":  e.Meeting.Nim,
+			"note": e.Note,
+		}))
+	})
+
+	bus.Subscribe(events.RoomBookingRequestedEvent, func(event events.Event) {
+		e := event.(events.RoomBookingRequested)
+		s.Record(auditEntryFor(e.Actor, "booking.requested", "room_booking", e.Booking.ID, map[string]interface{}{
+			"room": e.Booking.Room,
+			"date": e.Booking.Date.Format("2006-01-02"),
+			"kind": e.Booking.Kind,
+		}))
+	})
+
+	bus.Subscribe(events.RoomBookingDecidedEvent, func(event events.Event) {
+		e := event.(events.RoomBookingDecided)
+		s.Record(auditEntryFor(e.Actor, "booking."+string(e.Booking.Status), "room_booking", e.Booking.ID, map[string]interface{}{
+			"room":       e.Booking.Room,
+			"note":       e.Note,
+			"session_id": e.Booking.SessionID,
+		}))
+	})
+
+	bus.Subscribe(events.PermissionDecidedEvent, func(event events.Event) {
+		e := event.(events.PermissionDecided)
+		s.Record(auditEntryFor(e.Actor, "permission."+string(e.Request.Status), "permission_request", e.Request.ID, map[string]interface{}{
+			"nim":         e.Request.Nim,
+			"course_code": e.Request.CourseCode,
+			"note":        e.Note,
+			"excused":     e.Excused,
+		}))
+	})
+}
+
+// Subscribe notifies users about domain events that concern them
+func (s *NotificationService) Subscribe(bus *events.Bus) {
+	bus.Subscribe(events.SupervisionMeetingLoggedEvent, func(event events.Event) {
+		e := event.(events.SupervisionMeetingLogged)
+		approver := e.ApproverUserID
+		if approver == 0 {
+			approver = e.Meeting.SupervisorUserID
+		}
+		s.Notify(approver, "supervision.requested",
+			"Konfirmasi bimbingan",
+			fmt.Sprintf("Mahasiswa %s mencatat bimbingan \"%s\" pada %s", e.Meeting.Nim, e.Meeting.Topic, e.Meeting.MeetingDate.Format("2006-01-02")))
+	})
+
+	bus.Subscribe(events.SupervisionMeetingDecidedEvent, func(event events.Event) {
+		e := event.(events.SupervisionMeetingDecided)
+		s.Notify(e.Meeting.StudentUserID, "supervision."+string(e.Meeting.Status),
+			"Status bimbingan diperbarui",
+			fmt.Sprintf("Bimbingan \"%s\" pada %s telah %s", e.Meeting.Topic, e.Meeting.MeetingDate.Format("2006-01-02"), e.Meeting.Status))
+	})
+
+	bus.Subscribe(events.RoomBookingDecidedEvent, func(event events.Event) {
+		e := event.(events.RoomBookingDecided)
+		if e.Booking.Status == models.BookingCancelled {
+			return
+		}
+		message := fmt.Sprintf("Peminjaman ruangan %s pada %s %s-%s telah %s", e.Booking.Room, e.Booking.Date.Format("2006-01-02"), e.Booking.StartTime, e.Booking.EndTime, e.Booking.Status)
+		if e.Note != "" {
+			message += ": " + e.Note
+		}
+		s.Notify(e.Booking.RequesterUserID, "booking."+string(e.Booking.Status), "Status peminjaman ruangan diperbarui", message)
+	})
+
+	bus.Subscribe(events.PermissionRequestedEvent, func(event events.Event) {
+		e := event.(events.PermissionRequested)
+		approver := e.ApproverUserID
+		if approver == 0 {
+			approver = e.Request.LecturerUserID
+		}
+		s.Notify(approver, "permission.requested",
+			"Pengajuan izin mahasiswa",
+			fmt.Sprintf("Mahasiswa %s mengajukan %s untuk %s pada %s s.d. %s", e.Request.Nim, e.Request.Kind, e.Request.CourseCode, e.Request.StartDate.Format("2006-01-02"), e.Request.EndDate.Format("2006-01-02")))
+	})
+
+	bus.Subscribe(events.PermissionDecidedEvent, func(event events.Event) {
+		e := event.(events.PermissionDecided)
+		if e.Request.Status == models.PermissionCancelled {
+			return
+		}
+		message := fmt.Sprintf("Pengajuan %s untuk %s pada %s s.d. %s telah %s", e.Request.Kind, e.Request.CourseCode, e.Request.StartDate.Format("2006-01-02"), e.Request.EndDate.Format("2006-01-02"), e.Request.Status)
+		if e.Note != "" {
+			message += ": " + e.Note
+		}
+		s.Notify(e.Request.StudentUserID, "permission."+string(e.Request.Status), "Status pengajuan izin diperbarui", message)
+	})
+
+	bus.Subscribe(events.WorkflowReminderDueEvent, func(event events.Event) {
+		e := event.(events.WorkflowReminderDue)
+		if e.AssigneeUserID == 0 {
+			return
+		}
+		s.Notify(e.AssigneeUserID, "approval.reminder", "Pengingat persetujuan",
+			fmt.Sprintf("Persetujuan %s #%d harus diputuskan sebelum %s", e.Instance.Type, e.Instance.SubjectID, e.Instance.DueAt.Format("2006-01-02 15:04")))
+	})
+
+	bus.Subscribe(events.WorkflowEscalatedEvent, func(event events.Event) {
+		e := event.(events.WorkflowEscalated)
+		message := fmt.Sprintf("Persetujuan %s #%d belum diputuskan sejak %s", e.Instance.Type, e.Instance.SubjectID, e.Instance.StateEnteredAt.Format("2006-01-02"))
+		notified := map[uint]bool{}
+		recipients := append([]uint{e.AssigneeUserID, e.Instance.OwnerUserID}, e.EscalatedTo...)
+		for _, userID := range recipients {
+			if userID == 0 || notified[userID] {
+				continue
+			}
+			notified[userID] = true
+			s.Notify(userID, "approval.overdue", "Persetujuan melewati batas waktu", message)
+		}
+	})
+
+	bus.Subscribe(events.OfficeHourBookedEvent, func(event events.Event) {
+		e := event.(events.OfficeHourBooked)
+		if e.Booking.Slot == nil {
+			return
+		}
+		message := "Seorang mahasiswa memesan jam konsultasi " + officeHourLabel(e.Booking.Slot)
+		if e.Booking.Topic != "" {
+			message += ": " + e.Booking.Topic
+		}
+		s.Notify(e.Booking.Slot.LecturerUserID, "office_hour.booked", "Pemesanan jam konsultasi", message)
+	})
+
+	bus.Subscribe(events.OfficeHourBookingCancelledEvent, func(event events.Event) {
+		e := event.(events.OfficeHourBookingCancelled)
+		if e.Booking.Slot == nil {
+			return
+		}
+		s.Notify(e.Booking.Slot.LecturerUserID, "office_hour.booking_cancelled", "Pemesanan jam konsultasi dibatalkan",
+			"Seorang mahasiswa membatalkan pemesanan jam konsultasi "+officeHourLabel(e.Booking.Slot))
+	})
+
+	bus.Subscribe(events.OfficeHourSlotCancelledEvent, func(event events.Event) {
+		e := event.(events.OfficeHourSlotCancelled)
+		message := "Dosen membatalkan jam konsultasi " + officeHourLabel(&e.Slot)
+		for _, booking := range e.Bookings {
+			s.Notify(booking.StudentUserID, "office_hour.cancelled", "Jam konsultasi dibatalkan", message)
+		}
+	})
+}
+
+// SubscribeRoleLinking links the matching role to an account whenever one of its profiles is synced
+func SubscribeRoleLinking(bus *events.Bus, userRoleRepo repository.UserRoleRepository) {
+	bus.Subscribe(events.ProfileSyncedEvent, func(event events.Event) {
+		e := event.(events.ProfileSynced)
+		role := &models.UserRole{
+			Source:    models.CampusIdentity,
+			UserID:    e.UserID,
+			Role:      e.ProfileType,
+			ProfileID: e.ProfileID,
+		}
+		if err := userRoleRepo.Assign(role); err != nil {
+			log.Printf("[EVENTS] Failed to link %s role to user %d: %v", e.ProfileType, e.UserID, err)
+		}
+	})
+}
+
+// SubscribePermissionExcusal excuses students with an approved permission request from a
+// session when it is closed
+func SubscribePermissionExcusal(bus *events.Bus, permissionRepo repository.PermissionRequestRepository) {
+	bus.Subscribe(events.AttendanceSessionClosedEvent, func(event events.Event) {
+		e := event.(events.AttendanceSessionClosed)
+		if _, err := permissionRepo.ExcuseSession(e.Session.ID); err != nil {
+			log.Printf("[EVENTS] Failed to excuse permitted absences in session %d: %v", e.Session.ID, err)
+		}
+	})
+}
+
+// Subscribe drops the cached prodi of a user whenever one of their profiles is synced
+func (r *ProdiResolver) Subscribe(bus *events.Bus) {
+	r.cache.InvalidateOn(bus, events.ProfileSyncedEvent, func(event events.Event) []string {
+		e := event.(events.ProfileSynced)
+		return []string{strconv.FormatUint(uint64(e.UserID), 10)}
+	})
+}
+
+// Subscribe drops the cached overview of a lecturer whenever one of their sessions is opened
+// or closed, so the home screen shows it right away
+func (s *LecturerOverviewService) Subscribe(bus *events.Bus) {
+	s.cache.InvalidateOn(bus, events.AttendanceSessionOpenedEvent, func(event events.Event) []string {
+		e := event.(events.AttendanceSessionOpened)
+		return []string{strconv.FormatUint(uint64(e.Session.LecturerUserID), 10)}
+	})
+	s.cache.InvalidateOn(bus, events.AttendanceSessionClosedEvent, func(event events.Event) []string {
+		e := event.(events.AttendanceSessionClosed)
+		return []string{strconv.FormatUint(uint64(e.Session.LecturerUserID), 10)}
+	})
+}
+
+// Subscribe streams check-ins and the closing of sessions to the screens watching them
+func (s *LiveAttendanceService) Subscribe(bus *events.Bus) {
+	bus.Subscribe(events.AttendanceCheckedInEvent, func(event events.Event) {
+		e := event.(events.AttendanceCheckedIn)
+		record := e.Record
+		s.broadcast(LiveAttendanceEvent{Type: LiveCheckIn, SessionID: record.SessionID, Record: &record, At: record.CheckedInAt})
+	})
+
+	bus.Subscribe(events.AttendanceSessionClosedEvent, func(event events.Event) {
+		e := event.(events.AttendanceSessionClosed)
+		s.broadcast(LiveAttendanceEvent{Type: LiveSessionClosed, SessionID: e.Session.ID, PresentCount: e.PresentCount, At: time.Now()})
+	})
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// ExamEligibilityService decides which students may sit the exams of a course. It reads the
+// weighted percentage of the course recap, so optional meetings are left out and late
+// check-ins count with their late policy credit.
+type ExamEligibilityService struct {
+	policyRepo     repository.ExamEligibilityRepository
+	attendanceRepo repository.AttendanceRepository
+	enrollmentRepo repository.EnrollmentRepository
+}
+
+// NewExamEligibilityService creates a new ExamEligibilityService
+func NewExamEligibilityService(policyRepo repository.ExamEligibilityRepository, attendanceRepo repository.AttendanceRepository, enrollmentRepo repository.EnrollmentRepository) *ExamEligibilityService {
+	return &ExamEligibilityService{
+		policyRepo:     policyRepo,
+		attendanceRepo: attendanceRepo,
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+// countedMeetings returns how many meetings of a recap count towards percentages
+func countedMeetings(recap *models.AttendanceRecap) int {
+	counted := 0
+	for _, meeting := range recap.Meetings {
+		if !meeting.Optional {
+			counted++
+		}
+	}
+	return counted
+}
+
+// eligible checks a weighted percentage against the minimum; nobody is ineligible before
+// any meeting counts
+func eligible(meetings int, weightedPercent, minPercent float64) bool {
+	return meetings == 0 || weightedPercent >= minPercent
+}
+
+// ForStudent returns the exam eligibility of a student in each course they are enrolled in,
+// optionally for one semester
+func (s *ExamEligibilityService) ForStudent(nim, semester string) ([]models.ExamEligibility, error) {
+	enrollments, err := s.enrollmentRepo.FindByNim(nim, semester)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ExamEligibility, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		policy, err := s.policyRepo.FindForCourse(enrollment.CourseCode)
+		if err != nil {
+			return nil, err
+		}
+		recap, err := s.attendanceRepo.CourseRecap(models.AttendanceRecapFilter{
+			CourseCode: enrollment.CourseCode,
+			Semester:   enrollment.Semester,
+			ClassName:  enrollment.ClassName,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		item := models.ExamEligibility{
+			CourseCode: enrollment.CourseCode,
+			CourseName: enrollment.CourseName,
+			ClassName:  enrollment.ClassName,
+			Semester:   enrollment.Semester,
+			Meetings:   countedMeetings(recap),
+			MinPercent: policy.MinPercentOrDefault(),
+		}
+		for _, student := range recap.Students {
+			if student.Nim == nim {
+				item.WeightedPercent = student.WeightedPercent
+				break
+			}
+		}
+		item.Eligible = eligible(item.Meetings, item.WeightedPercent, item.MinPercent)
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// ForCourse returns the exam eligibility of every student of a course offering
+func (s *ExamEligibilityService) ForCourse(filter models.AttendanceRecapFilter) (*models.ExamEligibilityList, error) {
+	policy, err := s.policyRepo.FindForCourse(filter.CourseCode)
+	if err != nil {
+		return nil, err
+	}
+	recap, err := s.attendanceRepo.CourseRecap(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.ExamEligibilityList{
+		CourseCode: filter.CourseCode,
+		Semester:   filter.Semester,
+		ClassName:  filter.ClassName,
+		Meetings:   countedMeetings(recap),
+		MinPercent: policy.MinPercentOrDefault(),
+		Students:   []models.ExamEligibilityStudent{},
+	}
+	if len(recap.Meetings) == 0 {
+		// No session was held yet, so the recap has no roster; every enrolled student is eligible
+		enrollments, err := s.enrollmentRepo.FindByOffering(models.CourseOffering{
+			CourseCode: filter.CourseCode,
+			ClassName:  filter.ClassName,
+			Semester:   filter.Semester,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, enrollment := range enrollments {
+			recap.Students = append(recap.Students, models.AttendanceRecapStudent{Nim: enrollment.Nim})
+		}
+	}
+
+	for _, student := range recap.Students {
+		item := models.ExamEligibilityStudent{
+			Nim:             student.Nim,
+			WeightedPercent: student.WeightedPercent,
+			Eligible:        eligible(list.Meetings, student.WeightedPercent, list.MinPercent),
+		}
+		if item.Eligible {
+			list.Eligible++
+		}
+		list.Students = append(list.Students, item)
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/xlsx"
+)
+
+// ExportService builds downloadable attendance reports
+type ExportService struct {
+	attendanceRepo repository.AttendanceRepository
+}
+
+// NewExportService creates a new ExportService
+func NewExportService(attendanceRepo repository.AttendanceRepository) *ExportService {
+	return &ExportService{
+		attendanceRepo: attendanceRepo,
+	}
+}
+
+// AttendanceWorkbook builds an Excel workbook of a course recap: a summary sheet with
+// every student's totals, followed by one sheet per meeting
+func (s *ExportService) AttendanceWorkbook(recap *models.AttendanceRecap) (*xlsx.Workbook, error) {
+	workbook := xlsx.NewWorkbook()
+
+	summary := workbook.AddSheet("Summary")
+	summary.AddRow("Course", recap.CourseCode)
+	summary.AddRow("Semester", recap.Semester)
+	summary.AddRow("Class", recap.ClassName)
+	summary.AddRow("Meetings", len(recap.Meetings))
+	summary.AddRow()
+
+	header := []interface{}{"NIM", "Present", "Late", "Excused", "Absent", "Credit", "Weighted %"}
+	for _, meeting := range recap.Meetings {
+		if meeting.Optional {
+			header = append(header, fmt.Sprintf("M%d (optional)", meeting.MeetingNumber))
+		} else {
+			header = append(header, fmt.Sprintf("M%d", meeting.MeetingNumber))
+		}
+	}
+	summary.AddRow(header...)
+	for _, student := range recap.Students {
+		row := []interface{}{student.Nim, student.Present, student.Late, student.Excused, student.Absent, student.Credit, student.WeightedPercent}
+		for _, cell := range student.Meetings {
+			row = append(row, string(cell.Status))
+		}
+		summary.AddRow(row...)
+	}
+
+	for _, meeting := range recap.Meetings {
+		records, err := s.attendanceRepo.FindRecordsBySession(meeting.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		checkIns := make(map[string]models.AttendanceRecord, len(records))
+		for _, record := range records {
+			checkIns[record.Nim] = record
+		}
+
+		sheet := workbook.AddSheet(fmt.Sprintf("Meeting %d", meeting.MeetingNumber))
+		sheet.AddRow("Meeting", meeting.MeetingNumber)
+		sheet.AddRow("Opened at", meeting.OpenedAt)
+		if meeting.Optional {
+			sheet.AddRow("Optional", "Not counted in the summary")
+		}
+		sheet.AddRow()
+		sheet.AddRow("NIM", "Status", "Credit", "Checked in at", "Late (min)", "Method")
+		for _, student := range recap.Students {
+			status, credit := models.AttendanceAbsent, 0.0
+			for _, cell := range student.Meetings {
+				if cell.SessionID == meeting.SessionID {
+					status, credit = cell.Status, cell.Credit
+				}
+			}
+			if record, ok := checkIns[student.Nim]; ok {
+				sheet.AddRow(student.Nim, string(status), credit, record.CheckedInAt, record.LateMinutes, string(record.Method))
+			} else {
+				sheet.AddRow(student.Nim, string(status), credit)
+			}
+		}
+	}
+
+	return workbook, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// Accepted embedding sizes; common face models produce 128 to 512 values
+const (
+	minEmbeddingDimension = 64
+	maxEmbeddingDimension = 1024
+)
+
+var (
+	// ErrInvalidEmbedding is returned for an embedding of the wrong size or with invalid values
+	ErrInvalidEmbedding = errors.New("invalid face embedding")
+	// ErrFaceNotRegistered is returned when a student without a registered face is verified
+	ErrFaceNotRegistered = errors.New("no face registered")
+	// ErrFaceModelMismatch is returned when the embedding comes from another model than the registered one
+	ErrFaceModelMismatch = errors.New("face embedding was produced by a different model")
+	// ErrFaceMismatch is returned when the embedding is not similar enough to the registered one
+	ErrFaceMismatch = errors.New("face does not match")
+)
+
+// FaceService registers and verifies student face embeddings
+type FaceService struct {
+	faceRepo  repository.FaceRepository
+	threshold float64
+}
+
+// NewFaceService creates a new FaceService accepting matches with a cosine similarity of at
+// least threshold
+func NewFaceService(faceRepo repository.FaceRepository, threshold float64) *FaceService {
+	return &FaceService{faceRepo: faceRepo, threshold: threshold}
+}
+
+// MatchThreshold returns the minimum cosine similarity for a match
+func (s *FaceService) MatchThreshold() float64 {
+	return s.threshold
+}
+
+// validateEmbedding checks the size and values of an embedding
+func validateEmbedding(embedding []float64) error {
+	if len(embedding) < minEmbeddingDimension || len(embedding) > maxEmbeddingDimension {
+		return fmt.Errorf("%w: expected %d to %d values, got %d", ErrInvalidEmbedding, minEmbeddingDimension, maxEmbeddingDimension, len(embedding))
+	}
+	var norm float64
+	for _, value := range embedding {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("%w: values must be finite numbers", ErrInvalidEmbedding)
+		}
+		norm += value * value
+	}
+	if norm == 0 {
+		return fmt.Errorf("%w: embedding cannot be all zeros", ErrInvalidEmbedding)
+	}
+	return nil
+}
+
+// cosineSimilarity compares two embeddings of the same size
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Register stores the face embedding of a student, replacing any previous one
+func (s *FaceService) Register(studentUserID uint, nim string, embedding []float64, model string) (*models.FaceData, error) {
+	if err := validateEmbedding(embedding); err != nil {
+		return nil, err
+	}
+
+	face := &models.FaceData{
+		StudentUserID: studentUserID,
+		Nim:           nim,
+		Embedding:     embedding,
+		Dimension:     len(embedding),
+		Model:         model,
+	}
+	if err := s.faceRepo.Save(face); err != nil {
+		return nil, err
+	}
+	return face, nil
+}
+
+// Verify compares an embedding with the one registered by the student and returns the
+// similarity. ErrFaceMismatch is returned together with the score when it is too low.
+func (s *FaceService) Verify(studentUserID uint, embedding []float64, model string) (float64, error) {
+	if err := validateEmbedding(embedding); err != nil {
+		return 0, err
+	}
+
+	face, err := s.faceRepo.FindByStudent(studentUserID)
+	if err != nil {
+		return 0, err
+	}
+	if face == nil {
+		return 0, ErrFaceNotRegistered
+	}
+	if face.Model != "" && model != "" && face.Model != model {
+		return 0, ErrFaceModelMismatch
+	}
+	if len(face.Embedding) != len(embedding) {
+		return 0, fmt.Errorf("%w: expected %d values like the registered face", ErrInvalidEmbedding, len(face.Embedding))
+	}
+
+	score := math.Round(cosineSimilarity(face.Embedding, embedding)*10000) / 10000
+	if score < s.threshold {
+		return score, ErrFaceMismatch
+	}
+	return score, nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"net"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/config"
+)
+
+// FactorRolloutService decides per course whether the face and Wi-Fi factors of check-ins are
+// off, evaluated in shadow or enforced, and keeps what shadow factors would have decided so a
+// factor is only enforced once the would-have-failed rate of the course is known
+type FactorRolloutService struct {
+	rolloutRepo repository.FactorRolloutRepository
+	faceService *FaceService
+	wifi        config.WifiConfig
+	workers     *Workers
+}
+
+// NewFactorRolloutService creates a new FactorRolloutService
+func NewFactorRolloutService(rolloutRepo repository.FactorRolloutRepository, faceService *FaceService, wifi config.WifiConfig, workers *Workers) *FactorRolloutService {
+	return &FactorRolloutService{
+		rolloutRepo: rolloutRepo,
+		faceService: faceService,
+		wifi:        wifi,
+		workers:     workers,
+	}
+}
+
+// Modes returns the mode of each factor rolled out to a course; factors missing from the map
+// follow their feature flag
+func (s *FactorRolloutService) Modes(courseCode string) (map[models.CheckInFactor]models.FactorMode, error) {
+	rollouts, err := s.rolloutRepo.FindByCourse(courseCode)
+	if err != nil {
+		return nil, err
+	}
+	modes := make(map[models.CheckInFactor]models.FactorMode, len(rollouts))
+	for _, rollout := range rollouts {
+		modes[rollout.Factor] = rollout.Mode
+	}
+	return modes, nil
+}
+
+// WifiConfigured reports whether the campus Wi-Fi ranges are set, without which the Wi-Fi
+// factor cannot be evaluated
+func (s *FactorRolloutService) WifiConfigured() bool {
+	return len(s.wifi.Networks) > 0
+}
+
+// EvaluateFace checks a face embedding against the student's registered face without turning
+// the check-in away
+func (s *FactorRolloutService) EvaluateFace(studentUserID uint, embedding []float64, model string) models.ShadowFactorResult {
+	result := models.ShadowFactorResult{Factor: models.FaceFactor}
+	if len(embedding) == 0 {
+		result.Outcome = models.FactorMissing
+		return result
+	}
+
+	score, err := s.faceService.Verify(studentUserID, embedding, model)
+	switch {
+	case err == nil:
+		result.Outcome = models.FactorPassed
+		result.Score = &score
+	case errors.Is(err, ErrFaceMismatch):
+		result.Outcome = models.FactorFailed
+		result.Score = &score
+	case errors.Is(err, ErrInvalidEmbedding):
+		result.Outcome = models.FactorFailed
+		result.Detail = err.Error()
+	default:
+		// No face registered, an outdated model or a storage error
+		result.Outcome = models.FactorUnavailable
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// EvaluateWifi checks whether a client address belongs to the campus Wi-Fi
+func (s *FactorRolloutService) EvaluateWifi(clientIP string) models.ShadowFactorResult {
+	result := models.ShadowFactorResult{Factor: models.WifiFactor}
+	ip := net.ParseIP(clientIP)
+	switch {
+	case !s.WifiConfigured():
+		result.Outcome = models.FactorUnavailable
+		result.Detail = "campus Wi-Fi networks are not configured"
+	case ip == nil:
+		result.Outcome = models.FactorMissing
+		result.Detail = "client address is unknown"
+	case s.wifi.Contains(ip):
+		result.Outcome = models.FactorPassed
+	default:
+		result.Outcome = models.FactorFailed
+		result.Detail = "client address " + clientIP + " is outside the campus Wi-Fi"
+	}
+	return result
+}
+
+// RecordShadow stores the shadow factor results of a check-in attempt in the background so
+// the check-in response is not delayed; accepted tells whether the check-in was recorded
+func (s *FactorRolloutService) RecordShadow(session *models.AttendanceSession, studentUserID uint, results []models.ShadowFactorResult, accepted bool) {
+	if len(results) == 0 {
+		return
+	}
+	for i := range results {
+		results[i].SessionID = session.ID
+		results[i].CourseCode = session.CourseCode
+		results[i].StudentUserID = studentUserID
+		results[i].Accepted = accepted
+	}
+	s.workers.Go(func() {
+		if err := s.rolloutRepo.CreateShadowResults(results); err != nil {
+			log.Printf("[ROLLOUT] Failed to store shadow factor results for session %d: %v", session.ID, err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
+	"delpresence-api/pkg/database"
+)
+
+// Dependency statuses of the readiness probe
+const (
+	DependencyUp            = "up"
+	DependencyDown          = "down"
+	DependencyNotConfigured = "not_configured"
+)
+
+// Readiness outcomes
+const (
+	ReadinessReady       = "ready"
+	ReadinessDegraded    = "degraded"    // Ready, but an optional dependency is down
+	ReadinessUnavailable = "unavailable" // A dependency the API cannot serve without is down
+)
+
+const (
+	// readinessTimeout bounds each dependency probe so a hanging dependency cannot stall the
+	// orchestrator's probe
+	readinessTimeout = 3 * time.Second
+	// readinessCacheTTL is how long a readiness report is reused, so frequent probes from
+	// several replicas do not hammer the campus API
+	readinessCacheTTL = 5 * time.Second
+)
+
+// DependencyStatus is the outcome of probing one dependency
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"` // Whether the API is unavailable while the dependency is down
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ReadinessReport is the outcome of probing every dependency
+type ReadinessReport struct {
+	Status       string             `json:"status"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// HealthService probes the dependencies of the API for readiness checks. The database is
+// critical; the campus API and SMTP are reported but only degrade readiness, since the API keeps
+// serving from local data while they are out.
+type HealthService struct {
+	emailService *EmailService
+	campus       config.CampusConfig
+	breaker      *utils.CircuitBreaker
+
+	mu   sync.Mutex
+	last *ReadinessReport
+}
+
+// NewHealthService creates a new HealthService reading the campus API state from breaker
+func NewHealthService(emailService *EmailService, campus config.CampusConfig, breaker *utils.CircuitBreaker) *HealthService {
+	return &HealthService{
+		emailService: emailService,
+		campus:       campus,
+		breaker:      breaker,
+	}
+}
+
+// Ready probes the dependencies concurrently, or returns the report of a probe made within the
+// last few seconds. Probes do not use the caller's context, since their report is shared.
+func (s *HealthService) Ready() *ReadinessReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last != nil && time.Since(s.last.CheckedAt) < readinessCacheTTL {
+		return s.last
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+	defer cancel()
+
+	probes := []struct {
+		name     string
+		critical bool
+		probe    func(ctx context.Context) (bool, error)
+	}{
+		{"database", true, probeDatabase},
+		{"campus_api", false, s.probeCampus},
+		{"smtp", false, s.probeSMTP},
+	}
+
+	report := &ReadinessReport{
+		Status:       ReadinessReady,
+		CheckedAt:    time.Now(),
+		Dependencies: make([]DependencyStatus, len(probes)),
+	}
+	var wg sync.WaitGroup
+	for i := range probes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start := time.Now()
+			configured, err := probes[i].probe(ctx)
+			status := DependencyStatus{
+				Name:      probes[i].name,
+				Status:    DependencyUp,
+				Critical:  probes[i].critical,
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if !configured {
+				status.Status = DependencyNotConfigured
+			} else if err != nil {
+				status.Status = DependencyDown
+				status.Error = err.Error()
+			}
+			report.Dependencies[i] = status
+		}(i)
+	}
+	wg.Wait()
+
+	for _, dependency := range report.Dependencies {
+		if dependency.Status != DependencyDown {
+			continue
+		}
+		if dependency.Critical {
+			report.Status = ReadinessUnavailable
+			break
+		}
+		report.Status = ReadinessDegraded
+	}
+
+	s.last = report
+	return report
+}
+
+// probeDatabase pings the database
+func probeDatabase(ctx context.Context) (bool, error) {
+	if database.DB == nil {
+		return true, fmt.Errorf("database is not connected")
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return true, err
+	}
+	return true, sqlDB.PingContext(ctx)
+}
+
+// probeCampus checks the campus API answers. While its circuit breaker is open the API makes no
+// calls to it anyway, so the probe reports it down without another request.
+func (s *HealthService) probeCampus(ctx context.Context) (bool, error) {
+	if state := s.breaker.State(); state == utils.CircuitOpen {
+		return true, fmt.Errorf("circuit breaker is %s", state)
+	}
+	timeout := readinessTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	return true, utils.PingCampusAPI(s.campus.BaseURL, timeout)
+}
+
+// probeSMTP checks SMTP is configured. The handshake is left to the startup self-test, so
+// probes do not open a connection to the mail server every few seconds.
+func (s *HealthService) probeSMTP(ctx context.Context) (bool, error) {
+	return s.emailService.IsConfigured(), nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"delpresence-api/internal/repository"
+)
+
+// InternshipService contains internship logic shared by handlers and background jobs
+type InternshipService struct {
+	internshipRepo repository.InternshipRepository
+	mahasiswaRepo  repository.MahasiswaRepository
+	emailService   *EmailService
+}
+
+// NewInternshipService creates a new InternshipService
+func NewInternshipService(internshipRepo repository.InternshipRepository, mahasiswaRepo repository.MahasiswaRepository, emailService *EmailService) *InternshipService {
+	return &InternshipService{
+		internshipRepo: internshipRepo,
+		mahasiswaRepo:  mahasiswaRepo,
+		emailService:   emailService,
+	}
+}
+
+// studentFaculty returns the faculty of a student from the synced profile, or an empty string
+// when the student has not been synced
+func (s *InternshipService) studentFaculty(studentUserID uint) string {
+	snapshot, err := s.mahasiswaRepo.FindSnapshotByUserID(studentUserID)
+	if err != nil || snapshot == nil {
+		return ""
+	}
+	complete, err := snapshot.ToMahasiswaComplete()
+	if err != nil {
+		return ""
+	}
+	return complete.BasicInfo.Fakultas
+}
+
+// SendWeeklySummaries emails the academic supervisor of every active internship a
+// summary of the seven days before now. It returns the number of summaries sent.
+func (s *InternshipService) SendWeeklySummaries(ctx context.Context, now time.Time) (int, error) {
+	to := now.AddDate(0, 0, -1).Format("2006-01-02")
+	from := now.AddDate(0, 0, -7).Format("2006-01-02")
+
+	internships, err := s.internshipRepo.FindActive(to)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, internship := range internships {
+		checkIns, err := s.internshipRepo.FindCheckIns(internship.ID, from, to)
+		if err != nil {
+			log.Printf("Failed to load check-ins for internship %d: %v", internship.ID, err)
+			continue
+		}
+
+		confirmed := 0
+		for _, checkIn := range checkIns {
+			if checkIn.ConfirmedAt != nil {
+				confirmed++
+			}
+		}
+
+		data := EmailData{
+			Subject:       fmt.Sprintf("Ringkasan Mingguan Kerja Praktek %s", internship.Nim),
+			RecipientName: internship.SupervisorName,
+			Faculty:       s.studentFaculty(internship.StudentUserID),
+			Data: map[string]interface{}{
+				"nim":       internship.Nim,
+				"company":   internship.CompanyName,
+				"period":    fmt.Sprintf("%s s.d. %s", from, to),
+				"check_ins": checkIns,
+				"total":     len(checkIns),
+				"confirmed": confirmed,
+			},
+		}
+
+		if err := s.emailService.SendEmail(ctx, internship.SupervisorEmail, "internship_weekly_summary", data); err != nil {
+			log.Printf("Failed to send weekly summary for internship %d: %v", internship.ID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
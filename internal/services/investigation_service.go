@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// CampusLoginAction is the audit log action recorded for every successful campus login
+const CampusLoginAction = "auth.campus_login"
+
+// maxInvestigationLogins caps the campus logins fused into one timeline
+const maxInvestigationLogins = 500
+
+// InvestigationService fuses check-ins, logins and permission requests of a student into one
+// timeline for fraud investigations
+type InvestigationService struct {
+	telemetryRepo  repository.CheckInTelemetryRepository
+	attendanceRepo repository.AttendanceRepository
+	auditRepo      repository.AuditRepository
+	permissionRepo repository.PermissionRequestRepository
+}
+
+// NewInvestigationService creates a new InvestigationService
+func NewInvestigationService(telemetryRepo repository.CheckInTelemetryRepository, attendanceRepo repository.AttendanceRepository, auditRepo repository.AuditRepository, permissionRepo repository.PermissionRequestRepository) *InvestigationService {
+	return &InvestigationService{
+		telemetryRepo:  telemetryRepo,
+		attendanceRepo: attendanceRepo,
+		auditRepo:      auditRepo,
+		permissionRepo: permissionRepo,
+	}
+}
+
+// Timeline returns the activity of a student from the start of from until the end of to.
+// Check-in attempts older than the telemetry retention only show up as attendance records.
+func (s *InvestigationService) Timeline(studentUserID uint, from, to time.Time) (*models.InvestigationTimeline, error) {
+	end := to.AddDate(0, 0, 1)
+	timeline := &models.InvestigationTimeline{
+		StudentUserID: studentUserID,
+		From:          from,
+		To:            to,
+		Events:        []models.InvestigationEvent{},
+		Devices:       []models.InvestigationDevice{},
+		IPAddresses:   []models.InvestigationIPAddress{},
+	}
+	ips := make(map[string]*models.InvestigationIPAddress)
+
+	telemetry, err := s.telemetryRepo.FindByStudent(studentUserID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	devices := make(map[string]*models.InvestigationDevice)
+	for i := range telemetry {
+		attempt := &telemetry[i]
+		summary := fmt.Sprintf("Check-in to session %d rejected (%d)", attempt.SessionID, attempt.HTTPStatus)
+		if attempt.Accepted() {
+			summary = fmt.Sprintf("Check-in to session %d accepted", attempt.SessionID)
+		}
+		timeline.Events = append(timeline.Events, models.InvestigationEvent{
+			At:        attempt.CreatedAt,
+			Kind:      models.InvestigationCheckIn,
+			Summary:   summary,
+			IPAddress: attempt.IPAddress,
+			DeviceID:  attempt.DeviceID,
+			Data:      attempt,
+		})
+		if attempt.IPAddress != "" {
+			seenAt(ips, attempt.IPAddress, attempt.CreatedAt).CheckIns++
+		}
+
+		if attempt.DeviceID == "" {
+			continue
+		}
+		device, ok := devices[attempt.DeviceID]
+		if !ok {
+			device = &models.InvestigationDevice{DeviceID: attempt.DeviceID, FirstSeen: attempt.CreatedAt, SharedWith: []uint{}}
+			devices[attempt.DeviceID] = device
+		}
+		device.DeviceModel = attempt.DeviceModel
+		device.Attempts++
+		if !attempt.Accepted() {
+			device.Rejected++
+		}
+		device.LastSeen = attempt.CreatedAt
+	}
+
+	deviceIDs := make([]string, 0, len(devices))
+	for deviceID := range devices {
+		deviceIDs = append(deviceIDs, deviceID)
+	}
+	sharers, err := s.telemetryRepo.FindDeviceStudents(deviceIDs, studentUserID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, sharer := range sharers {
+		device := devices[sharer.DeviceID]
+		device.SharedWith = append(device.SharedWith, sharer.StudentUserID)
+	}
+	for _, device := range devices {
+		timeline.Devices = append(timeline.Devices, *device)
+	}
+	sort.Slice(timeline.Devices, func(i, j int) bool {
+		return timeline.Devices[i].FirstSeen.Before(timeline.Devices[j].FirstSeen)
+	})
+
+	records, err := s.attendanceRepo.FindRecordsByStudentBetween(studentUserID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		record := &records[i]
+		timeline.Events = append(timeline.Events, models.InvestigationEvent{
+			At:      record.CheckedInAt,
+			Kind:    models.InvestigationAttendance,
+			Summary: fmt.Sprintf("Recorded %s by %s for session %d", record.Status, record.Method, record.SessionID),
+			Data:    record,
+		})
+	}
+
+	logins, err := s.auditRepo.Find(repository.AuditLogFilter{
+		ActorUserID: studentUserID,
+		Action:      CampusLoginAction,
+		From:        &from,
+		To:          &end,
+		Limit:       maxInvestigationLogins,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range logins {
+		login := &logins[i]
+		timeline.Events = append(timeline.Events, models.InvestigationEvent{
+			At:        login.CreatedAt,
+			Kind:      models.InvestigationLogin,
+			Summary:   "Logged in with the campus account",
+			IPAddress: login.IPAddress,
+			Data:      login,
+		})
+		if login.IPAddress != "" {
+			seenAt(ips, login.IPAddress, login.CreatedAt).Logins++
+		}
+	}
+	for _, ip := range ips {
+		timeline.IPAddresses = append(timeline.IPAddresses, *ip)
+	}
+	sort.Slice(timeline.IPAddresses, func(i, j int) bool {
+		return timeline.IPAddresses[i].FirstSeen.Before(timeline.IPAddresses[j].FirstSeen)
+	})
+
+	requests, err := s.permissionRepo.FindByStudent(studentUserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range requests {
+		request := &requests[i]
+		if within(request.CreatedAt, from, end) {
+			timeline.Events = append(timeline.Events, models.InvestigationEvent{
+				At:      request.CreatedAt,
+				Kind:    models.InvestigationPermissionSubmitted,
+				Summary: fmt.Sprintf("Submitted %s request for %s", request.Kind, request.CourseCode),
+				Data:    request,
+			})
+		}
+		if request.DecidedAt != nil && within(*request.DecidedAt, from, end) {
+			timeline.Events = append(timeline.Events, models.InvestigationEvent{
+				At:      *request.DecidedAt,
+				Kind:    models.InvestigationPermissionDecided,
+				Summary: fmt.Sprintf("%s request for %s %s", request.Kind, request.CourseCode, request.Status),
+				Data:    request,
+			})
+		}
+	}
+
+	sort.SliceStable(timeline.Events, func(i, j int) bool {
+		return timeline.Events[i].At.Before(timeline.Events[j].At)
+	})
+	return timeline, nil
+}
+
+// DeviceActivity returns the check-in attempts made from a device by any student from the
+// start of from until the end of to
+func (s *InvestigationService) DeviceActivity(deviceID string, from, to time.Time) ([]models.CheckInTelemetry, error) {
+	return s.telemetryRepo.FindByDevice(deviceID, from, to.AddDate(0, 0, 1))
+}
+
+// seenAt returns the summary of an IP address, extending it to include at
+func seenAt(ips map[string]*models.InvestigationIPAddress, address string, at time.Time) *models.InvestigationIPAddress {
+	ip, ok := ips[address]
+	if !ok {
+		ip = &models.InvestigationIPAddress{IPAddress: address, FirstSeen: at, LastSeen: at}
+		ips[address] = ip
+	}
+	if at.Before(ip.FirstSeen) {
+		ip.FirstSeen = at
+	}
+	if at.After(ip.LastSeen) {
+		ip.LastSeen = at
+	}
+	return ip
+}
+
+// within checks whether at falls in [from, end)
+func within(at, from, end time.Time) bool {
+	return !at.Before(from) && at.Before(end)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// LatePolicyService grades check-ins by lateness according to the late policy of their course
+type LatePolicyService struct {
+	policyRepo     repository.LatePolicyRepository
+	attendanceRepo repository.AttendanceRepository
+}
+
+// NewLatePolicyService creates a new LatePolicyService
+func NewLatePolicyService(policyRepo repository.LatePolicyRepository, attendanceRepo repository.AttendanceRepository) *LatePolicyService {
+	return &LatePolicyService{
+		policyRepo:     policyRepo,
+		attendanceRepo: attendanceRepo,
+	}
+}
+
+// sessionStart is when lateness is counted from: the planned start of sessions created
+// ahead of time, otherwise when the lecturer opened the session
+func sessionStart(session *models.AttendanceSession) time.Time {
+	if session.ScheduledStart != nil {
+		return *session.ScheduledStart
+	}
+	return session.OpenedAt
+}
+
+// lateMinutes returns how many whole minutes after start a check-in was made
+func lateMinutes(start, checkedInAt time.Time) int {
+	if !checkedInAt.After(start) {
+		return 0
+	}
+	return int(checkedInAt.Sub(start) / time.Minute)
+}
+
+// Grade sets the status, lateness and credit of a new check-in to a session
+func (s *LatePolicyService) Grade(session *models.AttendanceSession, record *models.AttendanceRecord) error {
+	policy, err := s.policyRepo.FindForCourse(session.CourseCode)
+	if err != nil {
+		return err
+	}
+	record.LateMinutes = lateMinutes(sessionStart(session), record.CheckedInAt)
+	record.Status, record.Credit = policy.ApplyToSession(session.Optional, record.LateMinutes)
+	return nil
+}
+
+// Recalculate regrades the present and late check-ins of a course after its late policy
+// changed or one of its sessions was marked optional, or of every course without its own policy when courseCode is empty. It returns
+// the number of check-ins whose grade changed.
+func (s *LatePolicyService) Recalculate(courseCode string) (int, error) {
+	policy, err := s.policyRepo.FindForCourse(courseCode)
+	if err != nil {
+		return 0, err
+	}
+
+	timings, err := s.attendanceRepo.FindCheckInTimings(courseCode)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, timing := range timings {
+		minutes := lateMinutes(timing.SessionStart, timing.CheckedInAt)
+		status, credit := policy.ApplyToSession(timing.Optional, minutes)
+		if status == timing.Status && minutes == timing.LateMinutes && credit == timing.Credit {
+			continue
+		}
+		if err := s.attendanceRepo.UpdateRecordGrade(timing.RecordID, status, minutes, credit); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
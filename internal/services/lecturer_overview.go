@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// lecturerOverviewTTL is how long a lecturer's overview is served from memory
+const lecturerOverviewTTL = time.Minute
+
+// unsubmittedJournalWindow is how far back closed sessions without a topic are reported
+const unsubmittedJournalWindow = 30 * 24 * time.Hour
+
+// LecturerOverviewService builds the home screen summary of the lecturer app, caching it
+// briefly because the app requests it every time the screen is shown
+type LecturerOverviewService struct {
+	scheduleRepo    repository.ScheduleRepository
+	attendanceRepo  repository.AttendanceRepository
+	sessionCalendar *SessionCalendar
+	workflow        *WorkflowEngine
+	cache           *cache.Cache
+}
+
+// NewLecturerOverviewService creates a new LecturerOverviewService caching overviews in
+// overviewCache
+func NewLecturerOverviewService(scheduleRepo repository.ScheduleRepository, attendanceRepo repository.AttendanceRepository, sessionCalendar *SessionCalendar, workflow *WorkflowEngine, overviewCache *cache.Cache) *LecturerOverviewService {
+	return &LecturerOverviewService{
+		scheduleRepo:    scheduleRepo,
+		attendanceRepo:  attendanceRepo,
+		sessionCalendar: sessionCalendar,
+		workflow:        workflow,
+		cache:           overviewCache,
+	}
+}
+
+// Overview returns the overview of a lecturer, from the cache when it is still fresh
+func (s *LecturerOverviewService) Overview(lecturerUserID uint) (*models.LecturerOverview, error) {
+	key := strconv.FormatUint(uint64(lecturerUserID), 10)
+	var cached models.LecturerOverview
+	if s.cache.Get(key, &cached) {
+		return &cached, nil
+	}
+
+	overview, err := s.build(lecturerUserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, overview, lecturerOverviewTTL)
+	return overview, nil
+}
+
+// build assembles the overview of a lecturer at now
+func (s *LecturerOverviewService) build(lecturerUserID uint, now time.Time) (*models.LecturerOverview, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	overview := &models.LecturerOverview{
+		Date:                today.Format("2006-01-02"),
+		TodaySchedules:      []models.Schedule{},
+		TodaySessions:       []models.AttendanceSession{},
+		YesterdayRates:      []models.SessionAttendanceRate{},
+		PendingApprovals:    []models.PendingApprovalCount{},
+		UnsubmittedJournals: []models.AttendanceSession{},
+		GeneratedAt:         now,
+	}
+
+	closure, err := s.sessionCalendar.Closure(today)
+	if err != nil {
+		return nil, err
+	}
+	overview.Closure = closure
+
+	// Only the lecturer's latest semester is taught today; semesters sort by name
+	schedules, err := s.scheduleRepo.FindAll(repository.ScheduleFilter{LecturerUserID: lecturerUserID})
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		if schedule.Semester > overview.Semester {
+			overview.Semester = schedule.Semester
+		}
+	}
+	if closure == nil {
+		// Schedules number days 1 (Monday) to 7 (Sunday), time.Weekday 0 (Sunday) to 6
+		day := int(today.Weekday())
+		if day == 0 {
+			day = 7
+		}
+		for _, schedule := range schedules {
+			if schedule.Semester == overview.Semester && schedule.DayOfWeek == day {
+				overview.TodaySchedules = append(overview.TodaySchedules, schedule)
+			}
+		}
+	}
+
+	if overview.TodaySessions, err = s.attendanceRepo.FindSessionsBetween(lecturerUserID, today, tomorrow); err != nil {
+		return nil, err
+	}
+	if overview.YesterdayRates, err = s.attendanceRepo.SessionRates(lecturerUserID, yesterday, today); err != nil {
+		return nil, err
+	}
+	if overview.PendingApprovals, err = s.workflow.PendingApprovals(lecturerUserID); err != nil {
+		return nil, err
+	}
+	if overview.UnsubmittedJournals, err = s.attendanceRepo.FindSessionsWithoutTopic(lecturerUserID, now.Add(-unsubmittedJournalWindow)); err != nil {
+		return nil, err
+	}
+	return overview, nil
+}
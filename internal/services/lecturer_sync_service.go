@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+)
+
+const (
+	// lecturerSyncBatch is how many lecturers are written per statement
+	lecturerSyncBatch = 100
+	// lecturerSyncTimeout bounds a whole run, including the campus API call
+	lecturerSyncTimeout = 10 * time.Minute
+)
+
+// ErrSyncRunning is returned when a sync is triggered while the previous run is still importing
+var ErrSyncRunning = errors.New("a sync is already running")
+
+// LecturerSyncService imports every lecturer of the campus API in batches, so lecturer
+// profiles exist before each lecturer first opens the app
+type LecturerSyncService struct {
+	campusClient *utils.CampusClient
+	lecturerRepo repository.LecturerRepository
+	syncRepo     repository.SyncRunRepository
+	conflicts    *SyncConflictService
+	bus          *events.Bus
+	workers      *Workers
+	mutex        sync.Mutex
+	running      bool
+}
+
+// NewLecturerSyncService creates a new LecturerSyncService
+func NewLecturerSyncService(campusClient *utils.CampusClient, lecturerRepo repository.LecturerRepository, syncRepo repository.SyncRunRepository, conflicts *SyncConflictService, bus *events.Bus, workers *Workers) *LecturerSyncService {
+	return &LecturerSyncService{
+		campusClient: campusClient,
+		lecturerRepo: lecturerRepo,
+		syncRepo:     syncRepo,
+		conflicts:    conflicts,
+		bus:          bus,
+		workers:      workers,
+	}
+}
+
+// Trigger records a new lecturer sync and runs it in the background. The returned run is
+// still running; poll the run list for the result.
+func (s *LecturerSyncService) Trigger(triggeredBy uint) (*models.SyncRun, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.running {
+		return nil, ErrSyncRunning
+	}
+
+	run := &models.SyncRun{
+		Kind:        models.SyncLecturers,
+		Status:      models.SyncRunning,
+		TriggeredBy: triggeredBy,
+		StartedAt:   time.Now(),
+	}
+	if err := s.syncRepo.Create(run); err != nil {
+		return nil, err
+	}
+
+	s.running = true
+	s.workers.Go(func() {
+		s.run(*run)
+		s.mutex.Lock()
+		s.running = false
+		s.mutex.Unlock()
+	})
+
+	return run, nil
+}
+
+// Recent lists the latest lecturer syncs
+func (s *LecturerSyncService) Recent(limit int) ([]models.SyncRun, error) {
+	return s.syncRepo.FindRecent(models.SyncLecturers, limit)
+}
+
+// run imports the lecturers and records the outcome of the run
+func (s *LecturerSyncService) run(run models.SyncRun) {
+	err := s.importLecturers(&run)
+
+	completedAt := time.Now()
+	run.CompletedAt = &completedAt
+	if err != nil {
+		log.Printf("[SYNC] Lecturer sync %d failed: %v", run.ID, err)
+		run.Status = models.SyncFailed
+		run.Error = err.Error()
+	} else {
+		log.Printf("[SYNC] Lecturer sync %d completed: %d created, %d updated, %d skipped", run.ID, run.Created, run.Updated, run.Skipped)
+		run.Status = models.SyncCompleted
+	}
+
+	if err := s.syncRepo.Update(&run); err != nil {
+		log.Printf("[SYNC] Failed to record result of lecturer sync %d: %v", run.ID, err)
+	}
+}
+
+// importLecturers upserts the campus API's lecturers batch by batch, counting them on run
+func (s *LecturerSyncService) importLecturers(run *models.SyncRun) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lecturerSyncTimeout)
+	defer cancel()
+
+	details, err := s.campusClient.GetLecturers(ctx)
+	if err != nil {
+		return err
+	}
+	run.Fetched = len(details)
+
+	now := time.Now()
+	batch := make([]models.Lecturer, 0, lecturerSyncBatch)
+	for start := 0; start < len(details); start += lecturerSyncBatch {
+		batch = batch[:0]
+		userIDs := make([]uint, 0, lecturerSyncBatch)
+		seen := make(map[uint]bool, lecturerSyncBatch)
+		for _, detail := range details[start:min(start+lecturerSyncBatch, len(details))] {
+			// Lecturers are linked to accounts by campus user ID; a row listed twice in one
+			// batch would make the upsert touch the same lecturer twice
+			if detail.UserID == 0 || seen[detail.UserID] {
+				run.Skipped++
+				continue
+			}
+			seen[detail.UserID] = true
+			batch = append(batch, lecturerFromCampus(detail, now))
+			userIDs = append(userIDs, detail.UserID)
+		}
+
+		existing, err := s.lecturerRepo.FindExistingUserIDs(userIDs)
+		if err != nil {
+			return err
+		}
+		merges, err := s.mergeStored(batch, userIDs)
+		if err != nil {
+			return err
+		}
+		if err := s.lecturerRepo.UpsertFromCampus(batch); err != nil {
+			return err
+		}
+		for _, merge := range merges {
+			if err := s.conflicts.Save(merge); err != nil {
+				return err
+			}
+		}
+
+		for _, lecturer := range batch {
+			if existing[lecturer.LecturerUserID] {
+				run.Updated++
+				continue
+			}
+			run.Created++
+			// New profiles get the lecturer role linked to their account like a lazy sync
+			s.bus.Publish(events.ProfileSynced{
+				UserID:      lecturer.LecturerUserID,
+				ProfileType: models.LecturerType,
+				ProfileID:   lecturer.ID,
+				SyncedAt:    lecturer.LastSyncAt,
+			})
+		}
+	}
+	return nil
+}
+
+// mergeStored merges the synced fields of the batch's lecturers that already have a profile
+// with the values their owners edited
+func (s *LecturerSyncService) mergeStored(batch []models.Lecturer, userIDs []uint) ([]*ProfileMerge, error) {
+	stored, err := s.lecturerRepo.FindByUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byUserID := make(map[uint]*models.Lecturer, len(stored))
+	for i := range stored {
+		byUserID[stored[i].LecturerUserID] = &stored[i]
+	}
+
+	var merges []*ProfileMerge
+	for i := range batch {
+		lecturer, ok := byUserID[batch[i].LecturerUserID]
+		if !ok {
+			continue
+		}
+		merge, err := s.conflicts.Merge(models.LecturerType, lecturer.LecturerUserID, lecturer.LastSyncAt,
+			lecturer.SyncedFields(), batch[i].SyncedFields())
+		if err != nil {
+			return nil, err
+		}
+		merges = append(merges, merge)
+	}
+	return merges, nil
+}
+
+// lecturerFromCampus maps a lecturer of the campus API to a lecturer profile
+func lecturerFromCampus(detail models.CampusLecturerDetail, syncedAt time.Time) models.Lecturer {
+	return models.Lecturer{
+		// Campus users act under their campus user ID
+		LecturerUserID:   detail.UserID,
+		CampusUserID:     detail.UserID,
+		EmployeeID:       detail.PegawaiID,
+		LecturerID:       detail.DosenID,
+		IdentityNumber:   detail.NIP,
+		LecturerNumber:   detail.NIDN,
+		FullName:         detail.Nama,
+		Email:            detail.Email,
+		DepartmentID:     detail.ProdiID,
+		Department:       detail.Prodi,
+		AcademicRank:     detail.JabatanAkademik,
+		AcademicRankDesc: detail.JabatanAkademikDesc,
+		EducationLevel:   detail.JenjangPendidikan,
+		Status:           "Active",
+		LastSyncAt:       syncedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"delpresence-api/internal/models"
+)
+
+// liveAttendanceBuffer is how many events a watcher can fall behind before it misses some
+const liveAttendanceBuffer = 64
+
+// Types of the live attendance events
+const (
+	LiveCheckIn       = "check_in"
+	LiveSessionClosed = "session_closed"
+)
+
+// LiveAttendanceEvent is an update streamed to the screens watching a session
+type LiveAttendanceEvent struct {
+	Type         string                   `json:"type"`
+	SessionID    uint                     `json:"session_id"`
+	Record       *models.AttendanceRecord `json:"record,omitempty"`        // Set for check-ins
+	PresentCount int                      `json:"present_count,omitempty"` // Set when the session is closed
+	At           time.Time                `json:"at"`
+}
+
+// LiveAttendanceService fans the check-ins of attendance sessions out to the screens watching
+// them, such as the lecturer's dashboard. Events only reach watchers connected to the instance
+// the check-in was made on.
+type LiveAttendanceService struct {
+	mutex    sync.Mutex
+	watchers map[uint]map[chan LiveAttendanceEvent]struct{} // By session ID
+}
+
+// NewLiveAttendanceService creates a new LiveAttendanceService
+func NewLiveAttendanceService() *LiveAttendanceService {
+	return &LiveAttendanceService{
+		watchers: make(map[uint]map[chan LiveAttendanceEvent]struct{}),
+	}
+}
+
+// Watch subscribes to the events of a session. The returned function unsubscribes and must
+// be called once the watcher is gone.
+func (s *LiveAttendanceService) Watch(sessionID uint) (<-chan LiveAttendanceEvent, func()) {
+	events := make(chan LiveAttendanceEvent, liveAttendanceBuffer)
+
+	s.mutex.Lock()
+	if s.watchers[sessionID] == nil {
+		s.watchers[sessionID] = make(map[chan LiveAttendanceEvent]struct{})
+	}
+	s.watchers[sessionID][events] = struct{}{}
+	s.mutex.Unlock()
+
+	return events, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.watchers[sessionID], events)
+		if len(s.watchers[sessionID]) == 0 {
+			delete(s.watchers, sessionID)
+		}
+	}
+}
+
+// broadcast hands an event to every watcher of its session. Watchers too slow to keep up miss
+// it rather than holding up the others; they catch up by reloading the session's records.
+func (s *LiveAttendanceService) broadcast(event LiveAttendanceEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for events := range s.watchers[event.SessionID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
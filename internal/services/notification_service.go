@@ -0,0 +1,34 @@
+package services
+
+import (
+	"log"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// NotificationService delivers in-app notifications to users
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService creates a new NotificationService
+func NewNotificationService(notificationRepo repository.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notify stores a notification for a user. Failures are logged but never block the caller.
+func (s *NotificationService) Notify(userID uint, notificationType, title, message string) {
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+	}
+
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("Failed to store %s notification for user %d: %v", notificationType, userID, err)
+	}
+}
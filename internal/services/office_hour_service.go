@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// officeHourReminderInterval is how often slots due for a reminder are looked for
+const officeHourReminderInterval = 5 * time.Minute
+
+// officeHourLabel describes a slot in notifications, e.g. "2024-03-04 10:00-11:00 di Ruang Dosen"
+func officeHourLabel(slot *models.OfficeHourSlot) string {
+	return fmt.Sprintf("%s %s-%s di %s", slot.Date.Format("2006-01-02"), slot.StartTime, slot.EndTime, slot.Location)
+}
+
+// OfficeHourService reminds lecturers and students of upcoming office-hour slots
+type OfficeHourService struct {
+	officeHourRepo repository.OfficeHourRepository
+	notifications  *NotificationService
+	lead           time.Duration
+}
+
+// NewOfficeHourService creates a new OfficeHourService sending reminders lead before a slot starts
+func NewOfficeHourService(officeHourRepo repository.OfficeHourRepository, notifications *NotificationService, lead time.Duration) *OfficeHourService {
+	return &OfficeHourService{
+		officeHourRepo: officeHourRepo,
+		notifications:  notifications,
+		lead:           lead,
+	}
+}
+
+// SendReminders notifies the lecturer and booked students of every slot starting within the
+// reminder lead of now. Slots without bookings only remind their lecturer.
+func (s *OfficeHourService) SendReminders(now time.Time) error {
+	cutoff := now.Add(s.lead)
+	slots, err := s.officeHourRepo.FindUnremindedSlots(now, cutoff)
+	if err != nil {
+		return err
+	}
+
+	for i := range slots {
+		slot := &slots[i]
+		startsAt := slot.StartsAt()
+		if startsAt.Before(now) || startsAt.After(cutoff) {
+			continue
+		}
+
+		bookings, err := s.officeHourRepo.FindBookingsBySlot(slot.ID)
+		if err != nil {
+			return err
+		}
+		// Marked first so a failure below never sends the same reminder twice
+		if err := s.officeHourRepo.MarkReminded(slot.ID); err != nil {
+			return err
+		}
+
+		label := officeHourLabel(slot)
+		s.notifications.Notify(slot.LecturerUserID, "office_hour.reminder", "Pengingat jam konsultasi",
+			fmt.Sprintf("Jam konsultasi %s dimulai segera dengan %d pemesanan", label, len(bookings)))
+		for _, booking := range bookings {
+			s.notifications.Notify(booking.StudentUserID, "office_hour.reminder", "Pengingat jam konsultasi",
+				"Jam konsultasi yang Anda pesan "+label+" dimulai segera")
+		}
+	}
+	return nil
+}
+
+// RunReminders sends due reminders every few minutes until stop is closed
+func (s *OfficeHourService) RunReminders(stop <-chan struct{}) {
+	ticker := time.NewTicker(officeHourReminderInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.SendReminders(time.Now()); err != nil {
+			log.Printf("[OFFICE HOURS] Failed to send office-hour reminders: %v", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/xlsx"
+)
+
+// pddiktiSemesterPattern matches semesters such as "2024/2025 Ganjil"
+var pddiktiSemesterPattern = regexp.MustCompile(`^(\d{4})\s*/\s*\d{4}\s+(\S+)`)
+
+// PDDIKTISemester converts a semester such as "2024/2025 Ganjil" to the PDDIKTI semester ID
+// "20241" (2 for Genap, 3 for Pendek). Semesters in another format are returned unchanged.
+func PDDIKTISemester(semester string) string {
+	match := pddiktiSemesterPattern.FindStringSubmatch(strings.TrimSpace(semester))
+	if match == nil {
+		return semester
+	}
+	switch strings.ToLower(match[2]) {
+	case "ganjil":
+		return match[1] + "1"
+	case "genap":
+		return match[1] + "2"
+	case "pendek", "antara":
+		return match[1] + "3"
+	}
+	return semester
+}
+
+// PDDIKTIExportService builds the class activity of a course offering in the layout of the
+// PDDIKTI semester report: the class, its lecturers with planned and held meetings, and its
+// students with their attendance
+type PDDIKTIExportService struct {
+	attendanceRepo  repository.AttendanceRepository
+	scheduleRepo    repository.ScheduleRepository
+	lecturerRepo    repository.LecturerRepository
+	plannedMeetings int
+}
+
+// NewPDDIKTIExportService creates a new PDDIKTIExportService reporting plannedMeetings
+// meetings per class
+func NewPDDIKTIExportService(attendanceRepo repository.AttendanceRepository, scheduleRepo repository.ScheduleRepository, lecturerRepo repository.LecturerRepository, plannedMeetings int) *PDDIKTIExportService {
+	return &PDDIKTIExportService{
+		attendanceRepo:  attendanceRepo,
+		scheduleRepo:    scheduleRepo,
+		lecturerRepo:    lecturerRepo,
+		plannedMeetings: plannedMeetings,
+	}
+}
+
+// Workbook builds the PDDIKTI workbook of a course offering, with NIMs pseudonymized when
+// pseudonymizer is not nil. It returns nil when the course is not scheduled in the semester.
+func (s *PDDIKTIExportService) Workbook(filter models.AttendanceRecapFilter, pseudonymizer *pseudonym.Pseudonymizer) (*xlsx.Workbook, error) {
+	schedules, err := s.scheduleRepo.FindAll(repository.ScheduleFilter{
+		Semester:   filter.Semester,
+		CourseCode: filter.CourseCode,
+		ClassName:  filter.ClassName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	recap, err := s.attendanceRepo.CourseRecap(filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.attendanceRepo.LecturerMeetingCounts(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled lecturers are reported even before they held a meeting
+	held := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		held[count.LecturerUserID] = count.Meetings
+	}
+	for _, schedule := range schedules {
+		if _, ok := held[schedule.LecturerUserID]; !ok {
+			held[schedule.LecturerUserID] = 0
+		}
+	}
+	lecturerIDs := make([]uint, 0, len(held))
+	for id := range held {
+		lecturerIDs = append(lecturerIDs, id)
+	}
+	sort.Slice(lecturerIDs, func(i, j int) bool { return lecturerIDs[i] < lecturerIDs[j] })
+	lecturers, err := s.lecturerRepo.FindByUserIDs(lecturerIDs)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[uint]models.Lecturer, len(lecturers))
+	for _, lecturer := range lecturers {
+		profiles[lecturer.LecturerUserID] = lecturer
+	}
+
+	semester := PDDIKTISemester(filter.Semester)
+	className := filter.ClassName
+	if className == "" {
+		className = schedules[0].ClassName
+	}
+	heldMeetings := countedMeetings(recap)
+
+	workbook := xlsx.NewWorkbook()
+
+	class := workbook.AddSheet("Kelas Kuliah")
+	class.AddRow("id_semester", "kode_mata_kuliah", "nama_mata_kuliah", "nama_kelas_kuliah", "rencana_tatap_muka", "realisasi_tatap_muka", "jumlah_peserta")
+	class.AddRow(semester, filter.CourseCode, schedules[0].CourseName, className, s.plannedMeetings, heldMeetings, len(recap.Students))
+
+	teaching := workbook.AddSheet("Dosen Pengajar")
+	teaching.AddRow("id_semester", "kode_mata_kuliah", "nama_kelas_kuliah", "nidn", "nama_dosen", "rencana_tatap_muka", "realisasi_tatap_muka")
+	for _, id := range lecturerIDs {
+		profile := profiles[id]
+		teaching.AddRow(semester, filter.CourseCode, className, profile.LecturerNumber, profile.FullName, s.plannedMeetings, held[id])
+	}
+
+	students := workbook.AddSheet("Peserta Kelas")
+	students.AddRow("id_semester", "kode_mata_kuliah", "nama_kelas_kuliah", "nim", "hadir", "terlambat", "izin", "alpa", "persentase_kehadiran")
+	for _, student := range recap.Students {
+		nim := student.Nim
+		if pseudonymizer != nil {
+			nim = pseudonymizer.Nim(nim)
+		}
+		students.AddRow(semester, filter.CourseCode, className, nim, student.Present, student.Late, student.Excused, student.Absent, student.WeightedPercent)
+	}
+
+	return workbook, nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+)
+
+// PermissionWorkflowType is the workflow type of student permission (izin and sakit) requests
+const PermissionWorkflowType = "permission"
+
+// Actions available on a pending permission request
+const (
+	PermissionApproveAction = "approve"
+	PermissionRejectAction  = "reject"
+	PermissionCancelAction  = "cancel"
+)
+
+// PermissionWorkflow defines how a permission request is decided by the lecturer of the
+// course or withdrawn by the student. Its states match models.PermissionRequestStatus.
+func PermissionWorkflow() WorkflowDefinition {
+	pending := string(models.PermissionPending)
+	approved := string(models.PermissionApproved)
+	rejected := string(models.PermissionRejected)
+	cancelled := string(models.PermissionCancelled)
+
+	return WorkflowDefinition{
+		Type:    PermissionWorkflowType,
+		Scope:   models.LeaveApprovals,
+		Initial: pending,
+		States: map[string]WorkflowState{
+			pending:   {SLA: 3 * 24 * time.Hour},
+			approved:  {Terminal: true},
+			rejected:  {Terminal: true},
+			cancelled: {Terminal: true},
+		},
+		Rules: []WorkflowRule{
+			{From: pending, Action: PermissionApproveAction, To: approved},
+			{From: pending, Action: PermissionRejectAction, To: rejected},
+			{From: pending, Action: PermissionCancelAction, To: cancelled, ByRequester: true},
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/repository"
+)
+
+// prodiCacheTTL is how long a resolved prodi is reused; syncing the user's profile drops it
+const prodiCacheTTL = time.Hour
+
+// ProdiResolver finds the prodi of a user from locally synced profiles, caching the result
+// so it can be used on every request
+type ProdiResolver struct {
+	mahasiswaRepo repository.MahasiswaRepository
+	lecturerRepo  repository.LecturerRepository
+	cache         *cache.Cache
+}
+
+// NewProdiResolver creates a new ProdiResolver caching prodis in prodiCache
+func NewProdiResolver(mahasiswaRepo repository.MahasiswaRepository, lecturerRepo repository.LecturerRepository, prodiCache *cache.Cache) *ProdiResolver {
+	return &ProdiResolver{
+		mahasiswaRepo: mahasiswaRepo,
+		lecturerRepo:  lecturerRepo,
+		cache:         prodiCache,
+	}
+}
+
+// Resolve returns the prodi name of the principal, or an empty string when unknown
+func (r *ProdiResolver) Resolve(principal *auth.Principal) string {
+	if principal == nil || principal.IsAdmin() {
+		return ""
+	}
+
+	key := strconv.FormatUint(uint64(principal.UserID), 10)
+	var prodi string
+	if r.cache.Get(key, &prodi) {
+		return prodi
+	}
+
+	prodi = r.lookup(principal.UserID)
+	if prodi != "" {
+		r.cache.Set(key, prodi, prodiCacheTTL)
+	}
+	return prodi
+}
+
+// ResolveUser returns the prodi name of a non-admin user, or an empty string when unknown
+func (r *ProdiResolver) ResolveUser(userID uint) string {
+	return r.Resolve(&auth.Principal{UserID: userID})
+}
+
+// lookup reads the prodi from the student snapshot or lecturer profile
+func (r *ProdiResolver) lookup(userID uint) string {
+	if snapshot, err := r.mahasiswaRepo.FindSnapshotByUserID(userID); err == nil && snapshot != nil {
+		if complete, err := snapshot.ToMahasiswaComplete(); err == nil && complete.BasicInfo.ProdiName != "" {
+			return complete.BasicInfo.ProdiName
+		}
+	}
+	if lecturer, err := r.lecturerRepo.FindByUserID(userID); err == nil && lecturer != nil {
+		return lecturer.Department
+	}
+	return ""
+}
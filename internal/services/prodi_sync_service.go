@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+)
+
+const (
+	// prodiSyncPoll is how often the worker looks for queued profiles it was not woken for,
+	// such as those left by a restart, and how long it backs off while the campus API is down
+	prodiSyncPoll = time.Minute
+	// prodiSyncBatch is how many queued profiles are loaded at a time
+	prodiSyncBatch = 50
+	// prodiSyncTimeout bounds the campus API lookups of one profile
+	prodiSyncTimeout = 30 * time.Second
+)
+
+// ErrNoProdiProfiles is returned when a prodi has no local profiles to re-sync
+var ErrNoProdiProfiles = errors.New("prodi has no synced profiles")
+
+// ProdiSyncService re-syncs every local lecturer, assistant and student profile of a prodi
+// from the campus API, for start-of-semester data refreshes. Profiles are queued in the
+// database and synced one at a time by a worker that paces its campus API lookups.
+type ProdiSyncService struct {
+	campusClient  *utils.CampusClient
+	syncRepo      repository.SyncRunRepository
+	lecturerRepo  repository.LecturerRepository
+	assistantRepo repository.AssistantRepository
+	mahasiswaRepo repository.MahasiswaRepository
+	conflicts     *SyncConflictService
+	bus           *events.Bus
+	interval      time.Duration // Pause between campus API lookups
+	wake          chan struct{}
+	triggerMutex  sync.Mutex // Keeps two triggers from queuing the same prodi twice
+}
+
+// NewProdiSyncService creates a new ProdiSyncService pausing interval between profiles
+func NewProdiSyncService(campusClient *utils.CampusClient, syncRepo repository.SyncRunRepository, lecturerRepo repository.LecturerRepository, assistantRepo repository.AssistantRepository, mahasiswaRepo repository.MahasiswaRepository, conflicts *SyncConflictService, bus *events.Bus, interval time.Duration) *ProdiSyncService {
+	return &ProdiSyncService{
+		campusClient:  campusClient,
+		syncRepo:      syncRepo,
+		lecturerRepo:  lecturerRepo,
+		assistantRepo: assistantRepo,
+		mahasiswaRepo: mahasiswaRepo,
+		conflicts:     conflicts,
+		bus:           bus,
+		interval:      interval,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Trigger queues every local profile of the prodi for a re-sync. The returned run is still
+// running; poll Progress for the result.
+func (s *ProdiSyncService) Trigger(prodiID, triggeredBy uint) (*models.SyncRun, error) {
+	s.triggerMutex.Lock()
+	defer s.triggerMutex.Unlock()
+
+	running, err := s.syncRepo.FindRunning(models.SyncProdi)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range running {
+		if run.ProdiID != nil && *run.ProdiID == prodiID {
+			return nil, ErrSyncRunning
+		}
+	}
+
+	items, err := s.syncRepo.FindProdiProfiles(prodiID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrNoProdiProfiles
+	}
+
+	run := &models.SyncRun{
+		Kind:        models.SyncProdi,
+		Status:      models.SyncRunning,
+		ProdiID:     &prodiID,
+		Fetched:     len(items),
+		TriggeredBy: triggeredBy,
+		StartedAt:   time.Now(),
+	}
+	if err := s.syncRepo.CreateWithItems(run, items); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return run, nil
+}
+
+// Recent lists the latest prodi syncs
+func (s *ProdiSyncService) Recent(limit int) ([]models.SyncRun, error) {
+	return s.syncRepo.FindRecent(models.SyncProdi, limit)
+}
+
+// Progress returns a prodi sync, or nil when there is none with the ID
+func (s *ProdiSyncService) Progress(runID uint) (*models.SyncRun, error) {
+	run, err := s.syncRepo.FindByID(runID)
+	if err != nil || run == nil || run.Kind != models.SyncProdi {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Errors lists the profiles of a prodi sync that could not be synced, with the reason
+func (s *ProdiSyncService) Errors(runID uint) ([]models.SyncRunItem, error) {
+	return s.syncRepo.FindFailedItems(runID)
+}
+
+// Run syncs queued profiles until stop is closed, oldest run first
+func (s *ProdiSyncService) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(prodiSyncPoll)
+	defer ticker.Stop()
+
+	for {
+		s.processRunning(stop)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// Process syncs the queued profiles of a run in the foreground, for operators running a sync
+// from the command line. It returns false when it stopped early; the remaining profiles stay
+// queued for the worker.
+func (s *ProdiSyncService) Process(run *models.SyncRun, stop <-chan struct{}) bool {
+	return s.process(run, stop)
+}
+
+// processRunning works through the queued profiles of every running prodi sync
+func (s *ProdiSyncService) processRunning(stop <-chan struct{}) {
+	runs, err := s.syncRepo.FindRunning(models.SyncProdi)
+	if err != nil {
+		log.Printf("[SYNC] Failed to load running prodi syncs: %v", err)
+		return
+	}
+	for i := range runs {
+		if !s.process(&runs[i], stop) {
+			return
+		}
+	}
+}
+
+// process syncs the queued profiles of a run and completes it when none are left. It returns
+// false when it stopped early, because of stop or because the campus API is unavailable.
+func (s *ProdiSyncService) process(run *models.SyncRun, stop <-chan struct{}) bool {
+	for {
+		items, err := s.syncRepo.FindPendingItems(run.ID, prodiSyncBatch)
+		if err != nil {
+			log.Printf("[SYNC] Failed to load queued profiles of prodi sync %d: %v", run.ID, err)
+			return false
+		}
+		if len(items) == 0 {
+			s.complete(run)
+			return true
+		}
+
+		for i := range items {
+			select {
+			case <-stop:
+				return false
+			default:
+			}
+
+			err := s.syncProfile(&items[i])
+			if err != nil && s.campusClient.IsUnavailable(err) {
+				// Leave the profile queued and try again once the campus API recovers
+				log.Printf("[SYNC] Campus API unavailable, pausing prodi sync %d: %v", run.ID, err)
+				return false
+			}
+
+			processedAt := time.Now()
+			items[i].ProcessedAt = &processedAt
+			items[i].Status = models.SyncItemSynced
+			if err != nil {
+				items[i].Status = models.SyncItemFailed
+				items[i].Error = err.Error()
+			}
+			if err := s.syncRepo.FinishItem(run, &items[i]); err != nil {
+				log.Printf("[SYNC] Failed to record sync of %s %d: %v", items[i].ProfileType, items[i].UserID, err)
+				return false
+			}
+
+			select {
+			case <-stop:
+				return false
+			case <-time.After(s.interval):
+			}
+		}
+	}
+}
+
+// complete records the outcome of a run whose queued profiles were all processed
+func (s *ProdiSyncService) complete(run *models.SyncRun) {
+	completedAt := time.Now()
+	run.CompletedAt = &completedAt
+	run.Status = models.SyncCompleted
+	if run.Failed > 0 {
+		run.Error = fmt.Sprintf("%d of %d profiles could not be synced", run.Failed, run.Fetched)
+	}
+	log.Printf("[SYNC] Prodi sync %d completed: %d synced, %d failed", run.ID, run.Updated, run.Failed)
+
+	if err := s.syncRepo.Update(run); err != nil {
+		log.Printf("[SYNC] Failed to record result of prodi sync %d: %v", run.ID, err)
+	}
+}
+
+// syncProfile refreshes one queued profile from the campus API
+func (s *ProdiSyncService) syncProfile(item *models.SyncRunItem) error {
+	ctx, cancel := context.WithTimeout(context.Background(), prodiSyncTimeout)
+	defer cancel()
+
+	switch item.ProfileType {
+	case models.LecturerType:
+		return s.syncLecturer(ctx, item.UserID)
+	case models.AssistantType:
+		return s.syncAssistant(ctx, item.UserID)
+	case models.StudentType:
+		return s.syncStudent(ctx, item.UserID)
+	}
+	return fmt.Errorf("unknown profile type %q", item.ProfileType)
+}
+
+// syncLecturer refreshes a lecturer profile, keeping the fields the lecturer edited
+func (s *ProdiSyncService) syncLecturer(ctx context.Context, userID uint) error {
+	lecturer, err := s.lecturerRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if lecturer == nil {
+		return errors.New("lecturer profile no longer exists")
+	}
+
+	detail, err := s.campusClient.GetLecturerByUserID(ctx, lecturer.CampusUserID)
+	if err != nil {
+		return err
+	}
+	synced := lecturerFromCampus(*detail, time.Now())
+	synced.LecturerUserID = lecturer.LecturerUserID
+	merge, err := s.conflicts.Merge(models.LecturerType, userID, lecturer.LastSyncAt, lecturer.SyncedFields(), synced.SyncedFields())
+	if err != nil {
+		return err
+	}
+	if err := s.lecturerRepo.UpsertFromCampus([]models.Lecturer{synced}); err != nil {
+		return err
+	}
+	if err := s.conflicts.Save(merge); err != nil {
+		return err
+	}
+
+	s.publishSynced(userID, models.LecturerType, lecturer.ID, synced.LastSyncAt)
+	return nil
+}
+
+// syncAssistant refreshes the campus fields of an assistant profile, keeping the fields the
+// assistant edited
+func (s *ProdiSyncService) syncAssistant(ctx context.Context, userID uint) error {
+	assistant, err := s.assistantRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if assistant == nil {
+		return errors.New("assistant profile no longer exists")
+	}
+
+	detail, err := s.campusClient.GetEmployeeByUserID(ctx, assistant.CampusUserID)
+	if err != nil {
+		return err
+	}
+	synced := *assistant
+	synced.EmployeeID = detail.PegawaiID
+	synced.IdentityNumber = detail.NIP
+	synced.FullName = detail.Nama
+	synced.Email = detail.Email
+	synced.Username = detail.UserName
+	synced.Alias = strings.TrimSpace(detail.Alias)
+	synced.Position = strings.TrimSpace(detail.Posisi)
+	synced.EmployeeStatus = detail.StatusPegawai
+	synced.LastSyncAt = time.Now()
+	merge, err := s.conflicts.Merge(models.AssistantType, userID, assistant.LastSyncAt, assistant.SyncedFields(), synced.SyncedFields())
+	if err != nil {
+		return err
+	}
+	if err := s.assistantRepo.Update(&synced); err != nil {
+		return err
+	}
+	if err := s.conflicts.Save(merge); err != nil {
+		return err
+	}
+
+	s.publishSynced(userID, models.AssistantType, synced.ID, synced.LastSyncAt)
+	return nil
+}
+
+// syncStudent refreshes the local snapshot of a student
+func (s *ProdiSyncService) syncStudent(ctx context.Context, userID uint) error {
+	complete, err := s.campusClient.GetMahasiswaComplete(ctx, int(userID))
+	if err != nil {
+		return err
+	}
+	snapshot, err := models.NewMahasiswaSnapshot(complete)
+	if err != nil {
+		return err
+	}
+	if err := s.mahasiswaRepo.SaveSnapshot(snapshot); err != nil {
+		return err
+	}
+
+	s.publishSynced(userID, models.StudentType, snapshot.ID, snapshot.LastSyncAt)
+	return nil
+}
+
+// publishSynced announces a profile refreshed by a prodi sync
+func (s *ProdiSyncService) publishSynced(userID uint, profileType models.UserType, profileID uint, syncedAt time.Time) {
+	s.bus.Publish(events.ProfileSynced{
+		UserID:      userID,
+		ProfileType: profileType,
+		ProfileID:   profileID,
+		SyncedAt:    syncedAt,
+	})
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+)
+
+// Reidentification is the student behind a pseudonym. Either field may be empty when the
+// student is only known by the other identifier.
+type Reidentification struct {
+	Pseudonym string `json:"pseudonym"`
+	Kind      string `json:"kind"` // "nim" or "user_id", the identifier the pseudonym was derived from
+	Nim       string `json:"nim,omitempty"`
+	UserID    uint   `json:"user_id,omitempty"`
+}
+
+// PseudonymService maps pseudonyms back to students. Pseudonyms are keyed hashes and cannot be
+// reversed, so the service hashes every student the API knows of until one matches.
+type PseudonymService struct {
+	pseudonymizer *pseudonym.Pseudonymizer
+	mahasiswaRepo repository.MahasiswaRepository
+}
+
+// NewPseudonymService creates a new PseudonymService
+func NewPseudonymService(pseudonymizer *pseudonym.Pseudonymizer, mahasiswaRepo repository.MahasiswaRepository) *PseudonymService {
+	return &PseudonymService{
+		pseudonymizer: pseudonymizer,
+		mahasiswaRepo: mahasiswaRepo,
+	}
+}
+
+// Reidentify returns the student a pseudonym was derived from, or nil when no known student
+// matches it
+func (s *PseudonymService) Reidentify(value string) (*Reidentification, error) {
+	nims, err := s.mahasiswaRepo.FindKnownNIMs()
+	if err != nil {
+		return nil, err
+	}
+	for _, nim := range nims {
+		if s.pseudonymizer.Nim(nim) != value {
+			continue
+		}
+		result := &Reidentification{Pseudonym: value, Kind: "nim", Nim: nim}
+		snapshot, err := s.mahasiswaRepo.FindSnapshotByNIM(nim)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			result.UserID = snapshot.UserID
+		}
+		return result, nil
+	}
+
+	userIDs, err := s.mahasiswaRepo.FindKnownUserIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		if s.pseudonymizer.UserID(userID) != value {
+			continue
+		}
+		result := &Reidentification{Pseudonym: value, Kind: "user_id", UserID: userID}
+		snapshot, err := s.mahasiswaRepo.FindSnapshotByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			result.Nim = snapshot.Nim
+		}
+		return result, nil
+	}
+	return nil, nil
+}
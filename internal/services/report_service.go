@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/pkg/pdf"
+	"delpresence-api/pkg/qrcode"
+)
+
+// AttendanceReportInfo describes the course and lecturer of a PDF attendance report
+type AttendanceReportInfo struct {
+	CourseName   string
+	Meetings     int    // Held meetings, optional ones included
+	LecturerName string // Left blank in the signature block when unknown
+	LecturerNIP  string
+	// Printed with a QR code of VerificationURL so third parties can check the report
+	VerificationCode string
+	VerificationURL  string
+}
+
+// ReportService renders printable reports
+type ReportService struct {
+	institution string
+}
+
+// NewReportService creates a new ReportService printing institution in report headers
+func NewReportService(institution string) *ReportService {
+	return &ReportService{
+		institution: institution,
+	}
+}
+
+// AttendancePDF renders a course recap as a PDF with the institution header, course
+// details, each student's totals and a signature block for the lecturer
+func (s *ReportService) AttendancePDF(recap *models.AttendanceRecap, info AttendanceReportInfo) *pdf.Document {
+	doc := pdf.New()
+
+	doc.Text(s.institution, 14, true, "center")
+	doc.Text("Rekapitulasi Kehadiran Mahasiswa", 12, true, "center")
+	doc.Rule()
+	doc.Space(6)
+
+	course := recap.CourseCode
+	if info.CourseName != "" {
+		course += " - " + info.CourseName
+	}
+	details := [][2]string{
+		{"Mata Kuliah", course},
+		{"Kelas", recap.ClassName},
+		{"Semester", recap.Semester},
+		{"Jumlah Pertemuan", fmt.Sprintf("%d", info.Meetings)},
+	}
+	for _, detail := range details {
+		if detail[1] != "" {
+			doc.Text(fmt.Sprintf("%-18s: %s", detail[0], detail[1]), 10, false, "left")
+		}
+	}
+	doc.Space(10)
+
+	widths := []float64{30, 110, 55, 55, 55, 55, 65, 70.28}
+	doc.Row(widths, []string{"No", "NIM", "Hadir", "Terlambat", "Izin", "Alpa", "Kredit", "Kehadiran %"}, 9, true)
+	for i, student := range recap.Students {
+		doc.Row(widths, []string{
+			fmt.Sprintf("%d", i+1),
+			student.Nim,
+			fmt.Sprintf("%d", student.Present),
+			fmt.Sprintf("%d", student.Late),
+			fmt.Sprintf("%d", student.Excused),
+			fmt.Sprintf("%d", student.Absent),
+			fmt.Sprintf("%.2f", student.Credit),
+			fmt.Sprintf("%.2f", student.WeightedPercent),
+		}, 9, false)
+	}
+
+	// Signature block, right aligned
+	doc.Space(30)
+	doc.Text("Dicetak "+time.Now().Format("02-01-2006"), 10, false, "right")
+	doc.Text("Dosen Pengampu,", 10, false, "right")
+	doc.Space(50)
+	name := info.LecturerName
+	if name == "" {
+		name = "(..............................)"
+	}
+	doc.Text(name, 10, true, "right")
+	if info.LecturerNIP != "" {
+		doc.Text("NIP. "+info.LecturerNIP, 10, false, "right")
+	}
+
+	if info.VerificationCode != "" {
+		if modules, err := qrcode.Encode(info.VerificationURL); err == nil {
+			doc.Space(20)
+			doc.Matrix(modules, 70, "left")
+		}
+		doc.Space(6)
+		doc.Text("Kode verifikasi: "+info.VerificationCode, 8, false, "left")
+		doc.Text("Periksa keaslian dokumen di "+info.VerificationURL, 8, false, "left")
+	}
+
+	return doc
+}
+
+// ExamEligibilityPDF renders the exam eligibility list of a course for printing exam cards,
+// with each student's attendance and whether they may sit the exams
+func (s *ReportService) ExamEligibilityPDF(list *models.ExamEligibilityList, courseName string) *pdf.Document {
+	doc := pdf.New()
+
+	doc.Text(s.institution, 14, true, "center")
+	doc.Text("Daftar Peserta Ujian", 12, true, "center")
+	doc.Rule()
+	doc.Space(6)
+
+	course := list.CourseCode
+	if courseName != "" {
+		course += " - " + courseName
+	}
+	details := [][2]string{
+		{"Mata Kuliah", course},
+		{"Kelas", list.ClassName},
+		{"Semester", list.Semester},
+		{"Jumlah Pertemuan", fmt.Sprintf("%d", list.Meetings)},
+		{"Kehadiran Minimal", fmt.Sprintf("%.2f%%", list.MinPercent)},
+		{"Memenuhi Syarat", fmt.Sprintf("%d dari %d", list.Eligible, len(list.Students))},
+	}
+	for _, detail := range details {
+		if detail[1] != "" {
+			doc.Text(fmt.Sprintf("%-18s: %s", detail[0], detail[1]), 10, false, "left")
+		}
+	}
+	doc.Space(10)
+
+	widths := []float64{30, 150, 100, 215.28}
+	doc.Row(widths, []string{"No", "NIM", "Kehadiran %", "Keterangan"}, 9, true)
+	for i, student := range list.Students {
+		status := "Memenuhi syarat"
+		if !student.Eligible {
+			status = "Tidak memenuhi syarat"
+		}
+		doc.Row(widths, []string{
+			fmt.Sprintf("%d", i+1),
+			student.Nim,
+			fmt.Sprintf("%.2f", student.WeightedPercent),
+			status,
+		}, 9, false)
+	}
+
+	doc.Space(30)
+	doc.Text("Dicetak "+time.Now().Format("02-01-2006"), 10, false, "right")
+
+	return doc
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+)
+
+// RoomBookingWorkflowType is the workflow type of room booking requests
+const RoomBookingWorkflowType = "room_booking"
+
+// Actions available on a pending room booking
+const (
+	RoomBookingApproveAction = "approve"
+	RoomBookingRejectAction  = "reject"
+	RoomBookingCancelAction  = "cancel"
+)
+
+// RoomBookingWorkflow defines how a room booking is decided by a facilities admin or
+// withdrawn by its requester. Its states match models.RoomBookingStatus.
+func RoomBookingWorkflow() WorkflowDefinition {
+	pending := string(models.BookingPending)
+	approved := string(models.BookingApproved)
+	rejected := string(models.BookingRejected)
+	cancelled := string(models.BookingCancelled)
+
+	return WorkflowDefinition{
+		Type:          RoomBookingWorkflowType,
+		AdminApproved: true,
+		Initial:       pending,
+		States: map[string]WorkflowState{
+			pending:   {SLA: 3 * 24 * time.Hour},
+			approved:  {Terminal: true},
+			rejected:  {Terminal: true},
+			cancelled: {Terminal: true},
+		},
+		Rules: []WorkflowRule{
+			{From: pending, Action: RoomBookingApproveAction, To: approved},
+			{From: pending, Action: RoomBookingRejectAction, To: rejected},
+			{From: pending, Action: RoomBookingCancelAction, To: cancelled, ByRequester: true},
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"time"
+
+	"delpresence-api/internal/auth"
+	"delpresence-api/pkg/config"
+	"delpresence-api/pkg/jwt"
+)
+
+// DefaultScopedTokenTTL is how long a scoped token stays valid when the caller does not ask
+// for a shorter or longer one
+const DefaultScopedTokenTTL = 15 * time.Minute
+
+// ScopedToken is a minted token together with what it grants
+type ScopedToken struct {
+	Token     string    `json:"token"`
+	Scope     string    `json:"scope"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ScopedTokenService mints short-lived tokens granting a single capability on one resource,
+// for displays, kiosks and URLs that must not carry a user's access token
+type ScopedTokenService struct {
+	cfg config.ScopedTokenConfig
+}
+
+// NewScopedTokenService creates a new ScopedTokenService
+func NewScopedTokenService(cfg config.ScopedTokenConfig) *ScopedTokenService {
+	return &ScopedTokenService{cfg: cfg}
+}
+
+// Mint issues a token granting capability on a resource for ttl. A zero ttl uses
+// DefaultScopedTokenTTL; longer ones are capped at the maximum configured for capability.
+func (s *ScopedTokenService) Mint(capability, resource string, id, issuedBy uint, ttl time.Duration) (*ScopedToken, error) {
+	if ttl <= 0 {
+		ttl = DefaultScopedTokenTTL
+	}
+	ttl = min(ttl, s.cfg.MaxTTL(capability))
+
+	scope := auth.Scope(capability, resource, id)
+	token, expiresAt, err := jwt.GenerateScopedToken(scope, issuedBy, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &ScopedToken{Token: token, Scope: scope, ExpiresAt: expiresAt}, nil
+}
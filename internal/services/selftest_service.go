@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
+	"delpresence-api/pkg/database"
+)
+
+// Self-test check outcomes
+const (
+	SelfTestOK      = "ok"
+	SelfTestFailed  = "failed"
+	SelfTestSkipped = "skipped"
+)
+
+// selfTestTimeout bounds each network check so a dead dependency cannot stall boot
+const selfTestTimeout = 5 * time.Second
+
+// errSelfTestSkipped is returned by checks for optional dependencies that are not configured
+var errSelfTestSkipped = errors.New("skipped")
+
+// SelfTestCheck is the outcome of a single self-test check
+type SelfTestCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// SelfTestReport is the outcome of a full self-test run
+type SelfTestReport struct {
+	Status      string          `json:"status"`
+	StartedAt   time.Time       `json:"started_at"`
+	CompletedAt time.Time       `json:"completed_at"`
+	Checks      []SelfTestCheck `json:"checks"`
+}
+
+// SelfTestService checks the environment the API runs in and keeps the last report
+type SelfTestService struct {
+	mu   sync.RWMutex
+	last *SelfTestReport
+}
+
+// DefaultSelfTest holds the self-test report of this process
+var DefaultSelfTest = &SelfTestService{}
+
+// LastReport returns the most recent report, or nil if no run has completed yet
+func (s *SelfTestService) LastReport() *SelfTestReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Run executes every check, logs the report as JSON and stores it. The SMTP check uses
+// emailService and the other checks the loaded configuration.
+func (s *SelfTestService) Run(emailService *EmailService, cfg *config.Config) *SelfTestReport {
+	report := &SelfTestReport{Status: SelfTestOK, StartedAt: time.Now()}
+
+	checks := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{"database", checkDatabase},
+		{"migrations", checkMigrations},
+		{"campus_auth", func() (string, error) { return checkCampusAuth(cfg.Campus) }},
+		{"smtp", func() (string, error) { return checkSMTP(emailService) }},
+		{"redis", func() (string, error) { return checkRedis(cfg.Cache.RedisAddr) }},
+		{"environment", func() (string, error) { return checkEnvironment(cfg) }},
+	}
+
+	for _, check := range checks {
+		start := time.Now()
+		detail, err := check.run()
+		result := SelfTestCheck{
+			Name:      check.name,
+			Status:    SelfTestOK,
+			LatencyMS: time.Since(start).Milliseconds(),
+			Detail:    detail,
+		}
+		if errors.Is(err, errSelfTestSkipped) {
+			result.Status = SelfTestSkipped
+			result.Detail = "not configured"
+		} else if err != nil {
+			result.Status = SelfTestFailed
+			result.Detail = err.Error()
+			report.Status = SelfTestFailed
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	report.CompletedAt = time.Now()
+
+	if encoded, err := json.Marshal(report); err == nil {
+		log.Printf("[SELFTEST] %s", encoded)
+	}
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report
+}
+
+// checkDatabase pings the database
+func checkDatabase() (string, error) {
+	if database.DB == nil {
+		return "", fmt.Errorf("database is not connected")
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return "", err
+	}
+	return "", sqlDB.Ping()
+}
+
+// checkMigrations verifies the database is at the schema version this build expects
+func checkMigrations() (string, error) {
+	if database.DB == nil {
+		return "", fmt.Errorf("database is not connected")
+	}
+	version, err := database.CurrentSchemaVersion()
+	if err != nil {
+		return "", err
+	}
+	if version != database.ExpectedSchemaVersion {
+		return "", fmt.Errorf("schema version is %d, build expects %d", version, database.ExpectedSchemaVersion)
+	}
+	return fmt.Sprintf("schema version %d", version), nil
+}
+
+// checkCampusAuth verifies the campus auth endpoint is reachable
+func checkCampusAuth(campus config.CampusConfig) (string, error) {
+	return "", utils.PingCampusAuth(campus.AuthURL, selfTestTimeout)
+}
+
+// checkSMTP performs an SMTP handshake when email is configured
+func checkSMTP(emailService *EmailService) (string, error) {
+	if !emailService.IsConfigured() {
+		return "", errSelfTestSkipped
+	}
+	return "", emailService.Ping(selfTestTimeout)
+}
+
+// checkRedis pings Redis when an address is configured
+func checkRedis(addr string) (string, error) {
+	if addr == "" {
+		return "", errSelfTestSkipped
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, selfTestTimeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(selfTestTimeout))
+
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(reply) != "+PONG" {
+		return "", fmt.Errorf("unexpected Redis reply %q", strings.TrimSpace(reply))
+	}
+	return "", nil
+}
+
+// checkEnvironment verifies the settings the API cannot run safely without are present
+func checkEnvironment(cfg *config.Config) (string, error) {
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"JWT_SECRET", cfg.JWT.Secret},
+		{"JWT_SECRET_KEY", cfg.JWT.AdminSecret},
+		{"DB_HOST", cfg.Database.Host},
+		{"DB_USER", cfg.Database.User},
+		{"DB_NAME", cfg.Database.Name},
+	}
+
+	var missing []string
+	for _, setting := range required {
+		if setting.value == "" {
+			missing = append(missing, setting.key)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return "", nil
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// MaxSemesterArchiveSize is the largest semester archive accepted for import, in bytes
+const MaxSemesterArchiveSize = 1 << 30
+
+// Files of a semester archive
+const (
+	archiveManifestFile    = "manifest.json"
+	archiveSchedulesFile   = "schedules.jsonl"
+	archiveEnrollmentsFile = "enrollments.jsonl"
+	archiveSessionsFile    = "sessions.jsonl"
+	archiveRecordsFile     = "records.jsonl"
+	archiveEditsFile       = "edits.jsonl"
+	archiveMaterialsFile   = "materials.jsonl"
+	archiveAttachmentsDir  = "attachments"
+)
+
+// ErrInvalidSemesterArchive is returned for an upload that is not a semester archive this build can read
+var ErrInvalidSemesterArchive = errors.New("not a valid semester archive")
+
+// SemesterArchiveService exports the schedules, enrollments, attendance sessions, records,
+// corrections and session materials of a semester to a ZIP of JSONL files, and imports such
+// an archive into another environment for audits and disaster-recovery drills
+type SemesterArchiveService struct {
+	archiveRepo repository.SemesterArchiveRepository
+	attachments *AttachmentStore
+}
+
+// NewSemesterArchiveService creates a new SemesterArchiveService
+func NewSemesterArchiveService(archiveRepo repository.SemesterArchiveRepository, attachments *AttachmentStore) *SemesterArchiveService {
+	return &SemesterArchiveService{
+		archiveRepo: archiveRepo,
+		attachments: attachments,
+	}
+}
+
+// HasData checks whether a semester has anything to archive
+func (s *SemesterArchiveService) HasData(semester string) (bool, error) {
+	return s.archiveRepo.HasSemesterData(semester)
+}
+
+// writeJSONL writes rows as one JSON document per line to a new file of the archive
+func writeJSONL[T any](archive *zip.Writer, name string, rows []T) (int, error) {
+	file, err := archive.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(file)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
+
+// writeBatchedJSONL writes the rows each calls back with to a new file of the archive
+func writeBatchedJSONL[T any](archive *zip.Writer, name string, each func(func([]T) error) error) (int, error) {
+	file, err := archive.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(file)
+	count := 0
+	err = each(func(batch []T) error {
+		for _, row := range batch {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		count += len(batch)
+		return nil
+	})
+	return count, err
+}
+
+// Export writes the archive of a semester to w. Handouts missing from disk are left out and
+// their materials archived without attachment.
+func (s *SemesterArchiveService) Export(semester string, w io.Writer) (*models.SemesterArchiveManifest, error) {
+	manifest := &models.SemesterArchiveManifest{
+		Format:     models.SemesterArchiveFormat,
+		Version:    models.SemesterArchiveVersion,
+		Semester:   semester,
+		ExportedAt: time.Now(),
+		Counts:     map[string]int{},
+	}
+	archive := zip.NewWriter(w)
+
+	schedules, err := s.archiveRepo.FindSchedules(semester)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Counts[archiveSchedulesFile], err = writeJSONL(archive, archiveSchedulesFile, schedules); err != nil {
+		return nil, err
+	}
+	enrollments, err := s.archiveRepo.FindEnrollments(semester)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Counts[archiveEnrollmentsFile], err = writeJSONL(archive, archiveEnrollmentsFile, enrollments); err != nil {
+		return nil, err
+	}
+	sessions, err := s.archiveRepo.FindSessions(semester)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Counts[archiveSessionsFile], err = writeJSONL(archive, archiveSessionsFile, sessions); err != nil {
+		return nil, err
+	}
+	records := func(fn func([]models.AttendanceRecord) error) error { return s.archiveRepo.EachRecords(semester, fn) }
+	if manifest.Counts[archiveRecordsFile], err = writeBatchedJSONL(archive, archiveRecordsFile, records); err != nil {
+		return nil, err
+	}
+	edits := func(fn func([]models.AttendanceEdit) error) error { return s.archiveRepo.EachEdits(semester, fn) }
+	if manifest.Counts[archiveEditsFile], err = writeBatchedJSONL(archive, archiveEditsFile, edits); err != nil {
+		return nil, err
+	}
+
+	materials, err := s.archiveRepo.FindMaterials(semester)
+	if err != nil {
+		return nil, err
+	}
+	archived := make([]models.ArchivedMaterial, 0, len(materials))
+	for _, material := range materials {
+		item := models.ArchivedMaterial{SessionMaterial: material}
+		if material.HasAttachment() {
+			if _, err := os.Stat(s.attachments.Path(material.AttachmentName)); err == nil {
+				item.AttachmentName = material.AttachmentName
+			} else {
+				log.Printf("[ARCHIVE] Leaving out attachment of material %d: %v", material.ID, err)
+				item.AttachmentType = ""
+			}
+		}
+		archived = append(archived, item)
+	}
+	if manifest.Counts[archiveMaterialsFile], err = writeJSONL(archive, archiveMaterialsFile, archived); err != nil {
+		return nil, err
+	}
+	for _, material := range archived {
+		if material.AttachmentName == "" {
+			continue
+		}
+		if err := s.copyAttachment(archive, material.AttachmentName); err != nil {
+			return nil, err
+		}
+		manifest.Counts[archiveAttachmentsDir]++
+	}
+
+	file, err := archive.Create(archiveManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(file).Encode(manifest); err != nil {
+		return nil, err
+	}
+	return manifest, archive.Close()
+}
+
+// copyAttachment adds a stored handout to the archive
+func (s *SemesterArchiveService) copyAttachment(archive *zip.Writer, name string) error {
+	source, err := os.Open(s.attachments.Path(name))
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := archive.Create(path.Join(archiveAttachmentsDir, name))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(target, source)
+	return err
+}
+
+// readJSONL reads the rows of a file of the archive; a missing file has no rows
+func readJSONL[T any](files map[string]*zip.File, name string) ([]T, error) {
+	rows := []T{}
+	file, ok := files[name]
+	if !ok {
+		return rows, nil
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSemesterArchive, err)
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var row T
+		if err := decoder.Decode(&row); err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSemesterArchive, name, err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// Import reads a semester archive and stores its content with new IDs. Handouts are stored
+// again under new names. The semester must not have any schedules, enrollments or sessions
+// yet; repository.ErrSemesterNotEmpty is returned otherwise.
+func (s *SemesterArchiveService) Import(r io.ReaderAt, size int64) (*models.SemesterArchiveManifest, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSemesterArchive, err)
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	manifests, err := readJSONL[models.SemesterArchiveManifest](files, archiveManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) != 1 || manifests[0].Format != models.SemesterArchiveFormat || manifests[0].Semester == "" {
+		return nil, fmt.Errorf("%w: missing or unknown manifest", ErrInvalidSemesterArchive)
+	}
+	if manifests[0].Version > models.SemesterArchiveVersion {
+		return nil, fmt.Errorf("%w: version %d is newer than this build reads", ErrInvalidSemesterArchive, manifests[0].Version)
+	}
+
+	archive := &models.SemesterArchive{Manifest: manifests[0]}
+	semester := archive.Manifest.Semester
+	if archive.Schedules, err = readJSONL[models.Schedule](files, archiveSchedulesFile); err != nil {
+		return nil, err
+	}
+	if archive.Enrollments, err = readJSONL[models.Enrollment](files, archiveEnrollmentsFile); err != nil {
+		return nil, err
+	}
+	if archive.Sessions, err = readJSONL[models.AttendanceSession](files, archiveSessionsFile); err != nil {
+		return nil, err
+	}
+	if archive.Records, err = readJSONL[models.AttendanceRecord](files, archiveRecordsFile); err != nil {
+		return nil, err
+	}
+	if archive.Edits, err = readJSONL[models.AttendanceEdit](files, archiveEditsFile); err != nil {
+		return nil, err
+	}
+	if archive.Materials, err = readJSONL[models.ArchivedMaterial](files, archiveMaterialsFile); err != nil {
+		return nil, err
+	}
+
+	// Everything must belong to the semester of the manifest, which is checked to be empty
+	for _, schedule := range archive.Schedules {
+		if schedule.Semester != semester {
+			return nil, fmt.Errorf("%w: schedule %d is not in semester %s", ErrInvalidSemesterArchive, schedule.ID, semester)
+		}
+	}
+	for _, enrollment := range archive.Enrollments {
+		if enrollment.Semester != semester {
+			return nil, fmt.Errorf("%w: enrollment %d is not in semester %s", ErrInvalidSemesterArchive, enrollment.ID, semester)
+		}
+	}
+	for _, session := range archive.Sessions {
+		if session.Semester != semester {
+			return nil, fmt.Errorf("%w: session %d is not in semester %s", ErrInvalidSemesterArchive, session.ID, semester)
+		}
+	}
+
+	exists, err := s.archiveRepo.HasSemesterData(semester)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, repository.ErrSemesterNotEmpty
+	}
+
+	saved, err := s.restoreAttachments(files, archive.Materials)
+	if err != nil {
+		s.deleteAttachments(saved)
+		return nil, err
+	}
+	if err := s.archiveRepo.Import(archive); err != nil {
+		s.deleteAttachments(saved)
+		return nil, err
+	}
+
+	imported := archive.Manifest
+	imported.Counts = map[string]int{
+		archiveSchedulesFile:   len(archive.Schedules),
+		archiveEnrollmentsFile: len(archive.Enrollments),
+		archiveSessionsFile:    len(archive.Sessions),
+		archiveRecordsFile:     len(archive.Records),
+		archiveEditsFile:       len(archive.Edits),
+		archiveMaterialsFile:   len(archive.Materials),
+		archiveAttachmentsDir:  len(saved),
+	}
+	return &imported, nil
+}
+
+// restoreAttachments stores the handouts of archived materials and points the materials at
+// their new names. It returns the names stored so far, also on error.
+func (s *SemesterArchiveService) restoreAttachments(files map[string]*zip.File, materials []models.ArchivedMaterial) ([]string, error) {
+	var saved []string
+	for i := range materials {
+		material := &materials[i]
+		if material.AttachmentName == "" {
+			continue
+		}
+		file, ok := files[path.Join(archiveAttachmentsDir, path.Base(material.AttachmentName))]
+		if !ok {
+			return saved, fmt.Errorf("%w: attachment of material %d is missing", ErrInvalidSemesterArchive, material.ID)
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return saved, fmt.Errorf("%w: %v", ErrInvalidSemesterArchive, err)
+		}
+		name, contentType, err := s.attachments.Save(reader)
+		reader.Close()
+		if err != nil {
+			return saved, err
+		}
+		saved = append(saved, name)
+		material.AttachmentName, material.AttachmentType = name, contentType
+	}
+	return saved, nil
+}
+
+// deleteAttachments removes handouts stored for an import that failed
+func (s *SemesterArchiveService) deleteAttachments(names []string) {
+	for _, name := range names {
+		if err := s.attachments.Delete(name); err != nil {
+			log.Printf("[ARCHIVE] Failed to delete attachment %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// maxExpectedSessionRange bounds the date range of an expected session calculation
+const maxExpectedSessionRange = 366 * 24 * time.Hour
+
+// ErrInvalidDateRange is returned for a date range that is reversed or too long
+var ErrInvalidDateRange = errors.New("to must not be before from and the range may span at most one year")
+
+// SessionCalendar works out on which dates a schedule meets. Session generation and
+// attendance percentages both use it so they count the same meetings.
+type SessionCalendar struct {
+	calendarRepo repository.CalendarRepository
+}
+
+// NewSessionCalendar creates a new SessionCalendar
+func NewSessionCalendar(calendarRepo repository.CalendarRepository) *SessionCalendar {
+	return &SessionCalendar{
+		calendarRepo: calendarRepo,
+	}
+}
+
+// ExpectedSessions returns the dates between from and to (inclusive) on which a schedule
+// meets, leaving out holidays and exam weeks
+func (s *SessionCalendar) ExpectedSessions(schedule models.Schedule, from, to time.Time) (*models.ExpectedSessions, error) {
+	if to.Before(from) || to.Sub(from) > maxExpectedSessionRange {
+		return nil, ErrInvalidDateRange
+	}
+
+	calendarEvents, err := s.calendarRepo.FindBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ExpectedSessions{
+		ScheduleID: schedule.ID,
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		Sessions:   []models.ExpectedSession{},
+		Skipped:    []models.SkippedSession{},
+	}
+
+	// Schedules number days 1 (Monday) to 7 (Sunday), time.Weekday 0 (Sunday) to 6
+	offset := (schedule.DayOfWeek%7 - int(from.Weekday()) + 7) % 7
+	for date := from.AddDate(0, 0, offset); !date.After(to); date = date.AddDate(0, 0, 7) {
+		if event := coveringEvent(calendarEvents, date); event != nil {
+			result.Skipped = append(result.Skipped, models.SkippedSession{
+				Date:   date.Format("2006-01-02"),
+				Reason: event.Kind,
+				Name:   event.Name,
+			})
+			continue
+		}
+		result.Sessions = append(result.Sessions, models.ExpectedSession{
+			Date:      date.Format("2006-01-02"),
+			StartTime: schedule.StartTime,
+			EndTime:   schedule.EndTime,
+		})
+	}
+	return result, nil
+}
+
+// Closure returns the holiday or exam week covering a date, or nil when classes meet
+func (s *SessionCalendar) Closure(date time.Time) (*models.CalendarEvent, error) {
+	calendarEvents, err := s.calendarRepo.FindBetween(date, date)
+	if err != nil {
+		return nil, err
+	}
+	return coveringEvent(calendarEvents, date), nil
+}
+
+// coveringEvent returns the calendar event covering a date, if any
+func coveringEvent(calendarEvents []models.CalendarEvent, date time.Time) *models.CalendarEvent {
+	for i := range calendarEvents {
+		if calendarEvents[i].Covers(date) {
+			return &calendarEvents[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+const (
+	// statusCacheTTL is how long the status board is served from memory; clients poll it often
+	statusCacheTTL = 15 * time.Second
+	// statusUpcomingWindow is how far ahead scheduled messages are announced
+	statusUpcomingWindow = 7 * 24 * time.Hour
+)
+
+// StatusBoard lists the status messages clients show as banners
+type StatusBoard struct {
+	Active   []models.StatusMessage `json:"active"`   // Most severe first
+	Upcoming []models.StatusMessage `json:"upcoming"` // Starting within the next week, soonest first
+}
+
+// StatusService serves the status messages admins publish. The board is kept in memory for a
+// short while and the last one loaded is still served when the database cannot be reached, so
+// an outage banner stays up during the outage it announces.
+type StatusService struct {
+	statusRepo repository.StatusMessageRepository
+	mutex      sync.Mutex
+	messages   []models.StatusMessage // Shown between loadedAt and the end of the upcoming window
+	loadedAt   time.Time
+	loaded     bool // Whether messages were ever loaded, so they can be served stale
+}
+
+// NewStatusService creates a new StatusService
+func NewStatusService(statusRepo repository.StatusMessageRepository) *StatusService {
+	return &StatusService{
+		statusRepo: statusRepo,
+	}
+}
+
+// Board returns the messages shown now and the ones starting within the next week
+func (s *StatusService) Board() (*StatusBoard, error) {
+	messages, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	board := &StatusBoard{
+		Active:   []models.StatusMessage{},
+		Upcoming: []models.StatusMessage{},
+	}
+	for _, message := range messages {
+		switch {
+		case message.IsActive(now):
+			board.Active = append(board.Active, message)
+		case message.StartsAt.After(now):
+			board.Upcoming = append(board.Upcoming, message)
+		}
+	}
+	sort.SliceStable(board.Active, func(i, j int) bool {
+		return board.Active[i].Severity.Rank() > board.Active[j].Severity.Rank()
+	})
+	return board, nil
+}
+
+// Invalidate drops the cached board so a change is shown on the next poll
+func (s *StatusService) Invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// load returns the cached messages or reloads them once they are older than statusCacheTTL
+func (s *StatusService) load() ([]models.StatusMessage, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if !s.loadedAt.IsZero() && now.Sub(s.loadedAt) < statusCacheTTL {
+		return s.messages, nil
+	}
+
+	messages, err := s.statusRepo.FindShownBetween(now, now.Add(statusUpcomingWindow))
+	if err != nil {
+		if s.loaded {
+			log.Printf("[STATUS] Serving cached status messages, failed to reload them: %v", err)
+			return s.messages, nil
+		}
+		return nil, err
+	}
+	s.messages, s.loadedAt, s.loaded = messages, now, true
+	return messages, nil
+}
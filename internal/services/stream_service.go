@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+	"delpresence-api/pkg/config"
+
+	"gorm.io/gorm"
+)
+
+// StreamSchemaVersion is bumped whenever the envelope or an event payload changes incompatibly.
+// Version 2 pseudonymized the students in event payloads.
+const StreamSchemaVersion = 2
+
+const (
+	// streamBatchSize is how many outbox events the relay sends per poll
+	streamBatchSize = 100
+	// streamPollInterval is how often the relay looks for undelivered events
+	streamPollInterval = 5 * time.Second
+)
+
+// StreamEnvelope wraps every streamed event. Consumers must deduplicate on ID because
+// delivery is at-least-once.
+type StreamEnvelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// OutboxForwarder writes published domain events to the outbox for the stream relay. Students
+// are pseudonymized before the events leave the API, so the warehouse never holds their NIM,
+// campus user ID or name.
+type OutboxForwarder struct {
+	outboxRepo    repository.OutboxRepository
+	pseudonymizer *pseudonym.Pseudonymizer
+}
+
+// NewOutboxForwarder creates a new OutboxForwarder
+func NewOutboxForwarder(outboxRepo repository.OutboxRepository, pseudonymizer *pseudonym.Pseudonymizer) *OutboxForwarder {
+	return &OutboxForwarder{
+		outboxRepo:    outboxRepo,
+		pseudonymizer: pseudonymizer,
+	}
+}
+
+// Forward implements events.Forwarder for events published after their change committed
+func (f *OutboxForwarder) Forward(event events.Event) error {
+	row, err := f.envelope(event)
+	if err != nil {
+		return err
+	}
+	return f.outboxRepo.Create(row)
+}
+
+// Stage implements events.Outbox, writing the event in the transaction of its change
+func (f *OutboxForwarder) Stage(tx *gorm.DB, event events.Event) error {
+	row, err := f.envelope(event)
+	if err != nil {
+		return err
+	}
+	return f.outboxRepo.CreateInTx(tx, row)
+}
+
+// envelope builds the outbox row streamed for an event
+func (f *OutboxForwarder) envelope(event events.Event) (*models.OutboxEvent, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	data, err = f.pseudonymizer.JSON(data)
+	if err != nil {
+		return nil, err
+	}
+	id, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(StreamEnvelope{
+		ID:         id,
+		Type:       event.EventName(),
+		Version:    StreamSchemaVersion,
+		OccurredAt: time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEvent{
+		EventName: event.EventName(),
+		Payload:   string(payload),
+	}, nil
+}
+
+// StreamPublisher delivers an event to the streaming backend and only returns nil once
+// the backend acknowledged it
+type StreamPublisher interface {
+	Publish(key string, value []byte) error
+}
+
+// KafkaRESTPublisher publishes to a Kafka topic through a Kafka REST Proxy
+type KafkaRESTPublisher struct {
+	baseURL string
+	topic   string
+	client  *http.Client
+}
+
+// NewKafkaRESTPublisher creates a publisher for the given REST Proxy URL and topic
+func NewKafkaRESTPublisher(baseURL, topic string) *KafkaRESTPublisher {
+	return &KafkaRESTPublisher{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		topic:   topic,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Publish implements StreamPublisher
+func (p *KafkaRESTPublisher) Publish(key string, value []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"records": []map[string]interface{}{
+			{"key": key, "value": json.RawMessage(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.baseURL+"/topics/"+p.topic, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("kafka rest proxy returned %d: %s", resp.StatusCode, message)
+	}
+	return nil
+}
+
+// StreamRelay delivers outbox events to the streaming backend in order
+type StreamRelay struct {
+	outboxRepo repository.OutboxRepository
+	publisher  StreamPublisher
+}
+
+// NewStreamRelay creates a relay when a Kafka REST proxy is configured
+func NewStreamRelay(outboxRepo repository.OutboxRepository, stream config.StreamConfig) (*StreamRelay, bool) {
+	if stream.KafkaRESTURL == "" {
+		return nil, false
+	}
+
+	return &StreamRelay{
+		outboxRepo: outboxRepo,
+		publisher:  NewKafkaRESTPublisher(stream.KafkaRESTURL, stream.Topic),
+	}, true
+}
+
+// Run polls the outbox until stop is closed; a nil stop runs for the lifetime of the process
+func (r *StreamRelay) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.deliverPending()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverPending sends undelivered events oldest first and stops at the first failure,
+// so events are retried in order on the next poll
+func (r *StreamRelay) deliverPending() {
+	pending, err := r.outboxRepo.FindPending(streamBatchSize)
+	if err != nil {
+		log.Printf("[STREAM] Failed to load outbox: %v", err)
+		return
+	}
+
+	for _, event := range pending {
+		if err := r.publisher.Publish(event.EventName, []byte(event.Payload)); err != nil {
+			log.Printf("[STREAM] Failed to publish outbox event %d: %v", event.ID, err)
+			if err := r.outboxRepo.MarkFailed(event.ID, err.Error()); err != nil {
+				log.Printf("[STREAM] Failed to record failure of outbox event %d: %v", event.ID, err)
+			}
+			return
+		}
+		if err := r.outboxRepo.MarkPublished(event.ID); err != nil {
+			// The event will be sent again, which at-least-once consumers tolerate
+			log.Printf("[STREAM] Failed to mark outbox event %d as published: %v", event.ID, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"time"
+
+	"delpresence-api/internal/models"
+)
+
+// SupervisionWorkflowType is the workflow type of supervision meeting confirmations
+const SupervisionWorkflowType = "supervision"
+
+// Actions available on a pending supervision meeting
+const (
+	SupervisionConfirmAction = "confirm"
+	SupervisionRejectAction  = "reject"
+)
+
+// SupervisionWorkflow defines how a logged supervision meeting is confirmed. Its states
+// match models.SupervisionStatus.
+func SupervisionWorkflow() WorkflowDefinition {
+	pending := string(models.SupervisionPending)
+	confirmed := string(models.SupervisionConfirmed)
+	rejected := string(models.SupervisionRejected)
+
+	return WorkflowDefinition{
+		Type:    SupervisionWorkflowType,
+		Scope:   models.SupervisionApprovals,
+		Initial: pending,
+		States: map[string]WorkflowState{
+			pending:   {SLA: 7 * 24 * time.Hour},
+			confirmed: {Terminal: true},
+			rejected:  {Terminal: true},
+		},
+		Rules: []WorkflowRule{
+			{From: pending, Action: SupervisionConfirmAction, To: confirmed},
+			{From: pending, Action: SupervisionRejectAction, To: rejected},
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/config"
+)
+
+var (
+	// ErrSyncConflictNotFound is returned for conflicts that do not exist or belong to another profile
+	ErrSyncConflictNotFound = errors.New("sync conflict not found")
+	// ErrSyncConflictClosed is returned when resolving a conflict that was already resolved or superseded
+	ErrSyncConflictClosed = errors.New("sync conflict is no longer open")
+)
+
+// SyncConflictService merges the campus API's values of synced profile fields with the values
+// their owner edited. Each field is compared with its campus value at the previous sync: a field
+// only one side changed takes that side's value, and a field both sides changed is decided by
+// the field's policy and recorded as a conflict the owner can review.
+type SyncConflictService struct {
+	conflictRepo  repository.SyncConflictRepository
+	lecturerRepo  repository.LecturerRepository
+	assistantRepo repository.AssistantRepository
+	policies      map[string]string
+}
+
+// NewSyncConflictService creates a new SyncConflictService with the conflict policy per field
+func NewSyncConflictService(conflictRepo repository.SyncConflictRepository, lecturerRepo repository.LecturerRepository, assistantRepo repository.AssistantRepository, policies map[string]string) *SyncConflictService {
+	return &SyncConflictService{
+		conflictRepo:  conflictRepo,
+		lecturerRepo:  lecturerRepo,
+		assistantRepo: assistantRepo,
+		policies:      policies,
+	}
+}
+
+// Policy returns the conflict policy of a synced field
+func (s *SyncConflictService) Policy(field string) string {
+	if policy, ok := s.policies[field]; ok {
+		return policy
+	}
+	return config.CampusWins
+}
+
+// ProfileMerge is the outcome of merging the synced fields of one profile, stored with Save
+// once the merged profile was saved
+type ProfileMerge struct {
+	states    []models.ProfileFieldState
+	conflicts []models.SyncConflict
+}
+
+// Conflicts returns the fields both sides changed
+func (m *ProfileMerge) Conflicts() []models.SyncConflict {
+	return m.conflicts
+}
+
+// Merge resolves the synced fields of a profile synced from the campus API. local holds the
+// fields of the stored profile and campus those of the campus API; the merged values are written
+// to campus. lastSyncAt is when the stored profile was previously synced.
+func (s *SyncConflictService) Merge(profileType models.UserType, userID uint, lastSyncAt time.Time, local, campus map[string]*string) (*ProfileMerge, error) {
+	stored, err := s.conflictRepo.FindFieldStates(profileType, userID)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]models.ProfileFieldState, len(stored))
+	for _, state := range stored {
+		states[state.Field] = state
+	}
+
+	now := time.Now()
+	merge := &ProfileMerge{}
+	for field, campusValue := range campus {
+		localValue := *local[field]
+		remote := *campusValue
+
+		state, known := states[field]
+		if !known {
+			// Without a recorded campus value the stored profile still has the last synced one
+			state = models.ProfileFieldState{ProfileType: profileType, UserID: userID, Field: field, CampusValue: localValue}
+		}
+		base := state.CampusValue
+
+		merged := remote
+		switch {
+		case remote == base || localValue == remote:
+			// Only the owner changed the field, or both changed it alike
+			merged = localValue
+		case localValue == base:
+			// Only the campus API changed the field
+			state.LocalEditedAt = nil
+		default:
+			policy := s.Policy(field)
+			applied := models.SyncConflictCampus
+			if policy == config.LocalWins ||
+				policy == config.NewestWins && state.LocalEditedAt != nil && state.LocalEditedAt.After(lastSyncAt) {
+				applied = models.SyncConflictLocal
+				merged = localValue
+			} else {
+				state.LocalEditedAt = nil
+			}
+			merge.conflicts = append(merge.conflicts, models.SyncConflict{
+				ProfileType: profileType,
+				UserID:      userID,
+				Field:       field,
+				BaseValue:   base,
+				LocalValue:  localValue,
+				CampusValue: remote,
+				Policy:      policy,
+				Applied:     applied,
+				Status:      models.SyncConflictOpen,
+				DetectedAt:  now,
+			})
+		}
+		*campusValue = merged
+
+		if known && base == remote {
+			// Nothing new to remember about the field
+			continue
+		}
+		state.CampusValue = remote
+		merge.states = append(merge.states, state)
+	}
+	return merge, nil
+}
+
+// Save stores the outcome of a merge after the merged profile was saved
+func (s *SyncConflictService) Save(merge *ProfileMerge) error {
+	if len(merge.states) == 0 && len(merge.conflicts) == 0 {
+		return nil
+	}
+	for _, conflict := range merge.conflicts {
+		log.Printf("[SYNC] Conflict on %s of %s %d, applied the %s value (%s)",
+			conflict.Field, conflict.ProfileType, conflict.UserID, conflict.Applied, conflict.Policy)
+	}
+	return s.conflictRepo.SaveMerge(merge.states, merge.conflicts)
+}
+
+// RecordLocalEdits records that the owner of a profile edited synced fields. before and after
+// hold the synced fields before and after the edit.
+func (s *SyncConflictService) RecordLocalEdits(profileType models.UserType, userID uint, before map[string]string, after map[string]*string) error {
+	previous := make(map[string]string)
+	for field, value := range after {
+		if *value != before[field] {
+			previous[field] = before[field]
+		}
+	}
+	return s.conflictRepo.RecordLocalEdits(profileType, userID, previous, time.Now())
+}
+
+// OpenConflicts lists the conflicts of a profile waiting for its owner's review
+func (s *SyncConflictService) OpenConflicts(profileType models.UserType, userID uint) ([]models.SyncConflict, error) {
+	return s.conflictRepo.FindOpenConflicts(profileType, userID)
+}
+
+// Resolve closes a conflict of the user's profile, keeping the local or the campus value of the
+// field. Keeping the value the policy did not apply updates the profile.
+func (s *SyncConflictService) Resolve(profileType models.UserType, userID, conflictID uint, keep string) (*models.SyncConflict, error) {
+	conflict, err := s.conflictRepo.FindConflictByID(conflictID)
+	if err != nil {
+		return nil, err
+	}
+	if conflict == nil || conflict.ProfileType != profileType || conflict.UserID != userID {
+		return nil, ErrSyncConflictNotFound
+	}
+	if conflict.Status != models.SyncConflictOpen {
+		return nil, ErrSyncConflictClosed
+	}
+
+	if keep != conflict.Applied {
+		if err := s.applyValue(conflict, keep); err != nil {
+			return nil, err
+		}
+	}
+
+	resolvedAt := time.Now()
+	conflict.Status = models.SyncConflictResolved
+	conflict.Kept = keep
+	conflict.ResolvedAt = &resolvedAt
+	if err := s.conflictRepo.UpdateConflict(conflict); err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+// applyValue sets the field of a conflict to the value of one side on the profile it belongs to
+func (s *SyncConflictService) applyValue(conflict *models.SyncConflict, side string) error {
+	var fields map[string]*string
+	var save func() error
+	switch conflict.ProfileType {
+	case models.LecturerType:
+		lecturer, err := s.lecturerRepo.FindByUserID(conflict.UserID)
+		if err != nil {
+			return err
+		}
+		if lecturer == nil {
+			return ErrSyncConflictNotFound
+		}
+		fields = lecturer.SyncedFields()
+		save = func() error { return s.lecturerRepo.Update(lecturer) }
+	case models.AssistantType:
+		assistant, err := s.assistantRepo.FindByUserID(conflict.UserID)
+		if err != nil {
+			return err
+		}
+		if assistant == nil {
+			return ErrSyncConflictNotFound
+		}
+		fields = assistant.SyncedFields()
+		save = func() error { return s.assistantRepo.Update(assistant) }
+	}
+
+	field, ok := fields[conflict.Field]
+	if !ok {
+		return ErrSyncConflictNotFound
+	}
+	before := *field
+	*field = conflict.Value(side)
+	if err := save(); err != nil {
+		return err
+	}
+
+	if side == models.SyncConflictLocal {
+		// The kept value is the owner's edit again, newer than the sync that overrode it
+		return s.conflictRepo.RecordLocalEdits(conflict.ProfileType, conflict.UserID, map[string]string{conflict.Field: before}, time.Now())
+	}
+	return nil
+}
+
+// FieldValues copies the values of synced fields, to compare them after an edit
+func FieldValues(fields map[string]*string) map[string]string {
+	values := make(map[string]string, len(fields))
+	for field, value := range fields {
+		values[field] = *value
+	}
+	return values
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+const (
+	// telemetryPurgeInterval is how often telemetry past its retention is deleted
+	telemetryPurgeInterval = time.Hour
+	// telemetryPurgeBatch bounds each delete so a large backlog does not lock the table
+	telemetryPurgeBatch = 1000
+
+	// checkInHealthWindow is the recent period the health of a session's check-ins is judged on
+	checkInHealthWindow = 5 * time.Minute
+	// degradedMinFailures and degradedFailureRate are how many of the recent attempts the
+	// service must fail before a session is reported as degraded
+	degradedMinFailures = 3
+	degradedFailureRate = 0.2
+	// degradedLatencyMs is the recent median latency from which a session is reported as degraded
+	degradedLatencyMs = 3000
+)
+
+// CheckInHealthReport is the near real-time health of a session's check-ins, telling its
+// lecturer whether failing check-ins are the service's fault rather than the students'
+type CheckInHealthReport struct {
+	SessionID      uint                          `json:"session_id"`
+	Overall        *models.CheckInHealth         `json:"overall"`
+	Recent         *models.CheckInHealth         `json:"recent"` // Attempts of the last five minutes
+	FailureReasons []models.CheckInFailureReason `json:"failure_reasons"`
+	Degraded       bool                          `json:"degraded"`
+	DegradedReason string                        `json:"degraded_reason,omitempty"`
+}
+
+// TelemetryService stores raw check-in telemetry and deletes it after its retention
+type TelemetryService struct {
+	telemetryRepo repository.CheckInTelemetryRepository
+	workers       *Workers
+	retention     time.Duration
+}
+
+// NewTelemetryService creates a new TelemetryService keeping telemetry for retention
+func NewTelemetryService(telemetryRepo repository.CheckInTelemetryRepository, workers *Workers, retention time.Duration) *TelemetryService {
+	return &TelemetryService{
+		telemetryRepo: telemetryRepo,
+		workers:       workers,
+		retention:     retention,
+	}
+}
+
+// Record stores the telemetry of a check-in attempt in the background so the check-in
+// response is not delayed; shutdown waits for it to be written
+func (s *TelemetryService) Record(telemetry models.CheckInTelemetry) {
+	s.workers.Go(func() {
+		if err := s.telemetryRepo.Create(&telemetry); err != nil {
+			log.Printf("[TELEMETRY] Failed to store check-in telemetry for session %d: %v", telemetry.SessionID, err)
+		}
+	})
+}
+
+// SessionHealth reports how the check-ins of a session are faring overall and over the last
+// five minutes, and whether the service is currently failing them
+func (s *TelemetryService) SessionHealth(sessionID uint) (*CheckInHealthReport, error) {
+	overall, err := s.telemetryRepo.SessionHealth(sessionID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	recent, err := s.telemetryRepo.SessionHealth(sessionID, time.Now().Add(-checkInHealthWindow))
+	if err != nil {
+		return nil, err
+	}
+	reasons, err := s.telemetryRepo.FailureReasons(sessionID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CheckInHealthReport{
+		SessionID:      sessionID,
+		Overall:        overall,
+		Recent:         recent,
+		FailureReasons: reasons,
+	}
+	switch {
+	case recent.SystemFailures >= degradedMinFailures && recent.SystemFailureRate >= degradedFailureRate:
+		report.Degraded = true
+		report.DegradedReason = "system_failures"
+	case recent.Attempts >= degradedMinFailures && recent.MedianLatencyMs >= degradedLatencyMs:
+		report.Degraded = true
+		report.DegradedReason = "slow_responses"
+	}
+	return report, nil
+}
+
+// SLAReport summarizes the check-ins of every session with attempts between from and to,
+// starting with the sessions the service failed most
+func (s *TelemetryService) SLAReport(from, to time.Time) ([]models.SessionCheckInSLA, error) {
+	return s.telemetryRepo.SessionSLA(from, to)
+}
+
+// Purge deletes the telemetry past its retention and returns how many rows were deleted
+func (s *TelemetryService) Purge() (int64, error) {
+	cutoff := time.Now().Add(-s.retention)
+	var total int64
+	for {
+		deleted, err := s.telemetryRepo.DeleteBefore(cutoff, telemetryPurgeBatch)
+		total += deleted
+		if err != nil || deleted < telemetryPurgeBatch {
+			return total, err
+		}
+	}
+}
+
+// RunRetention purges expired telemetry every hour until stop is closed; a nil stop runs for
+// the lifetime of the process
+func (s *TelemetryService) RunRetention(stop <-chan struct{}) {
+	ticker := time.NewTicker(telemetryPurgeInterval)
+	defer ticker.Stop()
+	for {
+		if deleted, err := s.Purge(); err != nil {
+			log.Printf("[TELEMETRY] Failed to purge check-in telemetry: %v", err)
+		} else if deleted > 0 {
+			log.Printf("[TELEMETRY] Purged %d check-in telemetry rows older than %s", deleted, s.retention)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+const (
+	// usageFlushInterval is how often buffered usage is written to the rollup table
+	usageFlushInterval = time.Minute
+	// quotaCacheTTL is how long quotas are cached before they are reloaded
+	quotaCacheTTL = 30 * time.Second
+)
+
+// usageRollupKey identifies one row of the hourly rollup
+type usageRollupKey struct {
+	bucket time.Time
+	route  string
+	method string
+	userID uint
+	role   string
+}
+
+// UsageService records per-endpoint, per-user request counts and latencies into an hourly
+// rollup table and enforces the request quotas admins set on expensive endpoints. Requests
+// are buffered in memory and written once a minute.
+type UsageService struct {
+	usageRepo  repository.UsageRepository
+	quotaCache *cache.Cache
+	mutex      sync.Mutex
+	pending    map[usageRollupKey]*models.APIUsageRollup
+}
+
+// quotaCacheKey is the key every quota is cached under together, as they are checked on each
+// authenticated request
+const quotaCacheKey = "all"
+
+// NewUsageService creates a new UsageService caching the quotas in quotaCache
+func NewUsageService(usageRepo repository.UsageRepository, quotaCache *cache.Cache) *UsageService {
+	return &UsageService{
+		usageRepo:  usageRepo,
+		quotaCache: quotaCache,
+		pending:    make(map[usageRollupKey]*models.APIUsageRollup),
+	}
+}
+
+// Record buffers one request to route (the route pattern) made by a user acting as role.
+// Requests rejected by a quota are counted as throttled.
+func (s *UsageService) Record(route, method string, userID uint, role string, status int, duration time.Duration) {
+	key := usageRollupKey{
+		bucket: time.Now().Truncate(time.Hour),
+		route:  route,
+		method: method,
+		userID: userID,
+		role:   role,
+	}
+	durationMs := float64(duration) / float64(time.Millisecond)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	rollup, exists := s.pending[key]
+	if !exists {
+		rollup = &models.APIUsageRollup{Bucket: key.bucket, Route: route, Method: method, UserID: userID, Role: role}
+		s.pending[key] = rollup
+	}
+	rollup.Requests++
+	if status >= http.StatusInternalServerError {
+		rollup.Errors++
+	}
+	if status == http.StatusTooManyRequests {
+		rollup.Throttled++
+	}
+	rollup.TotalDurationMs += durationMs
+	rollup.MaxDurationMs = max(rollup.MaxDurationMs, durationMs)
+}
+
+// Flush writes the buffered usage to the rollup table. Usage that could not be written is kept
+// for the next flush.
+func (s *UsageService) Flush() error {
+	s.mutex.Lock()
+	pending := s.pending
+	s.pending = make(map[usageRollupKey]*models.APIUsageRollup)
+	s.mutex.Unlock()
+
+	rollups := make([]models.APIUsageRollup, 0, len(pending))
+	for _, rollup := range pending {
+		rollups = append(rollups, *rollup)
+	}
+	if err := s.usageRepo.AddRollups(rollups); err != nil {
+		s.mutex.Lock()
+		for key, rollup := range pending {
+			s.merge(key, rollup)
+		}
+		s.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// merge adds unwritten usage back to the buffer; the caller holds the mutex
+func (s *UsageService) merge(key usageRollupKey, rollup *models.APIUsageRollup) {
+	current, exists := s.pending[key]
+	if !exists {
+		s.pending[key] = rollup
+		return
+	}
+	current.Requests += rollup.Requests
+	current.Errors += rollup.Errors
+	current.Throttled += rollup.Throttled
+	current.TotalDurationMs += rollup.TotalDurationMs
+	current.MaxDurationMs = max(current.MaxDurationMs, rollup.MaxDurationMs)
+}
+
+// Run flushes buffered usage every minute until stop is closed, then flushes once more
+func (s *UsageService) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			if err := s.Flush(); err != nil {
+				log.Printf("[USAGE] Failed to write API usage on shutdown: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				log.Printf("[USAGE] Failed to write API usage: %v", err)
+			}
+		}
+	}
+}
+
+// Summary returns the usage between from and to grouped by the given dimensions
+// (see models.UsageDimensions)
+func (s *UsageService) Summary(from, to time.Time, groupBy []string, limit int) ([]models.APIUsageSummary, error) {
+	return s.usageRepo.Summary(from, to, groupBy, limit)
+}
+
+// CheckQuota returns where a user acting as role stands against the quota of route, or nil
+// when the route has no quota for the role
+func (s *UsageService) CheckQuota(route, role string, userID uint) (*models.QuotaStatus, error) {
+	quota, err := s.quotaFor(route, role)
+	if err != nil || quota == nil {
+		return nil, err
+	}
+
+	start, end := quota.Window.Bounds(time.Now())
+	used, err := s.usageRepo.CountRequests(route, userID, start)
+	if err != nil {
+		return nil, err
+	}
+	used += s.pendingRequests(route, userID, start)
+
+	return &models.QuotaStatus{
+		Quota:     quota,
+		Used:      used,
+		Remaining: max(int64(quota.MaxRequests)-used, 0),
+		ResetAt:   end,
+	}, nil
+}
+
+// pendingRequests counts the buffered requests of a user to route since a time, without
+// the throttled ones
+func (s *UsageService) pendingRequests(route string, userID uint, since time.Time) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var count int64
+	for key, rollup := range s.pending {
+		if key.route == route && key.userID == userID && !key.bucket.Before(since) {
+			count += rollup.Requests - rollup.Throttled
+		}
+	}
+	return count
+}
+
+// quotaFor returns the quota of route for role, falling back to the quota for every role
+func (s *UsageService) quotaFor(route, role string) (*models.UsageQuota, error) {
+	var quotas []models.UsageQuota
+	if !s.quotaCache.Get(quotaCacheKey, &quotas) {
+		var err error
+		if quotas, err = s.usageRepo.FindQuotas(); err != nil {
+			return nil, err
+		}
+		s.quotaCache.Set(quotaCacheKey, quotas, quotaCacheTTL)
+	}
+
+	var fallback *models.UsageQuota
+	for i, quota := range quotas {
+		if quota.Route != route {
+			continue
+		}
+		switch quota.Role {
+		case role:
+			return &quotas[i], nil
+		case "":
+			fallback = &quotas[i]
+		}
+	}
+	return fallback, nil
+}
+
+// Quotas lists every quota
+func (s *UsageService) Quotas() ([]models.UsageQuota, error) {
+	return s.usageRepo.FindQuotas()
+}
+
+// FindQuota returns a quota by ID, or nil when it does not exist
+func (s *UsageService) FindQuota(id uint) (*models.UsageQuota, error) {
+	return s.usageRepo.FindQuotaByID(id)
+}
+
+// SaveQuota creates or replaces the quota of a route for a role
+func (s *UsageService) SaveQuota(quota *models.UsageQuota) error {
+	if err := s.usageRepo.SaveQuota(quota); err != nil {
+		return err
+	}
+	s.invalidateQuotas()
+	return nil
+}
+
+// DeleteQuota removes a quota
+func (s *UsageService) DeleteQuota(id uint) error {
+	if err := s.usageRepo.DeleteQuota(id); err != nil {
+		return err
+	}
+	s.invalidateQuotas()
+	return nil
+}
+
+// invalidateQuotas reloads the quotas on the next check
+func (s *UsageService) invalidateQuotas() {
+	s.quotaCache.Delete(quotaCacheKey)
+}
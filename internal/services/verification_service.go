@@ -0,0 +1,114 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/internal/utils"
+)
+
+// newDocumentCode returns a random verification code for a certificate or report
+func newDocumentCode() (string, error) {
+	code, err := utils.GenerateSecureToken(8)
+	return strings.ToUpper(code), err
+}
+
+// verificationURL returns the public address that verifies a document code
+func verificationURL(baseURL, code string) string {
+	return baseURL + "/verify/" + code
+}
+
+// VerificationService records exported reports and lets third parties verify the code printed
+// on a report or participation certificate
+type VerificationService struct {
+	documentRepo repository.IssuedDocumentRepository
+	certificates *CertificateService
+	baseURL      string
+	signingKey   []byte
+	institution  string
+}
+
+// NewVerificationService creates a new VerificationService. Verification links point at
+// baseURL and reports are signed with signingKey.
+func NewVerificationService(documentRepo repository.IssuedDocumentRepository, certificates *CertificateService, baseURL, signingKey, institution string) *VerificationService {
+	return &VerificationService{
+		documentRepo: documentRepo,
+		certificates: certificates,
+		baseURL:      baseURL,
+		signingKey:   []byte(signingKey),
+		institution:  institution,
+	}
+}
+
+// Issue records a report about subject exported by a user and returns it with the code to
+// print on it
+func (s *VerificationService) Issue(kind models.IssuedDocumentKind, subject string, issuedByUserID uint) (*models.IssuedDocument, error) {
+	code, err := newDocumentCode()
+	if err != nil {
+		return nil, err
+	}
+	document := &models.IssuedDocument{
+		Code:           code,
+		Kind:           kind,
+		Subject:        subject,
+		IssuedByUserID: issuedByUserID,
+		IssuedAt:       time.Now(),
+	}
+	document.Signature = s.sign(document)
+	if err := s.documentRepo.Create(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// URL returns the public address that verifies a document code
+func (s *VerificationService) URL(code string) string {
+	return verificationURL(s.baseURL, code)
+}
+
+// Verify looks up a code among participation certificates and exported reports. It returns
+// nil when nothing was issued with the code.
+func (s *VerificationService) Verify(code string) (*models.DocumentVerification, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	certificate, err := s.certificates.Verify(code)
+	if err != nil {
+		return nil, err
+	}
+	if certificate != nil {
+		return &models.DocumentVerification{
+			Valid:    certificate.Valid,
+			Code:     certificate.Code,
+			Kind:     models.CertificateDocument,
+			Issuer:   certificate.Issuer,
+			Subject:  fmt.Sprintf("%s - %s (%s)", certificate.RecipientName, certificate.EventName, certificate.EventDate),
+			IssuedAt: certificate.IssuedAt,
+		}, nil
+	}
+
+	document, err := s.documentRepo.FindByCode(code)
+	if err != nil || document == nil {
+		return nil, err
+	}
+	return &models.DocumentVerification{
+		Valid:    hmac.Equal([]byte(document.Signature), []byte(s.sign(document))),
+		Code:     document.Code,
+		Kind:     document.Kind,
+		Issuer:   s.institution,
+		Subject:  document.Subject,
+		IssuedAt: document.IssuedAt,
+	}, nil
+}
+
+// sign returns the signature of a report's details
+func (s *VerificationService) sign(document *models.IssuedDocument) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%d", document.Code, document.Kind, document.Subject, document.IssuedByUserID, document.IssuedAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/pseudonym"
+	"delpresence-api/internal/repository"
+)
+
+// Files of a warehouse snapshot
+const (
+	WarehouseFactsFile    = "attendance_facts.csv"
+	WarehouseStudentsFile = "students.csv"
+	WarehouseCoursesFile  = "courses.csv"
+)
+
+// WarehouseExportService writes daily snapshots for the institutional data warehouse: the
+// attendance facts of a day and the student and course dimensions as CSV files in
+// <dir>/dt=YYYY-MM-DD. Students are pseudonymized like in the event stream.
+type WarehouseExportService struct {
+	warehouseRepo repository.WarehouseRepository
+	pseudonymizer *pseudonym.Pseudonymizer
+	dir           string
+}
+
+// NewWarehouseExportService creates a new WarehouseExportService writing snapshots to dir
+func NewWarehouseExportService(warehouseRepo repository.WarehouseRepository, pseudonymizer *pseudonym.Pseudonymizer, dir string) *WarehouseExportService {
+	return &WarehouseExportService{
+		warehouseRepo: warehouseRepo,
+		pseudonymizer: pseudonymizer,
+		dir:           dir,
+	}
+}
+
+// ParseWarehouseDate parses a snapshot date (YYYY-MM-DD) in the server's time zone. Days that
+// have not started yet are rejected.
+func ParseWarehouseDate(date string) (time.Time, error) {
+	day, err := time.ParseInLocation(models.WarehouseDateFormat, date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be formatted as YYYY-MM-DD")
+	}
+	if day.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("date %s is in the future", date)
+	}
+	return day, nil
+}
+
+// Yesterday returns the day the nightly snapshot covers
+func Yesterday(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, now.Location())
+}
+
+// Trigger records a new snapshot of day and writes it in the background.
+// The returned snapshot is still running; poll the snapshot list for the result.
+func (s *WarehouseExportService) Trigger(day time.Time, triggeredBy uint) (*models.WarehouseSnapshot, error) {
+	snapshot, err := s.start(day, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.run(context.Background(), *snapshot)
+
+	return snapshot, nil
+}
+
+// Snapshot writes the snapshot of day and returns its recorded result
+func (s *WarehouseExportService) Snapshot(ctx context.Context, day time.Time, triggeredBy uint) (*models.WarehouseSnapshot, error) {
+	snapshot, err := s.start(day, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, *snapshot)
+}
+
+// Backfill writes the snapshots of every day from from to to, inclusive, one after another.
+// done is called with the result of each day; it stops at the first failed day.
+func (s *WarehouseExportService) Backfill(ctx context.Context, from, to time.Time, done func(*models.WarehouseSnapshot)) error {
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		snapshot, err := s.Snapshot(ctx, day, 0)
+		if snapshot != nil {
+			done(snapshot)
+		}
+		if err != nil {
+			return fmt.Errorf("snapshot of %s: %w", day.Format(models.WarehouseDateFormat), err)
+		}
+	}
+	return nil
+}
+
+// start records a running snapshot of day
+func (s *WarehouseExportService) start(day time.Time, triggeredBy uint) (*models.WarehouseSnapshot, error) {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create warehouse directory: %v", err)
+	}
+
+	snapshot := &models.WarehouseSnapshot{
+		SnapshotDate: day.Format(models.WarehouseDateFormat),
+		Status:       models.WarehouseSnapshotRunning,
+		TriggeredBy:  triggeredBy,
+		StartedAt:    time.Now(),
+	}
+	if err := s.warehouseRepo.Create(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// run writes the files of a snapshot and records the result. The files are written to a
+// temporary directory first, so a failed run leaves an earlier snapshot of the date intact.
+func (s *WarehouseExportService) run(ctx context.Context, snapshot models.WarehouseSnapshot) (*models.WarehouseSnapshot, error) {
+	rows, err := s.write(ctx, snapshot.SnapshotDate)
+
+	completedAt := time.Now()
+	snapshot.CompletedAt = &completedAt
+	snapshot.Rows = rows
+	if err != nil {
+		log.Printf("[WAREHOUSE] Snapshot %d of %s failed: %v", snapshot.ID, snapshot.SnapshotDate, err)
+		snapshot.Status = models.WarehouseSnapshotFailed
+		snapshot.Error = err.Error()
+	} else {
+		log.Printf("[WAREHOUSE] Snapshot %d of %s completed (%d facts)", snapshot.ID, snapshot.SnapshotDate, rows[WarehouseFactsFile])
+		snapshot.Status = models.WarehouseSnapshotCompleted
+	}
+
+	if updateErr := s.warehouseRepo.Update(&snapshot); updateErr != nil {
+		log.Printf("[WAREHOUSE] Failed to record result of snapshot %d: %v", snapshot.ID, updateErr)
+		if err == nil {
+			err = updateErr
+		}
+	}
+	return &snapshot, err
+}
+
+// write writes the files of the snapshot of date and returns the rows written per file
+func (s *WarehouseExportService) write(ctx context.Context, date string) (map[string]int, error) {
+	day, err := time.ParseInLocation(models.WarehouseDateFormat, date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(s.dir, ".dt="+date+"-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	rows := map[string]int{}
+	if rows[WarehouseFactsFile], err = writeCSV(filepath.Join(tmp, WarehouseFactsFile), func(w *csv.Writer) (int, error) {
+		return s.writeFacts(ctx, w, date, day, day.AddDate(0, 0, 1))
+	}); err != nil {
+		return rows, err
+	}
+	if rows[WarehouseStudentsFile], err = writeCSV(filepath.Join(tmp, WarehouseStudentsFile), s.writeStudents); err != nil {
+		return rows, err
+	}
+	if rows[WarehouseCoursesFile], err = writeCSV(filepath.Join(tmp, WarehouseCoursesFile), s.writeCourses); err != nil {
+		return rows, err
+	}
+
+	target := filepath.Join(s.dir, "dt="+date)
+	if err := os.RemoveAll(target); err != nil {
+		return rows, err
+	}
+	return rows, os.Rename(tmp, target)
+}
+
+// writeCSV creates a CSV file and fills it with write, which returns the rows it wrote
+func writeCSV(path string, write func(w *csv.Writer) (int, error)) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	count, err := write(writer)
+	if err != nil {
+		return 0, err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, err
+	}
+	return count, file.Close()
+}
+
+// writeFacts writes the attendance of every student in the sessions opened in [from, to)
+func (s *WarehouseExportService) writeFacts(ctx context.Context, w *csv.Writer, date string, from, to time.Time) (int, error) {
+	if err := w.Write([]string{"snapshot_date", "session_id", "opened_at", "course_code", "class_name", "semester", "meeting_number", "optional", "lecturer_user_id", "student", "status", "method", "late_minutes", "credit", "overflow", "checked_in_at"}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.warehouseRepo.EachFact(from, to, func(fact models.WarehouseAttendanceFact) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		method, checkedInAt := "", ""
+		if fact.Method != nil {
+			method = string(*fact.Method)
+		}
+		if fact.CheckedInAt != nil {
+			checkedInAt = fact.CheckedInAt.Format(time.RFC3339)
+		}
+		count++
+		return w.Write([]string{
+			date,
+			strconv.FormatUint(uint64(fact.SessionID), 10),
+			fact.OpenedAt.Format(time.RFC3339),
+			fact.CourseCode,
+			fact.ClassName,
+			fact.Semester,
+			strconv.Itoa(fact.MeetingNumber),
+			strconv.FormatBool(fact.Optional),
+			strconv.FormatUint(uint64(fact.LecturerUserID), 10),
+			s.pseudonymizer.Nim(fact.Nim),
+			string(fact.Status),
+			method,
+			strconv.Itoa(fact.LateMinutes),
+			strconv.FormatFloat(fact.Credit, 'f', 2, 64),
+			strconv.FormatBool(fact.Overflow),
+			checkedInAt,
+		})
+	})
+	return count, err
+}
+
+// writeStudents writes the student dimension from the last synced campus data. Names, emails
+// and dormitories are left out.
+func (s *WarehouseExportService) writeStudents(w *csv.Writer) (int, error) {
+	if err := w.Write([]string{"student", "student_user", "prodi_id", "prodi_name", "fakultas", "angkatan", "status", "last_sync_at"}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.warehouseRepo.EachStudent(func(batch []models.MahasiswaSnapshot) error {
+		for _, snapshot := range batch {
+			complete, err := snapshot.ToMahasiswaComplete()
+			if err != nil {
+				log.Printf("[WAREHOUSE] Skipping unreadable snapshot of student %d: %v", snapshot.UserID, err)
+				continue
+			}
+			info := complete.BasicInfo
+			if err := w.Write([]string{
+				s.pseudonymizer.Nim(snapshot.Nim),
+				s.pseudonymizer.UserID(snapshot.UserID),
+				strconv.Itoa(info.ProdiID),
+				info.ProdiName,
+				info.Fakultas,
+				strconv.Itoa(info.Angkatan),
+				info.Status,
+				snapshot.LastSyncAt.Format(time.RFC3339),
+			}); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// writeCourses writes the course dimension, one row per class schedule
+func (s *WarehouseExportService) writeCourses(w *csv.Writer) (int, error) {
+	schedules, err := s.warehouseRepo.FindCourses()
+	if err != nil {
+		return 0, err
+	}
+	if err := w.Write([]string{"schedule_id", "course_code", "course_name", "class_name", "semester", "lecturer_user_id", "day_of_week", "start_time", "end_time", "room"}); err != nil {
+		return 0, err
+	}
+	for _, schedule := range schedules {
+		if err := w.Write([]string{
+			strconv.FormatUint(uint64(schedule.ID), 10),
+			schedule.CourseCode,
+			schedule.CourseName,
+			schedule.ClassName,
+			schedule.Semester,
+			strconv.FormatUint(uint64(schedule.LecturerUserID), 10),
+			strconv.Itoa(schedule.DayOfWeek),
+			schedule.StartTime,
+			schedule.EndTime,
+			schedule.Room,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	return len(schedules), nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Workers runs the background jobs of the API so they can be stopped and waited for when the
+// server shuts down
+type Workers struct {
+	stop     chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup
+}
+
+// NewWorkers creates a new Workers
+func NewWorkers() *Workers {
+	return &Workers{
+		stop: make(chan struct{}),
+	}
+}
+
+// Run starts a long-running job that returns once its stop channel is closed
+func (w *Workers) Run(name string, job func(stop <-chan struct{})) {
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+		job(w.stop)
+		log.Printf("[WORKERS] %s stopped", name)
+	}()
+}
+
+// Go runs a one-off task in the background; shutdown waits for it to finish
+func (w *Workers) Go(task func()) {
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+		task()
+	}()
+}
+
+// Shutdown stops the long-running jobs and waits for every job and task to finish, or until
+// ctx is done
+func (w *Workers) Shutdown(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		w.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
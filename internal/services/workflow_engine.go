@@ -0,0 +1,386 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/events"
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+	"delpresence-api/pkg/config"
+)
+
+// workflowSLAInterval is how often pending workflows are checked against their SLA
+const workflowSLAInterval = 5 * time.Minute
+
+var (
+	// ErrUnknownWorkflow is returned for a workflow type that was never registered
+	ErrUnknownWorkflow = errors.New("unknown workflow type")
+	// ErrInvalidTransition is returned when an action is not allowed in the current state
+	ErrInvalidTransition = errors.New("action is not allowed in the current state")
+	// ErrNotAssignee is returned when the actor may not act on the workflow
+	ErrNotAssignee = errors.New("user is not assigned to this approval")
+)
+
+// WorkflowState describes one state of a workflow
+type WorkflowState struct {
+	// SLA is how long an item may stay in this state before it is escalated; zero disables it
+	SLA time.Duration
+	// Terminal states complete the workflow
+	Terminal bool
+}
+
+// WorkflowRule allows Action to move a workflow from From to To
+type WorkflowRule struct {
+	From   string
+	Action string
+	To     string
+	// ByRequester rules are taken by the requester instead of the assignee, e.g. cancelling
+	ByRequester bool
+}
+
+// WorkflowDefinition describes an approval type. Assignees are resolved through the
+// ApprovalRouter with Scope, so delegations apply to every workflow.
+type WorkflowDefinition struct {
+	Type    string
+	Scope   models.ApprovalScope
+	Initial string
+	States  map[string]WorkflowState
+	Rules   []WorkflowRule
+	// AdminApproved workflows are decided by any admin holding the route's permission;
+	// they have no assignee and Scope is unused
+	AdminApproved bool
+}
+
+// rule finds the rule for action in state
+func (d *WorkflowDefinition) rule(state, action string) (WorkflowRule, bool) {
+	for _, rule := range d.Rules {
+		if rule.From == state && rule.Action == action {
+			return rule, true
+		}
+	}
+	return WorkflowRule{}, false
+}
+
+// EscalationResolver returns who is told about an approval that breached its SLA,
+// in addition to its assignee and owner
+type EscalationResolver func(instance models.WorkflowInstance) []uint
+
+// applySLAOverride replaces the SLA of every non-terminal state with the configured SLA for
+// the workflow type (e.g. WORKFLOW_SLA_SUPERVISION=3d) when one is set
+func (e *WorkflowEngine) applySLAOverride(definition *WorkflowDefinition) {
+	sla, ok := e.settings.SLAs[strings.ToLower(definition.Type)]
+	if !ok {
+		return
+	}
+
+	states := make(map[string]WorkflowState, len(definition.States))
+	for name, state := range definition.States {
+		if !state.Terminal {
+			state.SLA = sla
+		}
+		states[name] = state
+	}
+	definition.States = states
+}
+
+// reminderLead returns how long before a deadline assignees are reminded; it never exceeds
+// half of the SLA
+func (e *WorkflowEngine) reminderLead(sla time.Duration) time.Duration {
+	lead := e.settings.ReminderBefore
+	if lead > sla/2 {
+		lead = sla / 2
+	}
+	return lead
+}
+
+// WorkflowSubject identifies the item a workflow approves
+type WorkflowSubject struct {
+	Type            string
+	ID              uint
+	RequesterUserID uint
+	OwnerUserID     uint
+}
+
+// WorkflowEngine moves approval items through registered workflow definitions
+type WorkflowEngine struct {
+	workflowRepo   repository.WorkflowRepository
+	approvalRouter *ApprovalRouter
+	escalateTo     EscalationResolver
+	bus            *events.Bus
+	settings       config.WorkflowConfig
+
+	mu          sync.RWMutex
+	definitions map[string]*WorkflowDefinition
+}
+
+// NewWorkflowEngine creates a new WorkflowEngine
+func NewWorkflowEngine(workflowRepo repository.WorkflowRepository, approvalRouter *ApprovalRouter, escalateTo EscalationResolver, bus *events.Bus, settings config.WorkflowConfig) *WorkflowEngine {
+	return &WorkflowEngine{
+		workflowRepo:   workflowRepo,
+		approvalRouter: approvalRouter,
+		escalateTo:     escalateTo,
+		bus:            bus,
+		settings:       settings,
+		definitions:    make(map[string]*WorkflowDefinition),
+	}
+}
+
+// Register adds a workflow definition. It panics on an inconsistent definition, since
+// definitions are fixed at startup.
+func (e *WorkflowEngine) Register(definition WorkflowDefinition) {
+	if _, ok := definition.States[definition.Initial]; !ok {
+		panic(fmt.Sprintf("workflow %s: unknown initial state %q", definition.Type, definition.Initial))
+	}
+	for _, rule := range definition.Rules {
+		if _, ok := definition.States[rule.From]; !ok {
+			panic(fmt.Sprintf("workflow %s: unknown state %q", definition.Type, rule.From))
+		}
+		if _, ok := definition.States[rule.To]; !ok {
+			panic(fmt.Sprintf("workflow %s: unknown state %q", definition.Type, rule.To))
+		}
+	}
+
+	e.applySLAOverride(&definition)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.definitions[definition.Type] = &definition
+}
+
+// assignee resolves who decides a workflow owned by ownerUserID
+func (e *WorkflowEngine) assignee(definition *WorkflowDefinition, ownerUserID uint) uint {
+	if definition.AdminApproved {
+		return 0
+	}
+	return e.approvalRouter.Approver(ownerUserID, definition.Scope)
+}
+
+// definition returns the registered definition of a workflow type
+func (e *WorkflowEngine) definition(workflowType string) (*WorkflowDefinition, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	definition, ok := e.definitions[workflowType]
+	if !ok {
+		return nil, ErrUnknownWorkflow
+	}
+	return definition, nil
+}
+
+// enter moves an instance into state, resolving its assignee and SLA deadline
+func (e *WorkflowEngine) enter(definition *WorkflowDefinition, instance *models.WorkflowInstance, state string, now time.Time) {
+	instance.State = state
+	instance.StateEnteredAt = now
+	instance.AssigneeUserID = e.assignee(definition, instance.OwnerUserID)
+	instance.DueAt = nil
+	instance.RemindAt = nil
+	instance.RemindedAt = nil
+	instance.EscalatedAt = nil
+	instance.CompletedAt = nil
+
+	stateDef := definition.States[state]
+	if stateDef.Terminal {
+		instance.CompletedAt = &now
+	} else if stateDef.SLA > 0 {
+		due := now.Add(stateDef.SLA)
+		remind := due.Add(-e.reminderLead(stateDef.SLA))
+		instance.DueAt = &due
+		instance.RemindAt = &remind
+	}
+}
+
+// Start creates the workflow of a subject in its initial state
+func (e *WorkflowEngine) Start(subject WorkflowSubject) (*models.WorkflowInstance, error) {
+	definition, err := e.definition(subject.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	instance := &models.WorkflowInstance{
+		Type:            subject.Type,
+		SubjectID:       subject.ID,
+		RequesterUserID: subject.RequesterUserID,
+		OwnerUserID:     subject.OwnerUserID,
+	}
+	e.enter(definition, instance, definition.Initial, now)
+
+	transition := &models.WorkflowTransition{
+		ToState:     definition.Initial,
+		Action:      "start",
+		ActorUserID: subject.RequesterUserID,
+		CreatedAt:   now,
+	}
+	if err := e.workflowRepo.Create(instance, transition); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Instance returns the workflow of a subject, starting it for items created before the
+// workflow existed
+func (e *WorkflowEngine) Instance(subject WorkflowSubject) (*models.WorkflowInstance, error) {
+	instance, err := e.workflowRepo.FindBySubject(subject.Type, subject.ID)
+	if err != nil {
+		return nil, err
+	}
+	if instance != nil {
+		return instance, nil
+	}
+	return e.Start(subject)
+}
+
+// canTake checks whether userID may take rule on the workflow
+func (e *WorkflowEngine) canTake(definition *WorkflowDefinition, instance *models.WorkflowInstance, rule WorkflowRule, userID uint) bool {
+	switch {
+	case rule.ByRequester:
+		return userID == instance.RequesterUserID
+	case definition.AdminApproved:
+		return true
+	default:
+		return e.approvalRouter.CanApprove(userID, instance.OwnerUserID, definition.Scope)
+	}
+}
+
+// Act applies an action by actorUserID to the workflow of a subject
+func (e *WorkflowEngine) Act(subject WorkflowSubject, actorUserID uint, action, note string) (*models.WorkflowInstance, error) {
+	return e.act(subject, actorUserID, actorUserID, action, note)
+}
+
+// ActFor applies an action by actorUserID on behalf of onBehalfOfUserID, e.g. an assistant
+// deciding for the lecturer who granted them the permission. The action is authorized as
+// onBehalfOfUserID and recorded as taken by actorUserID.
+func (e *WorkflowEngine) ActFor(subject WorkflowSubject, actorUserID, onBehalfOfUserID uint, action, note string) (*models.WorkflowInstance, error) {
+	return e.act(subject, actorUserID, onBehalfOfUserID, action, note)
+}
+
+// act applies an action to the workflow of a subject, authorizing it as authorizedUserID
+func (e *WorkflowEngine) act(subject WorkflowSubject, actorUserID, authorizedUserID uint, action, note string) (*models.WorkflowInstance, error) {
+	definition, err := e.definition(subject.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := e.Instance(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, ok := definition.rule(instance.State, action)
+	if !ok {
+		return nil, ErrInvalidTransition
+	}
+	if !e.canTake(definition, instance, rule, authorizedUserID) {
+		return nil, ErrNotAssignee
+	}
+
+	from := instance.State
+	now := time.Now()
+	if instance.IsOverdue(now) {
+		instance.Breached = true
+	}
+	e.enter(definition, instance, rule.To, now)
+
+	transition := &models.WorkflowTransition{
+		FromState:   from,
+		ToState:     rule.To,
+		Action:      action,
+		ActorUserID: actorUserID,
+		Note:        note,
+		CreatedAt:   now,
+	}
+	if err := e.workflowRepo.Transition(instance, from, transition); err != nil {
+		if errors.Is(err, repository.ErrWorkflowStateChanged) {
+			return nil, ErrInvalidTransition
+		}
+		return nil, err
+	}
+	return instance, nil
+}
+
+// CheckSLAs reminds assignees of approvals nearing their SLA and escalates every
+// workflow that has passed the SLA of its current state
+func (e *WorkflowEngine) CheckSLAs() {
+	now := time.Now()
+
+	due, err := e.workflowRepo.FindDueForReminder(now)
+	if err != nil {
+		log.Printf("[WORKFLOW] Failed to load workflows due for a reminder: %v", err)
+	}
+	for _, instance := range due {
+		definition, err := e.definition(instance.Type)
+		if err != nil {
+			continue
+		}
+		if err := e.workflowRepo.MarkReminded(instance.ID, now); err != nil {
+			log.Printf("[WORKFLOW] Failed to remind workflow %d: %v", instance.ID, err)
+			continue
+		}
+		instance.RemindedAt = &now
+
+		e.bus.Publish(events.WorkflowReminderDue{
+			Actor:          events.Actor{Type: "system"},
+			Instance:       instance,
+			AssigneeUserID: e.assignee(definition, instance.OwnerUserID),
+		})
+	}
+
+	overdue, err := e.workflowRepo.FindOverdue(now)
+	if err != nil {
+		log.Printf("[WORKFLOW] Failed to load overdue workflows: %v", err)
+		return
+	}
+
+	for _, instance := range overdue {
+		definition, err := e.definition(instance.Type)
+		if err != nil {
+			continue
+		}
+		if err := e.workflowRepo.MarkEscalated(instance.ID, now); err != nil {
+			log.Printf("[WORKFLOW] Failed to escalate workflow %d: %v", instance.ID, err)
+			continue
+		}
+		instance.EscalatedAt = &now
+		instance.Breached = true
+
+		var escalatedTo []uint
+		if e.escalateTo != nil {
+			escalatedTo = e.escalateTo(instance)
+		}
+		e.bus.Publish(events.WorkflowEscalated{
+			Actor:          events.Actor{Type: "system"},
+			Instance:       instance,
+			AssigneeUserID: e.assignee(definition, instance.OwnerUserID),
+			EscalatedTo:    escalatedTo,
+		})
+	}
+}
+
+// SLAReport summarizes SLA compliance of workflows started between from and to
+func (e *WorkflowEngine) SLAReport(workflowType string, from, to time.Time) ([]models.SLAComplianceRow, error) {
+	return e.workflowRepo.SLACompliance(workflowType, from, to)
+}
+
+// PendingApprovals counts the undecided approvals per workflow type assigned to a user
+func (e *WorkflowEngine) PendingApprovals(userID uint) ([]models.PendingApprovalCount, error) {
+	return e.workflowRepo.CountPendingByAssignee(userID)
+}
+
+// RunSLAChecks checks SLAs until stop is closed; a nil stop runs for the lifetime of the process
+func (e *WorkflowEngine) RunSLAChecks(stop <-chan struct{}) {
+	ticker := time.NewTicker(workflowSLAInterval)
+	defer ticker.Stop()
+
+	for {
+		e.CheckSLAs()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
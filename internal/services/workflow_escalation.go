@@ -0,0 +1,37 @@
+package services
+
+import (
+	"log"
+
+	"delpresence-api/internal/models"
+	"delpresence-api/internal/repository"
+)
+
+// ProdiAdminEscalation escalates breached approvals to the active admins of the owner's
+// prodi, falling back to super admins when the prodi is unknown or has no admin
+func ProdiAdminEscalation(prodiResolver *ProdiResolver, adminRepo *repository.AdminRepository) EscalationResolver {
+	return func(instance models.WorkflowInstance) []uint {
+		var admins []models.Admin
+		if prodi := prodiResolver.ResolveUser(instance.OwnerUserID); prodi != "" {
+			found, err := adminRepo.FindActiveByDepartment(prodi)
+			if err != nil {
+				log.Printf("[WORKFLOW] Failed to load admins of %s: %v", prodi, err)
+			}
+			admins = found
+		}
+
+		if len(admins) == 0 {
+			found, err := adminRepo.FindActiveByAccessLevel(models.SuperAdminAccess)
+			if err != nil {
+				log.Printf("[WORKFLOW] Failed to load super admins: %v", err)
+			}
+			admins = found
+		}
+
+		userIDs := make([]uint, 0, len(admins))
+		for _, admin := range admins {
+			userIDs = append(userIDs, admin.UserID)
+		}
+		return userIDs
+	}
+}
@@ -0,0 +1,10 @@
+// Package templates holds the email templates compiled into the binary, so the API renders
+// emails wherever it runs from
+package templates
+
+import "embed"
+
+// Email holds the email templates under email/
+//
+//go:embed email/*.html
+var Email embed.FS
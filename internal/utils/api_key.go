@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// apiKeyPrefix marks keys issued by DelPresence so they are easy to spot in logs and configs
+const apiKeyPrefix = "dpk_"
+
+// GenerateAPIKey creates a new random API key
+func GenerateAPIKey() (string, error) {
+	token, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + token, nil
+}
+
+// HashAPIKey returns the SHA-256 hash of an API key as stored in the database
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
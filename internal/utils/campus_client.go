@@ -2,26 +2,28 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"delpresence-api/internal/cache"
+	"delpresence-api/internal/chaos"
+	"delpresence-api/internal/logging"
 	"delpresence-api/internal/models"
+	"delpresence-api/pkg/config"
 )
 
-const (
-	campusAPIBaseURL = "https://cis.del.ac.id/api"
-	campusAuthURL    = "https://cis-dev.del.ac.id/api/jwt-api/do-auth"
-	defaultUsername  = "johannes"
-	defaultPassword  = "Del@2022"
-)
+// campusLog logs campus API traffic; set LOG_MODULES=campusclient=debug to trace token handling
+var campusLog = logging.Module("campusclient")
 
 // TokenCache stores the authentication tokens
 type TokenCache struct {
@@ -30,114 +32,128 @@ type TokenCache struct {
 	ExpiresAt     time.Time
 	IsInitialized bool
 	mutex         sync.RWMutex
+	renewMutex    sync.Mutex // Held while a new token is fetched so only one login runs at a time
+}
+
+// refreshToken returns the cached refresh token
+func (tc *TokenCache) refreshToken() string {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+	return tc.RefreshToken
+}
+
+// usable returns the cached token when it is not about to expire
+func (tc *TokenCache) usable() (string, bool) {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+	if !tc.IsInitialized || tc.AuthToken == "" || time.Now().Add(30*time.Second).After(tc.ExpiresAt) {
+		return tc.AuthToken, false
+	}
+	return tc.AuthToken, true
+}
+
+// store replaces the cached tokens
+func (tc *TokenCache) store(token, refreshToken string, expiresAt time.Time) {
+	tc.mutex.Lock()
+	tc.AuthToken = token
+	tc.RefreshToken = refreshToken
+	tc.ExpiresAt = expiresAt
+	tc.IsInitialized = true
+	tc.mutex.Unlock()
 }
 
 // CampusClient is a client for interacting with the campus API
 type CampusClient struct {
+	baseURL    string
 	httpClient *http.Client
 	tokenCache *TokenCache
+	breaker    *CircuitBreaker
+	nims       *cache.Cache // NIMs by campus user ID, see cachedNim
+}
+
+// campusNimTTL is how long a resolved NIM is remembered. NIMs never change, so this only bounds
+// how many are kept for students who stopped using the app.
+const campusNimTTL = 7 * 24 * time.Hour
+
+// cachedNim returns the NIM of a student already resolved by user ID, so the detail lookup can
+// be issued without waiting for the basic info lookup
+func (c *CampusClient) cachedNim(userID int) (string, bool) {
+	var nim string
+	found := c.nims.Get(strconv.Itoa(userID), &nim)
+	return nim, found && nim != ""
+}
+
+// rememberNim stores the NIM of a campus user ID
+func (c *CampusClient) rememberNim(userID int, nim string) {
+	if nim == "" {
+		return
+	}
+	c.nims.Set(strconv.Itoa(userID), nim, campusNimTTL)
 }
 
 // AuthRoundTripper is a custom RoundTripper that adds authentication headers to requests
 type AuthRoundTripper struct {
 	BaseTransport http.RoundTripper
 	TokenCache    *TokenCache
+	Config        config.CampusConfig // Auth endpoint and service account credentials
 }
 
 // RoundTrip implements the http.RoundTripper interface
 func (rt *AuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	log.Printf("[TOKEN_DEBUG] Processing request to: %s", req.URL.String())
+	logger := campusLog.Ctx(req.Context())
+	logger.Debugf("Processing request to: %s", req.URL.String())
 
 	// Skip token check for authentication requests
-	if req.URL.String() == campusAuthURL {
-		log.Printf("[TOKEN_DEBUG] Direct auth request to: %s", campusAuthURL)
+	if req.URL.String() == rt.Config.AuthURL {
+		logger.Debugf("Direct auth request to: %s", rt.Config.AuthURL)
 		return rt.BaseTransport.RoundTrip(req)
 	}
 
-	// Check if we need to refresh token
-	rt.TokenCache.mutex.RLock()
-	token := rt.TokenCache.AuthToken
-	refreshToken := rt.TokenCache.RefreshToken
-	expiresAt := rt.TokenCache.ExpiresAt
-	isInitialized := rt.TokenCache.IsInitialized
-	rt.TokenCache.mutex.RUnlock()
-
 	// Get a new token if needed (none exists or is about to expire)
-	tokenIsExpiredOrMissing := !isInitialized || token == "" || time.Now().Add(30*time.Second).After(expiresAt)
-
-	if tokenIsExpiredOrMissing {
-		log.Printf("[TOKEN_DEBUG] Token is missing or about to expire. Current token: %s... Expiry: %v",
-			safeSubstring(token, 0, 10), expiresAt)
+	token, ok := rt.TokenCache.usable()
+	if !ok {
+		logger.Debugf("Token is missing or about to expire. Current token: %s...", safeSubstring(token, 0, 10))
 
-		// Try to use refresh token if available
-		if refreshToken != "" {
-			// TODO: Implement refresh token flow if campus API supports it
-			log.Println("[TOKEN_DEBUG] Refresh token available but refresh flow not implemented, falling back to new auth")
-		}
-
-		// Get a new token with full authentication
-		newToken, newRefreshToken, expiryTime, err := getNewToken()
+		var err error
+		token, err = rt.renewToken(token)
 		if err != nil {
-			log.Printf("[TOKEN_DEBUG] Failed to get authentication token: %v", err)
+			logger.Warnf("Failed to get authentication token: %v", err)
 			return nil, fmt.Errorf("failed to get authentication token: %w", err)
 		}
-
-		// Update token cache
-		rt.TokenCache.mutex.Lock()
-		rt.TokenCache.AuthToken = newToken
-		rt.TokenCache.RefreshToken = newRefreshToken
-		rt.TokenCache.ExpiresAt = expiryTime
-		rt.TokenCache.IsInitialized = true
-		rt.TokenCache.mutex.Unlock()
-
-		token = newToken
-		log.Printf("[TOKEN_DEBUG] Successfully obtained new token, expires at: %v", expiryTime)
-	} else {
-		log.Printf("[TOKEN_DEBUG] Using existing token, expires at: %v", expiresAt)
 	}
 
 	// Clone the request to avoid modifying the original
-	reqClone := req.Clone(req.Context())
-	reqClone.Header.Set("Authorization", "Bearer "+token)
-	log.Printf("[TOKEN_DEBUG] Request to %s with token (first 15 chars): %s...",
+	reqClone := authorizedClone(req, token)
+	logger.Debugf("Request to %s with token (first 15 chars): %s...",
 		reqClone.URL.String(),
 		safeSubstring(token, 0, 15))
 
 	// Send the request with the token
 	resp, err := rt.BaseTransport.RoundTrip(reqClone)
 	if err != nil {
-		log.Printf("[TOKEN_DEBUG] Campus API request failed: %v", err)
+		logger.Warnf("Campus API request failed: %v", err)
 		return nil, err
 	}
 
-	log.Printf("[TOKEN_DEBUG] Response from %s: %d", reqClone.URL.String(), resp.StatusCode)
+	logger.Debugf("Response from %s: %d", reqClone.URL.String(), resp.StatusCode)
 
 	// If we get a 401 Unauthorized, our token might be expired
 	if resp.StatusCode == http.StatusUnauthorized {
-		log.Println("[TOKEN_DEBUG] Got 401 Unauthorized, token might be expired")
+		logger.Infof("Got 401 Unauthorized, token might be expired")
 
 		// Close the current response body
 		resp.Body.Close()
 
-		// Force get a new token
-		newToken, newRefreshToken, expiryTime, err := getNewToken()
+		// Force get a new token unless another request already replaced the rejected one
+		newToken, err := rt.renewToken(token)
 		if err != nil {
-			log.Printf("[TOKEN_DEBUG] Failed to refresh authentication token: %v", err)
+			logger.Warnf("Failed to refresh authentication token: %v", err)
 			return nil, fmt.Errorf("failed to refresh authentication token: %w", err)
 		}
 
-		// Update token cache
-		rt.TokenCache.mutex.Lock()
-		rt.TokenCache.AuthToken = newToken
-		rt.TokenCache.RefreshToken = newRefreshToken
-		rt.TokenCache.ExpiresAt = expiryTime
-		rt.TokenCache.IsInitialized = true
-		rt.TokenCache.mutex.Unlock()
-
 		// Create a new request with the new token
-		reqClone = req.Clone(req.Context())
-		reqClone.Header.Set("Authorization", "Bearer "+newToken)
-		log.Printf("[TOKEN_DEBUG] Retrying request with new token (first 15 chars): %s...", safeSubstring(newToken, 0, 15))
+		reqClone = authorizedClone(req, newToken)
+		logger.Debugf("Retrying request with new token (first 15 chars): %s...", safeSubstring(newToken, 0, 15))
 
 		// Retry the request with the new token
 		return rt.BaseTransport.RoundTrip(reqClone)
@@ -146,21 +162,83 @@ func (rt *AuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	return resp, nil
 }
 
-// getNewToken authenticates and gets a new token from the campus API
+// authorizedClone returns a copy of req carrying the token and the ID of the API request it is
+// made for, so campus-side logs can be matched with ours
+func authorizedClone(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	if requestID := logging.RequestID(req.Context()); requestID != "" {
+		clone.Header.Set(logging.RequestIDHeader, requestID)
+	}
+	return clone
+}
+
+// renewToken gets a new token with the cached refresh token, logging in again with the
+// service account when there is none or the refresh fails, and caches it. Requests that find
+// the same stale token at the same time wait for one renewal and reuse its token instead of
+// each logging in.
+func (rt *AuthRoundTripper) renewToken(stale string) (string, error) {
+	rt.TokenCache.renewMutex.Lock()
+	defer rt.TokenCache.renewMutex.Unlock()
+
+	if token, ok := rt.TokenCache.usable(); ok && token != stale {
+		campusLog.Debugf("Token was renewed by a concurrent request, reusing it")
+		return token, nil
+	}
+
+	if refreshToken := rt.TokenCache.refreshToken(); refreshToken != "" && rt.Config.RefreshURL != "" {
+		token, newRefreshToken, expiresAt, err := refreshAuthToken(rt.Config.RefreshURL, refreshToken)
+		if err == nil {
+			// The campus API may keep the refresh token and only return a new access token
+			if newRefreshToken == "" {
+				newRefreshToken = refreshToken
+			}
+			rt.TokenCache.store(token, newRefreshToken, expiresAt)
+			campusLog.Debugf("Refreshed token, expires at: %v", expiresAt)
+			return token, nil
+		}
+		campusLog.Infof("Token refresh failed, falling back to a new login: %v", err)
+	}
+
+	token, refreshToken, expiresAt, err := getNewToken(rt.Config)
+	if err != nil {
+		return "", err
+	}
+	rt.TokenCache.store(token, refreshToken, expiresAt)
+	campusLog.Debugf("Successfully obtained new token, expires at: %v", expiresAt)
+	return token, nil
+}
+
+// getNewToken authenticates with the service account and gets a new token from the campus API
 // Returns token, refresh token, expiry time, and error
-func getNewToken() (string, string, time.Time, error) {
-	log.Printf("Authenticating with campus API using account: %s", defaultUsername)
+func getNewToken(cfg config.CampusConfig) (string, string, time.Time, error) {
+	campusLog.Infof("Authenticating with campus API using account: %s", cfg.Username)
+	return requestToken(cfg.AuthURL, [][2]string{
+		{"username", cfg.Username},
+		{"password", cfg.Password},
+	})
+}
+
+// refreshAuthToken exchanges a refresh token for a new token without the account password
+// Returns token, refresh token (empty when the campus API keeps the old one), expiry time, and error
+func refreshAuthToken(refreshURL, refreshToken string) (string, string, time.Time, error) {
+	campusLog.Debugf("Refreshing campus API token")
+	return requestToken(refreshURL, [][2]string{
+		{"refresh_token", refreshToken},
+	})
+}
 
+// requestToken posts form fields to a campus auth endpoint and parses the token it returns
+func requestToken(endpoint string, fields [][2]string) (string, string, time.Time, error) {
 	// Create a multipart form data request (matching Flutter's http.MultipartRequest)
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
 	// Add form fields
-	if err := writer.WriteField("username", defaultUsername); err != nil {
-		return "", "", time.Time{}, fmt.Errorf("failed to add username field: %w", err)
-	}
-	if err := writer.WriteField("password", defaultPassword); err != nil {
-		return "", "", time.Time{}, fmt.Errorf("failed to add password field: %w", err)
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return "", "", time.Time{}, fmt.Errorf("failed to add %s field: %w", field[0], err)
+		}
 	}
 
 	// Close writer to finalize the form
@@ -169,7 +247,7 @@ func getNewToken() (string, string, time.Time, error) {
 	}
 
 	// Create request
-	req, err := http.NewRequest("POST", campusAuthURL, body)
+	req, err := http.NewRequest("POST", endpoint, body)
 	if err != nil {
 		return "", "", time.Time{}, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -184,7 +262,7 @@ func getNewToken() (string, string, time.Time, error) {
 	}
 
 	// Log request info
-	log.Printf("Sending auth request to %s", campusAuthURL)
+	campusLog.Debugf("Sending auth request to %s", endpoint)
 
 	// Send request
 	resp, err := client.Do(req)
@@ -200,8 +278,7 @@ func getNewToken() (string, string, time.Time, error) {
 	}
 
 	// Log response info
-	log.Printf("Auth response status: %d", resp.StatusCode)
-	log.Printf("Auth response body: %s", string(respBody))
+	campusLog.Debugf("Auth response status: %d", resp.StatusCode)
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
@@ -226,7 +303,7 @@ func getNewToken() (string, string, time.Time, error) {
 
 	// Extract expiry time from token
 	expiryTime := extractExpiryFromToken(authResp.Token)
-	log.Printf("Got new token with expiry: %v", expiryTime)
+	campusLog.Infof("Got new token with expiry: %v", expiryTime)
 
 	return authResp.Token, authResp.RefreshToken, expiryTime, nil
 }
@@ -289,76 +366,66 @@ func safeSubstring(s string, start, end int) string {
 	return s[start:end]
 }
 
-// NewCampusClient creates a new client for the campus API
-func NewCampusClient() *CampusClient {
-	tokenCache := &TokenCache{
-		mutex: sync.RWMutex{},
-	}
+// NewCampusClient creates a new client for the campus API authenticating with the configured
+// service account. The client is safe for concurrent use; create it once at startup and share
+// it so every caller reuses the same token and breaker. Calls fail fast with ErrCampusUnavailable
+// while breaker is open; resolved NIMs are kept in nims.
+func NewCampusClient(cfg config.CampusConfig, breaker *CircuitBreaker, nims *cache.Cache) *CampusClient {
+	tokenCache := &TokenCache{}
 
 	transport := &AuthRoundTripper{
-		BaseTransport: http.DefaultTransport,
+		BaseTransport: &chaos.RoundTripper{Base: http.DefaultTransport, Target: chaos.Campus},
 		TokenCache:    tokenCache,
+		Config:        cfg,
 	}
 
 	httpClient := &http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second,
-	}
-
-	// Check if we should pre-fetch a token
-	tokenCache.mutex.RLock()
-	initialized := tokenCache.IsInitialized
-	tokenCache.mutex.RUnlock()
-
-	// Pre-fetch a token asynchronously only if not already initialized
-	if !initialized {
-		go func() {
-			// Double-check in case another goroutine has fetched it
-			tokenCache.mutex.RLock()
-			initialized := tokenCache.IsInitialized
-			tokenCache.mutex.RUnlock()
-
-			if initialized {
-				log.Printf("Token already pre-fetched, skipping")
-				return
-			}
-
-			token, refreshToken, expiresAt, err := getNewToken()
-			if err != nil {
-				log.Printf("Initial token fetch failed: %v", err)
-				return
-			}
-
-			tokenCache.mutex.Lock()
-			// Check again after acquiring the lock
-			if !tokenCache.IsInitialized {
-				tokenCache.AuthToken = token
-				tokenCache.RefreshToken = refreshToken
-				tokenCache.ExpiresAt = expiresAt
-				tokenCache.IsInitialized = true
-				log.Printf("Initial token pre-fetched successfully")
-			} else {
-				log.Printf("Token pre-fetched by another goroutine, discarding")
-			}
-			tokenCache.mutex.Unlock()
-		}()
+		Transport: &BreakerRoundTripper{
+			BaseTransport: transport,
+			Breaker:       breaker,
+		},
+		Timeout: 30 * time.Second,
 	}
 
+	// Pre-fetch a token asynchronously; requests made meanwhile wait for the same login
+	go func() {
+		if _, err := transport.renewToken(""); err != nil {
+			campusLog.Warnf("Initial token fetch failed: %v", err)
+			return
+		}
+		campusLog.Infof("Initial token pre-fetched successfully")
+	}()
+
 	return &CampusClient{
+		baseURL:    cfg.BaseURL,
 		httpClient: httpClient,
 		tokenCache: tokenCache,
+		breaker:    breaker,
+		nims:       nims,
 	}
 }
 
+// IsDegraded reports whether the service is running in degradation mode because the campus API
+// circuit breaker is open
+func (c *CampusClient) IsDegraded() bool {
+	return c.breaker.State() == CircuitOpen
+}
+
+// IsUnavailable reports whether err was caused by the campus API being unreachable
+func (c *CampusClient) IsUnavailable(err error) bool {
+	return errors.Is(err, ErrCampusUnavailable) || c.IsDegraded()
+}
+
 // GetMahasiswaByUserID fetches student information by user ID
-func (c *CampusClient) GetMahasiswaByUserID(userID int) (*models.MahasiswaInfo, error) {
-	url := fmt.Sprintf("%s/library-api/mahasiswa?userid=%d", campusAPIBaseURL, userID)
-	log.Printf("Fetching student info for user ID: %d from URL: %s", userID, url)
+func (c *CampusClient) GetMahasiswaByUserID(ctx context.Context, userID int) (*models.MahasiswaInfo, error) {
+	logger := campusLog.Ctx(ctx)
+	url := fmt.Sprintf("%s/library-api/mahasiswa?userid=%d", c.baseURL, userID)
+	logger.Debugf("Fetching student info for user ID: %d from URL: %s", userID, url)
 
 	// Send the request
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(ctx, url)
 	if err != nil {
-		log.Printf("Error fetching student info: %v", err)
+		logger.Warnf("Error fetching student info: %v", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -371,42 +438,108 @@ func (c *CampusClient) GetMahasiswaByUserID(userID int) (*models.MahasiswaInfo,
 
 	// Log a summary of the response
 	respSummary := safeSubstring(string(body), 0, 100)
-	log.Printf("Student info response (first 100 chars): %s...", respSummary)
+	logger.Debugf("Student info response (first 100 chars): %s...", respSummary)
 
 	// Parse response
 	var mahasiswaResp models.MahasiswaListResponse
 	if err := json.Unmarshal(body, &mahasiswaResp); err != nil {
-		log.Printf("Error parsing student info response: %v", err)
+		logger.Warnf("Error parsing student info response: %v", err)
 		return nil, err
 	}
 
 	// Check if response is valid
 	if mahasiswaResp.Result != "Ok" {
-		log.Printf("Campus API returned non-Ok result for user ID %d: %s", userID, mahasiswaResp.Result)
+		logger.Warnf("Campus API returned non-Ok result for user ID %d: %s", userID, mahasiswaResp.Result)
 		return nil, fmt.Errorf("API returned non-Ok result: %s", mahasiswaResp.Result)
 	}
 
 	// Check if any mahasiswa data was returned
 	if len(mahasiswaResp.Data.Mahasiswa) == 0 {
-		log.Printf("No student found with user ID: %d", userID)
+		logger.Infof("No student found with user ID: %d", userID)
 		return nil, fmt.Errorf("no student found with user ID: %d", userID)
 	}
 
-	log.Printf("Found student: %s (NIM: %s)",
+	logger.Debugf("Found student: %s (NIM: %s)",
 		mahasiswaResp.Data.Mahasiswa[0].Nama,
 		mahasiswaResp.Data.Mahasiswa[0].Nim)
+
+	// Remember the NIM so later complete fetches can run in parallel
+	c.rememberNim(userID, mahasiswaResp.Data.Mahasiswa[0].Nim)
+
 	return &mahasiswaResp.Data.Mahasiswa[0], nil
 }
 
+// GetLecturers fetches every lecturer listed by the campus API
+func (c *CampusClient) GetLecturers(ctx context.Context) ([]models.CampusLecturerDetail, error) {
+	logger := campusLog.Ctx(ctx)
+	url := c.URL("/library-api/dosen")
+	logger.Debugf("Fetching all lecturers from URL: %s", url)
+
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		logger.Warnf("Error fetching lecturers: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("campus API returned status: %d", resp.StatusCode)
+	}
+
+	var lecturerResp models.CampusLecturerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lecturerResp); err != nil {
+		logger.Warnf("Error parsing lecturers response: %v", err)
+		return nil, err
+	}
+	if lecturerResp.Result != "Ok" {
+		return nil, fmt.Errorf("API returned non-Ok result: %s", lecturerResp.Result)
+	}
+
+	logger.Infof("Fetched %d lecturers from campus API", len(lecturerResp.Data.Dosen))
+	return lecturerResp.Data.Dosen, nil
+}
+
+// GetLecturerByUserID fetches the campus record of one lecturer by campus user ID
+func (c *CampusClient) GetLecturerByUserID(ctx context.Context, userID uint) (*models.CampusLecturerDetail, error) {
+	var lecturerResp models.CampusLecturerResponse
+	if err := c.getJSON(ctx, c.URL(fmt.Sprintf("/library-api/dosen?userid=%d", userID)), &lecturerResp); err != nil {
+		return nil, err
+	}
+	if lecturerResp.Result != "Ok" {
+		return nil, fmt.Errorf("API returned non-Ok result: %s", lecturerResp.Result)
+	}
+	if len(lecturerResp.Data.Dosen) == 0 {
+		return nil, fmt.Errorf("no lecturer found with user ID: %d", userID)
+	}
+	return &lecturerResp.Data.Dosen[0], nil
+}
+
+// GetEmployeeByUserID fetches the campus employee record of a staff member, such as a
+// teaching assistant, by campus user ID
+func (c *CampusClient) GetEmployeeByUserID(ctx context.Context, userID uint) (*models.CampusAssistantDetail, error) {
+	var employeeResp models.CampusAssistantResponse
+	if err := c.getJSON(ctx, c.URL(fmt.Sprintf("/library-api/pegawai?userid=%d", userID)), &employeeResp); err != nil {
+		return nil, err
+	}
+	if employeeResp.Result != "Ok" {
+		return nil, fmt.Errorf("API returned non-Ok result: %s", employeeResp.Result)
+	}
+	if len(employeeResp.Data.Pegawai) == 0 {
+		return nil, fmt.Errorf("no employee found with user ID: %d", userID)
+	}
+	return &employeeResp.Data.Pegawai[0], nil
+}
+
 // GetMahasiswaDetailByNIM fetches detailed student information by NIM
-func (c *CampusClient) GetMahasiswaDetailByNIM(nim string) (*models.MahasiswaDetail, error) {
-	url := fmt.Sprintf("%s/library-api/get-student-by-nim?nim=%s", campusAPIBaseURL, nim)
-	log.Printf("Fetching student details for NIM: %s from URL: %s", nim, url)
+func (c *CampusClient) GetMahasiswaDetailByNIM(ctx context.Context, nim string) (*models.MahasiswaDetail, error) {
+	logger := campusLog.Ctx(ctx)
+	url := fmt.Sprintf("%s/library-api/get-student-by-nim?nim=%s", c.baseURL, nim)
+	logger.Debugf("Fetching student details for NIM: %s from URL: %s", nim, url)
 
 	// Send the request
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(ctx, url)
 	if err != nil {
-		log.Printf("Error fetching student details: %v", err)
+		logger.Warnf("Error fetching student details: %v", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -414,34 +547,159 @@ func (c *CampusClient) GetMahasiswaDetailByNIM(nim string) (*models.MahasiswaDet
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Error reading student details response: %v", err)
+		logger.Warnf("Error reading student details response: %v", err)
 		return nil, err
 	}
 
 	// Log a summary of the response
 	respSummary := safeSubstring(string(body), 0, 100)
-	log.Printf("Student details response for NIM %s (first 100 chars): %s...", nim, respSummary)
+	logger.Debugf("Student details response for NIM %s (first 100 chars): %s...", nim, respSummary)
 
 	// Parse response
 	var detailResp models.MahasiswaDetailResponse
 	if err := json.Unmarshal(body, &detailResp); err != nil {
-		log.Printf("Error parsing student details response: %v", err)
+		logger.Warnf("Error parsing student details response: %v", err)
 		return nil, err
 	}
 
 	// Check if response is valid
 	if detailResp.Result != "OK" {
-		log.Printf("Campus API returned non-OK result for NIM %s: %s", nim, detailResp.Result)
+		logger.Warnf("Campus API returned non-OK result for NIM %s: %s", nim, detailResp.Result)
 		return nil, fmt.Errorf("failed to get student details for NIM: %s", nim)
 	}
 
-	log.Printf("Successfully retrieved details for student with NIM: %s, Name: %s",
+	logger.Debugf("Successfully retrieved details for student with NIM: %s, Name: %s",
 		nim, detailResp.Data.Nama)
 	return &detailResp.Data, nil
 }
 
+// GetMahasiswaComplete fetches both the basic info and the details of a student.
+// When the student's NIM is already known both campus requests run concurrently,
+// otherwise the basic info is fetched first to resolve the NIM.
+func (c *CampusClient) GetMahasiswaComplete(ctx context.Context, userID int) (*models.MahasiswaComplete, error) {
+	logger := campusLog.Ctx(ctx)
+	nim, cached := c.cachedNim(userID)
+	if !cached {
+		logger.Debugf("NIM for user ID %d not cached, fetching sequentially", userID)
+
+		mahasiswaInfo, err := c.GetMahasiswaByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		mahasiswaDetail, err := c.GetMahasiswaDetailByNIM(ctx, mahasiswaInfo.Nim)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.MahasiswaComplete{
+			BasicInfo: *mahasiswaInfo,
+			Details:   *mahasiswaDetail,
+		}, nil
+	}
+
+	logger.Debugf("Using cached NIM %s for user ID %d, fetching in parallel", nim, userID)
+
+	var (
+		wg              sync.WaitGroup
+		mahasiswaInfo   *models.MahasiswaInfo
+		mahasiswaDetail *models.MahasiswaDetail
+		infoErr         error
+		detailErr       error
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		mahasiswaInfo, infoErr = c.GetMahasiswaByUserID(ctx, userID)
+	}()
+	go func() {
+		defer wg.Done()
+		mahasiswaDetail, detailErr = c.GetMahasiswaDetailByNIM(ctx, nim)
+	}()
+	wg.Wait()
+
+	if infoErr != nil {
+		return nil, infoErr
+	}
+
+	// The NIM may have changed on the campus side since it was cached
+	if mahasiswaInfo.Nim != nim {
+		logger.Debugf("Cached NIM %s is stale for user ID %d (now %s), refetching details", nim, userID, mahasiswaInfo.Nim)
+		mahasiswaDetail, detailErr = c.GetMahasiswaDetailByNIM(ctx, mahasiswaInfo.Nim)
+	}
+	if detailErr != nil {
+		return nil, detailErr
+	}
+
+	return &models.MahasiswaComplete{
+		BasicInfo: *mahasiswaInfo,
+		Details:   *mahasiswaDetail,
+	}, nil
+}
+
+// URL returns the address of a campus API path such as "/library-api/dosen"
+func (c *CampusClient) URL(path string) string {
+	return c.baseURL + path
+}
+
+// get sends a GET request on behalf of the request a context belongs to
+func (c *CampusClient) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(req)
+}
+
+// getJSON sends a GET request and decodes a successful JSON response into v
+func (c *CampusClient) getJSON(ctx context.Context, url string, v interface{}) error {
+	campusLog.Ctx(ctx).Debugf("Fetching %s", url)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("campus API returned status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
 // GetWithAuth makes an authenticated GET request to the specified URL
-func (c *CampusClient) GetWithAuth(url string) (*http.Response, error) {
-	log.Printf("Making authenticated request to: %s", url)
-	return c.httpClient.Get(url)
+func (c *CampusClient) GetWithAuth(ctx context.Context, url string) (*http.Response, error) {
+	logger := campusLog.Ctx(ctx)
+	logger.Debugf("Making authenticated request to: %s", url)
+	return c.get(ctx, url)
+}
+
+// PingCampusAuth checks that the campus auth endpoint is reachable without logging in
+func PingCampusAuth(authURL string, timeout time.Duration) error {
+	return pingCampus("campus auth", authURL, timeout)
+}
+
+// PingCampusAPI checks that the campus API answers at its base URL without logging in
+func PingCampusAPI(baseURL string, timeout time.Duration) error {
+	return pingCampus("campus API", baseURL, timeout)
+}
+
+// pingCampus requests a campus URL and reports whether the server behind it is up
+func pingCampus(name, url string, timeout time.Duration) error {
+	client := &http.Client{
+		Transport: &chaos.RoundTripper{Base: http.DefaultTransport, Target: chaos.Campus},
+		Timeout:   timeout,
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	// Any answer short of a server error means the endpoint is up
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%s returned status %d", name, resp.StatusCode)
+	}
+	return nil
 }
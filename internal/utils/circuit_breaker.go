@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"delpresence-api/internal/metrics"
+)
+
+// ErrCampusUnavailable is returned when the circuit breaker rejects a campus API call
+var ErrCampusUnavailable = errors.New("campus API is unavailable (circuit breaker open)")
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets all requests through
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects requests until the cooldown has elapsed
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single trial request through
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreaker tracks consecutive failures of a dependency and stops calling it
+// once a threshold is reached, giving it time to recover
+type CircuitBreaker struct {
+	threshold   int
+	cooldown    time.Duration
+	state       CircuitState
+	failures    int
+	openedAt    time.Time
+	trialActive bool
+	calls       uint64 // Completed calls since startup
+	failedCalls uint64 // Completed calls that failed since startup
+	mutex       sync.Mutex
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after threshold consecutive
+// failures and allows a trial request after cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+	}
+}
+
+// Allow reports whether a request may be sent to the dependency
+func (b *CircuitBreaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		// Cooldown elapsed, let one trial request through
+		b.state = CircuitHalfOpen
+		b.trialActive = true
+		log.Println("[CIRCUIT] Cooldown elapsed, circuit half-open")
+		return true
+	case CircuitHalfOpen:
+		if b.trialActive {
+			return false
+		}
+		b.trialActive = true
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess closes the circuit and resets the failure counter
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state != CircuitClosed {
+		log.Println("[CIRCUIT] Dependency recovered, circuit closed")
+	}
+	b.calls++
+	b.state = CircuitClosed
+	b.failures = 0
+	b.trialActive = false
+}
+
+// RecordFailure counts a failure and opens the circuit when the threshold is reached
+func (b *CircuitBreaker) RecordFailure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.calls++
+	b.failedCalls++
+	b.trialActive = false
+
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		if b.state != CircuitOpen {
+			log.Printf("[CIRCUIT] Circuit opened after %d consecutive failures", b.failures)
+		}
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+// Counts returns how many calls completed and how many of them failed since startup. Calls the
+// open circuit rejected are not counted.
+func (b *CircuitBreaker) Counts() (calls, failed uint64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.calls, b.failedCalls
+}
+
+// BreakerRoundTripper guards an http.RoundTripper with a circuit breaker
+type BreakerRoundTripper struct {
+	BaseTransport http.RoundTripper
+	Breaker       *CircuitBreaker
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (rt *BreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.Breaker.Allow() {
+		return nil, ErrCampusUnavailable
+	}
+
+	start := time.Now()
+	resp, err := rt.BaseTransport.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		metrics.CampusRequestDuration.Observe(time.Since(start).Seconds(), req.URL.Path, "error")
+		rt.Breaker.RecordFailure()
+		return resp, err
+	}
+
+	metrics.CampusRequestDuration.Observe(time.Since(start).Seconds(), req.URL.Path, "success")
+	rt.Breaker.RecordSuccess()
+	return resp, nil
+}
@@ -1,38 +0,0 @@
-package utils
-
-import (
-	"log"
-	"os"
-
-	"github.com/joho/godotenv"
-)
-
-// Config holds application configuration
-type Config struct {
-	JWTSecret string
-}
-
-// GetConfig initializes and returns the application configuration
-func GetConfig() *Config {
-	// Load environment variables from .env file
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Warning: Error loading .env file:", err)
-	}
-
-	// Initialize configuration with values from environment variables
-	config := &Config{
-		JWTSecret: getEnv("JWT_SECRET", ""),
-	}
-
-	return config
-}
-
-// getEnv gets an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
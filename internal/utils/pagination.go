@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Page sizes of list endpoints
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageOptions declares which sort and filter query parameters a list endpoint accepts. Names
+// are what clients send and are mapped to columns, so clients never name columns directly.
+type PageOptions struct {
+	Sorts       map[string]string // ?sort=name,-other; "-" sorts descending
+	Filters     map[string]string // ?filter[name]=value, matched exactly
+	Search      []string          // Columns ?q= is matched against, case-insensitively
+	DefaultSort string            // Sort used when the request has none, e.g. "-created_at"
+}
+
+// PageQuery is a list request parsed by ParsePageQuery
+type PageQuery struct {
+	Page  int
+	Limit int
+
+	order         []clause.OrderByColumn
+	filters       []clause.Expression
+	search        string
+	searchColumns []string
+}
+
+// ParsePageQuery reads the page, limit, sort, filter and q query parameters of a list
+// request. Unknown sort or filter names are an error so typos do not silently return
+// everything.
+func ParsePageQuery(c *gin.Context, opts PageOptions) (PageQuery, error) {
+	query := PageQuery{Page: 1, Limit: DefaultPageLimit}
+
+	if raw := c.Query("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return query, fmt.Errorf("page must be a positive number")
+		}
+		query.Page = page
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+		}
+		query.Limit = limit
+	}
+
+	sort := c.Query("sort")
+	if sort == "" {
+		sort = opts.DefaultSort
+	}
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		column, ok := opts.Sorts[strings.TrimPrefix(field, "-")]
+		if !ok {
+			return query, fmt.Errorf("cannot sort by %q", strings.TrimPrefix(field, "-"))
+		}
+		query.order = append(query.order, clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+
+	for name, value := range c.QueryMap("filter") {
+		column, ok := opts.Filters[name]
+		if !ok {
+			return query, fmt.Errorf("cannot filter by %q", name)
+		}
+		query.filters = append(query.filters, clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+
+	if search := strings.TrimSpace(c.Query("q")); search != "" && len(opts.Search) > 0 {
+		query.search = search
+		query.searchColumns = opts.Search
+	}
+
+	return query, nil
+}
+
+// Offset returns how many rows come before the requested page
+func (q PageQuery) Offset() int {
+	return (q.Page - 1) * q.Limit
+}
+
+// Filter is a GORM scope applying the filters and search of the request. Use it to count the
+// rows of every page.
+func (q PageQuery) Filter(db *gorm.DB) *gorm.DB {
+	for _, filter := range q.filters {
+		db = db.Where(filter)
+	}
+	if q.search != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q.search)
+		conditions := make([]clause.Expression, len(q.searchColumns))
+		for i, column := range q.searchColumns {
+			conditions[i] = clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{clause.Column{Name: column}, "%" + escaped + "%"}}
+		}
+		db = db.Where(clause.Or(conditions...))
+	}
+	return db
+}
+
+// Paginate is a GORM scope applying the filters, search, sort and page of the request
+func Paginate(q PageQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = q.Filter(db)
+		if len(q.order) > 0 {
+			db = db.Order(clause.OrderBy{Columns: q.order})
+		}
+		return db.Offset(q.Offset()).Limit(q.Limit)
+	}
+}
+
+// PageResult is the standard envelope of list endpoints
+type PageResult struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Pages int         `json:"pages"`
+}
+
+// NewPageResult wraps one page of items and the total number of rows matching the request
+func NewPageResult(items interface{}, total int64, q PageQuery) PageResult {
+	return PageResult{
+		Items: items,
+		Total: total,
+		Page:  q.Page,
+		Pages: int((total + int64(q.Limit) - 1) / int64(q.Limit)),
+	}
+}
@@ -2,9 +2,9 @@ package utils
 
 import (
 	"fmt"
-	"log"
 	"net/http"
-	"time"
+
+	"delpresence-api/internal/logging"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,22 +17,22 @@ type Response struct {
 	Error   interface{} `json:"error,omitempty"`
 }
 
-// LogError logs error with timestamp and additional info
+// apiLog controls the verbosity of the handler logging helpers below
+var apiLog = logging.Module("api")
+
+// LogError logs an error of a handler action
 func LogError(handler string, action string, err error) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	log.Printf("[ERROR] [%s] %s - %s: %v\n", timestamp, handler, action, err)
+	apiLog.Error(err.Error(), "handler", handler, "action", action)
 }
 
-// LogInfo logs information with timestamp
+// LogInfo logs information about a handler action
 func LogInfo(handler string, action string, message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	log.Printf("[INFO] [%s] %s - %s: %s\n", timestamp, handler, action, message)
+	apiLog.Info(message, "handler", handler, "action", action)
 }
 
-// LogWarning logs warning with timestamp
+// LogWarning logs a warning about a handler action
 func LogWarning(handler string, action string, message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	log.Printf("[WARNING] [%s] %s - %s: %s\n", timestamp, handler, action, message)
+	apiLog.Warn(message, "handler", handler, "action", action)
 }
 
 // SuccessResponse returns a success response
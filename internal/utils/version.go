@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AppVersionHeader carries the version of the mobile app making the request
+const AppVersionHeader = "X-App-Version"
+
+// AppVersionPolicy describes which mobile app versions are still supported. An empty minimum
+// version disables the gate.
+type AppVersionPolicy struct {
+	MinimumVersion string `json:"minimum_version"`
+	LatestVersion  string `json:"latest_version"`
+	UpdateURL      string `json:"update_url"`
+}
+
+// RequiresUpgrade checks whether a client version is below the minimum supported version
+func (p AppVersionPolicy) RequiresUpgrade(version string) bool {
+	return p.MinimumVersion != "" && CompareVersions(version, p.MinimumVersion) < 0
+}
+
+// CompareVersions compares dotted versions such as "1.10.2" numerically and returns
+// -1, 0 or 1. A leading "v" and suffixes like "-beta" or "+45" are ignored.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+	for i := range pa {
+		if pa[i] < pb[i] {
+			return -1
+		}
+		if pa[i] > pb[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version into its numeric parts
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			n = 0
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
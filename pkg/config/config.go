@@ -0,0 +1,854 @@
+// Package config loads the application configuration from the environment once at startup.
+// Values are read from the process environment after the first .env file found is loaded,
+// and handed to the packages that need them instead of being looked up where they are used.
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // CAMPUS_TIMEZONE must load on hosts without a zoneinfo database
+
+	"github.com/joho/godotenv"
+)
+
+// Config holds the application configuration
+type Config struct {
+	Env          string // "production" runs gin in release mode
+	SeedDevData  bool   // Fills the database with sample data on start; refused in production
+	ChaosEnabled bool   // Allows fault injection with the X-Chaos header; never on in production
+	// InstitutionName is printed on reports and certificates
+	InstitutionName string
+	// Location is the campus time zone wall-clock rules such as check-in blackouts are read in
+	Location *time.Location
+	// Features holds the FEATURE_<NAME> values by lowercase feature name, e.g. "geofence"
+	Features map[string]string
+	// Schedules holds the SCHEDULE_<NAME> overrides by lowercase job name, e.g. "token_purge"
+	Schedules   map[string]string
+	Log         LogConfig
+	Server      ServerConfig
+	CORS        CORSConfig
+	Session     SessionConfig
+	Database    DatabaseConfig
+	SMTP        SMTPConfig
+	JWT         JWTConfig
+	Campus      CampusConfig
+	CampusAlert CampusAlertConfig
+	Captcha     CaptchaConfig
+	Attestation AttestationConfig
+	Wifi        WifiConfig
+	Cache       CacheConfig
+	Privacy     PrivacyConfig
+	Documents   DocumentConfig
+	Scoped      ScopedTokenConfig
+	Storage     StorageConfig
+	Stream      StreamConfig
+	Workflow    WorkflowConfig
+	Attendance  AttendanceConfig
+	OfficeHours OfficeHoursConfig
+	Achievement AchievementConfig
+	AppVersion  AppVersionConfig
+}
+
+// LogConfig holds the log output settings
+type LogConfig struct {
+	Format  string // "text" or "json"
+	Level   string // Level of modules without an override; empty for info
+	Modules string // Per-module levels, e.g. "campusclient=debug,email=warn"
+}
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Port            string
+	PublicBaseURL   string        // Externally reachable base URL of the API, used in emailed links
+	ShutdownTimeout time.Duration // How long shutdown waits for in-flight requests and background jobs
+	MetricsToken    string        // Bearer token Prometheus scrapes /metrics with; open when empty
+	// TrustedProxies lists the IPs or CIDRs of the reverse proxies whose X-Forwarded-For is
+	// believed; when empty the client IP is always the address of the connecting peer
+	TrustedProxies []string
+}
+
+// CORSConfig holds the cross-origin settings for the web dashboard
+type CORSConfig struct {
+	AllowedOrigins []string
+}
+
+// SessionConfig holds the cookie settings of the web dashboard's campus SSO sessions
+type SessionConfig struct {
+	CookieDomain string // Domain the session cookies are set for; empty for the API host only
+	SecureCookie bool   // Only sends the cookies over HTTPS
+}
+
+// DatabaseConfig holds the PostgreSQL connection settings
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+	TimeZone string
+	// SlowQueryThreshold is how long a query may take before it is captured as slow
+	SlowQueryThreshold time.Duration
+	// SchemaCheckWarnOnly lets a build start on a schema migrated by a newer build
+	SchemaCheckWarnOnly bool
+}
+
+// DSN returns the connection string for the database
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode, c.TimeZone)
+}
+
+// SMTPConfig holds the outgoing email settings; email is disabled while Host is empty
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Tracking bool // Adds open and click tracking to critical emails
+	// TemplateDir holds templates that override the embedded ones of the same name
+	TemplateDir string
+}
+
+// JWTConfig holds the token signing settings
+type JWTConfig struct {
+	Secret        string        // Signs user access tokens
+	AdminSecret   string        // Signs admin and sudo tokens
+	Expiry        time.Duration // Lifetime of access tokens
+	RefreshExpiry time.Duration // Lifetime of refresh tokens
+}
+
+// Validate checks that both signing secrets are set
+func (c JWTConfig) Validate() error {
+	var problems []string
+	if c.Secret == "" {
+		problems = append(problems, "JWT_SECRET is required")
+	}
+	// default_secret_key was the built-in admin secret before it had to be configured
+	if c.AdminSecret == "" || c.AdminSecret == "default_secret_key" {
+		problems = append(problems, "JWT_SECRET_KEY is required and must not be default_secret_key")
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid JWT configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// SubKey derives the key of one purpose from the access token secret, so a value signed for
+// that purpose never validates as an access token or for another purpose
+func (c JWTConfig) SubKey(purpose string) string {
+	mac := hmac.New(sha256.New, []byte(c.Secret))
+	mac.Write([]byte("delpresence-" + purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// StorageConfig holds the local directories files are written to
+type StorageConfig struct {
+	AttachmentDir string // Uploaded sick notes and session handouts
+	BackupDir     string // Database dumps
+	WarehouseDir  string // Daily snapshots for the data warehouse
+}
+
+// StreamConfig holds the streaming backend outbox events are relayed to; the relay is off
+// while KafkaRESTURL is empty
+type StreamConfig struct {
+	KafkaRESTURL string
+	Topic        string
+}
+
+// WorkflowConfig holds the approval workflow deadlines
+type WorkflowConfig struct {
+	// SLAs replaces the SLA of every state of a workflow, by lowercase workflow type
+	SLAs map[string]time.Duration
+	// ReminderBefore is how long before a deadline assignees are reminded; it never exceeds
+	// half of the SLA
+	ReminderBefore time.Duration
+}
+
+// AttendanceConfig holds the check-in verification settings
+type AttendanceConfig struct {
+	FaceMatchThreshold float64       // Minimum cosine similarity of a face match, above 0 and at most 1
+	TelemetryRetention time.Duration // How long raw check-in telemetry is kept
+	QRTokenSecret      string        // Signs the rotating QR codes of attendance sessions
+	PlannedMeetings    int           // Meetings planned per course offering, as reported to PDDIKTI
+}
+
+// OfficeHoursConfig holds the office-hour reminder settings
+type OfficeHoursConfig struct {
+	ReminderBefore time.Duration // How long before a slot starts the reminders are sent
+}
+
+// AchievementConfig holds the schedule of the nightly streak and badge computation
+type AchievementConfig struct {
+	Hour int // Hour of the day, 0 to 23
+}
+
+// AppVersionConfig holds which mobile app versions are still supported; the gate is off
+// while MinimumVersion is empty
+type AppVersionConfig struct {
+	MinimumVersion string
+	LatestVersion  string // Defaults to MinimumVersion
+	UpdateURL      string
+}
+
+// PrivacyConfig holds the settings that keep students unidentifiable outside the API
+type PrivacyConfig struct {
+	// PseudonymKey keys the hash of student pseudonyms; changing it changes every pseudonym
+	PseudonymKey string
+}
+
+// Validate checks that pseudonyms have their own key
+func (c PrivacyConfig) Validate() error {
+	if c.PseudonymKey == "" {
+		return errors.New("PSEUDONYM_KEY is required")
+	}
+	return nil
+}
+
+// ScopedTokenConfig holds the limits of the single-capability tokens minted for displays and kiosks
+type ScopedTokenConfig struct {
+	// MaxTTLs caps the lifetime of scoped tokens by lowercase capability, e.g. "kiosk"; a
+	// capability without an entry is capped at one hour
+	MaxTTLs map[string]time.Duration
+}
+
+// MaxTTL returns the longest lifetime a token for capability may be minted with
+func (c ScopedTokenConfig) MaxTTL(capability string) time.Duration {
+	if ttl, ok := c.MaxTTLs[capability]; ok {
+		return ttl
+	}
+	return time.Hour
+}
+
+// DocumentConfig holds the keys that sign the documents the API issues. They are kept apart from
+// the token secrets so rotating JWT_SECRET does not invalidate documents already handed out.
+type DocumentConfig struct {
+	CertificateKey  string // Signs event participation certificates
+	VerificationKey string // Signs the other issued documents, such as attendance report PDFs
+}
+
+// Validate checks that issued documents have their own keys
+func (c DocumentConfig) Validate() error {
+	if c.CertificateKey == "" {
+		return errors.New("CERTIFICATE_SIGNING_KEY is required")
+	}
+	if c.VerificationKey == "" {
+		return errors.New("DOCUMENT_SIGNING_KEY is required")
+	}
+	return nil
+}
+
+// CampusConfig holds the campus API endpoints and the service account used to call them
+type CampusConfig struct {
+	BaseURL    string
+	AuthURL    string
+	RefreshURL string // Renews the service account token with its refresh token; empty to always log in again
+	Username   string
+	Password   string
+	// SyncInterval is the pause between campus API lookups of a bulk re-sync, so start-of-semester
+	// refreshes do not flood the campus API
+	SyncInterval time.Duration
+	// BreakerThreshold is how many consecutive failed calls open the circuit breaker, after which
+	// calls fail fast until BreakerCooldown has passed
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// FieldPolicies picks, per synced profile field, which value a sync keeps when the campus API
+	// and the owner of the profile both changed the field; fields not listed use CampusWins
+	FieldPolicies map[string]string
+}
+
+// Conflict policies selectable per field with CAMPUS_SYNC_FIELD_POLICIES
+const (
+	CampusWins = "campus-wins" // Takes the campus API's value
+	LocalWins  = "local-wins"  // Keeps the value edited by the owner
+	NewestWins = "newest-wins" // Keeps the owner's edit when it was made after the last sync
+)
+
+// CampusAlertConfig holds where campus IT is notified when the campus API is unhealthy; alerting
+// is off while neither WebhookURL nor Emails is set
+type CampusAlertConfig struct {
+	WebhookURL    string        // Receives alerts as JSON POST requests
+	WebhookSecret string        // Signs webhook bodies with HMAC-SHA256 when set
+	Emails        []string      // Addresses that receive alerts by email
+	ErrorRate     float64       // Share of failed calls over Window that raises an alert, 0 to 1
+	MinCalls      int           // Fewest calls over Window for the error rate to count
+	Window        time.Duration // Period the error rate is measured over
+	Recovery      time.Duration // How long a condition must stay healthy before it is reported resolved
+	Repeat        time.Duration // Reminder interval for alerts still firing; zero for none
+}
+
+// Enabled reports whether alerts have somewhere to go
+func (c CampusAlertConfig) Enabled() bool {
+	return c.WebhookURL != "" || len(c.Emails) > 0
+}
+
+// Validate checks that the alert thresholds and the webhook URL are usable
+func (c CampusAlertConfig) Validate() error {
+	var problems []string
+	if c.WebhookURL != "" && !isHTTPURL(c.WebhookURL) {
+		problems = append(problems, "CAMPUS_ALERT_WEBHOOK_URL must be an http or https URL")
+	}
+	if c.ErrorRate <= 0 || c.ErrorRate > 1 {
+		problems = append(problems, "CAMPUS_ALERT_ERROR_RATE must be above 0 and at most 1")
+	}
+	if c.MinCalls < 1 {
+		problems = append(problems, "CAMPUS_ALERT_MIN_CALLS must be at least 1")
+	}
+	if c.Window <= 0 || c.Recovery < 0 || c.Repeat < 0 {
+		problems = append(problems, "CAMPUS_ALERT_WINDOW must be positive and CAMPUS_ALERT_RECOVERY and CAMPUS_ALERT_REPEAT must not be negative")
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid campus alert configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CaptchaConfig holds the CAPTCHA settings of the login endpoints; CAPTCHA is off while
+// Provider is empty
+type CaptchaConfig struct {
+	Provider      string   // "recaptcha", "hcaptcha" or "turnstile"
+	Secret        string   // Server-side secret of the site
+	BypassClients []string // Client tokens of the mobile app builds that skip the CAPTCHA
+}
+
+// Validate checks that a known provider is configured with its secret
+func (c CaptchaConfig) Validate() error {
+	switch c.Provider {
+	case "":
+		return nil
+	case "recaptcha", "hcaptcha", "turnstile":
+	default:
+		return fmt.Errorf("invalid CAPTCHA configuration: unknown CAPTCHA_PROVIDER %q", c.Provider)
+	}
+	if c.Secret == "" {
+		return errors.New("invalid CAPTCHA configuration: CAPTCHA_SECRET is required")
+	}
+	return nil
+}
+
+// AttestationConfig holds the settings of device attestation on check-in. A platform is only
+// verified when its settings are present; FEATURE_DEVICE_ATTESTATION decides who must attest.
+type AttestationConfig struct {
+	AndroidPackage           string // Package name of the Android app
+	PlayIntegrityCredentials string // Path of the key file of the service account linked in the Play Console
+	AppleAppID               string // "<team ID>.<bundle ID>" of the iOS app
+	AppAttestRootCA          string // Path of the Apple App Attestation root CA certificate (PEM)
+	AppAttestDevelopment     bool   // Also accepts keys attested by development builds
+}
+
+// WifiConfig holds the address ranges of the campus Wi-Fi; check-ins are only verified to
+// come from it once at least one range is set
+type WifiConfig struct {
+	Networks []*net.IPNet // Public and private ranges clients on the campus Wi-Fi connect from
+}
+
+// Contains reports whether ip belongs to one of the campus Wi-Fi ranges
+func (c WifiConfig) Contains(ip net.IP) bool {
+	for _, network := range c.Networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cache drivers selectable with CACHE_DRIVER
+const (
+	MemoryCacheDriver = "memory"
+	RedisCacheDriver  = "redis"
+)
+
+// CacheConfig selects where cached values are kept. The memory driver keeps a copy per
+// instance; the redis driver shares them between instances.
+type CacheConfig struct {
+	Driver        string // MemoryCacheDriver or RedisCacheDriver
+	RedisAddr     string // host:port of the Redis server
+	RedisPassword string
+	RedisDB       int
+}
+
+// Validate checks that a known driver is configured with its settings
+func (c CacheConfig) Validate() error {
+	switch c.Driver {
+	case MemoryCacheDriver:
+		return nil
+	case RedisCacheDriver:
+		if c.RedisAddr == "" {
+			return errors.New("invalid cache configuration: REDIS_ADDR is required by the redis driver")
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid cache configuration: unknown CACHE_DRIVER %q", c.Driver)
+}
+
+// Validate checks that the campus API can be called with the configuration
+func (c CampusConfig) Validate() error {
+	var problems []string
+	if !isHTTPURL(c.BaseURL) {
+		problems = append(problems, "CAMPUS_API_BASE_URL must be an http or https URL")
+	}
+	if !isHTTPURL(c.AuthURL) {
+		problems = append(problems, "CAMPUS_API_AUTH_URL must be an http or https URL")
+	}
+	if c.RefreshURL != "" && !isHTTPURL(c.RefreshURL) {
+		problems = append(problems, "CAMPUS_API_REFRESH_URL must be an http or https URL")
+	}
+	if c.Username == "" || c.Password == "" {
+		problems = append(problems, "CAMPUS_API_USERNAME and CAMPUS_API_PASSWORD are required")
+	}
+	if c.BreakerThreshold < 1 || c.BreakerCooldown <= 0 {
+		problems = append(problems, "CAMPUS_BREAKER_THRESHOLD and CAMPUS_BREAKER_COOLDOWN must be positive")
+	}
+	if c.SyncInterval < 0 {
+		problems = append(problems, "CAMPUS_SYNC_INTERVAL must not be negative")
+	}
+	for field, policy := range c.FieldPolicies {
+		if policy != CampusWins && policy != LocalWins && policy != NewestWins {
+			problems = append(problems, fmt.Sprintf("CAMPUS_SYNC_FIELD_POLICIES has unknown policy %q for %s", policy, field))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid campus API configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// IsProduction reports whether the API runs in production
+func (c *Config) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// Load reads the .env file and the environment into a Config and validates it
+func Load() (*Config, error) {
+	loadEnvFile()
+
+	jwtExpiry, err := durationEnv("JWT_EXPIRY", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	refreshExpiry, err := durationEnv("JWT_REFRESH_EXPIRY", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	campusBreakerThreshold, err := strconv.Atoi(getEnv("CAMPUS_BREAKER_THRESHOLD", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAMPUS_BREAKER_THRESHOLD format: %v", err)
+	}
+	campusBreakerCooldown, err := durationEnv("CAMPUS_BREAKER_COOLDOWN", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	campusSyncInterval, err := durationEnv("CAMPUS_SYNC_INTERVAL", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+
+	campusAlertWindow, err := durationEnv("CAMPUS_ALERT_WINDOW", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	campusAlertRecovery, err := durationEnv("CAMPUS_ALERT_RECOVERY", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	campusAlertRepeat, err := durationEnv("CAMPUS_ALERT_REPEAT", 4*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	campusAlertErrorRate, err := strconv.ParseFloat(getEnv("CAMPUS_ALERT_ERROR_RATE", "0.5"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAMPUS_ALERT_ERROR_RATE format: %v", err)
+	}
+	campusAlertMinCalls, err := strconv.Atoi(getEnv("CAMPUS_ALERT_MIN_CALLS", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAMPUS_ALERT_MIN_CALLS format: %v", err)
+	}
+
+	// CAMPUS_SYNC_FIELD_POLICIES lists field=policy pairs, e.g. "email=local-wins,full_name=newest-wins"
+	campusFieldPolicies := make(map[string]string)
+	for _, pair := range listEnv("CAMPUS_SYNC_FIELD_POLICIES") {
+		field, policy, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid CAMPUS_SYNC_FIELD_POLICIES format: %q is not field=policy", pair)
+		}
+		campusFieldPolicies[strings.TrimSpace(field)] = strings.ToLower(strings.TrimSpace(policy))
+	}
+
+	// An empty CAMPUS_API_REFRESH_URL turns the refresh flow off, so only a missing one gets the default
+	campusRefreshURL, ok := os.LookupEnv("CAMPUS_API_REFRESH_URL")
+	if !ok {
+		campusRefreshURL = "https://cis-dev.del.ac.id/api/jwt-api/refresh-token"
+	}
+
+	var wifiNetworks []*net.IPNet
+	for _, cidr := range listEnv("CAMPUS_WIFI_NETWORKS") {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CAMPUS_WIFI_NETWORKS format: %v", err)
+		}
+		wifiNetworks = append(wifiNetworks, network)
+	}
+
+	redisDB, err := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_DB format: %v", err)
+	}
+
+	publicBaseURL := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8080"), "/")
+
+	slowQueryThreshold, err := durationEnv("DB_SLOW_QUERY_THRESHOLD", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	workflowReminder, err := dayDurationEnv("WORKFLOW_REMINDER_BEFORE", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	workflowSLAs := make(map[string]time.Duration)
+	for workflowType, value := range prefixedEnv("WORKFLOW_SLA_") {
+		sla, err := parseDayDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WORKFLOW_SLA_%s format: %v", strings.ToUpper(workflowType), err)
+		}
+		workflowSLAs[workflowType] = sla
+	}
+	// Kiosks are mounted in rooms for good, so re-provisioning them every hour is impractical
+	scopedMaxTTLs := map[string]time.Duration{"kiosk": 24 * time.Hour}
+	for capability, value := range prefixedEnv("SCOPED_TOKEN_MAX_TTL_") {
+		ttl, err := parseDayDuration(value)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid SCOPED_TOKEN_MAX_TTL_%s: must be a positive duration", strings.ToUpper(capability))
+		}
+		scopedMaxTTLs[capability] = ttl
+	}
+	telemetryRetention, err := dayDurationEnv("CHECKIN_TELEMETRY_RETENTION", 90*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	officeHourReminder, err := dayDurationEnv("OFFICE_HOURS_REMINDER_BEFORE", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	faceMatchThreshold, err := strconv.ParseFloat(getEnv("FACE_MATCH_THRESHOLD", "0.8"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FACE_MATCH_THRESHOLD format: %v", err)
+	}
+	plannedMeetings, err := strconv.Atoi(getEnv("PLANNED_MEETINGS", "14"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLANNED_MEETINGS format: %v", err)
+	}
+	achievementHour, err := strconv.Atoi(getEnv("ACHIEVEMENTS_HOUR", "1"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACHIEVEMENTS_HOUR format: %v", err)
+	}
+	minAppVersion := os.Getenv("MIN_APP_VERSION")
+	dbTimeZone := getEnv("DB_TIMEZONE", "Asia/Jakarta")
+	location, err := time.LoadLocation(getEnv("CAMPUS_TIMEZONE", dbTimeZone))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAMPUS_TIMEZONE: %v", err)
+	}
+
+	cfg := &Config{
+		Env:             os.Getenv("ENV"),
+		SeedDevData:     os.Getenv("SEED_DEV_DATA") == "true",
+		ChaosEnabled:    os.Getenv("CHAOS_ENABLED") == "true" && os.Getenv("ENV") != "production",
+		InstitutionName: getEnv("INSTITUTION_NAME", "Institut Teknologi Del"),
+		Location:        location,
+		Features:        prefixedEnv("FEATURE_"),
+		Schedules:       prefixedEnv("SCHEDULE_"),
+		Log: LogConfig{
+			Format:  os.Getenv("LOG_FORMAT"),
+			Level:   os.Getenv("LOG_LEVEL"),
+			Modules: os.Getenv("LOG_MODULES"),
+		},
+		Server: ServerConfig{
+			Port:            getEnv("SERVER_PORT", "8080"),
+			PublicBaseURL:   publicBaseURL,
+			ShutdownTimeout: shutdownTimeout,
+			MetricsToken:    os.Getenv("METRICS_TOKEN"),
+			TrustedProxies:  listEnv("TRUSTED_PROXIES"),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: strings.Split(getEnv("ALLOWED_ORIGINS", "http://localhost:3000"), ","),
+		},
+		Session: SessionConfig{
+			CookieDomain: os.Getenv("SESSION_COOKIE_DOMAIN"),
+			// Plain HTTP is only expected in local development
+			SecureCookie: strings.HasPrefix(publicBaseURL, "https://"),
+		},
+		Database: DatabaseConfig{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", "5432"),
+			User:     getEnv("DB_USER", "postgres"),
+			Password: os.Getenv("DB_PASSWORD"),
+			Name:     getEnv("DB_NAME", "delpresence"),
+			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+			TimeZone: dbTimeZone,
+
+			SlowQueryThreshold:  slowQueryThreshold,
+			SchemaCheckWarnOnly: os.Getenv("SCHEMA_CHECK_MODE") == "warn",
+		},
+		SMTP: SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     getEnv("SMTP_FROM", "DelPresence <no-reply@delpresence.ac.id>"),
+			Tracking: os.Getenv("EMAIL_TRACKING") == "true",
+
+			TemplateDir: os.Getenv("EMAIL_TEMPLATE_DIR"),
+		},
+		JWT: JWTConfig{
+			Secret:        os.Getenv("JWT_SECRET"),
+			AdminSecret:   os.Getenv("JWT_SECRET_KEY"),
+			Expiry:        jwtExpiry,
+			RefreshExpiry: refreshExpiry,
+		},
+		Campus: CampusConfig{
+			BaseURL:       strings.TrimRight(getEnv("CAMPUS_API_BASE_URL", "https://cis.del.ac.id/api"), "/"),
+			AuthURL:       getEnv("CAMPUS_API_AUTH_URL", "https://cis-dev.del.ac.id/api/jwt-api/do-auth"),
+			RefreshURL:    campusRefreshURL,
+			Username:      os.Getenv("CAMPUS_API_USERNAME"),
+			Password:      os.Getenv("CAMPUS_API_PASSWORD"),
+			SyncInterval:  campusSyncInterval,
+			FieldPolicies: campusFieldPolicies,
+
+			BreakerThreshold: campusBreakerThreshold,
+			BreakerCooldown:  campusBreakerCooldown,
+		},
+		CampusAlert: CampusAlertConfig{
+			WebhookURL:    os.Getenv("CAMPUS_ALERT_WEBHOOK_URL"),
+			WebhookSecret: os.Getenv("CAMPUS_ALERT_WEBHOOK_SECRET"),
+			Emails:        listEnv("CAMPUS_ALERT_EMAILS"),
+			ErrorRate:     campusAlertErrorRate,
+			MinCalls:      campusAlertMinCalls,
+			Window:        campusAlertWindow,
+			Recovery:      campusAlertRecovery,
+			Repeat:        campusAlertRepeat,
+		},
+		Captcha: CaptchaConfig{
+			Provider:      strings.ToLower(strings.TrimSpace(os.Getenv("CAPTCHA_PROVIDER"))),
+			Secret:        os.Getenv("CAPTCHA_SECRET"),
+			BypassClients: listEnv("CAPTCHA_BYPASS_CLIENTS"),
+		},
+		Attestation: AttestationConfig{
+			AndroidPackage:           os.Getenv("ATTESTATION_ANDROID_PACKAGE"),
+			PlayIntegrityCredentials: os.Getenv("PLAY_INTEGRITY_CREDENTIALS"),
+			AppleAppID:               os.Getenv("ATTESTATION_APPLE_APP_ID"),
+			AppAttestRootCA:          os.Getenv("APP_ATTEST_ROOT_CA"),
+			AppAttestDevelopment:     os.Getenv("APP_ATTEST_DEVELOPMENT") == "true",
+		},
+		Wifi: WifiConfig{
+			Networks: wifiNetworks,
+		},
+		Cache: CacheConfig{
+			Driver:        strings.ToLower(getEnv("CACHE_DRIVER", MemoryCacheDriver)),
+			RedisAddr:     os.Getenv("REDIS_ADDR"),
+			RedisPassword: os.Getenv("REDIS_PASSWORD"),
+			RedisDB:       redisDB,
+		},
+		Privacy: PrivacyConfig{
+			PseudonymKey: os.Getenv("PSEUDONYM_KEY"),
+		},
+		Scoped: ScopedTokenConfig{
+			MaxTTLs: scopedMaxTTLs,
+		},
+		Documents: DocumentConfig{
+			CertificateKey:  os.Getenv("CERTIFICATE_SIGNING_KEY"),
+			VerificationKey: os.Getenv("DOCUMENT_SIGNING_KEY"),
+		},
+		Storage: StorageConfig{
+			AttachmentDir: getEnv("ATTACHMENT_DIR", "attachments"),
+			BackupDir:     getEnv("BACKUP_DIR", "backups"),
+			WarehouseDir:  getEnv("WAREHOUSE_DIR", "warehouse"),
+		},
+		Stream: StreamConfig{
+			KafkaRESTURL: os.Getenv("STREAM_KAFKA_REST_URL"),
+			Topic:        getEnv("STREAM_TOPIC", "delpresence.events"),
+		},
+		Workflow: WorkflowConfig{
+			SLAs:           workflowSLAs,
+			ReminderBefore: workflowReminder,
+		},
+		Attendance: AttendanceConfig{
+			FaceMatchThreshold: faceMatchThreshold,
+			TelemetryRetention: telemetryRetention,
+			QRTokenSecret:      os.Getenv("QR_TOKEN_SECRET"),
+			PlannedMeetings:    plannedMeetings,
+		},
+		OfficeHours: OfficeHoursConfig{
+			ReminderBefore: officeHourReminder,
+		},
+		Achievement: AchievementConfig{
+			Hour: achievementHour,
+		},
+		AppVersion: AppVersionConfig{
+			MinimumVersion: minAppVersion,
+			LatestVersion:  getEnv("LATEST_APP_VERSION", minAppVersion),
+			UpdateURL:      os.Getenv("APP_UPDATE_URL"),
+		},
+	}
+
+	if err := cfg.JWT.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Privacy.Validate(); err != nil {
+		return nil, err
+	}
+	// Sharing the token secret would let a leaked JWT_SECRET re-identify exported pseudonyms
+	if cfg.Privacy.PseudonymKey == cfg.JWT.Secret {
+		return nil, errors.New("PSEUDONYM_KEY must differ from JWT_SECRET")
+	}
+	if err := cfg.Documents.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Campus.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.CampusAlert.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Captcha.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Cache.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Attendance.QRTokenSecret == "" || cfg.Attendance.QRTokenSecret == cfg.JWT.Secret {
+		return nil, errors.New("QR_TOKEN_SECRET is required and must differ from JWT_SECRET")
+	}
+	if cfg.Attendance.FaceMatchThreshold <= 0 || cfg.Attendance.FaceMatchThreshold > 1 {
+		return nil, errors.New("FACE_MATCH_THRESHOLD must be above 0 and at most 1")
+	}
+	if cfg.Attendance.TelemetryRetention <= 0 || cfg.OfficeHours.ReminderBefore <= 0 {
+		return nil, errors.New("CHECKIN_TELEMETRY_RETENTION and OFFICE_HOURS_REMINDER_BEFORE must be positive")
+	}
+	if cfg.Attendance.PlannedMeetings < 1 {
+		return nil, errors.New("PLANNED_MEETINGS must be at least 1")
+	}
+	if cfg.Achievement.Hour < 0 || cfg.Achievement.Hour > 23 {
+		return nil, errors.New("ACHIEVEMENTS_HOUR must be between 0 and 23")
+	}
+	if cfg.SeedDevData && cfg.IsProduction() {
+		return nil, errors.New("SEED_DEV_DATA must not be set in production")
+	}
+	return cfg, nil
+}
+
+// loadEnvFile loads the first .env file found in the usual locations
+func loadEnvFile() {
+	paths := []string{
+		".env",       // Current directory
+		"../../.env", // Project root when running from cmd/api
+	}
+	if ex, err := os.Executable(); err == nil {
+		paths = append(paths, filepath.Join(filepath.Dir(ex), ".env")) // Binary location
+	}
+
+	for _, path := range paths {
+		if err := godotenv.Load(path); err == nil {
+			log.Printf("Loaded .env from: %s", path)
+			return
+		}
+	}
+	log.Println("Warning: .env file not found, using default values")
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// isHTTPURL checks whether value is an absolute http or https URL
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// durationEnv parses a duration environment variable or returns a default value
+func durationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s format: %v", key, err)
+	}
+	return parsed, nil
+}
+
+// dayDurationEnv parses a duration environment variable that may also be given in days, e.g.
+// "3d", or returns a default value
+func dayDurationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := parseDayDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s format: %v", key, err)
+	}
+	return parsed, nil
+}
+
+// parseDayDuration parses a duration such as "72h" or "3d"
+func parseDayDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("%q is not a number of days", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// prefixedEnv returns the non-empty variables starting with prefix, by the lowercase rest of
+// their name, e.g. FEATURE_GEOFENCE as "geofence"
+func prefixedEnv(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok || name == "" {
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			values[strings.ToLower(name)] = value
+		}
+	}
+	return values
+}
+
+// listEnv returns the non-empty comma-separated values of a variable
+func listEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -1,12 +1,13 @@
 package database
 
 import (
-	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"delpresence-api/internal/chaos"
 	"delpresence-api/internal/models"
+	"delpresence-api/pkg/config"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -16,39 +17,7 @@ import (
 var DB *gorm.DB
 
 // ConnectDB establishes a connection to the database
-func ConnectDB() error {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	sslMode := os.Getenv("DB_SSL_MODE")
-	timezone := os.Getenv("DB_TIMEZONE")
-
-	// Set default values if environment variables are not set
-	if host == "" {
-		host = "localhost"
-	}
-	if port == "" {
-		port = "5432"
-	}
-	if user == "" {
-		user = "postgres"
-	}
-	if dbname == "" {
-		dbname = "delpresence"
-	}
-	if sslMode == "" {
-		sslMode = "disable"
-	}
-	if timezone == "" {
-		timezone = "Asia/Jakarta"
-	}
-
-	// Create DSN string for PostgreSQL
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		host, user, password, dbname, port, sslMode, timezone)
-
+func ConnectDB(cfg config.DatabaseConfig) error {
 	// Configure logger
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
@@ -63,13 +32,23 @@ func ConnectDB() error {
 
 	// Open connection
 	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: newLogger,
+	DB, err = gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		Logger: newSlowQueryLogger(newLogger, cfg.SlowQueryThreshold),
 	})
 	if err != nil {
 		return err
 	}
 
+	// Fault injection for resilience testing; a no-op unless CHAOS_ENABLED=true
+	if err := chaos.RegisterGormCallbacks(DB); err != nil {
+		return err
+	}
+
+	// Refuse to touch a schema migrated by a newer build
+	if err := checkSchemaVersion(cfg.SchemaCheckWarnOnly); err != nil {
+		return err
+	}
+
 	// Migrate the schema
 	err = DB.AutoMigrate(
 		&models.User{},
@@ -102,3 +81,15 @@ func ConnectDB() error {
 func GetDB() *gorm.DB {
 	return DB
 }
+
+// Close closes the connection pool of the database
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,314 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Campus user IDs of the sample data start far above those of the campus API, and sample emails
+// use the reserved .test domain, so sample lecturers and students cannot be mistaken for real ones
+const (
+	devLecturerUserID = 900001
+	devStudentUserID  = 910001
+)
+
+// devSemester is the semester the sample courses are taught in
+const devSemester = "2025/2026 Ganjil"
+
+// devMeetings is how many past meetings each sample class has held
+const devMeetings = 4
+
+// DevSeed lists the sample users created by SeedDevData, for signing in as them
+type DevSeed struct {
+	Lecturers []models.Lecturer
+	Students  []models.MahasiswaInfo
+}
+
+type devRoom struct {
+	code, name, building string
+	capacity             int
+	latitude, longitude  float64
+}
+
+type devCourse struct {
+	code, name, className, room string
+	lecturer                    int // Index into the sample lecturers
+	prodi                       int // Index into devProdis; its students are enrolled
+	day                         int
+	start, end                  string
+}
+
+type devProdi struct {
+	id      int
+	name    string
+	nimBase string
+	class   string
+}
+
+var devRooms = []devRoom{
+	{"GD5-101", "Ruang Kelas 5.1.1", "Gedung 5", 40, 2.38340, 99.14870},
+	{"GD7-201", "Ruang Kelas 7.2.1", "Gedung 7", 60, 2.38395, 99.14920},
+	{"GD9-LAB1", "Laboratorium Komputer 1", "Gedung 9", 30, 2.38280, 99.14810},
+}
+
+var devProdis = []devProdi{
+	{1, "S1 Informatika", "11S23", "12IF1"},
+	{2, "S1 Sistem Informasi", "12S23", "12SI1"},
+}
+
+var devLecturers = []struct {
+	name, email, nip string
+	prodi            int
+}{
+	{"Dr. Lestari Napitupulu, S.T., M.Kom.", "lestari.napitupulu@delpresence.test", "0309198201", 0},
+	{"Benny Sinaga, S.Kom., M.T.", "benny.sinaga@delpresence.test", "0411198502", 0},
+	{"Ribka Hutagalung, S.Kom., M.Sc.", "ribka.hutagalung@delpresence.test", "0512198003", 1},
+}
+
+var devStudentNames = []string{
+	"Yohana Sitorus", "Daniel Pardede", "Grace Hutapea", "Samuel Manurung", "Putri Situmorang",
+	"Joshua Nainggolan", "Ruth Simatupang", "Kevin Siahaan", "Maria Silaban", "Andreas Panjaitan",
+	"Natalia Sihombing", "Bona Tampubolon",
+}
+
+var devCourses = []devCourse{
+	{"11S2204", "Basis Data", "12IF1", "GD5-101", 0, 0, 1, "08:00", "09:40"},
+	{"11S2205", "Pemrograman Berbasis Web", "12IF1", "GD9-LAB1", 1, 0, 3, "10:00", "11:40"},
+	{"12S2102", "Analisis Proses Bisnis", "12SI1", "GD7-201", 2, 1, 2, "13:00", "14:40"},
+}
+
+// SeedDevData fills the database with sample rooms, lecturers, students, courses, schedules,
+// enrollments and attendance sessions with check-ins, so the web and mobile apps can be
+// developed without the campus API. It does nothing when the sample data already exists and
+// must never run against production.
+func SeedDevData() (*DevSeed, error) {
+	seed := &DevSeed{}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.Lecturer{}).Where("lecturer_user_id = ?", devLecturerUserID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			log.Println("Sample development data already exists")
+			return tx.Where("lecturer_user_id >= ?", devLecturerUserID).Order("lecturer_user_id").Find(&seed.Lecturers).Error
+		}
+
+		log.Println("Creating sample development data...")
+		now := time.Now()
+
+		rooms := make(map[string]models.Room, len(devRooms))
+		for _, sample := range devRooms {
+			latitude, longitude := sample.latitude, sample.longitude
+			room := models.Room{
+				Code:           sample.code,
+				Name:           sample.name,
+				Building:       sample.building,
+				Capacity:       sample.capacity,
+				Latitude:       &latitude,
+				Longitude:      &longitude,
+				GeofenceRadius: models.DefaultGeofenceRadius,
+			}
+			if err := tx.Where(models.Room{Code: sample.code}).FirstOrCreate(&room).Error; err != nil {
+				return err
+			}
+			rooms[room.Code] = room
+		}
+
+		for i, sample := range devLecturers {
+			prodi := devProdis[sample.prodi]
+			lecturer := models.Lecturer{
+				LecturerUserID: uint(devLecturerUserID + i),
+				IdentityNumber: sample.nip,
+				FullName:       sample.name,
+				Email:          sample.email,
+				DepartmentID:   uint(prodi.id),
+				Department:     prodi.name,
+				CampusUserID:   uint(devLecturerUserID + i),
+				Status:         "Active",
+				LastSyncAt:     now,
+			}
+			if err := tx.Create(&lecturer).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.UserRole{Source: models.CampusIdentity, UserID: lecturer.LecturerUserID, Role: models.LecturerType, ProfileID: lecturer.ID, IsDefault: true}).Error; err != nil {
+				return err
+			}
+			seed.Lecturers = append(seed.Lecturers, lecturer)
+		}
+
+		studentsByProdi := make(map[int][]models.MahasiswaInfo)
+		for i, name := range devStudentNames {
+			prodiIndex := i % len(devProdis)
+			prodi := devProdis[prodiIndex]
+			info := models.MahasiswaInfo{
+				DimID:     devStudentUserID + i,
+				UserID:    devStudentUserID + i,
+				UserName:  fmt.Sprintf("dev%02d", i+1),
+				Nim:       fmt.Sprintf("%s%03d", prodi.nimBase, i/len(devProdis)+1),
+				Nama:      name,
+				Email:     fmt.Sprintf("dev%02d@students.delpresence.test", i+1),
+				ProdiID:   prodi.id,
+				ProdiName: prodi.name,
+				Fakultas:  "Fakultas Informatika dan Teknik Elektro",
+				Angkatan:  2023,
+				Status:    "Aktif",
+				Asrama:    "Asrama 1",
+			}
+			basicInfo, err := json.Marshal(info)
+			if err != nil {
+				return err
+			}
+			details, err := json.Marshal(models.MahasiswaDetail{
+				Nim:        info.Nim,
+				Nama:       info.Nama,
+				Email:      info.Email,
+				Prodi:      prodi.name,
+				Fakultas:   info.Fakultas,
+				Sem:        5,
+				TahunMasuk: info.Angkatan,
+				Kelas:      prodi.class,
+				Asrama:     info.Asrama,
+			})
+			if err != nil {
+				return err
+			}
+			snapshot := models.MahasiswaSnapshot{
+				UserID:     uint(info.UserID),
+				Nim:        info.Nim,
+				BasicInfo:  string(basicInfo),
+				Details:    string(details),
+				LastSyncAt: now,
+			}
+			if err := tx.Create(&snapshot).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.UserRole{Source: models.CampusIdentity, UserID: snapshot.UserID, Role: models.StudentType, IsDefault: true}).Error; err != nil {
+				return err
+			}
+			studentsByProdi[prodiIndex] = append(studentsByProdi[prodiIndex], info)
+			seed.Students = append(seed.Students, info)
+		}
+
+		for _, course := range devCourses {
+			lecturer := seed.Lecturers[course.lecturer]
+			room := rooms[course.room]
+			if err := tx.Create(&models.Schedule{
+				CourseCode:     course.code,
+				CourseName:     course.name,
+				ClassName:      course.className,
+				LecturerUserID: lecturer.LecturerUserID,
+				Room:           course.room,
+				DayOfWeek:      course.day,
+				StartTime:      course.start,
+				EndTime:        course.end,
+				Semester:       devSemester,
+			}).Error; err != nil {
+				return err
+			}
+
+			students := studentsByProdi[course.prodi]
+			for _, student := range students {
+				if err := tx.Create(&models.Enrollment{
+					Nim:        student.Nim,
+					CourseCode: course.code,
+					CourseName: course.name,
+					ClassName:  course.className,
+					Semester:   devSemester,
+				}).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := createDevSessions(tx, course, lecturer, room, students, now); err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Created %d rooms, %d lecturers, %d students and %d courses of sample data", len(devRooms), len(seed.Lecturers), len(seed.Students), len(devCourses))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(seed.Students) == 0 {
+		var snapshots []models.MahasiswaSnapshot
+		if err := DB.Where("user_id >= ?", devStudentUserID).Order("user_id").Find(&snapshots).Error; err != nil {
+			return nil, err
+		}
+		for i := range snapshots {
+			complete, err := snapshots[i].ToMahasiswaComplete()
+			if err != nil {
+				return nil, err
+			}
+			seed.Students = append(seed.Students, complete.BasicInfo)
+		}
+	}
+	return seed, nil
+}
+
+// createDevSessions creates the past meetings of a sample class with varied check-ins, and an
+// open meeting today that students can check in to
+func createDevSessions(tx *gorm.DB, course devCourse, lecturer models.Lecturer, room models.Room, students []models.MahasiswaInfo, now time.Time) error {
+	for meeting := 1; meeting <= devMeetings+1; meeting++ {
+		start := now.AddDate(0, 0, -7*(devMeetings+1-meeting))
+		session := models.AttendanceSession{
+			LecturerUserID: lecturer.LecturerUserID,
+			CourseCode:     course.code,
+			CourseName:     course.name,
+			ClassName:      course.className,
+			Semester:       devSemester,
+			MeetingNumber:  meeting,
+			Topic:          fmt.Sprintf("Pertemuan %d %s", meeting, course.name),
+			Room:           room.Code,
+			Latitude:       room.Latitude,
+			Longitude:      room.Longitude,
+			GeofenceRadius: room.GeofenceRadius,
+			Status:         models.SessionOpen,
+			OpenedAt:       start,
+		}
+		if meeting <= devMeetings {
+			closedAt := start.Add(100 * time.Minute)
+			session.Status = models.SessionClosed
+			session.ClosedAt = &closedAt
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		if session.Status == models.SessionOpen {
+			continue
+		}
+
+		for i, student := range students {
+			// Every student misses a meeting now and then and some come late
+			if (i+meeting)%5 == 0 {
+				continue
+			}
+			record := models.AttendanceRecord{
+				SessionID:     session.ID,
+				StudentUserID: uint(student.UserID),
+				Nim:           student.Nim,
+				Status:        models.AttendancePresent,
+				Method:        models.CheckInQR,
+				Credit:        1,
+				CheckedInAt:   start.Add(time.Duration(2+i) * time.Minute),
+			}
+			if (i*meeting)%4 == 3 {
+				record.Status = models.AttendanceLate
+				record.LateMinutes = 15 + i
+				record.Credit = 0.5
+				record.CheckedInAt = start.Add(time.Duration(record.LateMinutes) * time.Minute)
+			}
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
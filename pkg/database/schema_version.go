@@ -0,0 +1,64 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"delpresence-api/internal/models"
+)
+
+// ExpectedSchemaVersion is the schema version this build reads and writes.
+// Bump it whenever a migration changes the schema in a way older builds cannot write safely.
+const ExpectedSchemaVersion = 2
+
+// ErrSchemaAhead is returned when the database was migrated by a newer build
+var ErrSchemaAhead = errors.New("database schema is newer than this build")
+
+// CurrentSchemaVersion returns the latest schema version applied to the database,
+// or zero if none has been recorded yet
+func CurrentSchemaVersion() (int, error) {
+	var version int
+	if err := DB.Model(&models.SchemaVersion{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// checkSchemaVersion refuses to start when the database schema is newer than this build,
+// so a stale replica cannot write incompatible rows during a rolling deploy. With warnOnly the
+// mismatch is only logged.
+func checkSchemaVersion(warnOnly bool) error {
+	if err := DB.AutoMigrate(&models.SchemaVersion{}); err != nil {
+		return err
+	}
+
+	version, err := CurrentSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > ExpectedSchemaVersion {
+		err := fmt.Errorf("%w: database is at version %d, build expects %d", ErrSchemaAhead, version, ExpectedSchemaVersion)
+		if !warnOnly {
+			return err
+		}
+		log.Printf("Warning: %v", err)
+	}
+	return nil
+}
+
+// recordSchemaVersion marks the expected schema version as applied once migrations succeed
+func recordSchemaVersion() error {
+	version, err := CurrentSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version >= ExpectedSchemaVersion {
+		return nil
+	}
+
+	log.Printf("Recording schema version %d (was %d)", ExpectedSchemaVersion, version)
+	return DB.Create(&models.SchemaVersion{Version: ExpectedSchemaVersion, AppliedAt: time.Now()}).Error
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// slowQueryCapacity is how many slow queries are kept in memory
+	slowQueryCapacity = 100
+	// explainBacklog is how many slow queries may wait for their plan; more are not explained
+	explainBacklog = 16
+)
+
+var (
+	// stringLiteralPattern matches quoted SQL string literals
+	stringLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// numberLiteralPattern matches numeric literals that are not part of an identifier
+	numberLiteralPattern = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+)
+
+// SlowQuery is a captured query that took longer than the slow query threshold.
+// Literal values are redacted so no personal data is kept.
+type SlowQuery struct {
+	ID         uint64    `json:"id"`
+	SQL        string    `json:"sql"`
+	DurationMs float64   `json:"duration_ms"`
+	Rows       int64     `json:"rows"`
+	Error      string    `json:"error,omitempty"`
+	Explain    string    `json:"explain,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// SlowQueryLog is a fixed-size ring buffer of recent slow queries
+type SlowQueryLog struct {
+	mutex   sync.Mutex
+	entries []SlowQuery
+	next    int
+	seq     uint64
+}
+
+// SlowQueries holds the slow queries captured since startup
+var SlowQueries = &SlowQueryLog{entries: make([]SlowQuery, 0, slowQueryCapacity)}
+
+// add stores a query, overwriting the oldest once the buffer is full, and returns its ID
+func (l *SlowQueryLog) add(query SlowQuery) uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.seq++
+	query.ID = l.seq
+	if len(l.entries) < slowQueryCapacity {
+		l.entries = append(l.entries, query)
+	} else {
+		l.entries[l.next] = query
+	}
+	l.next = (l.next + 1) % slowQueryCapacity
+	return query.ID
+}
+
+// setExplain attaches the query plan to a captured query if it is still in the buffer
+func (l *SlowQueryLog) setExplain(id uint64, explain string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i := range l.entries {
+		if l.entries[i].ID == id {
+			l.entries[i].Explain = explain
+			return
+		}
+	}
+}
+
+// Recent returns the captured slow queries, newest first
+func (l *SlowQueryLog) Recent() []SlowQuery {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	recent := make([]SlowQuery, len(l.entries))
+	for i := range l.entries {
+		// Walk backwards from the most recently written slot
+		index := (l.next - 1 - i + 2*len(l.entries)) % len(l.entries)
+		recent[i] = l.entries[index]
+	}
+	return recent
+}
+
+// RedactSQL replaces literal values in a statement with placeholders
+func RedactSQL(sql string) string {
+	sql = stringLiteralPattern.ReplaceAllString(sql, "'?'")
+	return numberLiteralPattern.ReplaceAllString(sql, "?")
+}
+
+// explainJob is a captured read whose plan is still to be fetched
+type explainJob struct {
+	id  uint64
+	sql string
+}
+
+var (
+	// explainQueue feeds the single EXPLAIN worker, so a burst of slow queries never takes
+	// more than one extra connection from a database that is already struggling
+	explainQueue     = make(chan explainJob, explainBacklog)
+	startExplainOnce sync.Once
+)
+
+// slowQueryLogger wraps the GORM logger and captures queries slower than threshold
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+}
+
+// newSlowQueryLogger wraps inner, capturing queries that take at least threshold
+func newSlowQueryLogger(inner logger.Interface, threshold time.Duration) logger.Interface {
+	startExplainOnce.Do(func() {
+		go func() {
+			for job := range explainQueue {
+				explainSlowQuery(job.id, job.sql)
+			}
+		}()
+	})
+	return &slowQueryLogger{Interface: inner, threshold: threshold}
+}
+
+// LogMode implements logger.Interface, keeping slow query capture on the new logger
+func (l *slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &slowQueryLogger{Interface: l.Interface.LogMode(level), threshold: l.threshold}
+}
+
+// Trace implements logger.Interface
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+
+	elapsed := time.Since(begin)
+	if elapsed < l.threshold {
+		return
+	}
+
+	sql, rows := fc()
+	query := SlowQuery{
+		SQL:        RedactSQL(sql),
+		DurationMs: float64(elapsed) / float64(time.Millisecond),
+		Rows:       rows,
+		CapturedAt: time.Now(),
+	}
+	if err != nil {
+		query.Error = RedactSQL(err.Error())
+	}
+	id := SlowQueries.add(query)
+
+	// Only plan reads; EXPLAIN without ANALYZE does not run the statement. Queries arriving
+	// while the backlog is full are kept without a plan.
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "SELECT") {
+		select {
+		case explainQueue <- explainJob{id: id, sql: sql}:
+		default:
+		}
+	}
+}
+
+// explainSlowQuery stores the redacted query plan of a captured query
+func explainSlowQuery(id uint64, sql string) {
+	if DB == nil {
+		return
+	}
+
+	var lines []string
+	silent := DB.Session(&gorm.Session{Logger: logger.Discard})
+	if err := silent.Raw("EXPLAIN " + sql).Scan(&lines).Error; err != nil {
+		return
+	}
+	SlowQueries.setExplain(id, RedactSQL(strings.Join(lines, "\n")))
+}
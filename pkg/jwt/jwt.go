@@ -3,19 +3,39 @@ package jwt
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 
+	"delpresence-api/pkg/config"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// settings holds the signing configuration set by Configure
+var settings = config.JWTConfig{
+	Expiry:        24 * time.Hour,
+	RefreshExpiry: 30 * 24 * time.Hour,
+}
+
+// Configure sets the signing configuration; it must be called once at startup
+func Configure(cfg config.JWTConfig) {
+	settings = cfg
+}
+
 // Custom error definitions
 var (
 	ErrInvalidToken = errors.New("token is invalid")
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// Token types carried in the token_type claim so the middleware knows how to treat a token
+const (
+	// LocalTokenType is issued to users with a local account
+	LocalTokenType = "local"
+	// CampusTokenType is issued to campus-authenticated users without a local account
+	CampusTokenType = "campus"
+)
+
 // CustomClaims defines the claims for JWT
 type CustomClaims struct {
 	UserID     uint   `json:"user_id"`
@@ -24,30 +44,18 @@ type CustomClaims struct {
 	MiddleName string `json:"middle_name"`
 	LastName   string `json:"last_name"`
 	Email      string `json:"email"`
+	// Roles held by the account and the role the token is acting as
+	Roles      []string `json:"roles,omitempty"`
+	ActiveRole string   `json:"active_role,omitempty"`
+	// CampusUserID is set on role-scoped tokens issued to campus-authenticated users
+	CampusUserID int `json:"campus_user_id,omitempty"`
+	// TokenType is LocalTokenType or CampusTokenType; tokens issued before it existed are local
+	TokenType string `json:"token_type,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateAccessToken generates a new JWT access token
 func GenerateAccessToken(userID uint, nimNip string, firstName string, middleName string, lastName string, email string) (string, time.Time, error) {
-	// Get secret key from environment
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		return "", time.Time{}, errors.New("JWT_SECRET environment variable not set")
-	}
-
-	// Parse expiry duration
-	expiryStr := os.Getenv("JWT_EXPIRY")
-	if expiryStr == "" {
-		expiryStr = "24h" // Default to 24 hours
-	}
-
-	expiry, err := time.ParseDuration(expiryStr)
-	if err != nil {
-		return "", time.Time{}, fmt.Errorf("invalid JWT_EXPIRY format: %v", err)
-	}
-
-	expiryTime := time.Now().Add(expiry)
-
 	// Create the Claims
 	claims := CustomClaims{
 		UserID:     userID,
@@ -56,13 +64,45 @@ func GenerateAccessToken(userID uint, nimNip string, firstName string, middleNam
 		MiddleName: middleName,
 		LastName:   lastName,
 		Email:      email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiryTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "delpresence-api",
-			Subject:   strconv.Itoa(int(userID)),
-		},
+		TokenType:  LocalTokenType,
+	}
+
+	return signClaims(claims)
+}
+
+// GenerateRoleToken generates an access token scoped to one of the roles held by the user.
+// campusUserID is zero for users that have a local account.
+func GenerateRoleToken(userID uint, campusUserID int, email string, roles []string, activeRole string) (string, time.Time, error) {
+	claims := CustomClaims{
+		UserID:       userID,
+		Email:        email,
+		Roles:        roles,
+		ActiveRole:   activeRole,
+		CampusUserID: campusUserID,
+		TokenType:    LocalTokenType,
+	}
+	if campusUserID != 0 {
+		claims.TokenType = CampusTokenType
+	}
+
+	return signClaims(claims)
+}
+
+// signClaims fills in the registered claims and signs the token
+func signClaims(claims CustomClaims) (string, time.Time, error) {
+	secretKey := settings.Secret
+	if secretKey == "" {
+		return "", time.Time{}, errors.New("JWT_SECRET environment variable not set")
+	}
+
+	expiryTime := time.Now().Add(settings.Expiry)
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiryTime),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		Issuer:    "delpresence-api",
+		Subject:   strconv.Itoa(int(claims.UserID)),
 	}
 
 	// Create token
@@ -79,8 +119,7 @@ func GenerateAccessToken(userID uint, nimNip string, firstName string, middleNam
 
 // ValidateToken validates a JWT token and returns the claims
 func ValidateToken(tokenString string) (*CustomClaims, error) {
-	// Get secret key from environment
-	secretKey := os.Getenv("JWT_SECRET")
+	secretKey := settings.Secret
 	if secretKey == "" {
 		return nil, errors.New("JWT_SECRET environment variable not set")
 	}
@@ -103,6 +142,9 @@ func ValidateToken(tokenString string) (*CustomClaims, error) {
 	}
 
 	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
+		if claims.TokenType == "" {
+			claims.TokenType = LocalTokenType
+		}
 		return claims, nil
 	}
 
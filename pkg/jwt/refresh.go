@@ -0,0 +1,34 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// GenerateRefreshToken generates an opaque refresh token and its expiry.
+// Only the hash from HashRefreshToken should be stored.
+func GenerateRefreshToken() (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return hex.EncodeToString(buf), time.Now().Add(settings.RefreshExpiry), nil
+}
+
+// HashRefreshToken returns the value a refresh token is stored under
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CSRFToken returns the CSRF token of a cookie session. It is derived from the session's
+// access token, so it changes whenever the session is refreshed and needs no storage.
+func CSRFToken(sessionToken string) string {
+	mac := hmac.New(sha256.New, []byte(settings.Secret))
+	mac.Write([]byte("csrf:" + sessionToken))
+	return hex.EncodeToString(mac.Sum(nil))
+}
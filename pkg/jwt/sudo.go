@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SudoTTL is how long an elevation stays valid after re-authentication
+const SudoTTL = 5 * time.Minute
+
+// sudoScope marks elevation tokens so they cannot be mistaken for access tokens
+const sudoScope = "sudo"
+
+// SudoClaims defines the claims of a short-lived elevation token
+type SudoClaims struct {
+	UserID uint   `json:"user_id"`
+	Scope  string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// AdminSecretKey returns the key admin tokens are signed with
+func AdminSecretKey() []byte {
+	return []byte(settings.AdminSecret)
+}
+
+// GenerateSudoToken generates an elevation token for an admin who just re-authenticated
+func GenerateSudoToken(userID uint) (string, time.Time, error) {
+	expiryTime := time.Now().Add(SudoTTL)
+	claims := SudoClaims{
+		UserID: userID,
+		Scope:  sudoScope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiryTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "delpresence-api",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(AdminSecretKey())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiryTime, nil
+}
+
+// ValidateSudoToken validates an elevation token and returns the user it was issued to
+func ValidateSudoToken(tokenString string) (*SudoClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &SudoClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return AdminSecretKey(), nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*SudoClaims)
+	if !ok || !token.Valid || claims.Scope != sudoScope {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
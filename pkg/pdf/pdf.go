@@ -0,0 +1,241 @@
+// Package pdf writes simple text and table documents as PDF with the standard library.
+// Documents are A4 portrait and use the built-in Helvetica fonts, so only Latin-1 text
+// is printed as is; other characters are replaced by "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContentType is the MIME type of PDF files
+const ContentType = "application/pdf"
+
+// Page geometry in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+// ContentWidth is the usable width of a page between the margins, in points
+const ContentWidth = pageWidth - 2*margin
+
+// Document is a PDF being laid out top to bottom
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64 // Baseline of the next line, from the bottom of the page
+}
+
+// New creates a document with one empty page
+func New() *Document {
+	d := &Document{}
+	d.addPage()
+	return d
+}
+
+// addPage starts a new page
+func (d *Document) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// page returns the content stream of the current page
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// ensure starts a new page unless height points still fit on the current one
+func (d *Document) ensure(height float64) {
+	if d.y-height < margin {
+		d.addPage()
+	}
+}
+
+// Text writes a line of text. Align is "left", "center" or "right".
+func (d *Document) Text(text string, size float64, bold bool, align string) {
+	d.ensure(size * 1.4)
+	d.y -= size
+	x := margin
+	switch align {
+	case "center":
+		x = margin + (ContentWidth-textWidth(text, size, bold))/2
+	case "right":
+		x = pageWidth - margin - textWidth(text, size, bold)
+	}
+	d.writeText(x, d.y, text, size, bold)
+	d.y -= size * 0.4
+}
+
+// Paragraph writes text wrapped at word boundaries to fit between the margins. Lines in
+// text start new lines.
+func (d *Document) Paragraph(text string, size float64, bold bool, align string) {
+	for _, line := range strings.Split(text, "\n") {
+		current := ""
+		for _, word := range strings.Fields(line) {
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if current != "" && textWidth(candidate, size, bold) > ContentWidth {
+				d.Text(current, size, bold, align)
+				candidate = word
+			}
+			current = candidate
+		}
+		d.Text(current, size, bold, align)
+	}
+}
+
+// Space moves down by height points
+func (d *Document) Space(height float64) {
+	d.y -= height
+	if d.y < margin {
+		d.addPage()
+	}
+}
+
+// Rule draws a horizontal line across the page
+func (d *Document) Rule() {
+	d.ensure(6)
+	d.y -= 3
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, d.y, pageWidth-margin, d.y)
+	d.y -= 3
+}
+
+// Row draws one table row with a border around every cell. Widths are in points and
+// text that does not fit a cell is cut off.
+func (d *Document) Row(widths []float64, cells []string, size float64, bold bool) {
+	height := size * 1.8
+	d.ensure(height)
+
+	x := margin
+	for i, width := range widths {
+		fmt.Fprintf(d.page(), "0.5 w %.2f %.2f %.2f %.2f re S\n", x, d.y-height, width, height)
+		if i < len(cells) {
+			text := fit(cells[i], width-6, size, bold)
+			d.writeText(x+3, d.y-height+size*0.55, text, size, bold)
+		}
+		x += width
+	}
+	d.y -= height
+}
+
+// Matrix draws a grid of square cells, such as a QR code, width points wide. Dark cells
+// are filled and light cells left blank. Align is "left", "center" or "right".
+func (d *Document) Matrix(cells [][]bool, width float64, align string) {
+	if len(cells) == 0 {
+		return
+	}
+	d.ensure(width)
+	x := margin
+	switch align {
+	case "center":
+		x = margin + (ContentWidth-width)/2
+	case "right":
+		x = pageWidth - margin - width
+	}
+
+	cell := width / float64(len(cells))
+	for row, line := range cells {
+		y := d.y - float64(row+1)*cell
+		// Runs of dark cells are drawn as one rectangle to keep the content stream small
+		for col := 0; col < len(line); col++ {
+			if !line[col] {
+				continue
+			}
+			start := col
+			for col+1 < len(line) && line[col+1] {
+				col++
+			}
+			fmt.Fprintf(d.page(), "%.2f %.2f %.2f %.2f re f\n", x+float64(start)*cell, y, float64(col-start+1)*cell, cell)
+		}
+	}
+	d.y -= width
+}
+
+// writeText places text with its baseline at x, y
+func (d *Document) writeText(x, y float64, text string, size float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(text))
+}
+
+// Write writes the document
+func (d *Document) Write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are the catalog, page tree and fonts; each page adds a page and its content
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// escape converts text to a Latin-1 PDF string literal body
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth estimates the width of text in points. Helvetica glyphs average about half
+// the font size, a little more in bold.
+func textWidth(text string, size float64, bold bool) float64 {
+	factor := 0.5
+	if bold {
+		factor = 0.55
+	}
+	return float64(len([]rune(text))) * size * factor
+}
+
+// fit cuts text so it fits within width points
+func fit(text string, width, size float64, bold bool) string {
+	runes := []rune(text)
+	for len(runes) > 0 && textWidth(string(runes), size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
@@ -0,0 +1,460 @@
+// Package qrcode encodes short text as a QR code with the standard library. Text is stored
+// in byte mode with error correction level M, in the smallest of versions 1 to 10 that fits
+// (up to 213 bytes), which is plenty for verification links printed on documents.
+package qrcode
+
+import (
+	"errors"
+)
+
+// ErrTooLong is returned when the text does not fit in the largest supported version
+var ErrTooLong = errors.New("text is too long for a QR code")
+
+// blockLayout is the error correction layout of a version at level M: every block gets ecLen
+// error correction codewords, and there are group1 blocks of data1 data codewords followed by
+// group2 blocks of data1+1 data codewords
+type blockLayout struct {
+	ecLen  int
+	group1 int
+	data1  int
+	group2 int
+}
+
+// layouts of versions 1 to 10 at error correction level M
+var layouts = []blockLayout{
+	{10, 1, 16, 0},
+	{16, 1, 28, 0},
+	{26, 1, 44, 0},
+	{18, 2, 32, 0},
+	{24, 2, 43, 0},
+	{16, 4, 27, 0},
+	{18, 4, 31, 0},
+	{22, 2, 38, 2},
+	{22, 3, 36, 2},
+	{26, 4, 43, 1},
+}
+
+// alignmentPositions are the centre coordinates of the alignment patterns of versions 1 to 10
+var alignmentPositions = [][]int{
+	{},
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+// dataCapacity returns how many data codewords a layout holds
+func (l blockLayout) dataCapacity() int {
+	return l.group1*l.data1 + l.group2*(l.data1+1)
+}
+
+// Encode returns the modules of the QR code of text, row by row; true is a dark module.
+// The quiet zone around the code is not included.
+func Encode(text string) ([][]bool, error) {
+	data := []byte(text)
+	version := 0
+	for v := 1; v <= len(layouts); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*layouts[v-1].dataCapacity() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	q := newSymbol(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(q.interleave(q.dataCodewords(data)))
+
+	// Keep the mask with the lowest penalty
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask) // Masks are their own inverse
+	}
+	q.applyMask(best)
+	q.drawFormatBits(best)
+
+	return q.modules, nil
+}
+
+// symbol is a QR code being drawn
+type symbol struct {
+	version    int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// newSymbol creates an empty symbol of a version
+func newSymbol(version int) *symbol {
+	size := 17 + 4*version
+	q := &symbol{
+		version:    version,
+		size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for y := range q.modules {
+		q.modules[y] = make([]bool, size)
+		q.isFunction[y] = make([]bool, size)
+	}
+	return q
+}
+
+// set draws a function module, which data and masks never touch
+func (q *symbol) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and reserves the
+// format and version areas
+func (q *symbol) drawFunctionPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	for _, centre := range [][2]int{{3, 3}, {q.size - 4, 3}, {3, q.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := centre[0]+dx, centre[1]+dy
+				if x < 0 || x >= q.size || y < 0 || y >= q.size {
+					continue
+				}
+				dist := max(abs(dx), abs(dy))
+				q.set(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	positions := alignmentPositions[q.version-1]
+	last := len(positions) - 1
+	for i, px := range positions {
+		for j, py := range positions {
+			// Alignment patterns never overlap the finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.set(px+dx, py+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	q.drawFormatBits(0)
+	q.drawVersionBits()
+}
+
+// drawFormatBits draws the error correction level and mask in both copies of the format area
+func (q *symbol) drawFormatBits(mask int) {
+	// Level M is encoded as 00
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(bits, i))
+	}
+	q.set(8, 7, bit(bits, 6))
+	q.set(8, 8, bit(bits, 7))
+	q.set(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.set(q.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.size-15+i, bit(bits, i))
+	}
+	q.set(8, q.size-8, true)
+}
+
+// drawVersionBits draws both copies of the version information of versions 7 and up
+func (q *symbol) drawVersionBits() {
+	if q.version < 7 {
+		return
+	}
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		a, b := q.size-11+i%3, i/3
+		q.set(a, b, bit(bits, i))
+		q.set(b, a, bit(bits, i))
+	}
+}
+
+// dataCodewords encodes data in byte mode and pads it to the capacity of the version
+func (q *symbol) dataCodewords(data []byte) []byte {
+	capacity := layouts[q.version-1].dataCapacity()
+	countBits := 8
+	if q.version >= 10 {
+		countBits = 16
+	}
+
+	var buf bitBuffer
+	buf.append(0x4, 4)
+	buf.append(len(data), countBits)
+	for _, b := range data {
+		buf.append(int(b), 8)
+	}
+	buf.append(0, min(4, capacity*8-buf.len()))
+	buf.append(0, (8-buf.len()%8)%8)
+	for pad := 0xEC; buf.len() < capacity*8; pad ^= 0xEC ^ 0x11 {
+		buf.append(pad, 8)
+	}
+	return buf.bytes()
+}
+
+// interleave splits the data codewords into blocks, adds their error correction codewords
+// and interleaves the blocks as the standard requires
+func (q *symbol) interleave(data []byte) []byte {
+	layout := layouts[q.version-1]
+	generator := rsGenerator(layout.ecLen)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < layout.group1+layout.group2; i++ {
+		length := layout.data1
+		if i >= layout.group1 {
+			length++
+		}
+		block := data[offset : offset+length]
+		offset += length
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, generator))
+	}
+
+	var result []byte
+	for i := 0; i <= layout.data1; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecLen; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// drawCodewords places the codewords in the zigzag order, two columns at a time from the
+// bottom right. Remainder modules are left light.
+func (q *symbol) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if upward {
+					y = q.size - 1 - vert
+				}
+				if q.isFunction[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				q.modules[y][x] = bit(int(codewords[i>>3]), 7-i&7)
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by a mask pattern
+func (q *symbol) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to scan; lower is better
+func (q *symbol) penalty() int {
+	penalty := 0
+	dark := 0
+
+	line := func(get func(i int) bool) {
+		run := 1
+		for i := 1; i <= q.size; i++ {
+			if i < q.size && get(i) == get(i-1) {
+				run++
+				continue
+			}
+			if run >= 5 {
+				penalty += run - 2
+			}
+			run = 1
+		}
+		// Finder-like 1:1:3:1:1 patterns with four light modules on one side
+		for i := 0; i+11 <= q.size; i++ {
+			core := get(i+4) && !get(i+5) && get(i+6) && get(i+7) && get(i+8) && !get(i+9) && get(i+10)
+			if core && !get(i) && !get(i+1) && !get(i+2) && !get(i+3) {
+				penalty += 40
+			}
+			core = get(i) && !get(i+1) && get(i+2) && get(i+3) && get(i+4) && !get(i+5) && get(i+6)
+			if core && !get(i+7) && !get(i+8) && !get(i+9) && !get(i+10) {
+				penalty += 40
+			}
+		}
+	}
+
+	for y := 0; y < q.size; y++ {
+		line(func(i int) bool { return q.modules[y][i] })
+		line(func(i int) bool { return q.modules[i][y] })
+	}
+
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	total := q.size * q.size
+	deviation := abs(dark*20-total*10) / total
+	return penalty + deviation*10
+}
+
+// bitBuffer collects bits most significant first
+type bitBuffer struct {
+	bits []bool
+}
+
+// append adds the low count bits of value
+func (b *bitBuffer) append(value, count int) {
+	for i := count - 1; i >= 0; i-- {
+		b.bits = append(b.bits, bit(value, i))
+	}
+}
+
+// len returns the number of bits collected
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+// bytes packs the bits into bytes; the length must be a multiple of 8
+func (b *bitBuffer) bytes() []byte {
+	out := make([]byte, len(b.bits)/8)
+	for i, set := range b.bits {
+		if set {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// rsGenerator returns the Reed-Solomon generator polynomial of a degree, highest coefficient
+// first without the leading 1
+func rsGenerator(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords of a block
+func rsRemainder(data, generator []byte) []byte {
+	result := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range generator {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// bit reports whether bit i of value is set
+func bit(value, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
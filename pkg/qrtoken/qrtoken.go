@@ -0,0 +1,121 @@
+// Package qrtoken generates and validates the short-lived signed payloads shown as
+// rotating QR codes during attendance sessions. Payloads are kept compact so the QR
+// code stays easy to scan from the back of a classroom.
+package qrtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotationInterval is how often the QR code of a session changes. The code of an interval is
+// derived from the session and the interval alone, so every screen showing the session shows
+// the same code without coordinating with the others.
+const RotationInterval = 15 * time.Second
+
+// DefaultTTL is how long a QR payload stays valid: the rest of its interval and the next one,
+// so a code scanned just before a rotation can still be submitted
+const DefaultTTL = 2 * RotationInterval
+
+// version prefixes every payload so the format can change later
+const version = "q1"
+
+// Custom error definitions
+var (
+	ErrInvalidToken = errors.New("QR token is invalid")
+	ErrExpiredToken = errors.New("QR token has expired")
+	ErrMissingKey   = errors.New("QR token secret is required")
+)
+
+// Token is a decoded QR payload
+type Token struct {
+	SessionID uint
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// Signer generates and validates payloads signed with its secret
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a Signer keyed with secret
+func NewSigner(secret string) (*Signer, error) {
+	if secret == "" {
+		return nil, ErrMissingKey
+	}
+	return &Signer{key: []byte(secret)}, nil
+}
+
+// sign computes the signature of the unsigned part of a payload
+func sign(key []byte, unsigned string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Current returns the signed payload of the current rotation interval of a session. Only
+// the codes of the current and the previous interval are unexpired at any time, so a photo of
+// an older code forwarded to an absent friend stops working within one rotation.
+func (s *Signer) Current(sessionID uint) (string, *Token) {
+	start := time.Now().Truncate(RotationInterval)
+	session := strconv.FormatUint(uint64(sessionID), 10)
+
+	token := &Token{
+		SessionID: sessionID,
+		Nonce:     sign(s.key, "nonce."+session+"."+strconv.FormatInt(start.Unix(), 10))[:12],
+		ExpiresAt: start.Add(DefaultTTL),
+	}
+
+	unsigned := strings.Join([]string{
+		version,
+		session,
+		strconv.FormatInt(token.ExpiresAt.Unix(), 10),
+		token.Nonce,
+	}, ".")
+
+	return unsigned + "." + sign(s.key, unsigned), token
+}
+
+// RotatesAt returns when the code of a payload is replaced on the screens showing it
+func (t *Token) RotatesAt() time.Time {
+	return t.ExpiresAt.Add(-RotationInterval)
+}
+
+// Validate checks the signature and expiry of a payload and returns its contents
+func (s *Signer) Validate(payload string) (*Token, error) {
+	parts := strings.Split(strings.TrimSpace(payload), ".")
+	if len(parts) != 5 || parts[0] != version {
+		return nil, ErrInvalidToken
+	}
+
+	unsigned := strings.Join(parts[:4], ".")
+	if !hmac.Equal([]byte(sign(s.key, unsigned)), []byte(parts[4])) {
+		return nil, ErrInvalidToken
+	}
+
+	sessionID, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	expiresUnix, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	token := &Token{
+		SessionID: uint(sessionID),
+		Nonce:     parts[3],
+		ExpiresAt: time.Unix(expiresUnix, 0),
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, ErrExpiredToken
+	}
+
+	return token, nil
+}
@@ -0,0 +1,183 @@
+// Package xlsx writes simple Office Open XML spreadsheets (.xlsx) with the standard
+// library. It supports plain text and number cells only, which is all the attendance
+// exports need, and streams the workbook straight to the response.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the MIME type of .xlsx files
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// maxSheetName is the longest sheet name Excel accepts
+const maxSheetName = 31
+
+// Workbook is a spreadsheet made of named sheets
+type Workbook struct {
+	sheets []*Sheet
+	names  map[string]bool
+}
+
+// Sheet is one worksheet of a workbook
+type Sheet struct {
+	name string
+	rows [][]interface{}
+}
+
+// NewWorkbook creates an empty workbook
+func NewWorkbook() *Workbook {
+	return &Workbook{names: make(map[string]bool)}
+}
+
+// AddSheet appends a sheet. Names are cleaned of characters Excel rejects, shortened
+// to 31 characters and made unique.
+func (w *Workbook) AddSheet(name string) *Sheet {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet"
+	}
+
+	unique := truncate(name, maxSheetName)
+	for i := 2; w.names[strings.ToLower(unique)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		unique = truncate(name, maxSheetName-len(suffix)) + suffix
+	}
+	w.names[strings.ToLower(unique)] = true
+
+	sheet := &Sheet{name: unique}
+	w.sheets = append(w.sheets, sheet)
+	return sheet
+}
+
+// AddRow appends a row. Integers and floats become number cells, times are written as
+// "2006-01-02 15:04" and everything else as text.
+func (s *Sheet) AddRow(values ...interface{}) {
+	s.rows = append(s.rows, values)
+}
+
+// Write writes the workbook as an .xlsx file
+func (w *Workbook) Write(out io.Writer) error {
+	zw := zip.NewWriter(out)
+
+	sheets := w.sheets
+	if len(sheets) == 0 {
+		sheets = []*Sheet{{name: "Sheet1"}}
+	}
+
+	var contentTypes, workbook, rels strings.Builder
+	contentTypes.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i, sheet := range sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheet.name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+	}
+	for _, part := range parts {
+		if err := writePart(zw, part.name, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range sheets {
+		if err := writePart(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml()); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// xml renders the sheet's worksheet part
+func (s *Sheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, value := range row {
+			ref := columnName(c) + strconv.Itoa(r+1)
+			switch v := value.(type) {
+			case nil:
+				continue
+			case int, int64, uint, uint64, float64:
+				fmt.Fprintf(&b, `<c r="%s"><v>%v</v></c>`, ref, v)
+			case time.Time:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, v.Format("2006-01-02 15:04"))
+			default:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(fmt.Sprint(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// writePart adds one file to the package
+func writePart(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+// columnName converts a zero-based column index to its letters (0 = A, 26 = AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// escape escapes text for use in XML
+func escape(value string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// truncate shortens a string to at most n runes
+func truncate(value string, n int) string {
+	runes := []rune(value)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return value
+}